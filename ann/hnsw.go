@@ -0,0 +1,408 @@
+// Package ann provides an in-memory approximate nearest neighbour index over
+// embedding vectors, using a Hierarchical Navigable Small World graph (HNSW).
+package ann
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// Result is one search hit; Score is the cosine similarity to the query
+type Result struct {
+	ID    string
+	Score float64
+}
+
+type node struct {
+	id        string
+	vector    []float32 // unit length, so similarity is a dot product
+	neighbors [][]int32 // per layer, up to maxNeighbors(layer)
+	deleted   bool      // tombstone: still routes searches, never returned
+}
+
+// Index is an HNSW graph over unit-normalized vectors. It is safe for
+// concurrent use. Removed vectors are tombstoned rather than unlinked, so a
+// graph with many removals should eventually be rebuilt (see Deleted).
+type Index struct {
+	m              int
+	efConstruction int
+	levelMult      float64
+
+	mu       sync.RWMutex
+	rng      *rand.Rand
+	nodes    []node
+	ids      map[string]int32 // live nodes only
+	deleted  int
+	entry    int32
+	maxLevel int
+}
+
+// New creates an empty index. m is the number of neighbours kept per node
+// (twice that on the bottom layer) and efConstruction the candidate list
+// size used while inserting; larger values trade build time for recall.
+func New(m, efConstruction int) *Index {
+	if m < 2 {
+		m = 2
+	}
+	if efConstruction < m {
+		efConstruction = m
+	}
+	return &Index{
+		m:              m,
+		efConstruction: efConstruction,
+		levelMult:      1 / math.Log(float64(m)),
+		rng:            rand.New(rand.NewSource(1)),
+		ids:            make(map[string]int32),
+		entry:          -1,
+	}
+}
+
+// Len returns the number of searchable vectors
+func (x *Index) Len() int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return len(x.ids)
+}
+
+// Deleted returns the number of tombstoned vectors still held in the graph
+func (x *Index) Deleted() int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return x.deleted
+}
+
+// Remove tombstones the vector stored under id, reporting whether it existed
+func (x *Index) Remove(id string) bool {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.remove(id)
+}
+
+func (x *Index) remove(id string) bool {
+	idx, ok := x.ids[id]
+	if !ok {
+		return false
+	}
+	x.nodes[idx].deleted = true
+	delete(x.ids, id)
+	x.deleted++
+	return true
+}
+
+// Add inserts a vector under id, replacing any vector already stored under
+// it. Zero vectors are ignored.
+func (x *Index) Add(id string, vector []float32) {
+	unit := normalize(vector)
+	if unit == nil {
+		return
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.remove(id)
+
+	level := int(-math.Log(1-x.rng.Float64()) * x.levelMult)
+	idx := int32(len(x.nodes))
+	x.nodes = append(x.nodes, node{
+		id:        id,
+		vector:    unit,
+		neighbors: make([][]int32, level+1),
+	})
+	x.ids[id] = idx
+
+	if x.entry < 0 {
+		x.entry = idx
+		x.maxLevel = level
+		return
+	}
+
+	// Descend greedily through the layers above the new node's level
+	ep := x.entry
+	for layer := x.maxLevel; layer > level; layer-- {
+		ep = x.greedyClosest(unit, ep, layer)
+	}
+
+	entryPoints := []int32{ep}
+	for layer := min(level, x.maxLevel); layer >= 0; layer-- {
+		candidates := x.searchLayer(unit, entryPoints, x.efConstruction, layer)
+		neighbors := closest(candidates, x.m)
+		x.nodes[idx].neighbors[layer] = neighbors
+
+		for _, n := range neighbors {
+			x.link(n, idx, layer)
+		}
+
+		// Continue from this layer's candidates, or the same entry points if
+		// everything nearby was tombstoned
+		if len(candidates) > 0 {
+			entryPoints = entryPoints[:0]
+			for _, c := range candidates {
+				entryPoints = append(entryPoints, c.idx)
+			}
+		}
+	}
+
+	if level > x.maxLevel {
+		x.entry = idx
+		x.maxLevel = level
+	}
+}
+
+// Search returns up to k approximate nearest neighbours of query, most
+// similar first. ef is the candidate list size (at least k); larger values
+// improve recall at the cost of latency.
+func (x *Index) Search(query []float32, k, ef int) []Result {
+	unit := normalize(query)
+	if unit == nil || k <= 0 {
+		return nil
+	}
+	if ef < k {
+		ef = k
+	}
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	if x.entry < 0 {
+		return nil
+	}
+
+	ep := x.entry
+	for layer := x.maxLevel; layer > 0; layer-- {
+		ep = x.greedyClosest(unit, ep, layer)
+	}
+	return x.results(x.searchLayer(unit, []int32{ep}, ef, 0), k)
+}
+
+// Exact scores every vector accepted by filter (nil accepts all) and returns
+// the k most similar. Use it when a filter is too selective for the graph.
+func (x *Index) Exact(query []float32, k int, filter func(id string) bool) []Result {
+	unit := normalize(query)
+	if unit == nil || k <= 0 {
+		return nil
+	}
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	var candidates []candidate
+	for i := range x.nodes {
+		if x.nodes[i].deleted {
+			continue
+		}
+		if filter != nil && !filter(x.nodes[i].id) {
+			continue
+		}
+		candidates = append(candidates, candidate{idx: int32(i), score: dot(unit, x.nodes[i].vector)})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return x.nodes[candidates[i].idx].id < x.nodes[candidates[j].idx].id
+	})
+	return x.results(candidates, k)
+}
+
+// Similarity returns the cosine similarity between query and the vector
+// stored under id
+func (x *Index) Similarity(query []float32, id string) (float64, bool) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	idx, ok := x.ids[id]
+	if !ok {
+		return 0, false
+	}
+	unit := normalize(query)
+	if unit == nil {
+		return 0, true
+	}
+	return dot(unit, x.nodes[idx].vector), true
+}
+
+// maxNeighbors is the degree limit for a layer
+func (x *Index) maxNeighbors(layer int) int {
+	if layer == 0 {
+		return 2 * x.m
+	}
+	return x.m
+}
+
+// link adds a directed edge from -> to, pruning from's farthest neighbours
+// when it exceeds the degree limit
+func (x *Index) link(from, to int32, layer int) {
+	n := &x.nodes[from]
+	n.neighbors[layer] = append(n.neighbors[layer], to)
+	if len(n.neighbors[layer]) <= x.maxNeighbors(layer) {
+		return
+	}
+
+	candidates := make([]candidate, len(n.neighbors[layer]))
+	for i, neighbor := range n.neighbors[layer] {
+		candidates[i] = candidate{idx: neighbor, score: dot(n.vector, x.nodes[neighbor].vector)}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+	n.neighbors[layer] = closest(candidates, x.maxNeighbors(layer))
+}
+
+// greedyClosest walks from ep towards query on one layer until no neighbour
+// is closer
+func (x *Index) greedyClosest(query []float32, ep int32, layer int) int32 {
+	best := dot(query, x.nodes[ep].vector)
+	for changed := true; changed; {
+		changed = false
+		for _, neighbor := range x.nodes[ep].neighbors[layer] {
+			if score := dot(query, x.nodes[neighbor].vector); score > best {
+				best, ep, changed = score, neighbor, true
+			}
+		}
+	}
+	return ep
+}
+
+// searchLayer is a best-first search of one layer keeping the ef closest
+// live nodes found; results are most similar first. Tombstoned nodes are
+// traversed but never kept.
+func (x *Index) searchLayer(query []float32, entryPoints []int32, ef, layer int) []candidate {
+	visited := make(map[int32]bool, ef*4)
+	toVisit := &maxHeap{}
+	found := &minHeap{}
+	for _, ep := range entryPoints {
+		visited[ep] = true
+		c := candidate{idx: ep, score: dot(query, x.nodes[ep].vector)}
+		heap.Push(toVisit, c)
+		if !x.nodes[ep].deleted {
+			heap.Push(found, c)
+		}
+	}
+	for found.Len() > ef {
+		heap.Pop(found)
+	}
+
+	for toVisit.Len() > 0 {
+		current := heap.Pop(toVisit).(candidate)
+		if found.Len() >= ef && current.score < (*found)[0].score {
+			break
+		}
+
+		for _, neighbor := range x.nodes[current.idx].neighbors[layer] {
+			if visited[neighbor] {
+				continue
+			}
+			visited[neighbor] = true
+
+			score := dot(query, x.nodes[neighbor].vector)
+			if found.Len() < ef || score > (*found)[0].score {
+				c := candidate{idx: neighbor, score: score}
+				heap.Push(toVisit, c)
+				if x.nodes[neighbor].deleted {
+					continue
+				}
+				heap.Push(found, c)
+				if found.Len() > ef {
+					heap.Pop(found)
+				}
+			}
+		}
+	}
+
+	results := make([]candidate, found.Len())
+	for i := len(results) - 1; i >= 0; i-- {
+		results[i] = heap.Pop(found).(candidate)
+	}
+	return results
+}
+
+// results converts the first k candidates to Results
+func (x *Index) results(candidates []candidate, k int) []Result {
+	if len(candidates) > k {
+		candidates = candidates[:k]
+	}
+	results := make([]Result, len(candidates))
+	for i, c := range candidates {
+		results[i] = Result{ID: x.nodes[c.idx].id, Score: c.score}
+	}
+	return results
+}
+
+// closest returns the indexes of the first n of candidates sorted by similarity
+func closest(candidates []candidate, n int) []int32 {
+	if len(candidates) > n {
+		candidates = candidates[:n]
+	}
+	indexes := make([]int32, len(candidates))
+	for i, c := range candidates {
+		indexes[i] = c.idx
+	}
+	return indexes
+}
+
+// normalize returns a unit-length copy of v, or nil for a zero vector
+func normalize(v []float32) []float32 {
+	var norm float64
+	for _, value := range v {
+		norm += float64(value) * float64(value)
+	}
+	if norm == 0 {
+		return nil
+	}
+
+	norm = math.Sqrt(norm)
+	unit := make([]float32, len(v))
+	for i, value := range v {
+		unit[i] = float32(float64(value) / norm)
+	}
+	return unit
+}
+
+// dot returns the dot product of two vectors, 0 if their lengths differ
+func dot(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var sum float32
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return float64(sum)
+}
+
+// =============================================================================
+// Candidate Heaps
+// =============================================================================
+
+type candidate struct {
+	idx   int32
+	score float64
+}
+
+// maxHeap pops the most similar candidate first
+type maxHeap []candidate
+
+func (h maxHeap) Len() int            { return len(h) }
+func (h maxHeap) Less(i, j int) bool  { return h[i].score > h[j].score }
+func (h maxHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *maxHeap) Push(x interface{}) { *h = append(*h, x.(candidate)) }
+func (h *maxHeap) Pop() interface{} {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
+
+// minHeap pops the least similar candidate first
+type minHeap []candidate
+
+func (h minHeap) Len() int            { return len(h) }
+func (h minHeap) Less(i, j int) bool  { return h[i].score < h[j].score }
+func (h minHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *minHeap) Push(x interface{}) { *h = append(*h, x.(candidate)) }
+func (h *minHeap) Pop() interface{} {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
@@ -0,0 +1,121 @@
+package ann
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func randomVectors(n, dims int, seed int64) [][]float32 {
+	rng := rand.New(rand.NewSource(seed))
+	vectors := make([][]float32, n)
+	for i := range vectors {
+		vectors[i] = make([]float32, dims)
+		for j := range vectors[i] {
+			vectors[i][j] = float32(rng.NormFloat64())
+		}
+	}
+	return vectors
+}
+
+func TestSearchRecall(t *testing.T) {
+	index := New(16, 200)
+	for i, vector := range randomVectors(2000, 32, 1) {
+		index.Add(fmt.Sprintf("a%d", i), vector)
+	}
+	if index.Len() != 2000 {
+		t.Fatalf("Len() = %d, expected 2000", index.Len())
+	}
+
+	const k = 10
+	hits, total := 0, 0
+	for _, query := range randomVectors(50, 32, 2) {
+		exact := make(map[string]bool)
+		for _, r := range index.Exact(query, k, nil) {
+			exact[r.ID] = true
+		}
+		results := index.Search(query, k, 64)
+		for i, r := range results {
+			if exact[r.ID] {
+				hits++
+			}
+			if i > 0 && r.Score > results[i-1].Score {
+				t.Fatalf("results not sorted by score: %v", results)
+			}
+		}
+		total += k
+	}
+
+	if recall := float64(hits) / float64(total); recall < 0.9 {
+		t.Errorf("recall@%d = %.2f, expected at least 0.9", k, recall)
+	}
+}
+
+func TestExactFilter(t *testing.T) {
+	index := New(8, 50)
+	index.Add("x", []float32{1, 0})
+	index.Add("y", []float32{0.9, 0.1})
+	index.Add("z", []float32{0, 1})
+	index.Add("zero", []float32{0, 0})
+
+	results := index.Exact([]float32{1, 0}, 5, func(id string) bool { return id != "x" })
+	if len(results) != 2 || results[0].ID != "y" || results[1].ID != "z" {
+		t.Errorf("Exact() = %v, expected [y z]", results)
+	}
+
+	if score, ok := index.Similarity([]float32{2, 0}, "x"); !ok || score < 0.999 {
+		t.Errorf("Similarity() = %v, %v; expected 1, true", score, ok)
+	}
+	if _, ok := index.Similarity([]float32{1, 0}, "zero"); ok {
+		t.Error("zero vector was indexed")
+	}
+}
+
+func TestRemoveAndReplace(t *testing.T) {
+	index := New(8, 50)
+	vectors := randomVectors(500, 16, 3)
+	for i, vector := range vectors {
+		index.Add(fmt.Sprintf("a%d", i), vector)
+	}
+
+	// Remove every even-numbered vector, including a0 itself
+	for i := 0; i < 500; i += 2 {
+		if !index.Remove(fmt.Sprintf("a%d", i)) {
+			t.Fatalf("Remove(a%d) = false", i)
+		}
+	}
+	if index.Remove("a0") {
+		t.Error("Remove() of a removed ID = true")
+	}
+	if index.Len() != 250 || index.Deleted() != 250 {
+		t.Errorf("Len(), Deleted() = %d, %d; expected 250, 250", index.Len(), index.Deleted())
+	}
+
+	results := index.Search(vectors[0], 10, 64)
+	if len(results) != 10 {
+		t.Fatalf("Search() returned %d results, expected 10", len(results))
+	}
+	for _, r := range results {
+		var n int
+		fmt.Sscanf(r.ID, "a%d", &n)
+		if n%2 == 0 {
+			t.Errorf("Search() returned removed vector %s", r.ID)
+		}
+	}
+
+	// Replacing a vector moves the ID to the new position
+	index.Add("a1", vectors[0])
+	if results := index.Search(vectors[0], 1, 64); len(results) != 1 || results[0].ID != "a1" {
+		t.Errorf("Search() after replace = %v, expected a1", results)
+	}
+	if index.Len() != 250 || index.Deleted() != 251 {
+		t.Errorf("Len(), Deleted() after replace = %d, %d; expected 250, 251", index.Len(), index.Deleted())
+	}
+}
+
+func TestEmptyIndex(t *testing.T) {
+	index := New(16, 200)
+	if results := index.Search([]float32{1, 0}, 5, 10); results != nil {
+		t.Errorf("Search() on empty index = %v", results)
+	}
+}
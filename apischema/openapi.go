@@ -0,0 +1,365 @@
+package apischema
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OpenAPIVersion is the OpenAPI Specification version of the Spec documents
+const OpenAPIVersion = "3.0.3"
+
+// Endpoint documents what an operation's route doesn't tell: its query
+// parameters and bodies, given as values of the Go types the handler binds
+// and writes
+type Endpoint struct {
+	Summary     string
+	Query       interface{} // struct bound with ShouldBindQuery; its form fields are query parameters
+	Params      []Param     // query parameters the handler reads one by one
+	Body        interface{} // struct bound with ShouldBindJSON
+	Status      int         // success status; 0 is 200
+	Response    interface{} // success body: a struct, a slice or an Object
+	ContentType string      // of the success body; "" is application/json
+	// Articles marks responses offered in every Options.ContentTypes
+	// encoding rather than JSON only
+	Articles bool
+}
+
+// Param is a query parameter
+type Param struct {
+	Name        string
+	Type        string // string (the default), integer, number or boolean
+	Enum        string // name of the Options.Enums list its values come from
+	Required    bool
+	Description string
+}
+
+// Object describes a JSON object the handler assembles itself (a gin.H)
+// by a value of each property's type
+type Object map[string]interface{}
+
+// OpenAPIOptions lists what OpenAPI adds to a Document
+type OpenAPIOptions struct {
+	Title          string
+	Description    string
+	APIKeyPrefixes []string            // path prefixes of the routes that take an API key
+	Endpoints      map[string]Endpoint // by operation name, e.g. "news.getByCategory"
+	Error          interface{}         // body of every error response
+}
+
+// Spec is an OpenAPI 3.0 document
+type Spec struct {
+	OpenAPI    string                               `json:"openapi"`
+	Info       SpecInfo                             `json:"info"`
+	Paths      map[string]map[string]*SpecOperation `json:"paths"` // path -> lowercase method -> operation
+	Components SpecComponents                       `json:"components"`
+}
+
+// SpecInfo is the document's title and API version
+type SpecInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// SpecOperation is one method of a path
+type SpecOperation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []SpecParameter       `json:"parameters,omitempty"`
+	RequestBody *SpecBody             `json:"requestBody,omitempty"`
+	Responses   map[string]SpecBody   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// SpecParameter is a path or query parameter
+type SpecParameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Required    bool    `json:"required,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// SpecBody is a request body or a response
+type SpecBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType is the schema of a body in one encoding
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// SpecComponents holds the schemas operations refer to and the ways
+// requests authenticate
+type SpecComponents struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+// SecurityScheme is an API key or bearer token
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	In     string `json:"in,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Scheme string `json:"scheme,omitempty"`
+}
+
+// Schema is the subset of the OpenAPI schema object the API's types need
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+}
+
+// Security scheme names
+const (
+	securityAPIKey = "apiKey"
+	securityAdmin  = "adminToken"
+)
+
+// OpenAPI renders doc as an OpenAPI 3.0 document. Every route is a path
+// operation; the Endpoints describe their parameters and bodies, and the
+// types they name become component schemas next to doc's types.
+func OpenAPI(doc *Document, opts OpenAPIOptions) *Spec {
+	types := make(map[string]Type, len(doc.Types))
+	for name, t := range doc.Types {
+		types[name] = t
+	}
+
+	spec := &Spec{
+		OpenAPI: OpenAPIVersion,
+		Info:    SpecInfo{Title: opts.Title, Description: opts.Description, Version: doc.APIVersion},
+		Paths:   map[string]map[string]*SpecOperation{},
+		Components: SpecComponents{
+			Schemas: map[string]*Schema{},
+			SecuritySchemes: map[string]SecurityScheme{
+				securityAPIKey: {Type: "apiKey", In: "header", Name: "X-API-Key"},
+				securityAdmin:  {Type: "http", Scheme: "bearer"},
+			},
+		},
+	}
+	var errorBody *SpecBody
+	if opts.Error != nil {
+		errorBody = &SpecBody{Description: "Error", Content: map[string]MediaType{
+			"application/json": {Schema: valueSchema(opts.Error, types)},
+		}}
+	}
+
+	for _, route := range doc.Routes {
+		endpoint := opts.Endpoints[route.Operation]
+		op := &SpecOperation{
+			OperationID: route.Operation,
+			Summary:     endpoint.Summary,
+			Responses:   map[string]SpecBody{},
+		}
+		if tag, _, ok := strings.Cut(route.Operation, "."); ok {
+			op.Tags = []string{tag}
+		}
+		for _, name := range route.PathParams {
+			op.Parameters = append(op.Parameters, SpecParameter{
+				Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"},
+			})
+		}
+		if endpoint.Query != nil {
+			op.Parameters = append(op.Parameters, queryParams(reflect.TypeOf(endpoint.Query), types)...)
+		}
+		for _, param := range endpoint.Params {
+			schema := &Schema{Type: param.Type, Enum: doc.Enums[param.Enum]}
+			if schema.Type == "" {
+				schema.Type = "string"
+			}
+			op.Parameters = append(op.Parameters, SpecParameter{
+				Name: param.Name, In: "query", Required: param.Required, Description: param.Description, Schema: schema,
+			})
+		}
+		if endpoint.Body != nil {
+			op.RequestBody = &SpecBody{Required: true, Content: map[string]MediaType{
+				"application/json": {Schema: valueSchema(endpoint.Body, types)},
+			}}
+		}
+
+		status := endpoint.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		response := SpecBody{Description: http.StatusText(status)}
+		if status != http.StatusNoContent {
+			contentType := endpoint.ContentType
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			schema := valueSchema(endpoint.Response, types)
+			response.Content = map[string]MediaType{contentType: {Schema: schema}}
+			if endpoint.Articles {
+				for _, contentType := range doc.ContentTypes {
+					if _, ok := response.Content[contentType]; !ok {
+						response.Content[contentType] = MediaType{Schema: &Schema{Type: "string", Format: "binary"}}
+					}
+				}
+			}
+		}
+		op.Responses[strconv.Itoa(status)] = response
+		if errorBody != nil {
+			op.Responses["default"] = *errorBody
+		}
+
+		switch {
+		case route.Admin:
+			op.Security = []map[string][]string{{securityAdmin: {}}}
+		case hasPrefix(route.Path, opts.APIKeyPrefixes):
+			op.Security = []map[string][]string{{securityAPIKey: {}}}
+		}
+
+		path := openAPIPath(route.Path)
+		if spec.Paths[path] == nil {
+			spec.Paths[path] = map[string]*SpecOperation{}
+		}
+		spec.Paths[path][strings.ToLower(route.Method)] = op
+	}
+
+	for name, t := range types {
+		spec.Components.Schemas[name] = objectSchema(t)
+	}
+	return spec
+}
+
+// UnknownOperations returns the sorted names of endpoints documented for
+// operations doc has no route for, which are left out of the Spec
+func UnknownOperations(doc *Document, endpoints map[string]Endpoint) []string {
+	known := make(map[string]bool, len(doc.Routes))
+	for _, route := range doc.Routes {
+		known[route.Operation] = true
+	}
+	var unknown []string
+	for name := range endpoints {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// objectSchema is the component schema of a described type; fields the
+// handler requires are required
+func objectSchema(t Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema, len(t.Fields))}
+	for _, field := range t.Fields {
+		property := fieldSchema(field.Type)
+		if field.Format != "" && property.Ref == "" {
+			property.Format = field.Format
+		}
+		schema.Properties[field.Name] = property
+		if field.Required {
+			schema.Required = append(schema.Required, field.Name)
+		}
+	}
+	return schema
+}
+
+// fieldSchema converts a Field.Type to a schema
+func fieldSchema(t string) *Schema {
+	switch {
+	case strings.HasPrefix(t, "array<"):
+		return &Schema{Type: "array", Items: fieldSchema(t[len("array<") : len(t)-1])}
+	case strings.HasPrefix(t, "map<"):
+		return &Schema{Type: "object", AdditionalProperties: fieldSchema(t[len("map<") : len(t)-1])}
+	case t == "any":
+		return &Schema{}
+	case t == "string" || t == "integer" || t == "number" || t == "boolean" || t == "object":
+		return &Schema{Type: t}
+	default:
+		return &Schema{Ref: "#/components/schemas/" + t}
+	}
+}
+
+// valueSchema describes the type of value, adding the named types it
+// refers to to types. An Object, or a slice of one, becomes an inline
+// object schema.
+func valueSchema(value interface{}, types map[string]Type) *Schema {
+	switch value := value.(type) {
+	case nil:
+		return &Schema{Type: "object"}
+	case Object:
+		schema := &Schema{Type: "object", Properties: make(map[string]*Schema, len(value))}
+		for name, property := range value {
+			schema.Properties[name] = valueSchema(property, types)
+		}
+		return schema
+	case []Object:
+		items := &Schema{Type: "object"}
+		if len(value) > 0 {
+			items = valueSchema(value[0], types)
+		}
+		return &Schema{Type: "array", Items: items}
+	default:
+		t := reflect.TypeOf(value)
+		schema := fieldSchema(describe(t, types))
+		if t == timeType {
+			schema.Format = "date-time"
+		}
+		return schema
+	}
+}
+
+// queryParams lists a query struct's fields as parameters, by the form
+// names ShouldBindQuery reads them by
+func queryParams(t reflect.Type, types map[string]Type) []SpecParameter {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	var params []SpecParameter
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			params = append(params, queryParams(sf.Type, types)...)
+			continue
+		}
+		name, _, _ := strings.Cut(sf.Tag.Get("form"), ",")
+		if name == "-" || !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		params = append(params, SpecParameter{
+			Name:     name,
+			In:       "query",
+			Required: strings.Contains(sf.Tag.Get("binding"), "required"),
+			Schema:   fieldSchema(describe(sf.Type, types)),
+		})
+	}
+	return params
+}
+
+// openAPIPath writes gin's :param and *param segments as {param}
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func hasPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
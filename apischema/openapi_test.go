@@ -0,0 +1,151 @@
+package apischema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type testError struct {
+	Message string `json:"message"`
+}
+
+type testBody struct {
+	Name    string   `json:"name" binding:"required"`
+	Radius  *float64 `json:"radius"`
+	Enabled bool     `json:"enabled"`
+}
+
+func testSpec() *Spec {
+	doc := Build(Options{
+		APIVersion: "v1",
+		Routes: gin.RoutesInfo{
+			{Method: "GET", Path: "/api/v1/news/nearby", Handler: "news-backend/handlers.(*NewsHandler).GetNearby-fm"},
+			{Method: "PUT", Path: "/api/v1/users/:id/places/:name", Handler: "news-backend/handlers.(*PlaceHandler).SavePlace-fm"},
+			{Method: "DELETE", Path: "/api/v1/admin/articles/:id", Handler: "news-backend/handlers.(*AdminHandler).DeleteArticle-fm"},
+			{Method: "GET", Path: "/api/v1/health", Handler: "news-backend/handlers.(*NewsHandler).HealthCheck-fm"},
+		},
+		AdminPrefix:  "/api/v1/admin",
+		ContentTypes: []string{"application/json", "application/x-protobuf"},
+		Enums:        map[string][]string{"profiles": {"balanced", "local"}},
+		Types:        []interface{}{testSource{}},
+	})
+	return OpenAPI(doc, OpenAPIOptions{
+		Title:          "Test API",
+		APIKeyPrefixes: []string{"/api/v1/news", "/api/v1/users"},
+		Error:          testError{},
+		Endpoints: map[string]Endpoint{
+			"news.getNearby": {
+				Summary: "News near a location",
+				Query:   testRequest{},
+				Params:  []Param{{Name: "ranking_profile", Enum: "profiles"}},
+				Response: Object{
+					"articles": []testArticle{},
+					"count":    0,
+					"pins":     []Object{{"id": ""}},
+				},
+				Articles: true,
+			},
+			"place.savePlace":     {Body: testBody{}, Response: testSource{}},
+			"admin.deleteArticle": {Status: 204},
+		},
+	})
+}
+
+func TestOpenAPIOperations(t *testing.T) {
+	spec := testSpec()
+	if spec.OpenAPI != OpenAPIVersion || spec.Info.Title != "Test API" || spec.Info.Version != "v1" {
+		t.Errorf("header = %s %+v, expected %s, Test API, v1", spec.OpenAPI, spec.Info, OpenAPIVersion)
+	}
+
+	nearby := spec.Paths["/api/v1/news/nearby"]["get"]
+	if nearby == nil {
+		t.Fatalf("Paths = %v, expected GET /api/v1/news/nearby", spec.Paths)
+	}
+	if nearby.OperationID != "news.getNearby" || nearby.Summary != "News near a location" ||
+		!reflect.DeepEqual(nearby.Tags, []string{"news"}) {
+		t.Errorf("nearby = %+v, expected the operation name, summary and news tag", nearby)
+	}
+	expectedParams := []SpecParameter{
+		{Name: "lat", In: "query", Required: true, Schema: &Schema{Type: "number"}},
+		{Name: "limit", In: "query", Schema: &Schema{Type: "integer"}},
+		{Name: "ranking_profile", In: "query", Schema: &Schema{Type: "string", Enum: []string{"balanced", "local"}}},
+	}
+	if !reflect.DeepEqual(nearby.Parameters, expectedParams) {
+		t.Errorf("nearby parameters = %+v, expected %+v", nearby.Parameters, expectedParams)
+	}
+	ok := nearby.Responses["200"]
+	if articles := ok.Content["application/json"].Schema.Properties["articles"]; articles == nil ||
+		articles.Items == nil || articles.Items.Ref != "#/components/schemas/testArticle" {
+		t.Errorf("nearby articles schema = %+v, expected an array of testArticle", articles)
+	}
+	if pins := ok.Content["application/json"].Schema.Properties["pins"]; pins == nil || pins.Items == nil ||
+		pins.Items.Properties["id"] == nil || pins.Items.Properties["id"].Type != "string" {
+		t.Errorf("nearby pins schema = %+v, expected an array of inline objects", pins)
+	}
+	if protobuf := ok.Content["application/x-protobuf"]; protobuf.Schema == nil || protobuf.Schema.Format != "binary" {
+		t.Errorf("nearby content = %v, expected the article list encodings offered", ok.Content)
+	}
+	if nearby.Responses["default"].Content["application/json"].Schema.Ref != "#/components/schemas/testError" {
+		t.Errorf("nearby default response = %+v, expected the error type", nearby.Responses["default"])
+	}
+	if !reflect.DeepEqual(nearby.Security, []map[string][]string{{"apiKey": {}}}) {
+		t.Errorf("nearby security = %v, expected the API key", nearby.Security)
+	}
+
+	save := spec.Paths["/api/v1/users/{id}/places/{name}"]["put"]
+	if save == nil || len(save.Parameters) != 2 || save.Parameters[1] != (SpecParameter{Name: "name", In: "path", Required: true, Schema: save.Parameters[1].Schema}) {
+		t.Fatalf("save place = %+v, expected {id} and {name} path parameters", save)
+	}
+	if body := save.RequestBody; body == nil || body.Content["application/json"].Schema.Ref != "#/components/schemas/testBody" {
+		t.Errorf("save place body = %+v, expected testBody", body)
+	}
+
+	remove := spec.Paths["/api/v1/admin/articles/{id}"]["delete"]
+	if content := remove.Responses["204"].Content; content != nil {
+		t.Errorf("delete 204 content = %v, expected none", content)
+	}
+	if !reflect.DeepEqual(remove.Security, []map[string][]string{{"adminToken": {}}}) {
+		t.Errorf("delete security = %v, expected the admin token", remove.Security)
+	}
+
+	health := spec.Paths["/api/v1/health"]["get"]
+	if health.Security != nil || health.Responses["200"].Content["application/json"].Schema.Type != "object" {
+		t.Errorf("health = %+v, expected an undocumented public operation", health)
+	}
+}
+
+func TestOpenAPISchemas(t *testing.T) {
+	schemas := testSpec().Components.Schemas
+	for _, name := range []string{"testSource", "testArticle", "testBody", "testError"} {
+		if schemas[name] == nil {
+			t.Errorf("schemas lack %s", name)
+		}
+	}
+
+	body := schemas["testBody"]
+	if !reflect.DeepEqual(body.Required, []string{"name"}) || body.Properties["radius"].Type != "number" {
+		t.Errorf("testBody = %+v, expected name required and radius a number", body)
+	}
+	article := schemas["testArticle"].Properties
+	if article["published"].Format != "date-time" || article["counts"].AdditionalProperties.Type != "integer" ||
+		article["source"].Ref != "#/components/schemas/testSource" {
+		t.Errorf("testArticle properties = %+v, expected date-time, map and reference properties", article)
+	}
+
+	if _, err := json.Marshal(schemas); err != nil {
+		t.Errorf("schemas don't marshal: %v", err)
+	}
+}
+
+func TestUnknownOperations(t *testing.T) {
+	doc := Build(Options{Routes: gin.RoutesInfo{
+		{Method: "GET", Path: "/api/v1/health", Handler: "news-backend/handlers.(*NewsHandler).HealthCheck-fm"},
+	}})
+	unknown := UnknownOperations(doc, map[string]Endpoint{"news.healthCheck": {}, "news.gone": {}})
+	if !reflect.DeepEqual(unknown, []string{"news.gone"}) {
+		t.Errorf("UnknownOperations() = %v, expected [news.gone]", unknown)
+	}
+}
@@ -0,0 +1,235 @@
+// Package apischema describes the API for client SDK generators: its
+// routes, the values its enumerated parameters take and the JSON shape of
+// its request and response types. Routes come from the router and shapes
+// from the Go types, so the description can't drift from the code.
+package apischema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Version is the version of the document format itself, bumped when it
+// changes incompatibly
+const Version = 1
+
+// Document is the machine-readable API description
+type Document struct {
+	SchemaVersion int    `json:"schema_version"`
+	APIVersion    string `json:"api_version"`
+	// Checksum changes whenever the routes, enums, types or content types
+	// do, so generators can tell when clients need regenerating
+	Checksum     string              `json:"checksum"`
+	ContentTypes []string            `json:"content_types"` // encodings article lists are offered in
+	Routes       []Route             `json:"routes"`
+	Enums        map[string][]string `json:"enums"`
+	Types        map[string]Type     `json:"types"`
+}
+
+// Route is one endpoint
+type Route struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// Operation names the endpoint after its handler, e.g.
+	// "news.getByCategory", for the generated client method
+	Operation  string   `json:"operation"`
+	PathParams []string `json:"path_params,omitempty"`
+	Admin      bool     `json:"admin,omitempty"` // authenticated with the admin token rather than an API key
+}
+
+// Type is the JSON shape of a struct
+type Type struct {
+	Fields []Field `json:"fields"`
+}
+
+// Field is one property of a Type. Type is string, integer, number,
+// boolean, any, another type's name, array<T> or map<T> (string keys).
+type Field struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Format   string `json:"format,omitempty"`   // "date-time" for timestamps
+	Optional bool   `json:"optional,omitempty"` // may be left out (omitempty or a pointer)
+	Required bool   `json:"required,omitempty"` // requests without it get 400
+}
+
+// Options lists what Build describes
+type Options struct {
+	APIVersion   string
+	Routes       gin.RoutesInfo
+	AdminPrefix  string // path prefix of the routes that need the admin token
+	ContentTypes []string
+	Enums        map[string][]string
+	Types        []interface{} // values of the request and response types; nested types are added too
+}
+
+// Build assembles the document, with routes sorted by path and method
+func Build(opts Options) *Document {
+	doc := &Document{
+		SchemaVersion: Version,
+		APIVersion:    opts.APIVersion,
+		ContentTypes:  opts.ContentTypes,
+		Routes:        make([]Route, 0, len(opts.Routes)),
+		Enums:         opts.Enums,
+		Types:         map[string]Type{},
+	}
+	for _, info := range opts.Routes {
+		doc.Routes = append(doc.Routes, Route{
+			Method:     info.Method,
+			Path:       info.Path,
+			Operation:  Operation(info.Handler, info.Method, info.Path),
+			PathParams: pathParams(info.Path),
+			Admin:      opts.AdminPrefix != "" && strings.HasPrefix(info.Path, opts.AdminPrefix),
+		})
+	}
+	sort.Slice(doc.Routes, func(i, j int) bool {
+		if doc.Routes[i].Path != doc.Routes[j].Path {
+			return doc.Routes[i].Path < doc.Routes[j].Path
+		}
+		return doc.Routes[i].Method < doc.Routes[j].Method
+	})
+	for _, value := range opts.Types {
+		describe(reflect.TypeOf(value), doc.Types)
+	}
+
+	body, _ := json.Marshal(struct {
+		ContentTypes []string
+		Routes       []Route
+		Enums        map[string][]string
+		Types        map[string]Type
+	}{doc.ContentTypes, doc.Routes, doc.Enums, doc.Types})
+	sum := sha256.Sum256(body)
+	doc.Checksum = hex.EncodeToString(sum[:])
+	return doc
+}
+
+// handlerMethod matches gin's name for a handler method value, e.g.
+// "news-backend/handlers.(*NewsHandler).GetByCategory-fm"
+var handlerMethod = regexp.MustCompile(`\(\*(\w+?)(?:Handler)?\)\.(\w+)-fm$`)
+
+// Operation names an endpoint "<handler>.<method>" after its handler, e.g.
+// "news.getByCategory", or after its method and path for other handlers
+func Operation(handler, method, path string) string {
+	if match := handlerMethod.FindStringSubmatch(handler); match != nil {
+		return lowerFirst(match[1]) + "." + lowerFirst(match[2])
+	}
+	var name strings.Builder
+	name.WriteString(strings.ToLower(method))
+	for _, segment := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '-' || r == '_' || r == ':' || r == '*' || r == '.'
+	}) {
+		name.WriteString(upperFirst(segment))
+	}
+	return name.String()
+}
+
+// pathParams returns the names of a route's :param and *param segments
+func pathParams(path string) []string {
+	var params []string
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			params = append(params, segment[1:])
+		}
+	}
+	return params
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// describe adds a named struct type, and the named structs it refers to,
+// to types, returning the name its fields refer to it by
+func describe(t reflect.Type, types map[string]Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return "string"
+	case t.Kind() == reflect.Struct && t.Name() != "":
+		if _, ok := types[t.Name()]; !ok {
+			types[t.Name()] = Type{} // placeholder, for types that refer to themselves
+			types[t.Name()] = Type{Fields: fields(t, types)}
+		}
+		return t.Name()
+	case t.Kind() == reflect.Struct:
+		return "object"
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string" // base64, as encoding/json writes []byte
+		}
+		return "array<" + describe(t.Elem(), types) + ">"
+	case t.Kind() == reflect.Map:
+		return "map<" + describe(t.Elem(), types) + ">"
+	case t.Kind() == reflect.String:
+		return "string"
+	case t.Kind() == reflect.Bool:
+		return "boolean"
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		return "integer"
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return "number"
+	default:
+		return "any"
+	}
+}
+
+// fields lists a struct's JSON properties, flattening embedded structs as
+// encoding/json does
+func fields(t reflect.Type, types map[string]Type) []Field {
+	var result []Field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, options := parseTag(sf)
+		if name == "-" || (!sf.IsExported() && !sf.Anonymous) {
+			continue
+		}
+		if sf.Anonymous && name == "" && sf.Type.Kind() == reflect.Struct {
+			result = append(result, fields(sf.Type, types)...)
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		field := Field{
+			Name:     name,
+			Type:     describe(sf.Type, types),
+			Optional: strings.Contains(options, "omitempty") || sf.Type.Kind() == reflect.Pointer,
+			Required: strings.Contains(sf.Tag.Get("binding"), "required"),
+		}
+		if base := sf.Type; base == timeType || (base.Kind() == reflect.Pointer && base.Elem() == timeType) {
+			field.Format = "date-time"
+		}
+		result = append(result, field)
+	}
+	return result
+}
+
+// parseTag returns a field's JSON name and options, falling back to its
+// form tag for types only bound from query strings
+func parseTag(sf reflect.StructField) (name, options string) {
+	tag, ok := sf.Tag.Lookup("json")
+	if !ok {
+		tag = sf.Tag.Get("form")
+	}
+	name, options, _ = strings.Cut(tag, ",")
+	return name, options
+}
+
+func lowerFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[size:]
+}
+
+func upperFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
@@ -0,0 +1,120 @@
+package apischema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type testArticle struct {
+	ID        string         `json:"id"`
+	Published time.Time      `json:"published"`
+	Score     float64        `json:"score,omitempty"`
+	Tags      []string       `json:"tags"`
+	Source    *testSource    `json:"source"`
+	Extra     map[string]any `json:"extra"`
+	Counts    map[string]int `json:"counts"`
+	internal  string
+	Hidden    string            `json:"-"`
+	Related   []testArticle     `json:"related"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+type testSource struct {
+	Name string `json:"name"`
+}
+
+type testTrending struct {
+	testArticle
+	Trending float64 `json:"trending_score"`
+}
+
+type testRequest struct {
+	Lat   float64 `form:"lat" binding:"required"`
+	Limit int     `form:"limit"`
+}
+
+func TestBuildTypes(t *testing.T) {
+	doc := Build(Options{Types: []interface{}{testTrending{}, testRequest{}}})
+
+	article, ok := doc.Types["testArticle"]
+	if !ok {
+		t.Fatalf("Types = %v, expected the embedded testArticle described", doc.Types)
+	}
+	expected := map[string]Field{
+		"id":        {Name: "id", Type: "string"},
+		"published": {Name: "published", Type: "string", Format: "date-time"},
+		"score":     {Name: "score", Type: "number", Optional: true},
+		"tags":      {Name: "tags", Type: "array<string>"},
+		"source":    {Name: "source", Type: "testSource", Optional: true},
+		"extra":     {Name: "extra", Type: "map<any>"},
+		"counts":    {Name: "counts", Type: "map<integer>"},
+		"related":   {Name: "related", Type: "array<testArticle>"},
+		"labels":    {Name: "labels", Type: "map<string>", Optional: true},
+	}
+	if len(article.Fields) != len(expected) {
+		t.Errorf("testArticle fields = %+v, expected %d", article.Fields, len(expected))
+	}
+	for _, field := range article.Fields {
+		if field != expected[field.Name] {
+			t.Errorf("field %q = %+v, expected %+v", field.Name, field, expected[field.Name])
+		}
+	}
+	if _, ok := doc.Types["testSource"]; !ok {
+		t.Error("Types lacks testSource, expected nested types added")
+	}
+
+	trending := doc.Types["testTrending"].Fields
+	if len(trending) != len(expected)+1 || trending[len(trending)-1].Name != "trending_score" {
+		t.Errorf("testTrending fields = %+v, expected the embedded fields flattened", trending)
+	}
+
+	request := doc.Types["testRequest"].Fields
+	if len(request) != 2 || request[0] != (Field{Name: "lat", Type: "number", Required: true}) {
+		t.Errorf("testRequest fields = %+v, expected form names and lat required", request)
+	}
+}
+
+func TestBuildRoutes(t *testing.T) {
+	routes := gin.RoutesInfo{
+		{Method: "GET", Path: "/api/v1/news/category", Handler: "news-backend/handlers.(*NewsHandler).GetByCategory-fm"},
+		{Method: "DELETE", Path: "/api/v1/users/:id/links/:linked_id", Handler: "news-backend/handlers.(*UserHandler).UnlinkUser-fm"},
+		{Method: "GET", Path: "/api/v1/admin/metrics", Handler: "news-backend/handlers.(*AdminHandler).GetMetrics-fm"},
+		{Method: "GET", Path: "/api/v1/news/article/:id/summary/stream", Handler: "main.main.func3"},
+	}
+	doc := Build(Options{Routes: routes, AdminPrefix: "/api/v1/admin"})
+
+	expected := []Route{
+		{Method: "GET", Path: "/api/v1/admin/metrics", Operation: "admin.getMetrics", Admin: true},
+		{Method: "GET", Path: "/api/v1/news/article/:id/summary/stream", Operation: "getApiV1NewsArticleIdSummaryStream", PathParams: []string{"id"}},
+		{Method: "GET", Path: "/api/v1/news/category", Operation: "news.getByCategory"},
+		{Method: "DELETE", Path: "/api/v1/users/:id/links/:linked_id", Operation: "user.unlinkUser", PathParams: []string{"id", "linked_id"}},
+	}
+	if len(doc.Routes) != len(expected) {
+		t.Fatalf("Routes = %+v, expected %d", doc.Routes, len(expected))
+	}
+	for i, route := range doc.Routes {
+		want := expected[i]
+		if route.Method != want.Method || route.Path != want.Path || route.Operation != want.Operation ||
+			route.Admin != want.Admin || len(route.PathParams) != len(want.PathParams) {
+			t.Errorf("Routes[%d] = %+v, expected %+v", i, route, want)
+		}
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	opts := Options{
+		Enums: map[string][]string{"intents": {"category", "search"}},
+		Types: []interface{}{testSource{}},
+	}
+	first := Build(opts).Checksum
+	if again := Build(opts).Checksum; again != first {
+		t.Errorf("Checksum = %s then %s, expected stable", first, again)
+	}
+
+	opts.Enums = map[string][]string{"intents": {"category", "search", "score"}}
+	if changed := Build(opts).Checksum; changed == first {
+		t.Error("Checksum unchanged after an enum value was added")
+	}
+}
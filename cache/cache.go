@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Supported backends
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+)
+
+// Cache stores encoded values under string keys. Implementations must be
+// safe for concurrent use. A zero ttl means the entry never expires.
+type Cache interface {
+	// Get returns the value for key, or false when it is missing or expired
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes keys and reports how many existed
+	Delete(ctx context.Context, keys ...string) (int, error)
+	// Keys lists the keys starting with prefix, in no particular order
+	Keys(ctx context.Context, prefix string) ([]string, error)
+}
+
+// New creates the cache for backend: in-process memory (the default), or
+// Redis at redisURL so several instances share entries
+func New(backend, redisURL string) (Cache, error) {
+	switch backend {
+	case "", BackendMemory:
+		return NewMemory(), nil
+	case BackendRedis:
+		if redisURL == "" {
+			return nil, fmt.Errorf("cache backend %q requires a Redis URL", backend)
+		}
+		return NewRedis(redisURL)
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q", backend)
+	}
+}
+
+// Clear deletes every key starting with prefix
+func Clear(ctx context.Context, c Cache, prefix string) (int, error) {
+	keys, err := c.Keys(ctx, prefix)
+	if err != nil || len(keys) == 0 {
+		return 0, err
+	}
+	return c.Delete(ctx, keys...)
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	value   []byte
+	expires time.Time // zero for no expiry
+}
+
+// Memory keeps entries in this process. Expired entries are dropped when
+// next read or listed.
+type Memory struct {
+	entries sync.Map // key -> *memoryEntry
+}
+
+// NewMemory creates an empty in-process cache
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+func (m *Memory) Get(_ context.Context, key string) ([]byte, bool, error) {
+	value, ok := m.entries.Load(key)
+	if !ok {
+		return nil, false, nil
+	}
+	entry := value.(*memoryEntry)
+	if entry.expired(time.Now()) {
+		m.entries.CompareAndDelete(key, value)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+func (m *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	entry := &memoryEntry{value: value}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+	m.entries.Store(key, entry)
+	return nil
+}
+
+func (m *Memory) Delete(_ context.Context, keys ...string) (int, error) {
+	deleted := 0
+	for _, key := range keys {
+		if value, loaded := m.entries.LoadAndDelete(key); loaded && !value.(*memoryEntry).expired(time.Now()) {
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (m *Memory) Keys(_ context.Context, prefix string) ([]string, error) {
+	now := time.Now()
+	keys := []string{}
+	m.entries.Range(func(key, value interface{}) bool {
+		if value.(*memoryEntry).expired(now) {
+			m.entries.CompareAndDelete(key, value)
+		} else if k := key.(string); strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+		return true
+	})
+	return keys, nil
+}
+
+func (e *memoryEntry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && now.After(e.expires)
+}
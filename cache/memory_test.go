@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+
+	m.Set(ctx, "trending:a", []byte("1"), time.Minute)
+	m.Set(ctx, "trending:b", []byte("2"), 0)
+	m.Set(ctx, "trending:expired", []byte("3"), time.Nanosecond)
+	m.Set(ctx, "summary:a", []byte("4"), 0)
+	time.Sleep(time.Millisecond)
+
+	if value, ok, _ := m.Get(ctx, "trending:a"); !ok || string(value) != "1" {
+		t.Errorf("Get(trending:a) = %q, %v", value, ok)
+	}
+	if _, ok, _ := m.Get(ctx, "trending:expired"); ok {
+		t.Error("expired entry returned")
+	}
+
+	keys, _ := m.Keys(ctx, "trending:")
+	sort.Strings(keys)
+	if len(keys) != 2 || keys[0] != "trending:a" || keys[1] != "trending:b" {
+		t.Errorf("Keys(trending:) = %v", keys)
+	}
+
+	if deleted, _ := m.Delete(ctx, "trending:a", "missing"); deleted != 1 {
+		t.Errorf("Delete() = %d, expected 1", deleted)
+	}
+	if cleared, _ := Clear(ctx, m, "trending:"); cleared != 1 {
+		t.Errorf("Clear() = %d, expected 1", cleared)
+	}
+	if _, ok, _ := m.Get(ctx, "summary:a"); !ok {
+		t.Error("Clear() removed an entry outside its prefix")
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		backend  string
+		redisURL string
+		wantErr  bool
+	}{
+		{"", "", false},
+		{BackendMemory, "", false},
+		{BackendRedis, "", true},
+		{BackendRedis, "redis://localhost:6379/0", false},
+		{BackendRedis, "not a url", true},
+		{"memcached", "", true},
+	}
+
+	for _, tt := range tests {
+		_, err := New(tt.backend, tt.redisURL)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("New(%q, %q) error = %v, wantErr %v", tt.backend, tt.redisURL, err, tt.wantErr)
+		}
+	}
+}
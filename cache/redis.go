@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// redisKeyPrefix namespaces cache entries in a Redis shared with other data
+	redisKeyPrefix = "news-backend:cache:"
+	// redisTimeout bounds each operation so a slow Redis degrades to misses
+	redisTimeout = time.Second
+	// redisScanCount is the batch size hinted to SCAN
+	redisScanCount = 500
+)
+
+// Redis stores entries in Redis so every instance pointed at it shares them
+type Redis struct {
+	client *redis.Client
+}
+
+// NewRedis connects to the Redis server at url (redis://host:6379/0)
+func NewRedis(url string) (*Redis, error) {
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	return &Redis{client: redis.NewClient(options)}, nil
+}
+
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+	value, err := r.client.Get(ctx, redisKeyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+	return r.client.Set(ctx, redisKeyPrefix+key, value, ttl).Err()
+}
+
+func (r *Redis) Delete(ctx context.Context, keys ...string) (int, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = redisKeyPrefix + key
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+	deleted, err := r.client.Del(ctx, prefixed...).Result()
+	return int(deleted), err
+}
+
+func (r *Redis) Keys(ctx context.Context, prefix string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+
+	keys := []string{}
+	iter := r.client.Scan(ctx, 0, redisKeyPrefix+escapeGlob(prefix)+"*", redisScanCount).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, strings.TrimPrefix(iter.Val(), redisKeyPrefix))
+	}
+	return keys, iter.Err()
+}
+
+// Close releases the connection pool
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
+
+// escapeGlob quotes the characters SCAN MATCH treats as a pattern
+func escapeGlob(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`).Replace(s)
+}
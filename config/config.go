@@ -1,68 +1,518 @@
 package config
 
 import (
-	"log"
+	"errors"
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 )
 
 type Config struct {
 	// Server Configuration
 	ServerPort string
+	GRPCPort   string // port of the gRPC API, empty disables it
+	AdminToken string // bearer token for /api/v1/admin, empty disables it
+	AdminAuthDisabled bool // leaves /api/v1/admin open, for local development only
+	ShutdownTimeout int // seconds to drain requests and workers on SIGTERM
+	HealthLLMCheckInterval int // seconds between LLM reachability checks for readiness probes
+	TrustedProxies     string // comma-separated IPs and CIDRs whose X-Forwarded-For is believed
+	CORSAllowedOrigins string // comma-separated origins, "*" for any
+	CORSAllowedMethods string // comma-separated methods allowed in preflights
+	CORSMaxAge         int    // seconds browsers may cache a preflight
+	HSTSMaxAge         int    // Strict-Transport-Security max-age, 0 = not sent
+	SwaggerUIURL       string // where /docs loads the Swagger UI scripts and styles from
+	PublicURL          string // base URL clients reach the API at, for absolute links; empty uses the request's host
+	CompressionAlgorithms string // comma-separated preference order, e.g. "br,gzip"; "none" disables
+	CompressionMinSize    int    // bytes; smaller responses are sent uncompressed
+
+	// Public API Access
+	APIKeys         string // comma-separated full-access keys; with DemoAPIKey, empty leaves the API open
+	DemoAPIKey      string // key for the rate-limited demo tier, empty disables
+	DemoRateLimit   int    // demo requests per minute per client IP
+	DemoMaxArticles int    // articles per demo response
+	// Request cost limits per tier; 0 = unlimited. RequestLimitMode is
+	// "clamp" (reduce excessive values, noted in metadata) or "reject" (400).
+	MaxRadiusKm           float64
+	MaxWindowDays         int // widest from/to span
+	DemoMaxRadiusKm       float64
+	DemoMaxWindowDays     int
+	RequestLimitMode      string
+	RedactionRules        string // response fields hidden per key, "<key|demo|*>:<fields>[@<sources>];..."
+	RateLimitPerMinute int    // requests per minute per API key or IP, 0 = unlimited
+	RateLimitRoutes    string // per-route overrides, "route=perMinute,..."
+	PublicStatsRateLimit int  // unauthenticated /stats/public requests per minute per IP, 0 = unlimited
+
+	// Edge Cache Configuration
+	EdgeCacheNewsTTL     int    // seconds shared caches may keep news responses, 0 disables
+	EdgeCacheTrendingTTL int    // seconds shared caches may keep trending responses, 0 disables
+	EdgeCacheLatestTTL   int    // seconds shared caches may keep the first page of latest news, 0 disables
+	EdgeCacheImageTTL    int    // seconds shared caches may keep proxied images, 0 disables
+	CDNPurgeURL          string // endpoint receiving surrogate-key purges, empty disables
+	CDNPurgeToken        string // bearer token for the purge endpoint
+
+	// Reverse Geocoding (trending location names)
+	GeocoderURL       string // Nominatim-compatible /reverse endpoint, empty reports coordinates
+	GeocoderTimeoutMs int
+	GeocodeCacheTTL   int // seconds a grid cell's name is reused
+
+	// In-process Response Cache
+	ResponseCacheTTL        int // seconds search/category/source responses are reused, 0 disables
+	ResponseCacheMaxEntries int
+
+	// Traffic Shadowing Configuration
+	ShadowUpstreamURL string  // secondary deployment receiving mirrored reads, empty disables
+	ShadowPercent     float64 // share of GET requests mirrored, 0-100
+	ShadowTimeoutMs   int
+	ShadowAPIKey      string // key sent with mirrored reads, empty forwards the caller's
+
+	// Multi-instance Cache Invalidation
+	RedisURL            string // redis://host:6379/0, empty runs single-instance
+	InvalidationChannel string // pub/sub channel shared by all instances
+	CacheBackend        string // "memory" or "redis" (shares trending and summaries via REDIS_URL)
 	
 	// Database Configuration
 	DatabasePath string
+	NewsDataFile string // JSON dataset loaded at startup and by reloads
+	MigrateOnStart bool // apply pending schema migrations at startup; off refuses to start with any pending
 	
 	// LLM Configuration
-	LLMProvider    string // "openai" or "groq"
+	LLMProvider    string // "openai", "groq", a priority list like "groq,openai", or "none"
+	LLMProviders   []LLMProviderConfig
+	LLMOffline     bool // LLM_PROVIDER=none: rule-based intents and description summaries, no API key needed
+	LLMBreakerThreshold int // consecutive failures before a provider is skipped
+	LLMBreakerCooldown  int // seconds a tripped provider is skipped
+	LLMMaxRPM           int // max LLM requests per minute, 0 = unlimited
+	LLMDailyTokenBudget int // max tokens per UTC day, 0 = unlimited
+	LLMTimeoutMs        int // per-call timeout for each provider attempt, 0 = none
+	LLMAuditPercent     float64 // share of LLM calls recorded for debugging, 0-100
+	LLMAuditRetentionDays int   // days audit records are kept
+	LLMAuditMaxEntries  int     // most audit records kept
+	IntentRules         bool    // resolve structured queries without the LLM
 	OpenAIKey      string
 	GroqKey        string
 	LLMBaseURL     string
 	IntentModel    string
 	SummaryModel   string
+	EmbeddingModel string
+	EmbeddingsEnabled bool
+	EmbeddingIndexRefresh  int // seconds between embedding new articles into the index, 0 loads once at startup
+	EmbeddingIndexCompactRatio float64 // tombstoned share of the index that triggers a rebuild
+	EmbeddingIndexM        int // HNSW neighbours per node
+	EmbeddingIndexEfSearch int // HNSW candidate list size per query
+	UserInterestHalfLifeHours float64 // hours after which an event counts half in a user's interest vector
+	UserInterestWeight     float64 // share of the personal ranking signal taken by interest similarity
 	
 	// Business Logic Configuration
 	DefaultRadius      float64
 	MaxArticlesReturn  int
+	MaxLimit           int // largest limit a request may ask for
 	ScoreThreshold     float64
 	
+	// Hybrid Search Weights
+	HybridTextWeight      float64
+	HybridRelevanceWeight float64
+	HybridSemanticWeight  float64
+	// How hybrid and ranking signals are rescaled per request before
+	// weighting: "minmax", "zscore" or "none"
+	ScoreNormalization string
+	// Comma-separated article text keyword search matches: title,
+	// description, summary (LLM summary) and topics (extracted topic names)
+	SearchFields string
+	
 	// Trending Configuration
 	TrendingCacheTTL   int // seconds
 	TrendingRadius     float64
 	TrendingTimeWindow int // hours
+	TrendingRefreshInterval int // seconds between materializing trending scores, 0 computes once at startup
+	EditionRefreshInterval  int // seconds between precomputing local edition feeds, 0 computes once at startup
+	EventRollupInterval     int // seconds between rolling up events older than the trending window, 0 disables
+	EventRetentionDays      int // days raw user events are kept once rolled up, 0 keeps them forever
+	EventQueueMax           int // event writes in flight before new events get 429, 0 unbounded
+	ArticleArchiveAfterDays int // days after publication articles move to articles_archive, 0 keeps them
+	ArticleArchiveInterval  int // seconds between archival passes, 0 disables
+	// TrendingFallbackFreshness is how many time windows old an article may be
+	// to appear in the no-events fallback
+	TrendingFallbackFreshness float64
+	// Nearby articles' score multiplier falls from TrendingProximityBoost at
+	// 0km along TrendingProximityCurve ("linear", "exponential" or "none")
+	TrendingProximityCurve   string
+	TrendingProximityBoost   float64
+	TrendingProximityScaleKm float64 // linear: no boost from here; exponential: extra boost halves every ScaleKm
+	// Anti-gaming: only a user's first TrendingMaxEventsPerUser events on an
+	// article count toward its score (0 = unlimited), and articles with at
+	// least TrendingAnomalyMinEvents events averaging more than
+	// TrendingAnomalyEventsPerUser per distinct user are dropped (0 disables)
+	TrendingMaxEventsPerUser     int
+	TrendingAnomalyMinEvents     int
+	TrendingAnomalyEventsPerUser float64
+	// Aggregate privacy for published engagement counts: cells with fewer
+	// than AggregateMinUsers distinct users are suppressed and counts get
+	// Laplace noise of scale 1/AggregateNoiseEpsilon (0 disables either)
+	AggregateMinUsers     int
+	AggregateNoiseEpsilon float64
+	AggregateNoiseSecret  string // seeds the noise; random per process when empty
+
+	// Relevance Refresh Configuration
+	RelevanceRefreshInterval  int     // seconds, 0 disables the worker
+	RelevanceEngagementWeight float64 // share of current_relevance driven by engagement
+	RelevanceLookbackHours    int     // event window used for engagement
+
+	// Ingest Configuration
+	IngestInterval int // seconds between connector runs, 0 disables
+	IngestQueueMax int // articles stored per source run, the rest are dropped; 0 unbounded
+
+	// Content Fetching Configuration
+	ContentFetchInterval       int     // seconds between batches, 0 disables
+	ContentFetchBatch          int     // articles fetched per batch
+	ContentFetchDomainInterval float64 // minimum seconds between requests to one host
+	ContentFetchTimeout        int     // seconds per request
+	ContentFetchUserAgent      string  // also selects the robots.txt rules that apply
+
+	// Image Proxy Configuration
+	ImageProxyTimeout  int // seconds per image download
+	ImageProxyMaxBytes int // largest image downloaded
+
+	// Summary Pre-generation Configuration
+	SummaryWorkerInterval int // seconds between batches, 0 disables
+	SummaryWorkerBatch    int // articles summarized per batch
+	SummaryMaxAttempts    int // failed or empty generations before an article is skipped
+	SummaryQueueMax       int // articles awaiting a summary above which ingest runs are deferred, 0 unbounded
+
+	// Sentiment Tagging Configuration
+	SentimentClassifier     string // "lexicon" or "llm"
+	SentimentWorkerInterval int    // seconds between batches, 0 disables
+	SentimentWorkerBatch    int    // articles tagged per batch
+
+	// Topic Extraction Configuration
+	TopicWorkerInterval int // seconds between extraction batches, 0 disables
+	TopicWorkerBatch    int // articles processed per batch
+
+	// Daily Digest Configuration
+	DigestRadiusKm   float64 // area around lat/lon the digest covers
+	DigestArticles   int     // articles briefed per digest
+	DigestCycleHours int     // digests are cached until the next UTC-aligned cycle
+
+	// Question Answering Configuration
+	AskCandidates      int // articles retrieved per method (keyword, semantic) before ranking
+	AskContextArticles int // top-ranked articles given to the LLM
+
+	// Conversational Query Configuration
+	QuerySessionTTL int // minutes a query session lasts after its last turn
+
+	// Summary Tone Experiment Configuration
+	SummaryExperimentArms     string  // comma-separated tones users without a stored tone are split between, empty disables
+	SummaryExperimentWarmup   int     // views every arm needs before traffic shifts toward the better ones
+	SummaryExperimentMinShare float64 // share of traffic every arm keeps after warmup
+	SummaryExperimentMaxBadRate float64 // bad_summary reports per view that stop an arm after warmup, 0 disables
+	SummaryExperimentAssignmentHours int // how long a user keeps a tone before being drawn again
+
+	// Source Reliability Configuration
+	SourceDefaultReliability float64 // rating of sources without a reliability_score
+	SourceReliabilityWeight  float64 // score change per unit of rating above or below the default
+
+	// Feed Composition Configuration
+	// Semicolon-separated slots of the user's local day, e.g.
+	// morning@5-11:briefing=0.6,trending=0.3,long_read=0.1,digest
+	FeedComposition string
+	FeedWindowHours int // how far back from the newest article feed candidates go
+	FeedSize        int // articles per feed when no limit is given
+
+	// Story Clustering Configuration
+	StoryClusterInterval     int     // seconds between clustering passes, 0 disables
+	StorySimilarityThreshold float64 // title+description shingle Jaccard that joins two articles
+	StoryWindowHours         int     // max publication gap between articles in one story
+	StoryCollapse            bool    // show one article per story in search and trending
+
+	// Webhook Configuration
+	WebhookURLs   string // comma-separated
+	WebhookSecret string // HMAC-SHA256 signing secret
+
+	// Feedback Configuration
+	FeedbackBlocklistThreshold int // open bad_summary reports that drop a summary, 0 disables
+
+	// SLO Configuration
+	SLOAvailabilityTarget float64 // fraction of requests that must not 5xx
+	SLOLatencyTarget      float64 // fraction of requests that must finish within the threshold
+	SLOLatencyThresholdMs int
+	SLOBurnRateThreshold  float64 // burn rate (in both windows) that triggers an alert
+	SLOCheckInterval      int     // seconds between burn-rate checks, 0 disables alerts
+	SLOMinRequests        int     // requests both windows need before they can alert
+
+	// Training Snapshot Configuration
+	SnapshotStoreURL     string // s3://bucket/prefix, or a local directory
+	SnapshotS3Endpoint   string // S3-compatible endpoint, empty is AWS
+	SnapshotS3Region     string
+	SnapshotPseudonymKey string // HMAC key for user and device IDs, empty draws one per snapshot
+	AWSAccessKeyID       string
+	AWSSecretAccessKey   string
+	AWSSessionToken      string
+
+	// Problems found while loading: values that couldn't be parsed, which
+	// keep their defaults, and an unusable LLM_PROVIDER
+	Problems []error
+}
+
+// LLMProviderConfig holds connection settings for one provider in the fallback chain
+type LLMProviderConfig struct {
+	Name         string
+	APIKey       string
+	BaseURL      string
+	IntentModel  string
+	SummaryModel string
 }
 
 var AppConfig *Config
 
+// loadProblems collects the problems found by LoadConfig
+var loadProblems []error
+
+// LoadConfig reads the configuration from the environment. It doesn't stop
+// at invalid values; they are listed in Problems.
 func LoadConfig() *Config {
+	loadProblems = nil
 	AppConfig = &Config{
 		ServerPort:         getEnv("PORT", "8080"),
+		GRPCPort:           os.Getenv("GRPC_PORT"),
+		AdminToken:         os.Getenv("ADMIN_TOKEN"),
+		AdminAuthDisabled:  getEnvBool("ADMIN_AUTH_DISABLED", false),
+		ShutdownTimeout:    getEnvInt("SHUTDOWN_TIMEOUT", 15),
+		HealthLLMCheckInterval: getEnvInt("HEALTH_LLM_CHECK_INTERVAL", 60),
+		TrustedProxies:     getEnv("TRUSTED_PROXIES", ""),
+		CORSAllowedOrigins: getEnv("CORS_ALLOWED_ORIGINS", "*"),
+		CORSAllowedMethods: getEnv("CORS_ALLOWED_METHODS", "GET,POST,PUT,DELETE,PATCH,OPTIONS"),
+		CORSMaxAge:         getEnvInt("CORS_MAX_AGE", 600),
+		HSTSMaxAge:         getEnvInt("HSTS_MAX_AGE", 0),
+		SwaggerUIURL:       getEnv("SWAGGER_UI_URL", "https://unpkg.com/swagger-ui-dist@5.17.14"),
+		PublicURL:          strings.TrimSuffix(os.Getenv("PUBLIC_URL"), "/"),
+		APIKeys:            os.Getenv("API_KEYS"),
+		DemoAPIKey:         os.Getenv("DEMO_API_KEY"),
+		DemoRateLimit:      getEnvInt("DEMO_RATE_LIMIT", 30),
+		DemoMaxArticles:    getEnvInt("DEMO_MAX_ARTICLES", 3),
+		MaxRadiusKm:        getEnvFloat("MAX_RADIUS_KM", 500),
+		MaxWindowDays:      getEnvInt("MAX_WINDOW_DAYS", 366),
+		DemoMaxRadiusKm:    getEnvFloat("DEMO_MAX_RADIUS_KM", 100),
+		DemoMaxWindowDays:  getEnvInt("DEMO_MAX_WINDOW_DAYS", 31),
+		RequestLimitMode:   getEnv("REQUEST_LIMIT_MODE", "clamp"),
+		RedactionRules:     os.Getenv("REDACTION_RULES"),
+		RateLimitPerMinute: getEnvInt("RATE_LIMIT_PER_MINUTE", 120),
+		RateLimitRoutes:    getEnv("RATE_LIMIT_ROUTES", "/api/v1/news/search=30,/api/v1/news/semantic-search=30,/api/v1/news/ask=30,/api/v1/news/query=30"),
+		PublicStatsRateLimit: getEnvInt("PUBLIC_STATS_RATE_LIMIT", 30),
+		CompressionAlgorithms: getEnv("COMPRESSION_ALGORITHMS", "br,gzip"),
+		CompressionMinSize:    getEnvInt("COMPRESSION_MIN_SIZE", 1024),
+		EdgeCacheNewsTTL:     getEnvInt("EDGE_CACHE_NEWS_TTL", 300),
+		EdgeCacheTrendingTTL: getEnvInt("EDGE_CACHE_TRENDING_TTL", 60),
+		EdgeCacheLatestTTL:   getEnvInt("EDGE_CACHE_LATEST_TTL", 60),
+		EdgeCacheImageTTL:    getEnvInt("EDGE_CACHE_IMAGE_TTL", 86400),
+		CDNPurgeURL:          os.Getenv("CDN_PURGE_URL"),
+		CDNPurgeToken:        os.Getenv("CDN_PURGE_TOKEN"),
+		GeocoderURL:          os.Getenv("GEOCODER_URL"),
+		GeocoderTimeoutMs:    getEnvInt("GEOCODER_TIMEOUT_MS", 2000),
+		GeocodeCacheTTL:      getEnvInt("GEOCODE_CACHE_TTL", 604800),
+		ResponseCacheTTL:        getEnvInt("RESPONSE_CACHE_TTL", 60),
+		ResponseCacheMaxEntries: getEnvInt("RESPONSE_CACHE_MAX_ENTRIES", 1000),
+		ShadowUpstreamURL:    os.Getenv("SHADOW_UPSTREAM_URL"),
+		ShadowPercent:        getEnvFloat("SHADOW_PERCENT", 0),
+		ShadowTimeoutMs:      getEnvInt("SHADOW_TIMEOUT_MS", 5000),
+		ShadowAPIKey:         os.Getenv("SHADOW_API_KEY"),
+		RedisURL:             os.Getenv("REDIS_URL"),
+		InvalidationChannel:  getEnv("INVALIDATION_CHANNEL", "news-backend:invalidate"),
+		CacheBackend:         getEnv("CACHE_BACKEND", "memory"),
 		DatabasePath:       getEnv("DB_PATH", "news.db"),
+		NewsDataFile:       getEnv("NEWS_DATA_FILE", "news_data.json"),
+		MigrateOnStart:     getEnvBool("MIGRATE_ON_START", true),
 		LLMProvider:        getEnv("LLM_PROVIDER", "groq"),
 		OpenAIKey:          os.Getenv("OPENAI_API_KEY"),
 		GroqKey:            os.Getenv("GROQ_API_KEY"),
 		LLMBaseURL:         getEnv("GROQ_BASE_URL", "https://api.groq.com/openai/v1"),
 		IntentModel:        getEnv("INTENT_MODEL", "llama-3.3-70b-versatile"),
 		SummaryModel:       getEnv("SUMMARY_MODEL", "llama-3.1-8b-instant"),
+		EmbeddingModel:     getEnv("EMBEDDING_MODEL", "text-embedding-3-small"),
+		EmbeddingsEnabled:  getEnvBool("EMBEDDINGS_ENABLED", false),
+		EmbeddingIndexRefresh:  getEnvInt("EMBEDDING_INDEX_REFRESH", 300),
+		EmbeddingIndexCompactRatio: getEnvFloat("EMBEDDING_INDEX_COMPACT_RATIO", 0.2),
+		EmbeddingIndexM:        getEnvInt("EMBEDDING_INDEX_M", 16),
+		EmbeddingIndexEfSearch: getEnvInt("EMBEDDING_INDEX_EF_SEARCH", 64),
+		UserInterestHalfLifeHours: getEnvFloat("USER_INTEREST_HALF_LIFE_HOURS", 72),
+		UserInterestWeight:     getEnvFloat("USER_INTEREST_WEIGHT", 0.5),
 		DefaultRadius:      getEnvFloat("DEFAULT_RADIUS", 10.0),
 		MaxArticlesReturn:  getEnvInt("MAX_ARTICLES", 5),
+		MaxLimit:           getEnvInt("MAX_LIMIT", 50),
 		ScoreThreshold:     getEnvFloat("SCORE_THRESHOLD", 0.7),
+		HybridTextWeight:      getEnvFloat("HYBRID_TEXT_WEIGHT", 0.4),
+		HybridRelevanceWeight: getEnvFloat("HYBRID_RELEVANCE_WEIGHT", 0.2),
+		HybridSemanticWeight:  getEnvFloat("HYBRID_SEMANTIC_WEIGHT", 0.4),
+		ScoreNormalization:    getEnv("SCORE_NORMALIZATION", "minmax"),
+		SearchFields:          getEnv("SEARCH_FIELDS", "title,description"),
 		TrendingCacheTTL:   getEnvInt("TRENDING_CACHE_TTL", 300),
 		TrendingRadius:     getEnvFloat("TRENDING_RADIUS", 50.0),
 		TrendingTimeWindow: getEnvInt("TRENDING_TIME_WINDOW", 24),
+		TrendingRefreshInterval: getEnvInt("TRENDING_REFRESH_INTERVAL", 60),
+		EditionRefreshInterval:  getEnvInt("EDITION_REFRESH_INTERVAL", 300),
+		EventRollupInterval:     getEnvInt("EVENT_ROLLUP_INTERVAL", 3600),
+		EventRetentionDays:      getEnvInt("EVENT_RETENTION_DAYS", 30),
+		EventQueueMax:           getEnvInt("EVENT_QUEUE_MAX", 256),
+		ArticleArchiveAfterDays: getEnvInt("ARTICLE_ARCHIVE_AFTER_DAYS", 0),
+		ArticleArchiveInterval:  getEnvInt("ARTICLE_ARCHIVE_INTERVAL", 3600),
+		TrendingFallbackFreshness: getEnvFloat("TRENDING_FALLBACK_FRESHNESS", 3.0),
+		TrendingProximityCurve:    getEnv("TRENDING_PROXIMITY_CURVE", "exponential"),
+		TrendingProximityBoost:    getEnvFloat("TRENDING_PROXIMITY_BOOST", 1.5),
+		TrendingProximityScaleKm:  getEnvFloat("TRENDING_PROXIMITY_SCALE_KM", 10),
+		TrendingMaxEventsPerUser:     getEnvInt("TRENDING_MAX_EVENTS_PER_USER", 5),
+		TrendingAnomalyMinEvents:     getEnvInt("TRENDING_ANOMALY_MIN_EVENTS", 20),
+		TrendingAnomalyEventsPerUser: getEnvFloat("TRENDING_ANOMALY_EVENTS_PER_USER", 10),
+		AggregateMinUsers:            getEnvInt("AGGREGATE_MIN_USERS", 0),
+		AggregateNoiseEpsilon:        getEnvFloat("AGGREGATE_NOISE_EPSILON", 0),
+		AggregateNoiseSecret:         getEnv("AGGREGATE_NOISE_SECRET", ""),
+
+		LLMBreakerThreshold: getEnvInt("LLM_BREAKER_THRESHOLD", 3),
+		LLMBreakerCooldown:  getEnvInt("LLM_BREAKER_COOLDOWN", 30),
+		LLMMaxRPM:           getEnvInt("LLM_MAX_RPM", 0),
+		LLMDailyTokenBudget: getEnvInt("LLM_DAILY_TOKEN_BUDGET", 0),
+		LLMTimeoutMs:        getEnvInt("LLM_TIMEOUT_MS", 10000),
+		LLMAuditPercent:     getEnvFloat("LLM_AUDIT_PERCENT", 0),
+		LLMAuditRetentionDays: getEnvInt("LLM_AUDIT_RETENTION_DAYS", 7),
+		LLMAuditMaxEntries:  getEnvInt("LLM_AUDIT_MAX_ENTRIES", 5000),
+		IntentRules:         getEnvBool("INTENT_RULES", true),
+
+		RelevanceRefreshInterval:  getEnvInt("RELEVANCE_REFRESH_INTERVAL", 900),
+		RelevanceEngagementWeight: getEnvFloat("RELEVANCE_ENGAGEMENT_WEIGHT", 0.3),
+		RelevanceLookbackHours:    getEnvInt("RELEVANCE_LOOKBACK_HOURS", 72),
+
+		IngestInterval: getEnvInt("INGEST_INTERVAL", 3600),
+		IngestQueueMax: getEnvInt("INGEST_QUEUE_MAX", 1000),
+
+		ContentFetchInterval:       getEnvInt("CONTENT_FETCH_INTERVAL", 0),
+		ContentFetchBatch:          getEnvInt("CONTENT_FETCH_BATCH", 50),
+		ContentFetchDomainInterval: getEnvFloat("CONTENT_FETCH_DOMAIN_INTERVAL", 5),
+		ContentFetchTimeout:        getEnvInt("CONTENT_FETCH_TIMEOUT", 15),
+		ContentFetchUserAgent:      getEnv("CONTENT_FETCH_USER_AGENT", "news-backend/1.0"),
+
+		ImageProxyTimeout:  getEnvInt("IMAGE_PROXY_TIMEOUT", 10),
+		ImageProxyMaxBytes: getEnvInt("IMAGE_PROXY_MAX_BYTES", 10<<20),
+
+		SummaryWorkerInterval: getEnvInt("SUMMARY_WORKER_INTERVAL", 60),
+		SummaryWorkerBatch:    getEnvInt("SUMMARY_WORKER_BATCH", 10),
+		SummaryMaxAttempts:    getEnvInt("SUMMARY_MAX_ATTEMPTS", 3),
+		SummaryQueueMax:       getEnvInt("SUMMARY_QUEUE_MAX", 5000),
+
+		SentimentClassifier:     getEnv("SENTIMENT_CLASSIFIER", "lexicon"),
+		SentimentWorkerInterval: getEnvInt("SENTIMENT_WORKER_INTERVAL", 60),
+		SentimentWorkerBatch:    getEnvInt("SENTIMENT_WORKER_BATCH", 50),
+
+		TopicWorkerInterval: getEnvInt("TOPIC_WORKER_INTERVAL", 60),
+		TopicWorkerBatch:    getEnvInt("TOPIC_WORKER_BATCH", 10),
+
+		DigestRadiusKm:   getEnvFloat("DIGEST_RADIUS_KM", 50),
+		DigestArticles:   getEnvInt("DIGEST_ARTICLES", 8),
+		DigestCycleHours: getEnvInt("DIGEST_CYCLE_HOURS", 6),
+
+		AskCandidates:      getEnvInt("ASK_CANDIDATES", 50),
+		AskContextArticles: getEnvInt("ASK_CONTEXT_ARTICLES", 5),
+
+		QuerySessionTTL: getEnvInt("QUERY_SESSION_TTL", 30),
+
+		SummaryExperimentArms:     os.Getenv("SUMMARY_EXPERIMENT_ARMS"),
+		SummaryExperimentWarmup:   getEnvInt("SUMMARY_EXPERIMENT_WARMUP", 200),
+		SummaryExperimentMinShare: getEnvFloat("SUMMARY_EXPERIMENT_MIN_SHARE", 0.1),
+		SummaryExperimentMaxBadRate: getEnvFloat("SUMMARY_EXPERIMENT_MAX_BAD_RATE", 0.05),
+		SummaryExperimentAssignmentHours: getEnvInt("SUMMARY_EXPERIMENT_ASSIGNMENT_HOURS", 24),
+
+		SourceDefaultReliability: getEnvFloat("SOURCE_DEFAULT_RELIABILITY", 0.5),
+		SourceReliabilityWeight:  getEnvFloat("SOURCE_RELIABILITY_WEIGHT", 0.4),
+
+		FeedComposition: getEnv("FEED_COMPOSITION", "morning@5-11:briefing=0.6,trending=0.3,long_read=0.1,digest;"+
+			"daytime@11-17:trending=0.5,briefing=0.3,long_read=0.2;"+
+			"evening@17-23:long_read=0.5,trending=0.3,briefing=0.2;"+
+			"night@23-5:trending=0.4,long_read=0.4,briefing=0.2"),
+		FeedWindowHours: getEnvInt("FEED_WINDOW_HOURS", 48),
+		FeedSize:        getEnvInt("FEED_SIZE", 10),
+
+		StoryClusterInterval:     getEnvInt("STORY_CLUSTER_INTERVAL", 600),
+		StorySimilarityThreshold: getEnvFloat("STORY_SIMILARITY_THRESHOLD", 0.6),
+		StoryWindowHours:         getEnvInt("STORY_WINDOW_HOURS", 48),
+		StoryCollapse:            getEnvBool("STORY_COLLAPSE", true),
+
+		WebhookURLs:   os.Getenv("WEBHOOK_URLS"),
+		WebhookSecret: os.Getenv("WEBHOOK_SECRET"),
+
+		FeedbackBlocklistThreshold: getEnvInt("FEEDBACK_BLOCKLIST_THRESHOLD", 0),
+
+		SLOAvailabilityTarget: getEnvFloat("SLO_AVAILABILITY_TARGET", 0.99),
+		SLOLatencyTarget:      getEnvFloat("SLO_LATENCY_TARGET", 0.95),
+		SLOLatencyThresholdMs: getEnvInt("SLO_LATENCY_THRESHOLD_MS", 1000),
+		SLOBurnRateThreshold:  getEnvFloat("SLO_BURN_RATE_THRESHOLD", 14.4),
+		SLOCheckInterval:      getEnvInt("SLO_CHECK_INTERVAL", 60),
+		SLOMinRequests:        getEnvInt("SLO_MIN_REQUESTS", 20),
+
+		SnapshotStoreURL:     getEnv("SNAPSHOT_STORE_URL", "./snapshots"),
+		SnapshotS3Endpoint:   os.Getenv("SNAPSHOT_S3_ENDPOINT"),
+		SnapshotS3Region:     getEnv("SNAPSHOT_S3_REGION", "us-east-1"),
+		SnapshotPseudonymKey: os.Getenv("SNAPSHOT_PSEUDONYM_KEY"),
+		AWSAccessKeyID:       os.Getenv("AWS_ACCESS_KEY_ID"),
+		AWSSecretAccessKey:   os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		AWSSessionToken:      os.Getenv("AWS_SESSION_TOKEN"),
 	}
 	
-	// Validate required configuration
-	if AppConfig.LLMProvider == "openai" && AppConfig.OpenAIKey == "" {
-		log.Fatal("OPENAI_API_KEY is required when LLM_PROVIDER is 'openai'")
+	// Build and validate the provider chain; "none" runs without an LLM
+	if strings.EqualFold(strings.TrimSpace(AppConfig.LLMProvider), "none") {
+		AppConfig.LLMOffline = true
+		AppConfig.Problems = loadProblems
+		return AppConfig
 	}
-	if AppConfig.LLMProvider == "groq" && AppConfig.GroqKey == "" {
-		log.Fatal("GROQ_API_KEY is required when LLM_PROVIDER is 'groq'")
+	names := 0
+	for _, name := range strings.Split(AppConfig.LLMProvider, ",") {
+		name = strings.TrimSpace(strings.ToLower(name))
+		if name == "" {
+			continue
+		}
+		names++
+		provider, err := loadProviderConfig(AppConfig, name)
+		if err != nil {
+			loadProblems = append(loadProblems, err)
+			continue
+		}
+		AppConfig.LLMProviders = append(AppConfig.LLMProviders, provider)
+	}
+	if names == 0 {
+		loadProblems = append(loadProblems, errors.New("LLM_PROVIDER must name at least one provider (openai, groq) or be none"))
 	}
 	
+	AppConfig.Problems = loadProblems
 	return AppConfig
 }
 
+// loadProviderConfig resolves credentials and models for a named provider
+// Models default to INTENT_MODEL/SUMMARY_MODEL and can be overridden per provider
+func loadProviderConfig(cfg *Config, name string) (LLMProviderConfig, error) {
+	provider := LLMProviderConfig{Name: name}
+
+	switch name {
+	case "openai":
+		provider.APIKey = cfg.OpenAIKey
+		provider.BaseURL = os.Getenv("OPENAI_BASE_URL")
+		provider.IntentModel = getEnv("OPENAI_INTENT_MODEL", cfg.IntentModel)
+		provider.SummaryModel = getEnv("OPENAI_SUMMARY_MODEL", cfg.SummaryModel)
+		if provider.APIKey == "" {
+			return provider, errors.New("OPENAI_API_KEY is required when LLM_PROVIDER includes 'openai'")
+		}
+	case "groq":
+		provider.APIKey = cfg.GroqKey
+		provider.BaseURL = cfg.LLMBaseURL
+		provider.IntentModel = getEnv("GROQ_INTENT_MODEL", cfg.IntentModel)
+		provider.SummaryModel = getEnv("GROQ_SUMMARY_MODEL", cfg.SummaryModel)
+		if provider.APIKey == "" {
+			return provider, errors.New("GROQ_API_KEY is required when LLM_PROVIDER includes 'groq'")
+		}
+	case "none":
+		return provider, errors.New("LLM_PROVIDER=none can't be combined with other providers")
+	default:
+		return provider, fmt.Errorf("invalid LLM provider %q in LLM_PROVIDER: expected openai, groq or none", name)
+	}
+
+	return provider, nil
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -75,6 +525,7 @@ func getEnvInt(key string, defaultValue int) int {
 		if intVal, err := strconv.Atoi(value); err == nil {
 			return intVal
 		}
+		invalidValue(key, value, "an integer")
 	}
 	return defaultValue
 }
@@ -84,6 +535,22 @@ func getEnvFloat(key string, defaultValue float64) float64 {
 		if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
 			return floatVal
 		}
+		invalidValue(key, value, "a number")
 	}
 	return defaultValue
 }
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolVal, err := strconv.ParseBool(value); err == nil {
+			return boolVal
+		}
+		invalidValue(key, value, "true or false")
+	}
+	return defaultValue
+}
+
+// invalidValue records that key's value couldn't be parsed as expected
+func invalidValue(key, value, expected string) {
+	loadProblems = append(loadProblems, fmt.Errorf("invalid %s %q: expected %s", key, value, expected))
+}
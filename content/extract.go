@@ -0,0 +1,224 @@
+// Package content fetches article pages and extracts their readable body
+// text and lead image. Extraction scores blocks of paragraphs the way
+// Readability does; fetching honours robots.txt and spaces requests to the
+// same host.
+package content
+
+import (
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ErrNoContent is returned when a page has no paragraphs of article text
+var ErrNoContent = errors.New("no readable content")
+
+// Page is what Extract finds in an article page
+type Page struct {
+	Text string
+	// ImageURL is the page's og:image (or twitter:image) as written, which
+	// may be relative to the page
+	ImageURL string
+}
+
+// imageProperties are the meta tags naming a page's lead image, preferred
+// in this order
+var imageProperties = []string{"og:image:secure_url", "og:image", "og:image:url", "twitter:image", "twitter:image:src"}
+
+// minParagraphLength is how long a paragraph must be to count as article
+// text rather than a caption, byline or button label
+const minParagraphLength = 25
+
+// skipped elements never hold article text
+var skipped = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true,
+	atom.Nav: true, atom.Header: true, atom.Footer: true, atom.Aside: true,
+	atom.Form: true, atom.Button: true, atom.Iframe: true, atom.Svg: true,
+	atom.Figcaption: true, atom.Select: true,
+}
+
+// breaks are the elements that separate the text around them
+var breaks = map[atom.Atom]bool{
+	atom.Br: true, atom.P: true, atom.Div: true, atom.Li: true, atom.Td: true, atom.Th: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+}
+
+// Class and id hints, as in Readability
+var (
+	positiveHint = regexp.MustCompile(`(?i)article|body|content|entry|main|post|story|text`)
+	negativeHint = regexp.MustCompile(`(?i)comment|footer|sidebar|widget|related|share|social|promo|sponsor|advert|\bads?\b|newsletter|subscribe|cookie|nav|menu|breadcrumb`)
+)
+
+// Extract returns the readable body text of an HTML page, the paragraphs of
+// the block that holds the most article-like text separated by blank lines,
+// and its lead image
+func Extract(r io.Reader) (*Page, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every paragraph scores its parent fully and its grandparent half,
+	// so the element wrapping the article body collects the most
+	scores := make(map[*html.Node]float64)
+	var candidates, paragraphs []*html.Node
+	credit := func(n *html.Node, score float64) {
+		if _, ok := scores[n]; !ok {
+			candidates = append(candidates, n)
+		}
+		scores[n] += score
+	}
+	walk(doc, func(n *html.Node) {
+		if n.DataAtom != atom.P && n.DataAtom != atom.Pre {
+			return
+		}
+		text := nodeText(n)
+		if len(text) < minParagraphLength {
+			return
+		}
+		paragraphs = append(paragraphs, n)
+		score := 1 + float64(strings.Count(text, ",")) + min(float64(len(text))/100, 3)
+		if parent := n.Parent; parent != nil {
+			credit(parent, score)
+			if grandparent := parent.Parent; grandparent != nil {
+				credit(grandparent, score/2)
+			}
+		}
+	})
+	if len(paragraphs) == 0 {
+		return nil, ErrNoContent
+	}
+
+	var best *html.Node
+	bestScore := 0.0
+	for _, n := range candidates {
+		score := (scores[n] + classWeight(n)) * (1 - linkDensity(n))
+		if best == nil || score > bestScore {
+			best, bestScore = n, score
+		}
+	}
+
+	var blocks []string
+	for _, p := range paragraphs {
+		if contains(best, p) {
+			blocks = append(blocks, nodeText(p))
+		}
+	}
+	return &Page{Text: strings.Join(blocks, "\n\n"), ImageURL: leadImage(doc)}, nil
+}
+
+// leadImage returns the image the page's meta tags name for sharing
+func leadImage(doc *html.Node) string {
+	found := make(map[string]string)
+	var find func(*html.Node)
+	find = func(n *html.Node) {
+		if n.DataAtom == atom.Meta {
+			var key, value string
+			for _, attr := range n.Attr {
+				switch attr.Key {
+				case "property", "name":
+					key = strings.ToLower(strings.TrimSpace(attr.Val))
+				case "content":
+					value = strings.TrimSpace(attr.Val)
+				}
+			}
+			if _, ok := found[key]; !ok && value != "" {
+				found[key] = value
+			}
+		}
+		// Meta tags outside the head are still honoured, as browsers do
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			find(child)
+		}
+	}
+	find(doc)
+
+	for _, property := range imageProperties {
+		if image, ok := found[property]; ok {
+			return image
+		}
+	}
+	return ""
+}
+
+// walk calls visit on n and its descendants, leaving out skipped elements
+// and those hinted to hold something other than the article
+func walk(n *html.Node, visit func(*html.Node)) {
+	if n.Type == html.ElementNode && (skipped[n.DataAtom] || (classWeight(n) < 0 && n.DataAtom != atom.Body)) {
+		return
+	}
+	visit(n)
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		walk(child, visit)
+	}
+}
+
+// classWeight scores an element's class and id: +25 for each that hints at
+// article text, -25 for each that hints at comments, navigation or ads
+func classWeight(n *html.Node) float64 {
+	weight := 0.0
+	for _, attr := range n.Attr {
+		if attr.Key != "class" && attr.Key != "id" {
+			continue
+		}
+		if negativeHint.MatchString(attr.Val) {
+			weight -= 25
+		}
+		if positiveHint.MatchString(attr.Val) {
+			weight += 25
+		}
+	}
+	return weight
+}
+
+// linkDensity is the share of n's text inside links
+func linkDensity(n *html.Node) float64 {
+	total := len(nodeText(n))
+	if total == 0 {
+		return 0
+	}
+	linked := 0
+	walk(n, func(child *html.Node) {
+		if child.DataAtom == atom.A {
+			linked += len(nodeText(child))
+		}
+	})
+	return min(float64(linked)/float64(total), 1)
+}
+
+// contains reports whether node is ancestor or one of its descendants
+func contains(ancestor, node *html.Node) bool {
+	for ; node != nil; node = node.Parent {
+		if node == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeText returns the text under n with whitespace collapsed
+func nodeText(n *html.Node) string {
+	var b strings.Builder
+	var collect func(*html.Node)
+	collect = func(n *html.Node) {
+		if n.Type == html.ElementNode && skipped[n.DataAtom] {
+			return
+		}
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		// Words on either side of a line break or block don't run together
+		if breaks[n.DataAtom] {
+			b.WriteByte(' ')
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			collect(child)
+		}
+	}
+	collect(n)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
@@ -0,0 +1,67 @@
+package content
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const articlePage = `<!DOCTYPE html>
+<html><head><title>Rains lash the coast</title>
+<meta name="twitter:image" content="https://example.com/small.jpg">
+<meta property="og:image" content="https://example.com/lead.jpg"><script>var p = "<p>not text, not text, not text</p>";</script></head>
+<body>
+<header><nav><a href="/">Home</a> <a href="/world">World</a></nav></header>
+<div class="sidebar"><p>Most read: a long list of other headlines, stories and links.</p></div>
+<div class="article-body">
+  <h1>Rains lash the coast</h1>
+  <p>Heavy rains lashed the coast on Tuesday, flooding roads, homes and fields across three districts.</p>
+  <figure><img src="a.jpg"><figcaption>Flooded roads in the district on Tuesday morning.</figcaption></figure>
+  <p>Officials said relief camps were opened, and <a href="/x">more than 2,000 people</a> were moved to safety.</p>
+  <p>Share</p>
+  <p>The weather office expects the rains to ease by Thursday, though fishermen were told to stay ashore.</p>
+</div>
+<div id="comments"><p>Great article, thanks for sharing this with all of us here today!</p></div>
+<footer><p>Copyright 2025 Example News, all rights reserved, worldwide.</p></footer>
+</body></html>`
+
+func TestExtract(t *testing.T) {
+	page, err := Extract(strings.NewReader(articlePage))
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if page.ImageURL != "https://example.com/lead.jpg" {
+		t.Errorf("Extract() image = %q, expected the og:image", page.ImageURL)
+	}
+	text := page.Text
+	paragraphs := strings.Split(text, "\n\n")
+	if len(paragraphs) != 3 {
+		t.Fatalf("Extract() = %q, expected the three article paragraphs", text)
+	}
+	if !strings.HasPrefix(paragraphs[0], "Heavy rains lashed the coast") ||
+		paragraphs[1] != "Officials said relief camps were opened, and more than 2,000 people were moved to safety." {
+		t.Errorf("Extract() = %q, expected the article paragraphs in order", text)
+	}
+	for _, unwanted := range []string{"Most read", "Great article", "Copyright", "Flooded roads", "Home", "not text"} {
+		if strings.Contains(text, unwanted) {
+			t.Errorf("Extract() kept %q: %q", unwanted, text)
+		}
+	}
+}
+
+func TestExtractWithoutParagraphs(t *testing.T) {
+	_, err := Extract(strings.NewReader(`<html><body><nav><p>Only navigation, with links to every section.</p></nav><p>Short</p></body></html>`))
+	if !errors.Is(err, ErrNoContent) {
+		t.Errorf("Extract() error = %v, expected ErrNoContent", err)
+	}
+}
+
+func TestExtractWithoutImage(t *testing.T) {
+	page, err := Extract(strings.NewReader(`<html><body><p>A page with a paragraph long enough to count, but no image.</p></body></html>`))
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if page.ImageURL != "" {
+		t.Errorf("Extract() image = %q, expected none", page.ImageURL)
+	}
+}
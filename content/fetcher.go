@@ -0,0 +1,190 @@
+package content
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Errors Fetch reports besides failed requests
+var (
+	ErrDisallowed = errors.New("disallowed by robots.txt")
+	ErrNotHTML    = errors.New("not an HTML page")
+	// ErrRobotsUnavailable is returned while a host's robots.txt can't be
+	// read; as RFC 9309 asks, nothing is fetched from the host meanwhile
+	ErrRobotsUnavailable = errors.New("robots.txt unavailable")
+)
+
+const (
+	maxPageBytes   = 2 << 20
+	maxRobotsBytes = 512 << 10
+	robotsTTL      = 24 * time.Hour
+	// robotsRetry is how long a host whose robots.txt couldn't be read is
+	// left alone before it is asked again
+	robotsRetry = time.Hour
+)
+
+// Fetcher downloads article pages and extracts their text. Requests to a
+// host are at least interval apart, or its robots.txt Crawl-delay when that
+// is longer, and paths its robots.txt disallows for the user agent aren't
+// fetched. It is safe for concurrent use; requests to one host queue up.
+type Fetcher struct {
+	client    *http.Client
+	userAgent string
+	interval  time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*host
+}
+
+// host is the state kept per scheme and host name
+type host struct {
+	mu          sync.Mutex // held while waiting for and sending a request
+	next        time.Time  // earliest time of the next request
+	robots      *Robots
+	robotsErr   error // why robots.txt couldn't be read, when robots is nil
+	robotsUntil time.Time
+}
+
+// NewFetcher creates a fetcher identifying itself as userAgent, which also
+// selects the robots.txt rules that apply
+func NewFetcher(client *http.Client, userAgent string, interval time.Duration) *Fetcher {
+	return &Fetcher{
+		client:    client,
+		userAgent: userAgent,
+		interval:  interval,
+		hosts:     make(map[string]*host),
+	}
+}
+
+// Fetch returns the readable text and lead image of the HTML page at
+// rawURL. The image URL is made absolute; images that aren't on http or
+// https are dropped.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (*Page, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("unsupported URL %q", rawURL)
+	}
+	origin := u.Scheme + "://" + strings.ToLower(u.Host)
+	h := f.host(origin)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	robots, err := f.robots(ctx, h, origin)
+	if err != nil {
+		return nil, err
+	}
+	if !robots.Allowed(u.RequestURI()) {
+		return nil, ErrDisallowed
+	}
+
+	resp, err := f.get(ctx, h, robots.CrawlDelay(), u.String(), "text/html")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil &&
+		mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, fmt.Errorf("%w: %s", ErrNotHTML, mediaType)
+	}
+	page, err := Extract(io.LimitReader(resp.Body, maxPageBytes))
+	if err != nil {
+		return nil, err
+	}
+	if page.ImageURL != "" {
+		// Relative to the page after redirects
+		image, err := resp.Request.URL.Parse(page.ImageURL)
+		if err != nil || (image.Scheme != "http" && image.Scheme != "https") {
+			page.ImageURL = ""
+		} else {
+			page.ImageURL = image.String()
+		}
+	}
+	return page, nil
+}
+
+func (f *Fetcher) host(origin string) *host {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	h, ok := f.hosts[origin]
+	if !ok {
+		h = &host{}
+		f.hosts[origin] = h
+	}
+	return h
+}
+
+// robots returns the host's rules, reading its robots.txt when they are
+// missing or stale. As RFC 9309 asks, a missing file (4xx) allows
+// everything, while an unreachable one or a server error is reported as
+// ErrRobotsUnavailable for robotsRetry. Called with h.mu held.
+func (f *Fetcher) robots(ctx context.Context, h *host, origin string) (*Robots, error) {
+	if time.Now().Before(h.robotsUntil) {
+		return h.robots, h.robotsErr
+	}
+
+	robots, err := f.readRobots(ctx, h, origin)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		h.robots, h.robotsErr, h.robotsUntil = nil, fmt.Errorf("%w: %v", ErrRobotsUnavailable, err), time.Now().Add(robotsRetry)
+	} else {
+		h.robots, h.robotsErr, h.robotsUntil = robots, nil, time.Now().Add(robotsTTL)
+	}
+	return h.robots, h.robotsErr
+}
+
+// readRobots requests and parses the host's robots.txt
+func (f *Fetcher) readRobots(ctx context.Context, h *host, origin string) (*Robots, error) {
+	resp, err := f.get(ctx, h, 0, origin+"/robots.txt", "text/plain")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return ParseRobots(io.LimitReader(resp.Body, maxRobotsBytes), f.userAgent)
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return allowAll, nil
+	default:
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+}
+
+// get waits for the host's next request slot, books the one after it and
+// sends a GET. Called with h.mu held.
+func (f *Fetcher) get(ctx context.Context, h *host, delay time.Duration, target, accept string) (*http.Response, error) {
+	if wait := time.Until(h.next); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	h.next = time.Now().Add(max(f.interval, delay))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", f.userAgent)
+	req.Header.Set("Accept", accept)
+	return f.client.Do(req)
+}
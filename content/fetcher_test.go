@@ -0,0 +1,119 @@
+package content
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFetcher(t *testing.T) {
+	var mu sync.Mutex
+	var robotsRequests int
+	var requests []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, time.Now())
+		mu.Unlock()
+		if got := r.Header.Get("User-Agent"); got != "news-backend/1.0" {
+			t.Errorf("User-Agent = %q, expected news-backend/1.0", got)
+		}
+		switch r.URL.Path {
+		case "/robots.txt":
+			mu.Lock()
+			robotsRequests++
+			mu.Unlock()
+			fmt.Fprint(w, "User-agent: *\nDisallow: /private/\n")
+		case "/feed.json":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{}`)
+		case "/missing":
+			http.NotFound(w, r)
+		default:
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprintf(w, `<html><head><meta property="og:image" content="../img/lead.jpg"></head><body><article><p>The story at %s, told at length for the test.</p></article></body></html>`, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	interval := 50 * time.Millisecond
+	fetcher := NewFetcher(server.Client(), "news-backend/1.0", interval)
+	ctx := context.Background()
+
+	page, err := fetcher.Fetch(ctx, server.URL+"/world/a")
+	if err != nil || !strings.Contains(page.Text, "The story at /world/a") {
+		t.Fatalf("Fetch() = %+v, %v, expected the page text", page, err)
+	}
+	if page.ImageURL != server.URL+"/img/lead.jpg" {
+		t.Errorf("Fetch() image = %q, expected the og:image resolved against the page", page.ImageURL)
+	}
+	if _, err := fetcher.Fetch(ctx, server.URL+"/world/b"); err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if _, err := fetcher.Fetch(ctx, server.URL+"/private/c"); !errors.Is(err, ErrDisallowed) {
+		t.Errorf("Fetch() of a disallowed path error = %v, expected ErrDisallowed", err)
+	}
+	if _, err := fetcher.Fetch(ctx, server.URL+"/feed.json"); !errors.Is(err, ErrNotHTML) {
+		t.Errorf("Fetch() of JSON error = %v, expected ErrNotHTML", err)
+	}
+	if _, err := fetcher.Fetch(ctx, server.URL+"/missing"); err == nil {
+		t.Error("Fetch() of a missing page succeeded")
+	}
+	if _, err := fetcher.Fetch(ctx, "ftp://example.com/a"); err == nil {
+		t.Error("Fetch() of an ftp URL succeeded")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if robotsRequests != 1 {
+		t.Errorf("robots.txt was requested %d times, expected once", robotsRequests)
+	}
+	for i := 1; i < len(requests); i++ {
+		if gap := requests[i].Sub(requests[i-1]); gap < interval-5*time.Millisecond {
+			t.Errorf("request %d came %v after the previous one, expected at least %v", i, gap, interval)
+		}
+	}
+}
+
+func TestFetcherUnreachableRobots(t *testing.T) {
+	robotsRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			robotsRequests++
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		t.Errorf("page %s was fetched while robots.txt was unavailable", r.URL.Path)
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher(server.Client(), "news-backend/1.0", 0)
+	for i := 0; i < 2; i++ {
+		if _, err := fetcher.Fetch(context.Background(), server.URL+"/world/a"); !errors.Is(err, ErrRobotsUnavailable) {
+			t.Errorf("Fetch() error = %v, expected ErrRobotsUnavailable", err)
+		}
+	}
+	if robotsRequests != 1 {
+		t.Errorf("robots.txt was requested %d times, expected once until robotsRetry passes", robotsRequests)
+	}
+}
+
+func TestFetcherCancelledWait(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher(server.Client(), "news-backend/1.0", time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	// The robots.txt request takes the slot; the page would wait an hour
+	if _, err := fetcher.Fetch(ctx, server.URL+"/world/a"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Fetch() error = %v, expected the context's deadline", err)
+	}
+}
@@ -0,0 +1,143 @@
+package content
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Robots holds the rules of a robots.txt file (RFC 9309) that apply to one
+// user agent
+type Robots struct {
+	rules      []robotsRule
+	crawlDelay time.Duration
+}
+
+type robotsRule struct {
+	allow   bool
+	pattern string // may hold * wildcards and end with $
+}
+
+// allowAll applies when a site has no robots.txt
+var allowAll = &Robots{}
+
+// ParseRobots reads the rules of the group naming userAgent, matched by its
+// product token case-insensitively, or else of the * group. Crawl-delay,
+// though not in the RFC, is kept as many news sites set it.
+func ParseRobots(r io.Reader, userAgent string) (*Robots, error) {
+	token := strings.ToLower(userAgent)
+	if i := strings.IndexAny(token, "/ "); i >= 0 {
+		token = token[:i]
+	}
+
+	var named, wildcard *Robots
+	var current []*Robots // groups the lines being read belong to
+	inAgents := false     // consecutive user-agent lines start one group
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		if key == "user-agent" {
+			if !inAgents {
+				current = nil
+			}
+			inAgents = true
+			agent := strings.ToLower(value)
+			switch {
+			case agent == "*":
+				if wildcard == nil {
+					wildcard = &Robots{}
+				}
+				current = append(current, wildcard)
+			case agent == token:
+				if named == nil {
+					named = &Robots{}
+				}
+				current = append(current, named)
+			}
+			continue
+		}
+		inAgents = false
+		for _, group := range current {
+			switch key {
+			case "allow", "disallow":
+				// An empty disallow allows everything
+				if value != "" {
+					group.rules = append(group.rules, robotsRule{allow: key == "allow", pattern: value})
+				}
+			case "crawl-delay":
+				if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+					group.crawlDelay = time.Duration(seconds * float64(time.Second))
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case named != nil:
+		return named, nil
+	case wildcard != nil:
+		return wildcard, nil
+	default:
+		return allowAll, nil
+	}
+}
+
+// Allowed reports whether path (with its query) may be fetched: the longest
+// matching rule decides, allow winning a tie, and unmatched paths are allowed
+func (r *Robots) Allowed(path string) bool {
+	if path == "" {
+		path = "/"
+	}
+	allowed, longest := true, -1
+	for _, rule := range r.rules {
+		if !matchRobotsPattern(rule.pattern, path) {
+			continue
+		}
+		if n := len(rule.pattern); n > longest || (n == longest && rule.allow) {
+			allowed, longest = rule.allow, n
+		}
+	}
+	return allowed
+}
+
+// CrawlDelay is the pause the site asks for between requests, 0 for none
+func (r *Robots) CrawlDelay() time.Duration {
+	return r.crawlDelay
+}
+
+// matchRobotsPattern matches path against a rule's pattern: a prefix match
+// where * stands for any characters and a trailing $ anchors the end
+func matchRobotsPattern(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+	parts := strings.Split(pattern, "*")
+
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	for i, part := range parts[1:] {
+		// The last part of an anchored pattern must end the path
+		if anchored && i == len(parts)-2 {
+			return strings.HasSuffix(rest, part)
+		}
+		j := strings.Index(rest, part)
+		if j < 0 {
+			return false
+		}
+		rest = rest[j+len(part):]
+	}
+	return !anchored || rest == ""
+}
@@ -0,0 +1,74 @@
+package content
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const robotsTxt = `# Example
+User-agent: *
+Disallow: /private/
+Disallow: /*.pdf$
+Allow: /private/press/
+Crawl-delay: 2
+
+User-agent: BadBot
+User-agent: news-backend
+Disallow: /amp/
+Allow: /amp/world/
+
+User-agent: other
+Disallow: /
+`
+
+func TestParseRobotsWildcardGroup(t *testing.T) {
+	robots, err := ParseRobots(strings.NewReader(robotsTxt), "SomeCrawler/2.0")
+	if err != nil {
+		t.Fatalf("ParseRobots() error = %v", err)
+	}
+	tests := map[string]bool{
+		"/world/story.html":      true,
+		"/private/notes":         false,
+		"/private/press/release": true, // the longer allow wins
+		"/files/report.pdf":      false,
+		"/files/report.pdf?dl=1": true, // $ anchors the end
+		"/amp/india/story.html":  true,
+		"":                       true,
+	}
+	for path, expected := range tests {
+		if got := robots.Allowed(path); got != expected {
+			t.Errorf("Allowed(%q) = %v, expected %v", path, got, expected)
+		}
+	}
+	if robots.CrawlDelay() != 2*time.Second {
+		t.Errorf("CrawlDelay() = %v, expected 2s", robots.CrawlDelay())
+	}
+}
+
+func TestParseRobotsNamedGroup(t *testing.T) {
+	// The agent's own group replaces the * group entirely
+	robots, err := ParseRobots(strings.NewReader(robotsTxt), "News-Backend/1.0")
+	if err != nil {
+		t.Fatalf("ParseRobots() error = %v", err)
+	}
+	if robots.Allowed("/amp/india/story.html") || !robots.Allowed("/amp/world/story.html") || !robots.Allowed("/private/notes") {
+		t.Error("Allowed() didn't apply the news-backend group")
+	}
+	if robots.CrawlDelay() != 0 {
+		t.Errorf("CrawlDelay() = %v, expected none outside the * group", robots.CrawlDelay())
+	}
+
+	// Product tokens match whole, not by prefix
+	robots, _ = ParseRobots(strings.NewReader(robotsTxt), "news-backend-content/1.0")
+	if robots.Allowed("/private/notes") {
+		t.Error("Allowed() applied the news-backend group to news-backend-content, expected the * group")
+	}
+}
+
+func TestParseRobotsEmpty(t *testing.T) {
+	robots, err := ParseRobots(strings.NewReader("Sitemap: https://example.com/sitemap.xml\n"), "news-backend")
+	if err != nil || !robots.Allowed("/anything") {
+		t.Errorf("ParseRobots() without groups = %v, %v, expected everything allowed", robots, err)
+	}
+}
@@ -0,0 +1,66 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"news-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ArchiveTable holds articles moved out of the articles table by the
+// ArchiveWorker, with the same columns plus archived_at
+const ArchiveTable = "articles_archive"
+
+// RetiredArticleIDs returns which of ids were deleted or archived. Ingest
+// and dataset reloads leave them alone, so a source still listing an old
+// article doesn't bring it back.
+func RetiredArticleIDs(db *gorm.DB, ids []string) (map[string]bool, error) {
+	retired := make(map[string]bool)
+	if len(ids) == 0 {
+		return retired, nil
+	}
+	var deleted, archived []string
+	err := db.Unscoped().Model(&models.Article{}).
+		Where("id IN ? AND deleted_at IS NOT NULL", ids).
+		Pluck("id", &deleted).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load deleted articles: %w", err)
+	}
+	if err := db.Table(ArchiveTable).Where("id IN ?", ids).Pluck("id", &archived).Error; err != nil {
+		return nil, fmt.Errorf("failed to load archived articles: %w", err)
+	}
+	for _, id := range append(deleted, archived...) {
+		retired[id] = true
+	}
+	return retired, nil
+}
+
+var (
+	articleColumnsOnce sync.Once
+	articleColumns     string
+)
+
+// ArticleColumns lists the stored columns of models.Article, comma-joined,
+// which the articles and archive tables share
+func ArticleColumns() string {
+	articleColumnsOnce.Do(func() {
+		s, err := schema.Parse(&models.Article{}, &sync.Map{}, schema.NamingStrategy{})
+		if err != nil {
+			panic(fmt.Sprintf("failed to parse article model: %v", err))
+		}
+		articleColumns = strings.Join(s.DBNames, ", ")
+	})
+	return articleColumns
+}
+
+// WithArchive returns an article query over both the articles and the
+// archive table, aliased as articles so the usual conditions apply
+func WithArchive(db *gorm.DB) *gorm.DB {
+	columns := ArticleColumns()
+	union := db.Raw("SELECT " + columns + " FROM articles UNION ALL SELECT " + columns + " FROM " + ArchiveTable)
+	return db.Model(&models.Article{}).Table("(?) AS articles", union)
+}
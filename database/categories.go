@@ -0,0 +1,73 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"news-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// SyncArticleCategories links each article to Category rows matching its
+// comma-joined Category field, creating categories as needed. Names are
+// matched case-insensitively; the first spelling seen is kept.
+func SyncArticleCategories(db *gorm.DB, articles []models.Article) error {
+	known := make(map[string]models.Category)
+
+	for i := range articles {
+		names := models.SplitCategories(articles[i].Category)
+		categories := make([]models.Category, 0, len(names))
+
+		for _, name := range names {
+			key := strings.ToLower(name)
+			category, ok := known[key]
+			if !ok {
+				err := db.Where("LOWER(name) = ?", key).
+					Attrs(models.Category{Name: name}).
+					FirstOrCreate(&category).Error
+				if err != nil {
+					return fmt.Errorf("failed to create category %q: %w", name, err)
+				}
+				known[key] = category
+			}
+			categories = append(categories, category)
+		}
+
+		article := models.Article{ID: articles[i].ID}
+		if err := db.Model(&article).Association("Categories").Replace(categories); err != nil {
+			return fmt.Errorf("failed to link categories for article %s: %w", articles[i].ID, err)
+		}
+	}
+	return nil
+}
+
+// migrateCategories backfills the category join table from the comma-joined
+// Category column for databases created before categories were normalized
+func migrateCategories() error {
+	var linked int64
+	if err := DB.Table("article_categories").Count(&linked).Error; err != nil {
+		return fmt.Errorf("failed to count category links: %w", err)
+	}
+	if linked > 0 {
+		return nil
+	}
+
+	var articles []models.Article
+	if err := DB.Select("id", "category").Where("category <> ''").Find(&articles).Error; err != nil {
+		return fmt.Errorf("failed to load article categories: %w", err)
+	}
+	if len(articles) == 0 {
+		return nil
+	}
+
+	if err := DB.Transaction(func(tx *gorm.DB) error {
+		return SyncArticleCategories(tx, articles)
+	}); err != nil {
+		return err
+	}
+
+	log.Printf("Migrated categories for %d articles", len(articles))
+	return nil
+}
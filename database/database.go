@@ -5,20 +5,55 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	"news-backend/config"
+	"news-backend/ingest"
+	"news-backend/migrations"
 	"news-backend/models"
 
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 )
 
 var DB *gorm.DB
 
-// InitDB initializes the database connection
-func InitDB(cfg *config.Config) error {
+// schemaModels are the models the migrations create tables for
+var schemaModels = []interface{}{
+	&models.Article{},
+	&models.UserEvent{},
+	&models.ArticleEmbedding{},
+	&models.Source{},
+	&models.UserLink{},
+	&models.UserLinkToken{},
+	&models.DeviceSecret{},
+	&models.Category{},
+	&models.Feedback{},
+	&models.LLMAudit{},
+	&models.UserPreference{},
+	&models.KeywordAlert{},
+	&models.AlertMatch{},
+	&models.FeedSignal{},
+	&models.TrendingScore{},
+	&models.Edition{},
+	&models.EditionOverride{},
+	&models.EventRollup{},
+	&models.Topic{},
+	&models.TopicFollow{},
+	&models.QuerySession{},
+	&models.SavedPlace{},
+	&models.SummaryExperimentAssignment{},
+	&models.SummaryExperimentArm{},
+	&models.UserMute{},
+	&models.UserInterest{},
+	&models.ArticleContent{},
+}
+
+// Connect opens the database without migrating it
+func Connect(cfg *config.Config) error {
 	var err error
 	
 	// Configure GORM logger
@@ -30,14 +65,52 @@ func InitDB(cfg *config.Config) error {
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
+	return nil
+}
+
+// InitDB initializes the database connection, applies pending schema
+// migrations (unless MIGRATE_ON_START is off) and refuses a schema that
+// still differs from the models
+func InitDB(cfg *config.Config) error {
+	if err := Connect(cfg); err != nil {
+		return err
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database handle: %w", err)
+	}
 	
-	// Auto migrate schemas
-	err = DB.AutoMigrate(
-		&models.Article{},
-		&models.UserEvent{},
-	)
+	if cfg.MigrateOnStart {
+		applied, err := migrations.Up(sqlDB)
+		for _, m := range applied {
+			log.Printf("Applied schema migration %s", m)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to migrate database: %w", err)
+		}
+	} else {
+		pending, err := migrations.Pending(sqlDB)
+		if err != nil {
+			return fmt.Errorf("failed to check schema migrations: %w", err)
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("%d schema migrations pending and MIGRATE_ON_START is off; apply them with -migrate up", len(pending))
+		}
+	}
+	
+	// A schema the migrations didn't build, e.g. by a build that still ran
+	// AutoMigrate, can't be trusted to match the models
+	drift, err := schemaDrift(DB.Session(&gorm.Session{Logger: logger.Discard}).Migrator())
 	if err != nil {
-		return fmt.Errorf("failed to migrate database: %w", err)
+		return fmt.Errorf("failed to check schema: %w", err)
+	}
+	if len(drift) > 0 {
+		return fmt.Errorf("database schema differs from the models (%s); run -check", strings.Join(drift, ", "))
+	}
+	
+	// Normalize comma-joined categories into the join table
+	if err := migrateCategories(); err != nil {
+		return fmt.Errorf("failed to migrate categories: %w", err)
 	}
 	
 	log.Println("Database initialized successfully")
@@ -46,33 +119,25 @@ func InitDB(cfg *config.Config) error {
 
 // LoadNewsData loads news articles from JSON file into database
 func LoadNewsData(filePath string) error {
-	// Check if data already exists
-	var count int64
-	DB.Model(&models.Article{}).Count(&count)
+	// Check if data already exists, including deleted and archived articles
+	var count, archived int64
+	DB.Unscoped().Model(&models.Article{}).Count(&count)
+	DB.Table(ArchiveTable).Count(&archived)
+	count += archived
 	if count > 0 {
 		log.Printf("Database already contains %d articles, skipping data load", count)
 		return nil
 	}
 	
-	log.Println("Loading news data from file:", filePath)
-	
-	// Read JSON file
-	raw, err := os.ReadFile(filePath)
+	articles, _, err := readNewsData(filePath)
 	if err != nil {
-		return fmt.Errorf("failed to read data file: %w", err)
+		return err
 	}
 	
-	// Parse JSON directly into Article slice (uses custom UnmarshalJSON)
-	var articles []models.Article
-	if err := json.Unmarshal(raw, &articles); err != nil {
-		return fmt.Errorf("failed to parse JSON: %w", err)
-	}
-	
-	log.Printf("Parsed %d articles from file", len(articles))
-	
-	// Insert articles in batches
+	// Insert articles in batches; repeats of a stored article are merged
 	batchSize := 100
 	successCount := 0
+	mergedCount := 0
 	errorCount := 0
 	
 	for i := 0; i < len(articles); i += batchSize {
@@ -82,15 +147,278 @@ func LoadNewsData(filePath string) error {
 		}
 		
 		batch := articles[i:end]
-		if err := DB.Create(&batch).Error; err != nil {
+		var fresh []models.Article
+		err := DB.Transaction(func(tx *gorm.DB) error {
+			var err error
+			if fresh, _, err = MergeDuplicates(tx, batch); err != nil || len(fresh) == 0 {
+				return err
+			}
+			if err := tx.Create(&fresh).Error; err != nil {
+				return err
+			}
+			return SyncArticleCategories(tx, fresh)
+		})
+		if err != nil {
 			log.Printf("Failed to insert batch: %v", err)
 			errorCount += len(batch)
 		} else {
-			successCount += len(batch)
+			successCount += len(fresh)
+			mergedCount += len(batch) - len(fresh)
+		}
+	}
+	
+	log.Printf("Data load complete: %d successful, %d merged into duplicates, %d errors", successCount, mergedCount, errorCount)
+	return nil
+}
+
+// ReloadResult summarizes an upsert of the dataset into an existing database
+type ReloadResult struct {
+	Total     int              `json:"total"`
+	Inserted  int              `json:"inserted"`
+	Updated   int              `json:"updated"` // Content hash changed
+	Unchanged int              `json:"unchanged"`
+	Merged    int              `json:"merged"`  // Same URL as another article, see MergeDuplicates
+	Skipped   int              `json:"skipped"` // Failed to transform
+	Changed   []models.Article `json:"-"`       // Updated articles, as loaded
+	MergedIDs []string         `json:"-"`       // Articles that absorbed a duplicate
+}
+
+// articleReloadColumns are the dataset columns a reload overwrites. Derived
+// and editorial columns (current_relevance, story_id, trending exclusion)
+// are kept; llm_summary, sentiment and tone are cleared because the content
+// they describe changed.
+var articleReloadColumns = []string{
+	"title", "description", "url", "publication_date", "source_name", "category",
+	"relevance_score", "latitude", "longitude", "content_hash", "llm_summary", "summary_attempts",
+	"sentiment", "tone", "publisher", "license", "attribution", "ingest_source",
+	"image_url",
+}
+
+// ReloadNewsData upserts articles from a JSON file: new IDs are inserted
+// and stored articles are updated only when their content hash changed, so
+// reloading an unchanged dataset is a no-op. Embeddings of updated articles
+// are dropped; callers invalidate any cached copies of Changed and
+// MergedIDs. Deleted and archived articles are left alone, and articles
+// with the URL of another are merged into it.
+func ReloadNewsData(filePath string) (*ReloadResult, error) {
+	articles, skipped, err := readNewsData(filePath)
+	if err != nil {
+		return nil, err
+	}
+	
+	result := &ReloadResult{Total: len(articles) + skipped, Skipped: skipped}
+	batchSize := 100
+	for i := 0; i < len(articles); i += batchSize {
+		end := min(i+batchSize, len(articles))
+		batch := articles[i:end]
+		
+		ids := make([]string, len(batch))
+		for j := range batch {
+			ids[j] = batch[j].ID
+		}
+		var stored []models.Article
+		if err := DB.Select("id", "title", "description", "content_hash").Where("id IN ?", ids).Find(&stored).Error; err != nil {
+			return result, fmt.Errorf("failed to load existing articles: %w", err)
+		}
+		retired, err := RetiredArticleIDs(DB, ids)
+		if err != nil {
+			return result, err
+		}
+		hashes := make(map[string]string, len(stored))
+		for _, article := range stored {
+			// Rows stored before hashing was introduced
+			if article.ContentHash == "" {
+				article.ContentHash = article.ComputeContentHash()
+			}
+			hashes[article.ID] = article.ContentHash
+		}
+		
+		var upserts []models.Article
+		for _, article := range batch {
+			hash, found := hashes[article.ID]
+			switch {
+			case retired[article.ID]:
+				result.Unchanged++
+			case !found:
+				article.CurrentRelevance = article.RelevanceScore
+				upserts = append(upserts, article)
+			case hash != article.ContentHash:
+				upserts = append(upserts, article)
+			default:
+				result.Unchanged++
+			}
+		}
+		if len(upserts) == 0 {
+			continue
+		}
+		
+		var fresh, changed []models.Article
+		var merged []string
+		err = DB.Transaction(func(tx *gorm.DB) error {
+			var err error
+			if fresh, merged, err = MergeDuplicates(tx, upserts); err != nil || len(fresh) == 0 {
+				return err
+			}
+			changed = changed[:0]
+			var changedIDs []string
+			for _, article := range fresh {
+				if _, found := hashes[article.ID]; found {
+					changed = append(changed, article)
+					changedIDs = append(changedIDs, article.ID)
+				}
+			}
+			// Rows whose hash already matches are left alone, even if
+			// another writer changed them since they were read above
+			err = tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "id"}},
+				DoUpdates: clause.AssignmentColumns(articleReloadColumns),
+				Where: clause.Where{Exprs: []clause.Expression{
+					clause.Expr{SQL: "articles.content_hash IS NOT excluded.content_hash"},
+				}},
+			}).Create(&fresh).Error
+			if err != nil {
+				return err
+			}
+			if len(changedIDs) > 0 {
+				if err := tx.Where("article_id IN ?", changedIDs).Delete(&models.ArticleEmbedding{}).Error; err != nil {
+					return err
+				}
+			}
+			return SyncArticleCategories(tx, fresh)
+		})
+		if err != nil {
+			return result, fmt.Errorf("failed to upsert articles: %w", err)
 		}
+		result.Inserted += len(fresh) - len(changed)
+		result.Updated += len(changed)
+		result.Merged += len(upserts) - len(fresh)
+		result.Changed = append(result.Changed, changed...)
+		result.MergedIDs = append(result.MergedIDs, merged...)
 	}
 	
-	log.Printf("Data load complete: %d successful, %d errors", successCount, errorCount)
+	log.Printf("Data reload complete: %d inserted, %d updated, %d merged, %d unchanged, %d skipped",
+		result.Inserted, result.Updated, result.Merged, result.Unchanged, result.Skipped)
+	return result, nil
+}
+
+// readNewsData parses and transforms the articles in a JSON dataset with the
+// stored source rules, returning how many records were skipped
+func readNewsData(filePath string) ([]models.Article, int, error) {
+	log.Println("Loading news data from file:", filePath)
+	
+	// Read JSON file
+	raw, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read data file: %w", err)
+	}
+	
+	// Parse JSON into raw records so per-source rules can be applied
+	rawArticles, err := ingest.ParseJSON(raw)
+	if err != nil {
+		return nil, 0, err
+	}
+	
+	log.Printf("Parsed %d articles from file", len(rawArticles))
+	
+	sources, err := LoadSourcesByName()
+	if err != nil {
+		return nil, 0, err
+	}
+	
+	articles := make([]models.Article, 0, len(rawArticles))
+	skipped := 0
+	for _, rawArticle := range rawArticles {
+		source := ingest.SourceFor(rawArticle, sources)
+		article, err := ingest.Transform(rawArticle, source.Rules)
+		if err != nil {
+			log.Printf("Skipping article %v: %v", rawArticle["id"], err)
+			skipped++
+			continue
+		}
+		article.ApplyProvenance(source, models.IngestSourceDataset)
+		articles = append(articles, article)
+	}
+	return articles, skipped, nil
+}
+
+// LoadSources upserts source definitions (including ingest rules) from a JSON file
+func LoadSources(filePath string) error {
+	raw, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read sources file: %w", err)
+	}
+	
+	var sources []models.Source
+	if err := json.Unmarshal(raw, &sources); err != nil {
+		return fmt.Errorf("failed to parse sources file: %w", err)
+	}
+	
+	for _, source := range sources {
+		var existing models.Source
+		if err := DB.Where("name = ?", source.Name).First(&existing).Error; err == nil {
+			source.ID = existing.ID
+			source.CreatedAt = existing.CreatedAt
+			// Keep a rating set through the admin API unless the file has one
+			if source.ReliabilityScore == nil {
+				source.ReliabilityScore = existing.ReliabilityScore
+			}
+		}
+		if err := DB.Save(&source).Error; err != nil {
+			return fmt.Errorf("failed to save source %s: %w", source.Name, err)
+		}
+	}
+	
+	log.Printf("Loaded %d source definitions", len(sources))
+	return nil
+}
+
+// LoadSourcesByName returns source definitions (ingest rules and licensing
+// defaults) keyed by source name
+func LoadSourcesByName() (map[string]models.Source, error) {
+	var sources []models.Source
+	if err := DB.Find(&sources).Error; err != nil {
+		return nil, fmt.Errorf("failed to load sources: %w", err)
+	}
+	
+	byName := make(map[string]models.Source, len(sources))
+	for _, source := range sources {
+		byName[source.Name] = source
+	}
+	return byName, nil
+}
+
+// BackfillProvenance fills the provenance of articles stored before it was
+// recorded from their source's licensing defaults
+func BackfillProvenance() error {
+	var names []string
+	if err := DB.Model(&models.Article{}).Where("license = '' OR license IS NULL").
+		Distinct().Pluck("source_name", &names).Error; err != nil {
+		return fmt.Errorf("failed to find articles without provenance: %w", err)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	
+	sources, err := LoadSourcesByName()
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		article := models.Article{SourceName: name}
+		article.ApplyProvenance(sources[name], models.IngestSourceUnknown)
+		err := DB.Model(&models.Article{}).
+			Where("source_name = ? AND (license = '' OR license IS NULL)", name).
+			Updates(map[string]interface{}{
+				"publisher":     article.Publisher,
+				"license":       article.License,
+				"attribution":   article.Attribution,
+				"ingest_source": article.IngestSource,
+			}).Error
+		if err != nil {
+			return fmt.Errorf("failed to backfill provenance for %s: %w", name, err)
+		}
+	}
+	log.Printf("Backfilled provenance for articles from %d sources", len(names))
 	return nil
 }
 
@@ -144,6 +472,7 @@ func SeedUserEvents() error {
 			event := models.UserEvent{
 				ArticleID: article.ID,
 				UserID:    fmt.Sprintf("user_%d", j%20), // Simulate 20 users
+				DeviceID:  fmt.Sprintf("user_%d", j%20),
 				EventType: eventType,
 				Latitude:  article.Latitude + (float64(j%5) - 2) * 0.1, // Vary location slightly
 				Longitude: article.Longitude + (float64(j%5) - 2) * 0.1,
@@ -170,6 +499,18 @@ func SeedUserEvents() error {
 	return nil
 }
 
+// Close closes the underlying database connection
+func Close() error {
+	if DB == nil {
+		return nil
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database handle: %w", err)
+	}
+	return sqlDB.Close()
+}
+
 // GetDB returns the database instance
 func GetDB() *gorm.DB {
 	return DB
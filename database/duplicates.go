@@ -0,0 +1,117 @@
+package database
+
+import (
+	"fmt"
+
+	"news-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// MergeDuplicates drops the articles that repeat, under another ID, one
+// already stored or one earlier in articles with the same URL, whether or
+// not the headline was edited, and merges each into the article it repeats: the earlier publication date
+// is kept and the repeat's relevance score taken. Stored articles are
+// updated through tx and their IDs returned, for callers to invalidate
+// cached copies. Deleted and archived articles absorb repeats too, rather
+// than come back under a new ID, though only deleted ones are updated.
+func MergeDuplicates(tx *gorm.DB, articles []models.Article) ([]models.Article, []string, error) {
+	if len(articles) == 0 {
+		return articles, nil, nil
+	}
+	urls := make([]string, len(articles))
+	for i := range articles {
+		urls[i] = articles[i].URL
+	}
+	var stored []models.Article
+	err := tx.Unscoped().Select("id", "url", "publication_date", "relevance_score").
+		Where("url IN ?", urls).
+		Order("deleted_at IS NOT NULL"). // live articles first
+		Find(&stored).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load articles with the same URL: %w", err)
+	}
+	storedByURL := make(map[string]*models.Article, len(stored))
+	for i := range stored {
+		if storedByURL[stored[i].URL] == nil {
+			storedByURL[stored[i].URL] = &stored[i]
+		}
+	}
+
+	var archived []models.Article
+	err = tx.Table(ArchiveTable).Select("id", "url").Where("url IN ?", urls).Find(&archived).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load archived articles with the same URL: %w", err)
+	}
+	archivedByURL := make(map[string]string, len(archived))
+	for i := range archived {
+		archivedByURL[archived[i].URL] = archived[i].ID
+	}
+
+	before := make(map[string]models.Article, len(stored))
+	for _, original := range stored {
+		before[original.ID] = original
+	}
+
+	fresh := make([]models.Article, 0, len(articles))
+	freshByURL := make(map[string]int)
+	for _, article := range articles {
+		if original := storedByURL[article.URL]; original != nil && original.ID != article.ID {
+			mergeDuplicate(original, &article)
+			continue
+		}
+		if id, ok := archivedByURL[article.URL]; ok && id != article.ID {
+			continue
+		}
+		if i, ok := freshByURL[article.URL]; ok {
+			mergeDuplicate(&fresh[i], &article)
+			continue
+		}
+		freshByURL[article.URL] = len(fresh)
+		fresh = append(fresh, article)
+	}
+
+	// Only stored articles whose merged values differ are written, so
+	// merging the same repeats again is a no-op
+	var merged []string
+	for _, original := range stored {
+		if previous := before[original.ID]; original.PublicationDate.Equal(previous.PublicationDate) &&
+			original.RelevanceScore == previous.RelevanceScore {
+			continue
+		}
+		err := tx.Unscoped().Model(&models.Article{}).Where("id = ?", original.ID).Updates(map[string]interface{}{
+			"publication_date": original.PublicationDate,
+			"relevance_score":  original.RelevanceScore,
+		}).Error
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to merge duplicate of article %s: %w", original.ID, err)
+		}
+		merged = append(merged, original.ID)
+	}
+	return fresh, merged, nil
+}
+
+// mergeDuplicate merges repeat into original
+func mergeDuplicate(original, repeat *models.Article) {
+	if repeat.PublicationDate.Before(original.PublicationDate) {
+		original.PublicationDate = repeat.PublicationDate
+	}
+	original.RelevanceScore = repeat.RelevanceScore
+}
+
+// DuplicateArticleID returns the ID of the live article other than id with
+// the given URL, which the unique index would reject a second one of, or ""
+// when there is none
+func DuplicateArticleID(db *gorm.DB, url, id string) (string, error) {
+	var ids []string
+	err := db.Model(&models.Article{}).
+		Where("url = ? AND id <> ?", url, id).
+		Limit(1).Pluck("id", &ids).Error
+	if err != nil {
+		return "", fmt.Errorf("failed to check for duplicate articles: %w", err)
+	}
+	if len(ids) == 0 {
+		return "", nil
+	}
+	return ids[0], nil
+}
@@ -0,0 +1,79 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"news-backend/migrations"
+	"news-backend/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get database handle: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	if _, err := migrations.Up(sqlDB); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	return db
+}
+
+func TestMergeDuplicatesEditedHeadline(t *testing.T) {
+	db := openTestDB(t)
+	published := time.Date(2025, 3, 22, 10, 0, 0, 0, time.UTC)
+	stored := models.Article{ID: "original", Title: "Minister resigns", URL: "https://example.com/story",
+		PublicationDate: published, RelevanceScore: 0.4}
+	if err := db.Create(&stored).Error; err != nil {
+		t.Fatalf("failed to store article: %v", err)
+	}
+
+	// The same story under a new ID with an edited headline, twice in one
+	// batch, next to a different story
+	articles := []models.Article{
+		{ID: "edited", Title: "Minister resigns amid inquiry", URL: "https://example.com/story",
+			PublicationDate: published.Add(time.Hour), RelevanceScore: 0.8},
+		{ID: "edited-again", Title: "Minister resigns, inquiry opens", URL: "https://example.com/story",
+			PublicationDate: published.Add(-time.Hour), RelevanceScore: 0.9},
+		{ID: "other", Title: "Minister resigns", URL: "https://example.com/other",
+			PublicationDate: published, RelevanceScore: 0.5},
+	}
+	fresh, merged, err := MergeDuplicates(db, articles)
+	if err != nil {
+		t.Fatalf("MergeDuplicates() error = %v", err)
+	}
+	if len(fresh) != 1 || fresh[0].ID != "other" {
+		t.Errorf("MergeDuplicates() kept %v, expected only the other story", fresh)
+	}
+	if len(merged) != 1 || merged[0] != "original" {
+		t.Errorf("MergeDuplicates() merged into %v, expected the stored article", merged)
+	}
+
+	var got models.Article
+	if err := db.First(&got, "id = ?", "original").Error; err != nil {
+		t.Fatal(err)
+	}
+	if !got.PublicationDate.Equal(published.Add(-time.Hour)) || got.RelevanceScore != 0.9 || got.Title != "Minister resigns" {
+		t.Errorf("stored article = %q published %v scored %v, expected the earliest date and latest score under its own title",
+			got.Title, got.PublicationDate, got.RelevanceScore)
+	}
+
+	if id, err := DuplicateArticleID(db, "https://example.com/story", "new"); err != nil || id != "original" {
+		t.Errorf("DuplicateArticleID() = %q, %v, expected the stored article", id, err)
+	}
+	edited := models.Article{ID: "new", Title: "Minister resigns amid inquiry", URL: "https://example.com/story"}
+	if err := db.Create(&edited).Error; err == nil {
+		t.Error("storing an edited headline under the same URL succeeded, expected the unique index to reject it")
+	}
+}
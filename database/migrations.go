@@ -0,0 +1,87 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"news-backend/config"
+	"news-backend/migrations"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+// SchemaStatus is how a database's schema compares with the migrations
+// and models
+type SchemaStatus struct {
+	Version uint64 // applied migration version, 0 for none
+	Pending []migrations.Migration
+	// Drift lists the tables, columns and indexes the models have but the
+	// fully migrated schema lacks, i.e. a missing migration
+	Drift []string
+}
+
+// CheckSchema opens the database at cfg.DatabasePath without changing it
+// and reports its schema version, the migrations the next start would
+// apply and, when none are pending, any drift from the models. A database
+// that doesn't exist yet isn't created; every migration is pending.
+func CheckSchema(cfg *config.Config) (SchemaStatus, error) {
+	var status SchemaStatus
+	if _, err := os.Stat(cfg.DatabasePath); errors.Is(err, os.ErrNotExist) {
+		status.Pending, err = migrations.All()
+		return status, err
+	}
+	db, err := gorm.Open(sqlite.Open(cfg.DatabasePath), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		return status, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return status, fmt.Errorf("failed to get database handle: %w", err)
+	}
+	defer sqlDB.Close()
+	// Reading the version also fails when the file isn't a database
+	if status.Version, _, err = migrations.Version(sqlDB); err != nil {
+		return status, err
+	}
+	if status.Pending, err = migrations.Pending(sqlDB); err != nil || len(status.Pending) > 0 {
+		return status, err
+	}
+	status.Drift, err = schemaDrift(db.Migrator())
+	return status, err
+}
+
+// schemaDrift lists what schemaModels have but the schema migrator reads
+// lacks
+func schemaDrift(migrator gorm.Migrator) ([]string, error) {
+	var missing []string
+	cache := &sync.Map{}
+	for _, model := range schemaModels {
+		s, err := schema.Parse(model, cache, schema.NamingStrategy{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse model: %w", err)
+		}
+		if !migrator.HasTable(model) {
+			missing = append(missing, "missing table "+s.Table)
+			continue
+		}
+		for _, field := range s.Fields {
+			if field.DBName == "" || field.IgnoreMigration {
+				continue
+			}
+			if !migrator.HasColumn(model, field.DBName) {
+				missing = append(missing, fmt.Sprintf("missing column %s.%s", s.Table, field.DBName))
+			}
+		}
+		for _, index := range s.ParseIndexes() {
+			if !migrator.HasIndex(model, index.Name) {
+				missing = append(missing, fmt.Sprintf("missing index %s on %s", index.Name, s.Table))
+			}
+		}
+	}
+	return missing, nil
+}
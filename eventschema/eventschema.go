@@ -0,0 +1,182 @@
+// Package eventschema decodes user event payloads. Payloads carry a
+// schema_version; older versions are validated by their own rules and
+// up-converted to the current one, so app versions that predate a field
+// keep working. Payloads from newer clients are read by the fields this
+// version knows, their other fields ignored.
+package eventschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"news-backend/models"
+	"news-backend/utils"
+)
+
+// CurrentVersion is the newest event schema this server understands
+const CurrentVersion = 2
+
+// Platforms a v2 event may come from. Events from before v2 are
+// PlatformUnknown; platforms added by newer clients are PlatformOther.
+const (
+	PlatformIOS     = "ios"
+	PlatformAndroid = "android"
+	PlatformWeb     = "web"
+	PlatformOther   = "other"
+	PlatformUnknown = "unknown"
+)
+
+// maxDwellMs bounds dwell time; longer values are tabs left open
+const maxDwellMs = 6 * 60 * 60 * 1000
+
+// ErrInvalidEvent wraps every validation failure
+var ErrInvalidEvent = errors.New("invalid event")
+
+// Event is a user event in the current schema
+type Event struct {
+	SchemaVersion int // as sent by the client
+	ArticleID     string
+	UserID        string // device or user identifier, as sent
+	EventType     string // lowercased
+	Lat           float64
+	Lon           float64
+	DwellMs       int64 // time spent on the article; 0 when unknown
+	Platform      string
+}
+
+// v1 is the original payload: {"article_id", "user_id", "event_type", "lat", "lon"}
+type v1 struct {
+	ArticleID string   `json:"article_id"`
+	UserID    string   `json:"user_id"`
+	EventType string   `json:"event_type"`
+	Lat       *float64 `json:"lat"`
+	Lon       *float64 `json:"lon"`
+}
+
+// EventPayload is an event as clients of the current version send it: v2 adds
+// the optional dwell time and platform to v1
+type EventPayload struct {
+	SchemaVersion *int `json:"schema_version"` // absent for v1 clients
+	v1
+	DwellMs  *int64 `json:"dwell_ms"`
+	Platform string `json:"platform"`
+}
+
+// Decode validates an event payload of any version and returns it in the
+// current schema. A payload without schema_version is version 1.
+func Decode(body []byte) (Event, error) {
+	var header struct {
+		SchemaVersion *int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(body, &header); err != nil {
+		return Event{}, fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+
+	// v1 payloads are read by the v1 fields only, so whatever else they
+	// carry can't fail them
+	var payload EventPayload
+	var target interface{} = &payload
+	if header.SchemaVersion == nil || *header.SchemaVersion == 1 {
+		target = &payload.v1
+	}
+	if err := json.Unmarshal(body, target); err != nil {
+		return Event{}, fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+	payload.SchemaVersion = header.SchemaVersion
+	return Validate(payload)
+}
+
+// Validate is Decode for a payload already decoded from another encoding,
+// such as the gRPC API's
+func Validate(payload EventPayload) (Event, error) {
+	version, err := schemaVersion(payload.SchemaVersion)
+	if err != nil {
+		return Event{}, err
+	}
+	var event Event
+	if version == 1 {
+		event, err = convertV1(payload.v1)
+	} else {
+		event, err = convertV2(payload)
+	}
+	if err != nil {
+		return Event{}, err
+	}
+	event.SchemaVersion = version
+	return event, nil
+}
+
+// schemaVersion reads a payload's schema_version, 1 when absent
+func schemaVersion(sent *int) (int, error) {
+	if sent == nil {
+		return 1, nil
+	}
+	if *sent < 1 {
+		return 0, fmt.Errorf("%w: schema_version %d (expected 1 to %d)", ErrInvalidEvent, *sent, CurrentVersion)
+	}
+	return *sent, nil
+}
+
+// convertV1 up-converts a v1 payload: v1 clients report neither dwell time
+// nor platform
+func convertV1(payload v1) (Event, error) {
+	event, err := payload.event()
+	if err != nil {
+		return Event{}, err
+	}
+	event.Platform = PlatformUnknown
+	return event, nil
+}
+
+// convertV2 converts a v2 (or newer) payload
+func convertV2(payload EventPayload) (Event, error) {
+	event, err := payload.v1.event()
+	if err != nil {
+		return Event{}, err
+	}
+
+	if payload.DwellMs != nil {
+		if *payload.DwellMs < 0 || *payload.DwellMs > maxDwellMs {
+			return Event{}, fmt.Errorf("%w: dwell_ms must be between 0 and %d", ErrInvalidEvent, maxDwellMs)
+		}
+		event.DwellMs = *payload.DwellMs
+	}
+
+	switch platform := strings.ToLower(strings.TrimSpace(payload.Platform)); platform {
+	case PlatformIOS, PlatformAndroid, PlatformWeb:
+		event.Platform = platform
+	case "":
+		event.Platform = PlatformUnknown
+	default:
+		event.Platform = PlatformOther
+	}
+	return event, nil
+}
+
+// event validates the fields every version has
+func (p v1) event() (Event, error) {
+	switch {
+	case p.ArticleID == "":
+		return Event{}, fmt.Errorf("%w: article_id is required", ErrInvalidEvent)
+	case p.UserID == "":
+		return Event{}, fmt.Errorf("%w: user_id is required", ErrInvalidEvent)
+	case p.Lat == nil || p.Lon == nil:
+		return Event{}, fmt.Errorf("%w: lat and lon are required", ErrInvalidEvent)
+	}
+	eventType := strings.ToLower(p.EventType)
+	if !models.IsValidEventType(eventType) {
+		return Event{}, fmt.Errorf("%w: event_type %q (expected view, click or share)", ErrInvalidEvent, p.EventType)
+	}
+	if err := utils.ValidateLocation(*p.Lat, *p.Lon); err != nil {
+		return Event{}, fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+	return Event{
+		ArticleID: p.ArticleID,
+		UserID:    p.UserID,
+		EventType: eventType,
+		Lat:       *p.Lat,
+		Lon:       *p.Lon,
+	}, nil
+}
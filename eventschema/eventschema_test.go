@@ -0,0 +1,97 @@
+package eventschema
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecodeUpConvertsV1(t *testing.T) {
+	event, err := Decode([]byte(`{"article_id": "a1", "user_id": "u1", "event_type": "VIEW", "lat": 0, "lon": 0}`))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	expected := Event{SchemaVersion: 1, ArticleID: "a1", UserID: "u1", EventType: "view", Platform: PlatformUnknown}
+	if event != expected {
+		t.Errorf("Decode() = %+v, expected %+v", event, expected)
+	}
+}
+
+func TestDecodeV2(t *testing.T) {
+	event, err := Decode([]byte(`{"schema_version": 2, "article_id": "a1", "user_id": "u1", "event_type": "click",
+		"lat": 37.7, "lon": -122.4, "dwell_ms": 4500, "platform": "iOS"}`))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	expected := Event{SchemaVersion: 2, ArticleID: "a1", UserID: "u1", EventType: "click",
+		Lat: 37.7, Lon: -122.4, DwellMs: 4500, Platform: PlatformIOS}
+	if event != expected {
+		t.Errorf("Decode() = %+v, expected %+v", event, expected)
+	}
+}
+
+func TestDecodeNewerVersion(t *testing.T) {
+	event, err := Decode([]byte(`{"schema_version": 5, "article_id": "a1", "user_id": "u1", "event_type": "share",
+		"lat": 1, "lon": 2, "platform": "visionos", "scroll_depth": 0.8}`))
+	if err != nil {
+		t.Fatalf("Decode() error = %v, expected newer payloads to be read by the known fields", err)
+	}
+	if event.SchemaVersion != 5 || event.Platform != PlatformOther || event.EventType != "share" {
+		t.Errorf("Decode() = %+v, expected version 5, platform %q, type share", event, PlatformOther)
+	}
+}
+
+func TestDecodeRejectsInvalid(t *testing.T) {
+	tests := map[string]string{
+		"not json":           `{"article_id": `,
+		"version zero":       `{"schema_version": 0, "article_id": "a1", "user_id": "u1", "event_type": "view", "lat": 1, "lon": 1}`,
+		"version not int":    `{"schema_version": "2", "article_id": "a1", "user_id": "u1", "event_type": "view", "lat": 1, "lon": 1}`,
+		"missing article":    `{"user_id": "u1", "event_type": "view", "lat": 1, "lon": 1}`,
+		"missing user":       `{"article_id": "a1", "event_type": "view", "lat": 1, "lon": 1}`,
+		"missing location":   `{"article_id": "a1", "user_id": "u1", "event_type": "view", "lat": 1}`,
+		"unknown event type": `{"article_id": "a1", "user_id": "u1", "event_type": "like", "lat": 1, "lon": 1}`,
+		"bad latitude":       `{"article_id": "a1", "user_id": "u1", "event_type": "view", "lat": 91, "lon": 1}`,
+		"negative dwell":     `{"schema_version": 2, "article_id": "a1", "user_id": "u1", "event_type": "view", "lat": 1, "lon": 1, "dwell_ms": -1}`,
+		"dwell too long":     `{"schema_version": 2, "article_id": "a1", "user_id": "u1", "event_type": "view", "lat": 1, "lon": 1, "dwell_ms": 86400000}`,
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Decode([]byte(body)); !errors.Is(err, ErrInvalidEvent) {
+				t.Errorf("Decode() error = %v, expected ErrInvalidEvent", err)
+			}
+		})
+	}
+}
+
+func TestDecodeV1IgnoresV2Fields(t *testing.T) {
+	// a v1 payload never had these fields, so they can't be trusted
+	event, err := Decode([]byte(`{"schema_version": 1, "article_id": "a1", "user_id": "u1", "event_type": "view",
+		"lat": 1, "lon": 1, "dwell_ms": -5, "platform": "web"}`))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if event.DwellMs != 0 || event.Platform != PlatformUnknown {
+		t.Errorf("Decode() = %+v, expected v1 defaults for dwell and platform", event)
+	}
+}
+
+func TestValidatePayload(t *testing.T) {
+	version, lat, lon := 2, 37.7, -122.4
+	payload := EventPayload{SchemaVersion: &version, Platform: "android"}
+	payload.ArticleID, payload.UserID, payload.EventType = "a1", "u1", "Share"
+	payload.Lat, payload.Lon = &lat, &lon
+
+	event, err := Validate(payload)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	expected := Event{SchemaVersion: 2, ArticleID: "a1", UserID: "u1", EventType: "share",
+		Lat: 37.7, Lon: -122.4, Platform: PlatformAndroid}
+	if event != expected {
+		t.Errorf("Validate() = %+v, expected %+v", event, expected)
+	}
+
+	payload.Lon = nil
+	if _, err := Validate(payload); !errors.Is(err, ErrInvalidEvent) {
+		t.Errorf("Validate() error = %v, expected ErrInvalidEvent without a longitude", err)
+	}
+}
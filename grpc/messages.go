@@ -0,0 +1,302 @@
+package grpc
+
+import (
+	"fmt"
+	"math"
+
+	"news-backend/models"
+	newsv1 "news-backend/proto"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The messages of proto/news_service.proto. Requests decode themselves and
+// responses encode themselves with protowire, like the REST API's protobuf
+// responses (see package newsv1).
+
+type queryRequest struct {
+	Query          string
+	SessionID      string
+	Lat, Lon       float64
+	Radius         float64
+	Limit, Page    int
+	RankingProfile string
+	UserID         string
+}
+
+func (m *queryRequest) unmarshal(b []byte) error {
+	return decodeFields(b, func(f field) error {
+		var err error
+		switch f.num {
+		case 1:
+			m.Query, err = f.string()
+		case 2:
+			m.SessionID, err = f.string()
+		case 3:
+			m.Lat, err = f.double()
+		case 4:
+			m.Lon, err = f.double()
+		case 5:
+			m.Radius, err = f.double()
+		case 6:
+			m.Limit, err = f.int()
+		case 7:
+			m.Page, err = f.int()
+		case 8:
+			m.RankingProfile, err = f.string()
+		case 9:
+			m.UserID, err = f.string()
+		}
+		return err
+	})
+}
+
+type queryResponse struct {
+	Intent             string
+	Entities           []byte // encoded google.protobuf.Struct
+	Articles           []models.ArticleResponse
+	Metadata           *models.ResponseMetadata
+	SessionID          string
+	Turn               int
+	SessionExpiresUnix int64
+	Demo               bool
+}
+
+func (m *queryResponse) marshal() []byte {
+	var b []byte
+	b = newsv1.AppendString(b, 1, m.Intent)
+	b = newsv1.AppendMessage(b, 2, m.Entities)
+	b = newsv1.AppendMessage(b, 3, newsv1.EncodeArticleList(m.Articles, m.Metadata, m.Demo))
+	b = newsv1.AppendString(b, 4, m.SessionID)
+	b = newsv1.AppendInt(b, 5, m.Turn)
+	b = newsv1.AppendInt(b, 6, int(m.SessionExpiresUnix))
+	return b
+}
+
+type searchRequest struct {
+	Query          string
+	Mode           string
+	From, To       string
+	Sentiment      string
+	MinSourceScore float64
+	Limit, Page    int
+	RankingProfile string
+	UserID         string
+}
+
+func (m *searchRequest) unmarshal(b []byte) error {
+	return decodeFields(b, func(f field) error {
+		var err error
+		switch f.num {
+		case 1:
+			m.Query, err = f.string()
+		case 2:
+			m.Mode, err = f.string()
+		case 3:
+			m.From, err = f.string()
+		case 4:
+			m.To, err = f.string()
+		case 5:
+			m.Sentiment, err = f.string()
+		case 6:
+			m.MinSourceScore, err = f.double()
+		case 7:
+			m.Limit, err = f.int()
+		case 8:
+			m.Page, err = f.int()
+		case 9:
+			m.RankingProfile, err = f.string()
+		case 10:
+			m.UserID, err = f.string()
+		}
+		return err
+	})
+}
+
+type trendingRequest struct {
+	Lat, Lon         float64
+	Radius           float64
+	Limit            int
+	IncludeSummaries *bool
+}
+
+func (m *trendingRequest) unmarshal(b []byte) error {
+	return decodeFields(b, func(f field) error {
+		var err error
+		switch f.num {
+		case 1:
+			m.Lat, err = f.double()
+		case 2:
+			m.Lon, err = f.double()
+		case 3:
+			m.Radius, err = f.double()
+		case 4:
+			m.Limit, err = f.int()
+		case 5:
+			var include bool
+			include, err = f.bool()
+			m.IncludeSummaries = &include
+		}
+		return err
+	})
+}
+
+// article is a news.v1.Article, the message Search streams
+type article struct {
+	models.ArticleResponse
+}
+
+func (m *article) marshal() []byte {
+	return newsv1.EncodeArticle(&m.ArticleResponse)
+}
+
+type trendingArticle struct {
+	models.TrendingArticleResponse
+}
+
+func (m *trendingArticle) marshal() []byte {
+	var b []byte
+	b = newsv1.AppendMessage(b, 1, newsv1.EncodeArticle(&m.ArticleResponse))
+	b = newsv1.AppendDouble(b, 2, m.TrendingScore)
+	b = newsv1.AppendInt(b, 3, m.EventCount)
+	b = newsv1.AppendInt(b, 4, m.UniqueUsers)
+	for _, key := range newsv1.SortedKeys(m.EventBreakdown) {
+		var entry []byte
+		entry = newsv1.AppendString(entry, 1, key)
+		entry = newsv1.AppendInt(entry, 2, m.EventBreakdown[key])
+		b = newsv1.AppendMessage(b, 5, entry)
+	}
+	return b
+}
+
+type event struct {
+	SchemaVersion int
+	ArticleID     string
+	UserID        string
+	EventType     string
+	Lat, Lon      *float64
+	DwellMs       *int64
+	Platform      string
+}
+
+func (m *event) unmarshal(b []byte) error {
+	return decodeFields(b, func(f field) error {
+		var err error
+		switch f.num {
+		case 1:
+			m.SchemaVersion, err = f.int()
+		case 2:
+			m.ArticleID, err = f.string()
+		case 3:
+			m.UserID, err = f.string()
+		case 4:
+			m.EventType, err = f.string()
+		case 5:
+			var lat float64
+			lat, err = f.double()
+			m.Lat = &lat
+		case 6:
+			var lon float64
+			lon, err = f.double()
+			m.Lon = &lon
+		case 7:
+			var dwell int64
+			dwell, err = f.int64()
+			m.DwellMs = &dwell
+		case 8:
+			m.Platform, err = f.string()
+		}
+		return err
+	})
+}
+
+type eventAck struct {
+	Sequence int64
+	Recorded bool
+	Error    string
+	Retry    bool
+}
+
+func (m *eventAck) marshal() []byte {
+	var b []byte
+	b = newsv1.AppendInt(b, 1, int(m.Sequence))
+	b = appendBool(b, 2, m.Recorded)
+	b = newsv1.AppendString(b, 3, m.Error)
+	b = appendBool(b, 4, m.Retry)
+	return b
+}
+
+func appendBool(b []byte, num protowire.Number, v bool) []byte {
+	if !v {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, 1)
+}
+
+// field is one field of an encoded message
+type field struct {
+	num   protowire.Number
+	typ   protowire.Type
+	value []byte // the encoded value, without the tag
+}
+
+// decodeFields calls visit with every field of b in order. Unknown fields
+// are the visitor's to skip, as proto3 requires.
+func decodeFields(b []byte, visit func(field) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		if err := visit(field{num: num, typ: typ, value: b[:n]}); err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+func (f field) wrongType(expected string) error {
+	return fmt.Errorf("field %d is not a %s", f.num, expected)
+}
+
+func (f field) string() (string, error) {
+	if f.typ != protowire.BytesType {
+		return "", f.wrongType("string")
+	}
+	v, _ := protowire.ConsumeString(f.value)
+	return v, nil
+}
+
+func (f field) double() (float64, error) {
+	if f.typ != protowire.Fixed64Type {
+		return 0, f.wrongType("double")
+	}
+	v, _ := protowire.ConsumeFixed64(f.value)
+	return math.Float64frombits(v), nil
+}
+
+func (f field) int64() (int64, error) {
+	if f.typ != protowire.VarintType {
+		return 0, f.wrongType("varint")
+	}
+	v, _ := protowire.ConsumeVarint(f.value)
+	return int64(v), nil
+}
+
+// int reads an int32
+func (f field) int() (int, error) {
+	v, err := f.int64()
+	return int(int32(v)), err
+}
+
+func (f field) bool() (bool, error) {
+	v, err := f.int64()
+	return v != 0, err
+}
@@ -0,0 +1,148 @@
+package grpc
+
+import (
+	"math"
+	"testing"
+
+	"news-backend/models"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func appendTestDouble(b []byte, num protowire.Number, v float64) []byte {
+	b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, math.Float64bits(v))
+}
+
+func appendTestVarint(b []byte, num protowire.Number, v uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+func appendTestString(b []byte, num protowire.Number, v string) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
+
+func TestQueryRequestUnmarshal(t *testing.T) {
+	var b []byte
+	b = appendTestString(b, 1, "tech news near me")
+	b = appendTestString(b, 2, "s1")
+	b = appendTestDouble(b, 3, 37.42)
+	b = appendTestDouble(b, 4, -122.08)
+	b = appendTestString(b, 99, "unknown fields are skipped")
+	b = appendTestVarint(b, 6, 5)
+	b = appendTestString(b, 8, "local")
+
+	var in queryRequest
+	if err := in.unmarshal(b); err != nil {
+		t.Fatalf("unmarshal() error = %v", err)
+	}
+	expected := queryRequest{Query: "tech news near me", SessionID: "s1", Lat: 37.42, Lon: -122.08, Limit: 5, RankingProfile: "local"}
+	if in != expected {
+		t.Errorf("unmarshal() = %+v, expected %+v", in, expected)
+	}
+}
+
+func TestTrendingRequestIncludeSummaries(t *testing.T) {
+	var in trendingRequest
+	if err := in.unmarshal(appendTestVarint(nil, 4, 10)); err != nil {
+		t.Fatalf("unmarshal() error = %v", err)
+	}
+	if in.IncludeSummaries != nil {
+		t.Errorf("IncludeSummaries = %v, expected unset when absent", *in.IncludeSummaries)
+	}
+
+	if err := in.unmarshal(appendTestVarint(nil, 5, 0)); err != nil {
+		t.Fatalf("unmarshal() error = %v", err)
+	}
+	if in.IncludeSummaries == nil || *in.IncludeSummaries {
+		t.Errorf("IncludeSummaries = %v, expected explicit false", in.IncludeSummaries)
+	}
+}
+
+func TestEventUnmarshalOptionalFields(t *testing.T) {
+	var b []byte
+	b = appendTestString(b, 2, "a1")
+	b = appendTestString(b, 3, "u1")
+	b = appendTestString(b, 4, "view")
+	b = appendTestDouble(b, 5, 0)
+	b = appendTestVarint(b, 7, 4500)
+
+	var in event
+	if err := in.unmarshal(b); err != nil {
+		t.Fatalf("unmarshal() error = %v", err)
+	}
+	if in.Lat == nil || *in.Lat != 0 {
+		t.Errorf("Lat = %v, expected an explicit 0", in.Lat)
+	}
+	if in.Lon != nil {
+		t.Errorf("Lon = %v, expected unset", *in.Lon)
+	}
+	if in.DwellMs == nil || *in.DwellMs != 4500 {
+		t.Errorf("DwellMs = %v, expected 4500", in.DwellMs)
+	}
+}
+
+func TestUnmarshalRejectsWrongType(t *testing.T) {
+	var in searchRequest
+	if err := in.unmarshal(appendTestVarint(nil, 1, 7)); err == nil {
+		t.Error("unmarshal() error = nil, expected an error for a varint query")
+	}
+	if err := in.unmarshal([]byte{0x0a, 0x05, 'a'}); err == nil {
+		t.Error("unmarshal() error = nil, expected an error for a truncated message")
+	}
+}
+
+func TestTrendingArticleMarshal(t *testing.T) {
+	m := &trendingArticle{models.TrendingArticleResponse{
+		ArticleResponse: models.ArticleResponse{Title: "Title"},
+		TrendingScore:   2.5,
+		EventCount:      3,
+		UniqueUsers:     2,
+		EventBreakdown:  map[string]int{"view": 2, "click": 1},
+	}}
+
+	var breakdown []string
+	var score float64
+	err := decodeFields(m.marshal(), func(f field) error {
+		switch f.num {
+		case 2:
+			score, _ = f.double()
+		case 5:
+			entry, _ := protowire.ConsumeBytes(f.value)
+			return decodeFields(entry, func(f field) error {
+				if f.num == 1 {
+					key, _ := f.string()
+					breakdown = append(breakdown, key)
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("decodeFields() error = %v", err)
+	}
+	if score != 2.5 {
+		t.Errorf("trending_score = %v, expected 2.5", score)
+	}
+	if len(breakdown) != 2 || breakdown[0] != "click" || breakdown[1] != "view" {
+		t.Errorf("event_breakdown keys = %v, expected [click view]", breakdown)
+	}
+}
+
+func TestEventAckMarshal(t *testing.T) {
+	ack := &eventAck{Sequence: 3, Error: "event queue is full", Retry: true}
+	fields := map[protowire.Number]bool{}
+	err := decodeFields(ack.marshal(), func(f field) error {
+		fields[f.num] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("decodeFields() error = %v", err)
+	}
+	if !fields[1] || fields[2] || !fields[3] || !fields[4] {
+		t.Errorf("encoded fields = %v, expected 1, 3 and 4 and no recorded=false", fields)
+	}
+}
@@ -0,0 +1,535 @@
+// Package grpc serves the core of the news API (Query, Search, Trending and
+// RecordEvent) over gRPC for internal consumers, on the same NewsService
+// and TrendingService as the REST API. The schema is
+// proto/news_service.proto.
+package grpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	"news-backend/eventschema"
+	"news-backend/models"
+	"news-backend/services"
+	"news-backend/utils"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// apiKeyMetadata is the metadata key calls present their API key in
+const apiKeyMetadata = "x-api-key"
+
+// eventMessageVersion is the event schema version the Event message
+// describes, assumed when schema_version is 0
+const eventMessageVersion = 2
+
+// Config configures access to the gRPC API with the same rules
+// middleware.APIKeyConfig and middleware.RateLimitConfig apply to REST
+type Config struct {
+	Keys            []string // full-access keys; with none and no demo key the API stays open
+	DemoKey         string   // key for the demo tier
+	DemoRateLimit   int      // demo calls per minute per client IP
+	DemoMaxArticles int      // articles per demo response
+	RateLimit       int      // calls per minute per key, or client IP without one; 0 = unlimited
+
+	Limits     services.RequestLimits  // cost limits for full-access calls
+	DemoLimits services.RequestLimits  // cost limits for demo calls
+	Redaction  services.RedactionRules // response fields hidden per key
+}
+
+// Server is the news.v1.NewsService gRPC server
+type Server struct {
+	server          *grpc.Server
+	newsService     *services.NewsService
+	trendingService *services.TrendingService
+	cfg             Config
+	demoLimiter     *utils.ClientLimiter
+	limiter         *utils.ClientLimiter // nil without a rate limit
+}
+
+// NewServer creates a gRPC server for the news service
+func NewServer(newsService *services.NewsService, trendingService *services.TrendingService, cfg Config) *Server {
+	s := &Server{
+		newsService:     newsService,
+		trendingService: trendingService,
+		cfg:             cfg,
+		demoLimiter:     utils.NewClientLimiter(cfg.DemoRateLimit),
+	}
+	if cfg.RateLimit > 0 {
+		s.limiter = utils.NewClientLimiter(cfg.RateLimit)
+	}
+	s.server = grpc.NewServer(
+		grpc.ForceServerCodec(codec{}),
+		grpc.UnaryInterceptor(s.unaryAuth),
+		grpc.StreamInterceptor(s.streamAuth),
+	)
+	s.server.RegisterService(&serviceDesc, s)
+	return s
+}
+
+// Serve accepts connections on lis until Shutdown
+func (s *Server) Serve(lis net.Listener) error {
+	return s.server.Serve(lis)
+}
+
+// Shutdown stops accepting calls and waits for in-flight ones, cancelling
+// those still running when ctx is done
+func (s *Server) Shutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
+}
+
+// =============================================================================
+// Service Description
+// =============================================================================
+
+// newsServiceServer is what serviceDesc's handlers call
+type newsServiceServer interface {
+	query(ctx context.Context, in *queryRequest) (*queryResponse, error)
+	search(in *searchRequest, stream grpc.ServerStream) error
+	trending(in *trendingRequest, stream grpc.ServerStream) error
+	recordEvent(stream grpc.ServerStream) error
+}
+
+// serviceDesc is what protoc-gen-go-grpc would generate for NewsService
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: "news.v1.NewsService",
+	HandlerType: (*newsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Query", Handler: queryHandler},
+	},
+	Streams: []grpc.StreamDesc{
+		{StreamName: "Search", Handler: searchHandler, ServerStreams: true},
+		{StreamName: "Trending", Handler: trendingHandler, ServerStreams: true},
+		{StreamName: "RecordEvent", Handler: recordEventHandler, ServerStreams: true, ClientStreams: true},
+	},
+	Metadata: "news_service.proto",
+}
+
+func queryHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(queryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(newsServiceServer).query(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/news.v1.NewsService/Query"}
+	return interceptor(ctx, in, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(newsServiceServer).query(ctx, req.(*queryRequest))
+	})
+}
+
+func searchHandler(srv interface{}, stream grpc.ServerStream) error {
+	in := new(searchRequest)
+	if err := stream.RecvMsg(in); err != nil {
+		return err
+	}
+	return srv.(newsServiceServer).search(in, stream)
+}
+
+func trendingHandler(srv interface{}, stream grpc.ServerStream) error {
+	in := new(trendingRequest)
+	if err := stream.RecvMsg(in); err != nil {
+		return err
+	}
+	return srv.(newsServiceServer).trending(in, stream)
+}
+
+func recordEventHandler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(newsServiceServer).recordEvent(stream)
+}
+
+// =============================================================================
+// Authentication
+// =============================================================================
+
+// fullAccessMethods are the calls that write, which the demo tier can't
+// make, as middleware.FullAccess guards their REST routes
+var fullAccessMethods = map[string]bool{
+	"/news.v1.NewsService/RecordEvent": true,
+}
+
+// authenticate checks the call's API key and gives its context the key's
+// tier, redaction rules and request limits, as the REST middleware does.
+// Calls are rate limited per key, or per client IP for the demo tier and
+// when no keys are configured.
+func (s *Server) authenticate(ctx context.Context, method string) (context.Context, error) {
+	client := "ip:" + peerIP(ctx)
+	if len(s.cfg.Keys) == 0 && s.cfg.DemoKey == "" {
+		if err := s.rateLimit(client); err != nil {
+			return nil, err
+		}
+		ctx = services.WithRedaction(ctx, s.cfg.Redaction.For(services.RedactionSubjectAll))
+		return services.WithRequestLimits(ctx, s.cfg.Limits), nil
+	}
+
+	var presented string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(apiKeyMetadata); len(values) > 0 {
+			presented = values[0]
+		}
+	}
+	for _, key := range s.cfg.Keys {
+		if subtle.ConstantTimeCompare([]byte(presented), []byte(key)) == 1 {
+			if err := s.rateLimit("key:" + presented); err != nil {
+				return nil, err
+			}
+			ctx = services.WithRedaction(ctx, s.cfg.Redaction.For(presented))
+			return services.WithRequestLimits(ctx, s.cfg.Limits), nil
+		}
+	}
+
+	if s.cfg.DemoKey == "" || subtle.ConstantTimeCompare([]byte(presented), []byte(s.cfg.DemoKey)) != 1 {
+		return nil, status.Error(codes.Unauthenticated, "a valid API key is required")
+	}
+	if fullAccessMethods[method] {
+		return nil, status.Error(codes.PermissionDenied, "not available with the demo API key")
+	}
+	if _, _, ok := s.demoLimiter.Allow(client); !ok {
+		return nil, status.Errorf(codes.ResourceExhausted, "the demo tier allows %d calls per minute", s.cfg.DemoRateLimit)
+	}
+	if err := s.rateLimit(client); err != nil {
+		return nil, err
+	}
+	ctx = services.WithDemoTier(ctx, s.cfg.DemoMaxArticles)
+	ctx = services.WithRedaction(ctx, s.cfg.Redaction.For(services.RedactionSubjectDemo))
+	return services.WithRequestLimits(ctx, s.cfg.DemoLimits), nil
+}
+
+// rateLimit takes one of client's calls under RateLimit
+func (s *Server) rateLimit(client string) error {
+	if s.limiter == nil {
+		return nil
+	}
+	if _, _, ok := s.limiter.Allow(client); !ok {
+		return status.Errorf(codes.ResourceExhausted, "the API allows %d calls per minute", s.cfg.RateLimit)
+	}
+	return nil
+}
+
+// peerIP is the address of the client making the call, without its port
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+func (s *Server) unaryAuth(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := s.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) streamAuth(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authenticate(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+}
+
+// authenticatedStream is a stream with the context authenticate returned
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// =============================================================================
+// RPCs
+// =============================================================================
+
+func (s *Server) query(ctx context.Context, in *queryRequest) (*queryResponse, error) {
+	if strings.TrimSpace(in.Query) == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+	radius, err := services.LimitRadius(ctx, in.Radius)
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	ranking, err := rankingOptions(in.RankingProfile, in.UserID, in.Lat, in.Lon)
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	paging, err := pagingOptions(ctx, in.Limit, in.Page)
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	result, intentResp, session, err := s.newsService.QueryInSession(ctx, in.SessionID, in.Query,
+		in.Lat, in.Lon, radius, services.DateRange{}, ranking, paging)
+	if errors.Is(err, services.ErrSessionNotFound) {
+		return nil, status.Error(codes.NotFound, "query session not found or expired")
+	}
+	if errors.Is(err, services.ErrLocationRequired) {
+		return nil, invalidArgument(err)
+	}
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	entities, err := encodeEntities(intentResp.Entities)
+	if err != nil {
+		return nil, internalError(err)
+	}
+	articles := articleResponses(ctx, result.Articles)
+	meta := models.NewResponseMetadata(len(articles), result.TotalAvailable, in.Query, nil)
+	meta.Page, meta.PageSize = result.Page, result.PageSize
+	meta.Ranking = result.Ranking
+	meta.Clamped = services.ClampedParams(ctx)
+	return &queryResponse{
+		Intent:             intentResp.Intent,
+		Entities:           entities,
+		Articles:           articles,
+		Metadata:           meta,
+		SessionID:          session.ID,
+		Turn:               session.Turns,
+		SessionExpiresUnix: session.ExpiresAt.Unix(),
+		Demo:               services.IsDemoTier(ctx),
+	}, nil
+}
+
+func (s *Server) search(in *searchRequest, stream grpc.ServerStream) error {
+	ctx := stream.Context()
+	if strings.TrimSpace(in.Query) == "" {
+		return status.Error(codes.InvalidArgument, "query is required")
+	}
+	mode := in.Mode
+	if mode == "" {
+		mode = services.SearchModeKeyword
+	}
+	if mode != services.SearchModeKeyword && mode != services.SearchModeHybrid {
+		return status.Error(codes.InvalidArgument, "mode must be 'keyword' or 'hybrid'")
+	}
+	dates, err := dateRange(ctx, in.From, in.To)
+	if err != nil {
+		return invalidArgument(err)
+	}
+	if in.Sentiment != "" && !utils.IsSentiment(in.Sentiment) {
+		return status.Error(codes.InvalidArgument, "sentiment must be one of positive, negative or neutral")
+	}
+	if in.MinSourceScore < 0 || in.MinSourceScore > 1 {
+		return status.Error(codes.InvalidArgument, "min_source_score must be between 0 and 1")
+	}
+	ranking, err := rankingOptions(in.RankingProfile, in.UserID, 0, 0)
+	if err != nil {
+		return invalidArgument(err)
+	}
+	paging, err := pagingOptions(ctx, in.Limit, in.Page)
+	if err != nil {
+		return invalidArgument(err)
+	}
+
+	result, _, err := s.newsService.SearchWithIntentMode(ctx, in.Query, mode, dates, ranking, in.Sentiment, nil, in.MinSourceScore, false, paging)
+	if err != nil {
+		return internalError(err)
+	}
+	for _, resp := range articleResponses(ctx, result.Articles) {
+		if err := stream.SendMsg(&article{resp}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) trending(in *trendingRequest, stream grpc.ServerStream) error {
+	ctx := stream.Context()
+	if err := utils.ValidateLocation(in.Lat, in.Lon); err != nil {
+		return invalidArgument(err)
+	}
+	if in.Limit < 0 {
+		return status.Error(codes.InvalidArgument, "limit must be a positive integer")
+	}
+	radius, err := services.LimitRadius(ctx, in.Radius)
+	if err != nil {
+		return invalidArgument(err)
+	}
+	limit, err := services.LimitArticles(ctx, in.Limit)
+	if err != nil {
+		return invalidArgument(err)
+	}
+	includeSummaries := in.IncludeSummaries == nil || *in.IncludeSummaries
+
+	trendingArticles, _, _, err := s.trendingService.GetTrendingNewsWithSummaries(ctx, in.Lat, in.Lon, radius, limit, includeSummaries)
+	if err != nil {
+		return internalError(err)
+	}
+	for i := range trendingArticles {
+		resp := trendingArticles[i].ToResponse()
+		services.RedactArticle(ctx, &resp.ArticleResponse)
+		if !includeSummaries {
+			resp.LLMSummary = ""
+		}
+		if err := stream.SendMsg(&trendingArticle{resp}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordEvent records events until the client closes its side of the
+// stream. A rejected event is acknowledged with the reason and doesn't end
+// the stream.
+func (s *Server) recordEvent(stream grpc.ServerStream) error {
+	for sequence := int64(1); ; sequence++ {
+		in := new(event)
+		err := stream.RecvMsg(in)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		version := in.SchemaVersion
+		if version == 0 {
+			version = eventMessageVersion
+		}
+		payload := eventschema.EventPayload{SchemaVersion: &version, DwellMs: in.DwellMs, Platform: in.Platform}
+		payload.ArticleID, payload.UserID, payload.EventType = in.ArticleID, in.UserID, in.EventType
+		payload.Lat, payload.Lon = in.Lat, in.Lon
+
+		ack := &eventAck{Sequence: sequence}
+		validated, err := eventschema.Validate(payload)
+		if err == nil {
+			err = s.trendingService.RecordUserEvent(validated)
+		}
+		switch {
+		case err == nil:
+			ack.Recorded = true
+		case errors.Is(err, services.ErrEventQueueFull):
+			ack.Error, ack.Retry = err.Error(), true
+		default:
+			ack.Error = err.Error()
+		}
+		if err := stream.SendMsg(ack); err != nil {
+			return err
+		}
+	}
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+// rankingOptions reads an optional ranking profile, as the REST
+// ranking_profile parameter. A zero location is no location.
+func rankingOptions(profile, userID string, lat, lon float64) (services.RankingOptions, error) {
+	opts := services.RankingOptions{Profile: profile}
+	if profile == "" {
+		return opts, nil
+	}
+	if !services.IsRankingProfile(profile) {
+		return opts, fmt.Errorf("ranking_profile must be one of balanced, fresh, personal or local")
+	}
+	if lat != 0 || lon != 0 {
+		if err := utils.ValidateLocation(lat, lon); err != nil {
+			return opts, err
+		}
+		opts.Lat, opts.Lon, opts.HasLocation = lat, lon, true
+	}
+	opts.UserID = userID
+	return opts, nil
+}
+
+// pagingOptions checks limit and page, 0 meaning their defaults, and caps
+// limit to the request limits
+func pagingOptions(ctx context.Context, limit, page int) (services.Paging, error) {
+	if limit < 0 {
+		return services.Paging{}, fmt.Errorf("limit must be a positive integer")
+	}
+	if page < 0 {
+		return services.Paging{}, fmt.Errorf("page must be a positive integer")
+	}
+	limit, err := services.LimitArticles(ctx, limit)
+	return services.Paging{Limit: limit, Page: page}, err
+}
+
+// dateRange parses optional from and to dates as the REST from and to
+// parameters
+func dateRange(ctx context.Context, from, to string) (services.DateRange, error) {
+	var dates services.DateRange
+	var err error
+	if dates.From, err = utils.ParseDate(from, false); err != nil {
+		return dates, fmt.Errorf("invalid 'from': %w", err)
+	}
+	if dates.To, err = utils.ParseDate(to, true); err != nil {
+		return dates, fmt.Errorf("invalid 'to': %w", err)
+	}
+	if !dates.From.IsZero() && !dates.To.IsZero() && dates.To.Before(dates.From) {
+		return dates, fmt.Errorf("'from' must not be after 'to'")
+	}
+	dates.From, err = services.LimitWindow(ctx, dates.From, dates.To)
+	return dates, err
+}
+
+// articleResponses converts articles, hiding the fields the call's
+// redaction rules cover
+func articleResponses(ctx context.Context, articles []models.Article) []models.ArticleResponse {
+	responses := make([]models.ArticleResponse, len(articles))
+	for i := range articles {
+		responses[i] = articles[i].ToResponse()
+		services.RedactArticle(ctx, &responses[i])
+	}
+	return responses
+}
+
+// encodeEntities encodes intent entities as a google.protobuf.Struct. They
+// go through JSON first, since structpb only takes JSON-shaped values.
+func encodeEntities(entities models.Entities) ([]byte, error) {
+	raw, err := json.Marshal(entities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode entities: %w", err)
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, fmt.Errorf("failed to encode entities: %w", err)
+	}
+	message, err := structpb.NewStruct(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode entities: %w", err)
+	}
+	return proto.Marshal(message)
+}
+
+func invalidArgument(err error) error {
+	return status.Error(codes.InvalidArgument, err.Error())
+}
+
+func internalError(err error) error {
+	return status.Error(codes.Internal, err.Error())
+}
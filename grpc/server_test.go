@@ -0,0 +1,109 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"news-backend/services"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	queryMethod       = "/news.v1.NewsService/Query"
+	recordEventMethod = "/news.v1.NewsService/RecordEvent"
+)
+
+// callContext is an incoming call from ip presenting key, if any
+func callContext(ip, key string) context.Context {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 5000}})
+	if key == "" {
+		return ctx
+	}
+	return metadata.NewIncomingContext(ctx, metadata.Pairs(apiKeyMetadata, key))
+}
+
+func TestAuthenticate(t *testing.T) {
+	s := NewServer(nil, nil, Config{Keys: []string{"key1", "key2"}, DemoKey: "demo", DemoRateLimit: 10})
+	tests := []struct {
+		name     string
+		key      string
+		method   string
+		expected codes.Code
+	}{
+		{"valid key", "key2", queryMethod, codes.OK},
+		{"valid key writes", "key2", recordEventMethod, codes.OK},
+		{"demo key", "demo", queryMethod, codes.OK},
+		{"demo key writes", "demo", recordEventMethod, codes.PermissionDenied},
+		{"wrong key", "key3", queryMethod, codes.Unauthenticated},
+		{"no key", "", queryMethod, codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.authenticate(callContext("10.0.0.1", tt.key), tt.method); status.Code(err) != tt.expected {
+				t.Errorf("authenticate() error = %v, expected %v", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAuthenticateDemoKeyOnly(t *testing.T) {
+	s := NewServer(nil, nil, Config{
+		DemoKey:         "demo",
+		DemoRateLimit:   2,
+		DemoMaxArticles: 5,
+		DemoLimits:      services.RequestLimits{MaxRadiusKm: 10},
+	})
+
+	if _, err := s.authenticate(callContext("10.0.0.1", ""), queryMethod); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("authenticate() without a key error = %v, expected %v", err, codes.Unauthenticated)
+	}
+
+	ctx, err := s.authenticate(callContext("10.0.0.1", "demo"), queryMethod)
+	if err != nil {
+		t.Fatalf("authenticate() with the demo key error = %v", err)
+	}
+	if !services.IsDemoTier(ctx) {
+		t.Error("demo key call is not in the demo tier")
+	}
+	if radius, err := services.LimitRadius(ctx, 500); err != nil || radius != 10 {
+		t.Errorf("LimitRadius() = %v, %v, expected the demo limit of 10 to apply", radius, err)
+	}
+
+	if _, err := s.authenticate(callContext("10.0.0.1", "demo"), queryMethod); err != nil {
+		t.Fatalf("second demo call error = %v", err)
+	}
+	if _, err := s.authenticate(callContext("10.0.0.1", "demo"), queryMethod); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("third demo call in a minute error = %v, expected %v", err, codes.ResourceExhausted)
+	}
+	if _, err := s.authenticate(callContext("10.0.0.2", "demo"), queryMethod); err != nil {
+		t.Errorf("demo call from another client error = %v, expected its own limit", err)
+	}
+}
+
+func TestAuthenticateRateLimit(t *testing.T) {
+	s := NewServer(nil, nil, Config{Keys: []string{"key1"}, RateLimit: 1})
+	if _, err := s.authenticate(callContext("10.0.0.1", "key1"), queryMethod); err != nil {
+		t.Fatalf("first call error = %v", err)
+	}
+	// The key's bucket is shared by every client using it
+	if _, err := s.authenticate(callContext("10.0.0.2", "key1"), queryMethod); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("second call in a minute error = %v, expected %v", err, codes.ResourceExhausted)
+	}
+}
+
+func TestAuthenticateOpenWithoutKeys(t *testing.T) {
+	s := NewServer(nil, nil, Config{Limits: services.RequestLimits{MaxRadiusKm: 100}})
+	ctx, err := s.authenticate(callContext("10.0.0.1", ""), queryMethod)
+	if err != nil {
+		t.Fatalf("authenticate() error = %v, expected calls without keys configured to pass", err)
+	}
+	radius, err := services.LimitRadius(ctx, 500)
+	if err != nil || radius != 100 {
+		t.Errorf("LimitRadius() = %v, %v, expected the configured limit of 100 to apply", radius, err)
+	}
+}
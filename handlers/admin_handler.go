@@ -0,0 +1,545 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"news-backend/ingest"
+	"news-backend/metrics"
+	"news-backend/models"
+	"news-backend/services"
+	"news-backend/shadow"
+	"news-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type AdminHandler struct {
+	llmService       *services.LLMService
+	trendingService  *services.TrendingService
+	articleService   *services.ArticleService
+	embeddingService *services.EmbeddingService
+	sloService       *services.SLOService
+	registry         *metrics.Registry
+	shadowMirror     *shadow.Mirror
+	degradation      *services.DegradationService
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(llmService *services.LLMService, trendingService *services.TrendingService,
+	articleService *services.ArticleService, embeddingService *services.EmbeddingService,
+	sloService *services.SLOService, registry *metrics.Registry, shadowMirror *shadow.Mirror,
+	degradation *services.DegradationService) *AdminHandler {
+	return &AdminHandler{
+		llmService:       llmService,
+		trendingService:  trendingService,
+		articleService:   articleService,
+		embeddingService: embeddingService,
+		sloService:       sloService,
+		registry:         registry,
+		shadowMirror:     shadowMirror,
+		degradation:      degradation,
+	}
+}
+
+// GetDegradation reports which optional subsystems are healthy, disabled or
+// degraded and the features reduced as a result
+// GET /api/v1/admin/degradation
+func (h *AdminHandler) GetDegradation(c *gin.Context) {
+	c.JSON(http.StatusOK, h.degradation.Report(c.Request.Context()))
+}
+
+// GetEmbeddingIndex returns the in-memory embedding index size and staleness
+// GET /api/v1/admin/embeddings/index
+func (h *AdminHandler) GetEmbeddingIndex(c *gin.Context) {
+	stats, err := h.embeddingService.IndexStats()
+	if errors.Is(err, services.ErrEmbeddingsDisabled) {
+		respondWithError(c, http.StatusServiceUnavailable, "Embeddings unavailable", err.Error())
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// RebuildEmbeddingIndex rebuilds the in-memory embedding index from the database
+// POST /api/v1/admin/embeddings/index/rebuild
+func (h *AdminHandler) RebuildEmbeddingIndex(c *gin.Context) {
+	if !h.embeddingService.Enabled() {
+		respondWithError(c, http.StatusServiceUnavailable, "Embeddings unavailable", services.ErrEmbeddingsDisabled.Error())
+		return
+	}
+	if err := h.embeddingService.LoadIndex(); err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+	h.GetEmbeddingIndex(c)
+}
+
+// createArticleRequest is the body for adding an article
+type createArticleRequest struct {
+	ID              string    `json:"id"`
+	Title           string    `json:"title" binding:"required,max=500"`
+	Description     string    `json:"description" binding:"required"`
+	URL             string    `json:"url" binding:"required,url"`
+	PublicationDate time.Time `json:"publication_date" binding:"required"`
+	SourceName      string    `json:"source_name" binding:"required"`
+	Category        []string  `json:"category" binding:"required,min=1,dive,required"`
+	RelevanceScore  float64   `json:"relevance_score" binding:"min=0,max=1"`
+	Latitude        float64   `json:"latitude" binding:"min=-90,max=90"`
+	Longitude       float64   `json:"longitude" binding:"min=-180,max=180"`
+	Publisher       string    `json:"publisher"`   // defaults to the source's publisher
+	License         string    `json:"license"`     // defaults to the source's license
+	Attribution     string    `json:"attribution"` // defaults to the source's attribution
+	ImageURL        string    `json:"image_url" binding:"omitempty,url"`
+}
+
+// updateArticleRequest holds the fields to change; omitted fields are kept
+type updateArticleRequest struct {
+	Title           *string    `json:"title" binding:"omitempty,min=1,max=500"`
+	Description     *string    `json:"description" binding:"omitempty,min=1"`
+	URL             *string    `json:"url" binding:"omitempty,url"`
+	PublicationDate *time.Time `json:"publication_date"`
+	SourceName      *string    `json:"source_name" binding:"omitempty,min=1"`
+	Category        *[]string  `json:"category" binding:"omitempty,min=1,dive,required"`
+	RelevanceScore  *float64   `json:"relevance_score" binding:"omitempty,min=0,max=1"`
+	Latitude        *float64   `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude       *float64   `json:"longitude" binding:"omitempty,min=-180,max=180"`
+	Publisher       *string    `json:"publisher" binding:"omitempty,min=1"`
+	License         *string    `json:"license" binding:"omitempty,min=1"`
+	Attribution     *string    `json:"attribution" binding:"omitempty,min=1"`
+	ImageURL        *string    `json:"image_url" binding:"omitempty,url"`
+}
+
+// apply copies the provided fields onto article
+func (r *updateArticleRequest) apply(article *models.Article) {
+	if r.Title != nil {
+		article.Title = *r.Title
+	}
+	if r.Description != nil {
+		article.Description = *r.Description
+	}
+	if r.URL != nil {
+		article.URL = *r.URL
+	}
+	if r.PublicationDate != nil {
+		article.PublicationDate = *r.PublicationDate
+	}
+	if r.SourceName != nil {
+		article.SourceName = *r.SourceName
+	}
+	if r.Category != nil {
+		article.Category = strings.Join(*r.Category, ",")
+	}
+	if r.RelevanceScore != nil {
+		article.RelevanceScore = *r.RelevanceScore
+	}
+	if r.Latitude != nil {
+		article.Latitude = *r.Latitude
+	}
+	if r.Longitude != nil {
+		article.Longitude = *r.Longitude
+	}
+	if r.Publisher != nil {
+		article.Publisher = *r.Publisher
+	}
+	if r.License != nil {
+		article.License = *r.License
+	}
+	if r.Attribution != nil {
+		article.Attribution = *r.Attribution
+	}
+	if r.ImageURL != nil {
+		article.ImageURL = *r.ImageURL
+	}
+}
+
+// CreateArticle adds an article
+// POST /api/v1/admin/articles
+// Body: {"title": "...", "description": "...", "url": "...", "publication_date": "...", "source_name": "...", "category": ["..."], ...}
+func (h *AdminHandler) CreateArticle(c *gin.Context) {
+	var req createArticleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	article := models.Article{
+		ID:              req.ID,
+		Title:           req.Title,
+		Description:     req.Description,
+		URL:             req.URL,
+		PublicationDate: req.PublicationDate,
+		SourceName:      req.SourceName,
+		Category:        strings.Join(req.Category, ","),
+		RelevanceScore:  req.RelevanceScore,
+		Latitude:        req.Latitude,
+		Longitude:       req.Longitude,
+		Publisher:       req.Publisher,
+		License:         req.License,
+		Attribution:     req.Attribution,
+		ImageURL:        req.ImageURL,
+	}
+
+	err := h.articleService.Create(&article)
+	if errors.Is(err, services.ErrArticleExists) {
+		respondWithError(c, http.StatusConflict, "Conflict", "An article with this ID already exists")
+		return
+	}
+	if errors.Is(err, services.ErrDuplicateArticle) {
+		respondWithError(c, http.StatusConflict, "Conflict", err.Error())
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, article)
+}
+
+// ImportArticles bulk-loads articles from a JSON array or CSV body and
+// reports every rejected row
+// POST /api/v1/admin/articles/import?dry_run=true
+// Content-Type: application/json or text/csv
+func (h *AdminHandler) ImportArticles(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	var records []ingest.RawArticle
+	if c.ContentType() == "text/csv" {
+		records, err = ingest.ParseCSV(body)
+	} else {
+		records, err = ingest.ParseJSON(body)
+	}
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	report, err := h.articleService.Import(records, c.Query("dry_run") == "true")
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ReloadArticles upserts the configured dataset, updating articles whose
+// content changed and inserting new ones
+// POST /api/v1/admin/articles/reload
+func (h *AdminHandler) ReloadArticles(c *gin.Context) {
+	result, err := h.articleService.Reload()
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// UpdateArticle changes an article's fields
+// PUT /api/v1/admin/articles/:id
+// Body: any subset of the CreateArticle fields except id
+func (h *AdminHandler) UpdateArticle(c *gin.Context) {
+	var req updateArticleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	article, err := h.articleService.Update(c.Param("id"), req.apply)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		respondNotFound(c, "Article not found")
+		return
+	}
+	if errors.Is(err, services.ErrDuplicateArticle) {
+		respondWithError(c, http.StatusConflict, "Conflict", err.Error())
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, article)
+}
+
+// DeleteArticle soft-deletes an article
+// DELETE /api/v1/admin/articles/:id
+func (h *AdminHandler) DeleteArticle(c *gin.Context) {
+	err := h.articleService.Delete(c.Param("id"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		respondNotFound(c, "Article not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RestoreArticle brings back a deleted article
+// POST /api/v1/admin/articles/:id/restore
+func (h *AdminHandler) RestoreArticle(c *gin.Context) {
+	article, err := h.articleService.Restore(c.Param("id"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		respondNotFound(c, "Deleted article not found")
+		return
+	}
+	if errors.Is(err, services.ErrDuplicateArticle) {
+		respondWithError(c, http.StatusConflict, "Conflict", err.Error())
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, article)
+}
+
+// GetShadowStats returns shadow traffic counters and the most recent
+// response diffs against the secondary upstream
+// GET /api/v1/admin/shadow
+func (h *AdminHandler) GetShadowStats(c *gin.Context) {
+	stats := h.shadowMirror.Stats()
+	c.JSON(http.StatusOK, gin.H{
+		"enabled": h.shadowMirror != nil,
+		"stats":   stats,
+	})
+}
+
+// GetMetrics returns lifetime request metrics per endpoint and the depth
+// and lag of the async pipelines
+// GET /api/v1/admin/metrics
+func (h *AdminHandler) GetMetrics(c *gin.Context) {
+	endpoints := h.registry.Snapshot()
+	c.JSON(http.StatusOK, gin.H{
+		"endpoints": endpoints,
+		"count":     len(endpoints),
+		"queues":    h.registry.Queues(),
+	})
+}
+
+// GetSLOStatus returns current SLO compliance and burn rates per endpoint
+// GET /api/v1/admin/slo
+func (h *AdminHandler) GetSLOStatus(c *gin.Context) {
+	statuses := h.sloService.Report()
+
+	alerting := 0
+	for _, status := range statuses {
+		if status.Alerting {
+			alerting++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"slos":     statuses,
+		"alerting": alerting,
+	})
+}
+
+// trendingExclusionRequest toggles a trending opt-out
+type trendingExclusionRequest struct {
+	Excluded *bool `json:"excluded" binding:"required"`
+}
+
+// GetTrendingExclusions lists articles and sources excluded from trending
+// GET /api/v1/admin/trending/exclusions
+func (h *AdminHandler) GetTrendingExclusions(c *gin.Context) {
+	exclusions, err := h.trendingService.GetExclusions()
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, exclusions)
+}
+
+// SetArticleTrendingExclusion opts an article in or out of trending
+// PUT /api/v1/admin/trending/exclusions/articles/:id
+// Body: {"excluded": true}
+func (h *AdminHandler) SetArticleTrendingExclusion(c *gin.Context) {
+	var req trendingExclusionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	err := h.trendingService.SetArticleExclusion(c.Param("id"), *req.Excluded)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		respondNotFound(c, "Article not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"article_id": c.Param("id"),
+		"excluded":   *req.Excluded,
+	})
+}
+
+// SetSourceTrendingExclusion opts a whole source in or out of trending
+// PUT /api/v1/admin/trending/exclusions/sources/:name
+// Body: {"excluded": true}
+func (h *AdminHandler) SetSourceTrendingExclusion(c *gin.Context) {
+	var req trendingExclusionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.trendingService.SetSourceExclusion(c.Param("name"), *req.Excluded); err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"source":   c.Param("name"),
+		"excluded": *req.Excluded,
+	})
+}
+
+// ReplayTrending recomputes what trending returned around a location at a
+// past moment, from the events up to then
+// GET /api/v1/admin/trending/replay?at=2026-03-26T09:00:00Z&lat=37.4220&lon=-122.0840&radius=50&limit=10
+func (h *AdminHandler) ReplayTrending(c *gin.Context) {
+	var req models.TrendingRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBadRequest(c, "Latitude and longitude are required")
+		return
+	}
+	if c.Query("at") == "" {
+		respondMissingParam(c, "at")
+		return
+	}
+	at, err := parseTimeParam(c.Query("at"))
+	if err != nil {
+		respondBadRequest(c, "Invalid 'at': "+err.Error())
+		return
+	}
+	if at.After(time.Now()) {
+		respondBadRequest(c, "'at' must not be in the future")
+		return
+	}
+
+	replay, err := h.trendingService.ReplayTrending(at, req.Latitude, req.Longitude, req.Radius, req.Limit)
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	articles := make([]models.TrendingArticleResponse, len(replay.Articles))
+	for i := range replay.Articles {
+		articles[i] = replay.Articles[i].ToResponse()
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"at":           replay.At.UTC().Format(time.RFC3339),
+		"window_start": replay.WindowStart.UTC().Format(time.RFC3339),
+		"events":       replay.Events,
+		"complete":     replay.Complete,
+		"location":     fmt.Sprintf("%.4f,%.4f", req.Latitude, req.Longitude),
+		"radius_km":    replay.RadiusKm,
+		"articles":     articles,
+		"count":        len(articles),
+	})
+}
+
+// ListEvents lists raw user events newest first, one page per request.
+// next_cursor fetches the following page.
+// GET /api/v1/admin/events?limit=100&cursor=...&user_id=...&article_id=...&event_type=view&from=2026-03-01&to=2026-03-26
+func (h *AdminHandler) ListEvents(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 || limit > 1000 {
+		respondBadRequest(c, "limit must be an integer between 1 and 1000")
+		return
+	}
+	cursor, err := utils.DecodeCursor(c.Query("cursor"))
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	dates, err := parseDateRange(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	eventType := strings.ToLower(c.Query("event_type"))
+	if eventType != "" && !models.IsValidEventType(eventType) {
+		respondBadRequest(c, "event_type must be one of view, click or share")
+		return
+	}
+
+	page, err := h.trendingService.ListEvents(services.EventStatsFilter{
+		From:      dates.From,
+		To:        dates.To,
+		ArticleID: c.Query("article_id"),
+		EventType: eventType,
+	}, c.Query("user_id"), cursor, limit)
+	if errors.Is(err, utils.ErrInvalidCursor) {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events":      page.Events,
+		"count":       len(page.Events),
+		"next_cursor": page.NextCursor,
+	})
+}
+
+// GetLLMUsage returns today's LLM token spend and rate limit state
+// GET /api/v1/admin/llm/usage
+func (h *AdminHandler) GetLLMUsage(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"usage":     h.llmService.Usage(),
+		"providers": h.llmService.ProviderStatuses(),
+	})
+}
+
+// GetLLMAudit lists sampled LLM interactions, newest first
+// GET /api/v1/admin/llm/audit?purpose=summary&limit=50&offset=0
+func (h *AdminHandler) GetLLMAudit(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 {
+		respondBadRequest(c, "limit must be a positive integer")
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		respondBadRequest(c, "offset must be a non-negative integer")
+		return
+	}
+
+	entries, total, err := h.llmService.AuditEntries(c.Query("purpose"), limit, offset)
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"count":   len(entries),
+		"total":   total,
+	})
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"news-backend/models"
+	"news-backend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type AlertHandler struct {
+	alertService *services.KeywordAlertService
+}
+
+// NewAlertHandler creates a new keyword alert handler
+func NewAlertHandler(alertService *services.KeywordAlertService) *AlertHandler {
+	return &AlertHandler{
+		alertService: alertService,
+	}
+}
+
+// ListAlerts returns all keyword alerts
+// GET /api/v1/admin/alerts/keywords
+func (h *AlertHandler) ListAlerts(c *gin.Context) {
+	alerts, err := h.alertService.ListAlerts()
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"alerts": alerts,
+		"count":  len(alerts),
+	})
+}
+
+// CreateAlert adds a keyword alert
+// POST /api/v1/admin/alerts/keywords
+// Body: {"keyword": "Reliance", "kind": "entity", "editor": "business-desk"}
+func (h *AlertHandler) CreateAlert(c *gin.Context) {
+	var req struct {
+		Keyword string `json:"keyword" binding:"required"`
+		Kind    string `json:"kind"`
+		Editor  string `json:"editor"`
+		Enabled *bool  `json:"enabled"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	alert := models.KeywordAlert{
+		Keyword: req.Keyword,
+		Kind:    req.Kind,
+		Editor:  req.Editor,
+		Enabled: req.Enabled == nil || *req.Enabled,
+	}
+	err := h.alertService.CreateAlert(&alert)
+	if errors.Is(err, services.ErrInvalidAlert) {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, alert)
+}
+
+// SetAlertEnabled pauses or resumes a keyword alert
+// PUT /api/v1/admin/alerts/keywords/:id
+// Body: {"enabled": false}
+func (h *AlertHandler) SetAlertEnabled(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondBadRequest(c, "Invalid alert id")
+		return
+	}
+
+	var req struct {
+		Enabled *bool `json:"enabled" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	alert, err := h.alertService.SetAlertEnabled(uint(id), *req.Enabled)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		respondNotFound(c, "Alert not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, alert)
+}
+
+// DeleteAlert removes a keyword alert
+// DELETE /api/v1/admin/alerts/keywords/:id
+func (h *AlertHandler) DeleteAlert(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondBadRequest(c, "Invalid alert id")
+		return
+	}
+
+	err = h.alertService.DeleteAlert(uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		respondNotFound(c, "Alert not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListMatches returns articles tagged by keyword alerts, newest first
+// GET /api/v1/admin/alerts/matches?status=new&alert_id=3&limit=50&offset=0
+func (h *AlertHandler) ListMatches(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 {
+		respondBadRequest(c, "limit must be a positive integer")
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		respondBadRequest(c, "offset must be a non-negative integer")
+		return
+	}
+	var alertID uint64
+	if value := c.Query("alert_id"); value != "" {
+		if alertID, err = strconv.ParseUint(value, 10, 64); err != nil {
+			respondBadRequest(c, "Invalid alert_id")
+			return
+		}
+	}
+
+	status := c.DefaultQuery("status", models.AlertMatchStatusNew)
+	if status == "all" {
+		status = ""
+	}
+
+	matches, total, err := h.alertService.ListMatches(status, uint(alertID), limit, offset)
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"matches": matches,
+		"count":   len(matches),
+		"total":   total,
+	})
+}
+
+// TriageMatch marks a tagged article as handled
+// POST /api/v1/admin/alerts/matches/:id/triage
+func (h *AlertHandler) TriageMatch(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondBadRequest(c, "Invalid match id")
+		return
+	}
+
+	match, err := h.alertService.TriageMatch(uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		respondNotFound(c, "Match not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, match)
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	"news-backend/models"
+	"news-backend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type ArticleHandler struct {
+	articleService  *services.ArticleService
+	newsService     *services.NewsService
+	trendingService *services.TrendingService
+}
+
+// NewArticleHandler creates a new article detail handler
+func NewArticleHandler(articleService *services.ArticleService, newsService *services.NewsService, trendingService *services.TrendingService) *ArticleHandler {
+	return &ArticleHandler{
+		articleService:  articleService,
+		newsService:     newsService,
+		trendingService: trendingService,
+	}
+}
+
+// GetArticle returns one article with its summary and engagement counts
+// GET /api/v1/articles/:id
+func (h *ArticleHandler) GetArticle(c *gin.Context) {
+	article, err := h.articleService.GetArticleByID(c.Param("id"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		respondNotFound(c, "Article not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	engagement, err := h.trendingService.GetArticleEngagement(article.ID)
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	enriched := h.newsService.EnrichWithSummaries(c.Request.Context(), []models.Article{*article})[0]
+	addArticleSurrogateKeys(c, []models.Article{enriched})
+
+	body := gin.H{
+		"article":    articleToResponse(c, &enriched),
+		"engagement": engagement,
+	}
+	watermarkDemo(c, body)
+	c.JSON(http.StatusOK, body)
+}
+
+// StreamSummary streams an article's summary as server-sent events while
+// the LLM writes it: "delta" events carry text as it arrives, then "done"
+// the complete summary, or "error" when the LLM fails midway
+// GET /api/v1/news/article/:id/summary/stream?tone=simple
+func (h *ArticleHandler) StreamSummary(c *gin.Context) {
+	article, err := h.articleService.GetArticleByID(c.Param("id"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		respondNotFound(c, "Article not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+	if slices.Contains(articleToResponse(c, article).Redacted, services.RedactSummary) {
+		respondWithError(c, http.StatusForbidden, "Forbidden", "This article's summary is redacted for this API key")
+		return
+	}
+
+	// Errors before the first delta still get a status code
+	ctx := c.Request.Context()
+	streaming := false
+	summary, err := h.newsService.StreamSummary(ctx, article, func(delta string) error {
+		if !streaming {
+			streaming = true
+			c.Header("X-Accel-Buffering", "no") // no proxy buffering
+			c.Status(http.StatusOK)
+		}
+		c.SSEvent("delta", gin.H{"text": delta})
+		c.Writer.Flush()
+		return ctx.Err()
+	})
+	if err != nil && !streaming {
+		if errors.Is(err, services.ErrDemoTier) {
+			respondWithError(c, http.StatusForbidden, "Forbidden", "Generating summaries is "+err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrSummaryBlocked) {
+			respondNotFound(c, "This article's summary was "+err.Error())
+			return
+		}
+		respondWithError(c, http.StatusServiceUnavailable, "Summary unavailable", err.Error())
+		return
+	}
+	if err != nil {
+		c.SSEvent("error", gin.H{"message": err.Error()})
+		return
+	}
+	c.SSEvent("done", gin.H{"summary": summary, "tone": services.SummaryTone(ctx)})
+}
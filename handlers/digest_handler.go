@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"news-backend/models"
+	"news-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DigestHandler struct {
+	digestService *services.DigestService
+}
+
+// NewDigestHandler creates a new digest handler
+func NewDigestHandler(digestService *services.DigestService) *DigestHandler {
+	return &DigestHandler{
+		digestService: digestService,
+	}
+}
+
+// GetDigest returns an LLM briefing on the day's top articles around a location
+// GET /api/v1/digest?lat=28.61&lon=77.20&categories=sports,business
+func (h *DigestHandler) GetDigest(c *gin.Context) {
+	var req models.DigestRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBadRequest(c, "Latitude and longitude are required")
+		return
+	}
+	var categories []string
+	if req.Categories != "" {
+		categories = strings.Split(req.Categories, ",")
+	}
+
+	digest, cached, err := h.digestService.GetDigest(c.Request.Context(), req.Latitude, req.Longitude, categories)
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	body := gin.H{
+		"briefing":           digest.Briefing,
+		"briefing_available": digest.Briefing != "" || len(digest.Articles) == 0,
+		"articles":           articlesToResponses(c, digest.Articles),
+		"categories":         digest.Categories,
+		"window": gin.H{
+			"from": digest.WindowStart.UTC().Format(time.RFC3339),
+			"to":   digest.WindowEnd.UTC().Format(time.RFC3339),
+		},
+		"generated_at": digest.GeneratedAt.UTC().Format(time.RFC3339),
+		"expires_at":   digest.ExpiresAt.UTC().Format(time.RFC3339),
+		"cached":       cached,
+	}
+	watermarkDemo(c, body)
+	c.JSON(http.StatusOK, body)
+}
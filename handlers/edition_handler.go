@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"news-backend/models"
+	"news-backend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type EditionHandler struct {
+	editionService *services.EditionService
+}
+
+// NewEditionHandler creates a new local edition handler
+func NewEditionHandler(editionService *services.EditionService) *EditionHandler {
+	return &EditionHandler{
+		editionService: editionService,
+	}
+}
+
+// editionRequest is the body for creating or updating an edition
+type editionRequest struct {
+	Slug      string   `json:"slug"`
+	Name      string   `json:"name" binding:"required"`
+	Latitude  *float64 `json:"latitude" binding:"required"`
+	Longitude *float64 `json:"longitude" binding:"required"`
+	RadiusKm  float64  `json:"radius_km"`
+	Enabled   *bool    `json:"enabled"`
+}
+
+func (r editionRequest) edition() models.Edition {
+	return models.Edition{
+		Slug:      r.Slug,
+		Name:      r.Name,
+		Latitude:  *r.Latitude,
+		Longitude: *r.Longitude,
+		RadiusKm:  r.RadiusKm,
+		Enabled:   r.Enabled == nil || *r.Enabled,
+	}
+}
+
+// overrideRequest is the body for pinning or excluding an article in an edition
+type overrideRequest struct {
+	Action   string `json:"action" binding:"required"`
+	Position int    `json:"position"`
+}
+
+// ListEditions returns the enabled local editions
+// GET /api/v1/editions
+func (h *EditionHandler) ListEditions(c *gin.Context) {
+	editions, err := h.editionService.ListEditions(false)
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"editions": editions,
+		"count":    len(editions),
+	})
+}
+
+// GetEditionNews returns an edition's precomputed news feed and trending list
+// GET /api/v1/editions/:slug/news
+func (h *EditionHandler) GetEditionNews(c *gin.Context) {
+	feed, err := h.editionService.Feed(c.Param("slug"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		respondNotFound(c, "Edition not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	articles, trending := feed.Articles, feed.Trending
+	if limit := services.TierLimit(c.Request.Context(), len(articles)); limit < len(articles) {
+		articles = articles[:limit]
+	}
+	if limit := services.TierLimit(c.Request.Context(), len(trending)); limit < len(trending) {
+		trending = trending[:limit]
+	}
+
+	articleResponses := make([]models.ArticleResponse, len(articles))
+	for i := range articles {
+		articleResponses[i] = articleToResponse(c, &articles[i])
+	}
+	trendingResponses := make([]models.TrendingArticleResponse, len(trending))
+	tagged := append([]models.Article(nil), articles...)
+	for i := range trending {
+		trendingResponses[i] = trendingToResponse(c, &trending[i])
+		tagged = append(tagged, trending[i].Article)
+	}
+
+	metadata := models.NewResponseMetadata(len(articleResponses), feed.TotalAvailable, "", map[string]string{
+		"edition": feed.Edition.Slug,
+	})
+	addArticleSurrogateKeys(c, tagged)
+	respondArticles(c, gin.H{
+		"edition":     feed.Edition,
+		"articles":    articleResponses,
+		"trending":    trendingResponses,
+		"pinned":      append([]string{}, feed.Pinned...),
+		"computed_at": feed.ComputedAt.Format(time.RFC3339),
+		"metadata":    metadata,
+	}, articleResponses, metadata)
+}
+
+// ListAllEditions returns every edition, including disabled ones
+// GET /api/v1/admin/editions
+func (h *EditionHandler) ListAllEditions(c *gin.Context) {
+	editions, err := h.editionService.ListEditions(true)
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"editions": editions,
+		"count":    len(editions),
+	})
+}
+
+// CreateEdition adds a local edition and precomputes its feed
+// POST /api/v1/admin/editions
+// Body: {"slug": "sf-bay-area", "name": "SF Bay Area", "latitude": 37.77, "longitude": -122.42, "radius_km": 60}
+func (h *EditionHandler) CreateEdition(c *gin.Context) {
+	var req editionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	edition := req.edition()
+	err := h.editionService.CreateEdition(&edition)
+	if errors.Is(err, services.ErrInvalidEdition) {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, edition)
+}
+
+// UpdateEdition replaces an edition's name, location, radius and status
+// PUT /api/v1/admin/editions/:slug
+// Body: {"name": "Delhi NCR", "latitude": 28.61, "longitude": 77.21, "radius_km": 40, "enabled": true}
+func (h *EditionHandler) UpdateEdition(c *gin.Context) {
+	var req editionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	edition, err := h.editionService.UpdateEdition(c.Param("slug"), req.edition())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		respondNotFound(c, "Edition not found")
+		return
+	}
+	if errors.Is(err, services.ErrInvalidEdition) {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, edition)
+}
+
+// DeleteEdition removes an edition
+// DELETE /api/v1/admin/editions/:slug
+func (h *EditionHandler) DeleteEdition(c *gin.Context) {
+	err := h.editionService.DeleteEdition(c.Param("slug"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		respondNotFound(c, "Edition not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListOverrides returns an edition's editorial overrides
+// GET /api/v1/admin/editions/:slug/overrides
+func (h *EditionHandler) ListOverrides(c *gin.Context) {
+	overrides, err := h.editionService.ListOverrides(c.Param("slug"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		respondNotFound(c, "Edition not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"overrides": overrides,
+		"count":     len(overrides),
+	})
+}
+
+// SetOverride pins an article at a position in an edition's feed or
+// excludes it, replacing any earlier override of that article
+// PUT /api/v1/admin/editions/:slug/overrides/:article_id
+// Body: {"action": "pin", "position": 1} or {"action": "exclude"}
+func (h *EditionHandler) SetOverride(c *gin.Context) {
+	var req overrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	override, err := h.editionService.SetOverride(c.Param("slug"), models.EditionOverride{
+		ArticleID: c.Param("article_id"),
+		Action:    req.Action,
+		Position:  req.Position,
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		respondNotFound(c, "Edition not found")
+		return
+	}
+	if errors.Is(err, services.ErrInvalidEdition) {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, override)
+}
+
+// DeleteOverride returns an article to the automatic ranking of an edition
+// DELETE /api/v1/admin/editions/:slug/overrides/:article_id
+func (h *EditionHandler) DeleteOverride(c *gin.Context) {
+	err := h.editionService.DeleteOverride(c.Param("slug"), c.Param("article_id"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		respondNotFound(c, "Override not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"news-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExperimentHandler struct {
+	summaryExperiment *services.SummaryExperimentService
+}
+
+// NewExperimentHandler creates a new experiment handler
+func NewExperimentHandler(summaryExperiment *services.SummaryExperimentService) *ExperimentHandler {
+	return &ExperimentHandler{
+		summaryExperiment: summaryExperiment,
+	}
+}
+
+// GetSummaryToneExperiment reports each summary tone's assignments,
+// engagement and bad_summary rates, and share of new assignments
+// GET /api/v1/admin/experiments/summary-tone
+func (h *ExperimentHandler) GetSummaryToneExperiment(c *gin.Context) {
+	report, err := h.summaryExperiment.Report()
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// ResetSummaryToneExperiment drops every assignment and result so the
+// experiment starts over
+// POST /api/v1/admin/experiments/summary-tone/reset
+func (h *ExperimentHandler) ResetSummaryToneExperiment(c *gin.Context) {
+	if err := h.summaryExperiment.Reset(); err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Summary tone experiment reset"})
+}
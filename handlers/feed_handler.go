@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"news-backend/models"
+	"news-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeedHandler struct {
+	feedService *services.FeedService
+}
+
+// NewFeedHandler creates a new feed handler
+func NewFeedHandler(feedService *services.FeedService) *FeedHandler {
+	return &FeedHandler{
+		feedService: feedService,
+	}
+}
+
+// GetFeed returns a feed whose mix of briefings, long reads and trending
+// articles follows the user's local time of day
+// GET /api/v1/feed?lat=28.61&lon=77.20&tz=Asia/Kolkata&limit=10
+func (h *FeedHandler) GetFeed(c *gin.Context) {
+	var req models.FeedRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBadRequest(c, "Latitude and longitude are required")
+		return
+	}
+
+	var err error
+	if req.Radius, err = services.LimitRadius(c.Request.Context(), req.Radius); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	if req.Limit, err = services.LimitArticles(c.Request.Context(), req.Limit); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	loc, err := services.FeedTimezone(req.Timezone, req.Longitude)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	includeSummaries := req.IncludeSummaries == nil || *req.IncludeSummaries
+
+	feed, err := h.feedService.Compose(c.Request.Context(), req.Latitude, req.Longitude, req.Radius,
+		time.Now().In(loc), req.Limit, includeSummaries)
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	var layouts []string
+	if req.Layout {
+		layouts = layoutHints(feed.Articles, feed.TrendingScores)
+	}
+
+	articles := make([]models.FeedArticleResponse, len(feed.Articles))
+	plainResponses := make([]models.ArticleResponse, len(feed.Articles))
+	for i := range feed.Articles {
+		resp := articleToResponse(c, &feed.Articles[i])
+		if !includeSummaries {
+			resp.LLMSummary = ""
+		}
+		if layouts != nil {
+			resp.Layout = layouts[i]
+		}
+		articles[i] = models.FeedArticleResponse{ArticleResponse: resp, Kind: feed.Kinds[i]}
+		plainResponses[i] = resp
+	}
+
+	response := models.FeedResponse{
+		Articles: articles,
+		Metadata: models.NewResponseMetadata(len(articles), len(articles), "", map[string]string{
+			"lat": fmt.Sprintf("%.4f", req.Latitude),
+			"lon": fmt.Sprintf("%.4f", req.Longitude),
+		}),
+		Demo: services.IsDemoTier(c.Request.Context()),
+	}
+	response.Metadata.Summaries = feed.Summaries
+	// A saved place's coordinates aren't echoed
+	if name := services.NearPlace(c.Request.Context()); name != "" {
+		response.Metadata.Filters = map[string]string{"near": name}
+	}
+	response.Metadata.Composition = &models.FeedComposition{
+		Slot:      feed.Slot.Name,
+		LocalTime: feed.LocalTime.Format("15:04"),
+		Timezone:  loc.String(),
+		Shares:    feed.Slot.Shares,
+		Mix:       feed.Mix,
+		Digest:    feed.Slot.Digest,
+	}
+	if feed.Digest != nil {
+		response.Digest = &models.FeedDigest{
+			Briefing:          feed.Digest.Briefing,
+			BriefingAvailable: feed.Digest.Briefing != "" || len(feed.Digest.Articles) == 0,
+			ArticleIDs:        make([]string, len(feed.Digest.Articles)),
+			ExpiresAt:         feed.Digest.ExpiresAt.UTC().Format(time.RFC3339),
+		}
+		for i := range feed.Digest.Articles {
+			response.Digest.ArticleIDs[i] = feed.Digest.Articles[i].ID
+		}
+	}
+
+	addArticleSurrogateKeys(c, feed.Articles)
+	respondArticles(c, response, plainResponses, response.Metadata)
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"news-backend/models"
+	"news-backend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type FeedbackHandler struct {
+	feedbackService *services.FeedbackService
+}
+
+// NewFeedbackHandler creates a new feedback handler
+func NewFeedbackHandler(feedbackService *services.FeedbackService) *FeedbackHandler {
+	return &FeedbackHandler{
+		feedbackService: feedbackService,
+	}
+}
+
+// SubmitFeedback records feedback about a summary or ranking
+// POST /api/v1/feedback
+// Body: {"article_id": "...", "type": "bad_summary", "query": "...", "endpoint": "search", "user_id": "...", "comment": "..."}
+func (h *FeedbackHandler) SubmitFeedback(c *gin.Context) {
+	var req models.FeedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	feedback := models.Feedback{
+		ArticleID: req.ArticleID,
+		Type:      req.Type,
+		UserID:    req.UserID,
+		Query:     req.Query,
+		Endpoint:  req.Endpoint,
+		Comment:   req.Comment,
+	}
+
+	err := h.feedbackService.Submit(&feedback)
+	if errors.Is(err, services.ErrInvalidFeedback) {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		respondNotFound(c, "Article not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, feedback)
+}
+
+// ListFeedback returns the feedback review queue
+// GET /api/v1/admin/feedback?status=open&type=bad_summary&limit=50
+func (h *FeedbackHandler) ListFeedback(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 {
+		respondBadRequest(c, "limit must be a positive integer")
+		return
+	}
+
+	status := c.DefaultQuery("status", models.FeedbackStatusOpen)
+	if status == "all" {
+		status = ""
+	}
+
+	feedback, total, err := h.feedbackService.List(status, c.Query("type"), limit)
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"feedback": feedback,
+		"count":    len(feedback),
+		"total":    total,
+	})
+}
+
+// ResolveFeedback closes a feedback entry
+// POST /api/v1/admin/feedback/:id/resolve
+// Body: {"action": "resolve" | "dismiss" | "blocklist_summary"}
+func (h *FeedbackHandler) ResolveFeedback(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondBadRequest(c, "Invalid feedback id")
+		return
+	}
+
+	var req struct {
+		Action string `json:"action" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	feedback, err := h.feedbackService.Resolve(uint(id), req.Action)
+	if errors.Is(err, services.ErrInvalidFeedback) {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		respondNotFound(c, "Feedback not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, feedback)
+}
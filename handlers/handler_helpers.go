@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"news-backend/models"
 	"news-backend/services"
+	"news-backend/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,6 +19,9 @@ import (
 
 // respondWithError sends a standardized error response
 func respondWithError(c *gin.Context, code int, error, message string) {
+	// Errors must never be served from an edge cache
+	c.Header("Cache-Control", "private, no-store")
+	c.Writer.Header().Del("Surrogate-Key")
 	c.JSON(code, models.ErrorResponse{
 		Error:   error,
 		Message: message,
@@ -44,34 +51,193 @@ func respondNotFound(c *gin.Context, message string) {
 
 // respondWithEntities sends a successful response with articles and parsed entities
 func (h *NewsHandler) respondWithEntities(c *gin.Context, result *services.FetchResult, intentResp *models.IntentResponse, query string) {
+	articles := articlesToResponses(c, result.Articles)
+	metadata := models.NewResponseMetadata(
+		len(result.Articles),
+		result.TotalAvailable,
+		query,
+		nil,
+	)
+	metadata.Page, metadata.PageSize = result.Page, result.PageSize
+	metadata.Ranking = result.Ranking
 	response := gin.H{
-		"articles": articlesToResponses(result.Articles),
-		"metadata": models.NewResponseMetadata(
-			len(result.Articles),
-			result.TotalAvailable,
-			query,
-			nil,
-		),
+		"articles": articles,
+		"metadata": metadata,
 		"intent":   intentResp.Intent,
 		"entities": intentResp.Entities,
 	}
+	if result.Facets != nil {
+		response["facets"] = result.Facets
+	}
 
-	c.JSON(http.StatusOK, response)
+	addArticleSurrogateKeys(c, result.Articles)
+	respondArticles(c, response, articles, metadata)
+}
+
+// =============================================================================
+// Query Parameter Helpers
+// =============================================================================
+
+// parseTimeParam parses an RFC3339 timestamp or YYYY-MM-DD date (UTC).
+// An empty value yields the zero time.
+func parseTimeParam(value string) (time.Time, error) {
+	return utils.ParseDate(value, false)
+}
+
+// parseDateRange reads the optional from/to publication date filters. A
+// date-only "to" includes the whole day.
+func parseDateRange(c *gin.Context) (services.DateRange, error) {
+	var dates services.DateRange
+	var err error
+	if dates.From, err = utils.ParseDate(c.Query("from"), false); err != nil {
+		return dates, fmt.Errorf("invalid 'from': %w", err)
+	}
+	if dates.To, err = utils.ParseDate(c.Query("to"), true); err != nil {
+		return dates, fmt.Errorf("invalid 'to': %w", err)
+	}
+	if !dates.From.IsZero() && !dates.To.IsZero() && dates.To.Before(dates.From) {
+		return dates, fmt.Errorf("'from' must not be after 'to'")
+	}
+	dates.From, err = services.LimitWindow(c.Request.Context(), dates.From, dates.To)
+	return dates, err
+}
+
+// parseSentiment reads the optional sentiment filter
+func parseSentiment(c *gin.Context) (string, error) {
+	sentiment := c.Query("sentiment")
+	if sentiment != "" && !utils.IsSentiment(sentiment) {
+		return "", fmt.Errorf("sentiment must be one of positive, negative or neutral")
+	}
+	return sentiment, nil
+}
+
+// parseMinSourceScore reads the optional min_source_score filter
+func parseMinSourceScore(c *gin.Context) (float64, error) {
+	raw := c.Query("min_source_score")
+	if raw == "" {
+		return 0, nil
+	}
+	score, err := strconv.ParseFloat(raw, 64)
+	if err != nil || score < 0 || score > 1 {
+		return 0, fmt.Errorf("min_source_score must be a number between 0 and 1")
+	}
+	return score, nil
+}
+
+// parseLimit reads the optional limit on how many articles to return, 0
+// when absent (MAX_ARTICLES). Values above the request's limit (MAX_LIMIT)
+// are clamped or rejected as REQUEST_LIMIT_MODE says.
+func parseLimit(c *gin.Context) (int, error) {
+	raw := c.Query("limit")
+	if raw == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("limit must be a positive integer")
+	}
+	return services.LimitArticles(c.Request.Context(), limit)
+}
+
+// parsePaging reads the optional limit and 1-based page of the results
+func parsePaging(c *gin.Context) (services.Paging, error) {
+	limit, err := parseLimit(c)
+	if err != nil {
+		return services.Paging{}, err
+	}
+	paging := services.Paging{Limit: limit}
+	if raw := c.Query("page"); raw != "" {
+		if paging.Page, err = strconv.Atoi(raw); err != nil || paging.Page <= 0 {
+			return services.Paging{}, fmt.Errorf("page must be a positive integer")
+		}
+	}
+	return paging, nil
+}
+
+// parseSearchFields reads the optional search_fields override of SEARCH_FIELDS
+func parseSearchFields(c *gin.Context) ([]string, error) {
+	spec := c.Query("search_fields")
+	if spec == "" {
+		return nil, nil
+	}
+	return services.ParseSearchFields(spec)
+}
+
+// parseRankingOptions reads the optional ranking_profile with the user_id
+// and lat/lon its personal and distance signals use. A ranked response for
+// a user_id is personal, so it is kept out of shared caches.
+func parseRankingOptions(c *gin.Context) (services.RankingOptions, error) {
+	opts := services.RankingOptions{Profile: c.Query("ranking_profile")}
+	if opts.Profile == "" {
+		return opts, nil
+	}
+	if !services.IsRankingProfile(opts.Profile) {
+		return opts, fmt.Errorf("ranking_profile must be one of balanced, fresh, personal or local")
+	}
+
+	if lat, lon := c.Query("lat"), c.Query("lon"); lat != "" || lon != "" {
+		var latErr, lonErr error
+		opts.Lat, latErr = strconv.ParseFloat(lat, 64)
+		opts.Lon, lonErr = strconv.ParseFloat(lon, 64)
+		if latErr != nil || lonErr != nil {
+			return opts, fmt.Errorf("lat and lon must both be numbers")
+		}
+		if err := utils.ValidateLocation(opts.Lat, opts.Lon); err != nil {
+			return opts, err
+		}
+		opts.HasLocation = true
+	}
+
+	opts.UserID = c.Query("user_id")
+	if opts.UserID != "" {
+		c.Header("Cache-Control", "private, no-store")
+		c.Writer.Header().Del("Surrogate-Key")
+	}
+	return opts, nil
 }
 
 // =============================================================================
 // Article Conversion Helpers
 // =============================================================================
 
+// articleToResponse converts an Article to an ArticleResponse, hiding the
+// fields the request's redaction rules cover
+func articleToResponse(c *gin.Context, article *models.Article) models.ArticleResponse {
+	resp := article.ToResponse()
+	services.RedactArticle(c.Request.Context(), &resp)
+	return resp
+}
+
+// trendingToResponse converts a TrendingArticle like articleToResponse
+func trendingToResponse(c *gin.Context, article *models.TrendingArticle) models.TrendingArticleResponse {
+	resp := article.ToResponse()
+	services.RedactArticle(c.Request.Context(), &resp.ArticleResponse)
+	return resp
+}
+
 // articlesToResponses converts a slice of Articles to ArticleResponses
-func articlesToResponses(articles []models.Article) []models.ArticleResponse {
+func articlesToResponses(c *gin.Context, articles []models.Article) []models.ArticleResponse {
 	responses := make([]models.ArticleResponse, len(articles))
-	for i, article := range articles {
-		responses[i] = article.ToResponse()
+	for i := range articles {
+		responses[i] = articleToResponse(c, &articles[i])
 	}
 	return responses
 }
 
+// layoutHints returns a layout hint for each article of a list from its
+// importance signals; trendingScores holds the scores of the trending ones
+func layoutHints(articles []models.Article, trendingScores map[string]float64) []string {
+	signals := make([]utils.LayoutSignals, len(articles))
+	for i := range articles {
+		signals[i] = utils.LayoutSignals{
+			Breaking: articles[i].Tone == utils.ToneUrgent,
+			Trending: trendingScores[articles[i].ID],
+			HasImage: articles[i].ImageURL != "",
+		}
+	}
+	return utils.LayoutHints(signals)
+}
+
 // =============================================================================
 // Common Handler Patterns
 // =============================================================================
@@ -84,7 +250,43 @@ func (h *NewsHandler) handleSearchWithIntent(c *gin.Context) {
 		return
 	}
 
-	result, intentResp, err := h.newsService.SearchWithIntent(query)
+	dates, err := parseDateRange(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	ranking, err := parseRankingOptions(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	sentiment, err := parseSentiment(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	searchFields, err := parseSearchFields(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	minSourceScore, err := parseMinSourceScore(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	paging, err := parsePaging(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	result, intentResp, err := h.newsService.SearchWithIntent(c.Request.Context(), query, dates, ranking, sentiment, searchFields, minSourceScore, paging)
 	if err != nil {
 		respondInternalError(c, err.Error())
 		return
@@ -101,6 +303,9 @@ type FetchOptions struct {
 	Radius   float64
 	Query    string
 	Filters  map[string]string
+	Dates    services.DateRange
+	Ranking  services.RankingOptions
+	Paging   services.Paging
 }
 
 // fetchAndRespond is a helper that handles the common pattern of:
@@ -109,28 +314,36 @@ type FetchOptions struct {
 // 3. Convert to response
 // 4. Send JSON response with metadata
 func (h *NewsHandler) fetchAndRespond(c *gin.Context, intent string, opts FetchOptions) {
-	result, err := h.newsService.FetchArticlesWithMetadata(services.FetchParams{
+	result, err := h.newsService.FetchArticlesWithMetadata(c.Request.Context(), services.FetchParams{
 		Intent:   intent,
 		Entities: opts.Entities,
 		Lat:      opts.Lat,
 		Lon:      opts.Lon,
 		Radius:   opts.Radius,
+		Dates:    opts.Dates,
+		Ranking:  opts.Ranking,
+		Paging:   opts.Paging,
 	})
 	if err != nil {
 		respondWithError(c, http.StatusInternalServerError, "Failed to fetch articles", err.Error())
 		return
 	}
 
-	articles := h.newsService.EnrichWithSummaries(result.Articles)
-	articleResponses := articlesToResponses(articles)
+	articles := h.newsService.EnrichWithSummaries(c.Request.Context(), result.Articles)
+	articleResponses := articlesToResponses(c, articles)
+	addArticleSurrogateKeys(c, articles)
 
-	c.JSON(http.StatusOK, gin.H{
+	metadata := models.NewResponseMetadata(
+		len(articleResponses),
+		result.TotalAvailable,
+		opts.Query,
+		opts.Filters,
+	)
+	metadata.Page, metadata.PageSize = result.Page, result.PageSize
+	metadata.Ranking = result.Ranking
+
+	respondArticles(c, gin.H{
 		"articles": articleResponses,
-		"metadata": models.NewResponseMetadata(
-			len(articleResponses),
-			result.TotalAvailable,
-			opts.Query,
-			opts.Filters,
-		),
-	})
+		"metadata": metadata,
+	}, articleResponses, metadata)
 }
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+
+	"news-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct {
+	healthService *services.HealthService
+}
+
+// NewHealthHandler creates a new health check handler
+func NewHealthHandler(healthService *services.HealthService) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+	}
+}
+
+// HealthCheck reports the service's dependencies, with 503 when it can't
+// serve requests
+// GET /api/v1/health
+func (h *HealthHandler) HealthCheck(c *gin.Context) {
+	h.respondReadiness(c)
+}
+
+// Liveness answers as long as the process serves HTTP, so an orchestrator
+// only restarts it when it hangs, not when a dependency is down
+// GET /healthz
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readiness reports whether the service can serve requests: 200 while the
+// database answers, even with optional dependencies down, and 503 otherwise
+// GET /readyz
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	h.respondReadiness(c)
+}
+
+func (h *HealthHandler) respondReadiness(c *gin.Context) {
+	report := h.healthService.Readiness(c.Request.Context())
+	status := http.StatusOK
+	if !report.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"news-backend/imageproxy"
+	"news-backend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type ImageHandler struct {
+	articleService *services.ArticleService
+	imageService   *services.ImageService
+}
+
+// NewImageHandler creates a new image proxy handler
+func NewImageHandler(articleService *services.ArticleService, imageService *services.ImageService) *ImageHandler {
+	return &ImageHandler{
+		articleService: articleService,
+		imageService:   imageService,
+	}
+}
+
+// GetImage sends an article's lead image, scaled down to width pixels wide
+// when given
+// GET /api/v1/images/:article_id?width=320
+func (h *ImageHandler) GetImage(c *gin.Context) {
+	width := 0
+	if raw := c.Query("width"); raw != "" {
+		var err error
+		if width, err = strconv.Atoi(raw); err != nil || width <= 0 || width > imageproxy.MaxWidth {
+			respondBadRequest(c, fmt.Sprintf("width must be between 1 and %d", imageproxy.MaxWidth))
+			return
+		}
+	}
+
+	article, err := h.articleService.GetArticleByID(c.Param("article_id"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		respondNotFound(c, "Article not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+	if slices.Contains(articleToResponse(c, article).Redacted, services.RedactURL) {
+		respondWithError(c, http.StatusForbidden, "Forbidden", "This article's links are redacted for this API key")
+		return
+	}
+	if article.ImageURL == "" {
+		respondNotFound(c, "Article has no image")
+		return
+	}
+
+	img, err := h.imageService.Image(c.Request.Context(), article.ImageURL, width)
+	if err != nil {
+		respondWithError(c, http.StatusBadGateway, "Image unavailable", err.Error())
+		return
+	}
+	// Purged with the article, whose image may change
+	c.Header("Surrogate-Key", services.ArticleSurrogateKey(article.ID))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, img.ContentType, img.Data)
+}
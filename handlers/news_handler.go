@@ -1,21 +1,28 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 
+	"news-backend/models"
 	"news-backend/services"
+	"news-backend/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
 type NewsHandler struct {
-	newsService *services.NewsService
+	newsService      *services.NewsService
+	embeddingService *services.EmbeddingService
 }
 
 // NewNewsHandler creates a new news handler
-func NewNewsHandler(newsService *services.NewsService) *NewsHandler {
+func NewNewsHandler(newsService *services.NewsService, embeddingService *services.EmbeddingService) *NewsHandler {
 	return &NewsHandler{
-		newsService: newsService,
+		newsService:      newsService,
+		embeddingService: embeddingService,
 	}
 }
 
@@ -39,7 +46,43 @@ func (h *NewsHandler) GetByScore(c *gin.Context) {
 		query = "top trending news" // Default query for score-based retrieval
 	}
 
-	result, intentResp, err := h.newsService.SearchWithIntent(query)
+	dates, err := parseDateRange(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	ranking, err := parseRankingOptions(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	sentiment, err := parseSentiment(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	searchFields, err := parseSearchFields(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	minSourceScore, err := parseMinSourceScore(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	paging, err := parsePaging(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	result, intentResp, err := h.newsService.SearchWithIntent(c.Request.Context(), query, dates, ranking, sentiment, searchFields, minSourceScore, paging)
 	if err != nil {
 		respondInternalError(c, err.Error())
 		return
@@ -51,13 +94,7 @@ func (h *NewsHandler) GetByScore(c *gin.Context) {
 // GetNearby retrieves news near a location using LLM to parse query
 // GET /api/v1/news/nearby?lat=37.4220&lon=-122.0840&radius=10&query=local+news
 func (h *NewsHandler) GetNearby(c *gin.Context) {
-	var req struct {
-		Lat    float64 `form:"lat" binding:"required"`
-		Lon    float64 `form:"lon" binding:"required"`
-		Radius float64 `form:"radius"`
-		Query  string  `form:"query"`
-	}
-
+	var req models.NearbyRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
 		respondBadRequest(c, "Latitude and longitude are required")
 		return
@@ -67,29 +104,374 @@ func (h *NewsHandler) GetNearby(c *gin.Context) {
 		req.Query = "local news" // Default query for nearby
 	}
 
-	articles, intentResp, err := h.newsService.QueryWithIntent(req.Query, req.Lat, req.Lon, req.Radius)
+	radius, err := services.LimitRadius(c.Request.Context(), req.Radius)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	req.Radius = radius
+
+	dates, err := parseDateRange(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	ranking, err := parseRankingOptions(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	paging, err := parsePaging(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	result, intentResp, err := h.newsService.QueryWithIntent(c.Request.Context(), req.Query, req.Lat, req.Lon, req.Radius, dates, ranking, paging)
 	if err != nil {
 		respondInternalError(c, err.Error())
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	articles := articlesToResponses(c, result.Articles)
+	addArticleSurrogateKeys(c, result.Articles)
+	metadata := models.NewResponseMetadata(len(articles), result.TotalAvailable, req.Query, nil)
+	metadata.Page, metadata.PageSize = result.Page, result.PageSize
+	metadata.Ranking = result.Ranking
+	response := gin.H{
 		"intent":   intentResp.Intent,
 		"entities": intentResp.Entities,
-		"articles": articlesToResponses(articles),
+		"articles": articles,
 		"count":    len(articles),
+		"facets":   result.Facets,
 		"location": map[string]interface{}{
 			"lat":    req.Lat,
 			"lon":    req.Lon,
 			"radius": req.Radius,
 		},
-	})
+		"metadata": metadata,
+	}
+	// A saved place's coordinates aren't echoed
+	if name := services.NearPlace(c.Request.Context()); name != "" {
+		response["location"] = map[string]interface{}{"near": name, "radius": req.Radius}
+	}
+	if result.Ranking != nil {
+		response["ranking"] = result.Ranking
+	}
+	respondArticles(c, response, articles, metadata)
 }
 
 // Search performs text search on articles using LLM to parse query
-// GET /api/v1/news/search?query=climate+change
+// GET /api/v1/news/search?query=climate+change&mode=hybrid&from=2025-01-01&to=2025-01-31&sentiment=positive&search_fields=title,summary
 func (h *NewsHandler) Search(c *gin.Context) {
-	h.handleSearchWithIntent(c)
+	query := c.Query("query")
+	if query == "" {
+		respondMissingParam(c, "Query parameter")
+		return
+	}
+
+	mode := c.DefaultQuery("mode", services.SearchModeKeyword)
+	if mode != services.SearchModeKeyword && mode != services.SearchModeHybrid {
+		respondBadRequest(c, "mode must be 'keyword' or 'hybrid'")
+		return
+	}
+
+	dates, err := parseDateRange(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	ranking, err := parseRankingOptions(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	sentiment, err := parseSentiment(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	searchFields, err := parseSearchFields(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	minSourceScore, err := parseMinSourceScore(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	paging, err := parsePaging(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	includeArchived := c.Query("include_archived") == "true"
+
+	result, intentResp, err := h.newsService.SearchWithIntentMode(c.Request.Context(), query, mode, dates, ranking, sentiment, searchFields, minSourceScore, includeArchived, paging)
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	h.respondWithEntities(c, result, intentResp, query)
+}
+
+// SemanticSearch ranks articles by embedding similarity to the query
+// GET /api/v1/news/semantic-search?query=EV+battery+plants
+func (h *NewsHandler) SemanticSearch(c *gin.Context) {
+	query := c.Query("query")
+	if query == "" {
+		respondMissingParam(c, "Query parameter")
+		return
+	}
+
+	dates, err := parseDateRange(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	limit, err := parseLimit(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	if limit == 0 {
+		limit = h.newsService.MaxArticles()
+	}
+
+	result, err := h.embeddingService.SemanticSearch(c.Request.Context(), query, limit, dates)
+	if errors.Is(err, services.ErrEmbeddingsDisabled) {
+		respondWithError(c, http.StatusServiceUnavailable, "Semantic search unavailable", err.Error())
+		return
+	}
+	if errors.Is(err, services.ErrDemoTier) {
+		respondWithError(c, http.StatusForbidden, "Forbidden", "Semantic search is "+err.Error())
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	result.Articles = services.Mutes(c.Request.Context()).Filter(result.Articles)
+	enriched := h.newsService.EnrichWithSummaries(c.Request.Context(), result.Articles)
+	articles := articlesToResponses(c, enriched)
+	addArticleSurrogateKeys(c, enriched)
+	metadata := models.NewResponseMetadata(
+		len(articles),
+		result.TotalAvailable,
+		query,
+		nil,
+	)
+
+	respondArticles(c, gin.H{
+		"articles": articles,
+		"metadata": metadata,
+	}, articles, metadata)
+}
+
+// Ask answers a question from the most relevant articles, citing them
+// POST /api/v1/news/ask?from=2025-03-01
+// Body: {"question": "What did the RBI decide about the repo rate?"}
+func (h *NewsHandler) Ask(c *gin.Context) {
+	var req models.AskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	dates, err := parseDateRange(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	answer, err := h.newsService.Ask(c.Request.Context(), req.Question, dates)
+	if errors.Is(err, services.ErrInvalidQuestion) {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	if errors.Is(err, services.ErrDemoTier) {
+		respondWithError(c, http.StatusForbidden, "Forbidden", "Question answering is "+err.Error())
+		return
+	}
+	if errors.Is(err, services.ErrAnswerUnavailable) {
+		respondWithError(c, http.StatusServiceUnavailable, "Answer unavailable", err.Error())
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	citations := make([]gin.H, len(answer.Citations))
+	for i := range answer.Citations {
+		resp := articleToResponse(c, &answer.Citations[i])
+		citations[i] = gin.H{
+			"id":               answer.Citations[i].ID,
+			"title":            resp.Title,
+			"url":              resp.URL,
+			"source_name":      resp.SourceName,
+			"publication_date": resp.PublicationDate,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"question":         answer.Question,
+		"answer":           answer.Answer,
+		"grounded":         len(citations) > 0,
+		"citations":        citations,
+		"context_articles": answer.Context,
+	})
+}
+
+// Query answers a free-form query as a turn of a conversation: follow-ups
+// sent with the returned session_id refine the previous query
+// POST /api/v1/news/query?from=2025-03-01
+// Body: {"query": "cricket news", "session_id": "...", "lat": 19.07, "lon": 72.87, "radius": 10}
+func (h *NewsHandler) Query(c *gin.Context) {
+	var req models.NewsQueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	radius, err := services.LimitRadius(c.Request.Context(), req.Radius)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	dates, err := parseDateRange(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	ranking, err := parseRankingOptions(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	paging, err := parsePaging(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	result, intentResp, session, err := h.newsService.QueryInSession(c.Request.Context(), req.SessionID, req.Query,
+		req.Latitude, req.Longitude, radius, dates, ranking, paging)
+	if errors.Is(err, services.ErrSessionNotFound) {
+		respondNotFound(c, "Query session not found or expired")
+		return
+	}
+	if errors.Is(err, services.ErrLocationRequired) {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	articles := articlesToResponses(c, result.Articles)
+	addArticleSurrogateKeys(c, result.Articles)
+	metadata := models.NewResponseMetadata(len(articles), result.TotalAvailable, req.Query, nil)
+	metadata.Page, metadata.PageSize = result.Page, result.PageSize
+	metadata.Ranking = result.Ranking
+	c.JSON(http.StatusOK, gin.H{
+		"intent":   intentResp.Intent,
+		"entities": intentResp.Entities,
+		"articles": articles,
+		"count":    len(articles),
+		"facets":   result.Facets,
+		"session": gin.H{
+			"id":         session.ID,
+			"turn":       session.Turns,
+			"expires_at": session.ExpiresAt,
+		},
+		"metadata": metadata,
+	})
+}
+
+// GetLatest lists articles newest first, one page per request. The
+// metadata's next_cursor fetches the following page.
+// GET /api/v1/news/latest?category=sports&source=News18&since=2025-03-20&limit=20&cursor=...
+func (h *NewsHandler) GetLatest(c *gin.Context) {
+	params := services.LatestParams{
+		Category: c.Query("category"),
+		Source:   c.Query("source"),
+	}
+	var err error
+	if params.Cursor, err = utils.DecodeCursor(c.Query("cursor")); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	if params.Since, err = parseTimeParam(c.Query("since")); err != nil {
+		respondBadRequest(c, "Invalid 'since': "+err.Error())
+		return
+	}
+	if params.Limit, err = parseLimit(c); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	page, err := h.newsService.Latest(c.Request.Context(), params)
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	// Later pages only change when their articles do, which purges them
+	if params.Cursor.IsZero() && strings.HasPrefix(c.Writer.Header().Get("Cache-Control"), "public") {
+		if ttl := h.newsService.LatestCacheTTL(); ttl > 0 {
+			c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", ttl))
+		} else {
+			c.Header("Cache-Control", "private, no-store")
+			c.Writer.Header().Del("Surrogate-Key")
+		}
+	}
+
+	filters := map[string]string{}
+	for _, key := range []string{"category", "source", "since"} {
+		if value := c.Query(key); value != "" {
+			filters[key] = value
+		}
+	}
+	articles := articlesToResponses(c, page.Articles)
+	addArticleSurrogateKeys(c, page.Articles)
+	metadata := models.NewResponseMetadata(len(articles), len(articles), "", filters)
+	metadata.NextCursor = page.NextCursor
+
+	respondArticles(c, gin.H{
+		"articles": articles,
+		"metadata": metadata,
+	}, articles, metadata)
+}
+
+// GetCategories lists all categories with their article counts
+// GET /api/v1/news/categories
+func (h *NewsHandler) GetCategories(c *gin.Context) {
+	categories, err := h.newsService.GetCategories()
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"categories": categories,
+		"count":      len(categories),
+	})
 }
 
 // GetStats returns statistics about the news database
@@ -103,12 +485,13 @@ func (h *NewsHandler) GetStats(c *gin.Context) {
 	c.JSON(http.StatusOK, stats)
 }
 
-// HealthCheck is a simple health check endpoint
-// GET /api/v1/health
-func (h *NewsHandler) HealthCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "healthy",
-		"service": "news-backend",
-		"version": "1.0.0",
-	})
+// GetPublicStats returns coarse corpus aggregates for status pages
+// GET /api/v1/stats/public
+func (h *NewsHandler) GetPublicStats(c *gin.Context) {
+	stats, err := h.newsService.GetPublicStats()
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, stats)
 }
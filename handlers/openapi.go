@@ -0,0 +1,468 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"news-backend/apischema"
+	"news-backend/eventschema"
+	"news-backend/models"
+	"news-backend/services"
+)
+
+// apiKeyPrefixes are the path prefixes of the routes behind the API key check
+var apiKeyPrefixes = []string{
+	"/api/v1/news", "/api/v1/stories", "/api/v1/articles", "/api/v1/editions", "/api/v1/trending",
+	"/api/v1/digest", "/api/v1/feed", "/api/v1/feeds", "/api/v1/topics", "/api/v1/users", "/api/v1/feedback",
+}
+
+// Query parameters shared by the news endpoints, read by the parse helpers
+var (
+	dateParams = []apischema.Param{
+		{Name: "from", Description: "Published at or after: RFC3339 or YYYY-MM-DD"},
+		{Name: "to", Description: "Published before: RFC3339, or YYYY-MM-DD for the whole day"},
+	}
+	rankingParams = []apischema.Param{
+		{Name: "ranking_profile", Enum: "ranking_profiles"},
+		{Name: "lat", Type: "number", Description: "Location for the distance signal"},
+		{Name: "lon", Type: "number", Description: "Location for the distance signal"},
+		{Name: "user_id", Description: "User whose history feeds the personal signal; also applies their mutes"},
+	}
+	filterParams = []apischema.Param{
+		{Name: "sentiment", Enum: "sentiments"},
+		{Name: "search_fields", Description: "Comma-separated fields to match the query in"},
+		{Name: "min_source_score", Type: "number", Description: "Minimum source reliability, 0 to 1"},
+	}
+	pagingParams = []apischema.Param{
+		{Name: "limit", Type: "integer", Description: "Articles per page, up to MAX_LIMIT"},
+		{Name: "page", Type: "integer", Description: "1-based page number"},
+	}
+	feedParams = []apischema.Param{
+		{Name: "category"},
+		{Name: "source"},
+		{Name: "sort", Description: "latest (the default) or top"},
+		{Name: "limit", Type: "integer", Description: "Articles in the feed, up to MAX_LIMIT"},
+		{Name: "api_key", Description: "API key, for feed readers that can't send X-API-Key"},
+	}
+	toneParam  = apischema.Param{Name: "tone", Enum: "summary_tones"}
+	nearParam  = apischema.Param{Name: "near", Description: "Name of one of the user's saved places, instead of lat and lon"}
+	queryParam = apischema.Param{Name: "query", Required: true, Description: "Free-text query interpreted by the LLM"}
+)
+
+// params joins parameter lists
+func params(lists ...[]apischema.Param) []apischema.Param {
+	var all []apischema.Param
+	for _, list := range lists {
+		all = append(all, list...)
+	}
+	return all
+}
+
+// Response bodies the handlers assemble as gin.H
+var (
+	articleList = apischema.Object{
+		"articles": []models.ArticleResponse{},
+		"metadata": models.ResponseMetadata{},
+	}
+	intentArticleList = apischema.Object{
+		"articles": []models.ArticleResponse{},
+		"metadata": models.ResponseMetadata{},
+		"intent":   "",
+		"entities": models.Entities{},
+		"facets":   models.Facets{},
+	}
+	statusMessage = apischema.Object{"status": "", "message": ""}
+)
+
+// endpoints documents each operation's parameters and bodies for the
+// OpenAPI spec. Operations are named after their handlers (see
+// apischema.Operation); routes missing here are listed without them.
+var endpoints = map[string]apischema.Endpoint{
+	"schema.getSchema":    {Summary: "Routes, enumerations and types for generating client SDKs"},
+	"schema.getOpenAPI":   {Summary: "This OpenAPI document"},
+	"schema.getWellKnown": {Summary: "API versions and where their descriptions are"},
+	"schema.getDocs":      {Summary: "Swagger UI on this document", Response: "", ContentType: "text/html"},
+
+	"health.healthCheck": {Summary: "Readiness and dependency checks; 503 when the service can't serve requests", Response: services.HealthReport{}},
+	"health.liveness":    {Summary: "Liveness probe: 200 while the process serves HTTP", Response: apischema.Object{"status": ""}},
+	"health.readiness":   {Summary: "Readiness probe, as /api/v1/health", Response: services.HealthReport{}},
+
+	"news.getByCategory": {
+		Summary:  "News in the category the query names",
+		Params:   params([]apischema.Param{queryParam, toneParam}, dateParams, rankingParams, filterParams, pagingParams),
+		Response: intentArticleList,
+		Articles: true,
+	},
+	"news.getBySource": {
+		Summary:  "News from the source the query names",
+		Params:   params([]apischema.Param{queryParam, toneParam}, dateParams, rankingParams, filterParams, pagingParams),
+		Response: intentArticleList,
+		Articles: true,
+	},
+	"news.getByScore": {
+		Summary: "Most relevant news",
+		Params: params([]apischema.Param{{Name: "query", Description: "Defaults to \"top trending news\""}, toneParam},
+			dateParams, rankingParams, filterParams, pagingParams),
+		Response: intentArticleList,
+		Articles: true,
+	},
+	"news.search": {
+		Summary: "Text search",
+		Params: params([]apischema.Param{queryParam, {Name: "mode", Enum: "search_modes"}, toneParam,
+			{Name: "include_archived", Type: "boolean", Description: "Also search articles moved to the archive"}},
+			dateParams, rankingParams, filterParams, pagingParams),
+		Response: intentArticleList,
+		Articles: true,
+	},
+	"news.getNearby": {
+		Summary: "News near a location",
+		Query:   models.NearbyRequest{},
+		Params:  params([]apischema.Param{nearParam, toneParam}, dateParams, rankingParams[:1], pagingParams),
+		Response: apischema.Object{
+			"articles": []models.ArticleResponse{},
+			"metadata": models.ResponseMetadata{},
+			"intent":   "",
+			"entities": models.Entities{},
+			"facets":   models.Facets{},
+			"count":    0,
+			"location": apischema.Object{"lat": 0.0, "lon": 0.0, "radius": 0.0},
+			"ranking":  models.RankingInfo{},
+		},
+		Articles: true,
+	},
+	"news.semanticSearch": {
+		Summary:  "Articles nearest the query in embedding space",
+		Params:   params([]apischema.Param{queryParam, toneParam}, dateParams, pagingParams[:1]),
+		Response: articleList,
+		Articles: true,
+	},
+	"news.getLatest": {
+		Summary: "Newest articles, one cursor page at a time",
+		Params: []apischema.Param{
+			{Name: "category"}, {Name: "source"},
+			{Name: "since", Description: "Published at or after: RFC3339 or YYYY-MM-DD"},
+			pagingParams[0],
+			{Name: "cursor", Description: "metadata.next_cursor of the previous page"},
+			toneParam,
+		},
+		Response: articleList,
+		Articles: true,
+	},
+	"news.ask": {
+		Summary: "Answer a question from the articles, citing them",
+		Params:  dateParams,
+		Body:    models.AskRequest{},
+		Response: apischema.Object{
+			"question": "",
+			"answer":   "",
+			"grounded": false,
+			"citations": []apischema.Object{{
+				"id": "", "title": "", "url": "", "source_name": "", "publication_date": time.Time{},
+			}},
+			"context_articles": 0,
+		},
+	},
+	"news.query": {
+		Summary: "Conversational query; follow-ups with the session id refine it",
+		Params:  params(dateParams, rankingParams, pagingParams),
+		Body:    models.NewsQueryRequest{},
+		Response: apischema.Object{
+			"articles": []models.ArticleResponse{},
+			"metadata": models.ResponseMetadata{},
+			"intent":   "",
+			"entities": models.Entities{},
+			"facets":   models.Facets{},
+			"count":    0,
+			"session":  apischema.Object{"id": "", "turn": 0, "expires_at": time.Time{}},
+		},
+	},
+	"news.getCategories": {
+		Summary:  "Categories with their article counts",
+		Response: apischema.Object{"categories": []models.CategoryCount{}, "count": 0},
+	},
+	"news.getStats":       {Summary: "Corpus statistics"},
+	"news.getPublicStats": {Summary: "Coarse corpus aggregates for status pages"},
+
+	"story.getStory": {
+		Summary: "A cluster of near-duplicate articles",
+		Params:  []apischema.Param{toneParam},
+		Response: apischema.Object{
+			"story_id":       "",
+			"representative": models.ArticleResponse{},
+			"articles":       []models.ArticleResponse{},
+			"count":          0,
+		},
+	},
+	"article.getArticle": {
+		Summary:  "An article with its summary and engagement",
+		Params:   []apischema.Param{toneParam},
+		Response: apischema.Object{"article": models.ArticleResponse{}, "engagement": services.ArticleEngagement{}},
+	},
+	"article.streamSummary": {
+		Summary: "Article summary as server-sent events: delta, then done or error",
+		Params:  []apischema.Param{toneParam},
+	},
+	"image.getImage": {
+		Summary: "An article's lead image, proxied from its publisher and scaled down to width",
+		Params: []apischema.Param{
+			{Name: "width", Type: "integer", Description: "Pixels wide, up to 2048; narrower images and WebP or AVIF are sent as they are"},
+		},
+		Response:    "",
+		ContentType: "image/*",
+	},
+
+	"edition.listEditions": {
+		Summary:  "Enabled local editions",
+		Response: apischema.Object{"editions": []models.Edition{}, "count": 0},
+	},
+	"edition.getEditionNews": {
+		Summary: "An edition's precomputed feed and trending list",
+		Response: apischema.Object{
+			"edition":     models.Edition{},
+			"articles":    []models.ArticleResponse{},
+			"trending":    []models.TrendingArticleResponse{},
+			"pinned":      []string{},
+			"computed_at": time.Time{},
+			"metadata":    models.ResponseMetadata{},
+		},
+		Articles: true,
+	},
+
+	"trending.getTrending": {
+		Summary:  "Trending news around a location",
+		Query:    models.TrendingRequest{},
+		Params:   []apischema.Param{nearParam, {Name: "user_id"}, toneParam},
+		Response: models.TrendingResponse{},
+		Articles: true,
+	},
+	"trending.recordEvent": {
+		Summary:  "Record a view, click or share; every event schema_version is accepted",
+		Body:     eventschema.EventPayload{},
+		Response: statusMessage,
+	},
+	"trending.getEventStats": {
+		Summary: "Event counts, optionally bucketed and filtered",
+		Query:   models.EventStatsRequest{},
+	},
+	"trending.invalidateCache": {Summary: "Clear the trending cache", Response: statusMessage},
+
+	"digest.getDigest": {
+		Summary: "Daily briefing on the top articles around a location",
+		Query:   models.DigestRequest{},
+		Response: apischema.Object{
+			"briefing":           "",
+			"briefing_available": false,
+			"articles":           []models.ArticleResponse{},
+			"categories":         []string{},
+			"window":             apischema.Object{"from": time.Time{}, "to": time.Time{}},
+			"generated_at":       time.Time{},
+			"expires_at":         time.Time{},
+			"cached":             false,
+		},
+	},
+	"feed.getFeed": {
+		Summary:  "Feed mixed by the user's local time of day",
+		Query:    models.FeedRequest{},
+		Params:   []apischema.Param{nearParam, {Name: "user_id"}, toneParam},
+		Response: models.FeedResponse{},
+		Articles: true,
+	},
+	"syndication.getRSS": {
+		Summary:     "Latest or top articles as an RSS 2.0 feed",
+		Params:      feedParams,
+		Response:    "",
+		ContentType: "application/rss+xml",
+	},
+	"syndication.getAtom": {
+		Summary:     "Latest or top articles as an Atom 1.0 feed",
+		Params:      feedParams,
+		Response:    "",
+		ContentType: "application/atom+xml",
+	},
+	"topic.getTrending": {
+		Summary: "Topics ranked by recent articles and engagement",
+		Params: []apischema.Param{
+			{Name: "hours", Type: "integer", Description: "Window, 24 by default"},
+			{Name: "limit", Type: "integer"},
+		},
+		Response: apischema.Object{"topics": []models.TrendingTopic{}, "window_hours": 0},
+	},
+
+	"user.getLinks": {
+		Summary:  "Identifiers linked to a user",
+		Response: apischema.Object{"canonical_user_id": "", "links": []models.UserLink{}},
+	},
+	"user.registerDevice": {
+		Summary:  "Register the identifier and issue its device secret",
+		Status:   http.StatusCreated,
+		Response: models.DeviceSecretResponse{},
+	},
+	"user.issueLinkToken": {
+		Summary:  "Issue a token for linking the identifier to another",
+		Body:     models.LinkTokenRequest{},
+		Status:   http.StatusCreated,
+		Response: models.LinkTokenResponse{},
+	},
+	"user.linkUser": {
+		Summary:  "Link another identifier to the user",
+		Body:     models.LinkUserRequest{},
+		Status:   http.StatusCreated,
+		Response: models.UserLink{},
+	},
+	"user.unlinkUser": {Summary: "Remove a linked identifier", Response: statusMessage},
+	"user.getLinkSuggestions": {
+		Summary:  "Scores of identifiers that likely belong to the same person",
+		Response: apischema.Object{"user_id": "", "suggestions": []models.UserLinkSuggestion{}},
+	},
+	"user.getPreferences": {Summary: "Response preferences", Response: models.UserPreference{}},
+	"user.setPreferences": {
+		Summary:  "Set response preferences",
+		Body:     models.PreferencesRequest{},
+		Response: models.UserPreference{},
+	},
+	"user.getFeedAdjustments": {
+		Summary: "Category and source adjustments from feed signals",
+		Response: apischema.Object{
+			"user_id":              "",
+			"category_adjustments": map[string]float64{},
+			"source_adjustments":   map[string]float64{},
+		},
+	},
+	"user.recordFeedSignal": {
+		Summary:  "Record a more or less like this reaction",
+		Body:     models.FeedSignalRequest{},
+		Status:   http.StatusCreated,
+		Response: models.FeedSignal{},
+	},
+	"user.clearFeedSignals": {
+		Summary:  "Reset feed signals",
+		Response: apischema.Object{"user_id": "", "cleared": 0},
+	},
+	"user.getMutes": {
+		Summary:  "Muted sources, categories and keywords",
+		Response: apischema.Object{"user_id": "", "mutes": []models.UserMute{}},
+	},
+	"user.addMute": {
+		Summary:  "Mute a source, category or keyword",
+		Body:     models.MuteRequest{},
+		Status:   http.StatusCreated,
+		Response: models.UserMute{},
+	},
+	"user.removeMute": {Summary: "Unmute", Response: statusMessage},
+	"topic.getFollowed": {
+		Summary:  "Followed topics",
+		Response: apischema.Object{"user_id": "", "topics": []models.TopicFollow{}},
+	},
+	"topic.follow": {
+		Summary:  "Follow a topic",
+		Status:   http.StatusCreated,
+		Response: models.TopicFollow{},
+	},
+	"topic.unfollow": {Summary: "Unfollow a topic", Response: statusMessage},
+	"place.getPlaces": {
+		Summary:  "Saved places",
+		Response: apischema.Object{"user_id": "", "places": []models.SavedPlace{}},
+	},
+	"place.savePlace": {
+		Summary:  "Save or move a named place",
+		Body:     models.SavePlaceRequest{},
+		Response: models.SavedPlace{},
+	},
+	"place.deletePlace": {Summary: "Delete a saved place", Response: statusMessage},
+	"feedback.submitFeedback": {
+		Summary:  "Report a bad summary or ranking",
+		Body:     models.FeedbackRequest{},
+		Status:   http.StatusCreated,
+		Response: models.Feedback{},
+	},
+
+	"admin.createArticle": {
+		Summary:  "Add an article",
+		Body:     createArticleRequest{},
+		Status:   http.StatusCreated,
+		Response: models.Article{},
+	},
+	"admin.updateArticle":  {Summary: "Change an article's fields", Body: updateArticleRequest{}, Response: models.Article{}},
+	"admin.deleteArticle":  {Summary: "Soft-delete an article; it can be restored", Status: http.StatusNoContent},
+	"admin.restoreArticle": {Summary: "Restore a deleted article", Response: models.Article{}},
+	"admin.setArticleTrendingExclusion": {
+		Summary: "Opt an article in or out of trending",
+		Body:    trendingExclusionRequest{},
+	},
+	"admin.setSourceTrendingExclusion": {
+		Summary: "Opt a source in or out of trending",
+		Body:    trendingExclusionRequest{},
+	},
+	"admin.replayTrending": {
+		Summary:  "Trending as it was at a past moment",
+		Query:    models.TrendingRequest{},
+		Params:   []apischema.Param{{Name: "at", Required: true, Description: "RFC3339 timestamp"}},
+		Response: models.TrendingResponse{},
+	},
+	"edition.listAllEditions": {
+		Summary:  "Every edition, including disabled ones",
+		Response: apischema.Object{"editions": []models.Edition{}, "count": 0},
+	},
+	"edition.createEdition": {
+		Summary:  "Add a local edition",
+		Body:     editionRequest{},
+		Status:   http.StatusCreated,
+		Response: models.Edition{},
+	},
+	"edition.updateEdition": {Summary: "Replace an edition", Body: editionRequest{}, Response: models.Edition{}},
+	"edition.deleteEdition": {Summary: "Delete an edition", Status: http.StatusNoContent},
+	"edition.listOverrides": {
+		Summary:  "An edition's editorial overrides",
+		Response: apischema.Object{"overrides": []models.EditionOverride{}, "count": 0},
+	},
+	"edition.setOverride": {
+		Summary:  "Pin or exclude an article in an edition",
+		Body:     overrideRequest{},
+		Response: models.EditionOverride{},
+	},
+	"edition.deleteOverride": {Summary: "Return an article to automatic ranking", Status: http.StatusNoContent},
+	"admin.importArticles": {
+		Summary: "Bulk-load articles from a JSON array or CSV body",
+		Params:  []apischema.Param{{Name: "dry_run", Type: "boolean"}},
+	},
+	"admin.reloadArticles":        {Summary: "Upsert the configured dataset"},
+	"admin.getLLMUsage":           {Summary: "Today's LLM token spend and rate limit state"},
+	"admin.getLLMAudit":           {Summary: "Sampled LLM interactions, newest first"},
+	"admin.getMetrics":            {Summary: "Request metrics per endpoint and pipeline queue depths"},
+	"admin.getSLOStatus":          {Summary: "SLO compliance and burn rates per endpoint"},
+	"admin.getDegradation":        {Summary: "Optional subsystems and the features reduced without them"},
+	"admin.getEmbeddingIndex":     {Summary: "In-memory embedding index size and staleness"},
+	"admin.rebuildEmbeddingIndex": {Summary: "Rebuild the in-memory embedding index"},
+	"admin.getShadowStats":        {Summary: "Shadow traffic counters and recent differences"},
+	"admin.getTrendingExclusions": {Summary: "Articles and sources excluded from trending"},
+	"admin.listEvents": {
+		Summary: "Raw user events, newest first, one cursor page at a time",
+		Params: params([]apischema.Param{
+			{Name: "limit", Type: "integer"},
+			{Name: "cursor", Description: "next_cursor of the previous page"},
+			{Name: "user_id"}, {Name: "article_id"}, {Name: "event_type", Enum: "event_types"},
+		}, dateParams),
+		Response: apischema.Object{"events": []models.UserEvent{}, "count": 0, "next_cursor": ""},
+	},
+	"source.listSources":                    {Summary: "Every source's reliability rating"},
+	"source.setSourceReliability":           {Summary: "Rate a source; a null score clears the rating"},
+	"feedback.listFeedback":                 {Summary: "The feedback review queue"},
+	"feedback.resolveFeedback":              {Summary: "Close a feedback entry", Response: models.Feedback{}},
+	"experiment.getSummaryToneExperiment":   {Summary: "Summary tone experiment results per arm"},
+	"experiment.resetSummaryToneExperiment": {Summary: "Start the summary tone experiment over"},
+	"snapshot.createSnapshot":               {Summary: "Export a consistent snapshot for model training", Status: http.StatusCreated, Response: services.SnapshotManifest{}},
+	"alert.listAlerts":                      {Summary: "Keyword alerts"},
+	"alert.createAlert":                     {Summary: "Add a keyword alert", Status: http.StatusCreated, Response: models.KeywordAlert{}},
+	"alert.setAlertEnabled":                 {Summary: "Pause or resume a keyword alert"},
+	"alert.deleteAlert":                     {Summary: "Delete a keyword alert", Status: http.StatusNoContent},
+	"alert.listMatches":                     {Summary: "Articles tagged by keyword alerts, newest first"},
+	"alert.triageMatch":                     {Summary: "Mark a tagged article as handled"},
+	"export.exportArticles": {
+		Summary: "Articles with summaries and engagement counts, streamed for offline analysis",
+		Params: params([]apischema.Param{
+			{Name: "format", Description: "csv (the default) or ndjson"},
+		}, dateParams),
+		Response:    "",
+		ContentType: "text/csv",
+	},
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"news-backend/models"
+	"news-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PlaceHandler struct {
+	placeService *services.PlaceService
+}
+
+// NewPlaceHandler creates a new place handler
+func NewPlaceHandler(placeService *services.PlaceService) *PlaceHandler {
+	return &PlaceHandler{
+		placeService: placeService,
+	}
+}
+
+// GetPlaces lists a user's saved places
+// GET /api/v1/users/:id/places
+func (h *PlaceHandler) GetPlaces(c *gin.Context) {
+	places, err := h.placeService.List(c.Param("id"))
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id": c.Param("id"),
+		"places":  places,
+	})
+}
+
+// SavePlace stores or moves a named place for near=<name> requests
+// PUT /api/v1/users/:id/places/:name
+// Body: {"lat": 17.385, "lon": 78.4867}
+func (h *PlaceHandler) SavePlace(c *gin.Context) {
+	var req models.SavePlaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	place, err := h.placeService.Save(c.Param("id"), c.Param("name"), *req.Lat, *req.Lon)
+	if errors.Is(err, services.ErrInvalidPlace) {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	if errors.Is(err, services.ErrPlacesNotEnabled) {
+		respondWithError(c, http.StatusForbidden, "Forbidden", err.Error())
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, place)
+}
+
+// DeletePlace removes a saved place
+// DELETE /api/v1/users/:id/places/:name
+func (h *PlaceHandler) DeletePlace(c *gin.Context) {
+	err := h.placeService.Delete(c.Param("id"), c.Param("name"))
+	if errors.Is(err, services.ErrPlaceNotFound) {
+		respondNotFound(c, "Place not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Place deleted",
+	})
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"news-backend/middleware"
+	"news-backend/models"
+	newsv1 "news-backend/proto"
+	"news-backend/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+)
+
+// =============================================================================
+// Content Negotiation
+// =============================================================================
+
+// articleListFormats are the encodings offered for article list responses,
+// in preference order when the client accepts several
+var articleListFormats = []string{
+	binding.MIMEJSON,
+	binding.MIMEMSGPACK,
+	binding.MIMEMSGPACK2,
+	binding.MIMEPROTOBUF,
+}
+
+// respondArticles renders an article list in the format requested by the
+// Accept header, and says so with Vary. JSON and MessagePack encode body
+// as-is; protobuf encodes the articles and metadata as an ArticleList (see
+// proto/news.proto). Unknown or missing Accept headers get JSON. Demo tier
+// responses are marked in every format.
+func respondArticles(c *gin.Context, body interface{}, articles []models.ArticleResponse, metadata *models.ResponseMetadata) {
+	c.Set(middleware.ResultCountKey, len(articles))
+	watermarkDemo(c, body)
+	if metadata != nil {
+		metadata.Clamped = services.ClampedParams(c.Request.Context())
+	}
+	middleware.AddVary(c.Writer.Header(), "Accept")
+	switch c.NegotiateFormat(articleListFormats...) {
+	case binding.MIMEMSGPACK, binding.MIMEMSGPACK2:
+		c.Render(http.StatusOK, render.MsgPack{Data: body})
+	case binding.MIMEPROTOBUF:
+		c.Data(http.StatusOK, binding.MIMEPROTOBUF, newsv1.EncodeArticleList(articles, metadata, services.IsDemoTier(c.Request.Context())))
+	default:
+		c.JSON(http.StatusOK, body)
+	}
+}
+
+// watermarkDemo adds "demo": true to a gin.H body served to the demo tier,
+// before it is encoded as JSON or MessagePack. Response structs carry their
+// own Demo field.
+func watermarkDemo(c *gin.Context, body interface{}) {
+	if h, ok := body.(gin.H); ok && services.IsDemoTier(c.Request.Context()) {
+		h["demo"] = true
+	}
+}
+
+// =============================================================================
+// Edge Cache Tagging
+// =============================================================================
+
+// addArticleSurrogateKeys tags an edge-cacheable response with the articles
+// it contains, so purging one article reaches every listing that includes it.
+// Responses without a Surrogate-Key (not publicly cached) are left alone.
+func addArticleSurrogateKeys(c *gin.Context, articles []models.Article) {
+	header := c.Writer.Header()
+	keys := header.Get("Surrogate-Key")
+	if keys == "" || len(articles) == 0 {
+		return
+	}
+
+	tagged := make([]string, 0, len(articles)+1)
+	tagged = append(tagged, keys)
+	for i := range articles {
+		tagged = append(tagged, services.ArticleSurrogateKey(articles[i].ID))
+	}
+	header.Set("Surrogate-Key", strings.Join(tagged, " "))
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"news-backend/models"
+	"news-backend/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestRespondArticlesMarksDemoInEveryFormat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/news", func(c *gin.Context) {
+		if c.Query("demo") == "true" {
+			c.Request = c.Request.WithContext(services.WithDemoTier(c.Request.Context(), 5))
+		}
+		articles := []models.ArticleResponse{{Title: "Title", URL: "https://example.com/story"}}
+		metadata := models.NewResponseMetadata(len(articles), len(articles), "", nil)
+		respondArticles(c, gin.H{"articles": articles, "metadata": metadata}, articles, metadata)
+	})
+
+	tests := []struct {
+		accept string
+		demo   func(t *testing.T, body []byte) bool
+	}{
+		{binding.MIMEJSON, jsonDemo},
+		{binding.MIMEMSGPACK, func(t *testing.T, body []byte) bool {
+			// "demo" as a fixstr key followed by true
+			return bytes.Contains(body, []byte("\xa4demo\xc3"))
+		}},
+		{binding.MIMEPROTOBUF, protobufDemo},
+	}
+	for _, tt := range tests {
+		for _, demo := range []bool{true, false} {
+			target := "/news"
+			if demo {
+				target += "?demo=true"
+			}
+			req := httptest.NewRequest(http.MethodGet, target, nil)
+			req.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Accept %s: status = %d, expected 200", tt.accept, w.Code)
+			}
+			if got := tt.demo(t, w.Body.Bytes()); got != demo {
+				t.Errorf("Accept %s, demo tier %v: marked demo = %v", tt.accept, demo, got)
+			}
+		}
+	}
+}
+
+func jsonDemo(t *testing.T, body []byte) bool {
+	t.Helper()
+	var response struct {
+		Demo bool `json:"demo"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		t.Fatalf("failed to decode JSON response: %v", err)
+	}
+	return response.Demo
+}
+
+// protobufDemo reports whether an ArticleList sets demo (field 3)
+func protobufDemo(t *testing.T, body []byte) bool {
+	t.Helper()
+	demo := false
+	for len(body) > 0 {
+		num, typ, n := protowire.ConsumeTag(body)
+		if n < 0 {
+			t.Fatalf("malformed ArticleList: %v", protowire.ParseError(n))
+		}
+		body = body[n:]
+		if num == 3 && typ == protowire.VarintType {
+			v, m := protowire.ConsumeVarint(body)
+			if m < 0 {
+				t.Fatalf("malformed ArticleList demo: %v", protowire.ParseError(m))
+			}
+			demo = v == 1
+		}
+		n = protowire.ConsumeFieldValue(num, typ, body)
+		if n < 0 {
+			t.Fatalf("malformed ArticleList field %d: %v", num, protowire.ParseError(n))
+		}
+		body = body[n:]
+	}
+	return demo
+}
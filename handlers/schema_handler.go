@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"news-backend/apischema"
+	"news-backend/eventschema"
+	"news-backend/models"
+	"news-backend/services"
+	"news-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Where the v1 API descriptions are served
+const (
+	schemaPath  = "/api/v1/schema"
+	openAPIPath = "/api/v1/openapi.json"
+)
+
+// SchemaHandler serves the machine-readable API descriptions that client
+// SDKs are generated from, and Swagger UI to browse them
+type SchemaHandler struct {
+	router       *gin.Engine
+	swaggerUIURL string
+	once         sync.Once
+	doc          *apischema.Document
+	spec         []byte // OpenAPI document, marshaled
+	specETag     string
+}
+
+// NewSchemaHandler creates a new schema handler describing router's routes;
+// the docs page loads Swagger UI from swaggerUIURL
+func NewSchemaHandler(router *gin.Engine, swaggerUIURL string) *SchemaHandler {
+	return &SchemaHandler{router: router, swaggerUIURL: strings.TrimSuffix(swaggerUIURL, "/")}
+}
+
+// document builds the descriptions on first use, once every route is registered
+func (h *SchemaHandler) document() *apischema.Document {
+	h.once.Do(func() {
+		h.doc = apischema.Build(apischema.Options{
+			APIVersion:   "v1",
+			Routes:       h.router.Routes(),
+			AdminPrefix:  "/api/v1/admin",
+			ContentTypes: articleListFormats,
+			Enums: map[string][]string{
+				"intents": {models.IntentCategory, models.IntentSource, models.IntentSearch,
+					models.IntentNearby, models.IntentScore, models.IntentDiscovery},
+				"event_types": {models.EventTypeView, models.EventTypeClick, models.EventTypeShare},
+				"event_platforms": {eventschema.PlatformIOS, eventschema.PlatformAndroid, eventschema.PlatformWeb,
+					eventschema.PlatformOther, eventschema.PlatformUnknown},
+				"event_buckets":    {services.EventBucketHour, services.EventBucketDay},
+				"ranking_profiles": services.RankingProfiles(),
+				"search_modes":     {services.SearchModeKeyword, services.SearchModeHybrid},
+				"search_fields": {services.SearchFieldTitle, services.SearchFieldDescription,
+					services.SearchFieldSummary, services.SearchFieldTopics, services.SearchFieldContent},
+				"sentiments": {utils.SentimentPositive, utils.SentimentNegative, utils.SentimentNeutral},
+				"tones":      {utils.ToneFactual, utils.ToneAnalytical, utils.ToneOpinion, utils.ToneUrgent},
+				"summary_tones": {models.SummaryToneNeutral, models.SummaryToneSimple,
+					models.SummaryToneDetailed, models.SummaryToneKidFriendly},
+				"mute_kinds": {models.MuteKindSource, models.MuteKindCategory, models.MuteKindKeyword},
+			},
+			Types: []interface{}{
+				models.ArticleResponse{},
+				models.TrendingArticleResponse{},
+				models.ResponseMetadata{},
+				models.Facets{},
+				models.IntentResponse{},
+				models.NewsQueryRequest{},
+				models.NewsQueryResponse{},
+				models.TrendingRequest{},
+				models.TrendingResponse{},
+				models.DigestRequest{},
+				models.FeedRequest{},
+				models.FeedResponse{},
+				models.EventStatsRequest{},
+				models.ErrorResponse{},
+				models.CategoryCount{},
+				models.Edition{},
+				models.TrendingTopic{},
+				models.UserPreference{},
+				models.UserMute{},
+				models.SavedPlace{},
+				models.UserLink{},
+			},
+		})
+
+		if unknown := apischema.UnknownOperations(h.doc, endpoints); len(unknown) > 0 {
+			log.Printf("OpenAPI spec documents operations without a route: %s", strings.Join(unknown, ", "))
+		}
+		spec := apischema.OpenAPI(h.doc, apischema.OpenAPIOptions{
+			Title:          "Contextual News Data Retrieval System",
+			Description:    "News retrieval, trending and personalization API. Article lists are also offered as MessagePack and Protocol Buffers.",
+			APIKeyPrefixes: apiKeyPrefixes,
+			Endpoints:      endpoints,
+			Error:          models.ErrorResponse{},
+		})
+		h.spec, _ = json.Marshal(spec)
+		sum := sha256.Sum256(h.spec)
+		h.specETag = `"` + hex.EncodeToString(sum[:]) + `"`
+	})
+	return h.doc
+}
+
+// GetSchema describes the v1 API: routes, enumerations and JSON types.
+// Clients revalidate with the ETag, the document's checksum, to find out
+// when it changed.
+// GET /api/v1/schema
+func (h *SchemaHandler) GetSchema(c *gin.Context) {
+	doc := h.document()
+	etag := `"` + doc.Checksum + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "no-cache")
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.JSON(http.StatusOK, doc)
+}
+
+// GetWellKnown lists the API versions and where their descriptions are,
+// for generators that only know the host
+// GET /.well-known/api-schema
+func (h *SchemaHandler) GetWellKnown(c *gin.Context) {
+	doc := h.document()
+	c.Header("Cache-Control", "no-cache")
+	c.JSON(http.StatusOK, gin.H{
+		"versions": []gin.H{{
+			"api_version":    doc.APIVersion,
+			"schema_version": doc.SchemaVersion,
+			"url":            schemaPath,
+			"openapi_url":    openAPIPath,
+			"checksum":       doc.Checksum,
+		}},
+	})
+}
+
+// GetOpenAPI serves the v1 API as an OpenAPI 3.0 document, generated from
+// the routes and the request and response types
+// GET /api/v1/openapi.json
+func (h *SchemaHandler) GetOpenAPI(c *gin.Context) {
+	h.document()
+	c.Header("ETag", h.specETag)
+	c.Header("Cache-Control", "no-cache")
+	if c.GetHeader("If-None-Match") == h.specETag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", h.spec)
+}
+
+// swaggerUIScript starts Swagger UI on the OpenAPI document; the docs
+// page's Content-Security-Policy allows it by hash
+const swaggerUIScript = `SwaggerUIBundle({url: "` + openAPIPath + `", dom_id: "#swagger-ui"});`
+
+// GetDocs serves Swagger UI for browsing and trying the OpenAPI document
+// GET /docs
+func (h *SchemaHandler) GetDocs(c *gin.Context) {
+	assets := html.EscapeString(h.swaggerUIURL)
+	page := `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>News API</title>
+<link rel="stylesheet" href="` + assets + `/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="` + assets + `/swagger-ui-bundle.js"></script>
+<script>` + swaggerUIScript + `</script>
+</body>
+</html>
+`
+	// The API's default policy blocks every script and style
+	sum := sha256.Sum256([]byte(swaggerUIScript))
+	origin := assetOrigin(h.swaggerUIURL)
+	c.Header("Content-Security-Policy", fmt.Sprintf("default-src 'none'; script-src %s 'sha256-%s'; "+
+		"style-src %s 'unsafe-inline'; img-src %s data:; connect-src 'self'; frame-ancestors 'none'",
+		origin, base64.StdEncoding.EncodeToString(sum[:]), origin, origin))
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page))
+}
+
+// assetOrigin is the CSP source of the Swagger UI assets: their origin, or
+// 'self' when they're served by this host
+func assetOrigin(assets string) string {
+	u, err := url.Parse(assets)
+	if err != nil || u.Host == "" {
+		return "'self'"
+	}
+	return u.Scheme + "://" + u.Host
+}
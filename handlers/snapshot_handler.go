@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"news-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SnapshotHandler struct {
+	snapshotService *services.SnapshotService
+}
+
+// NewSnapshotHandler creates a new snapshot handler
+func NewSnapshotHandler(snapshotService *services.SnapshotService) *SnapshotHandler {
+	return &SnapshotHandler{
+		snapshotService: snapshotService,
+	}
+}
+
+// CreateSnapshot exports a consistent snapshot of articles, events and
+// engagement aggregates to SNAPSHOT_STORE_URL and returns its manifest
+// POST /api/v1/admin/snapshots
+func (h *SnapshotHandler) CreateSnapshot(c *gin.Context) {
+	manifest, err := h.snapshotService.Create(c.Request.Context())
+	if errors.Is(err, services.ErrSnapshotRunning) {
+		respondWithError(c, http.StatusConflict, "Conflict", err.Error())
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusCreated, manifest)
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"news-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SourceHandler struct {
+	sourceService *services.SourceService
+}
+
+// NewSourceHandler creates a new source handler
+func NewSourceHandler(sourceService *services.SourceService) *SourceHandler {
+	return &SourceHandler{
+		sourceService: sourceService,
+	}
+}
+
+// ListSources returns every source's reliability rating
+// GET /api/v1/admin/sources
+func (h *SourceHandler) ListSources(c *gin.Context) {
+	sources, err := h.sourceService.List()
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sources": sources,
+		"count":   len(sources),
+	})
+}
+
+// SetSourceReliability rates a source; a null score clears the rating
+// PUT /api/v1/admin/sources/:name
+// Body: {"reliability_score": 0.8}
+func (h *SourceHandler) SetSourceReliability(c *gin.Context) {
+	var req struct {
+		ReliabilityScore *float64 `json:"reliability_score"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	source, err := h.sourceService.SetReliability(c.Param("name"), req.ReliabilityScore)
+	if errors.Is(err, services.ErrInvalidReliability) {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, source)
+}
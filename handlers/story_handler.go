@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"news-backend/models"
+	"news-backend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type StoryHandler struct {
+	storyService *services.StoryService
+	newsService  *services.NewsService
+}
+
+// NewStoryHandler creates a new story handler
+func NewStoryHandler(storyService *services.StoryService, newsService *services.NewsService) *StoryHandler {
+	return &StoryHandler{
+		storyService: storyService,
+		newsService:  newsService,
+	}
+}
+
+// GetStory returns a cluster of near-duplicate articles with one summary
+// GET /api/v1/stories/:id
+func (h *StoryHandler) GetStory(c *gin.Context) {
+	story, err := h.storyService.GetStory(c.Param("id"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		respondNotFound(c, "Story not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	// Only the representative is summarized; the rest cover the same event
+	representative := h.newsService.EnrichWithSummaries(c.Request.Context(), []models.Article{story.Representative})[0]
+	articles := story.Articles[:services.TierLimit(c.Request.Context(), len(story.Articles))]
+	addArticleSurrogateKeys(c, articles)
+
+	body := gin.H{
+		"story_id":       story.ID,
+		"representative": articleToResponse(c, &representative),
+		"articles":       articlesToResponses(c, articles),
+		"count":          len(articles),
+	}
+	watermarkDemo(c, body)
+	c.JSON(http.StatusOK, body)
+}
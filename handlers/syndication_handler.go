@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"news-backend/middleware"
+	"news-backend/models"
+	"news-backend/services"
+	"news-backend/syndication"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Orders a feed can list articles in
+const (
+	feedSortLatest = "latest"
+	feedSortTop    = "top"
+)
+
+type SyndicationHandler struct {
+	newsService *services.NewsService
+	publicURL   string
+}
+
+// NewSyndicationHandler creates a new RSS and Atom feed handler. Links in
+// the feeds start with publicURL, or the request's host when it is empty.
+func NewSyndicationHandler(newsService *services.NewsService, publicURL string) *SyndicationHandler {
+	return &SyndicationHandler{
+		newsService: newsService,
+		publicURL:   publicURL,
+	}
+}
+
+// GetRSS returns the latest or top articles as an RSS 2.0 feed
+// GET /api/v1/feeds/rss?category=technology&sort=latest
+func (h *SyndicationHandler) GetRSS(c *gin.Context) {
+	h.respondFeed(c, syndication.ContentTypeRSS, syndication.RSS)
+}
+
+// GetAtom returns the latest or top articles as an Atom 1.0 feed
+// GET /api/v1/feeds/atom?category=technology&sort=latest
+func (h *SyndicationHandler) GetAtom(c *gin.Context) {
+	h.respondFeed(c, syndication.ContentTypeAtom, syndication.Atom)
+}
+
+// respondFeed loads the feed's articles and writes them with render
+func (h *SyndicationHandler) respondFeed(c *gin.Context, contentType string, render func(syndication.Feed) ([]byte, error)) {
+	sort := c.DefaultQuery("sort", feedSortLatest)
+	if sort != feedSortLatest && sort != feedSortTop {
+		respondBadRequest(c, "sort must be latest or top")
+		return
+	}
+	limit, err := parseLimit(c)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	category, source := c.Query("category"), c.Query("source")
+
+	var articles []models.Article
+	if sort == feedSortTop {
+		result, err := h.newsService.FetchArticlesWithMetadata(c.Request.Context(), services.FetchParams{
+			Intent:   models.IntentScore,
+			Category: category,
+			Source:   source,
+			Paging:   services.Paging{Limit: limit},
+		})
+		if err != nil {
+			respondInternalError(c, err.Error())
+			return
+		}
+		articles = h.newsService.EnrichWithSummaries(c.Request.Context(), result.Articles)
+	} else {
+		page, err := h.newsService.Latest(c.Request.Context(), services.LatestParams{
+			Category: category,
+			Source:   source,
+			Limit:    limit,
+		})
+		if err != nil {
+			respondInternalError(c, err.Error())
+			return
+		}
+		articles = page.Articles
+	}
+
+	base := h.baseURL(c)
+	feed := syndication.Feed{
+		Title:       feedTitle(sort, category, source),
+		Description: feedDescription(sort, category, source),
+		Link:        base + "/",
+		SelfURL:     base + c.Request.URL.Path + feedQuery(c.Request.URL.Query()),
+		Items:       make([]syndication.Item, len(articles)),
+	}
+	feed.ID = feed.SelfURL
+	for i := range articles {
+		resp := articleToResponse(c, &articles[i])
+		item := syndication.Item{
+			ID:        base + "/api/v1/articles/" + url.PathEscape(articles[i].ID),
+			Title:     resp.Title,
+			Link:      resp.URL,
+			Summary:   resp.LLMSummary,
+			Author:    resp.SourceName,
+			Published: resp.PublicationDate,
+		}
+		// Without the article's URL, link to its detail endpoint
+		if item.Link == "" {
+			item.Link = item.ID
+		}
+		if item.Summary == "" {
+			item.Summary = resp.Description
+		}
+		if resp.Category != "" {
+			item.Categories = strings.Split(resp.Category, ",")
+		}
+		feed.Items[i] = item
+	}
+
+	body, err := render(feed)
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+	c.Set(middleware.ResultCountKey, len(articles))
+	addArticleSurrogateKeys(c, articles)
+	c.Data(http.StatusOK, contentType+"; charset=utf-8", body)
+}
+
+// baseURL returns the URL the API is reached at, without a trailing slash
+func (h *SyndicationHandler) baseURL(c *gin.Context) string {
+	if h.publicURL != "" {
+		return h.publicURL
+	}
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
+
+// feedQuery returns the query string that selects a feed, leaving out the
+// API key so it isn't published in the feed
+func feedQuery(query url.Values) string {
+	query.Del("api_key")
+	if len(query) == 0 {
+		return ""
+	}
+	return "?" + query.Encode()
+}
+
+func feedTitle(sort, category, source string) string {
+	title := "Latest news"
+	if sort == feedSortTop {
+		title = "Top news"
+	}
+	if category != "" {
+		title += ": " + category
+	}
+	if source != "" {
+		title += " from " + source
+	}
+	return title
+}
+
+func feedDescription(sort, category, source string) string {
+	description := "The newest articles"
+	if sort == feedSortTop {
+		description = "The most relevant articles"
+	}
+	if category != "" {
+		description += " in " + category
+	}
+	if source != "" {
+		description += " from " + source
+	}
+	return description
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"news-backend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxTopicWindowHours bounds the trending topics window to a week
+const maxTopicWindowHours = 168
+
+type TopicHandler struct {
+	topicService *services.TopicService
+}
+
+// NewTopicHandler creates a new topic handler
+func NewTopicHandler(topicService *services.TopicService) *TopicHandler {
+	return &TopicHandler{
+		topicService: topicService,
+	}
+}
+
+// GetTrending ranks topics by recent articles and engagement
+// GET /api/v1/topics/trending?hours=24&limit=20
+func (h *TopicHandler) GetTrending(c *gin.Context) {
+	hours, err := strconv.Atoi(c.DefaultQuery("hours", "24"))
+	if err != nil || hours <= 0 || hours > maxTopicWindowHours {
+		respondBadRequest(c, "hours must be between 1 and 168")
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		respondBadRequest(c, "limit must be a positive integer")
+		return
+	}
+
+	topics, err := h.topicService.Trending(hours, limit)
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"topics":       topics,
+		"window_hours": hours,
+	})
+}
+
+// GetFollowed lists the topics a user follows
+// GET /api/v1/users/:id/topics
+func (h *TopicHandler) GetFollowed(c *gin.Context) {
+	follows, err := h.topicService.Followed(c.Param("id"))
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id": c.Param("id"),
+		"topics":  follows,
+	})
+}
+
+// Follow makes a user follow a topic, boosting it in their personalized feed
+// POST /api/v1/users/:id/topics/:topic/follow
+func (h *TopicHandler) Follow(c *gin.Context) {
+	follow, err := h.topicService.Follow(c.Param("id"), c.Param("topic"))
+	if errors.Is(err, services.ErrInvalidTopic) {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, follow)
+}
+
+// Unfollow stops a user following a topic
+// DELETE /api/v1/users/:id/topics/:topic/follow
+func (h *TopicHandler) Unfollow(c *gin.Context) {
+	err := h.topicService.Unfollow(c.Param("id"), c.Param("topic"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		respondNotFound(c, "Topic not followed")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Topic unfollowed",
+	})
+}
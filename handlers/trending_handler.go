@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
+	"news-backend/eventschema"
 	"news-backend/models"
 	"news-backend/services"
 
@@ -23,7 +26,7 @@ func NewTrendingHandler(trendingService *services.TrendingService) *TrendingHand
 }
 
 // GetTrending retrieves trending news for a location
-// GET /api/v1/trending?lat=37.4220&lon=-122.0840&radius=50&limit=5
+// GET /api/v1/trending?lat=37.4220&lon=-122.0840&radius=50&limit=5&include_summaries=false
 func (h *TrendingHandler) GetTrending(c *gin.Context) {
 	var req models.TrendingRequest
 
@@ -32,12 +35,26 @@ func (h *TrendingHandler) GetTrending(c *gin.Context) {
 		return
 	}
 
+	var err error
+	if req.Radius, err = services.LimitRadius(c.Request.Context(), req.Radius); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	if req.Limit, err = services.LimitArticles(c.Request.Context(), req.Limit); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	includeSummaries := req.IncludeSummaries == nil || *req.IncludeSummaries
+
 	// Get trending articles with summaries
-	trendingArticles, cache, err := h.trendingService.GetTrendingNewsWithSummaries(
+	trendingArticles, cache, summaries, err := h.trendingService.GetTrendingNewsWithSummaries(
+		c.Request.Context(),
 		req.Latitude,
 		req.Longitude,
 		req.Radius,
 		req.Limit,
+		includeSummaries,
 	)
 
 	if err != nil {
@@ -45,13 +62,30 @@ func (h *TrendingHandler) GetTrending(c *gin.Context) {
 		return
 	}
 
+	articles := make([]models.Article, len(trendingArticles))
+	trendingScores := make(map[string]float64, len(trendingArticles))
+	for i := range trendingArticles {
+		articles[i] = trendingArticles[i].Article
+		trendingScores[trendingArticles[i].ID] = trendingArticles[i].TrendingScore
+	}
+	var layouts []string
+	if req.Layout {
+		layouts = layoutHints(articles, trendingScores)
+	}
+
 	// Convert to response format
-	articleResponses := make([]models.ArticleResponse, len(trendingArticles))
-	for i, article := range trendingArticles {
-		resp := article.Article.ToResponse()
-		// Note: TrendingScore and EventCount are not in ArticleResponse
-		// If needed, extend ArticleResponse or create TrendingArticleResponse
+	articleResponses := make([]models.TrendingArticleResponse, len(trendingArticles))
+	plainResponses := make([]models.ArticleResponse, len(trendingArticles))
+	for i := range trendingArticles {
+		resp := trendingToResponse(c, &trendingArticles[i])
+		if !includeSummaries {
+			resp.LLMSummary = ""
+		}
+		if layouts != nil {
+			resp.Layout = layouts[i]
+		}
 		articleResponses[i] = resp
+		plainResponses[i] = resp.ArticleResponse
 	}
 
 	response := models.TrendingResponse{
@@ -68,43 +102,50 @@ func (h *TrendingHandler) GetTrending(c *gin.Context) {
 		),
 		Location: cache.Location,
 		RadiusKm: cache.RadiusKm,
+		Demo:     services.IsDemoTier(c.Request.Context()),
+	}
+	response.Metadata.Summaries = summaries
+	// A saved place's coordinates aren't echoed
+	if name := services.NearPlace(c.Request.Context()); name != "" {
+		delete(response.Metadata.Filters, "lat")
+		delete(response.Metadata.Filters, "lon")
+		response.Metadata.Filters["near"] = name
 	}
 
 	if cache != nil {
 		response.CachedAt = cache.CachedAt.Format("2006-01-02T15:04:05Z07:00")
 	}
 
-	c.JSON(http.StatusOK, response)
+	addArticleSurrogateKeys(c, articles)
+	respondArticles(c, response, plainResponses, response.Metadata)
 }
 
-// RecordEvent records a user interaction event
+// maxEventBytes bounds an event payload, which is a few hundred bytes
+const maxEventBytes = 16 << 10
+
+// RecordEvent records a user interaction event. Payloads of every event
+// schema version are accepted; those without schema_version are version 1.
 // POST /api/v1/trending/event
-// Body: {"article_id": "...", "user_id": "...", "event_type": "view", "lat": 37.4220, "lon": -122.0840}
+// Body: {"schema_version": 2, "article_id": "...", "user_id": "...", "event_type": "view", "lat": 37.4220, "lon": -122.0840, "dwell_ms": 5400, "platform": "ios"}
 func (h *TrendingHandler) RecordEvent(c *gin.Context) {
-	var req struct {
-		ArticleID string  `json:"article_id" binding:"required"`
-		UserID    string  `json:"user_id" binding:"required"`
-		EventType string  `json:"event_type" binding:"required"`
-		Lat       float64 `json:"lat" binding:"required"`
-		Lon       float64 `json:"lon" binding:"required"`
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxEventBytes))
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
 	}
-
-	if err := c.ShouldBindJSON(&req); err != nil {
+	event, err := eventschema.Decode(body)
+	if err != nil {
 		respondBadRequest(c, err.Error())
 		return
 	}
 
-	// Normalize event type
-	eventType := strings.ToLower(req.EventType)
-
-	err := h.trendingService.RecordUserEvent(
-		req.ArticleID,
-		req.UserID,
-		eventType,
-		req.Lat,
-		req.Lon,
-	)
+	err = h.trendingService.RecordUserEvent(event)
 
+	if errors.Is(err, services.ErrEventQueueFull) {
+		c.Header("Retry-After", "1")
+		respondWithError(c, http.StatusTooManyRequests, "Event queue full", err.Error())
+		return
+	}
 	if err != nil {
 		respondBadRequest(c, err.Error())
 		return
@@ -117,9 +158,64 @@ func (h *TrendingHandler) RecordEvent(c *gin.Context) {
 }
 
 // GetEventStats returns statistics about user events
-// GET /api/v1/trending/stats
+// GET /api/v1/trending/stats?from=2025-01-01&to=2025-01-08&bucket=day&event_type=view&article_id=...&lat=..&lon=..&radius=..
 func (h *TrendingHandler) GetEventStats(c *gin.Context) {
-	stats, err := h.trendingService.GetEventStats()
+	var req models.EventStatsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	filter := services.EventStatsFilter{
+		Bucket:    strings.ToLower(req.Bucket),
+		ArticleID: req.ArticleID,
+		EventType: strings.ToLower(req.EventType),
+	}
+
+	var err error
+	if filter.From, err = parseTimeParam(req.From); err != nil {
+		respondBadRequest(c, "Invalid 'from': "+err.Error())
+		return
+	}
+	if filter.To, err = parseTimeParam(req.To); err != nil {
+		respondBadRequest(c, "Invalid 'to': "+err.Error())
+		return
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		respondBadRequest(c, "'from' must be before 'to'")
+		return
+	}
+	if filter.From, err = services.LimitWindow(c.Request.Context(), filter.From, filter.To); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	if filter.Bucket != "" && !services.IsValidEventBucket(filter.Bucket) {
+		respondBadRequest(c, "Invalid bucket. Must be 'hour' or 'day'")
+		return
+	}
+	if filter.EventType != "" && !models.IsValidEventType(filter.EventType) {
+		respondBadRequest(c, "Invalid event_type. Must be 'view', 'click' or 'share'")
+		return
+	}
+
+	// Region filter needs both coordinates
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		respondBadRequest(c, "Both 'lat' and 'lon' are required for a region filter")
+		return
+	}
+	if req.Latitude != nil {
+		if req.Radius, err = services.LimitRadius(c.Request.Context(), req.Radius); err != nil {
+			respondBadRequest(c, err.Error())
+			return
+		}
+		filter.Region = &services.EventRegion{
+			Lat:      *req.Latitude,
+			Lon:      *req.Longitude,
+			RadiusKm: req.Radius,
+		}
+	}
+
+	stats, err := h.trendingService.GetEventStats(filter)
 	if err != nil {
 		respondInternalError(c, err.Error())
 		return
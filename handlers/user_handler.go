@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"news-backend/models"
+	"news-backend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type UserHandler struct {
+	userService *services.UserService
+}
+
+// NewUserHandler creates a new user handler
+func NewUserHandler(userService *services.UserService) *UserHandler {
+	return &UserHandler{
+		userService: userService,
+	}
+}
+
+// GetLinks lists identifiers linked to a user
+// GET /api/v1/users/:id/links
+func (h *UserHandler) GetLinks(c *gin.Context) {
+	canonicalID, links, err := h.userService.GetLinks(c.Param("id"))
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"canonical_user_id": canonicalID,
+		"links":             links,
+	})
+}
+
+// RegisterDevice issues the identifier its device secret, once
+// POST /api/v1/users/:id/device
+func (h *UserHandler) RegisterDevice(c *gin.Context) {
+	device, err := h.userService.RegisterDevice(c.Param("id"))
+	if errors.Is(err, services.ErrDeviceRegistered) {
+		respondWithError(c, http.StatusConflict, "Conflict", err.Error())
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, device)
+}
+
+// IssueLinkToken issues a single-use token the identifier hands to the
+// device it wants to be linked to
+// POST /api/v1/users/:id/link-tokens
+// Body: {"device_secret": "<secret returned when :id registered>"}
+func (h *UserHandler) IssueLinkToken(c *gin.Context) {
+	var req models.LinkTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	token, err := h.userService.IssueLinkToken(c.Param("id"), req.DeviceSecret)
+	if errors.Is(err, services.ErrInvalidDeviceSecret) {
+		respondWithError(c, http.StatusForbidden, "Forbidden", err.Error())
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, token)
+}
+
+// LinkUser links another device/user identifier to this user
+// POST /api/v1/users/:id/links
+// Body: {"user_id": "web-session-123", "token": "<link token issued to web-session-123>"}
+func (h *UserHandler) LinkUser(c *gin.Context) {
+	var req models.LinkUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	link, err := h.userService.LinkUsers(c.Param("id"), req.UserID, req.Token, models.LinkMethodExplicit)
+	if errors.Is(err, services.ErrInvalidLink) {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	if errors.Is(err, services.ErrInvalidLinkToken) {
+		respondWithError(c, http.StatusForbidden, "Forbidden", err.Error())
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, link)
+}
+
+// UnlinkUser removes a linked identifier
+// DELETE /api/v1/users/:id/links/:linked_id
+func (h *UserHandler) UnlinkUser(c *gin.Context) {
+	err := h.userService.UnlinkUser(c.Param("id"), c.Param("linked_id"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		respondNotFound(c, "Link not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "User unlinked",
+	})
+}
+
+// GetLinkSuggestions scores identifiers that likely belong to the same person
+// GET /api/v1/users/:id/links/suggestions
+func (h *UserHandler) GetLinkSuggestions(c *gin.Context) {
+	suggestions, err := h.userService.SuggestLinks(c.Param("id"))
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id":     c.Param("id"),
+		"suggestions": suggestions,
+	})
+}
+
+// GetPreferences returns a user's response preferences
+// GET /api/v1/users/:id/preferences
+func (h *UserHandler) GetPreferences(c *gin.Context) {
+	prefs, err := h.userService.GetPreferences(c.Param("id"))
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// SetPreferences stores a user's response preferences
+// PUT /api/v1/users/:id/preferences
+// Body: {"summary_tone": "simple", "save_places": true}
+func (h *UserHandler) SetPreferences(c *gin.Context) {
+	var req models.PreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	if req.SummaryTone == nil && req.SavePlaces == nil {
+		respondBadRequest(c, "summary_tone or save_places is required")
+		return
+	}
+
+	prefs, err := h.userService.SetPreferences(c.Param("id"), req.SummaryTone, req.SavePlaces)
+	if errors.Is(err, services.ErrInvalidPreference) {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// RecordFeedSignal adjusts a user's personalized ranking with a "more like
+// this" or "less like this" reaction to an article
+// POST /api/v1/users/:id/signals
+// Body: {"article_id": "...", "signal": "less_like_this"}
+func (h *UserHandler) RecordFeedSignal(c *gin.Context) {
+	var req models.FeedSignalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	signal, err := h.userService.RecordFeedSignal(c.Param("id"), req.ArticleID, req.Signal)
+	if errors.Is(err, services.ErrInvalidSignal) {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		respondNotFound(c, "Article not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, signal)
+}
+
+// GetFeedAdjustments returns the category and source adjustments a user's
+// feed signals currently apply
+// GET /api/v1/users/:id/signals
+func (h *UserHandler) GetFeedAdjustments(c *gin.Context) {
+	affinity, err := h.userService.Affinity(c.Param("id"))
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id":              c.Param("id"),
+		"category_adjustments": affinity.CategoryAdjustments,
+		"source_adjustments":   affinity.SourceAdjustments,
+	})
+}
+
+// ClearFeedSignals resets a user's feed signals
+// DELETE /api/v1/users/:id/signals
+func (h *UserHandler) ClearFeedSignals(c *gin.Context) {
+	cleared, err := h.userService.ClearFeedSignals(c.Param("id"))
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id": c.Param("id"),
+		"cleared": cleared,
+	})
+}
+
+// GetMutes lists the sources, categories and keywords a user muted
+// GET /api/v1/users/:id/mutes
+func (h *UserHandler) GetMutes(c *gin.Context) {
+	mutes, err := h.userService.ListMutes(c.Param("id"))
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id": c.Param("id"),
+		"mutes":   mutes,
+	})
+}
+
+// AddMute hides a source, category or keyword from a user's news and feed
+// POST /api/v1/users/:id/mutes
+// Body: {"kind": "source", "value": "Example Times"}
+func (h *UserHandler) AddMute(c *gin.Context) {
+	var req models.MuteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	mute, err := h.userService.AddMute(c.Param("id"), req.Kind, req.Value)
+	if errors.Is(err, services.ErrInvalidMute) {
+		respondBadRequest(c, err.Error())
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, mute)
+}
+
+// RemoveMute unmutes a source, category or keyword
+// DELETE /api/v1/users/:id/mutes/:mute_id
+func (h *UserHandler) RemoveMute(c *gin.Context) {
+	muteID, err := strconv.ParseUint(c.Param("mute_id"), 10, 64)
+	if err != nil {
+		respondBadRequest(c, "Invalid mute id")
+		return
+	}
+
+	err = h.userService.RemoveMute(c.Param("id"), uint(muteID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		respondNotFound(c, "Mute not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Mute removed",
+	})
+}
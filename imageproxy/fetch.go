@@ -0,0 +1,109 @@
+// Package imageproxy downloads article images from their publishers and
+// scales them down, so clients can load card thumbnails from the API
+// instead of from every publisher's servers.
+package imageproxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// Errors Fetch reports about the image rather than the connection
+var (
+	ErrNotImage       = errors.New("not a supported image")
+	ErrTooLarge       = errors.New("image too large")
+	ErrPrivateAddress = errors.New("address is not public")
+)
+
+// types are the image formats passed on; SVG is left out as it can carry
+// scripts that would run on the API's origin
+var types = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+	"image/avif": true,
+}
+
+// Image is an encoded image and its media type
+type Image struct {
+	Data        []byte
+	ContentType string
+}
+
+// NewClient creates a client that only connects to public addresses, so
+// URLs taken from articles, such as their images and pages, can't reach
+// services on the host's network
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: publicOnly}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil // the dialer must see the image host's address
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// publicOnly refuses connections to loopback, private, link-local and
+// unspecified addresses; it runs after name resolution, for every address
+// tried, redirects included
+func publicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+	}
+	return nil
+}
+
+// Fetch downloads the image at rawURL, refusing responses that aren't a
+// supported image type or exceed maxBytes
+func Fetch(ctx context.Context, client *http.Client, rawURL string, maxBytes int64) (*Image, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("unsupported URL %q", rawURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "image/*")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || !types[mediaType] {
+		return nil, fmt.Errorf("%w: %q", ErrNotImage, resp.Header.Get("Content-Type"))
+	}
+	if resp.ContentLength > maxBytes {
+		return nil, fmt.Errorf("%w: %d bytes", ErrTooLarge, resp.ContentLength)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("%w: over %d bytes", ErrTooLarge, maxBytes)
+	}
+	return &Image{Data: data, ContentType: mediaType}, nil
+}
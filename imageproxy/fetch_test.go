@@ -0,0 +1,60 @@
+package imageproxy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/lead.jpg":
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Write([]byte("jpeg bytes"))
+		case "/large.jpg":
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Write([]byte(strings.Repeat("x", 100)))
+		case "/logo.svg":
+			w.Header().Set("Content-Type", "image/svg+xml")
+			w.Write([]byte("<svg/>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	img, err := Fetch(ctx, server.Client(), server.URL+"/lead.jpg", 64)
+	if err != nil || string(img.Data) != "jpeg bytes" || img.ContentType != "image/jpeg" {
+		t.Fatalf("Fetch() = %+v, %v, expected the JPEG", img, err)
+	}
+	if _, err := Fetch(ctx, server.Client(), server.URL+"/large.jpg", 64); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Fetch() of a large image error = %v, expected ErrTooLarge", err)
+	}
+	if _, err := Fetch(ctx, server.Client(), server.URL+"/logo.svg", 64); !errors.Is(err, ErrNotImage) {
+		t.Errorf("Fetch() of an SVG error = %v, expected ErrNotImage", err)
+	}
+	if _, err := Fetch(ctx, server.Client(), server.URL+"/missing.jpg", 64); err == nil {
+		t.Error("Fetch() of a missing image succeeded")
+	}
+	if _, err := Fetch(ctx, server.Client(), "file:///etc/passwd", 64); err == nil {
+		t.Error("Fetch() of a file URL succeeded")
+	}
+}
+
+func TestNewClientRefusesPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the client reached a loopback server")
+	}))
+	defer server.Close()
+
+	_, err := Fetch(context.Background(), NewClient(time.Second), server.URL+"/lead.jpg", 64)
+	if !errors.Is(err, ErrPrivateAddress) {
+		t.Errorf("Fetch() error = %v, expected ErrPrivateAddress", err)
+	}
+}
@@ -0,0 +1,106 @@
+package imageproxy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // registers the GIF decoder
+	"image/jpeg"
+	"image/png"
+)
+
+const (
+	// MaxWidth is the widest image Resize produces
+	MaxWidth = 2048
+	// maxPixels bounds the images decoded, as a small file can declare a
+	// huge canvas
+	maxPixels = 40_000_000
+	// jpegQuality is good enough for thumbnails at a fraction of the size
+	jpegQuality = 85
+)
+
+// ErrCannotResize is returned for formats the standard library can't decode
+var ErrCannotResize = errors.New("image format can't be resized")
+
+// Resize scales img down to width pixels wide, keeping its aspect ratio.
+// Images already that narrow are returned as they are. JPEGs stay JPEGs;
+// PNGs and GIFs become PNGs (a GIF's animation is reduced to its first
+// frame).
+func Resize(img *Image, width int) (*Image, error) {
+	if width <= 0 || width > MaxWidth {
+		return nil, fmt.Errorf("width must be between 1 and %d", MaxWidth)
+	}
+	if img.ContentType != "image/jpeg" && img.ContentType != "image/png" && img.ContentType != "image/gif" {
+		return nil, fmt.Errorf("%w: %s", ErrCannotResize, img.ContentType)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(img.Data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotImage, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxPixels {
+		return nil, fmt.Errorf("%w: %dx%d pixels", ErrTooLarge, config.Width, config.Height)
+	}
+	if config.Width <= width {
+		return img, nil
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(img.Data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotImage, err)
+	}
+	height := max(1, (config.Height*width+config.Width/2)/config.Width)
+	dst := scaleDown(src, width, height)
+
+	var buf bytes.Buffer
+	if img.ContentType == "image/jpeg" {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: jpegQuality})
+	} else {
+		err = png.Encode(&buf, dst)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	contentType := img.ContentType
+	if contentType == "image/gif" {
+		contentType = "image/png"
+	}
+	return &Image{Data: buf.Bytes(), ContentType: contentType}, nil
+}
+
+// scaleDown averages the source pixels each destination pixel covers (a
+// box filter), which is sharp enough when shrinking and needs no library
+func scaleDown(src image.Image, width, height int) *image.RGBA {
+	// Premultiplied alpha, so transparent pixels don't darken the average
+	bounds := src.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+	sw, sh := bounds.Dx(), bounds.Dy()
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := y*sh/height, max((y+1)*sh/height, y*sh/height+1)
+		for x := 0; x < width; x++ {
+			x0, x1 := x*sw/width, max((x+1)*sw/width, x*sw/width+1)
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				row := rgba.Pix[sy*rgba.Stride+x0*4 : sy*rgba.Stride+x1*4]
+				for i := 0; i < len(row); i += 4 {
+					r += int(row[i])
+					g += int(row[i+1])
+					b += int(row[i+2])
+					a += int(row[i+3])
+					n++
+				}
+			}
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8((r + n/2) / n)
+			dst.Pix[i+1] = uint8((g + n/2) / n)
+			dst.Pix[i+2] = uint8((b + n/2) / n)
+			dst.Pix[i+3] = uint8((a + n/2) / n)
+		}
+	}
+	return dst
+}
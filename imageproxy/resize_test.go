@@ -0,0 +1,92 @@
+package imageproxy
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"testing"
+)
+
+// encodePNG draws a w×h image whose left half is red and right half blue
+func encodePNG(t *testing.T, w, h int) *Image {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if x < w/2 {
+				img.Set(x, y, color.RGBA{R: 255, A: 255})
+			} else {
+				img.Set(x, y, color.RGBA{B: 255, A: 255})
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return &Image{Data: buf.Bytes(), ContentType: "image/png"}
+}
+
+func TestResize(t *testing.T) {
+	resized, err := Resize(encodePNG(t, 400, 300), 100)
+	if err != nil {
+		t.Fatalf("Resize() error = %v", err)
+	}
+	if resized.ContentType != "image/png" {
+		t.Errorf("Resize() type = %s, expected image/png", resized.ContentType)
+	}
+	img, err := png.Decode(bytes.NewReader(resized.Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := img.Bounds().Size(); got != image.Pt(100, 75) {
+		t.Fatalf("Resize() size = %v, expected 100x75 keeping the aspect ratio", got)
+	}
+	if r, _, b, _ := img.At(10, 10).RGBA(); r>>8 != 255 || b != 0 {
+		t.Errorf("left pixel = %v, expected red", img.At(10, 10))
+	}
+	if r, _, b, _ := img.At(90, 10).RGBA(); r != 0 || b>>8 != 255 {
+		t.Errorf("right pixel = %v, expected blue", img.At(90, 10))
+	}
+}
+
+func TestResizeNarrowImage(t *testing.T) {
+	original := encodePNG(t, 80, 60)
+	resized, err := Resize(original, 100)
+	if err != nil {
+		t.Fatalf("Resize() error = %v", err)
+	}
+	if resized != original {
+		t.Error("Resize() re-encoded an image narrower than the width")
+	}
+}
+
+func TestResizeGIF(t *testing.T) {
+	palette := []color.Color{color.White, color.Black}
+	var buf bytes.Buffer
+	if err := gif.Encode(&buf, image.NewPaletted(image.Rect(0, 0, 64, 64), palette), nil); err != nil {
+		t.Fatal(err)
+	}
+	resized, err := Resize(&Image{Data: buf.Bytes(), ContentType: "image/gif"}, 32)
+	if err != nil {
+		t.Fatalf("Resize() error = %v", err)
+	}
+	if resized.ContentType != "image/png" {
+		t.Errorf("Resize() type = %s, expected GIFs to become PNGs", resized.ContentType)
+	}
+}
+
+func TestResizeErrors(t *testing.T) {
+	if _, err := Resize(&Image{Data: []byte("RIFF"), ContentType: "image/webp"}, 100); !errors.Is(err, ErrCannotResize) {
+		t.Errorf("Resize() of WebP error = %v, expected ErrCannotResize", err)
+	}
+	if _, err := Resize(&Image{Data: []byte("not a png"), ContentType: "image/png"}, 100); !errors.Is(err, ErrNotImage) {
+		t.Errorf("Resize() of garbage error = %v, expected ErrNotImage", err)
+	}
+	if _, err := Resize(encodePNG(t, 10, 10), MaxWidth+1); err == nil {
+		t.Error("Resize() beyond MaxWidth succeeded")
+	}
+}
@@ -0,0 +1,61 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"news-backend/models"
+)
+
+// SourceConnector fetches raw articles from an external source
+// Implementations cover RSS-less feeds: HTML scrapers, proprietary APIs, files, etc.
+type SourceConnector interface {
+	Fetch(ctx context.Context) ([]RawArticle, error)
+}
+
+// ConnectorFactory builds a connector for a configured source
+type ConnectorFactory func(source models.Source) (SourceConnector, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]ConnectorFactory)
+)
+
+// Register makes a connector type available by name
+// Typically called from an init function in the connector's file
+func Register(name string, factory ConnectorFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("ingest: connector %q registered twice", name))
+	}
+	registry[name] = factory
+}
+
+// NewConnector builds the connector named in the source's Connector field
+func NewConnector(source models.Source) (SourceConnector, error) {
+	registryMu.RLock()
+	factory, ok := registry[source.Connector]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown connector %q for source %s", source.Connector, source.Name)
+	}
+	return factory(source)
+}
+
+// Connectors returns the names of all registered connectors
+func Connectors() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,93 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"news-backend/models"
+)
+
+func init() {
+	Register("json_file", newJSONFileConnector)
+	Register("json_http", newJSONHTTPConnector)
+}
+
+// jsonFileConnector reads a JSON array of articles from a local file
+// Config: {"path": "/data/feed.json"}
+type jsonFileConnector struct {
+	path string
+}
+
+func newJSONFileConnector(source models.Source) (SourceConnector, error) {
+	path := source.Config["path"]
+	if path == "" {
+		return nil, fmt.Errorf("json_file connector requires config.path")
+	}
+	return &jsonFileConnector{path: path}, nil
+}
+
+// Fetch reads and parses the file
+func (c *jsonFileConnector) Fetch(ctx context.Context) ([]RawArticle, error) {
+	raw, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", c.path, err)
+	}
+
+	var articles []RawArticle
+	if err := json.Unmarshal(raw, &articles); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", c.path, err)
+	}
+	return articles, nil
+}
+
+// jsonHTTPConnector fetches a JSON array of articles from an HTTP endpoint
+// Config: {"url": "https://example.com/api/articles", "auth_header": "Bearer ..."}
+type jsonHTTPConnector struct {
+	url        string
+	authHeader string
+	client     *http.Client
+}
+
+func newJSONHTTPConnector(source models.Source) (SourceConnector, error) {
+	url := source.Config["url"]
+	if url == "" {
+		return nil, fmt.Errorf("json_http connector requires config.url")
+	}
+	return &jsonHTTPConnector{
+		url:        url,
+		authHeader: source.Config["auth_header"],
+		client:     &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Fetch requests and parses the endpoint's response
+func (c *jsonHTTPConnector) Fetch(ctx context.Context) ([]RawArticle, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.authHeader != "" {
+		req.Header.Set("Authorization", c.authHeader)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", c.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, c.url)
+	}
+
+	var articles []RawArticle
+	if err := json.NewDecoder(resp.Body).Decode(&articles); err != nil {
+		return nil, fmt.Errorf("failed to parse response from %s: %w", c.url, err)
+	}
+	return articles, nil
+}
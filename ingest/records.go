@@ -0,0 +1,79 @@
+package ingest
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"news-backend/models"
+)
+
+// ParseJSON decodes a JSON array of article records
+func ParseJSON(data []byte) ([]RawArticle, error) {
+	var records []RawArticle
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return records, nil
+}
+
+// ParseCSV decodes CSV article records. The first row names the fields
+// (id, title, publication_date, ...); category may hold several
+// comma-separated values in one quoted cell.
+func ParseCSV(data []byte) ([]RawArticle, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(header[i]))
+	}
+
+	var records []RawArticle
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CSV: %w", err)
+		}
+
+		record := make(RawArticle, len(header))
+		for i, field := range header {
+			if i < len(row) && row[i] != "" {
+				record[field] = row[i]
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// Validate checks the fields Transform does not require but every stored
+// article needs
+func Validate(article models.Article) error {
+	if article.Title == "" {
+		return fmt.Errorf("missing title")
+	}
+	if article.URL == "" {
+		return fmt.Errorf("missing url")
+	}
+	if u, err := url.Parse(article.URL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("invalid url %q", article.URL)
+	}
+	if article.RelevanceScore < 0 || article.RelevanceScore > 1 {
+		return fmt.Errorf("relevance_score %v out of range [0, 1]", article.RelevanceScore)
+	}
+	if article.Latitude < -90 || article.Latitude > 90 || article.Longitude < -180 || article.Longitude > 180 {
+		return fmt.Errorf("invalid coordinates (%v, %v)", article.Latitude, article.Longitude)
+	}
+	return nil
+}
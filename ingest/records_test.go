@@ -0,0 +1,61 @@
+package ingest
+
+import (
+	"testing"
+
+	"news-backend/models"
+)
+
+func TestParseCSV(t *testing.T) {
+	data := []byte("ID,Title,URL,Publication_Date,Category,Relevance_Score\n" +
+		"a1,Markets rally,https://example.com/a1,2025-03-26T04:46:55,\"Business, World\",0.8\n" +
+		"a2,No score,https://example.com/a2,2025-03-26,,\n")
+
+	records, err := ParseCSV(data)
+	if err != nil {
+		t.Fatalf("ParseCSV() error = %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("ParseCSV() returned %d records, expected 2", len(records))
+	}
+
+	article, err := Transform(records[0], models.SourceRules{})
+	if err != nil {
+		t.Fatalf("Transform() error = %v", err)
+	}
+	if article.Category != "Business,World" || article.RelevanceScore != 0.8 {
+		t.Errorf("Transform() = category %q, score %v", article.Category, article.RelevanceScore)
+	}
+	if _, ok := records[1]["category"]; ok {
+		t.Error("empty CSV cell was kept")
+	}
+
+	if _, err := ParseCSV([]byte("id,title\n\"unterminated\n")); err == nil {
+		t.Error("ParseCSV() accepted malformed CSV")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := models.Article{Title: "T", URL: "https://example.com/a", RelevanceScore: 0.5}
+	if err := Validate(valid); err != nil {
+		t.Errorf("Validate() of valid article = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(a *models.Article)
+	}{
+		{"Missing title", func(a *models.Article) { a.Title = "" }},
+		{"Missing URL", func(a *models.Article) { a.URL = "" }},
+		{"Relative URL", func(a *models.Article) { a.URL = "/news/a" }},
+		{"Score out of range", func(a *models.Article) { a.RelevanceScore = 1.5 }},
+		{"Bad latitude", func(a *models.Article) { a.Latitude = 91 }},
+	}
+	for _, tt := range tests {
+		article := valid
+		tt.modify(&article)
+		if err := Validate(article); err == nil {
+			t.Errorf("%s: Validate() = nil, expected error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package ingest
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"news-backend/models"
+)
+
+// RawArticle is an untyped article record as received from a feed
+type RawArticle map[string]interface{}
+
+// publicationDateLayouts are tried in order when parsing publication dates
+var publicationDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// Transform converts a raw record into an Article, applying the source's rules
+func Transform(raw RawArticle, rules models.SourceRules) (models.Article, error) {
+	raw = applyFieldMap(raw, rules.FieldMap)
+
+	article := models.Article{
+		ID:             stringField(raw, "id"),
+		Title:          strings.TrimSpace(stringField(raw, "title")),
+		Description:    strings.TrimSpace(stringField(raw, "description")),
+		URL:            stringField(raw, "url"),
+		SourceName:     stringField(raw, "source_name"),
+		RelevanceScore: floatField(raw, "relevance_score"),
+		Latitude:       floatField(raw, "latitude"),
+		Longitude:      floatField(raw, "longitude"),
+		Publisher:      strings.TrimSpace(stringField(raw, "publisher")),
+		License:        strings.TrimSpace(stringField(raw, "license")),
+		Attribution:    strings.TrimSpace(stringField(raw, "attribution")),
+		ImageURL:       strings.TrimSpace(stringField(raw, "image_url")),
+	}
+	article.CurrentRelevance = article.RelevanceScore
+
+	if article.ID == "" {
+		return article, fmt.Errorf("missing id")
+	}
+
+	title, err := cleanTitle(article.Title, rules.TitleCleanup)
+	if err != nil {
+		return article, err
+	}
+	article.Title = title
+
+	pubDate, err := parsePublicationDate(stringField(raw, "publication_date"), rules.Timezone)
+	if err != nil {
+		return article, err
+	}
+	article.PublicationDate = pubDate
+
+	article.Category = strings.Join(mapCategories(categoryField(raw, "category"), rules.CategoryMap), ",")
+	article.ContentHash = article.ComputeContentHash()
+
+	return article, nil
+}
+
+// SourceFor returns the source, out of sources keyed by name, whose rules
+// apply to raw: the one its source_name field names or else one whose
+// FieldMap maps another field to source_name and finds its own name there.
+// It is the zero Source, with default rules, when none matches.
+func SourceFor(raw RawArticle, sources map[string]models.Source) models.Source {
+	if name := stringField(raw, "source_name"); name != "" {
+		if source, ok := sources[name]; ok {
+			return source
+		}
+	}
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names) // the same source wins on every run
+	for _, name := range names {
+		for from, to := range sources[name].Rules.FieldMap {
+			if to == "source_name" && stringField(raw, from) == name {
+				return sources[name]
+			}
+		}
+	}
+	return models.Source{}
+}
+
+// applyFieldMap copies raw fields to their mapped article field names
+func applyFieldMap(raw RawArticle, fieldMap map[string]string) RawArticle {
+	if len(fieldMap) == 0 {
+		return raw
+	}
+
+	mapped := make(RawArticle, len(raw))
+	for key, value := range raw {
+		mapped[key] = value
+	}
+	for from, to := range fieldMap {
+		if value, ok := raw[from]; ok {
+			mapped[to] = value
+		}
+	}
+	return mapped
+}
+
+// cleanTitle applies regex cleanup rules in order
+func cleanTitle(title string, rules []models.RegexReplace) (string, error) {
+	for _, rule := range rules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return title, fmt.Errorf("invalid title cleanup pattern %q: %w", rule.Pattern, err)
+		}
+		title = re.ReplaceAllString(title, rule.Replace)
+	}
+	return strings.TrimSpace(title), nil
+}
+
+// parsePublicationDate parses a date, using timezone for values without an offset
+func parsePublicationDate(value, timezone string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, fmt.Errorf("missing publication_date")
+	}
+
+	loc := time.UTC
+	if timezone != "" {
+		tz, err := time.LoadLocation(timezone)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid timezone %q: %w", timezone, err)
+		}
+		loc = tz
+	}
+
+	for _, layout := range publicationDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid publication_date %q", value)
+}
+
+// mapCategories rewrites categories through the mapping table, dropping duplicates
+func mapCategories(categories []string, categoryMap map[string]string) []string {
+	lookup := make(map[string]string, len(categoryMap))
+	for from, to := range categoryMap {
+		lookup[strings.ToLower(from)] = to
+	}
+
+	seen := make(map[string]bool, len(categories))
+	result := make([]string, 0, len(categories))
+	for _, category := range categories {
+		category = strings.TrimSpace(category)
+		if mapped, ok := lookup[strings.ToLower(category)]; ok {
+			category = mapped
+		}
+		if category == "" || seen[category] {
+			continue
+		}
+		seen[category] = true
+		result = append(result, category)
+	}
+	return result
+}
+
+// stringField reads a string value from a raw record
+func stringField(raw RawArticle, key string) string {
+	switch v := raw[key].(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// floatField reads a numeric value from a raw record
+func floatField(raw RawArticle, key string) float64 {
+	switch v := raw[key].(type) {
+	case float64:
+		return v
+	case string:
+		f, _ := strconv.ParseFloat(v, 64)
+		return f
+	default:
+		return 0
+	}
+}
+
+// categoryField reads categories given either as a list or a comma-separated string
+func categoryField(raw RawArticle, key string) []string {
+	switch v := raw[key].(type) {
+	case []interface{}:
+		categories := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				categories = append(categories, s)
+			}
+		}
+		return categories
+	case []string:
+		return v
+	case string:
+		return strings.Split(v, ",")
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,189 @@
+package ingest
+
+import (
+	"testing"
+	"time"
+
+	"news-backend/models"
+)
+
+func TestTransform_Defaults(t *testing.T) {
+	raw := RawArticle{
+		"id":               "a1",
+		"title":            "  Markets rally  ",
+		"publication_date": "2025-03-26T04:46:55",
+		"source_name":      "Reuters",
+		"category":         []interface{}{"Business", "World"},
+		"relevance_score":  0.8,
+	}
+
+	article, err := Transform(raw, models.SourceRules{})
+	if err != nil {
+		t.Fatalf("Transform() error = %v", err)
+	}
+
+	if article.Title != "Markets rally" {
+		t.Errorf("Title = %q, expected trimmed title", article.Title)
+	}
+	if article.Category != "Business,World" {
+		t.Errorf("Category = %q, expected %q", article.Category, "Business,World")
+	}
+	expectedDate := time.Date(2025, 3, 26, 4, 46, 55, 0, time.UTC)
+	if !article.PublicationDate.Equal(expectedDate) {
+		t.Errorf("PublicationDate = %v, expected %v", article.PublicationDate, expectedDate)
+	}
+	if article.CurrentRelevance != 0.8 {
+		t.Errorf("CurrentRelevance = %v, expected 0.8", article.CurrentRelevance)
+	}
+}
+
+func TestTransform_Rules(t *testing.T) {
+	raw := RawArticle{
+		"guid":      "b2",
+		"headline":  "BREAKING: Storm hits coast | Example News",
+		"published": "2025-03-26 10:00:00",
+		"section":   "tech, Technology, weather",
+	}
+
+	rules := models.SourceRules{
+		FieldMap: map[string]string{
+			"guid":      "id",
+			"headline":  "title",
+			"published": "publication_date",
+			"section":   "category",
+		},
+		CategoryMap: map[string]string{"tech": "Technology", "weather": "Environment"},
+		TitleCleanup: []models.RegexReplace{
+			{Pattern: `^BREAKING:\s*`, Replace: ""},
+			{Pattern: `\s*\|.*$`, Replace: ""},
+		},
+		Timezone: "Asia/Kolkata",
+	}
+
+	article, err := Transform(raw, rules)
+	if err != nil {
+		t.Fatalf("Transform() error = %v", err)
+	}
+
+	if article.ID != "b2" {
+		t.Errorf("ID = %q, expected mapped id", article.ID)
+	}
+	if article.Title != "Storm hits coast" {
+		t.Errorf("Title = %q, expected cleaned title", article.Title)
+	}
+	if article.Category != "Technology,Environment" {
+		t.Errorf("Category = %q, expected mapped and deduplicated categories", article.Category)
+	}
+	if got := article.PublicationDate.UTC().Hour(); got != 4 {
+		t.Errorf("PublicationDate UTC hour = %d, expected 4 (10:00 IST)", got)
+	}
+}
+
+func TestTransform_Provenance(t *testing.T) {
+	raw := RawArticle{
+		"id":               "c3",
+		"publication_date": "2025-03-26",
+		"source_name":      "Example Wire",
+		"rights":           " CC-BY-4.0 ",
+		"publisher":        "Example Daily",
+	}
+	source := models.Source{
+		Name:    "Example Wire",
+		License: "all-rights-reserved",
+		Rules:   models.SourceRules{FieldMap: map[string]string{"rights": "license"}},
+	}
+
+	article, err := Transform(raw, source.Rules)
+	if err != nil {
+		t.Fatalf("Transform() error = %v", err)
+	}
+	article.ApplyProvenance(source, models.IngestSourceConnector+"json_http")
+
+	// The feed's values win over the source's defaults
+	if article.License != "CC-BY-4.0" {
+		t.Errorf("License = %q, expected the feed's license", article.License)
+	}
+	if article.Publisher != "Example Daily" {
+		t.Errorf("Publisher = %q, expected the feed's publisher", article.Publisher)
+	}
+	if article.Attribution != "Source: Example Daily" {
+		t.Errorf("Attribution = %q, expected a credit line naming the publisher", article.Attribution)
+	}
+	if article.IngestSource != "connector:json_http" {
+		t.Errorf("IngestSource = %q, expected connector:json_http", article.IngestSource)
+	}
+
+	// Without feed values the source's defaults, then the global ones, apply
+	bare := models.Article{SourceName: "Example Wire"}
+	bare.ApplyProvenance(models.Source{Attribution: "Courtesy of Example Wire"}, models.IngestSourceDataset)
+	if bare.Publisher != "Example Wire" || bare.License != models.DefaultLicense || bare.Attribution != "Courtesy of Example Wire" {
+		t.Errorf("ApplyProvenance() defaults = %q, %q, %q", bare.Publisher, bare.License, bare.Attribution)
+	}
+}
+
+func TestTransform_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		raw   RawArticle
+		rules models.SourceRules
+	}{
+		{"Missing id", RawArticle{"publication_date": "2025-03-26"}, models.SourceRules{}},
+		{"Bad date", RawArticle{"id": "x", "publication_date": "yesterday"}, models.SourceRules{}},
+		{"Bad timezone", RawArticle{"id": "x", "publication_date": "2025-03-26"}, models.SourceRules{Timezone: "Mars/Base"}},
+		{"Bad pattern", RawArticle{"id": "x", "publication_date": "2025-03-26"},
+			models.SourceRules{TitleCleanup: []models.RegexReplace{{Pattern: "("}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Transform(tt.raw, tt.rules); err == nil {
+				t.Error("Transform() expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestSourceFor(t *testing.T) {
+	sources := map[string]models.Source{
+		"Reuters": {Name: "Reuters"},
+		"Wire": {Name: "Wire", Rules: models.SourceRules{
+			FieldMap:    map[string]string{"outlet": "source_name", "headline": "title"},
+			CategoryMap: map[string]string{"biz": "Business"},
+		}},
+	}
+
+	tests := []struct {
+		name     string
+		raw      RawArticle
+		expected string
+	}{
+		{"Named by source_name", RawArticle{"source_name": "Reuters"}, "Reuters"},
+		{"Named by a mapped field", RawArticle{"outlet": "Wire"}, "Wire"},
+		{"Mapped field naming another source", RawArticle{"outlet": "Reuters"}, ""},
+		{"Unknown source", RawArticle{"source_name": "Other"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SourceFor(tt.raw, sources).Name; got != tt.expected {
+				t.Errorf("SourceFor() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+
+	// The mapped source's own rules fill in its name and apply
+	raw := RawArticle{
+		"id":               "w1",
+		"headline":         "Rates hold",
+		"outlet":           "Wire",
+		"publication_date": "2025-03-26",
+		"category":         "biz",
+	}
+	article, err := Transform(raw, SourceFor(raw, sources).Rules)
+	if err != nil {
+		t.Fatalf("Transform() error = %v", err)
+	}
+	if article.SourceName != "Wire" || article.Title != "Rates hold" || article.Category != "Business" {
+		t.Errorf("Transform() = source %q, title %q, category %q, expected the Wire rules applied",
+			article.SourceName, article.Title, article.Category)
+	}
+}
@@ -1,21 +1,78 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
+	"math/rand"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
+	"news-backend/cache"
 	"news-backend/config"
 	"news-backend/database"
+	grpcapi "news-backend/grpc"
 	"news-backend/handlers"
+	"news-backend/metrics"
 	"news-backend/middleware"
+	"news-backend/migrations"
+	"news-backend/objectstore"
+	"news-backend/replay"
 	"news-backend/services"
+	"news-backend/shadow"
+	"news-backend/utils"
 
 	"github.com/gin-gonic/gin"
+
+	// IANA time zones for feed composition; the runtime image has no tzdata
+	_ "time/tzdata"
 )
 
 func main() {
+	reload := flag.Bool("reload", false, "upsert changed articles from NEWS_DATA_FILE into a non-empty database")
+	migrate := flag.String("migrate", "", "apply pending schema migrations (up), revert the newest one (down) or list them (status) and exit")
+	check := flag.Bool("check", false, "validate the configuration, list pending schema migrations, ping the cache and LLM providers and exit")
+	evalIntents := flag.String("eval-intents", "", "run the intent evaluation cases in this file, print a report and exit")
+	evalMode := flag.String("eval-mode", services.IntentEvalLive, "intent evaluation mode: live, replay (recorded answers) or record")
+	evalMinAccuracy := flag.Float64("eval-min-accuracy", 0, "exit with status 1 when the intent evaluation accuracy is below this (0-1)")
+	var replayOpts replayOptions
+	flag.StringVar(&replayOpts.logFile, "replay", "", "replay the GET requests logged in this file against -replay-target, print a report and exit")
+	flag.StringVar(&replayOpts.target, "replay-target", "", "base URL of the environment requests are replayed against")
+	flag.StringVar(&replayOpts.from, "replay-from", "", "replay requests logged from this time on (RFC3339 or YYYY-MM-DD)")
+	flag.StringVar(&replayOpts.to, "replay-to", "", "replay requests logged before this time (RFC3339 or YYYY-MM-DD)")
+	flag.Float64Var(&replayOpts.sample, "replay-sample", 1, "share of the logged requests to replay (0-1)")
+	flag.Float64Var(&replayOpts.speed, "replay-speed", 1, "replay pace relative to the logged one, 0 sends as fast as possible")
+	flag.Float64Var(&replayOpts.maxMismatch, "replay-max-mismatch", 0.01, "exit with status 1 when more than this share of statuses differ (0-1)")
+	flag.Parse()
+
+	// Replays only talk to the target, so they need no configuration
+	if replayOpts.logFile != "" {
+		os.Exit(runReplay(replayOpts))
+	}
+
 	// Load configuration
 	cfg := config.LoadConfig()
+	if *check {
+		os.Exit(runCheck(cfg))
+	}
+	if *migrate != "" {
+		os.Exit(runMigrate(cfg, *migrate))
+	}
+	if problems := validateConfig(cfg); len(problems) > 0 {
+		for _, problem := range problems {
+			log.Printf("Configuration error: %v", problem)
+		}
+		log.Fatalf("Invalid configuration, %d problems; run with -check to also test the database, cache and LLM providers", len(problems))
+	}
 	log.Println("Configuration loaded successfully")
 
 	// Initialize database
@@ -24,14 +81,26 @@ func main() {
 	}
 	log.Println("Database initialized")
 
-	// Load news data from JSON file
-	dataFile := "news_data.json"
-	if _, err := os.Stat(dataFile); err == nil {
+	// Load source definitions (ingest rules) before articles so they apply
+	sourcesFile := "sources.json"
+	if _, err := os.Stat(sourcesFile); err == nil {
+		if err := database.LoadSources(sourcesFile); err != nil {
+			log.Printf("Warning: Failed to load sources: %v", err)
+		}
+	}
+	if err := database.BackfillProvenance(); err != nil {
+		log.Printf("Warning: Failed to backfill article provenance: %v", err)
+	}
+
+	// Load news data from JSON file into an empty database; with --reload
+	// changed articles are upserted once services are up to invalidate caches
+	dataFile := cfg.NewsDataFile
+	if _, err := os.Stat(dataFile); err != nil {
+		log.Printf("Warning: News data file not found: %s", dataFile)
+	} else if !*reload {
 		if err := database.LoadNewsData(dataFile); err != nil {
 			log.Printf("Warning: Failed to load news data: %v", err)
 		}
-	} else {
-		log.Printf("Warning: News data file not found: %s", dataFile)
 	}
 
 	// Seed user events for trending functionality
@@ -40,77 +109,476 @@ func main() {
 	}
 
 	// Initialize services
-	llmService := services.NewLLMService(cfg)
-	newsService := services.NewNewsService(cfg, llmService)
-	trendingService := services.NewTrendingService(cfg, llmService)
+	invalidationService, err := services.NewInvalidationService(cfg)
+	if err != nil {
+		log.Fatalf("Invalid REDIS_URL: %v", err)
+	}
+	sharedCache, err := cache.New(cfg.CacheBackend, cfg.RedisURL)
+	if err != nil {
+		log.Fatalf("Failed to configure cache: %v", err)
+	}
+	llmService := services.NewLLMService(cfg, invalidationService, sharedCache)
+	if *evalIntents != "" {
+		os.Exit(runIntentEval(llmService, *evalIntents, *evalMode, *evalMinAccuracy))
+	}
+	if cfg.LLMOffline {
+		log.Println("LLM_PROVIDER is none: intents come from rules, summaries from descriptions")
+	}
+	embeddingService := services.NewEmbeddingService(cfg, llmService)
+	webhookService := services.NewWebhookService(cfg)
+	userService := services.NewUserService(cfg, invalidationService)
+	summaryExperiment := services.NewSummaryExperimentService(cfg, userService)
+	newsService := services.NewNewsService(cfg, llmService, embeddingService, userService)
+	cdnService := services.NewCDNService(cfg)
+	geocodingService := services.NewGeocodingService(cfg, sharedCache)
+	metricsRegistry := metrics.NewRegistry()
+	trendingService := services.NewTrendingService(cfg, llmService, userService, cdnService, invalidationService, geocodingService, sharedCache, summaryExperiment, embeddingService,
+		metricsRegistry.Queue("events", cfg.EventQueueMax))
+	sourceService := services.NewSourceService(cfg, trendingService)
+	feedbackService := services.NewFeedbackService(cfg, llmService, cdnService, summaryExperiment)
+	storyService := services.NewStoryService(cfg)
+	keywordAlertService := services.NewKeywordAlertService(cfg, webhookService)
+	editionService := services.NewEditionService(cfg, newsService, trendingService)
+	placeService := services.NewPlaceService(cfg, userService)
+	digestService := services.NewDigestService(cfg, llmService, trendingService, sharedCache)
+	feedService := services.NewFeedService(cfg, llmService, trendingService, digestService)
+	articleService := services.NewArticleService(cfg, llmService, embeddingService, trendingService, webhookService, cdnService)
+	sloService := services.NewSLOService(cfg, metricsRegistry, webhookService)
+	snapshotStore, err := objectstore.Open(cfg.SnapshotStoreURL, objectstore.S3Options{
+		Endpoint:     cfg.SnapshotS3Endpoint,
+		Region:       cfg.SnapshotS3Region,
+		AccessKey:    cfg.AWSAccessKeyID,
+		SecretKey:    cfg.AWSSecretAccessKey,
+		SessionToken: cfg.AWSSessionToken,
+	})
+	if err != nil {
+		log.Fatalf("Invalid SNAPSHOT_STORE_URL: %v", err)
+	}
+	snapshotService := services.NewSnapshotService(cfg, snapshotStore)
+	exportService := services.NewExportService(cfg)
+	shadowMirror, err := shadow.New(cfg.ShadowUpstreamURL, cfg.ShadowPercent,
+		time.Duration(cfg.ShadowTimeoutMs)*time.Millisecond, cfg.ShadowAPIKey)
+	if err != nil {
+		log.Fatalf("Failed to configure traffic shadowing: %v", err)
+	}
+	if shadowMirror != nil {
+		log.Printf("Shadowing %.1f%% of read traffic to %s", cfg.ShadowPercent, cfg.ShadowUpstreamURL)
+	}
+	if cfg.AdminAuthDisabled {
+		log.Println("Warning: ADMIN_AUTH_DISABLED is set, admin endpoints are unauthenticated")
+	} else if cfg.AdminToken == "" {
+		log.Println("Warning: ADMIN_TOKEN is not set, admin endpoints answer 503")
+	}
 	log.Println("Services initialized")
 
+	if *reload {
+		if _, err := articleService.Reload(); err != nil {
+			log.Printf("Warning: Failed to reload news data: %v", err)
+		}
+	}
+
+	// Start background workers
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	var workers sync.WaitGroup
+	startWorker := func(run func(ctx context.Context)) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			run(workerCtx)
+		}()
+	}
+
+	relevanceWorker := services.NewRelevanceWorker(cfg)
+	startWorker(relevanceWorker.Start)
+
+	eventRetentionWorker := services.NewEventRetentionWorker(cfg)
+	startWorker(eventRetentionWorker.Start)
+
+	archiveWorker := services.NewArchiveWorker(cfg, embeddingService, cdnService, trendingService)
+	startWorker(archiveWorker.Start)
+
+	summaryQueue := metricsRegistry.Queue("summaries", cfg.SummaryQueueMax)
+	summaryWorker := services.NewSummaryWorker(cfg, llmService, summaryQueue)
+	startWorker(summaryWorker.Start)
+
+	sentimentWorker := services.NewSentimentWorker(cfg, llmService)
+	startWorker(sentimentWorker.Start)
+
+	topicService := services.NewTopicService(cfg, llmService, userService)
+	startWorker(topicService.Start)
+
+	ingestService := services.NewIngestService(cfg, llmService, embeddingService, webhookService, cdnService, keywordAlertService, topicService,
+		metricsRegistry.Queue("ingest", cfg.IngestQueueMax), summaryQueue)
+	startWorker(ingestService.Start)
+
+	contentWorker := services.NewContentWorker(cfg, llmService, cdnService, trendingService)
+	startWorker(contentWorker.Start)
+
+	startWorker(trendingService.Start)
+
+	startWorker(editionService.Start)
+
+	startWorker(sloService.Start)
+
+	startWorker(invalidationService.Start)
+
+	startWorker(storyService.Start)
+
+	// Compute missing article embeddings and load the in-memory index
+	// without blocking startup
+	startWorker(embeddingService.Start)
+
 	// Initialize handlers
-	newsHandler := handlers.NewNewsHandler(newsService)
+	newsHandler := handlers.NewNewsHandler(newsService, embeddingService)
 	trendingHandler := handlers.NewTrendingHandler(trendingService)
+	userHandler := handlers.NewUserHandler(userService)
+	topicHandler := handlers.NewTopicHandler(topicService)
+	placeHandler := handlers.NewPlaceHandler(placeService)
+	feedbackHandler := handlers.NewFeedbackHandler(feedbackService)
+	alertHandler := handlers.NewAlertHandler(keywordAlertService)
+	editionHandler := handlers.NewEditionHandler(editionService)
+	digestHandler := handlers.NewDigestHandler(digestService)
+	feedHandler := handlers.NewFeedHandler(feedService)
+	experimentHandler := handlers.NewExperimentHandler(summaryExperiment)
+	snapshotHandler := handlers.NewSnapshotHandler(snapshotService)
+	exportHandler := handlers.NewExportHandler(exportService)
+	sourceHandler := handlers.NewSourceHandler(sourceService)
+	storyHandler := handlers.NewStoryHandler(storyService, newsService)
+	articleHandler := handlers.NewArticleHandler(articleService, newsService, trendingService)
+	imageHandler := handlers.NewImageHandler(articleService, services.NewImageService(cfg))
+	syndicationHandler := handlers.NewSyndicationHandler(newsService, cfg.PublicURL)
+	degradationService := services.NewDegradationService(cfg, llmService, embeddingService, geocodingService, ingestService, sharedCache, metricsRegistry)
+	adminHandler := handlers.NewAdminHandler(llmService, trendingService, articleService, embeddingService, sloService, metricsRegistry, shadowMirror, degradationService)
+	healthHandler := handlers.NewHealthHandler(services.NewHealthService(cfg, llmService, sharedCache))
 
 	// Setup Gin router
 	if cfg.ServerPort == "8080" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 	router := gin.New()
+	if err := middleware.TrustProxies(router, cfg.TrustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+	schemaHandler := handlers.NewSchemaHandler(router, cfg.SwaggerUIURL)
 
 	// Global middleware
 	router.Use(middleware.Logger())
-	router.Use(middleware.CORS())
+	router.Use(middleware.Metrics(metricsRegistry))
+	router.Use(middleware.SecurityHeaders(cfg.HSTSMaxAge))
+	router.Use(middleware.CORS(middleware.CORSConfig{
+		AllowedOrigins: strings.Split(cfg.CORSAllowedOrigins, ","),
+		AllowedMethods: strings.Split(cfg.CORSAllowedMethods, ","),
+		MaxAge:         cfg.CORSMaxAge,
+	}))
+	router.Use(middleware.Compression(strings.Split(cfg.CompressionAlgorithms, ","), cfg.CompressionMinSize))
 	router.Use(middleware.ErrorHandler())
 	router.Use(gin.Recovery())
 
+	// Repeated identical searches are answered from memory until the TTL
+	// runs out or a purge covers them
+	responseCache := middleware.NewResponseCache(time.Duration(cfg.ResponseCacheTTL)*time.Second, cfg.ResponseCacheMaxEntries)
+	cdnService.OnPurge(responseCache.Purge)
+	responseCaching := middleware.ResponseCaching(responseCache)
+
+	// Public routes share one API key check so they share the demo rate limit
+	var apiKeys []string
+	if cfg.APIKeys != "" {
+		apiKeys = strings.Split(cfg.APIKeys, ",")
+	}
+	rejectExcessive := cfg.RequestLimitMode == "reject"
+	redactionRules, err := services.ParseRedactionRules(cfg.RedactionRules)
+	if err != nil {
+		log.Fatalf("Invalid REDACTION_RULES: %v", err)
+	}
+	requestLimits := services.RequestLimits{
+		MaxRadiusKm:   cfg.MaxRadiusKm,
+		MaxWindowDays: cfg.MaxWindowDays,
+		MaxArticles:   cfg.MaxLimit,
+		Reject:        rejectExcessive,
+	}
+	demoLimits := services.RequestLimits{
+		MaxRadiusKm:   cfg.DemoMaxRadiusKm,
+		MaxWindowDays: cfg.DemoMaxWindowDays,
+		MaxArticles:   cfg.DemoMaxArticles,
+		Reject:        rejectExcessive,
+	}
+	apiKey := middleware.APIKey(middleware.APIKeyConfig{
+		Keys:            apiKeys,
+		DemoKey:         cfg.DemoAPIKey,
+		DemoRateLimit:   cfg.DemoRateLimit,
+		DemoMaxArticles: cfg.DemoMaxArticles,
+		Limits:          requestLimits,
+		DemoLimits:      demoLimits,
+		Redaction:       redactionRules,
+	})
+
+	// Runs after apiKey so clients are counted by accepted key
+	routeLimits, err := middleware.ParseRouteLimits(cfg.RateLimitRoutes)
+	if err != nil {
+		log.Fatalf("Invalid RATE_LIMIT_ROUTES: %v", err)
+	}
+	rateLimit := middleware.RateLimit(middleware.RateLimitConfig{
+		PerMinute: cfg.RateLimitPerMinute,
+		Routes:    routeLimits,
+	})
+
+	// Summaries follow the tone parameter, the user's stored preference or
+	// their summary tone experiment arm
+	summaryTone := middleware.SummaryTone(summaryExperiment.PreferredSummaryTone)
+
+	// near=<place> resolves to the coordinates of the user's saved place
+	nearPlace := middleware.NearPlace(placeService.Resolve)
+
+	// Sources, categories and keywords the user_id's user muted are left out
+	mutes := middleware.Mutes(userService.MuteSetFor)
+
 	// API v1 routes
 	v1 := router.Group("/api/v1")
 	{
 		// Health check
-		v1.GET("/health", newsHandler.HealthCheck)
+		v1.GET("/health", middleware.NoStore(), healthHandler.HealthCheck)
+
+		// Routes, enumerations and types for generating client SDKs
+		v1.GET("/schema", schemaHandler.GetSchema)
+		v1.GET("/openapi.json", schemaHandler.GetOpenAPI)
 
-		// News endpoints
-		news := v1.Group("/news")
+		// News endpoints are not personalized and can be served from the edge
+		news := v1.Group("/news", apiKey, rateLimit,
+			middleware.PublicCache(cfg.EdgeCacheNewsTTL, services.SurrogateKeyNews),
+			middleware.Shadow(shadowMirror), summaryTone, mutes)
 		{
 			// API endpoints as per assignment requirements
-			news.GET("/category", newsHandler.GetByCategory)
-			news.GET("/source", newsHandler.GetBySource)
+			news.GET("/category", responseCaching, newsHandler.GetByCategory)
+			news.GET("/categories", newsHandler.GetCategories)
+			news.GET("/latest", responseCaching, newsHandler.GetLatest)
+			news.GET("/source", responseCaching, newsHandler.GetBySource)
 			news.GET("/score", newsHandler.GetByScore)
-			news.GET("/nearby", newsHandler.GetNearby)
-			news.GET("/search", newsHandler.Search)
+			news.GET("/nearby", nearPlace, newsHandler.GetNearby)
+			news.GET("/search", responseCaching, newsHandler.Search)
+			news.GET("/semantic-search", newsHandler.SemanticSearch)
+
+			// Answers grounded in retrieved articles
+			news.POST("/ask", middleware.NoStore(), newsHandler.Ask)
+
+			// Conversational queries whose follow-ups refine the previous turn
+			news.POST("/query", middleware.NoStore(), newsHandler.Query)
 
 			// Statistics
 			news.GET("/stats", newsHandler.GetStats)
 		}
 
+		// Public corpus aggregates need no API key, so they have their own per-IP limit
+		v1.GET("/stats/public",
+			middleware.RateLimit(middleware.RateLimitConfig{PerMinute: cfg.PublicStatsRateLimit}),
+			middleware.PublicCache(cfg.EdgeCacheNewsTTL, services.SurrogateKeyNews),
+			newsHandler.GetPublicStats)
+
+		// Story endpoints: clusters of near-duplicate articles
+		v1.GET("/stories/:id", apiKey, rateLimit, middleware.PublicCache(cfg.EdgeCacheNewsTTL, services.SurrogateKeyNews),
+			summaryTone, storyHandler.GetStory)
+
+		// Article detail; engagement counts move like trending, so it is cached as long
+		v1.GET("/articles/:id", apiKey, rateLimit, middleware.PublicCache(cfg.EdgeCacheTrendingTTL, services.SurrogateKeyTrending),
+			summaryTone, articleHandler.GetArticle)
+
+		// Article summary streamed as server-sent events while the LLM writes it
+		v1.GET("/news/article/:id/summary/stream", apiKey, rateLimit, middleware.NoStore(), summaryTone,
+			articleHandler.StreamSummary)
+
+		// Article lead images, proxied and resized for cards
+		v1.GET("/images/:article_id", apiKey, rateLimit, middleware.PublicCache(cfg.EdgeCacheImageTTL),
+			imageHandler.GetImage)
+
+		// Local editions are precomputed per city and served from the edge
+		editions := v1.Group("/editions", apiKey, rateLimit,
+			middleware.PublicCache(cfg.EdgeCacheNewsTTL, services.SurrogateKeyNews))
+		{
+			editions.GET("", editionHandler.ListEditions)
+			editions.GET("/:slug/news", editionHandler.GetEditionNews)
+		}
+
 		// Trending endpoints
-		trending := v1.Group("/trending")
+		trending := v1.Group("/trending", apiKey, rateLimit)
 		{
 			// Get trending news
-			trending.GET("", trendingHandler.GetTrending)
+			trending.GET("", middleware.PublicCache(cfg.EdgeCacheTrendingTTL, services.SurrogateKeyTrending),
+				middleware.Shadow(shadowMirror), summaryTone, nearPlace, trendingHandler.GetTrending)
 
 			// Record user event
-			trending.POST("/event", trendingHandler.RecordEvent)
+			trending.POST("/event", middleware.NoStore(), middleware.FullAccess(), trendingHandler.RecordEvent)
 
 			// Statistics
-			trending.GET("/stats", trendingHandler.GetEventStats)
+			trending.GET("/stats", middleware.NoStore(), middleware.FullAccess(), trendingHandler.GetEventStats)
 
 			// Cache management
-			trending.POST("/cache/invalidate", trendingHandler.InvalidateCache)
+			trending.POST("/cache/invalidate", middleware.NoStore(), middleware.FullAccess(), trendingHandler.InvalidateCache)
+		}
+
+		// Daily briefing on the top articles around a location, cached until
+		// the next digest cycle
+		v1.GET("/digest", apiKey, rateLimit, digestHandler.GetDigest)
+
+		// Briefings, long reads and trending articles mixed by the user's
+		// local time of day
+		v1.GET("/feed", apiKey, rateLimit, middleware.PublicCache(cfg.EdgeCacheTrendingTTL, services.SurrogateKeyTrending),
+			summaryTone, nearPlace, mutes, feedHandler.GetFeed)
+
+		// RSS and Atom feeds for feed readers, which pass the key as api_key
+		feeds := v1.Group("/feeds", apiKey, rateLimit,
+			middleware.PublicCache(cfg.EdgeCacheNewsTTL, services.SurrogateKeyNews))
+		{
+			feeds.GET("/rss", syndicationHandler.GetRSS)
+			feeds.GET("/atom", syndicationHandler.GetAtom)
+		}
+
+		// Topics extracted from articles, ranked like trending articles
+		v1.GET("/topics/trending", apiKey, rateLimit, middleware.PublicCache(cfg.EdgeCacheTrendingTTL, services.SurrogateKeyTrending),
+			topicHandler.GetTrending)
+
+		// User identity endpoints
+		users := v1.Group("/users", middleware.NoStore(), apiKey, rateLimit, middleware.FullAccess())
+		{
+			// Cross-device identity linking
+			users.GET("/:id/links", userHandler.GetLinks)
+			users.POST("/:id/device", userHandler.RegisterDevice)
+			users.POST("/:id/link-tokens", userHandler.IssueLinkToken)
+			users.POST("/:id/links", userHandler.LinkUser)
+			users.DELETE("/:id/links/:linked_id", userHandler.UnlinkUser)
+			users.GET("/:id/links/suggestions", userHandler.GetLinkSuggestions)
+
+			// Response preferences such as the summary tone
+			users.GET("/:id/preferences", userHandler.GetPreferences)
+			users.PUT("/:id/preferences", userHandler.SetPreferences)
+
+			// "More/less like this" ranking adjustments
+			users.GET("/:id/signals", userHandler.GetFeedAdjustments)
+			users.POST("/:id/signals", userHandler.RecordFeedSignal)
+			users.DELETE("/:id/signals", userHandler.ClearFeedSignals)
+
+			// Followed topics, boosted in the personalized feed
+			users.GET("/:id/topics", topicHandler.GetFollowed)
+			users.POST("/:id/topics/:topic/follow", topicHandler.Follow)
+			users.DELETE("/:id/topics/:topic/follow", topicHandler.Unfollow)
+
+			// Named places for near=<name>, saved after opting in with save_places
+			users.GET("/:id/places", placeHandler.GetPlaces)
+			users.PUT("/:id/places/:name", placeHandler.SavePlace)
+			users.DELETE("/:id/places/:name", placeHandler.DeletePlace)
+
+			// Sources, categories and keywords hidden from news and the feed
+			users.GET("/:id/mutes", userHandler.GetMutes)
+			users.POST("/:id/mutes", userHandler.AddMute)
+			users.DELETE("/:id/mutes/:mute_id", userHandler.RemoveMute)
+		}
+
+		// User feedback on summaries and rankings
+		v1.POST("/feedback", middleware.NoStore(), apiKey, rateLimit, middleware.FullAccess(), feedbackHandler.SubmitFeedback)
+
+		// Admin endpoints
+		admin := v1.Group("/admin", middleware.NoStore(), middleware.AdminAuth(cfg.AdminToken, cfg.AdminAuthDisabled))
+		{
+			// Editorial article corrections
+			admin.POST("/articles", adminHandler.CreateArticle)
+			admin.POST("/articles/import", adminHandler.ImportArticles)
+			admin.POST("/articles/reload", adminHandler.ReloadArticles)
+			admin.PUT("/articles/:id", adminHandler.UpdateArticle)
+			admin.DELETE("/articles/:id", adminHandler.DeleteArticle)
+			admin.POST("/articles/:id/restore", adminHandler.RestoreArticle)
+
+			// LLM spend and rate limits
+			admin.GET("/llm/usage", adminHandler.GetLLMUsage)
+			admin.GET("/llm/audit", adminHandler.GetLLMAudit)
+
+			// Request metrics and SLO compliance
+			admin.GET("/metrics", adminHandler.GetMetrics)
+			admin.GET("/slo", adminHandler.GetSLOStatus)
+
+			// Optional subsystems and the features reduced without them
+			admin.GET("/degradation", adminHandler.GetDegradation)
+
+			// In-memory embedding index
+			admin.GET("/embeddings/index", adminHandler.GetEmbeddingIndex)
+			admin.POST("/embeddings/index/rebuild", adminHandler.RebuildEmbeddingIndex)
+
+			// Canary traffic shadowing
+			admin.GET("/shadow", adminHandler.GetShadowStats)
+
+			// Trending opt-outs
+			admin.GET("/trending/exclusions", adminHandler.GetTrendingExclusions)
+			admin.PUT("/trending/exclusions/articles/:id", adminHandler.SetArticleTrendingExclusion)
+			admin.PUT("/trending/exclusions/sources/:name", adminHandler.SetSourceTrendingExclusion)
+			admin.GET("/trending/replay", adminHandler.ReplayTrending)
+
+			// Raw user events
+			admin.GET("/events", adminHandler.ListEvents)
+
+			// Source reliability ratings
+			admin.GET("/sources", sourceHandler.ListSources)
+			admin.PUT("/sources/:name", sourceHandler.SetSourceReliability)
+
+			// Feedback review queue
+			admin.GET("/feedback", feedbackHandler.ListFeedback)
+			admin.POST("/feedback/:id/resolve", feedbackHandler.ResolveFeedback)
+
+			// Summary tone experiment
+			admin.GET("/experiments/summary-tone", experimentHandler.GetSummaryToneExperiment)
+			admin.POST("/experiments/summary-tone/reset", experimentHandler.ResetSummaryToneExperiment)
+
+			// Consistent exports for training ranking models
+			admin.POST("/snapshots", snapshotHandler.CreateSnapshot)
+
+			// Article table with summaries and engagement, streamed as CSV or NDJSON
+			admin.GET("/export/articles", exportHandler.ExportArticles)
+
+			// Editor keyword alerts on ingested content
+			admin.GET("/alerts/keywords", alertHandler.ListAlerts)
+			admin.POST("/alerts/keywords", alertHandler.CreateAlert)
+			admin.PUT("/alerts/keywords/:id", alertHandler.SetAlertEnabled)
+			admin.DELETE("/alerts/keywords/:id", alertHandler.DeleteAlert)
+			admin.GET("/alerts/matches", alertHandler.ListMatches)
+			admin.POST("/alerts/matches/:id/triage", alertHandler.TriageMatch)
+
+			// Local editions
+			admin.GET("/editions", editionHandler.ListAllEditions)
+			admin.POST("/editions", editionHandler.CreateEdition)
+			admin.PUT("/editions/:slug", editionHandler.UpdateEdition)
+			admin.DELETE("/editions/:slug", editionHandler.DeleteEdition)
+			admin.GET("/editions/:slug/overrides", editionHandler.ListOverrides)
+			admin.PUT("/editions/:slug/overrides/:article_id", editionHandler.SetOverride)
+			admin.DELETE("/editions/:slug/overrides/:article_id", editionHandler.DeleteOverride)
 		}
 	}
 
+	// Kubernetes probes: liveness only needs the process, readiness the database
+	router.GET("/healthz", middleware.NoStore(), healthHandler.Liveness)
+	router.GET("/readyz", middleware.NoStore(), healthHandler.Readiness)
+
+	// Where generators find the API description of each version
+	router.GET("/.well-known/api-schema", schemaHandler.GetWellKnown)
+
+	// Swagger UI on the OpenAPI document
+	router.GET("/docs", schemaHandler.GetDocs)
+
 	// Root endpoint
 	router.GET("/", func(c *gin.Context) {
 		c.JSON(200, gin.H{
 			"service": "Contextual News Data Retrieval System",
-			"version": "1.0.0",
+			"version": services.ServiceVersion,
 			"status":  "running",
 			"endpoints": gin.H{
 				"health":   "/api/v1/health",
+				"live":     "/healthz",
+				"ready":    "/readyz",
+				"schema":   "/api/v1/schema",
+				"openapi":  "/api/v1/openapi.json",
+				"docs":     "/docs",
 				"category": "/api/v1/news/category?query=<query>",
 				"source":   "/api/v1/news/source?query=<query>",
 				"score":    "/api/v1/news/score?query=<query>",
 				"nearby":   "/api/v1/news/nearby?lat=<lat>&lon=<lon>&radius=<km>&query=<query>",
 				"search":   "/api/v1/news/search?query=<query>",
+				"semantic": "/api/v1/news/semantic-search?query=<query>",
 				"trending": "/api/v1/trending?lat=<lat>&lon=<lon>&radius=<km>&limit=<n>",
 			},
 		})
@@ -118,10 +586,467 @@ func main() {
 
 	// Start server
 	serverAddr := ":" + cfg.ServerPort
-	log.Printf("Starting server on %s", serverAddr)
-	log.Printf("API Documentation: http://localhost%s/", serverAddr)
+	server := &http.Server{
+		Addr:    serverAddr,
+		Handler: router,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("Starting server on %s", serverAddr)
+		log.Printf("API Documentation: http://localhost%s/", serverAddr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	// The gRPC API takes the same keys, tiers and rate limit as REST
+	var grpcServer *grpcapi.Server
+	if cfg.GRPCPort != "" {
+		lis, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+		if err != nil {
+			log.Fatalf("Failed to listen on GRPC_PORT: %v", err)
+		}
+		grpcServer = grpcapi.NewServer(newsService, trendingService, grpcapi.Config{
+			Keys:            apiKeys,
+			DemoKey:         cfg.DemoAPIKey,
+			DemoRateLimit:   cfg.DemoRateLimit,
+			DemoMaxArticles: cfg.DemoMaxArticles,
+			RateLimit:       cfg.RateLimitPerMinute,
+			Limits:          requestLimits,
+			DemoLimits:      demoLimits,
+			Redaction:       redactionRules,
+		})
+		go func() {
+			log.Printf("Starting gRPC server on %s", lis.Addr())
+			if err := grpcServer.Serve(lis); err != nil {
+				serverErr <- err
+			}
+		}()
+	}
+
+	// Wait for a termination signal or a fatal server error
+	signalCtx, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
 
-	if err := router.Run(serverAddr); err != nil {
+	select {
+	case err := <-serverErr:
 		log.Fatalf("Failed to start server: %v", err)
+	case <-signalCtx.Done():
+		log.Println("Shutdown signal received")
 	}
+
+	shutdown(server, grpcServer, stopWorkers, &workers, webhookService, cdnService, shadowMirror,
+		time.Duration(cfg.ShutdownTimeout)*time.Second)
+}
+
+// runIntentEval evaluates intent parsing against the cases in path and
+// prints a report, returning the process exit status
+func runIntentEval(llmService *services.LLMService, path, mode string, minAccuracy float64) int {
+	if !services.IsIntentEvalMode(mode) {
+		log.Printf("Invalid -eval-mode %q: expected live, replay or record", mode)
+		return 2
+	}
+	cases, err := services.LoadIntentEvalCases(path)
+	if err != nil {
+		log.Printf("Intent evaluation failed: %v", err)
+		return 2
+	}
+
+	report := llmService.EvaluateIntents(context.Background(), cases, mode)
+	for _, failure := range report.Failures {
+		switch {
+		case failure.Error != "":
+			fmt.Printf("FAIL %q (%s): %s\n", failure.Query, failure.Source, failure.Error)
+		case failure.Intent != failure.ExpectedIntent:
+			fmt.Printf("FAIL %q (%s): intent %s, expected %s\n", failure.Query, failure.Source, failure.Intent, failure.ExpectedIntent)
+		default:
+			fmt.Printf("FAIL %q (%s): entities %v differ, got %v\n", failure.Query, failure.Source, failure.Mismatched, failure.Entities)
+		}
+	}
+	fmt.Printf("Intent accuracy: %d/%d (%.1f%%)\n", report.IntentCorrect, report.Cases, report.IntentAccuracy()*100)
+	fmt.Printf("Intent and entity accuracy: %d/%d (%.1f%%)\n", report.Passed, report.Cases, report.Accuracy()*100)
+
+	if mode == services.IntentEvalRecord {
+		if err := services.SaveIntentEvalCases(path, cases); err != nil {
+			log.Printf("Intent evaluation failed: %v", err)
+			return 2
+		}
+		log.Printf("Recorded LLM answers in %s", path)
+	}
+	if report.Accuracy() < minAccuracy {
+		return 1
+	}
+	return 0
+}
+
+// checkCacheTimeout bounds the -check cache read
+const checkCacheTimeout = 2 * time.Second
+
+// validateConfig returns every problem with cfg that would stop the server
+// from starting or make it misbehave, without contacting anything
+func validateConfig(cfg *config.Config) []error {
+	problems := append([]error(nil), cfg.Problems...)
+	invalid := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Errorf(format, args...))
+	}
+
+	if cfg.AdminAuthDisabled && cfg.AdminToken != "" {
+		invalid("ADMIN_AUTH_DISABLED would ignore the ADMIN_TOKEN that is set; unset one of them")
+	}
+	if cfg.LLMOffline && cfg.EmbeddingsEnabled {
+		invalid("EMBEDDINGS_ENABLED requires an LLM provider; LLM_PROVIDER is none")
+	}
+	if cfg.SentimentClassifier != services.SentimentClassifierLexicon && cfg.SentimentClassifier != services.SentimentClassifierLLM {
+		invalid("invalid SENTIMENT_CLASSIFIER %q: expected lexicon or llm", cfg.SentimentClassifier)
+	} else if cfg.LLMOffline && cfg.SentimentClassifier == services.SentimentClassifierLLM {
+		invalid("SENTIMENT_CLASSIFIER=llm requires an LLM provider; LLM_PROVIDER is none")
+	}
+	if cfg.UserInterestWeight < 0 || cfg.UserInterestWeight > 1 {
+		invalid("invalid USER_INTEREST_WEIGHT %v: expected a weight between 0 and 1", cfg.UserInterestWeight)
+	}
+	if cfg.MaxLimit < cfg.MaxArticlesReturn {
+		invalid("invalid MAX_LIMIT %d: expected at least MAX_ARTICLES (%d)", cfg.MaxLimit, cfg.MaxArticlesReturn)
+	}
+	if arms, err := services.ParseExperimentArms(cfg.SummaryExperimentArms); err != nil {
+		invalid("invalid SUMMARY_EXPERIMENT_ARMS: %v", err)
+	} else if cfg.SummaryExperimentMinShare < 0 || cfg.SummaryExperimentMinShare*float64(len(arms)) > 1 {
+		invalid("invalid SUMMARY_EXPERIMENT_MIN_SHARE %v: the %d arms' shares must add up to at most 1", cfg.SummaryExperimentMinShare, len(arms))
+	}
+	if !utils.IsNormalization(cfg.ScoreNormalization) {
+		invalid("invalid SCORE_NORMALIZATION %q: expected minmax, zscore or none", cfg.ScoreNormalization)
+	}
+	if cfg.SummaryMaxAttempts < 1 {
+		invalid("invalid SUMMARY_MAX_ATTEMPTS %d: expected at least 1", cfg.SummaryMaxAttempts)
+	}
+	if _, err := services.ParseSearchFields(cfg.SearchFields); err != nil {
+		invalid("invalid SEARCH_FIELDS: %v", err)
+	}
+	if !utils.IsProximityCurve(cfg.TrendingProximityCurve) {
+		invalid("invalid TRENDING_PROXIMITY_CURVE %q: expected linear, exponential or none", cfg.TrendingProximityCurve)
+	}
+	if cfg.SourceDefaultReliability < 0 || cfg.SourceDefaultReliability > 1 {
+		invalid("invalid SOURCE_DEFAULT_RELIABILITY %v: expected a rating between 0 and 1", cfg.SourceDefaultReliability)
+	}
+	if cfg.SourceReliabilityWeight < 0 || cfg.SourceReliabilityWeight > 1 {
+		invalid("invalid SOURCE_RELIABILITY_WEIGHT %v: expected a weight between 0 and 1", cfg.SourceReliabilityWeight)
+	}
+	if _, err := services.ParseFeedComposition(cfg.FeedComposition); err != nil {
+		invalid("invalid FEED_COMPOSITION: %v", err)
+	}
+	if cfg.RequestLimitMode != "clamp" && cfg.RequestLimitMode != "reject" {
+		invalid("invalid REQUEST_LIMIT_MODE %q: expected clamp or reject", cfg.RequestLimitMode)
+	}
+	if _, err := services.ParseRedactionRules(cfg.RedactionRules); err != nil {
+		invalid("invalid REDACTION_RULES: %v", err)
+	}
+	if _, err := middleware.ParseRouteLimits(cfg.RateLimitRoutes); err != nil {
+		invalid("invalid RATE_LIMIT_ROUTES: %v", err)
+	}
+	if _, err := services.NewInvalidationService(cfg); err != nil {
+		invalid("invalid REDIS_URL: %v", err)
+	}
+	if _, err := cache.New(cfg.CacheBackend, cfg.RedisURL); err != nil {
+		invalid("invalid CACHE_BACKEND: %v", err)
+	}
+	if _, err := shadow.New(cfg.ShadowUpstreamURL, cfg.ShadowPercent, time.Second, cfg.ShadowAPIKey); err != nil {
+		invalid("invalid SHADOW_UPSTREAM_URL: %v", err)
+	}
+	if cfg.ContentFetchInterval > 0 {
+		if cfg.ContentFetchBatch <= 0 {
+			invalid("invalid CONTENT_FETCH_BATCH %d: expected at least 1", cfg.ContentFetchBatch)
+		}
+		if cfg.ContentFetchDomainInterval < 0 {
+			invalid("invalid CONTENT_FETCH_DOMAIN_INTERVAL %v: expected 0 or more seconds", cfg.ContentFetchDomainInterval)
+		}
+		if cfg.ContentFetchTimeout <= 0 {
+			invalid("invalid CONTENT_FETCH_TIMEOUT %d: expected at least 1 second", cfg.ContentFetchTimeout)
+		}
+		if strings.TrimSpace(cfg.ContentFetchUserAgent) == "" {
+			invalid("CONTENT_FETCH_USER_AGENT is empty: sites need a user agent to apply their robots.txt rules")
+		}
+	}
+	if cfg.ImageProxyTimeout <= 0 {
+		invalid("invalid IMAGE_PROXY_TIMEOUT %d: expected at least 1 second", cfg.ImageProxyTimeout)
+	}
+	if cfg.ImageProxyMaxBytes <= 0 {
+		invalid("invalid IMAGE_PROXY_MAX_BYTES %d: expected at least 1 byte", cfg.ImageProxyMaxBytes)
+	}
+	if cfg.SLOMinRequests < 0 {
+		invalid("invalid SLO_MIN_REQUESTS %d: expected 0 or more requests", cfg.SLOMinRequests)
+	}
+	return problems
+}
+
+// runCheck validates the configuration, lists the schema migrations the
+// next start would apply and checks that the cache and every LLM provider
+// answer, printing a line per check. It returns the process exit status:
+// 1 when anything failed.
+func runCheck(cfg *config.Config) int {
+	failed := false
+	pass := func(check, format string, args ...interface{}) {
+		fmt.Printf("ok    %-8s %s\n", check, fmt.Sprintf(format, args...))
+	}
+	fail := func(check, format string, args ...interface{}) {
+		failed = true
+		fmt.Printf("FAIL  %-8s %s\n", check, fmt.Sprintf(format, args...))
+	}
+
+	problems := validateConfig(cfg)
+	for _, problem := range problems {
+		fail("config", "%v", problem)
+	}
+	if len(problems) == 0 {
+		pass("config", "all settings valid")
+	}
+
+	schema, err := database.CheckSchema(cfg)
+	switch {
+	case err != nil:
+		fail("database", "%s: %v (check DB_PATH)", cfg.DatabasePath, err)
+	case len(schema.Pending) > 0 && !cfg.MigrateOnStart:
+		fail("database", "%s: schema version %d, %d migrations pending and MIGRATE_ON_START is off (run -migrate up)",
+			cfg.DatabasePath, schema.Version, len(schema.Pending))
+	case len(schema.Pending) > 0:
+		pass("database", "%s: schema version %d, %d migrations pending, applied at the next start",
+			cfg.DatabasePath, schema.Version, len(schema.Pending))
+	case len(schema.Drift) > 0:
+		fail("database", "%s: schema version %d lacks what the models need; a migration is missing", cfg.DatabasePath, schema.Version)
+	default:
+		pass("database", "%s: schema version %d, up to date", cfg.DatabasePath, schema.Version)
+	}
+	for _, m := range schema.Pending {
+		fmt.Printf("        %s\n", m)
+	}
+	for _, missing := range schema.Drift {
+		fmt.Printf("        %s\n", missing)
+	}
+
+	store, err := cache.New(cfg.CacheBackend, cfg.RedisURL)
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), checkCacheTimeout)
+		_, _, err = store.Get(ctx, "check:probe")
+		cancel()
+		if err != nil {
+			fail("cache", "%s backend unreachable: %v (check REDIS_URL)", cfg.CacheBackend, err)
+		} else {
+			pass("cache", "%s backend reachable", cfg.CacheBackend)
+		}
+	}
+
+	switch {
+	case cfg.LLMOffline:
+		pass("llm", "disabled, LLM_PROVIDER is none")
+	case len(cfg.LLMProviders) > 0 && store != nil:
+		// An invalid REDIS_URL is already reported with the configuration
+		invalidation, err := services.NewInvalidationService(cfg)
+		if err != nil {
+			break
+		}
+		llmService := services.NewLLMService(cfg, invalidation, store)
+		for _, result := range llmService.PingProviders(context.Background()) {
+			if result.Reachable {
+				pass("llm", "%s answered (%dms)", result.Name, result.LatencyMs)
+			} else {
+				fail("llm", "%s: %s (check its API key, base URL and model)", result.Name, result.Error)
+			}
+		}
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// runMigrate applies, reverts or lists schema migrations, returning the
+// process exit status
+func runMigrate(cfg *config.Config, command string) int {
+	if command != "up" && command != "down" && command != "status" {
+		log.Printf("Invalid -migrate %q: expected up, down or status", command)
+		return 2
+	}
+	if err := database.Connect(cfg); err != nil {
+		log.Printf("Migration failed: %v", err)
+		return 1
+	}
+	defer database.Close()
+	sqlDB, err := database.GetDB().DB()
+	if err != nil {
+		log.Printf("Migration failed: %v", err)
+		return 1
+	}
+
+	var done []migrations.Migration
+	switch command {
+	case "up":
+		done, err = migrations.Up(sqlDB)
+		for _, m := range done {
+			fmt.Printf("Applied %s\n", m)
+		}
+	case "down":
+		done, err = migrations.Down(sqlDB, 1)
+		for _, m := range done {
+			fmt.Printf("Reverted %s\n", m)
+		}
+	}
+	if err != nil {
+		log.Printf("Migration failed: %v", err)
+		return 1
+	}
+
+	version, dirty, err := migrations.Version(sqlDB)
+	if err != nil {
+		log.Printf("Migration failed: %v", err)
+		return 1
+	}
+	if dirty {
+		fmt.Printf("Schema version %d is dirty: check the schema and force the right version with the migrate CLI\n", version)
+		return 1
+	}
+	pending, err := migrations.Pending(sqlDB)
+	if err != nil {
+		log.Printf("Migration failed: %v", err)
+		return 1
+	}
+	fmt.Printf("Schema version %d, %d migrations pending\n", version, len(pending))
+	for _, m := range pending {
+		fmt.Printf("  %s\n", m)
+	}
+	return 0
+}
+
+// replayOptions are the -replay flags
+type replayOptions struct {
+	logFile     string
+	target      string
+	from, to    string
+	sample      float64
+	speed       float64
+	maxMismatch float64
+}
+
+// runReplay replays a window of logged requests against the target and
+// prints how its statuses and result counts compare, returning the process
+// exit status
+func runReplay(opts replayOptions) int {
+	if opts.target == "" {
+		log.Println("Replay failed: -replay-target is required")
+		return 2
+	}
+	if opts.sample <= 0 || opts.sample > 1 || opts.speed < 0 {
+		log.Println("Replay failed: -replay-sample must be in (0, 1] and -replay-speed non-negative")
+		return 2
+	}
+	from, err := utils.ParseDate(opts.from, false)
+	if err != nil {
+		log.Printf("Replay failed: invalid -replay-from: %v", err)
+		return 2
+	}
+	to, err := utils.ParseDate(opts.to, false)
+	if err != nil {
+		log.Printf("Replay failed: invalid -replay-to: %v", err)
+		return 2
+	}
+
+	file, err := os.Open(opts.logFile)
+	if err != nil {
+		log.Printf("Replay failed: %v", err)
+		return 2
+	}
+	entries, err := replay.Load(file, from, to, opts.sample, rand.New(rand.NewSource(time.Now().UnixNano())))
+	file.Close()
+	if err != nil {
+		log.Printf("Replay failed: %v", err)
+		return 2
+	}
+	if len(entries) == 0 {
+		log.Println("Replay failed: no replayable requests in the window")
+		return 2
+	}
+	log.Printf("Replaying %d requests from %s to %s against %s at %gx",
+		len(entries), entries[0].Time.Format(time.RFC3339), entries[len(entries)-1].Time.Format(time.RFC3339), opts.target, opts.speed)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	results, err := replay.Run(ctx, entries, replay.Options{Target: opts.target, Speed: opts.speed, Timeout: 30 * time.Second})
+	if err != nil && len(results) == 0 {
+		log.Printf("Replay failed: %v", err)
+		return 2
+	}
+	if err != nil {
+		log.Printf("Replay interrupted after %d requests", len(results))
+	}
+
+	report := replay.Summarize(results)
+	fmt.Printf("Requests: %d, status matched: %d (%.1f%%), failed: %d\n",
+		report.Requests, report.StatusMatched, (1-report.MismatchRate())*100, report.Failed)
+	changes := make([]string, 0, len(report.StatusChanges))
+	for change := range report.StatusChanges {
+		changes = append(changes, change)
+	}
+	sort.Strings(changes)
+	for _, change := range changes {
+		fmt.Printf("  status %s: %d\n", change, report.StatusChanges[change])
+	}
+	fmt.Printf("Latency p50: %v, p95: %v\n", report.LatencyP50, report.LatencyP95)
+	fmt.Printf("%-40s %8s %8s %14s %14s %10s\n", "Endpoint", "Requests", "Status=", "Mean results", "Empty", "Changed")
+	for _, e := range report.Endpoints {
+		fmt.Printf("%-40s %8d %8d %6.1f -> %-5.1f %5d -> %-5d %10d\n", e.Endpoint, e.Requests, e.StatusMatched,
+			e.LoggedMean, e.TargetMean, e.LoggedEmpty, e.TargetEmpty, e.ResultsChanged)
+	}
+
+	if report.MismatchRate() > opts.maxMismatch {
+		return 1
+	}
+	return 0
+}
+
+// shutdown drains in-flight requests, stops background workers, waits for
+// pending webhook deliveries and CDN purges and closes the database, all
+// within timeout
+func shutdown(server *http.Server, grpcServer *grpcapi.Server, stopWorkers context.CancelFunc, workers *sync.WaitGroup,
+	webhookService *services.WebhookService, cdnService *services.CDNService, shadowMirror *shadow.Mirror,
+	timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Stop accepting requests and let in-flight ones (and their event writes) finish
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Warning: HTTP server did not drain cleanly: %v", err)
+	}
+	if grpcServer != nil {
+		if err := grpcServer.Shutdown(ctx); err != nil {
+			log.Printf("Warning: gRPC server did not drain cleanly: %v", err)
+		}
+	}
+
+	// Workers exit at their next cancellation check, after any batch in progress
+	stopWorkers()
+	workersDone := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(workersDone)
+	}()
+	select {
+	case <-workersDone:
+	case <-ctx.Done():
+		log.Println("Warning: Background workers did not stop before the shutdown timeout")
+	}
+
+	if err := webhookService.Wait(ctx); err != nil {
+		log.Printf("Warning: Pending webhook deliveries abandoned: %v", err)
+	}
+	if err := cdnService.Wait(ctx); err != nil {
+		log.Printf("Warning: Pending CDN purges abandoned: %v", err)
+	}
+	if err := shadowMirror.Wait(ctx); err != nil {
+		log.Printf("Warning: Pending shadow requests abandoned: %v", err)
+	}
+
+	if err := database.Close(); err != nil {
+		log.Printf("Warning: Failed to close database: %v", err)
+	}
+
+	log.Println("Server stopped")
 }
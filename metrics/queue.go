@@ -0,0 +1,173 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Queue tracks one stage of an async pipeline: how many items wait in it,
+// how long the oldest has waited and how much work it turned away. Stages
+// either hold items in flight (TryEnter) or report a depth they poll from
+// elsewhere (SetDepth).
+type Queue struct {
+	mu       sync.Mutex
+	name     string
+	limit    int64 // 0 means unbounded
+	depth    int64 // polled depth, see SetDepth
+	waiting  time.Time
+	inFlight map[uint64]time.Time
+	nextID   uint64
+	rejected int64
+	dropped  int64
+	now      func() time.Time
+}
+
+// QueueSnapshot is the current state of one pipeline stage
+type QueueSnapshot struct {
+	Name       string  `json:"name"`
+	Depth      int64   `json:"depth"`
+	Limit      int64   `json:"limit"`
+	LagSeconds float64 `json:"lag_seconds"`
+	Saturated  bool    `json:"saturated"`
+	Rejected   int64   `json:"rejected"`
+	Dropped    int64   `json:"dropped"`
+}
+
+// Queue returns the named pipeline stage, creating it on first use. A
+// non-positive limit leaves the stage unbounded.
+func (r *Registry) Queue(name string, limit int) *Queue {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	q, ok := r.queues[name]
+	if !ok {
+		q = &Queue{name: name, inFlight: make(map[uint64]time.Time), now: r.now}
+		r.queues[name] = q
+	}
+	q.mu.Lock()
+	q.limit = max(int64(limit), 0)
+	q.mu.Unlock()
+	return q
+}
+
+// Queues returns a snapshot of every pipeline stage, sorted by name
+func (r *Registry) Queues() []QueueSnapshot {
+	r.mu.Lock()
+	queues := make([]*Queue, 0, len(r.queues))
+	for _, q := range r.queues {
+		queues = append(queues, q)
+	}
+	r.mu.Unlock()
+
+	snapshots := make([]QueueSnapshot, len(queues))
+	for i, q := range queues {
+		snapshots[i] = q.Snapshot()
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Name < snapshots[j].Name
+	})
+	return snapshots
+}
+
+// TryEnter admits one item unless the stage is full, in which case the item
+// counts as rejected. The returned leave func must be called once the item
+// is processed.
+func (q *Queue) TryEnter() (leave func(), ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.saturated() {
+		q.rejected++
+		return nil, false
+	}
+	q.nextID++
+	id := q.nextID
+	q.inFlight[id] = q.now()
+	return func() {
+		q.mu.Lock()
+		delete(q.inFlight, id)
+		q.mu.Unlock()
+	}, true
+}
+
+// SetDepth reports how many items wait in a polled stage. Its lag runs from
+// when the stage was last empty or made progress.
+func (q *Queue) SetDepth(depth int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.depth = depth
+	switch {
+	case depth <= 0:
+		q.waiting = time.Time{}
+	case q.waiting.IsZero():
+		q.waiting = q.now()
+	}
+}
+
+// Progress marks that a polled stage processed items, restarting its lag
+func (q *Queue) Progress() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.depth > 0 {
+		q.waiting = q.now()
+	}
+}
+
+// Reject counts work the stage turned away without taking it in
+func (q *Queue) Reject() {
+	q.mu.Lock()
+	q.rejected++
+	q.mu.Unlock()
+}
+
+// Drop counts n items the stage discarded
+func (q *Queue) Drop(n int) {
+	q.mu.Lock()
+	q.dropped += int64(n)
+	q.mu.Unlock()
+}
+
+// Saturated reports whether the stage is at or over its limit
+func (q *Queue) Saturated() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.saturated()
+}
+
+// Snapshot returns the stage's current state
+func (q *Queue) Snapshot() QueueSnapshot {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	snapshot := QueueSnapshot{
+		Name:      q.name,
+		Depth:     q.total(),
+		Limit:     q.limit,
+		Saturated: q.saturated(),
+		Rejected:  q.rejected,
+		Dropped:   q.dropped,
+	}
+	oldest := q.waiting
+	for _, entered := range q.inFlight {
+		if oldest.IsZero() || entered.Before(oldest) {
+			oldest = entered
+		}
+	}
+	if !oldest.IsZero() {
+		snapshot.LagSeconds = q.now().Sub(oldest).Seconds()
+	}
+	return snapshot
+}
+
+// total is the polled depth plus the items in flight; q.mu must be held
+func (q *Queue) total() int64 {
+	return q.depth + int64(len(q.inFlight))
+}
+
+// saturated reports whether the limit is reached; q.mu must be held
+func (q *Queue) saturated() bool {
+	return q.limit > 0 && q.total() >= q.limit
+}
@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQueueTryEnter(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_700_000_000, 0)}
+	r := newRegistryWithClock(clock.Now)
+	q := r.Queue("events", 2)
+
+	leaveFirst, ok := q.TryEnter()
+	if !ok {
+		t.Fatal("first TryEnter() rejected, expected admitted")
+	}
+	clock.now = clock.now.Add(3 * time.Second)
+	leaveSecond, ok := q.TryEnter()
+	if !ok {
+		t.Fatal("second TryEnter() rejected, expected admitted")
+	}
+	if _, ok := q.TryEnter(); ok {
+		t.Error("TryEnter() over the limit admitted, expected rejected")
+	}
+
+	snapshot := q.Snapshot()
+	if snapshot.Depth != 2 || !snapshot.Saturated || snapshot.Rejected != 1 {
+		t.Errorf("Snapshot() = %+v, expected depth 2, saturated, 1 rejected", snapshot)
+	}
+	if snapshot.LagSeconds != 3 {
+		t.Errorf("LagSeconds = %v, expected the oldest item's 3", snapshot.LagSeconds)
+	}
+
+	leaveFirst()
+	leaveSecond()
+	if snapshot := q.Snapshot(); snapshot.Depth != 0 || snapshot.Saturated || snapshot.LagSeconds != 0 {
+		t.Errorf("Snapshot() after leaving = %+v, expected an empty stage", snapshot)
+	}
+}
+
+func TestQueueSetDepth(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_700_000_000, 0)}
+	r := newRegistryWithClock(clock.Now)
+	q := r.Queue("summaries", 100)
+
+	q.SetDepth(40)
+	clock.now = clock.now.Add(time.Minute)
+	q.SetDepth(120)
+	if snapshot := q.Snapshot(); snapshot.LagSeconds != 60 || !snapshot.Saturated {
+		t.Errorf("Snapshot() = %+v, expected 60s lag and saturated", snapshot)
+	}
+
+	t.Run("Progress restarts the lag", func(t *testing.T) {
+		q.Progress()
+		clock.now = clock.now.Add(10 * time.Second)
+		if got := q.Snapshot().LagSeconds; got != 10 {
+			t.Errorf("LagSeconds = %v, expected 10", got)
+		}
+	})
+
+	t.Run("An empty stage has no lag", func(t *testing.T) {
+		q.SetDepth(0)
+		if snapshot := q.Snapshot(); snapshot.LagSeconds != 0 || snapshot.Saturated {
+			t.Errorf("Snapshot() = %+v, expected no lag", snapshot)
+		}
+	})
+
+	t.Run("Unbounded stages never saturate", func(t *testing.T) {
+		unbounded := r.Queue("ingest", 0)
+		unbounded.SetDepth(1_000_000)
+		unbounded.Drop(3)
+		unbounded.Reject()
+		snapshot := unbounded.Snapshot()
+		if snapshot.Saturated || snapshot.Dropped != 3 || snapshot.Rejected != 1 {
+			t.Errorf("Snapshot() = %+v, expected unsaturated with 3 dropped and 1 rejected", snapshot)
+		}
+	})
+
+	t.Run("Queues are sorted", func(t *testing.T) {
+		queues := r.Queues()
+		if len(queues) != 2 || queues[0].Name != "ingest" || queues[1].Name != "summaries" {
+			t.Errorf("Queues() = %+v, expected [ingest summaries]", queues)
+		}
+	})
+}
@@ -0,0 +1,202 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// RetentionMinutes is how far back per-minute request samples are kept
+const RetentionMinutes = 60
+
+// LatencyBuckets are the upper bounds of the request latency histogram.
+// Latency SLO thresholds are evaluated against these bounds.
+var LatencyBuckets = []time.Duration{
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// minuteSample aggregates the requests of one endpoint during one minute
+type minuteSample struct {
+	minute  int64 // unix minute this sample belongs to
+	total   int64
+	errors  int64
+	latency []int64 // counts per LatencyBuckets bound, plus one overflow slot
+}
+
+// endpointStats holds lifetime totals and a ring of recent minute samples
+type endpointStats struct {
+	total      int64
+	errors     int64
+	latencySum time.Duration
+	minutes    [RetentionMinutes]minuteSample
+}
+
+// Registry collects in-memory request metrics per endpoint
+type Registry struct {
+	mu        sync.Mutex
+	endpoints map[string]*endpointStats
+	queues    map[string]*Queue
+	now       func() time.Time
+}
+
+// NewRegistry creates an empty metrics registry
+func NewRegistry() *Registry {
+	return newRegistryWithClock(time.Now)
+}
+
+// newRegistryWithClock creates a registry with an injectable clock for tests
+func newRegistryWithClock(now func() time.Time) *Registry {
+	return &Registry{
+		endpoints: make(map[string]*endpointStats),
+		queues:    make(map[string]*Queue),
+		now:       now,
+	}
+}
+
+// Observe records one request. Status codes >= 500 count as errors.
+func (r *Registry) Observe(endpoint string, status int, latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats, ok := r.endpoints[endpoint]
+	if !ok {
+		stats = &endpointStats{}
+		r.endpoints[endpoint] = stats
+	}
+
+	isError := status >= 500
+	stats.total++
+	stats.latencySum += latency
+	if isError {
+		stats.errors++
+	}
+
+	minute := r.now().Unix() / 60
+	sample := &stats.minutes[minute%RetentionMinutes]
+	if sample.minute != minute {
+		*sample = minuteSample{minute: minute, latency: make([]int64, len(LatencyBuckets)+1)}
+	}
+	sample.total++
+	if isError {
+		sample.errors++
+	}
+	sample.latency[latencyBucket(latency)]++
+}
+
+// latencyBucket returns the histogram slot for a latency
+func latencyBucket(latency time.Duration) int {
+	for i, bound := range LatencyBuckets {
+		if latency <= bound {
+			return i
+		}
+	}
+	return len(LatencyBuckets)
+}
+
+// WindowStats summarizes an endpoint's requests over a recent window
+type WindowStats struct {
+	Total   int64
+	Errors  int64
+	latency []int64
+}
+
+// CountWithin returns how many requests completed within threshold. Only
+// whole histogram buckets are counted, so the result is conservative when
+// threshold doesn't match a bucket bound.
+func (w WindowStats) CountWithin(threshold time.Duration) int64 {
+	var count int64
+	for i, bound := range LatencyBuckets {
+		if bound > threshold || i >= len(w.latency) {
+			break
+		}
+		count += w.latency[i]
+	}
+	return count
+}
+
+// Window aggregates an endpoint's requests over the last d (at most
+// RetentionMinutes), including the current minute
+func (r *Registry) Window(endpoint string, d time.Duration) WindowStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	window := WindowStats{latency: make([]int64, len(LatencyBuckets)+1)}
+	stats, ok := r.endpoints[endpoint]
+	if !ok {
+		return window
+	}
+
+	minutes := int64(d / time.Minute)
+	if minutes < 1 {
+		minutes = 1
+	}
+	if minutes > RetentionMinutes {
+		minutes = RetentionMinutes
+	}
+
+	oldest := r.now().Unix()/60 - minutes + 1
+	for i := range stats.minutes {
+		sample := &stats.minutes[i]
+		if sample.total == 0 || sample.minute < oldest {
+			continue
+		}
+		window.Total += sample.total
+		window.Errors += sample.errors
+		for j, count := range sample.latency {
+			window.latency[j] += count
+		}
+	}
+	return window
+}
+
+// Endpoints returns the names of all observed endpoints, sorted
+func (r *Registry) Endpoints() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.endpoints))
+	for name := range r.endpoints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// EndpointSnapshot is the lifetime view of one endpoint's metrics
+type EndpointSnapshot struct {
+	Endpoint     string  `json:"endpoint"`
+	Requests     int64   `json:"requests"`
+	Errors       int64   `json:"errors"`
+	AvgLatencyMs float64 `json:"avg_latency_ms"`
+}
+
+// Snapshot returns lifetime totals for every endpoint, sorted by name
+func (r *Registry) Snapshot() []EndpointSnapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snapshots := make([]EndpointSnapshot, 0, len(r.endpoints))
+	for name, stats := range r.endpoints {
+		snapshot := EndpointSnapshot{
+			Endpoint: name,
+			Requests: stats.total,
+			Errors:   stats.errors,
+		}
+		if stats.total > 0 {
+			snapshot.AvgLatencyMs = float64(stats.latencySum) / float64(time.Millisecond) / float64(stats.total)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Endpoint < snapshots[j].Endpoint
+	})
+	return snapshots
+}
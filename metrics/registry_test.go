@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock for window tests
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func TestRegistryObserve(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_700_000_000, 0)}
+	r := newRegistryWithClock(clock.Now)
+
+	r.Observe("GET /a", 200, 40*time.Millisecond)
+	r.Observe("GET /a", 503, 300*time.Millisecond)
+	r.Observe("GET /a", 404, 2*time.Second)
+	r.Observe("GET /b", 200, 10*time.Millisecond)
+
+	t.Run("Endpoints are sorted", func(t *testing.T) {
+		endpoints := r.Endpoints()
+		if len(endpoints) != 2 || endpoints[0] != "GET /a" || endpoints[1] != "GET /b" {
+			t.Errorf("Endpoints() = %v, expected [GET /a GET /b]", endpoints)
+		}
+	})
+
+	t.Run("Only 5xx counts as error", func(t *testing.T) {
+		window := r.Window("GET /a", 5*time.Minute)
+		if window.Total != 3 || window.Errors != 1 {
+			t.Errorf("Window() = %d total / %d errors, expected 3 / 1", window.Total, window.Errors)
+		}
+	})
+
+	t.Run("Latency histogram", func(t *testing.T) {
+		window := r.Window("GET /a", 5*time.Minute)
+		tests := []struct {
+			threshold time.Duration
+			expected  int64
+		}{
+			{50 * time.Millisecond, 1},
+			{500 * time.Millisecond, 2},
+			{400 * time.Millisecond, 1}, // not a bucket bound, rounds down to 250ms
+			{5 * time.Second, 3},
+		}
+		for _, tt := range tests {
+			if got := window.CountWithin(tt.threshold); got != tt.expected {
+				t.Errorf("CountWithin(%v) = %d, expected %d", tt.threshold, got, tt.expected)
+			}
+		}
+	})
+
+	t.Run("Snapshot totals", func(t *testing.T) {
+		snapshot := r.Snapshot()
+		if len(snapshot) != 2 || snapshot[0].Requests != 3 || snapshot[0].Errors != 1 {
+			t.Errorf("Snapshot() = %+v, unexpected totals", snapshot)
+		}
+	})
+}
+
+func TestRegistryWindowExpiry(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_700_000_000, 0)}
+	r := newRegistryWithClock(clock.Now)
+
+	r.Observe("GET /a", 500, time.Millisecond)
+	clock.now = clock.now.Add(10 * time.Minute)
+	r.Observe("GET /a", 200, time.Millisecond)
+
+	if got := r.Window("GET /a", 5*time.Minute); got.Total != 1 || got.Errors != 0 {
+		t.Errorf("5m window = %d total / %d errors, expected 1 / 0", got.Total, got.Errors)
+	}
+	if got := r.Window("GET /a", time.Hour); got.Total != 2 || got.Errors != 1 {
+		t.Errorf("1h window = %d total / %d errors, expected 2 / 1", got.Total, got.Errors)
+	}
+
+	// A full retention period later the ring slot is reused
+	clock.now = clock.now.Add(RetentionMinutes * time.Minute)
+	if got := r.Window("GET /a", time.Hour); got.Total != 0 {
+		t.Errorf("expired window total = %d, expected 0", got.Total)
+	}
+	if got := r.Window("GET /missing", time.Hour); got.Total != 0 {
+		t.Errorf("unknown endpoint total = %d, expected 0", got.Total)
+	}
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"math"
+	"net/http"
+
+	"news-backend/services"
+	"news-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiKeyContextKey holds the full-access key a request was accepted with
+const apiKeyContextKey = "api_key"
+
+// APIKeyConfig configures access to the public API
+type APIKeyConfig struct {
+	Keys            []string // full-access keys
+	DemoKey         string   // key for the demo tier
+	DemoRateLimit   int      // demo requests per minute per client IP
+	DemoMaxArticles int      // articles per demo response
+
+	Limits     services.RequestLimits // cost limits for full-access requests
+	DemoLimits services.RequestLimits // cost limits for demo requests
+
+	Redaction services.RedactionRules // response fields hidden per key
+}
+
+// APIKey requires a key in the X-API-Key header or api_key parameter once
+// any key is configured; with none the API stays open. Requests with the
+// demo key are rate limited per client IP, marked with X-Demo-Tier and run
+// in the demo tier (see services.WithDemoTier). Each request gets its
+// tier's cost limits (see services.WithRequestLimits) and its key's
+// redaction rules (see services.WithRedaction). Routes should share one
+// handler so they share the demo rate limit.
+func APIKey(cfg APIKeyConfig) gin.HandlerFunc {
+	if len(cfg.Keys) == 0 && cfg.DemoKey == "" {
+		return func(c *gin.Context) {
+			ctx := services.WithRedaction(c.Request.Context(), cfg.Redaction.For(services.RedactionSubjectAll))
+			c.Request = c.Request.WithContext(services.WithRequestLimits(ctx, cfg.Limits))
+			c.Next()
+		}
+	}
+
+	keys := make([][]byte, len(cfg.Keys))
+	for i, key := range cfg.Keys {
+		keys[i] = []byte(key)
+	}
+	demoKey := []byte(cfg.DemoKey)
+	limiter := utils.NewClientLimiter(cfg.DemoRateLimit)
+
+	return func(c *gin.Context) {
+		// Responses differ by key, so shared caches must not mix them
+		c.Writer.Header().Add("Vary", "X-API-Key")
+
+		presented := c.GetHeader("X-API-Key")
+		if presented == "" {
+			presented = c.Query("api_key")
+		}
+		for _, key := range keys {
+			if subtle.ConstantTimeCompare([]byte(presented), key) == 1 {
+				c.Set(apiKeyContextKey, presented)
+				ctx := services.WithRedaction(c.Request.Context(), cfg.Redaction.For(presented))
+				c.Request = c.Request.WithContext(services.WithRequestLimits(ctx, cfg.Limits))
+				c.Next()
+				return
+			}
+		}
+
+		if len(demoKey) == 0 || subtle.ConstantTimeCompare([]byte(presented), demoKey) != 1 {
+			abortWithError(c, http.StatusUnauthorized, "Unauthorized", "A valid API key is required")
+			return
+		}
+		if _, wait, ok := limiter.Allow(c.ClientIP()); !ok {
+			c.Header("Retry-After", fmt.Sprintf("%d", int(math.Ceil(wait.Seconds()))))
+			abortWithError(c, http.StatusTooManyRequests, "Rate limit exceeded",
+				fmt.Sprintf("The demo tier allows %d requests per minute", cfg.DemoRateLimit))
+			return
+		}
+
+		c.Header("X-Demo-Tier", "true")
+		ctx := services.WithDemoTier(c.Request.Context(), cfg.DemoMaxArticles)
+		ctx = services.WithRedaction(ctx, cfg.Redaction.For(services.RedactionSubjectDemo))
+		c.Request = c.Request.WithContext(services.WithRequestLimits(ctx, cfg.DemoLimits))
+		c.Next()
+	}
+}
+
+// FullAccess rejects demo-tier requests, for routes that expose user data
+// or write
+func FullAccess() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if services.IsDemoTier(c.Request.Context()) {
+			abortWithError(c, http.StatusForbidden, "Forbidden", "Not available with the demo API key")
+			return
+		}
+		c.Next()
+	}
+}
+
+// abortWithError stops the chain with the standard error body
+func abortWithError(c *gin.Context, code int, err, message string) {
+	c.Header("Cache-Control", "private, no-store")
+	c.Writer.Header().Del("Surrogate-Key")
+	c.AbortWithStatusJSON(code, gin.H{
+		"error":   err,
+		"message": message,
+		"code":    code,
+	})
+}
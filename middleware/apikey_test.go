@@ -0,0 +1,210 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"testing"
+
+	"news-backend/models"
+	"news-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		cfg      APIKeyConfig
+		url      string
+		header   string
+		expected int
+		demo     bool
+	}{
+		{"No keys configured", APIKeyConfig{}, "/news", "", http.StatusOK, false},
+		{"Full key in header", APIKeyConfig{Keys: []string{"full"}, DemoKey: "demo"}, "/news", "full", http.StatusOK, false},
+		{"Demo key in query", APIKeyConfig{Keys: []string{"full"}, DemoKey: "demo", DemoRateLimit: 5}, "/news?api_key=demo", "", http.StatusOK, true},
+		{"Missing key", APIKeyConfig{DemoKey: "demo"}, "/news", "", http.StatusUnauthorized, false},
+		{"Wrong key", APIKeyConfig{Keys: []string{"full"}}, "/news", "nope", http.StatusUnauthorized, false},
+		{"Demo on full-access route", APIKeyConfig{DemoKey: "demo", DemoRateLimit: 5}, "/users", "demo", http.StatusForbidden, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			apiKey := APIKey(tt.cfg)
+			demo := false
+			router.GET("/news", apiKey, func(c *gin.Context) {
+				demo = services.IsDemoTier(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+			router.GET("/users", apiKey, FullAccess(), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", tt.url, nil)
+			if tt.header != "" {
+				req.Header.Set("X-API-Key", tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expected {
+				t.Errorf("status = %d, expected %d", w.Code, tt.expected)
+			}
+			if demo != tt.demo {
+				t.Errorf("demo tier = %v, expected %v", demo, tt.demo)
+			}
+		})
+	}
+}
+
+func TestAPIKeyDemoRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/news", APIKey(APIKeyConfig{DemoKey: "demo", DemoRateLimit: 2}), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for i, expected := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		req := httptest.NewRequest("GET", "/news", nil)
+		req.Header.Set("X-API-Key", "demo")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != expected {
+			t.Fatalf("request %d: status = %d, expected %d", i+1, w.Code, expected)
+		}
+		if expected == http.StatusTooManyRequests && w.Header().Get("Retry-After") == "" {
+			t.Error("Retry-After header missing")
+		}
+	}
+
+	// Other clients have their own bucket
+	req := httptest.NewRequest("GET", "/news", nil)
+	req.Header.Set("X-API-Key", "demo")
+	req.RemoteAddr = "198.51.100.7:1234"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("other client: status = %d, expected %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestAPIKeyRequestLimits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := APIKeyConfig{
+		Keys:       []string{"full"},
+		DemoKey:    "demo",
+		Limits:     services.RequestLimits{MaxRadiusKm: 500, MaxArticles: 10},
+		DemoLimits: services.RequestLimits{MaxRadiusKm: 50, MaxArticles: 3, Reject: true},
+	}
+
+	tests := []struct {
+		name     string
+		cfg      APIKeyConfig
+		key      string
+		radius   float64
+		limit    int
+		expected int
+		radiusKm string // applied radius, empty when rejected
+		clamped  map[string]string
+	}{
+		{"Within limits", cfg, "full", 100, 5, http.StatusOK, "100", nil},
+		{"Clamped", cfg, "full", 20000, 50, http.StatusOK, "500", map[string]string{"radius": "500", "limit": "10"}},
+		{"Demo rejects", cfg, "demo", 100, 1, http.StatusBadRequest, "", nil},
+		{"Open API still limited", APIKeyConfig{Limits: cfg.Limits}, "", 20000, 0, http.StatusOK, "500", map[string]string{"radius": "500"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			var clamped map[string]string
+			router.GET("/trending", APIKey(tt.cfg), func(c *gin.Context) {
+				radius, err := services.LimitRadius(c.Request.Context(), tt.radius)
+				if err == nil {
+					_, err = services.LimitArticles(c.Request.Context(), tt.limit)
+				}
+				if err != nil {
+					c.Status(http.StatusBadRequest)
+					return
+				}
+				clamped = services.ClampedParams(c.Request.Context())
+				c.String(http.StatusOK, "%g", radius)
+			})
+
+			req := httptest.NewRequest("GET", "/trending", nil)
+			req.Header.Set("X-API-Key", tt.key)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expected {
+				t.Fatalf("status = %d, expected %d", w.Code, tt.expected)
+			}
+			if got := w.Body.String(); got != tt.radiusKm {
+				t.Errorf("radius = %q, expected %q", got, tt.radiusKm)
+			}
+			if !reflect.DeepEqual(clamped, tt.clamped) {
+				t.Errorf("clamped = %v, expected %v", clamped, tt.clamped)
+			}
+		})
+	}
+}
+
+func TestAPIKeyRedaction(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	rules, err := services.ParseRedactionRules("*:url@Bloomberg; demo:coordinates; partner:summary,description")
+	if err != nil {
+		t.Fatalf("ParseRedactionRules() error: %v", err)
+	}
+	cfg := APIKeyConfig{Keys: []string{"full", "partner"}, DemoKey: "demo", DemoRateLimit: 5, Redaction: rules}
+
+	tests := []struct {
+		name     string
+		key      string
+		source   string
+		expected []string
+	}{
+		{"Full key, unrestricted source", "full", "Reuters", nil},
+		{"Full key, restricted source", "full", "bloomberg", []string{"url"}},
+		{"Demo key", "demo", "Reuters", []string{"coordinates"}},
+		{"Partner key", "partner", "Bloomberg", []string{"url", "summary", "description"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			var resp models.ArticleResponse
+			router.GET("/news", APIKey(cfg), func(c *gin.Context) {
+				resp = models.ArticleResponse{URL: "https://example.com", SourceName: tt.source, Latitude: 12.9, LLMSummary: "summary"}
+				services.RedactArticle(c.Request.Context(), &resp)
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", "/news", nil)
+			req.Header.Set("X-API-Key", tt.key)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if !reflect.DeepEqual(resp.Redacted, tt.expected) {
+				t.Errorf("redacted = %v, expected %v", resp.Redacted, tt.expected)
+			}
+			if hidden := resp.URL == ""; hidden != slices.Contains(tt.expected, "url") {
+				t.Errorf("url = %q with redacted %v", resp.URL, tt.expected)
+			}
+			if hidden := resp.Latitude == 0; hidden != slices.Contains(tt.expected, "coordinates") {
+				t.Errorf("latitude = %v with redacted %v", resp.Latitude, tt.expected)
+			}
+		})
+	}
+
+	if _, err := services.ParseRedactionRules("demo:email"); err == nil {
+		t.Error("ParseRedactionRules() accepted an unknown field")
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAuth requires "Authorization: Bearer <token>" on every request.
+// Without a token the routes answer 503, unless open is set to leave them
+// unauthenticated for local development.
+func AdminAuth(token string, open bool) gin.HandlerFunc {
+	if open {
+		return func(c *gin.Context) { c.Next() }
+	}
+	if token == "" {
+		return func(c *gin.Context) {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error":   "Service unavailable",
+				"message": "Admin endpoints are disabled until ADMIN_TOKEN is set",
+				"code":    http.StatusServiceUnavailable,
+			})
+		}
+	}
+
+	expected := []byte(token)
+	return func(c *gin.Context) {
+		presented, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(presented), expected) != 1 {
+			c.Header("WWW-Authenticate", `Bearer realm="admin"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error":   "Unauthorized",
+				"message": "A valid admin token is required",
+				"code":    http.StatusUnauthorized,
+			})
+			return
+		}
+		c.Next()
+	}
+}
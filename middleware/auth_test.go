@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAdminAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name          string
+		token         string
+		open          bool
+		authorization string
+		expected      int
+	}{
+		{"Valid token", "secret", false, "Bearer secret", http.StatusOK},
+		{"Wrong token", "secret", false, "Bearer nope", http.StatusUnauthorized},
+		{"Missing header", "secret", false, "", http.StatusUnauthorized},
+		{"Wrong scheme", "secret", false, "Basic secret", http.StatusUnauthorized},
+		{"No token configured", "", false, "", http.StatusServiceUnavailable},
+		{"No token configured, any header", "", false, "Bearer ", http.StatusServiceUnavailable},
+		{"Auth disabled", "", true, "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/admin", AdminAuth(tt.token, tt.open), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", "/admin", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expected {
+				t.Errorf("status = %d, expected %d", w.Code, tt.expected)
+			}
+		})
+	}
+}
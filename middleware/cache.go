@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PublicCache marks responses as cacheable by browsers and CDNs for maxAge
+// seconds and tags them with surrogate keys for targeted purges. Responses
+// vary by Accept, since listings are negotiated between JSON, MessagePack
+// and protobuf. A non-positive maxAge falls back to NoStore.
+func PublicCache(maxAge int, surrogateKeys ...string) gin.HandlerFunc {
+	if maxAge <= 0 {
+		return NoStore()
+	}
+
+	cacheControl := fmt.Sprintf("public, max-age=%d", maxAge)
+	surrogateKey := strings.Join(surrogateKeys, " ")
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", cacheControl)
+		AddVary(c.Writer.Header(), "Accept")
+		if surrogateKey != "" {
+			c.Header("Surrogate-Key", surrogateKey)
+		}
+		c.Next()
+	}
+}
+
+// NoStore keeps personalized, administrative and write responses out of
+// every cache
+func NoStore() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "private, no-store")
+		c.Next()
+	}
+}
+
+// AddVary adds field to the Vary header unless it is already listed
+func AddVary(header http.Header, field string) {
+	for _, value := range header.Values("Vary") {
+		for _, listed := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(listed), field) {
+				return
+			}
+		}
+	}
+	header.Add("Vary", field)
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+)
+
+func TestPublicCacheVariesByAccept(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Compression([]string{"gzip"}, 1024))
+	router.GET("/news", PublicCache(60), ResponseCaching(NewResponseCache(time.Minute, 10)), func(c *gin.Context) {
+		AddVary(c.Writer.Header(), "Accept")
+		if c.NegotiateFormat(binding.MIMEJSON, binding.MIMEMSGPACK) == binding.MIMEMSGPACK {
+			c.Render(http.StatusOK, render.MsgPack{Data: gin.H{"total": 1}})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"total": 1})
+	})
+
+	get := func(accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/news", nil)
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Shared caches must not hand the JSON body to a MessagePack client
+	for _, tt := range []struct {
+		accept, cache, contentType string
+	}{
+		{"application/json", "MISS", binding.MIMEJSON},
+		{"application/json", "HIT", binding.MIMEJSON},
+		{binding.MIMEMSGPACK, "MISS", "application/msgpack"},
+	} {
+		w := get(tt.accept)
+		if got := w.Header().Get("X-Cache"); got != tt.cache {
+			t.Errorf("Accept %s: X-Cache = %q, expected %s", tt.accept, got, tt.cache)
+		}
+		if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, tt.contentType) {
+			t.Errorf("Accept %s: Content-Type = %q, expected %s", tt.accept, got, tt.contentType)
+		}
+		if got := w.Header().Values("Vary"); len(got) != 2 || got[0] != "Accept-Encoding" || got[1] != "Accept" {
+			t.Errorf("Accept %s: Vary = %v, expected Accept-Encoding and Accept once each", tt.accept, got)
+		}
+	}
+}
+
+func TestAddVary(t *testing.T) {
+	header := http.Header{}
+	header.Add("Vary", "Origin, accept")
+	AddVary(header, "Accept")
+	AddVary(header, "X-API-Key")
+	AddVary(header, "X-API-Key")
+	if got := header.Values("Vary"); len(got) != 2 || got[1] != "X-API-Key" {
+		t.Errorf("Vary = %v, expected Origin, accept and X-API-Key once", got)
+	}
+}
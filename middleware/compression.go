@@ -0,0 +1,222 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+// Supported Content-Encoding values
+const (
+	EncodingBrotli = "br"
+	EncodingGzip   = "gzip"
+)
+
+// compressor is the common surface of the gzip and brotli writers
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+var compressorPools = map[string]*sync.Pool{
+	EncodingBrotli: {New: func() interface{} { return brotli.NewWriter(io.Discard) }},
+	EncodingGzip:   {New: func() interface{} { return gzip.NewWriter(io.Discard) }},
+}
+
+// Content types that are already compressed and only get bigger
+var incompressibleTypes = []string{"image/", "video/", "audio/", "application/zip", "application/gzip", "font/woff"}
+
+// Compression middleware compresses responses of at least minSize bytes with
+// the first of algorithms ("br", "gzip") the client accepts. Smaller bodies
+// are sent as-is since encoding overhead outweighs the saving. Unknown
+// algorithm names are ignored; an empty list disables compression.
+func Compression(algorithms []string, minSize int) gin.HandlerFunc {
+	var supported []string
+	for _, algorithm := range algorithms {
+		algorithm = strings.ToLower(strings.TrimSpace(algorithm))
+		if _, ok := compressorPools[algorithm]; ok {
+			supported = append(supported, algorithm)
+		}
+	}
+
+	return func(c *gin.Context) {
+		if len(supported) == 0 || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		encoding := NegotiateEncoding(c.GetHeader("Accept-Encoding"), supported)
+		if encoding == "" {
+			c.Next()
+			return
+		}
+
+		writer := &compressWriter{
+			ResponseWriter: c.Writer,
+			encoding:       encoding,
+			minSize:        minSize,
+		}
+		c.Writer = writer
+		defer writer.finish()
+
+		c.Next()
+	}
+}
+
+// NegotiateEncoding picks the first of supported that the Accept-Encoding
+// header allows (q > 0), honouring "*". Returns "" when none is acceptable.
+func NegotiateEncoding(acceptEncoding string, supported []string) string {
+	accepted := make(map[string]float64)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		fields := strings.Split(part, ";")
+		name := strings.ToLower(strings.TrimSpace(fields[0]))
+		if name == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if value, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = value
+				}
+			}
+		}
+		accepted[name] = q
+	}
+
+	for _, encoding := range supported {
+		q, ok := accepted[encoding]
+		if !ok {
+			q, ok = accepted["*"]
+		}
+		if ok && q > 0 {
+			return encoding
+		}
+	}
+	return ""
+}
+
+// compressWriter buffers the body until it reaches minSize, then switches to
+// streaming it through the compressor. Bodies that never reach the threshold
+// are written uncompressed when the handler returns.
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	minSize  int
+
+	buf         bytes.Buffer
+	compressor  compressor
+	passthrough bool // decided not to compress; write straight through
+	written     bool // the handler wrote to the body, even if it is still buffered
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	w.written = true
+	switch {
+	case w.compressor != nil:
+		return w.compressor.Write(data)
+	case w.passthrough:
+		return w.ResponseWriter.Write(data)
+	}
+
+	w.buf.Write(data)
+	if w.buf.Len() >= w.minSize {
+		if err := w.start(); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Written reports whether the body was written to, so later middleware
+// doesn't write a second response over one still in the buffer
+func (w *compressWriter) Written() bool {
+	return w.written || w.ResponseWriter.Written()
+}
+
+// Flush sends what is buffered so far, uncompressed if the threshold was not
+// reached yet
+func (w *compressWriter) Flush() {
+	if w.compressor == nil && !w.passthrough {
+		w.passthrough = true
+		w.flushBuffer()
+	}
+	if w.compressor != nil {
+		w.compressor.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// start decides whether the response is worth compressing and, if so, sets
+// the headers and routes the buffered body through the compressor
+func (w *compressWriter) start() error {
+	if !w.compressible() {
+		w.passthrough = true
+		return w.flushBuffer()
+	}
+
+	header := w.Header()
+	header.Set("Content-Encoding", w.encoding)
+	header.Del("Content-Length")
+
+	w.compressor = compressorPools[w.encoding].Get().(compressor)
+	w.compressor.Reset(w.ResponseWriter)
+	_, err := w.compressor.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+// compressible reports whether the status and headers allow a new encoding
+func (w *compressWriter) compressible() bool {
+	status := w.Status()
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		return false
+	}
+
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	contentType := header.Get("Content-Type")
+	for _, prefix := range incompressibleTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *compressWriter) flushBuffer() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+// finish writes a below-threshold body as-is or closes the compressor
+func (w *compressWriter) finish() {
+	if w.compressor == nil {
+		w.flushBuffer()
+		return
+	}
+	w.compressor.Close()
+	w.compressor.Reset(io.Discard)
+	compressorPools[w.encoding].Put(w.compressor)
+	w.compressor = nil
+}
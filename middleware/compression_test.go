@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	supported := []string{EncodingBrotli, EncodingGzip}
+	tests := []struct {
+		acceptEncoding string
+		expected       string
+	}{
+		{"gzip, deflate, br", EncodingBrotli},
+		{"gzip", EncodingGzip},
+		{"br;q=0, gzip;q=0.5", EncodingGzip},
+		{"*", EncodingBrotli},
+		{"*;q=0", ""},
+		{"identity", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NegotiateEncoding(tt.acceptEncoding, supported); got != tt.expected {
+			t.Errorf("NegotiateEncoding(%q) = %q, expected %q", tt.acceptEncoding, got, tt.expected)
+		}
+	}
+}
+
+func TestCompression(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	large := strings.Repeat("news ", 1000)
+
+	router := gin.New()
+	router.Use(Compression([]string{"br", "gzip"}, 1024))
+	router.GET("/small", func(c *gin.Context) { c.String(http.StatusOK, "tiny") })
+	router.GET("/large", func(c *gin.Context) { c.String(http.StatusOK, large) })
+	router.GET("/image", func(c *gin.Context) { c.Data(http.StatusOK, "image/png", []byte(large)) })
+	// Answers only when the handler didn't, as error handlers do
+	fallback := func(c *gin.Context) {
+		c.Next()
+		if !c.Writer.Written() {
+			c.String(http.StatusInternalServerError, "fallback")
+		}
+	}
+	router.GET("/buffered", fallback, func(c *gin.Context) { c.String(http.StatusOK, "tiny") })
+	router.GET("/unanswered", fallback, func(c *gin.Context) {})
+
+	request := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Below threshold is sent as-is", func(t *testing.T) {
+		w := request("/small", "gzip")
+		if w.Header().Get("Content-Encoding") != "" || w.Body.String() != "tiny" {
+			t.Errorf("got encoding %q body %q, expected uncompressed", w.Header().Get("Content-Encoding"), w.Body.String())
+		}
+		if w.Header().Get("Vary") != "Accept-Encoding" {
+			t.Errorf("Vary = %q, expected Accept-Encoding", w.Header().Get("Vary"))
+		}
+	})
+
+	t.Run("Gzip", func(t *testing.T) {
+		w := request("/large", "gzip")
+		if w.Header().Get("Content-Encoding") != EncodingGzip {
+			t.Fatalf("Content-Encoding = %q, expected gzip", w.Header().Get("Content-Encoding"))
+		}
+		reader, err := gzip.NewReader(w.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if body, _ := io.ReadAll(reader); string(body) != large {
+			t.Errorf("decompressed body mismatch (%d bytes)", len(body))
+		}
+	})
+
+	t.Run("Brotli preferred", func(t *testing.T) {
+		w := request("/large", "gzip, br")
+		if w.Header().Get("Content-Encoding") != EncodingBrotli {
+			t.Fatalf("Content-Encoding = %q, expected br", w.Header().Get("Content-Encoding"))
+		}
+		body, _ := io.ReadAll(brotli.NewReader(bytes.NewReader(w.Body.Bytes())))
+		if string(body) != large {
+			t.Errorf("decompressed body mismatch (%d bytes)", len(body))
+		}
+	})
+
+	t.Run("Buffered body counts as written", func(t *testing.T) {
+		if w := request("/buffered", "gzip"); w.Code != http.StatusOK || w.Body.String() != "tiny" {
+			t.Errorf("got %d %q, expected only the handler's response", w.Code, w.Body.String())
+		}
+		if w := request("/unanswered", "gzip"); w.Code != http.StatusInternalServerError || w.Body.String() != "fallback" {
+			t.Errorf("got %d %q, expected the fallback response", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("Incompressible content type", func(t *testing.T) {
+		w := request("/image", "gzip")
+		if w.Header().Get("Content-Encoding") != "" || w.Body.Len() != len(large) {
+			t.Errorf("image was compressed (encoding %q)", w.Header().Get("Content-Encoding"))
+		}
+	})
+}
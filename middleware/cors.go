@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// corsAllowedHeaders are the request headers browsers may send
+const corsAllowedHeaders = "Accept, Accept-Encoding, Authorization, Cache-Control, Content-Type, " +
+	"If-None-Match, Origin, X-API-Key, X-Requested-With"
+
+// corsExposedHeaders are the response headers browser code may read
+const corsExposedHeaders = "ETag, Retry-After, X-Cache, X-Demo-Tier, X-RateLimit-Limit, " +
+	"X-RateLimit-Remaining, X-Summary-Tone"
+
+// CORSConfig configures which browser origins may call the API
+type CORSConfig struct {
+	AllowedOrigins []string // "*", exact origins, or one wildcard per entry like "https://*.example.com"
+	AllowedMethods []string
+	MaxAge         int // seconds browsers may cache a preflight response
+}
+
+// CORS answers preflight requests and adds Cross-Origin Resource Sharing
+// headers for allowed origins. With "*" any origin may read responses but
+// without credentials; listed origins are echoed back with credentials
+// allowed. Preflights from other origins get 403.
+func CORS(cfg CORSConfig) gin.HandlerFunc {
+	allowAny := false
+	var origins []string
+	for _, origin := range cfg.AllowedOrigins {
+		origin = strings.TrimSpace(origin)
+		if origin == "*" {
+			allowAny = true
+		}
+		if origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	methods := make([]string, 0, len(cfg.AllowedMethods))
+	for _, method := range cfg.AllowedMethods {
+		if method = strings.TrimSpace(method); method != "" {
+			methods = append(methods, strings.ToUpper(method))
+		}
+	}
+	allowMethods := strings.Join(methods, ", ")
+	maxAge := strconv.Itoa(cfg.MaxAge)
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		header := c.Writer.Header()
+		allowed := origin != "" && (allowAny || originAllowed(origins, origin))
+
+		if allowAny {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Add("Vary", "Origin")
+			if allowed {
+				header.Set("Access-Control-Allow-Origin", origin)
+				header.Set("Access-Control-Allow-Credentials", "true")
+			}
+		}
+		if allowed {
+			header.Set("Access-Control-Expose-Headers", corsExposedHeaders)
+		}
+
+		if c.Request.Method != http.MethodOptions {
+			c.Next()
+			return
+		}
+		if origin != "" && !allowed {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		header.Set("Access-Control-Allow-Methods", allowMethods)
+		header.Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+		if cfg.MaxAge > 0 {
+			header.Set("Access-Control-Max-Age", maxAge)
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+// originAllowed reports whether origin matches one of the allowed patterns
+func originAllowed(patterns []string, origin string) bool {
+	for _, pattern := range patterns {
+		prefix, suffix, wildcard := strings.Cut(pattern, "*")
+		if !wildcard {
+			if strings.EqualFold(pattern, origin) {
+				return true
+			}
+			continue
+		}
+		lower := strings.ToLower(origin)
+		if len(lower) > len(prefix)+len(suffix) &&
+			strings.HasPrefix(lower, strings.ToLower(prefix)) && strings.HasSuffix(lower, strings.ToLower(suffix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// SecurityHeaders sets headers that keep browsers from sniffing, framing or
+// leaking referrers for API responses. A positive hstsMaxAge also sends
+// Strict-Transport-Security; only set it when the API is served over HTTPS.
+func SecurityHeaders(hstsMaxAge int) gin.HandlerFunc {
+	hsts := ""
+	if hstsMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(hstsMaxAge) + "; includeSubDomains"
+	}
+
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Set("X-Frame-Options", "DENY")
+		header.Set("Referrer-Policy", "no-referrer")
+		header.Set("Content-Security-Policy", "default-src 'none'; frame-ancestors 'none'")
+		if hsts != "" {
+			header.Set("Strict-Transport-Security", hsts)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCORS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		origins     []string
+		method      string
+		origin      string
+		status      int
+		allowOrigin string
+		credentials string
+	}{
+		{"Any origin", []string{"*"}, "GET", "https://a.example", http.StatusOK, "*", ""},
+		{"Any origin preflight", []string{"*"}, "OPTIONS", "https://a.example", http.StatusNoContent, "*", ""},
+		{"Listed origin", []string{"https://app.example.com"}, "GET", "https://app.example.com", http.StatusOK, "https://app.example.com", "true"},
+		{"Wildcard origin", []string{" https://*.example.org "}, "OPTIONS", "https://news.example.org", http.StatusNoContent, "https://news.example.org", "true"},
+		{"Wildcard needs a subdomain", []string{"https://*.example.org"}, "OPTIONS", "https://.example.org", http.StatusForbidden, "", ""},
+		{"Unlisted origin", []string{"https://app.example.com"}, "GET", "https://evil.example", http.StatusOK, "", ""},
+		{"Unlisted preflight", []string{"https://app.example.com"}, "OPTIONS", "https://evil.example", http.StatusForbidden, "", ""},
+		{"No origin", []string{"https://app.example.com"}, "GET", "", http.StatusOK, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(CORS(CORSConfig{AllowedOrigins: tt.origins, AllowedMethods: []string{"get", " POST"}, MaxAge: 600}))
+			router.GET("/news", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(tt.method, "/news", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d, expected %d", w.Code, tt.status)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, expected %q", got, tt.allowOrigin)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Credentials"); got != tt.credentials {
+				t.Errorf("Access-Control-Allow-Credentials = %q, expected %q", got, tt.credentials)
+			}
+			if tt.status == http.StatusNoContent {
+				if got := w.Header().Get("Access-Control-Allow-Methods"); got != "GET, POST" {
+					t.Errorf("Access-Control-Allow-Methods = %q", got)
+				}
+				if got := w.Header().Get("Access-Control-Max-Age"); got != "600" {
+					t.Errorf("Access-Control-Max-Age = %q", got)
+				}
+			}
+		})
+	}
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, hstsMaxAge := range []int{0, 31536000} {
+		router := gin.New()
+		router.Use(SecurityHeaders(hstsMaxAge))
+		router.GET("/news", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/news", nil))
+
+		if w.Header().Get("X-Content-Type-Options") != "nosniff" || w.Header().Get("X-Frame-Options") != "DENY" {
+			t.Errorf("missing security headers: %v", w.Header())
+		}
+		hsts := w.Header().Get("Strict-Transport-Security")
+		if (hstsMaxAge > 0) != (hsts == "max-age=31536000; includeSubDomains") {
+			t.Errorf("HSTS_MAX_AGE %d: Strict-Transport-Security = %q", hstsMaxAge, hsts)
+		}
+	}
+}
@@ -1,12 +1,19 @@
 package middleware
 
 import (
+	"fmt"
 	"log"
 	"time"
 
+	"news-backend/metrics"
+
 	"github.com/gin-gonic/gin"
 )
 
+// ResultCountKey is the context key under which handlers store how many
+// articles a response lists, logged for request replays (see package replay)
+const ResultCountKey = "result_count"
+
 // Logger middleware logs request details
 func Logger() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -23,25 +30,28 @@ func Logger() gin.HandlerFunc {
 		clientIP := c.ClientIP()
 		method := c.Request.Method
 
-		log.Printf("[%s] %s %s | Status: %d | Latency: %v | IP: %s | Query: %s",
+		line := fmt.Sprintf("[%s] %s %s | Status: %d | Latency: %v | IP: %s | Query: %s",
 			method, path, query, statusCode, latency, clientIP, query)
+		if count, ok := c.Get(ResultCountKey); ok {
+			line += fmt.Sprintf(" | Results: %d", count)
+		}
+		log.Print(line)
 	}
 }
 
-// CORS middleware handles Cross-Origin Resource Sharing
-func CORS() gin.HandlerFunc {
+// Metrics middleware records status and latency per route into the registry.
+// Routes are keyed by method and path template, e.g. "GET /api/v1/trending".
+func Metrics(registry *metrics.Registry) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
+		start := time.Now()
 
 		c.Next()
+
+		endpoint := c.FullPath()
+		if endpoint == "" {
+			endpoint = "unmatched"
+		}
+		registry.Observe(c.Request.Method+" "+endpoint, c.Writer.Status(), time.Since(start))
 	}
 }
 
@@ -51,6 +61,8 @@ func ErrorHandler() gin.HandlerFunc {
 		defer func() {
 			if err := recover(); err != nil {
 				log.Printf("Panic recovered: %v", err)
+				c.Header("Cache-Control", "private, no-store")
+				c.Writer.Header().Del("Surrogate-Key")
 				c.JSON(500, gin.H{
 					"error":   "Internal Server Error",
 					"message": "An unexpected error occurred",
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+
+	"news-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Mutes hides what the user_id parameter's user muted (loaded via load) from
+// the articles the request fetches. Responses with anything hidden are kept
+// out of shared caches.
+func Mutes(load func(userID string) (*services.MuteSet, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.Query("user_id")
+		if userID == "" {
+			c.Next()
+			return
+		}
+
+		mutes, err := load(userID)
+		if err != nil {
+			abortWithError(c, http.StatusInternalServerError, "Internal error", err.Error())
+			return
+		}
+		if !mutes.Empty() {
+			c.Header("Cache-Control", "private, no-store")
+			c.Writer.Header().Del("Surrogate-Key")
+			c.Request = c.Request.WithContext(services.WithMutes(c.Request.Context(), mutes))
+		}
+		c.Next()
+	}
+}
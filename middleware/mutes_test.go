@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"news-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	load := func(userID string) (*services.MuteSet, error) {
+		switch userID {
+		case "u1":
+			return &services.MuteSet{Sources: []string{"reuters"}}, nil
+		case "broken":
+			return nil, errors.New("database is locked")
+		default:
+			return &services.MuteSet{}, nil
+		}
+	}
+
+	tests := []struct {
+		name    string
+		url     string
+		status  int
+		muted   bool
+		private bool
+	}{
+		{"No user", "/news", http.StatusOK, false, false},
+		{"User with mutes", "/news?user_id=u1", http.StatusOK, true, true},
+		{"User without mutes", "/news?user_id=u2", http.StatusOK, false, false},
+		{"Load failure", "/news?user_id=broken", http.StatusInternalServerError, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			muted := false
+			router.GET("/news", Mutes(load), func(c *gin.Context) {
+				muted = !services.Mutes(c.Request.Context()).Empty()
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", tt.url, nil))
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d, expected %d", w.Code, tt.status)
+			}
+			if muted != tt.muted {
+				t.Errorf("muted = %v, expected %v", muted, tt.muted)
+			}
+			if private := w.Header().Get("Cache-Control") == "private, no-store"; private != tt.private {
+				t.Errorf("private = %v, expected %v", private, tt.private)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"news-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NearPlace resolves the near parameter to the coordinates of the user_id
+// parameter's saved place (via resolve) and sets lat and lon in the URL to
+// them, so handlers binding the query see an ordinary location request
+// (c.Query may still return the original values), and marks the request
+// with services.WithNearPlace so responses don't echo the coordinates. It
+// needs a full-access key, since the response reveals what is near the
+// user's place, and keeps the response out of shared caches.
+func NearPlace(resolve func(userID, name string) (float64, float64, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Query("near")
+		if name == "" {
+			c.Next()
+			return
+		}
+		userID := c.Query("user_id")
+		if userID == "" {
+			abortWithError(c, http.StatusBadRequest, "Invalid request", "near requires user_id")
+			return
+		}
+		if services.IsDemoTier(c.Request.Context()) {
+			abortWithError(c, http.StatusForbidden, "Forbidden", "near is not available with the demo API key")
+			return
+		}
+
+		lat, lon, err := resolve(userID, name)
+		if errors.Is(err, services.ErrPlaceNotFound) {
+			abortWithError(c, http.StatusNotFound, "Not found", "Place not found")
+			return
+		}
+		if err != nil {
+			abortWithError(c, http.StatusInternalServerError, "Internal error", err.Error())
+			return
+		}
+
+		query := c.Request.URL.Query()
+		query.Set("lat", strconv.FormatFloat(lat, 'f', -1, 64))
+		query.Set("lon", strconv.FormatFloat(lon, 'f', -1, 64))
+		c.Request.URL.RawQuery = query.Encode()
+		c.Request = c.Request.WithContext(services.WithNearPlace(c.Request.Context(), name))
+		c.Header("Cache-Control", "private, no-store")
+		c.Writer.Header().Del("Surrogate-Key")
+		c.Next()
+	}
+}
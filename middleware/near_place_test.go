@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"news-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestNearPlace(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	resolve := func(userID, name string) (float64, float64, error) {
+		if userID == "u1" && name == "home" {
+			return 17.385, 78.4867, nil
+		}
+		return 0, 0, services.ErrPlaceNotFound
+	}
+
+	tests := []struct {
+		name    string
+		url     string
+		demo    bool
+		status  int
+		lat     string
+		lon     string
+		private bool
+	}{
+		{"Coordinates pass through", "/nearby?lat=1.5&lon=2.5", false, http.StatusOK, "1.5", "2.5", false},
+		{"Saved place", "/nearby?near=home&user_id=u1", false, http.StatusOK, "17.385", "78.4867", true},
+		{"Place replaces coordinates", "/nearby?near=home&user_id=u1&lat=1&lon=2", false, http.StatusOK, "17.385", "78.4867", true},
+		{"Unknown place", "/nearby?near=work&user_id=u1", false, http.StatusNotFound, "", "", true},
+		{"Missing user", "/nearby?near=home", false, http.StatusBadRequest, "", "", true},
+		{"Demo tier", "/nearby?near=home&user_id=u1", true, http.StatusForbidden, "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			if tt.demo {
+				router.Use(func(c *gin.Context) {
+					c.Request = c.Request.WithContext(services.WithDemoTier(c.Request.Context(), 3))
+				})
+			}
+			lat, lon, place := "", "", ""
+			router.GET("/nearby", NearPlace(resolve), func(c *gin.Context) {
+				// Query binding reads the URL, not gin's query cache
+				query := c.Request.URL.Query()
+				lat, lon = query.Get("lat"), query.Get("lon")
+				place = services.NearPlace(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", tt.url, nil))
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d, expected %d", w.Code, tt.status)
+			}
+			if lat != tt.lat || lon != tt.lon {
+				t.Errorf("lat, lon = %q, %q, expected %q, %q", lat, lon, tt.lat, tt.lon)
+			}
+			if resolved := tt.status == http.StatusOK && tt.private; (place == "home") != resolved {
+				t.Errorf("NearPlace() = %q, expected the place to be marked only when resolved", place)
+			}
+			if private := w.Header().Get("Cache-Control") == "private, no-store"; private != tt.private {
+				t.Errorf("private = %v, expected %v", private, tt.private)
+			}
+		})
+	}
+}
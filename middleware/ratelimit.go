@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"news-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitConfig configures per-client request limits
+type RateLimitConfig struct {
+	PerMinute int            // requests per minute on routes without an override, 0 = unlimited
+	Routes    map[string]int // route pattern (e.g. /api/v1/news/search) -> requests per minute
+}
+
+// RateLimit limits requests per client with token buckets. Clients are
+// identified by the full-access API key the APIKey middleware accepted, or by
+// IP otherwise (including the demo tier, whose key is shared). Routes listed
+// in Routes get their own bucket per client; the rest share one. Responses
+// carry X-RateLimit-Limit and X-RateLimit-Remaining, and 429s Retry-After.
+func RateLimit(cfg RateLimitConfig) gin.HandlerFunc {
+	var fallback *utils.ClientLimiter
+	if cfg.PerMinute > 0 {
+		fallback = utils.NewClientLimiter(cfg.PerMinute)
+	}
+	routes := make(map[string]*utils.ClientLimiter, len(cfg.Routes))
+	for route, perMinute := range cfg.Routes {
+		if perMinute > 0 {
+			routes[route] = utils.NewClientLimiter(perMinute)
+		}
+	}
+
+	return func(c *gin.Context) {
+		limiter, ok := routes[c.FullPath()]
+		if !ok {
+			limiter = fallback
+		}
+		if limiter == nil {
+			c.Next()
+			return
+		}
+
+		client := "ip:" + c.ClientIP()
+		if key := c.GetString(apiKeyContextKey); key != "" {
+			client = "key:" + key
+		}
+
+		remaining, wait, allowed := limiter.Allow(client)
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limiter.PerMinute()))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		if !allowed {
+			c.Header("Retry-After", fmt.Sprintf("%d", int(math.Ceil(wait.Seconds()))))
+			abortWithError(c, http.StatusTooManyRequests, "Rate limit exceeded",
+				fmt.Sprintf("This endpoint allows %d requests per minute", limiter.PerMinute()))
+			return
+		}
+		c.Next()
+	}
+}
+
+// TrustProxies makes router take client IPs, which rate limits count by,
+// from X-Forwarded-For only on requests from one of proxies, a
+// comma-separated list of IPs and CIDRs. Empty trusts none, so clients
+// can't pick a fresh IP per request by sending the header themselves.
+func TrustProxies(router *gin.Engine, proxies string) error {
+	var trusted []string
+	for _, proxy := range strings.Split(proxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trusted = append(trusted, proxy)
+		}
+	}
+	return router.SetTrustedProxies(trusted)
+}
+
+// ParseRouteLimits parses "route=perMinute" pairs separated by commas, e.g.
+// "/api/v1/news/search=30,/api/v1/news/semantic-search=30"
+func ParseRouteLimits(s string) (map[string]int, error) {
+	limits := make(map[string]int)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		route, value, found := strings.Cut(pair, "=")
+		perMinute, err := strconv.Atoi(strings.TrimSpace(value))
+		if !found || err != nil || perMinute < 0 {
+			return nil, fmt.Errorf("invalid route limit %q, expected route=requests_per_minute", pair)
+		}
+		limits[strings.TrimSpace(route)] = perMinute
+	}
+	return limits, nil
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	limits := RateLimit(RateLimitConfig{PerMinute: 3, Routes: map[string]int{"/search": 1}})
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	group := router.Group("", APIKey(APIKeyConfig{Keys: []string{"k1", "k2"}}), limits)
+	group.GET("/search", ok)
+	group.GET("/category", ok)
+	group.GET("/source", ok)
+
+	get := func(path, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("X-API-Key", key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// The override route has its own bucket
+	if w := get("/search", "k1"); w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Limit") != "1" {
+		t.Fatalf("first search: status %d, limit %q", w.Code, w.Header().Get("X-RateLimit-Limit"))
+	}
+	w := get("/search", "k1")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Errorf("second search: status %d, Retry-After %q", w.Code, w.Header().Get("Retry-After"))
+	}
+
+	// Other routes share the default bucket
+	for i, path := range []string{"/category", "/source", "/category"} {
+		w := get(path, "k1")
+		if w.Code != http.StatusOK {
+			t.Fatalf("request %d: status %d", i+1, w.Code)
+		}
+		if expected := []string{"2", "1", "0"}[i]; w.Header().Get("X-RateLimit-Remaining") != expected {
+			t.Errorf("request %d: remaining %q, expected %s", i+1, w.Header().Get("X-RateLimit-Remaining"), expected)
+		}
+	}
+	if w := get("/source", "k1"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("fourth request: status %d", w.Code)
+	}
+
+	// Another key from the same IP has its own budget
+	if w := get("/source", "k2"); w.Code != http.StatusOK {
+		t.Errorf("other key: status %d", w.Code)
+	}
+}
+
+func TestRateLimitIgnoresUntrustedForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, tt := range []struct {
+		name    string
+		proxies string
+		limited bool
+	}{
+		{"no trusted proxies", "", true},
+		{"from a trusted proxy", "192.0.2.0/24", false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			if err := TrustProxies(router, tt.proxies); err != nil {
+				t.Fatalf("TrustProxies() error = %v", err)
+			}
+			router.GET("/news", RateLimit(RateLimitConfig{PerMinute: 1}), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			var codes []int
+			for _, forwarded := range []string{"198.51.100.1", "198.51.100.2"} {
+				req := httptest.NewRequest("GET", "/news", nil)
+				req.RemoteAddr = "192.0.2.10:4000"
+				req.Header.Set("X-Forwarded-For", forwarded)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				codes = append(codes, w.Code)
+			}
+			// A new X-Forwarded-For only gets a fresh bucket through a trusted proxy
+			if limited := codes[1] == http.StatusTooManyRequests; codes[0] != http.StatusOK || limited != tt.limited {
+				t.Errorf("statuses = %v, expected the second request limited: %v", codes, tt.limited)
+			}
+		})
+	}
+
+	if err := TrustProxies(gin.New(), "not-an-ip"); err == nil {
+		t.Error("TrustProxies() accepted an invalid proxy")
+	}
+}
+
+func TestParseRouteLimits(t *testing.T) {
+	limits, err := ParseRouteLimits(" /api/v1/news/search=30, /api/v1/news/semantic-search = 10 ,")
+	expected := map[string]int{"/api/v1/news/search": 30, "/api/v1/news/semantic-search": 10}
+	if err != nil || !reflect.DeepEqual(limits, expected) {
+		t.Errorf("ParseRouteLimits() = %v, %v", limits, err)
+	}
+
+	for _, invalid := range []string{"/search", "/search=fast", "/search=-1"} {
+		if _, err := ParseRouteLimits(invalid); err == nil {
+			t.Errorf("ParseRouteLimits(%q) should fail", invalid)
+		}
+	}
+}
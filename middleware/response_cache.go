@@ -0,0 +1,290 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"news-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// cachedHeaders are the handler-set headers replayed on a cache hit
+var cachedHeaders = []string{"Content-Type", "Cache-Control", "Surrogate-Key"}
+
+type cachedResponse struct {
+	status        int
+	header        http.Header
+	body          []byte
+	etag          string
+	surrogateKeys []string
+	expires       time.Time
+}
+
+// ResponseCache keeps successful, publicly cacheable GET responses in memory
+// for a TTL so repeated identical queries skip the database and LLM. It is
+// safe for concurrent use.
+type ResponseCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*cachedResponse
+}
+
+// NewResponseCache creates a cache holding up to maxEntries responses for
+// ttl. It returns nil (ETags only, no caching) when ttl is not positive.
+func NewResponseCache(ttl time.Duration, maxEntries int) *ResponseCache {
+	if ttl <= 0 {
+		return nil
+	}
+	if maxEntries < 1 {
+		maxEntries = 1
+	}
+	return &ResponseCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*cachedResponse),
+	}
+}
+
+// Len returns the number of cached responses, including expired ones not
+// yet evicted
+func (rc *ResponseCache) Len() int {
+	if rc == nil {
+		return 0
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return len(rc.entries)
+}
+
+// Purge drops every cached response tagged with any of the surrogate keys
+func (rc *ResponseCache) Purge(keys ...string) {
+	if rc == nil || len(keys) == 0 {
+		return
+	}
+	purged := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		purged[key] = true
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for cacheKey, entry := range rc.entries {
+		for _, key := range entry.surrogateKeys {
+			if purged[key] {
+				delete(rc.entries, cacheKey)
+				break
+			}
+		}
+	}
+}
+
+func (rc *ResponseCache) get(key string) *cachedResponse {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	entry, ok := rc.entries[key]
+	if !ok {
+		return nil
+	}
+	if time.Now().After(entry.expires) {
+		delete(rc.entries, key)
+		return nil
+	}
+	return entry
+}
+
+func (rc *ResponseCache) put(key string, entry *cachedResponse) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if _, ok := rc.entries[key]; !ok && len(rc.entries) >= rc.maxEntries {
+		rc.evict()
+	}
+	rc.entries[key] = entry
+}
+
+// evict drops expired entries, or the one closest to expiring if none are
+func (rc *ResponseCache) evict() {
+	now := time.Now()
+	var oldestKey string
+	var oldest time.Time
+	for key, entry := range rc.entries {
+		if now.After(entry.expires) {
+			delete(rc.entries, key)
+			continue
+		}
+		if oldestKey == "" || entry.expires.Before(oldest) {
+			oldestKey, oldest = key, entry.expires
+		}
+	}
+	if len(rc.entries) >= rc.maxEntries {
+		delete(rc.entries, oldestKey)
+	}
+}
+
+// ResponseCaching tags GET responses with a content ETag, answers a
+// matching If-None-Match with 304, and serves repeated requests from cache.
+// Only 200 responses without a private or no-store Cache-Control are cached.
+// A nil cache still sets ETags. X-Cache reports HIT or MISS.
+func ResponseCaching(cache *ResponseCache) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		key := responseCacheKey(c)
+		if cache != nil {
+			if entry := cache.get(key); entry != nil {
+				header := c.Writer.Header()
+				for name, values := range entry.header {
+					header[name] = values
+				}
+				header.Set("X-Cache", "HIT")
+				writeWithETag(c, entry.status, entry.etag, entry.body)
+				c.Abort()
+				return
+			}
+		}
+
+		buffer := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = buffer
+		func() {
+			// Restore the writer even on panic so Recovery can respond
+			defer func() { c.Writer = buffer.ResponseWriter }()
+			c.Next()
+		}()
+
+		status := buffer.Status()
+		body := buffer.body.Bytes()
+		// Weak, since Compression may re-encode the bytes hashed here
+		etag := ""
+		if status == http.StatusOK {
+			sum := sha256.Sum256(body)
+			etag = `W/"` + hex.EncodeToString(sum[:16]) + `"`
+		}
+
+		header := c.Writer.Header()
+		cacheControl := header.Get("Cache-Control")
+		if cache != nil && etag != "" &&
+			!strings.Contains(cacheControl, "private") && !strings.Contains(cacheControl, "no-store") {
+			entry := &cachedResponse{
+				status:        status,
+				header:        make(http.Header),
+				body:          append([]byte(nil), body...),
+				etag:          etag,
+				surrogateKeys: strings.Fields(header.Get("Surrogate-Key")),
+				expires:       time.Now().Add(cache.ttl),
+			}
+			for _, name := range cachedHeaders {
+				if values := header.Values(name); len(values) > 0 {
+					entry.header[name] = values
+				}
+			}
+			cache.put(key, entry)
+			header.Set("X-Cache", "MISS")
+		}
+
+		writeWithETag(c, status, etag, body)
+	}
+}
+
+// writeWithETag sends body, or 304 when the request already has this ETag
+func writeWithETag(c *gin.Context, status int, etag string, body []byte) {
+	if etag != "" {
+		c.Header("ETag", etag)
+		if etagMatches(c.GetHeader("If-None-Match"), etag) {
+			c.Writer.WriteHeader(http.StatusNotModified)
+			c.Writer.WriteHeaderNow()
+			return
+		}
+	}
+	c.Writer.WriteHeader(status)
+	c.Writer.Write(body)
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, using
+// weak comparison as RFC 9110 requires for GET
+func etagMatches(ifNoneMatch, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// responseCacheKey identifies equivalent requests: the path, the query
+// parameters sorted with empty values dropped, the Accept header, the API
+// tier and the redaction rules (the key itself is left out so full keys
+// redacted alike share entries)
+func responseCacheKey(c *gin.Context) string {
+	query := c.Request.URL.Query()
+	names := make([]string, 0, len(query))
+	for name := range query {
+		if name != "api_key" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(c.Request.URL.Path)
+	separator := "?"
+	for _, name := range names {
+		values := query[name]
+		sort.Strings(values)
+		for _, value := range values {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			b.WriteString(separator)
+			b.WriteString(url.QueryEscape(name))
+			b.WriteByte('=')
+			b.WriteString(url.QueryEscape(value))
+			separator = "&"
+		}
+	}
+	b.WriteString("|")
+	b.WriteString(c.GetHeader("Accept"))
+	if services.IsDemoTier(c.Request.Context()) {
+		b.WriteString("|demo")
+	}
+	if signature := services.RedactionSignature(c.Request.Context()); signature != "" {
+		b.WriteString("|redact=")
+		b.WriteString(signature)
+	}
+	return b.String()
+}
+
+// bufferedWriter holds the response body so headers can still be set after
+// the handler runs
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+func (w *bufferedWriter) Written() bool {
+	return w.body.Len() > 0
+}
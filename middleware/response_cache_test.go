@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestResponseCaching(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cache := NewResponseCache(time.Minute, 10)
+	calls := 0
+	router := gin.New()
+	router.GET("/search", ResponseCaching(cache), func(c *gin.Context) {
+		calls++
+		c.Header("Surrogate-Key", "news article-1")
+		if c.Query("user_id") != "" {
+			c.Header("Cache-Control", "private, no-store")
+		}
+		c.JSON(http.StatusOK, gin.H{"query": c.Query("query")})
+	})
+
+	get := func(url, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", url, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := get("/search?query=cricket&from=", "")
+	if first.Code != http.StatusOK || first.Header().Get("X-Cache") != "MISS" {
+		t.Fatalf("first request: status %d, X-Cache %q", first.Code, first.Header().Get("X-Cache"))
+	}
+	etag := first.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("ETag missing")
+	}
+
+	// Parameter order and empty parameters don't change the key
+	second := get("/search?from=&query=cricket", "")
+	if second.Header().Get("X-Cache") != "HIT" || second.Body.String() != first.Body.String() || calls != 1 {
+		t.Errorf("second request: X-Cache %q, handler calls %d", second.Header().Get("X-Cache"), calls)
+	}
+	if second.Header().Get("Content-Type") != first.Header().Get("Content-Type") {
+		t.Errorf("Content-Type on hit = %q", second.Header().Get("Content-Type"))
+	}
+
+	if w := get("/search?query=cricket", etag); w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("If-None-Match: status %d, body %q; expected 304 without body", w.Code, w.Body.String())
+	}
+
+	// Private responses are never stored
+	get("/search?query=cricket&user_id=u1", "")
+	if w := get("/search?query=cricket&user_id=u1", ""); w.Header().Get("X-Cache") != "" || calls != 3 {
+		t.Errorf("private response: X-Cache %q, handler calls %d", w.Header().Get("X-Cache"), calls)
+	}
+
+	cache.Purge("article-2")
+	if cache.Len() != 1 {
+		t.Errorf("Len() after unrelated purge = %d, expected 1", cache.Len())
+	}
+	cache.Purge("article-1")
+	if w := get("/search?query=cricket", ""); w.Header().Get("X-Cache") != "MISS" {
+		t.Errorf("after purge: X-Cache %q, expected MISS", w.Header().Get("X-Cache"))
+	}
+}
+
+func TestResponseCacheEviction(t *testing.T) {
+	cache := NewResponseCache(time.Minute, 2)
+	for _, key := range []string{"a", "b", "c"} {
+		cache.put(key, &cachedResponse{expires: time.Now().Add(time.Minute)})
+		time.Sleep(time.Millisecond)
+	}
+	if cache.Len() != 2 || cache.get("a") != nil || cache.get("c") == nil {
+		t.Errorf("expected the entry closest to expiring to be evicted, have %d entries", cache.Len())
+	}
+
+	if NewResponseCache(0, 10) != nil {
+		t.Error("NewResponseCache() with no TTL should disable caching")
+	}
+}
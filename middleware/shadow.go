@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"bytes"
+
+	"news-backend/shadow"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Shadow middleware mirrors a sample of GET requests to the mirror's
+// upstream once the primary response is complete, for offline comparison.
+// A nil mirror disables it.
+func Shadow(mirror *shadow.Mirror) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !mirror.Sample(c.Request) {
+			c.Next()
+			return
+		}
+
+		recorder := &bodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		mirror.Send(c.Request, recorder.Status(), recorder.body.Bytes())
+	}
+}
+
+// bodyRecorder keeps a copy of everything written to the response
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
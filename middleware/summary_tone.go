@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+
+	"news-backend/models"
+	"news-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SummaryTone sets the tone summaries are written in for the request: the
+// tone parameter, else the stored preference of the user_id parameter (via
+// preferred, which may be nil), else neutral. Responses following a stored
+// preference are kept out of shared caches. The tone used is echoed in
+// X-Summary-Tone.
+func SummaryTone(preferred func(userID string) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tone := c.Query("tone")
+		if tone != "" && !models.IsValidSummaryTone(tone) {
+			abortWithError(c, http.StatusBadRequest, "Invalid request",
+				"tone must be one of: simple, neutral, detailed, kid_friendly")
+			return
+		}
+		if userID := c.Query("user_id"); tone == "" && userID != "" && preferred != nil {
+			tone = preferred(userID)
+			c.Header("Cache-Control", "private, no-store")
+			c.Writer.Header().Del("Surrogate-Key")
+		}
+		if tone == "" {
+			tone = models.SummaryToneNeutral
+		}
+
+		c.Header("X-Summary-Tone", tone)
+		c.Request = c.Request.WithContext(services.WithSummaryTone(c.Request.Context(), tone))
+		c.Next()
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"news-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSummaryTone(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	preferred := func(userID string) string {
+		if userID == "u1" {
+			return "kid_friendly"
+		}
+		return "neutral"
+	}
+
+	tests := []struct {
+		name    string
+		url     string
+		status  int
+		tone    string
+		private bool
+	}{
+		{"Default", "/news", http.StatusOK, "neutral", false},
+		{"Parameter", "/news?tone=detailed", http.StatusOK, "detailed", false},
+		{"Parameter beats preference", "/news?tone=simple&user_id=u1", http.StatusOK, "simple", false},
+		{"Preference", "/news?user_id=u1", http.StatusOK, "kid_friendly", true},
+		{"Unknown tone", "/news?tone=sarcastic", http.StatusBadRequest, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			tone := ""
+			router.GET("/news", SummaryTone(preferred), func(c *gin.Context) {
+				tone = services.SummaryTone(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", tt.url, nil))
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d, expected %d", w.Code, tt.status)
+			}
+			if tone != tt.tone {
+				t.Errorf("tone = %q, expected %q", tone, tt.tone)
+			}
+			if private := w.Header().Get("Cache-Control") == "private, no-store"; private != tt.private {
+				t.Errorf("private = %v, expected %v", private, tt.private)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+-- The schema GORM AutoMigrate created before versioned migrations: the
+-- articles and user_events tables. IF NOT EXISTS lets those databases adopt
+-- this version as is; the columns and tables added since are migration 2.
+
+CREATE TABLE IF NOT EXISTS articles (
+    id text,
+    title text,
+    description text,
+    url text,
+    publication_date datetime,
+    source_name text,
+    category text,
+    relevance_score real,
+    latitude real,
+    longitude real,
+    llm_summary text,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_location ON articles (latitude, longitude);
+CREATE INDEX IF NOT EXISTS idx_relevance ON articles (relevance_score);
+CREATE INDEX IF NOT EXISTS idx_category ON articles (category);
+CREATE INDEX IF NOT EXISTS idx_source ON articles (source_name);
+CREATE INDEX IF NOT EXISTS idx_pub_date ON articles (publication_date);
+CREATE INDEX IF NOT EXISTS idx_title ON articles (title);
+
+CREATE TABLE IF NOT EXISTS user_events (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    article_id text,
+    user_id text,
+    event_type text,
+    latitude real,
+    longitude real,
+    timestamp datetime
+);
+CREATE INDEX IF NOT EXISTS idx_timestamp ON user_events (timestamp);
+CREATE INDEX IF NOT EXISTS idx_event_type ON user_events (event_type);
+CREATE INDEX IF NOT EXISTS idx_user_events ON user_events (user_id);
+CREATE INDEX IF NOT EXISTS idx_article_events ON user_events (article_id);
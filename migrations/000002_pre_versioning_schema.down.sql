@@ -0,0 +1,46 @@
+DROP TABLE IF EXISTS user_interests;
+DROP TABLE IF EXISTS user_mutes;
+DROP TABLE IF EXISTS summary_experiment_arms;
+DROP TABLE IF EXISTS summary_experiment_assignments;
+DROP TABLE IF EXISTS saved_places;
+DROP TABLE IF EXISTS query_sessions;
+DROP TABLE IF EXISTS topic_follows;
+DROP TABLE IF EXISTS event_rollups;
+DROP TABLE IF EXISTS edition_overrides;
+DROP TABLE IF EXISTS editions;
+DROP TABLE IF EXISTS trending_scores;
+DROP TABLE IF EXISTS feed_signals;
+DROP TABLE IF EXISTS alert_matches;
+DROP TABLE IF EXISTS keyword_alerts;
+DROP TABLE IF EXISTS user_preferences;
+DROP TABLE IF EXISTS llm_audits;
+DROP TABLE IF EXISTS feedbacks;
+DROP TABLE IF EXISTS user_links;
+DROP TABLE IF EXISTS sources;
+DROP TABLE IF EXISTS article_embeddings;
+DROP TABLE IF EXISTS article_categories;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS article_topics;
+DROP TABLE IF EXISTS topics;
+
+DROP INDEX IF EXISTS idx_device_events;
+ALTER TABLE user_events DROP COLUMN schema_version;
+ALTER TABLE user_events DROP COLUMN platform;
+ALTER TABLE user_events DROP COLUMN dwell_ms;
+ALTER TABLE user_events DROP COLUMN device_id;
+
+DROP INDEX IF EXISTS idx_current_relevance;
+DROP INDEX IF EXISTS idx_story;
+DROP INDEX IF EXISTS idx_sentiment;
+ALTER TABLE articles DROP COLUMN image_url;
+ALTER TABLE articles DROP COLUMN ingest_source;
+ALTER TABLE articles DROP COLUMN attribution;
+ALTER TABLE articles DROP COLUMN license;
+ALTER TABLE articles DROP COLUMN publisher;
+ALTER TABLE articles DROP COLUMN tone;
+ALTER TABLE articles DROP COLUMN sentiment;
+ALTER TABLE articles DROP COLUMN story_id;
+ALTER TABLE articles DROP COLUMN exclude_from_trending;
+ALTER TABLE articles DROP COLUMN content_hash;
+ALTER TABLE articles DROP COLUMN current_relevance;
+ALTER TABLE articles DROP COLUMN topics_hash;
@@ -0,0 +1,293 @@
+-- The columns and tables added to the baseline schema while GORM
+-- AutoMigrate still managed it
+
+ALTER TABLE articles ADD COLUMN topics_hash text;
+ALTER TABLE articles ADD COLUMN current_relevance real;
+ALTER TABLE articles ADD COLUMN content_hash text;
+ALTER TABLE articles ADD COLUMN exclude_from_trending numeric DEFAULT false;
+ALTER TABLE articles ADD COLUMN story_id text;
+ALTER TABLE articles ADD COLUMN sentiment text;
+ALTER TABLE articles ADD COLUMN tone text;
+ALTER TABLE articles ADD COLUMN publisher text;
+ALTER TABLE articles ADD COLUMN license text;
+ALTER TABLE articles ADD COLUMN attribution text;
+ALTER TABLE articles ADD COLUMN ingest_source text;
+ALTER TABLE articles ADD COLUMN image_url text;
+CREATE INDEX IF NOT EXISTS idx_sentiment ON articles (sentiment);
+CREATE INDEX IF NOT EXISTS idx_story ON articles (story_id);
+CREATE INDEX IF NOT EXISTS idx_current_relevance ON articles (current_relevance);
+
+-- Relevance decays from the stored score
+UPDATE articles SET current_relevance = relevance_score;
+
+ALTER TABLE user_events ADD COLUMN device_id text;
+ALTER TABLE user_events ADD COLUMN dwell_ms integer;
+ALTER TABLE user_events ADD COLUMN platform text DEFAULT 'unknown';
+ALTER TABLE user_events ADD COLUMN schema_version integer DEFAULT 1;
+CREATE INDEX IF NOT EXISTS idx_device_events ON user_events (device_id);
+
+-- Events recorded before devices were tracked came from the user's device
+UPDATE user_events SET device_id = user_id;
+
+CREATE TABLE IF NOT EXISTS topics (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    name text,
+    created_at datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_topics_name ON topics (name);
+
+CREATE TABLE IF NOT EXISTS article_topics (
+    article_id text,
+    topic_id integer,
+    PRIMARY KEY (article_id,topic_id),
+    CONSTRAINT fk_article_topics_article FOREIGN KEY (article_id) REFERENCES articles(id),
+    CONSTRAINT fk_article_topics_topic FOREIGN KEY (topic_id) REFERENCES topics(id)
+);
+
+CREATE TABLE IF NOT EXISTS categories (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    name text,
+    created_at datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_name ON categories (name);
+
+CREATE TABLE IF NOT EXISTS article_categories (
+    article_id text,
+    category_id integer,
+    PRIMARY KEY (article_id,category_id),
+    CONSTRAINT fk_article_categories_article FOREIGN KEY (article_id) REFERENCES articles(id),
+    CONSTRAINT fk_article_categories_category FOREIGN KEY (category_id) REFERENCES categories(id)
+);
+
+CREATE TABLE IF NOT EXISTS article_embeddings (
+    article_id text,
+    model text,
+    dimensions integer,
+    vector blob,
+    created_at datetime,
+    PRIMARY KEY (article_id)
+);
+
+CREATE TABLE IF NOT EXISTS sources (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    name text,
+    enabled numeric DEFAULT true,
+    exclude_from_trending numeric DEFAULT false,
+    reliability_score real,
+    connector text,
+    config text,
+    rules text,
+    publisher text,
+    license text,
+    attribution text,
+    created_at datetime,
+    updated_at datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sources_name ON sources (name);
+
+CREATE TABLE IF NOT EXISTS user_links (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    canonical_user_id text,
+    linked_user_id text,
+    method text,
+    created_at datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_links_linked_user_id ON user_links (linked_user_id);
+CREATE INDEX IF NOT EXISTS idx_canonical_user ON user_links (canonical_user_id);
+
+CREATE TABLE IF NOT EXISTS feedbacks (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    article_id text,
+    user_id text,
+    type text,
+    query text,
+    endpoint text,
+    comment text,
+    status text DEFAULT 'open',
+    resolution text,
+    created_at datetime,
+    resolved_at datetime
+);
+CREATE INDEX IF NOT EXISTS idx_feedback_status ON feedbacks (status);
+CREATE INDEX IF NOT EXISTS idx_feedback_type ON feedbacks (type);
+CREATE INDEX IF NOT EXISTS idx_feedback_article ON feedbacks (article_id);
+
+CREATE TABLE IF NOT EXISTS llm_audits (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    purpose text,
+    provider text,
+    model text,
+    prompt text,
+    response text,
+    error text,
+    latency_ms integer,
+    total_tokens integer
+);
+CREATE INDEX IF NOT EXISTS idx_llm_audit_purpose ON llm_audits (purpose);
+CREATE INDEX IF NOT EXISTS idx_llm_audit_created ON llm_audits (created_at);
+
+CREATE TABLE IF NOT EXISTS user_preferences (
+    user_id text,
+    summary_tone text,
+    save_places numeric,
+    updated_at datetime,
+    PRIMARY KEY (user_id)
+);
+
+CREATE TABLE IF NOT EXISTS keyword_alerts (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    keyword text,
+    kind text,
+    editor text,
+    enabled numeric,
+    created_at datetime,
+    updated_at datetime
+);
+
+CREATE TABLE IF NOT EXISTS alert_matches (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    alert_id integer,
+    article_id text,
+    keyword text,
+    editor text,
+    title text,
+    url text,
+    source_name text,
+    status text DEFAULT 'new',
+    created_at datetime,
+    triaged_at datetime
+);
+CREATE INDEX IF NOT EXISTS idx_alert_match_status ON alert_matches (status);
+CREATE INDEX IF NOT EXISTS idx_alert_match_article_id ON alert_matches (article_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_alert_match_article ON alert_matches (alert_id, article_id);
+
+CREATE TABLE IF NOT EXISTS feed_signals (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    user_id text,
+    article_id text,
+    signal text,
+    created_at datetime,
+    updated_at datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_feed_signal_user_article ON feed_signals (user_id, article_id);
+
+CREATE TABLE IF NOT EXISTS trending_scores (
+    lat_cell integer,
+    lon_cell integer,
+    article_id text,
+    latitude real,
+    longitude real,
+    events integer,
+    views integer,
+    clicks integer,
+    shares integer,
+    users integer,
+    weight real,
+    computed_at datetime,
+    PRIMARY KEY (lat_cell,lon_cell,article_id)
+);
+CREATE INDEX IF NOT EXISTS idx_trending_score_location ON trending_scores (latitude, longitude);
+
+CREATE TABLE IF NOT EXISTS editions (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    slug text,
+    name text,
+    latitude real,
+    longitude real,
+    radius_km real,
+    enabled numeric,
+    created_at datetime,
+    updated_at datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_editions_slug ON editions (slug);
+
+CREATE TABLE IF NOT EXISTS edition_overrides (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    edition_id integer,
+    article_id text,
+    action text,
+    position integer,
+    created_at datetime,
+    updated_at datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_edition_override_article ON edition_overrides (edition_id, article_id);
+
+CREATE TABLE IF NOT EXISTS event_rollups (
+    article_id text,
+    hour datetime,
+    event_type text,
+    count integer,
+    users integer,
+    PRIMARY KEY (article_id,hour,event_type)
+);
+CREATE INDEX IF NOT EXISTS idx_event_rollup_hour ON event_rollups (hour);
+
+CREATE TABLE IF NOT EXISTS topic_follows (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    user_id text,
+    topic_id integer,
+    created_at datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_topic_follow_user_topic ON topic_follows (user_id, topic_id);
+
+CREATE TABLE IF NOT EXISTS query_sessions (
+    id text,
+    query text,
+    intent text,
+    entities text,
+    turns integer,
+    created_at datetime,
+    updated_at datetime,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_query_sessions_updated_at ON query_sessions (updated_at);
+
+CREATE TABLE IF NOT EXISTS saved_places (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    user_id text,
+    name text,
+    latitude real,
+    longitude real,
+    created_at datetime,
+    updated_at datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_saved_place_user_name ON saved_places (user_id, name);
+
+CREATE TABLE IF NOT EXISTS summary_experiment_assignments (
+    user_id text,
+    tone text,
+    assigned_at datetime,
+    PRIMARY KEY (user_id)
+);
+
+CREATE TABLE IF NOT EXISTS summary_experiment_arms (
+    tone text,
+    assignments integer,
+    views integer,
+    engagements integer,
+    bad_summaries integer,
+    stopped numeric,
+    updated_at datetime,
+    PRIMARY KEY (tone)
+);
+
+CREATE TABLE IF NOT EXISTS user_mutes (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    user_id text,
+    kind text,
+    value text,
+    created_at datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_mute ON user_mutes (user_id, kind, value);
+
+CREATE TABLE IF NOT EXISTS user_interests (
+    user_id text,
+    model text,
+    dimensions integer,
+    vector blob,
+    weight real,
+    events integer,
+    updated_at datetime,
+    PRIMARY KEY (user_id)
+);
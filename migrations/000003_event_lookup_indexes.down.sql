@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_articles_missing_sentiment;
+DROP INDEX IF EXISTS idx_articles_missing_summary;
+DROP INDEX IF EXISTS idx_user_events_article_time;
+DROP INDEX IF EXISTS idx_user_events_user_time;
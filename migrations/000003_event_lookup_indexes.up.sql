@@ -0,0 +1,11 @@
+-- A user's recent events (interest profiles, personalization) and an
+-- article's events since the rollup watermark (trending, exports) were
+-- read through single-column indexes
+CREATE INDEX IF NOT EXISTS idx_user_events_user_time ON user_events (user_id, timestamp);
+CREATE INDEX IF NOT EXISTS idx_user_events_article_time ON user_events (article_id, timestamp);
+
+-- The summary and sentiment workers poll for articles still missing one
+CREATE INDEX IF NOT EXISTS idx_articles_missing_summary ON articles (id)
+    WHERE llm_summary = '' OR llm_summary IS NULL;
+CREATE INDEX IF NOT EXISTS idx_articles_missing_sentiment ON articles (id)
+    WHERE sentiment = '' OR sentiment IS NULL;
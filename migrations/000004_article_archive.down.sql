@@ -0,0 +1,18 @@
+-- Archived articles move back; soft-deleted ones are gone for good
+INSERT INTO articles (id, title, description, url, publication_date, source_name, category,
+    topics_hash, relevance_score, current_relevance, latitude, longitude, llm_summary,
+    content_hash, exclude_from_trending, story_id, sentiment, tone, publisher, license,
+    attribution, ingest_source, image_url, deleted_at)
+SELECT id, title, description, url, publication_date, source_name, category,
+    topics_hash, relevance_score, current_relevance, latitude, longitude, llm_summary,
+    content_hash, exclude_from_trending, story_id, sentiment, tone, publisher, license,
+    attribution, ingest_source, image_url, deleted_at
+FROM articles_archive WHERE id NOT IN (SELECT id FROM articles);
+DROP INDEX IF EXISTS idx_articles_archive_pub_date;
+DROP TABLE IF EXISTS articles_archive;
+
+DELETE FROM article_categories WHERE article_id IN (SELECT id FROM articles WHERE deleted_at IS NOT NULL);
+DELETE FROM article_topics WHERE article_id IN (SELECT id FROM articles WHERE deleted_at IS NOT NULL);
+DELETE FROM articles WHERE deleted_at IS NOT NULL;
+DROP INDEX IF EXISTS idx_articles_deleted_at;
+ALTER TABLE articles DROP COLUMN deleted_at;
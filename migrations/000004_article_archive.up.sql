@@ -0,0 +1,36 @@
+-- Deleted articles are kept, hidden from queries, until purged or restored
+ALTER TABLE articles ADD COLUMN deleted_at datetime;
+CREATE INDEX IF NOT EXISTS idx_articles_deleted_at ON articles (deleted_at);
+
+-- Articles older than ARTICLE_ARCHIVE_AFTER_DAYS are moved here by the
+-- ArchiveWorker, so hot-path queries only scan recent content. Searches
+-- read it with include_archived=true.
+CREATE TABLE IF NOT EXISTS articles_archive (
+    id text,
+    title text,
+    description text,
+    url text,
+    publication_date datetime,
+    source_name text,
+    category text,
+    topics_hash text,
+    relevance_score real,
+    current_relevance real,
+    latitude real,
+    longitude real,
+    llm_summary text,
+    content_hash text,
+    exclude_from_trending numeric DEFAULT false,
+    story_id text,
+    sentiment text,
+    tone text,
+    publisher text,
+    license text,
+    attribution text,
+    ingest_source text,
+    image_url text,
+    deleted_at datetime,
+    archived_at datetime NOT NULL,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_articles_archive_pub_date ON articles_archive (publication_date);
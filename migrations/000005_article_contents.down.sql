@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS article_contents_fts_delete;
+DROP TRIGGER IF EXISTS article_contents_fts_update;
+DROP TRIGGER IF EXISTS article_contents_fts_insert;
+DROP TABLE IF EXISTS article_contents_fts;
+DROP INDEX IF EXISTS idx_article_contents_status;
+DROP TABLE IF EXISTS article_contents;
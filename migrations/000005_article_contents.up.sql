@@ -0,0 +1,28 @@
+-- Readable body text fetched from article URLs by the ContentWorker
+CREATE TABLE IF NOT EXISTS article_contents (
+    article_id text,
+    url text,
+    body text,
+    word_count integer,
+    status text,
+    error text,
+    attempts integer,
+    fetched_at datetime,
+    PRIMARY KEY (article_id)
+);
+CREATE INDEX IF NOT EXISTS idx_article_contents_status ON article_contents (status, fetched_at);
+
+-- Full-text index of the bodies for the content search field. The docid is
+-- the article_contents rowid; triggers keep it in sync.
+CREATE VIRTUAL TABLE IF NOT EXISTS article_contents_fts USING fts4(body, tokenize=unicode61);
+
+CREATE TRIGGER IF NOT EXISTS article_contents_fts_insert AFTER INSERT ON article_contents BEGIN
+    INSERT INTO article_contents_fts (docid, body) VALUES (new.rowid, new.body);
+END;
+CREATE TRIGGER IF NOT EXISTS article_contents_fts_update AFTER UPDATE OF body ON article_contents BEGIN
+    DELETE FROM article_contents_fts WHERE docid = old.rowid;
+    INSERT INTO article_contents_fts (docid, body) VALUES (new.rowid, new.body);
+END;
+CREATE TRIGGER IF NOT EXISTS article_contents_fts_delete AFTER DELETE ON article_contents BEGIN
+    DELETE FROM article_contents_fts WHERE docid = old.rowid;
+END;
@@ -0,0 +1,2 @@
+-- Copies merged by the up migration stay soft-deleted; they can be restored
+DROP INDEX IF EXISTS idx_articles_url;
@@ -0,0 +1,27 @@
+-- An article is identified by its URL: a repeat under a new ID, with the
+-- same headline or an edited one, is the same story.
+
+-- Existing copies merge into the earliest published one, which takes the
+-- relevance score of the latest; the others are soft-deleted
+UPDATE articles SET relevance_score = (
+    SELECT latest.relevance_score FROM articles latest
+    WHERE latest.url = articles.url AND latest.deleted_at IS NULL
+    ORDER BY latest.publication_date DESC, latest.id DESC LIMIT 1
+)
+WHERE deleted_at IS NULL AND EXISTS (
+    SELECT 1 FROM articles copy
+    WHERE copy.url = articles.url AND copy.deleted_at IS NULL AND copy.id <> articles.id
+);
+UPDATE articles SET deleted_at = CURRENT_TIMESTAMP
+WHERE deleted_at IS NULL AND EXISTS (
+    SELECT 1 FROM articles earlier
+    WHERE earlier.url = articles.url AND earlier.deleted_at IS NULL
+        AND (earlier.publication_date < articles.publication_date
+            OR (earlier.publication_date = articles.publication_date AND earlier.id < articles.id))
+);
+DELETE FROM article_categories WHERE article_id IN (SELECT id FROM articles WHERE deleted_at IS NOT NULL);
+DELETE FROM article_topics WHERE article_id IN (SELECT id FROM articles WHERE deleted_at IS NOT NULL);
+DELETE FROM article_embeddings WHERE article_id IN (SELECT id FROM articles WHERE deleted_at IS NOT NULL);
+
+-- Deleted articles don't count, so a restore is checked against live ones
+CREATE UNIQUE INDEX IF NOT EXISTS idx_articles_url ON articles (url) WHERE deleted_at IS NULL;
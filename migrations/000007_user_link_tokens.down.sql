@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_user_link_tokens_user;
+DROP TABLE IF EXISTS user_link_tokens;
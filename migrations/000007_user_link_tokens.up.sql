@@ -0,0 +1,10 @@
+-- Single-use tokens an identifier is issued to prove it consents to being
+-- linked to another
+CREATE TABLE IF NOT EXISTS user_link_tokens (
+    token_hash text,
+    user_id text,
+    expires_at datetime,
+    created_at datetime,
+    PRIMARY KEY (token_hash)
+);
+CREATE INDEX IF NOT EXISTS idx_user_link_tokens_user ON user_link_tokens (user_id);
@@ -0,0 +1,2 @@
+ALTER TABLE articles_archive DROP COLUMN summary_blocked;
+ALTER TABLE articles DROP COLUMN summary_blocked;
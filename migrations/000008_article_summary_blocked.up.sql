@@ -0,0 +1,4 @@
+-- Summaries withdrawn after bad_summary feedback stay withdrawn: the
+-- summary worker and request paths skip flagged articles
+ALTER TABLE articles ADD COLUMN summary_blocked numeric DEFAULT false;
+ALTER TABLE articles_archive ADD COLUMN summary_blocked numeric DEFAULT false;
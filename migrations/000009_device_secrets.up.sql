@@ -0,0 +1,8 @@
+-- Secrets identifiers are issued when they register, proving a caller holds
+-- the device before a link token is issued to it
+CREATE TABLE IF NOT EXISTS device_secrets (
+    user_id text,
+    secret_hash text,
+    created_at datetime,
+    PRIMARY KEY (user_id)
+);
@@ -0,0 +1,4 @@
+-- Summary generations that failed or came back empty. The summary worker
+-- gives up on an article after SUMMARY_MAX_ATTEMPTS, until its text changes.
+ALTER TABLE articles ADD COLUMN summary_attempts integer DEFAULT 0;
+ALTER TABLE articles_archive ADD COLUMN summary_attempts integer DEFAULT 0;
@@ -0,0 +1,2 @@
+-- Timestamps stay in UTC; the local offsets they were written with are gone
+SELECT 1;
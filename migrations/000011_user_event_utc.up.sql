@@ -0,0 +1,5 @@
+-- User event timestamps are stored in UTC so they compare, as strings, with
+-- the UTC bounds of the retention worker and event queries. Events written
+-- with a local offset are converted; sub-millisecond precision is dropped.
+UPDATE user_events SET timestamp = strftime('%Y-%m-%d %H:%M:%f+00:00', timestamp)
+WHERE timestamp NOT LIKE '%+00:00';
@@ -0,0 +1,261 @@
+// Package migrations holds the versioned schema migrations and applies
+// them with golang-migrate. Files follow its layout,
+// {version}_{title}.up.sql and {version}_{title}.down.sql, and the applied
+// version is kept in its schema_migrations table, so the migrate CLI can
+// inspect or force the same databases.
+package migrations
+
+import (
+	"bytes"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// Migration is one schema version
+type Migration struct {
+	Version uint64
+	Title   string
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%d_%s", m.Version, m.Title)
+}
+
+// ErrDirty is returned when a migration was left half-applied, by a failed
+// migration, the migrate CLI or a crash; the schema has to be checked and
+// the version forced before migrating again
+var ErrDirty = errors.New("database schema is dirty")
+
+// All returns the embedded migrations in version order
+func All() ([]Migration, error) {
+	return load(files)
+}
+
+// load lists the migrations in fsys. Every version needs an up and a down
+// file so each can be reverted.
+func load(fsys fs.FS) ([]Migration, error) {
+	src, err := iofs.New(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	defer src.Close()
+
+	var migrations []Migration
+	version, err := src.First()
+	for ; err == nil; version, err = src.Next(version) {
+		m, err := readMigration(src, version)
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, m)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+	return migrations, nil
+}
+
+// readMigration checks that version has a non-empty up and down file with
+// one title
+func readMigration(src source.Driver, version uint) (Migration, error) {
+	up, title, err := src.ReadUp(version)
+	if err != nil {
+		return Migration{}, fmt.Errorf("migration version %d has no up file: %w", version, err)
+	}
+	m := Migration{Version: uint64(version), Title: title}
+	if err := checkBody(m, "up", up); err != nil {
+		return Migration{}, err
+	}
+	down, downTitle, err := src.ReadDown(version)
+	if err != nil {
+		return Migration{}, fmt.Errorf("migration %s needs both an up and a down file: %w", m, err)
+	}
+	if downTitle != title {
+		down.Close()
+		return Migration{}, fmt.Errorf("migration version %d has two titles, %s and %s", version, title, downTitle)
+	}
+	return m, checkBody(m, "down", down)
+}
+
+func checkBody(m Migration, direction string, body io.ReadCloser) error {
+	defer body.Close()
+	sql, err := io.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("failed to read migration %s %s: %w", m, direction, err)
+	}
+	if len(bytes.TrimSpace(sql)) == 0 {
+		return fmt.Errorf("migration %s has an empty %s file", m, direction)
+	}
+	return nil
+}
+
+// Version returns the applied schema version, 0 when no migration ran, and
+// whether it was left dirty. It only reads, so a database without
+// golang-migrate's table is left as it is.
+func Version(db *sql.DB) (uint64, bool, error) {
+	var exists int
+	err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, sqlite3.DefaultMigrationsTable).Scan(&exists)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if exists == 0 {
+		return 0, false, nil
+	}
+	var version int64
+	var dirty bool
+	err = db.QueryRow(`SELECT version, dirty FROM `+sqlite3.DefaultMigrationsTable+` LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && version < 0) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return uint64(version), dirty, nil
+}
+
+// Pending returns the migrations newer than the applied version
+func Pending(db *sql.DB) ([]Migration, error) {
+	return pending(db, files)
+}
+
+func pending(db *sql.DB, fsys fs.FS) ([]Migration, error) {
+	all, err := load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	version, dirty, err := Version(db)
+	if err != nil {
+		return nil, err
+	}
+	if dirty {
+		return nil, fmt.Errorf("%w at version %d", ErrDirty, version)
+	}
+	return after(all, version), nil
+}
+
+func after(all []Migration, version uint64) []Migration {
+	for i, m := range all {
+		if m.Version > version {
+			return all[i:]
+		}
+	}
+	return nil
+}
+
+// Up applies the pending migrations in order and returns them. Each runs
+// in a transaction, so a failing one changes nothing but leaves its
+// version dirty, to be checked and forced before migrating again.
+func Up(db *sql.DB) ([]Migration, error) {
+	return up(db, files, 0)
+}
+
+// up applies the migrations in fsys up to target, or all of them for 0
+func up(db *sql.DB, fsys fs.FS, target uint64) ([]Migration, error) {
+	todo, err := pending(db, fsys)
+	if err != nil {
+		return nil, err
+	}
+	if target > 0 {
+		for i, m := range todo {
+			if m.Version > target {
+				todo = todo[:i]
+				break
+			}
+		}
+	}
+	if len(todo) == 0 {
+		return nil, nil
+	}
+
+	m, src, err := newMigrate(db, fsys)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+	err = m.Migrate(uint(todo[len(todo)-1].Version))
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		version, _, _ := Version(db)
+		applied := todo[:len(todo)-len(after(todo, version))]
+		if len(applied) > 0 && applied[len(applied)-1].Version == version {
+			// Its version is recorded, dirty, before it runs
+			applied = applied[:len(applied)-1]
+		}
+		return applied, fmt.Errorf("failed to apply migration %s: %w", todo[len(applied)], err)
+	}
+	return todo, nil
+}
+
+// Down reverts up to steps of the applied migrations, newest first, and
+// returns the ones reverted
+func Down(db *sql.DB, steps int) ([]Migration, error) {
+	return down(db, files, steps)
+}
+
+func down(db *sql.DB, fsys fs.FS, steps int) ([]Migration, error) {
+	all, err := load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	version, dirty, err := Version(db)
+	if err != nil {
+		return nil, err
+	}
+	if dirty {
+		return nil, fmt.Errorf("%w at version %d", ErrDirty, version)
+	}
+	applied := all[:len(all)-len(after(all, version))]
+	if len(applied) > 0 && applied[len(applied)-1].Version != version {
+		return nil, fmt.Errorf("schema version %d has no migration file", version)
+	}
+	steps = min(steps, len(applied))
+	if steps <= 0 {
+		return nil, nil
+	}
+
+	m, src, err := newMigrate(db, fsys)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+	if err := m.Steps(-steps); err != nil {
+		return nil, fmt.Errorf("failed to revert migrations: %w", err)
+	}
+	reverted := make([]Migration, 0, steps)
+	for i := len(applied) - 1; i >= len(applied)-steps; i-- {
+		reverted = append(reverted, applied[i])
+	}
+	return reverted, nil
+}
+
+// newMigrate prepares golang-migrate to run the migrations in fsys on db.
+// The source is returned to be closed instead of the Migrate, whose Close
+// would close db too.
+func newMigrate(db *sql.DB, fsys fs.FS) (*migrate.Migrate, source.Driver, error) {
+	src, err := iofs.New(fsys, ".")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	driver, err := sqlite3.WithInstance(db, &sqlite3.Config{})
+	if err != nil {
+		src.Close()
+		return nil, nil, fmt.Errorf("failed to prepare database for migrations: %w", err)
+	}
+	m, err := migrate.NewWithInstance("iofs", src, "sqlite3", driver)
+	if err != nil {
+		src.Close()
+		return nil, nil, fmt.Errorf("failed to prepare migrations: %w", err)
+	}
+	return m, src, nil
+}
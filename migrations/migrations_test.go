@@ -0,0 +1,245 @@
+package migrations
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get database handle: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	return sqlDB
+}
+
+func hasTable(t *testing.T, db *sql.DB, name string) bool {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, name).Scan(&n); err != nil {
+		t.Fatalf("failed to list tables: %v", err)
+	}
+	return n > 0
+}
+
+func TestLoad(t *testing.T) {
+	migrations, err := load(fstest.MapFS{
+		"2_add_b.up.sql":   {Data: []byte("CREATE TABLE b (id integer);")},
+		"2_add_b.down.sql": {Data: []byte("DROP TABLE b;")},
+		"1_add_a.up.sql":   {Data: []byte("CREATE TABLE a (id integer);")},
+		"1_add_a.down.sql": {Data: []byte("DROP TABLE a;")},
+	})
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+	if len(migrations) != 2 || migrations[0].String() != "1_add_a" || migrations[1].String() != "2_add_b" {
+		t.Errorf("load() = %v, expected 1_add_a and 2_add_b in order", migrations)
+	}
+
+	tests := map[string]fstest.MapFS{
+		"missing down":  {"1_add_a.up.sql": {Data: []byte("SELECT 1;")}},
+		"two titles":    {"1_add_a.up.sql": {Data: []byte("SELECT 1;")}, "1_add_b.down.sql": {Data: []byte("SELECT 1;")}},
+		"empty version": {"1_add_a.up.sql": {Data: []byte("SELECT 1;")}, "1_add_a.down.sql": {}},
+	}
+	for name, fsys := range tests {
+		if _, err := load(fsys); err == nil {
+			t.Errorf("load() with %s file accepted it", name)
+		}
+	}
+}
+
+func TestEmbeddedUpAndDown(t *testing.T) {
+	all, err := All()
+	if err != nil {
+		t.Fatalf("All() error = %v", err)
+	}
+	db := openTestDB(t)
+
+	applied, err := Up(db)
+	if err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	if len(applied) != len(all) {
+		t.Errorf("Up() applied %d migrations, expected all %d", len(applied), len(all))
+	}
+	version, dirty, err := Version(db)
+	if err != nil || dirty || version != all[len(all)-1].Version {
+		t.Errorf("Version() = %d, %v, %v, expected the newest version, clean", version, dirty, err)
+	}
+	if !hasTable(t, db, "articles") {
+		t.Error("Up() didn't create the articles table")
+	}
+	if again, err := Up(db); err != nil || len(again) != 0 {
+		t.Errorf("second Up() = %v, %v, expected nothing to apply", again, err)
+	}
+
+	// Every migration reverts, and the schema can be rebuilt afterwards
+	reverted, err := Down(db, len(all))
+	if err != nil {
+		t.Fatalf("Down() error = %v", err)
+	}
+	if len(reverted) != len(all) || hasTable(t, db, "articles") {
+		t.Errorf("Down() reverted %d migrations, expected all %d and no tables left", len(reverted), len(all))
+	}
+	if version, _, _ := Version(db); version != 0 {
+		t.Errorf("Version() after Down() = %d, expected 0", version)
+	}
+	if _, err := Up(db); err != nil {
+		t.Errorf("Up() after Down() error = %v", err)
+	}
+}
+
+func TestUpgradesBaselineSchema(t *testing.T) {
+	// A database AutoMigrate created before versioning has the initial
+	// schema and no version; the later columns are added and backfilled
+	baseline, err := files.ReadFile("000001_initial_schema.up.sql")
+	if err != nil {
+		t.Fatalf("failed to read the initial schema: %v", err)
+	}
+	db := openTestDB(t)
+	if _, err := db.Exec(string(baseline)); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	_, err = db.Exec(`INSERT INTO articles (id, title, relevance_score) VALUES ('a1', 'kept', 0.7);
+		INSERT INTO user_events (article_id, user_id, event_type) VALUES ('a1', 'u1', 'view');`)
+	if err != nil {
+		t.Fatalf("failed to insert rows: %v", err)
+	}
+	if _, err := Up(db); err != nil {
+		t.Fatalf("Up() on the baseline schema error = %v", err)
+	}
+
+	var title string
+	var relevance float64
+	err = db.QueryRow(`SELECT title, current_relevance FROM articles WHERE id = 'a1'`).Scan(&title, &relevance)
+	if err != nil || title != "kept" || relevance != 0.7 {
+		t.Errorf("article = %q, %v, %v, expected the existing row kept with current_relevance 0.7", title, relevance, err)
+	}
+	var device, platform string
+	err = db.QueryRow(`SELECT device_id, platform FROM user_events WHERE user_id = 'u1'`).Scan(&device, &platform)
+	if err != nil || device != "u1" || platform != "unknown" {
+		t.Errorf("event device = %q, platform %q, %v, expected u1 and unknown", device, platform, err)
+	}
+	if !hasTable(t, db, "sources") {
+		t.Error("Up() didn't create the tables added after the baseline")
+	}
+}
+
+func TestMergesDuplicateArticles(t *testing.T) {
+	db := openTestDB(t)
+	if _, err := up(db, files, 5); err != nil {
+		t.Fatalf("up() error = %v", err)
+	}
+	_, err := db.Exec(`INSERT INTO articles (id, url, title, publication_date, relevance_score) VALUES
+		('first', 'https://example.com/a', 'Story', '2025-03-22 10:00:00', 0.2),
+		('second', 'https://example.com/a', 'Story', '2025-03-23 10:00:00', 0.5),
+		('edited', 'https://example.com/a', 'Story, updated', '2025-03-24 10:00:00', 0.9),
+		('other', 'https://example.com/b', 'Story', '2025-03-24 10:00:00', 0.5)`)
+	if err != nil {
+		t.Fatalf("failed to insert articles: %v", err)
+	}
+	if _, err := Up(db); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	var live []string
+	rows, err := db.Query(`SELECT id FROM articles WHERE deleted_at IS NULL ORDER BY id`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rows.Next() {
+		var id string
+		rows.Scan(&id)
+		live = append(live, id)
+	}
+	rows.Close()
+	if strings.Join(live, ",") != "first,other" {
+		t.Errorf("live articles = %v, expected the earliest copy and the other story", live)
+	}
+	var score float64
+	if err := db.QueryRow(`SELECT relevance_score FROM articles WHERE id = 'first'`).Scan(&score); err != nil || score != 0.9 {
+		t.Errorf("merged relevance = %v, %v, expected the latest copy's 0.9", score, err)
+	}
+	if _, err := db.Exec(`INSERT INTO articles (id, url, title) VALUES ('third', 'https://example.com/a', 'Another headline')`); err == nil {
+		t.Error("inserting another article with the URL succeeded, expected the unique index to reject it")
+	}
+}
+
+func TestFailedMigrationRollsBack(t *testing.T) {
+	db := openTestDB(t)
+	fsys := fstest.MapFS{
+		"1_add_a.up.sql":    {Data: []byte("CREATE TABLE a (id integer);")},
+		"1_add_a.down.sql":  {Data: []byte("DROP TABLE a;")},
+		"2_broken.up.sql":   {Data: []byte("CREATE TABLE b (id integer); INSERT INTO missing VALUES (1);")},
+		"2_broken.down.sql": {Data: []byte("DROP TABLE b;")},
+	}
+	applied, err := up(db, fsys, 0)
+	if err == nil || !strings.Contains(err.Error(), "2_broken") {
+		t.Fatalf("up() error = %v, expected migration 2_broken to fail", err)
+	}
+	if len(applied) != 1 {
+		t.Errorf("up() applied %v, expected only 1_add_a", applied)
+	}
+	// The failed version is left dirty, to be checked before forcing it
+	if version, dirty, _ := Version(db); version != 2 || !dirty {
+		t.Errorf("Version() = %d, dirty %v, expected 2 and dirty", version, dirty)
+	}
+	if !hasTable(t, db, "a") || hasTable(t, db, "b") {
+		t.Error("expected table a kept and table b of the failed migration rolled back")
+	}
+}
+
+func TestDirtyVersionStopsMigrations(t *testing.T) {
+	db := openTestDB(t)
+	if _, err := db.Exec(`CREATE TABLE schema_migrations (version uint64, dirty bool);
+		INSERT INTO schema_migrations (version, dirty) VALUES (1, 1);`); err != nil {
+		t.Fatalf("failed to mark the schema dirty: %v", err)
+	}
+	if _, err := Up(db); !errors.Is(err, ErrDirty) {
+		t.Errorf("Up() error = %v, expected ErrDirty", err)
+	}
+	if _, err := Down(db, 1); !errors.Is(err, ErrDirty) {
+		t.Errorf("Down() error = %v, expected ErrDirty", err)
+	}
+}
+
+func TestConvertsEventTimestampsToUTC(t *testing.T) {
+	db := openTestDB(t)
+	if _, err := up(db, files, 10); err != nil {
+		t.Fatalf("up() error = %v", err)
+	}
+	_, err := db.Exec(`INSERT INTO user_events (article_id, user_id, event_type, timestamp) VALUES
+		('a1', 'local', 'view', '2026-03-26 15:30:00.123456789+05:30'),
+		('a1', 'utc', 'view', '2026-03-26 10:00:00.5+00:00')`)
+	if err != nil {
+		t.Fatalf("failed to insert events: %v", err)
+	}
+	if _, err := Up(db); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	// As text, since SQLite compares them that way
+	for user, want := range map[string]string{
+		"local": "2026-03-26 10:00:00.123+00:00",
+		"utc":   "2026-03-26 10:00:00.5+00:00",
+	} {
+		var got string
+		if err := db.QueryRow(`SELECT timestamp || '' FROM user_events WHERE user_id = ?`, user).Scan(&got); err != nil || got != want {
+			t.Errorf("%s event timestamp = %q, %v, expected %q", user, got, err, want)
+		}
+	}
+}
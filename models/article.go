@@ -1,9 +1,15 @@
 package models
 
 import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"net/url"
 	"strings"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Article represents a news article in the database
@@ -12,15 +18,36 @@ type Article struct {
 	ID              string    `gorm:"primaryKey" json:"id"`
 	Title           string    `gorm:"index:idx_title" json:"title"`
 	Description     string    `json:"description"`
-	URL             string    `json:"url"`
+	URL             string    `gorm:"uniqueIndex:idx_articles_url,where:deleted_at IS NULL" json:"url"` // Unique among live articles, see database.MergeDuplicates
 	PublicationDate time.Time `gorm:"index:idx_pub_date" json:"publication_date"`
 	SourceName      string    `gorm:"index:idx_source" json:"source_name"`
-	Category        string    `gorm:"index:idx_category" json:"category"`
+	Category        string    `gorm:"index:idx_category" json:"category"` // Comma-joined, kept for display
+	Categories      []Category `gorm:"many2many:article_categories;" json:"-"`
+	Topics          []Topic   `gorm:"many2many:article_topics;" json:"-"`
+	TopicsHash      string    `json:"-"` // ContentHash when topics were last extracted
 	RelevanceScore  float64   `gorm:"index:idx_relevance" json:"relevance_score"`
+	// CurrentRelevance blends RelevanceScore with recent engagement (see RelevanceWorker)
+	CurrentRelevance float64  `gorm:"index:idx_current_relevance" json:"current_relevance"`
 	Latitude        float64   `gorm:"index:idx_location" json:"latitude"`
 	Longitude       float64   `gorm:"index:idx_location" json:"longitude"`
 	LLMSummary      string    `json:"llm_summary,omitempty"`
+	SummaryBlocked  bool      `gorm:"default:false" json:"-"` // Summary withdrawn after bad_summary reports, never regenerated
+	SummaryAttempts int       `gorm:"default:0" json:"-"` // Failed summary generations since the text last changed
+	ContentHash     string    `json:"-"` // Hash of title+description for change detection
+	ExcludeFromTrending bool  `gorm:"default:false" json:"exclude_from_trending"`
+	StoryID         string    `gorm:"index:idx_story" json:"story_id,omitempty"` // Cluster of near-duplicate articles (see StoryService)
+	Sentiment       string    `gorm:"index:idx_sentiment" json:"sentiment,omitempty"` // Set by the SentimentWorker, empty until classified
+	Tone            string    `json:"tone,omitempty"`
+	// Provenance and licensing, filled by ApplyProvenance when stored
+	Publisher       string    `json:"publisher"`   // Original publisher, which may differ from the syndicating source
+	License         string    `json:"license"`     // e.g. "all-rights-reserved" or "CC-BY-4.0"
+	Attribution     string    `json:"attribution"` // Credit line consumers must show with the content
+	IngestSource    string    `json:"ingest_source"` // How the article entered the database (see IngestSource*)
+	ImageURL        string    `json:"image_url,omitempty"` // Lead image, when the source provides one
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete, see ArticleService.Delete
 	Distance        float64   `gorm:"-" json:"distance,omitempty"` // Computed, not stored
+	Similarity      float64   `gorm:"-" json:"similarity,omitempty"` // Computed for semantic search
+	ScoreBreakdown  map[string]float64 `gorm:"-" json:"score_breakdown,omitempty"` // Per-signal ranking scores
 }
 
 
@@ -35,10 +62,24 @@ type ArticleResponse struct {
 	SourceName      string    `json:"source_name"`
 	Category        string    `json:"category"`
 	RelevanceScore  float64   `json:"relevance_score"`
+	CurrentRelevance float64  `json:"current_relevance"`
 	LLMSummary      string    `json:"llm_summary"`
 	Latitude        float64   `json:"latitude"`
 	Longitude       float64   `json:"longitude"`
 	Distance        float64   `json:"distance,omitempty"`
+	Similarity      float64   `json:"similarity,omitempty"`
+	ScoreBreakdown  map[string]float64 `json:"score_breakdown,omitempty"`
+	StoryID         string    `json:"story_id,omitempty"`
+	Sentiment       string    `json:"sentiment,omitempty"`
+	Tone            string    `json:"tone,omitempty"`
+	Publisher       string    `json:"publisher"`
+	License         string    `json:"license"`
+	Attribution     string    `json:"attribution"`
+	IngestSource    string    `json:"ingest_source"`
+	ImageURL        string    `json:"image_url,omitempty"`
+	ThumbnailURL    string    `json:"thumbnail_url,omitempty"` // The image through the API's image proxy, which can resize it
+	Layout          string    `json:"layout,omitempty"`   // hero, standard or compact, when layout hints are requested
+	Redacted        []string  `json:"redacted,omitempty"` // Fields hidden for this API key
 }
 
 // ToResponse converts an Article to ArticleResponse
@@ -51,11 +92,65 @@ func (a *Article) ToResponse() ArticleResponse {
 		SourceName:      a.SourceName,
 		Category:        a.Category,
 		RelevanceScore:  a.RelevanceScore,
+		CurrentRelevance: a.CurrentRelevance,
 		LLMSummary:      a.LLMSummary,
 		Latitude:        a.Latitude,
 		Longitude:       a.Longitude,
 		Distance:        a.Distance,
+		Similarity:      a.Similarity,
+		ScoreBreakdown:  a.ScoreBreakdown,
+		StoryID:         a.StoryID,
+		Sentiment:       a.Sentiment,
+		Tone:            a.Tone,
+		Publisher:       a.Publisher,
+		License:         a.License,
+		Attribution:     a.Attribution,
+		IngestSource:    a.IngestSource,
+		ImageURL:        a.ImageURL,
+		ThumbnailURL:    a.ThumbnailURL(),
+	}
+}
+
+// ThumbnailURL is the path of the article's image on the image proxy
+// (relative to the API's host, so cached responses don't depend on it),
+// empty when it has none
+func (a *Article) ThumbnailURL() string {
+	if a.ImageURL == "" {
+		return ""
+	}
+	return "/api/v1/images/" + url.PathEscape(a.ID)
+}
+
+// Ingest sources recorded in Article.IngestSource
+const (
+	IngestSourceDataset   = "dataset"    // Loaded or reloaded from NEWS_DATA_FILE
+	IngestSourceAdmin     = "admin"      // Created or imported through the admin API
+	IngestSourceConnector = "connector:" // Prefix of the ingest connector's name
+	IngestSourceUnknown   = "unknown"    // Stored before provenance was recorded
+)
+
+// DefaultLicense applies when neither the feed nor the source names a
+// license: the content may be linked to but not republished
+const DefaultLicense = "all-rights-reserved"
+
+// ApplyProvenance fills provenance fields the feed left empty from the
+// source's licensing defaults, and records how the article was ingested.
+// The publisher defaults to the source name and the attribution to a
+// credit line naming the publisher.
+func (a *Article) ApplyProvenance(source Source, ingestSource string) {
+	a.Publisher = cmp.Or(a.Publisher, source.Publisher, a.SourceName)
+	a.License = cmp.Or(a.License, source.License, DefaultLicense)
+	a.Attribution = cmp.Or(a.Attribution, source.Attribution)
+	if a.Attribution == "" && a.Publisher != "" {
+		a.Attribution = "Source: " + a.Publisher
 	}
+	a.IngestSource = ingestSource
+}
+
+// ComputeContentHash returns a hash of the fields whose change warrants re-summarization
+func (a *Article) ComputeContentHash() string {
+	sum := sha256.Sum256([]byte(a.Title + "\n" + a.Description))
+	return hex.EncodeToString(sum[:])
 }
 
 // ArticleSortable interface implementation for generic sorting
@@ -144,6 +239,7 @@ func (a *Article) UnmarshalJSON(data []byte) error {
 	a.SourceName = raw.SourceName
 	a.Category = strings.Join(raw.Category, ",")
 	a.RelevanceScore = raw.RelevanceScore
+	a.CurrentRelevance = raw.RelevanceScore
 	a.Latitude = raw.Latitude
 	a.Longitude = raw.Longitude
 
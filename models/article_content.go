@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// Content fetch outcomes
+const (
+	ContentStatusFetched    = "fetched"
+	ContentStatusDisallowed = "disallowed" // robots.txt forbids the page
+	ContentStatusFailed     = "failed"     // retried on later passes, up to a limit
+)
+
+// ArticleContent is the readable body text fetched from an article's URL
+// by the ContentWorker. Bodies are indexed for full-text search in
+// article_contents_fts, kept in sync by triggers.
+type ArticleContent struct {
+	ArticleID string    `gorm:"primaryKey" json:"article_id"`
+	URL       string    `json:"url"`
+	Body      string    `json:"body"`
+	WordCount int       `json:"word_count"`
+	Status    string    `gorm:"index:idx_article_contents_status" json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Attempts  int       `json:"attempts"`
+	FetchedAt time.Time `gorm:"index:idx_article_contents_status" json:"fetched_at"` // Time of the last attempt
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Category is a normalized article category, linked to articles through the
+// article_categories join table
+type Category struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"uniqueIndex" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CategoryCount is a category with the number of articles tagged with it
+type CategoryCount struct {
+	Name         string `json:"name"`
+	ArticleCount int64  `json:"article_count"`
+}
+
+// FacetCount is the number of matching articles sharing one facet value
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// Facets holds aggregation counts over a full result set, for filter sidebars
+type Facets struct {
+	Categories []FacetCount `json:"categories"`
+	Sources    []FacetCount `json:"sources"`
+	Days       []FacetCount `json:"days"` // publication day, YYYY-MM-DD
+}
+
+// SplitCategories splits the comma-joined Article.Category string into
+// trimmed, de-duplicated names (case-insensitively)
+func SplitCategories(joined string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(joined, ",") {
+		name = strings.TrimSpace(name)
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		names = append(names, name)
+	}
+	return names
+}
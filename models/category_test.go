@@ -0,0 +1,29 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitCategories(t *testing.T) {
+	tests := []struct {
+		name     string
+		joined   string
+		expected []string
+	}{
+		{"Single category", "Tech", []string{"Tech"}},
+		{"Trimmed names", " Tech , Biotech ", []string{"Tech", "Biotech"}},
+		{"Substrings stay separate", "Biotech,Tech,FinTech", []string{"Biotech", "Tech", "FinTech"}},
+		{"Case-insensitive duplicates", "Tech,tech, TECH", []string{"Tech"}},
+		{"Empty names dropped", ",Tech,,", []string{"Tech"}},
+		{"Empty string", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitCategories(tt.joined); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("SplitCategories(%q) = %q, expected %q", tt.joined, got, tt.expected)
+			}
+		})
+	}
+}
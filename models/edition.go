@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+)
+
+// Edition is a named local edition: the news and trending feed around a city
+// center, precomputed on a schedule for fast, cacheable city pages
+type Edition struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Slug      string    `gorm:"uniqueIndex" json:"slug"` // URL name, e.g. "sf-bay-area"
+	Name      string    `json:"name"`
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	RadiusKm  float64   `json:"radius_km"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// EditionOverride is an editor's adjustment to one article of an edition's
+// feed, layered on top of the automatic ranking
+type EditionOverride struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	EditionID uint      `gorm:"uniqueIndex:idx_edition_override_article" json:"-"`
+	ArticleID string    `gorm:"uniqueIndex:idx_edition_override_article" json:"article_id"`
+	Action    string    `json:"action"`             // "pin" or "exclude"
+	Position  int       `json:"position,omitempty"` // 1-based feed slot of a pinned article
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Edition override actions. A pinned article is placed at its position
+// whether or not the automatic ranking picked it, which is also how editors
+// reorder a feed; an excluded article is dropped from the feed and trending.
+const (
+	EditionOverridePin     = "pin"
+	EditionOverrideExclude = "exclude"
+)
+
+// IsValidEditionOverrideAction reports whether action is a known edition override action
+func IsValidEditionOverrideAction(action string) bool {
+	return action == EditionOverridePin || action == EditionOverrideExclude
+}
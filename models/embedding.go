@@ -0,0 +1,46 @@
+package models
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// ArticleEmbedding stores the embedding vector for an article
+// Vectors are persisted as little-endian float32 blobs
+type ArticleEmbedding struct {
+	ArticleID  string    `gorm:"primaryKey" json:"article_id"`
+	Model      string    `json:"model"`
+	Dimensions int       `json:"dimensions"`
+	Vector     []byte    `json:"-"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// SetVector encodes a float32 vector into the Vector blob
+func (e *ArticleEmbedding) SetVector(vector []float32) {
+	e.Vector = encodeVector(vector)
+	e.Dimensions = len(vector)
+}
+
+// GetVector decodes the Vector blob into a float32 slice
+func (e *ArticleEmbedding) GetVector() []float32 {
+	return decodeVector(e.Vector)
+}
+
+// encodeVector encodes a float32 vector as a little-endian blob
+func encodeVector(vector []float32) []byte {
+	buf := make([]byte, 4*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(v))
+	}
+	return buf
+}
+
+// decodeVector decodes a little-endian blob into a float32 slice
+func decodeVector(blob []byte) []float32 {
+	vector := make([]float32, len(blob)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(blob[i*4:]))
+	}
+	return vector
+}
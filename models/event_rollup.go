@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+)
+
+// EventRollup is an hourly per-article aggregate of user events older than
+// the trending window, kept after the raw events are deleted
+type EventRollup struct {
+	ArticleID string    `gorm:"primaryKey" json:"article_id"`
+	Hour      time.Time `gorm:"primaryKey;index:idx_event_rollup_hour" json:"hour"` // Start of the hour, UTC
+	EventType string    `gorm:"primaryKey" json:"event_type"`
+	Count     int       `json:"count"`
+	Users     int       `json:"users"` // Distinct users within the hour
+}
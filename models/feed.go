@@ -0,0 +1,53 @@
+package models
+
+// Kinds of articles a composed feed mixes
+const (
+	FeedKindBriefing = "briefing"  // fresh, factual or urgent quick reads
+	FeedKindLongRead = "long_read" // analytical and opinion pieces
+	FeedKindTrending = "trending"  // most engaged-with around the location
+)
+
+// FeedArticleResponse is an article in a composed feed with the kind of
+// slot it fills
+type FeedArticleResponse struct {
+	ArticleResponse
+	Kind string `json:"kind"`
+}
+
+// FeedComposition reports the time-of-day rule a feed was composed with
+type FeedComposition struct {
+	Slot      string             `json:"slot"`       // e.g. "morning"
+	LocalTime string             `json:"local_time"` // HH:MM in Timezone
+	Timezone  string             `json:"timezone"`
+	Shares    map[string]float64 `json:"shares"` // Kind -> share of the feed the slot asks for
+	Mix       map[string]int     `json:"mix"`    // Kind -> articles actually included
+	Digest    bool               `json:"digest"` // Whether the slot leads with the daily digest
+}
+
+// FeedRequest represents the query parameters of a composed feed
+type FeedRequest struct {
+	Latitude  float64 `form:"lat" binding:"required"`
+	Longitude float64 `form:"lon" binding:"required"`
+	Radius    float64 `form:"radius"` // in km, optional
+	Limit     int     `form:"limit"`
+	Timezone  string  `form:"tz"` // IANA name; estimated from lon when empty
+	// IncludeSummaries defaults to true; false skips LLM enrichment entirely
+	IncludeSummaries *bool `form:"include_summaries"`
+	Layout           bool  `form:"layout"` // add a layout hint to every article
+}
+
+// FeedDigest is the daily digest a morning feed leads with
+type FeedDigest struct {
+	Briefing          string   `json:"briefing"`
+	BriefingAvailable bool     `json:"briefing_available"`
+	ArticleIDs        []string `json:"article_ids"`
+	ExpiresAt         string   `json:"expires_at"`
+}
+
+// FeedResponse represents a composed feed
+type FeedResponse struct {
+	Articles []FeedArticleResponse `json:"articles"`
+	Digest   *FeedDigest           `json:"digest,omitempty"`
+	Metadata *ResponseMetadata     `json:"metadata"`
+	Demo     bool                  `json:"demo,omitempty"` // Served to the demo tier
+}
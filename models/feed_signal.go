@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+)
+
+// FeedSignal is a user's "more/less like this" reaction to an article. Only
+// the latest signal per canonical user and article is kept.
+type FeedSignal struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    string    `gorm:"uniqueIndex:idx_feed_signal_user_article" json:"user_id"` // Canonical user
+	ArticleID string    `gorm:"uniqueIndex:idx_feed_signal_user_article" json:"article_id"`
+	Signal    string    `json:"signal"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// FeedSignalRequest is the body for recording a feed signal
+type FeedSignalRequest struct {
+	ArticleID string `json:"article_id" binding:"required"`
+	Signal    string `json:"signal" binding:"required"`
+}
+
+// Feed signals
+const (
+	FeedSignalMore = "more_like_this"
+	FeedSignalLess = "less_like_this"
+)
+
+// IsValidFeedSignal reports whether signal is a known feed signal
+func IsValidFeedSignal(signal string) bool {
+	return signal == FeedSignalMore || signal == FeedSignalLess
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+)
+
+// Feedback is a user report about a summary or ranking, queued for review
+type Feedback struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	ArticleID  string     `gorm:"index:idx_feedback_article" json:"article_id"`
+	UserID     string     `json:"user_id,omitempty"`
+	Type       string     `gorm:"index:idx_feedback_type" json:"type"`
+	Query      string     `json:"query,omitempty"`    // Search query the article was returned for
+	Endpoint   string     `json:"endpoint,omitempty"` // e.g. "search", "trending"
+	Comment    string     `json:"comment,omitempty"`
+	Status     string     `gorm:"index:idx_feedback_status;default:open" json:"status"`
+	Resolution string     `json:"resolution,omitempty"` // Review action taken
+	CreatedAt  time.Time  `json:"created_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+// FeedbackRequest is the body for submitting feedback
+type FeedbackRequest struct {
+	ArticleID string `json:"article_id" binding:"required"`
+	Type      string `json:"type" binding:"required"`
+	UserID    string `json:"user_id"`
+	Query     string `json:"query"`
+	Endpoint  string `json:"endpoint"`
+	Comment   string `json:"comment"`
+}
+
+// Feedback types
+const (
+	FeedbackBadSummary       = "bad_summary"
+	FeedbackIrrelevantResult = "irrelevant_result"
+	FeedbackWrongCategory    = "wrong_category"
+)
+
+// Feedback review statuses
+const (
+	FeedbackStatusOpen     = "open"
+	FeedbackStatusResolved = "resolved"
+)
+
+// IsValidFeedbackType reports whether feedbackType is a known feedback type
+func IsValidFeedbackType(feedbackType string) bool {
+	switch feedbackType {
+	case FeedbackBadSummary, FeedbackIrrelevantResult, FeedbackWrongCategory:
+		return true
+	default:
+		return false
+	}
+}
@@ -6,7 +6,7 @@ type Entities map[string]interface{}
 
 // IntentResponse represents the LLM's analysis of user query
 type IntentResponse struct {
-	Intent   string   `json:"intent"`   // "category", "source", "search", "nearby", "score"
+	Intent   string   `json:"intent"`   // "category", "source", "search", "nearby", "score", "discovery"
 	Entities Entities `json:"entities"` // Extracted entities (people, organizations, locations, events, etc.)
 }
 
@@ -17,6 +17,9 @@ const (
 	IntentSearch   = "search"
 	IntentNearby   = "nearby"
 	IntentScore    = "score"
+
+	// IntentDiscovery asks for the latest news with no topic ("what's happening today")
+	IntentDiscovery = "discovery"
 )
 
 // NewsQueryRequest represents an incoming news query
@@ -25,6 +28,8 @@ type NewsQueryRequest struct {
 	Latitude  float64 `json:"lat" form:"lat"`
 	Longitude float64 `json:"lon" form:"lon"`
 	Radius    float64 `json:"radius" form:"radius"` // in km, optional
+	// SessionID continues a conversational session; empty starts a new one
+	SessionID string `json:"session_id" form:"session_id"`
 }
 
 // NewsQueryResponse represents the response for a news query
@@ -35,6 +40,11 @@ type NewsQueryResponse struct {
 	Count    int               `json:"count"`
 }
 
+// AskRequest is the body of a question answered from the articles
+type AskRequest struct {
+	Question string `json:"question" binding:"required"`
+}
+
 // ErrorResponse represents an error response
 type ErrorResponse struct {
 	Error   string `json:"error"`
@@ -48,25 +58,69 @@ type TrendingRequest struct {
 	Longitude float64 `json:"lon" form:"lon" binding:"required"`
 	Radius    float64 `json:"radius" form:"radius"` // in km, optional
 	Limit     int     `json:"limit" form:"limit"`
+	// IncludeSummaries defaults to true; false skips LLM enrichment entirely
+	IncludeSummaries *bool `json:"include_summaries" form:"include_summaries"`
+	Layout           bool  `json:"layout" form:"layout"` // add a layout hint to every article
+}
+
+// NearbyRequest represents the query parameters of the nearby endpoint
+type NearbyRequest struct {
+	Lat    float64 `form:"lat" binding:"required"`
+	Lon    float64 `form:"lon" binding:"required"`
+	Radius float64 `form:"radius"` // in km, optional
+	Query  string  `form:"query"`
+}
+
+// DigestRequest represents the query parameters for the daily digest
+type DigestRequest struct {
+	Latitude   float64 `form:"lat" binding:"required"`
+	Longitude  float64 `form:"lon" binding:"required"`
+	Categories string  `form:"categories"` // comma-separated, optional
+}
+
+// EventStatsRequest represents the query parameters of the event stats endpoint
+type EventStatsRequest struct {
+	From      string   `form:"from"`   // RFC3339 or YYYY-MM-DD, inclusive
+	To        string   `form:"to"`     // RFC3339 or YYYY-MM-DD, exclusive
+	Bucket    string   `form:"bucket"` // "hour" or "day"
+	ArticleID string   `form:"article_id"`
+	EventType string   `form:"event_type"`
+	Latitude  *float64 `form:"lat"`
+	Longitude *float64 `form:"lon"`
+	Radius    float64  `form:"radius"` // in km, defaults to TRENDING_RADIUS
 }
 
 // TrendingResponse represents trending news response
 type TrendingResponse struct {
-	Articles []ArticleResponse `json:"articles"`
-	Metadata *ResponseMetadata `json:"metadata"`
-	Location string            `json:"location"`
-	RadiusKm float64           `json:"radius_km"`
-	CachedAt string            `json:"cached_at,omitempty"`
+	Articles []TrendingArticleResponse `json:"articles"`
+	Metadata *ResponseMetadata         `json:"metadata"`
+	Location string                    `json:"location"`
+	RadiusKm float64                   `json:"radius_km"`
+	CachedAt string                    `json:"cached_at,omitempty"`
+	Demo     bool                      `json:"demo,omitempty"` // Served to the demo tier
 }
 
 // ResponseMetadata contains pagination and query information for API responses
 type ResponseMetadata struct {
-	Count          int               `json:"count"`             // Number of articles returned
-	TotalAvailable int               `json:"total_available"`   // Total matching articles before limit
-	Page           int               `json:"page"`              // Current page number
-	PageSize       int               `json:"page_size"`         // Items per page
-	Query          string            `json:"query,omitempty"`   // Original query string
-	Filters        map[string]string `json:"filters,omitempty"` // Applied filters (category, source, etc.)
+	Count          int               `json:"count"`                 // Number of articles returned
+	TotalAvailable int               `json:"total_available"`       // Total matching articles before limit
+	Page           int               `json:"page"`                  // Current page number
+	PageSize       int               `json:"page_size"`             // Items per page
+	Query          string            `json:"query,omitempty"`       // Original query string
+	Filters        map[string]string `json:"filters,omitempty"`     // Applied filters (category, source, etc.)
+	Summaries      string            `json:"summaries,omitempty"`   // LLM summary enrichment status, when applicable
+	Ranking        *RankingInfo      `json:"ranking,omitempty"`     // Ranking profile applied, when requested
+	Composition    *FeedComposition  `json:"composition,omitempty"` // Time-of-day mix of a composed feed
+	Clamped        map[string]string `json:"clamped,omitempty"`     // Parameters reduced to the API key's limits -> value applied
+	NextCursor     string            `json:"next_cursor,omitempty"` // Cursor of the next page of a paginated listing
+}
+
+// RankingInfo reports the ranking profile used to order a response
+type RankingInfo struct {
+	Profile       string             `json:"profile"`
+	Weights       map[string]float64 `json:"weights"`       // Signal name -> weight
+	Personalized  bool               `json:"personalized"`  // Whether a user's history fed the personal signal
+	Normalization string             `json:"normalization"` // How signals were rescaled before weighting
 }
 
 // NewResponseMetadata creates a new ResponseMetadata with defaults
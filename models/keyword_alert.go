@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+)
+
+// KeywordAlert is an editor's watch on a keyword or named entity in ingested articles
+type KeywordAlert struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Keyword   string    `json:"keyword"`
+	Kind      string    `json:"kind"`             // "keyword" or "entity"
+	Editor    string    `json:"editor,omitempty"` // Who set up the alert, passed on in notifications
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Keyword alert kinds. Keywords match case-insensitively; entities (people,
+// companies, places) match case-sensitively so "Apple" doesn't fire on "apple".
+const (
+	AlertKindKeyword = "keyword"
+	AlertKindEntity  = "entity"
+)
+
+// IsValidAlertKind reports whether kind is a known keyword alert kind
+func IsValidAlertKind(kind string) bool {
+	return kind == AlertKindKeyword || kind == AlertKindEntity
+}
+
+// AlertMatch tags an article that matched a keyword alert, queued for triage.
+// Keyword, editor and article details are copied so the queue reads on its own.
+type AlertMatch struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	AlertID    uint       `gorm:"uniqueIndex:idx_alert_match_article" json:"alert_id"`
+	ArticleID  string     `gorm:"uniqueIndex:idx_alert_match_article;index:idx_alert_match_article_id" json:"article_id"`
+	Keyword    string     `json:"keyword"`
+	Editor     string     `json:"editor,omitempty"`
+	Title      string     `json:"title"`
+	URL        string     `json:"url"`
+	SourceName string     `json:"source_name"`
+	Status     string     `gorm:"index:idx_alert_match_status;default:new" json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	TriagedAt  *time.Time `json:"triaged_at,omitempty"`
+}
+
+// Alert match triage statuses
+const (
+	AlertMatchStatusNew     = "new"
+	AlertMatchStatusTriaged = "triaged"
+)
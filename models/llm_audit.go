@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// LLMAudit is a sampled LLM interaction kept for debugging output quality.
+// Prompt and response are scrubbed of PII before they are stored.
+type LLMAudit struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt   time.Time `gorm:"index:idx_llm_audit_created" json:"created_at"`
+	Purpose     string    `gorm:"index:idx_llm_audit_purpose" json:"purpose"` // intent, summary or embedding
+	Provider    string    `json:"provider,omitempty"`                         // empty when no provider was tried
+	Model       string    `json:"model,omitempty"`
+	Prompt      string    `json:"prompt"`
+	Response    string    `json:"response,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	LatencyMs   int64     `json:"latency_ms"`
+	TotalTokens int       `json:"total_tokens"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// QuerySession holds the last resolved intent of a conversational query so
+// follow-ups ("only from Reuters") refine it instead of starting over
+type QuerySession struct {
+	ID        string    `gorm:"primaryKey" json:"id"`
+	Query     string    `json:"query"` // latest follow-up as asked
+	Intent    string    `json:"intent"`
+	Entities  Entities  `gorm:"serializer:json" json:"entities"` // merged across turns, including location
+	Turns     int       `json:"turns"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `gorm:"index" json:"updated_at"`
+	ExpiresAt time.Time `gorm:"-" json:"expires_at"` // UpdatedAt plus QUERY_SESSION_TTL
+}
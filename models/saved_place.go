@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+// SavedPlace is a named location ("home", "work") a canonical user saved,
+// so nearby and trending requests can ask for near=<name> instead of
+// sending coordinates. Places are only stored for users who opted in with
+// the save_places preference.
+type SavedPlace struct {
+	ID        uint      `gorm:"primaryKey" json:"-"`
+	UserID    string    `gorm:"uniqueIndex:idx_saved_place_user_name" json:"user_id"` // Canonical user
+	Name      string    `gorm:"uniqueIndex:idx_saved_place_user_name" json:"name"`    // Lowercased
+	Latitude  float64   `json:"-"`                                                    // Never sent back, only resolved for near=<name>
+	Longitude float64   `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SavePlaceRequest is the body for saving a named place
+type SavePlaceRequest struct {
+	Lat *float64 `json:"lat" binding:"required,min=-90,max=90"`
+	Lon *float64 `json:"lon" binding:"required,min=-180,max=180"`
+}
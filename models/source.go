@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+)
+
+// Source represents a content source and how its feed is ingested
+// Name matches Article.SourceName
+type Source struct {
+	ID      uint   `gorm:"primaryKey" json:"id"`
+	Name    string `gorm:"uniqueIndex" json:"name"`
+	Enabled bool   `gorm:"default:true" json:"enabled"`
+	// ExcludeFromTrending keeps all of this source's articles out of trending
+	ExcludeFromTrending bool `gorm:"default:false" json:"exclude_from_trending"`
+	// ReliabilityScore rates the source from 0 (unreliable) to 1 for search
+	// ranking, trending and min_source_score; nil uses SOURCE_DEFAULT_RELIABILITY
+	ReliabilityScore *float64 `json:"reliability_score,omitempty"`
+	// Connector is the registered ingest connector name (empty = not fetched)
+	Connector string            `json:"connector,omitempty"`
+	Config    map[string]string `gorm:"serializer:json" json:"config,omitempty"`
+	Rules     SourceRules       `gorm:"serializer:json" json:"rules"`
+	// Licensing defaults for articles whose feed doesn't carry them
+	Publisher   string    `json:"publisher,omitempty"`
+	License     string    `json:"license,omitempty"`
+	Attribution string    `json:"attribution,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// SourceRules holds per-source transformations applied during ingestion
+type SourceRules struct {
+	// FieldMap renames raw feed fields to article fields (raw name -> article name)
+	FieldMap map[string]string `json:"field_map,omitempty"`
+	// CategoryMap rewrites feed categories (case-insensitive) to canonical names
+	CategoryMap map[string]string `json:"category_map,omitempty"`
+	// TitleCleanup regex replacements applied to titles in order
+	TitleCleanup []RegexReplace `json:"title_cleanup,omitempty"`
+	// Timezone (IANA name) used for publication dates without an offset
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// RegexReplace is a single regex substitution rule
+type RegexReplace struct {
+	Pattern string `json:"pattern"`
+	Replace string `json:"replace"`
+}
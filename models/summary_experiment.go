@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// SummaryExperimentAssignment is the summary tone the summary tone
+// experiment serves a user until it is drawn again
+type SummaryExperimentAssignment struct {
+	UserID     string    `gorm:"primaryKey" json:"user_id"` // Canonical user
+	Tone       string    `json:"tone"`
+	AssignedAt time.Time `json:"assigned_at"`
+}
+
+// SummaryExperimentArm counts what users served one tone did
+type SummaryExperimentArm struct {
+	Tone         string    `gorm:"primaryKey" json:"tone"`
+	Assignments  int64     `json:"assignments"`
+	Views        int64     `json:"views"`
+	Engagements  int64     `json:"engagements"`   // clicks and shares
+	BadSummaries int64     `json:"bad_summaries"` // bad_summary reports
+	Stopped      bool      `json:"stopped"`       // tripped the bad summary guardrail
+	UpdatedAt    time.Time `json:"updated_at"`
+}
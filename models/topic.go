@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+)
+
+// Topic is a subject extracted from articles (a person, organization, event
+// or theme), linked to articles through the article_topics join table.
+// Names are normalized with utils.NormalizeTopic.
+type Topic struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"uniqueIndex" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TopicFollow is a canonical user following a topic; followed topics boost
+// the user's personal ranking signal
+type TopicFollow struct {
+	ID        uint      `gorm:"primaryKey" json:"-"`
+	UserID    string    `gorm:"uniqueIndex:idx_topic_follow_user_topic" json:"user_id"` // Canonical user
+	TopicID   uint      `gorm:"uniqueIndex:idx_topic_follow_user_topic" json:"-"`
+	Topic     string    `gorm:"-" json:"topic"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TrendingTopic is a topic ranked by recent articles and engagement
+type TrendingTopic struct {
+	Name       string  `json:"name"`
+	Articles   int64   `json:"articles"`   // tagged articles published in the window
+	Engagement float64 `json:"engagement"` // weighted user events on tagged articles in the window
+	Score      float64 `json:"score"`
+}
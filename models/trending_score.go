@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+)
+
+// TrendingScore is the materialized engagement with an article from one grid
+// cell over the trending time window, kept up to date by the trending worker
+type TrendingScore struct {
+	LatCell    int       `gorm:"primaryKey;autoIncrement:false" json:"lat_cell"`
+	LonCell    int       `gorm:"primaryKey;autoIncrement:false" json:"lon_cell"`
+	ArticleID  string    `gorm:"primaryKey" json:"article_id"`
+	Latitude   float64   `gorm:"index:idx_trending_score_location" json:"latitude"` // Mean location of the cell's events
+	Longitude  float64   `gorm:"index:idx_trending_score_location" json:"longitude"`
+	Events     int       `json:"events"`
+	Views      int       `json:"views"` // Events by type, summing to Events
+	Clicks     int       `json:"clicks"`
+	Shares     int       `json:"shares"`
+	Users      int       `json:"users"`  // Distinct users whose first event on the article fell in this cell
+	Weight     float64   `json:"weight"` // Event weights with recency decay as of ComputedAt, capped per user
+	ComputedAt time.Time `json:"computed_at"`
+}
+
+// TrendingScoreColumn returns the column counting events of eventType
+func TrendingScoreColumn(eventType string) string {
+	switch eventType {
+	case EventTypeClick:
+		return "clicks"
+	case EventTypeShare:
+		return "shares"
+	default:
+		return "views"
+	}
+}
+
+// AddEvent counts one event of eventType
+func (t *TrendingScore) AddEvent(eventType string) {
+	t.Events++
+	switch TrendingScoreColumn(eventType) {
+	case "clicks":
+		t.Clicks++
+	case "shares":
+		t.Shares++
+	default:
+		t.Views++
+	}
+}
@@ -2,17 +2,32 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // UserEvent represents a user interaction with an article
 type UserEvent struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
 	ArticleID string    `gorm:"index:idx_article_events" json:"article_id"`
-	UserID    string    `gorm:"index:idx_user_events" json:"user_id"`
-	EventType string    `gorm:"index:idx_event_type" json:"event_type"` // "view", "click", "share"
+	UserID    string    `gorm:"index:idx_user_events" json:"user_id"`     // Canonical user
+	DeviceID  string    `gorm:"index:idx_device_events" json:"device_id"` // Identifier sent by the client
+	EventType string    `gorm:"index:idx_event_type" json:"event_type"`   // "view", "click", "share"
 	Latitude  float64   `json:"latitude"`
 	Longitude float64   `json:"longitude"`
+	DwellMs   int64     `json:"dwell_ms"`                        // Time spent on the article; 0 when not reported
+	Platform  string    `gorm:"default:unknown" json:"platform"` // "ios", "android", "web", "other", "unknown"
 	Timestamp time.Time `gorm:"index:idx_timestamp" json:"timestamp"`
+	// SchemaVersion is the event schema the client sent; old versions are
+	// up-converted on ingestion
+	SchemaVersion int `gorm:"default:1" json:"schema_version"`
+}
+
+// BeforeSave stores the timestamp in UTC. SQLite compares timestamps as
+// text, so events and the bounds queried against them must share a zone.
+func (e *UserEvent) BeforeSave(*gorm.DB) error {
+	e.Timestamp = e.Timestamp.UTC()
+	return nil
 }
 
 // EventType constants
@@ -22,6 +37,16 @@ const (
 	EventTypeShare = "share"
 )
 
+// IsValidEventType reports whether eventType is a known event type
+func IsValidEventType(eventType string) bool {
+	switch eventType {
+	case EventTypeView, EventTypeClick, EventTypeShare:
+		return true
+	default:
+		return false
+	}
+}
+
 // GetEventWeight returns the weight for trending score calculation
 func GetEventWeight(eventType string) float64 {
 	switch eventType {
@@ -39,6 +64,33 @@ func GetEventWeight(eventType string) float64 {
 // TrendingArticle represents an article with trending score
 type TrendingArticle struct {
 	Article
-	TrendingScore float64 `json:"trending_score"`
-	EventCount    int     `json:"event_count"`
+	TrendingScore  float64        `json:"trending_score"`
+	EventCount     int            `json:"event_count"`
+	UniqueUsers    int            `json:"unique_users"`
+	EventBreakdown map[string]int `json:"event_breakdown,omitempty"` // Event type -> count within the radius
+}
+
+// TrendingArticleResponse is an ArticleResponse with the signals that ranked
+// it in trending, so clients can explain the ranking
+type TrendingArticleResponse struct {
+	ArticleResponse
+	TrendingScore  float64        `json:"trending_score"`
+	EventCount     int            `json:"event_count"`
+	UniqueUsers    int            `json:"unique_users"`
+	EventBreakdown map[string]int `json:"event_breakdown"`
+}
+
+// ToResponse converts a TrendingArticle to TrendingArticleResponse
+func (t *TrendingArticle) ToResponse() TrendingArticleResponse {
+	breakdown := t.EventBreakdown
+	if breakdown == nil {
+		breakdown = map[string]int{}
+	}
+	return TrendingArticleResponse{
+		ArticleResponse: t.Article.ToResponse(),
+		TrendingScore:   t.TrendingScore,
+		EventCount:      t.EventCount,
+		UniqueUsers:     t.UniqueUsers,
+		EventBreakdown:  breakdown,
+	}
 }
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+)
+
+// UserInterest is a canonical user's interest embedding: the average of the
+// embeddings of the articles they engaged with, weighted by event weight and
+// decayed with age. It is updated as events arrive.
+type UserInterest struct {
+	UserID     string    `gorm:"primaryKey" json:"user_id"`
+	Model      string    `json:"model"` // Embedding model of the articles folded in
+	Dimensions int       `json:"dimensions"`
+	Vector     []byte    `json:"-"`
+	Weight     float64   `json:"weight"` // Decayed event weight folded in, as of UpdatedAt
+	Events     int       `json:"events"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// SetVector encodes a float32 vector into the Vector blob
+func (i *UserInterest) SetVector(vector []float32) {
+	i.Vector = encodeVector(vector)
+	i.Dimensions = len(vector)
+}
+
+// GetVector decodes the Vector blob into a float32 slice
+func (i *UserInterest) GetVector() []float32 {
+	return decodeVector(i.Vector)
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+)
+
+// UserLink maps a device/user identifier onto a canonical user
+type UserLink struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	CanonicalUserID string    `gorm:"index:idx_canonical_user" json:"canonical_user_id"`
+	LinkedUserID    string    `gorm:"uniqueIndex" json:"linked_user_id"`
+	Method          string    `json:"method"` // "explicit" or "suggested"
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// LinkUserRequest is the body for linking an identifier to a user. Token
+// is a link token issued to UserID, proving the caller holds that device.
+type LinkUserRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+	Token  string `json:"token" binding:"required"`
+}
+
+// DeviceSecret is the secret an identifier is issued when it registers,
+// which it presents to act as that device. Only the secret's hash is stored.
+type DeviceSecret struct {
+	UserID     string    `gorm:"primaryKey" json:"-"`
+	SecretHash string    `json:"-"`
+	CreatedAt  time.Time `json:"-"`
+}
+
+// DeviceSecretResponse is a newly registered device's secret, returned once
+type DeviceSecretResponse struct {
+	UserID       string `json:"user_id"`
+	DeviceSecret string `json:"device_secret"`
+}
+
+// LinkTokenRequest is the body for issuing a link token: the secret the
+// identifier got when it registered
+type LinkTokenRequest struct {
+	DeviceSecret string `json:"device_secret" binding:"required"`
+}
+
+// UserLinkToken is a single-use token issued to an identifier, which
+// another identifier presents to link it. Only the token's hash is stored.
+type UserLinkToken struct {
+	TokenHash string    `gorm:"primaryKey" json:"-"`
+	UserID    string    `gorm:"index:idx_user_link_tokens_user" json:"-"`
+	ExpiresAt time.Time `json:"-"`
+	CreatedAt time.Time `json:"-"`
+}
+
+// LinkTokenResponse is a newly issued link token
+type LinkTokenResponse struct {
+	UserID    string    `json:"user_id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Link methods
+const (
+	LinkMethodExplicit  = "explicit"
+	LinkMethodSuggested = "suggested"
+)
+
+// UserLinkSuggestion is a heuristic guess that another identifier belongs
+// to the same person. Which identifier it is stays hidden, along with its
+// reading history and location: the person links their other device with a
+// link token issued on it.
+type UserLinkSuggestion struct {
+	Score float64 `json:"score"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+)
+
+// UserMute is a source, category or keyword a canonical user never wants
+// to see. Values are stored lowercased.
+type UserMute struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    string    `gorm:"uniqueIndex:idx_user_mute" json:"user_id"` // Canonical user
+	Kind      string    `gorm:"uniqueIndex:idx_user_mute" json:"kind"`
+	Value     string    `gorm:"uniqueIndex:idx_user_mute" json:"value"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MuteRequest is the body for muting a source, category or keyword
+type MuteRequest struct {
+	Kind  string `json:"kind" binding:"required"`
+	Value string `json:"value" binding:"required"`
+}
+
+// Mute kinds
+const (
+	MuteKindSource   = "source"   // articles whose source_name matches
+	MuteKindCategory = "category" // articles in the category
+	MuteKindKeyword  = "keyword"  // articles whose title or description contains the keyword
+)
+
+// IsValidMuteKind reports whether kind is a known mute kind
+func IsValidMuteKind(kind string) bool {
+	switch kind {
+	case MuteKindSource, MuteKindCategory, MuteKindKeyword:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+)
+
+// UserPreference holds a canonical user's defaults for API responses
+type UserPreference struct {
+	UserID      string    `gorm:"primaryKey" json:"user_id"`
+	SummaryTone string    `json:"summary_tone"`
+	SavePlaces  bool      `json:"save_places"` // opt-in to storing named places
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// PreferencesRequest is the body for setting a user's preferences; fields
+// left out keep their current value
+type PreferencesRequest struct {
+	SummaryTone *string `json:"summary_tone"`
+	SavePlaces  *bool   `json:"save_places"`
+}
+
+// Summary tones
+const (
+	SummaryToneSimple      = "simple"
+	SummaryToneNeutral     = "neutral" // default
+	SummaryToneDetailed    = "detailed"
+	SummaryToneKidFriendly = "kid_friendly"
+)
+
+// IsValidSummaryTone reports whether tone is a known summary tone
+func IsValidSummaryTone(tone string) bool {
+	switch tone {
+	case SummaryToneSimple, SummaryToneNeutral, SummaryToneDetailed, SummaryToneKidFriendly:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,78 @@
+package objectstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Dir stores objects as files under a local directory, e.g. a mounted
+// volume shared with the consumers
+type Dir struct {
+	root string
+}
+
+// NewDir creates a store writing under root, creating it if needed
+func NewDir(root string) (*Dir, error) {
+	if root == "" {
+		return nil, fmt.Errorf("store directory is empty")
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+	return &Dir{root: root}, nil
+}
+
+// Put writes the object to a temporary file and renames it into place, so
+// readers never see a partial object
+func (d *Dir) Put(ctx context.Context, key string, body io.ReadSeeker, size int64, _ string) error {
+	path, err := d.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", key, err)
+	}
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind %s: %w", key, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-"+filepath.Base(path))
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", key, err)
+	}
+	defer os.Remove(tmp.Name())
+	written, err := io.Copy(tmp, io.LimitReader(body, size))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	if written != size {
+		return fmt.Errorf("failed to write %s: body has %d bytes, expected %d", key, written, size)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store %s: %w", key, err)
+	}
+	return nil
+}
+
+func (d *Dir) Location(key string) string {
+	return filepath.Join(d.root, filepath.FromSlash(key))
+}
+
+// path resolves key under the root, refusing keys that would leave it
+func (d *Dir) path(key string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(key))
+	if key == "" || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid object key %q", key)
+	}
+	return filepath.Join(d.root, clean), nil
+}
@@ -0,0 +1,38 @@
+package objectstore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDirPut(t *testing.T) {
+	store, err := NewDir(filepath.Join(t.TempDir(), "snapshots"))
+	if err != nil {
+		t.Fatalf("NewDir() error = %v", err)
+	}
+	body := strings.NewReader("rows")
+	body.Seek(2, 0) // Put reads from the start whatever the offset
+	if err := store.Put(context.Background(), "run/articles.jsonl", body, 4, ""); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	data, err := os.ReadFile(store.Location("run/articles.jsonl"))
+	if err != nil || string(data) != "rows" {
+		t.Errorf("stored = %q, %v, expected rows", data, err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(store.Location("run/articles.jsonl")))
+	if len(entries) != 1 {
+		t.Errorf("directory has %d entries, expected no temporary files left", len(entries))
+	}
+}
+
+func TestDirRejectsEscapingKeys(t *testing.T) {
+	store, _ := NewDir(t.TempDir())
+	for _, key := range []string{"", "../x", "a/../../x", "/etc/x"} {
+		if err := store.Put(context.Background(), key, strings.NewReader("x"), 1, ""); err == nil {
+			t.Errorf("Put(%q) succeeded, expected an invalid key", key)
+		}
+	}
+}
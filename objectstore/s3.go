@@ -0,0 +1,212 @@
+package objectstore
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const defaultS3Region = "us-east-1"
+
+// S3 stores objects in a bucket through the S3 REST API, with path-style
+// requests signed by AWS Signature Version 4, so it also works against
+// S3-compatible stores such as MinIO. Each object is sent in one PUT,
+// which S3 limits to 5 GB.
+type S3 struct {
+	bucket   string
+	prefix   string // prepended to every key, without slashes around it
+	endpoint *url.URL
+	signer   signer
+	client   *http.Client
+	now      func() time.Time
+}
+
+// NewS3 creates a store writing to bucket under prefix
+func NewS3(bucket, prefix string, opts S3Options) (*S3, error) {
+	if bucket == "" {
+		return nil, fmt.Errorf("s3 store needs a bucket")
+	}
+	if opts.AccessKey == "" || opts.SecretKey == "" {
+		return nil, fmt.Errorf("s3 store needs an access key and a secret key")
+	}
+	region := opts.Region
+	if region == "" {
+		region = defaultS3Region
+	}
+	endpoint := opts.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + region + ".amazonaws.com"
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", endpoint)
+	}
+	return &S3{
+		bucket:   bucket,
+		prefix:   strings.Trim(prefix, "/"),
+		endpoint: u,
+		signer: signer{
+			accessKey:    opts.AccessKey,
+			secretKey:    opts.SecretKey,
+			sessionToken: opts.SessionToken,
+			region:       region,
+		},
+		client: &http.Client{},
+		now:    time.Now,
+	}, nil
+}
+
+func (s *S3) Put(ctx context.Context, key string, body io.ReadSeeker, size int64, contentType string) error {
+	// The payload is hashed into the signature, so it is read twice
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind %s: %w", key, err)
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(hash, io.LimitReader(body, size)); err != nil {
+		return fmt.Errorf("failed to hash %s: %w", key, err)
+	}
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind %s: %w", key, err)
+	}
+
+	objectPath := strings.TrimSuffix(s.endpoint.Path, "/") + "/" + s.bucket + "/" + s.objectKey(key)
+	u := *s.endpoint
+	u.Path = objectPath
+	u.RawPath = uriEncode(objectPath, false)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), io.LimitReader(body, size))
+	if err != nil {
+		return fmt.Errorf("failed to build request for %s: %w", key, err)
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	s.signer.sign(req, hex.EncodeToString(hash.Sum(nil)), s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to upload %s: status %d: %s", key, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+func (s *S3) Location(key string) string {
+	return "s3://" + s.bucket + "/" + s.objectKey(key)
+}
+
+func (s *S3) objectKey(key string) string {
+	if s.prefix == "" {
+		return key
+	}
+	return s.prefix + "/" + key
+}
+
+// signer signs requests with AWS Signature Version 4 for S3
+type signer struct {
+	accessKey    string
+	secretKey    string
+	sessionToken string
+	region       string
+}
+
+// sign adds the X-Amz-* and Authorization headers to req. Every header
+// already set on req is signed, along with Host.
+func (s signer) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		if strings.EqualFold(name, "Authorization") {
+			continue
+		}
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	for _, part := range []string{s.region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s,SignedHeaders=%s,Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalQuery sorts and encodes query parameters the way SigV4 expects
+func canonicalQuery(query url.Values) string {
+	pairs := make([]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes every byte but the unreserved characters, and
+// slashes unless encodeSlash is set
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package objectstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// Store writes objects under string keys. Keys are slash-separated paths
+// relative to the store's location.
+type Store interface {
+	// Put writes size bytes of body to key, replacing any object there.
+	// body is read from its start and may be read more than once.
+	Put(ctx context.Context, key string, body io.ReadSeeker, size int64, contentType string) error
+	// Location names where key is stored, e.g. s3://bucket/prefix/key
+	Location(key string) string
+}
+
+// S3Options configures S3 (or S3-compatible) stores
+type S3Options struct {
+	Endpoint     string // e.g. http://minio:9000; empty is AWS in Region
+	Region       string
+	AccessKey    string
+	SecretKey    string
+	SessionToken string // for temporary credentials
+}
+
+// Open creates the store for location: s3://bucket/prefix, or a local
+// directory given as a path or a file:// URL
+func Open(location string, opts S3Options) (Store, error) {
+	if !strings.Contains(location, "://") {
+		return NewDir(location)
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid store location %q: %w", location, err)
+	}
+	switch u.Scheme {
+	case "file":
+		return NewDir(u.Path)
+	case "s3":
+		return NewS3(u.Host, strings.Trim(u.Path, "/"), opts)
+	default:
+		return nil, fmt.Errorf("unsupported store location %q (expected a path, file:// or s3://)", location)
+	}
+}
@@ -1,20 +1,28 @@
 package prompts
 
+import (
+	"time"
+
+	"news-backend/models"
+)
+
 // IntentParsingPrompt is the system prompt for intent classification and entity extraction
 const IntentParsingPrompt = `You are an intent classification and entity extraction system for a news retrieval API. 
 Analyze the user's query and return ONLY a valid JSON object with no additional text.
 
 Rules:
-1. Determine the primary intent from: "category", "source", "search", "nearby", "score"
+1. Determine the primary intent from: "category", "source", "search", "nearby", "score", "discovery"
 2. Extract relevant entities (people, organizations, locations, events, query terms, etc.)
-3. Return only the JSON, no markdown, no explanations
+3. If the query mentions a time period ("yesterday", "last week", "since March"), resolve it against today's date and add "from" and/or "to" entities as YYYY-MM-DD
+4. Return only the JSON, no markdown, no explanations
 
 Intent definitions:
 - "category": User wants news from specific category (Technology, Business, Sports, etc.)
 - "source": User wants news from specific source (e.g., "New York Times", "Reuters")
 - "nearby": User wants local news near a location
 - "score": User wants highly relevant/trending news
-- "search": Default for general queries or specific topic search
+- "discovery": User wants the latest news in general, with no topic, source or place ("what's happening today", "catch me up")
+- "search": Default for specific topic search
 
 Example 1:
 Query: "Latest developments in the Elon Musk Twitter acquisition near Palo Alto"
@@ -54,8 +62,63 @@ Output: {
   "entities": {"source": "Reuters"}
 }
 
+Example 5:
+Query: "Anything new I should know about?"
+Output: {
+  "intent": "discovery",
+  "entities": {}
+}
+
+Example 6 (today is 2025-03-12):
+Query: "Tesla news from last week"
+Output: {
+  "intent": "search",
+  "entities": {
+    "query": "Tesla",
+    "organizations": ["Tesla"],
+    "from": "2025-03-03",
+    "to": "2025-03-09"
+  }
+}
+
 Return ONLY the JSON object.`
 
+// IntentParsingPromptFor returns the intent prompt with today's date so
+// relative dates in the query can be resolved
+func IntentParsingPromptFor(now time.Time) string {
+	return IntentParsingPrompt + "\n\nToday's date is " + now.Format("2006-01-02") + "."
+}
+
+// FollowUpIntentPrompt is the system prompt for merging a follow-up query
+// into the intent of the previous turn of a conversation
+const FollowUpIntentPrompt = `You are an intent classification and entity extraction system for a news retrieval API, continuing a conversation.
+You are given the intent resolved for the previous query as JSON and the user's follow-up query.
+Return ONLY a valid JSON object with no additional text: the complete intent after applying the follow-up.
+
+Rules:
+1. Intents and entities are the same as for a single query: "category", "source", "search", "nearby", "score", "discovery"
+2. A follow-up that adds a constraint ("only from Reuters", "just sports", "from yesterday") keeps every previous entity and adds or replaces the constrained one
+3. A follow-up that changes the subject ("what about Tesla?") replaces the "query" entity and keeps the other constraints
+4. A follow-up that asks for something unrelated starts over: ignore the previous intent
+5. Always include the "query" entity with the search terms that still apply
+6. Keep "lat", "lon" and "radius" from the previous intent unchanged
+
+Example:
+Previous: {"intent": "search", "entities": {"query": "cricket"}}
+Follow-up: "only from Reuters"
+Output: {
+  "intent": "search",
+  "entities": {"query": "cricket", "source": "Reuters"}
+}
+
+Return ONLY the JSON object.`
+
+// FollowUpIntentPromptFor returns the follow-up prompt with today's date so
+// relative dates in the follow-up can be resolved
+func FollowUpIntentPromptFor(now time.Time) string {
+	return FollowUpIntentPrompt + "\n\nToday's date is " + now.Format("2006-01-02") + "."
+}
+
 // SummaryPrompt is the system prompt for generating article summaries
 const SummaryPrompt = `You are a news summarization engine. Create a concise, factual one-sentence summary of the article.
 Requirements:
@@ -64,3 +127,83 @@ Requirements:
 - Be objective and factual
 - No opinions or editorializing
 - If content is insufficient, return "Summary unavailable."`
+
+// summaryToneInstructions replace SummaryPrompt's requirements for the
+// non-default tones
+var summaryToneInstructions = map[string]string{
+	models.SummaryToneSimple: `Requirements:
+- One or two short sentences
+- Plain, everyday words that an adult learner of English can follow
+- Explain or avoid jargon and abbreviations
+- Be objective and factual, no opinions`,
+	models.SummaryToneDetailed: `Requirements:
+- Three to four sentences
+- Cover the main point, the key people or organizations involved, and why it matters
+- Keep figures, dates and places from the article
+- Be objective and factual, no opinions or editorializing`,
+	models.SummaryToneKidFriendly: `Requirements:
+- One or two short sentences a 10-year-old can understand
+- Simple words, no jargon
+- Describe violent or distressing events gently and without graphic detail
+- Be factual, no opinions`,
+}
+
+// SummaryPromptFor returns the summary system prompt for a tone, falling
+// back to SummaryPrompt for the neutral or an unknown tone
+func SummaryPromptFor(tone string) string {
+	instructions, ok := summaryToneInstructions[tone]
+	if !ok {
+		return SummaryPrompt
+	}
+	return `You are a news summarization engine. Summarize the article.
+` + instructions + `
+- If content is insufficient, return "Summary unavailable."`
+}
+
+// SentimentPrompt is the system prompt for tagging an article's sentiment
+// and tone
+const SentimentPrompt = `You classify news articles. Read the article and answer with JSON only, no explanation:
+{"sentiment": "...", "tone": "..."}
+
+sentiment is how the news itself reads for the people it is about:
+- "positive": wins, progress, recoveries, good outcomes
+- "negative": deaths, disasters, crime, losses, conflict
+- "neutral": neither, or evenly mixed
+
+tone is how the article is written:
+- "urgent": breaking news, alerts, warnings to act now
+- "opinion": columns, editorials, commentary
+- "analytical": explainers, analysis, studies and data
+- "factual": straight reporting of what happened`
+
+// TopicPrompt is the system prompt for extracting an article's topics
+const TopicPrompt = `You extract topics from news articles for a topic-follow feature.
+Return ONLY a JSON array of 1 to 5 short topics, most important first, no explanation:
+["topic", ...]
+
+Topics are what a reader might follow: named people, organizations, places, events,
+products or themes (e.g. "IPL 2025", "Reserve Bank of India", "electric vehicles").
+- Use the common name, 1 to 4 words, no hashtags
+- Skip generic words like "news", "update" or "report"
+- Skip the article's broad section (sports, business, world) unless it is the only topic`
+
+// DigestPrompt is the system prompt for writing a local news briefing from
+// the day's top articles
+const DigestPrompt = `You write a daily news briefing for readers in one area.
+You are given the day's top articles, numbered, most important first, each with its source.
+Write a cohesive briefing of 3 to 5 short paragraphs in plain text:
+- Open with the most important story, then group related stories together
+- Use only facts from the articles; do not speculate or add background you weren't given
+- Mention sources by name where it helps ("according to Reuters"), no URLs or article numbers
+- Neutral tone, no headline, no bullet points, no sign-off`
+
+// AnswerPrompt is the system prompt for answering a question from retrieved
+// articles
+const AnswerPrompt = `You answer questions about the news using only the numbered articles you are given.
+Return ONLY a JSON object, no markdown:
+{"answer": "...", "citations": [1, 3]}
+
+- Base every statement on the articles; do not use outside knowledge or speculate
+- Cite the numbers of the articles the answer relies on, most important first
+- Keep the answer to a few sentences and mention dates when they matter
+- If the articles don't answer the question, say so in "answer" and return "citations": []`
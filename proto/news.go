@@ -0,0 +1,159 @@
+// Package newsv1 encodes the messages of news.proto with protowire, so
+// the wire format stays hand-maintained next to the schema rather than
+// generated. Field numbers are stable; only add fields.
+package newsv1
+
+import (
+	"math"
+	"sort"
+
+	"news-backend/models"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// EncodeArticleList encodes a news.v1.ArticleList message, marked as demo
+// when served to the demo tier
+func EncodeArticleList(articles []models.ArticleResponse, metadata *models.ResponseMetadata, demo bool) []byte {
+	var b []byte
+	for i := range articles {
+		b = AppendMessage(b, 1, EncodeArticle(&articles[i]))
+	}
+	if metadata != nil {
+		b = AppendMessage(b, 2, EncodeMetadata(metadata))
+	}
+	b = AppendBool(b, 3, demo)
+	return b
+}
+
+// EncodeArticle encodes a news.v1.Article message
+func EncodeArticle(a *models.ArticleResponse) []byte {
+	var b []byte
+	b = AppendString(b, 1, a.Title)
+	b = AppendString(b, 2, a.Description)
+	b = AppendString(b, 3, a.URL)
+	if !a.PublicationDate.IsZero() {
+		b = protowire.AppendTag(b, 4, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(a.PublicationDate.Unix()))
+	}
+	b = AppendString(b, 5, a.SourceName)
+	b = AppendString(b, 6, a.Category)
+	b = AppendDouble(b, 7, a.RelevanceScore)
+	b = AppendDouble(b, 8, a.CurrentRelevance)
+	b = AppendString(b, 9, a.LLMSummary)
+	b = AppendDouble(b, 10, a.Latitude)
+	b = AppendDouble(b, 11, a.Longitude)
+	b = AppendDouble(b, 12, a.Distance)
+	b = AppendDouble(b, 13, a.Similarity)
+	for _, key := range SortedKeys(a.ScoreBreakdown) {
+		var entry []byte
+		entry = AppendString(entry, 1, key)
+		entry = AppendDouble(entry, 2, a.ScoreBreakdown[key])
+		b = AppendMessage(b, 14, entry)
+	}
+	b = AppendString(b, 15, a.StoryID)
+	b = AppendString(b, 16, a.Sentiment)
+	b = AppendString(b, 17, a.Tone)
+	b = AppendString(b, 18, a.Publisher)
+	b = AppendString(b, 19, a.License)
+	b = AppendString(b, 20, a.Attribution)
+	b = AppendString(b, 21, a.IngestSource)
+	for _, field := range a.Redacted {
+		b = AppendString(b, 22, field)
+	}
+	b = AppendString(b, 23, a.ImageURL)
+	b = AppendString(b, 24, a.Layout)
+	b = AppendString(b, 25, a.ThumbnailURL)
+	return b
+}
+
+// EncodeMetadata encodes a news.v1.ResponseMetadata message
+func EncodeMetadata(m *models.ResponseMetadata) []byte {
+	var b []byte
+	b = AppendInt(b, 1, m.Count)
+	b = AppendInt(b, 2, m.TotalAvailable)
+	b = AppendInt(b, 3, m.Page)
+	b = AppendInt(b, 4, m.PageSize)
+	b = AppendString(b, 5, m.Query)
+	for _, key := range SortedKeys(m.Filters) {
+		var entry []byte
+		entry = AppendString(entry, 1, key)
+		entry = AppendString(entry, 2, m.Filters[key])
+		b = AppendMessage(b, 6, entry)
+	}
+	b = AppendString(b, 7, m.Summaries)
+	if m.Ranking != nil {
+		b = AppendMessage(b, 8, encodeRanking(m.Ranking))
+	}
+	for _, key := range SortedKeys(m.Clamped) {
+		var entry []byte
+		entry = AppendString(entry, 1, key)
+		entry = AppendString(entry, 2, m.Clamped[key])
+		b = AppendMessage(b, 9, entry)
+	}
+	b = AppendString(b, 10, m.NextCursor)
+	return b
+}
+
+// encodeRanking encodes a news.v1.RankingInfo message
+func encodeRanking(r *models.RankingInfo) []byte {
+	var b []byte
+	b = AppendString(b, 1, r.Profile)
+	for _, key := range SortedKeys(r.Weights) {
+		var entry []byte
+		entry = AppendString(entry, 1, key)
+		entry = AppendDouble(entry, 2, r.Weights[key])
+		b = AppendMessage(b, 2, entry)
+	}
+	b = AppendBool(b, 3, r.Personalized)
+	return b
+}
+
+// The append helpers skip zero values, matching proto3 default semantics
+
+func AppendString(b []byte, num protowire.Number, v string) []byte {
+	if v == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
+
+func AppendDouble(b []byte, num protowire.Number, v float64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, math.Float64bits(v))
+}
+
+func AppendInt(b []byte, num protowire.Number, v int) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, uint64(int64(v)))
+}
+
+func AppendBool(b []byte, num protowire.Number, v bool) []byte {
+	if !v {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, 1)
+}
+
+func AppendMessage(b []byte, num protowire.Number, msg []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, msg)
+}
+
+// SortedKeys returns map keys in order so encoding is deterministic
+func SortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
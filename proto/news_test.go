@@ -0,0 +1,276 @@
+package newsv1
+
+import (
+	"math"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"news-backend/models"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// protoFieldPattern matches a field declaration in news.proto
+var protoFieldPattern = regexp.MustCompile(`^\s*(repeated\s+)?(map<[^>]+>|\w+)\s+(\w+)\s*=\s*(\d+);`)
+
+// protoWireTypes are the wire types of the scalar and map types news.proto uses
+var protoWireTypes = map[string]protowire.Type{
+	"string": protowire.BytesType,
+	"int32":  protowire.VarintType,
+	"int64":  protowire.VarintType,
+	"bool":   protowire.VarintType,
+	"double": protowire.Fixed64Type,
+}
+
+// protoMessageFields reads the field numbers and wire types of a message
+// from news.proto
+func protoMessageFields(t *testing.T, message string) map[protowire.Number]protowire.Type {
+	t.Helper()
+	data, err := os.ReadFile("news.proto")
+	if err != nil {
+		t.Fatalf("failed to read news.proto: %v", err)
+	}
+	fields := make(map[protowire.Number]protowire.Type)
+	inMessage := false
+	for _, line := range strings.Split(string(data), "\n") {
+		switch {
+		case strings.HasPrefix(line, "message "+message+" {"):
+			inMessage = true
+		case inMessage && strings.HasPrefix(line, "}"):
+			return fields
+		case inMessage:
+			match := protoFieldPattern.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+			num, _ := strconv.Atoi(match[4])
+			typ, ok := protoWireTypes[match[2]]
+			if !ok {
+				// Maps and messages are length-delimited
+				typ = protowire.BytesType
+			}
+			fields[protowire.Number(num)] = typ
+		}
+	}
+	t.Fatalf("message %s not found in news.proto", message)
+	return nil
+}
+
+// consumeFields splits a message into its fields, failing on malformed input
+func consumeFields(t *testing.T, b []byte, field func(num protowire.Number, typ protowire.Type, value []byte)) {
+	t.Helper()
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			t.Fatalf("malformed tag: %v", protowire.ParseError(n))
+		}
+		b = b[n:]
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			t.Fatalf("malformed field %d: %v", num, protowire.ParseError(n))
+		}
+		field(num, typ, b[:n])
+		b = b[n:]
+	}
+}
+
+func decodeString(t *testing.T, value []byte) string {
+	t.Helper()
+	s, n := protowire.ConsumeString(value)
+	if n < 0 {
+		t.Fatalf("malformed string: %v", protowire.ParseError(n))
+	}
+	return s
+}
+
+func decodeBytes(t *testing.T, value []byte) []byte {
+	t.Helper()
+	b, n := protowire.ConsumeBytes(value)
+	if n < 0 {
+		t.Fatalf("malformed bytes: %v", protowire.ParseError(n))
+	}
+	return b
+}
+
+func decodeDouble(t *testing.T, value []byte) float64 {
+	t.Helper()
+	v, n := protowire.ConsumeFixed64(value)
+	if n < 0 {
+		t.Fatalf("malformed double: %v", protowire.ParseError(n))
+	}
+	return math.Float64frombits(v)
+}
+
+func decodeVarint(t *testing.T, value []byte) uint64 {
+	t.Helper()
+	v, n := protowire.ConsumeVarint(value)
+	if n < 0 {
+		t.Fatalf("malformed varint: %v", protowire.ParseError(n))
+	}
+	return v
+}
+
+// decodeArticle decodes a news.v1.Article, checking each field's wire type
+// against news.proto
+func decodeArticle(t *testing.T, b []byte, schema map[protowire.Number]protowire.Type, seen map[protowire.Number]bool) models.ArticleResponse {
+	t.Helper()
+	var a models.ArticleResponse
+	consumeFields(t, b, func(num protowire.Number, typ protowire.Type, value []byte) {
+		want, ok := schema[num]
+		if !ok {
+			t.Errorf("field %d is not in news.proto", num)
+			return
+		}
+		if typ != want {
+			t.Errorf("field %d has wire type %v, news.proto declares %v", num, typ, want)
+			return
+		}
+		seen[num] = true
+		switch num {
+		case 1:
+			a.Title = decodeString(t, value)
+		case 2:
+			a.Description = decodeString(t, value)
+		case 3:
+			a.URL = decodeString(t, value)
+		case 4:
+			a.PublicationDate = time.Unix(int64(decodeVarint(t, value)), 0).UTC()
+		case 5:
+			a.SourceName = decodeString(t, value)
+		case 6:
+			a.Category = decodeString(t, value)
+		case 7:
+			a.RelevanceScore = decodeDouble(t, value)
+		case 8:
+			a.CurrentRelevance = decodeDouble(t, value)
+		case 9:
+			a.LLMSummary = decodeString(t, value)
+		case 10:
+			a.Latitude = decodeDouble(t, value)
+		case 11:
+			a.Longitude = decodeDouble(t, value)
+		case 12:
+			a.Distance = decodeDouble(t, value)
+		case 13:
+			a.Similarity = decodeDouble(t, value)
+		case 14:
+			var key string
+			var score float64
+			consumeFields(t, decodeBytes(t, value), func(num protowire.Number, typ protowire.Type, value []byte) {
+				switch {
+				case num == 1 && typ == protowire.BytesType:
+					key = decodeString(t, value)
+				case num == 2 && typ == protowire.Fixed64Type:
+					score = decodeDouble(t, value)
+				default:
+					t.Errorf("score_breakdown entry field %d has wire type %v", num, typ)
+				}
+			})
+			if a.ScoreBreakdown == nil {
+				a.ScoreBreakdown = make(map[string]float64)
+			}
+			a.ScoreBreakdown[key] = score
+		case 15:
+			a.StoryID = decodeString(t, value)
+		case 16:
+			a.Sentiment = decodeString(t, value)
+		case 17:
+			a.Tone = decodeString(t, value)
+		case 18:
+			a.Publisher = decodeString(t, value)
+		case 19:
+			a.License = decodeString(t, value)
+		case 20:
+			a.Attribution = decodeString(t, value)
+		case 21:
+			a.IngestSource = decodeString(t, value)
+		case 22:
+			a.Redacted = append(a.Redacted, decodeString(t, value))
+		case 23:
+			a.ImageURL = decodeString(t, value)
+		case 24:
+			a.Layout = decodeString(t, value)
+		case 25:
+			a.ThumbnailURL = decodeString(t, value)
+		default:
+			t.Errorf("field %d of news.proto is not decoded by this test", num)
+		}
+	})
+	return a
+}
+
+func TestEncodeArticleListRoundTrip(t *testing.T) {
+	articleSchema := protoMessageFields(t, "Article")
+	if len(articleSchema) != 25 {
+		t.Fatalf("news.proto declares %d Article fields, expected 25", len(articleSchema))
+	}
+	listSchema := protoMessageFields(t, "ArticleList")
+
+	article := models.ArticleResponse{
+		Title:            "Monsoon arrives early",
+		Description:      "Rain reaches the coast a week ahead of schedule.",
+		URL:              "https://example.com/monsoon",
+		PublicationDate:  time.Date(2026, 3, 26, 9, 30, 15, 0, time.UTC),
+		SourceName:       "Example Times",
+		Category:         "Weather,India",
+		RelevanceScore:   0.82,
+		CurrentRelevance: 0.61,
+		LLMSummary:       "The monsoon came early.",
+		Latitude:         9.9312,
+		Longitude:        -76.2673,
+		Distance:         12.5,
+		Similarity:       0.93,
+		ScoreBreakdown:   map[string]float64{"relevance": 0.4, "recency": 0.25},
+		StoryID:          "story-1",
+		Sentiment:        "neutral",
+		Tone:             "simple",
+		Publisher:        "Example Media",
+		License:          "CC-BY-4.0",
+		Attribution:      "Example Media, CC BY 4.0",
+		IngestSource:     "connector:rss",
+		Redacted:         []string{"summary", "url"},
+		ImageURL:         "https://example.com/monsoon.jpg",
+		Layout:           "hero",
+		ThumbnailURL:     "/api/v1/images/abc",
+	}
+	metadata := models.NewResponseMetadata(2, 40, "monsoon", nil)
+	encoded := EncodeArticleList([]models.ArticleResponse{article, {Title: "Second"}}, metadata, true)
+
+	var articles []models.ArticleResponse
+	seen := make(map[protowire.Number]bool)
+	var gotMetadata, demo bool
+	consumeFields(t, encoded, func(num protowire.Number, typ protowire.Type, value []byte) {
+		if want, ok := listSchema[num]; !ok || typ != want {
+			t.Errorf("ArticleList field %d has wire type %v, news.proto declares %v (declared: %v)", num, typ, want, ok)
+			return
+		}
+		switch num {
+		case 1:
+			articles = append(articles, decodeArticle(t, decodeBytes(t, value), articleSchema, seen))
+		case 2:
+			gotMetadata = true
+		case 3:
+			demo = decodeVarint(t, value) == 1
+		}
+	})
+
+	if len(articles) != 2 || !gotMetadata || !demo {
+		t.Fatalf("decoded %d articles, metadata %v, demo %v, expected 2 articles with metadata, marked demo", len(articles), gotMetadata, demo)
+	}
+	for num := range articleSchema {
+		if !seen[num] {
+			t.Errorf("Article field %d was not encoded", num)
+		}
+	}
+	if !reflect.DeepEqual(articles[0], article) {
+		t.Errorf("decoded article = %+v, expected %+v", articles[0], article)
+	}
+	if !reflect.DeepEqual(articles[1], models.ArticleResponse{Title: "Second"}) {
+		t.Errorf("decoded second article = %+v, expected only its title", articles[1])
+	}
+}
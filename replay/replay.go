@@ -0,0 +1,321 @@
+// Package replay re-sends a window of logged production requests to a
+// target environment, at their original pace or faster, and compares its
+// status codes and result counts with the logged ones, to validate a
+// release or its capacity before rollout.
+package replay
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Header marks replayed requests, e.g. to tell them apart in the target's logs
+const Header = "X-Replay-Request"
+
+// logTimeLayout is the timestamp the standard logger prefixes lines with
+const logTimeLayout = "2006/01/02 15:04:05"
+
+// maxInFlight bounds concurrent requests when the target falls behind the
+// replay schedule
+const maxInFlight = 64
+
+// logLine matches the request lines middleware.Logger writes:
+// "2026/03/26 09:00:00 [GET] /api/v1/news/latest since=2026-03-20 | Status: 200 | ... | Results: 5"
+var logLine = regexp.MustCompile(`^(\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}) \[([A-Z]+)\] (\S+) (\S*) \| Status: (\d{3}) \|.*?(?:\| Results: (\d+))?$`)
+
+// Entry is one request read from the request log
+type Entry struct {
+	Time    time.Time
+	Method  string
+	Path    string
+	Query   string
+	Status  int
+	Results int // -1 when the logged response had no article list
+}
+
+// ParseLine parses a request log line. ok is false for any other line.
+// Log timestamps are in the local time zone, as the logger writes them.
+func ParseLine(line string) (entry Entry, ok bool) {
+	match := logLine.FindStringSubmatch(strings.TrimSpace(line))
+	if match == nil {
+		return Entry{}, false
+	}
+	at, err := time.ParseInLocation(logTimeLayout, match[1], time.Local)
+	if err != nil {
+		return Entry{}, false
+	}
+	entry = Entry{Time: at, Method: match[2], Path: match[3], Query: match[4], Results: -1}
+	entry.Status, _ = strconv.Atoi(match[5])
+	if match[6] != "" {
+		entry.Results, _ = strconv.Atoi(match[6])
+	}
+	return entry, true
+}
+
+// Replayable reports whether a logged request is safe to send again: reads
+// outside the admin API, which writes or needs credentials the log lacks
+func (e Entry) Replayable() bool {
+	return e.Method == http.MethodGet && !strings.HasPrefix(e.Path, "/api/v1/admin")
+}
+
+// Load reads the replayable requests logged within [from, to) from r, a
+// zero bound leaving that end open, and keeps a random sample (0-1) of them
+func Load(r io.Reader, from, to time.Time, sample float64, rng *rand.Rand) ([]Entry, error) {
+	var entries []Entry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		entry, ok := ParseLine(scanner.Text())
+		if !ok || !entry.Replayable() {
+			continue
+		}
+		if (!from.IsZero() && entry.Time.Before(from)) || (!to.IsZero() && !entry.Time.Before(to)) {
+			continue
+		}
+		if sample < 1 && rng.Float64() >= sample {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read request log: %w", err)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+	return entries, nil
+}
+
+// Options configures a replay
+type Options struct {
+	Target  string        // base URL of the environment under test
+	Speed   float64       // 1 keeps the logged pace, 2 doubles it, 0 sends as fast as possible
+	Timeout time.Duration // per request
+}
+
+// Result is how the target answered one replayed request
+type Result struct {
+	Entry   Entry
+	Status  int // 0 when the request failed
+	Results int // -1 when the response has no article list
+	Latency time.Duration
+	Error   string
+}
+
+// Run sends every entry to the target, spaced like they were logged (scaled
+// by Speed), and returns the results in entry order. Requests don't wait
+// for earlier ones, so a slow target builds up load as production would.
+func Run(ctx context.Context, entries []Entry, opts Options) ([]Result, error) {
+	target, err := url.Parse(opts.Target)
+	if err != nil || target.Scheme == "" || target.Host == "" {
+		return nil, fmt.Errorf("invalid replay target %q", opts.Target)
+	}
+	client := &http.Client{Timeout: opts.Timeout}
+
+	results := make([]Result, len(entries))
+	slots := make(chan struct{}, maxInFlight)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i, entry := range entries {
+		if opts.Speed > 0 {
+			offset := time.Duration(float64(entry.Time.Sub(entries[0].Time)) / opts.Speed)
+			select {
+			case <-time.After(time.Until(start.Add(offset))):
+			case <-ctx.Done():
+				wg.Wait()
+				return results[:i], ctx.Err()
+			}
+		}
+
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(i int, entry Entry) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			results[i] = send(ctx, client, *target, entry)
+		}(i, entry)
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// send replays one request against target
+func send(ctx context.Context, client *http.Client, target url.URL, entry Entry) Result {
+	result := Result{Entry: entry, Results: -1}
+	target.Path = strings.TrimSuffix(target.Path, "/") + entry.Path
+	target.RawQuery = entry.Query
+
+	req, err := http.NewRequestWithContext(ctx, entry.Method, target.String(), nil)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	req.Header.Set(Header, "1")
+	req.Header.Set("Accept", "application/json")
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	result.Latency = time.Since(start)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Status = resp.StatusCode
+	result.Results = articleCount(body)
+	return result
+}
+
+// articleCount returns the length of a JSON body's article list, or -1
+func articleCount(body []byte) int {
+	var doc struct {
+		Articles *[]json.RawMessage `json:"articles"`
+	}
+	if json.Unmarshal(body, &doc) != nil || doc.Articles == nil {
+		return -1
+	}
+	return len(*doc.Articles)
+}
+
+// EndpointReport compares one endpoint's logged and replayed responses.
+// Result counts only cover requests with a count on both sides.
+type EndpointReport struct {
+	Endpoint       string  `json:"endpoint"`
+	Requests       int     `json:"requests"`
+	StatusMatched  int     `json:"status_matched"`
+	Compared       int     `json:"compared"` // requests with result counts on both sides
+	LoggedMean     float64 `json:"logged_mean_results"`
+	TargetMean     float64 `json:"target_mean_results"`
+	LoggedEmpty    int     `json:"logged_empty"` // responses without results
+	TargetEmpty    int     `json:"target_empty"`
+	ResultsChanged int     `json:"results_changed"` // requests whose count differs
+}
+
+// Report summarizes a replay
+type Report struct {
+	Requests      int              `json:"requests"`
+	StatusMatched int              `json:"status_matched"`
+	Failed        int              `json:"failed"`         // requests that got no response
+	StatusChanges map[string]int   `json:"status_changes"` // "200 -> 500" -> requests
+	LatencyP50    time.Duration    `json:"latency_p50"`
+	LatencyP95    time.Duration    `json:"latency_p95"`
+	Endpoints     []EndpointReport `json:"endpoints"` // by request count, descending
+}
+
+// MismatchRate is the share of requests whose status differs from the logged one
+func (r Report) MismatchRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return 1 - float64(r.StatusMatched)/float64(r.Requests)
+}
+
+// Summarize compares the replayed results with the logged ones, grouping
+// requests by endpoint
+func Summarize(results []Result) Report {
+	report := Report{Requests: len(results), StatusChanges: map[string]int{}}
+	endpoints := map[string]*EndpointReport{}
+	var latencies []time.Duration
+	for _, result := range results {
+		name := Endpoint(result.Entry.Path)
+		endpoint, ok := endpoints[name]
+		if !ok {
+			endpoint = &EndpointReport{Endpoint: name}
+			endpoints[name] = endpoint
+		}
+		endpoint.Requests++
+
+		if result.Error != "" {
+			report.Failed++
+			report.StatusChanges[fmt.Sprintf("%d -> error", result.Entry.Status)]++
+			continue
+		}
+		latencies = append(latencies, result.Latency)
+		if result.Status == result.Entry.Status {
+			report.StatusMatched++
+			endpoint.StatusMatched++
+		} else {
+			report.StatusChanges[fmt.Sprintf("%d -> %d", result.Entry.Status, result.Status)]++
+		}
+
+		if result.Entry.Results < 0 || result.Results < 0 {
+			continue
+		}
+		endpoint.Compared++
+		endpoint.LoggedMean += float64(result.Entry.Results)
+		endpoint.TargetMean += float64(result.Results)
+		if result.Entry.Results == 0 {
+			endpoint.LoggedEmpty++
+		}
+		if result.Results == 0 {
+			endpoint.TargetEmpty++
+		}
+		if result.Results != result.Entry.Results {
+			endpoint.ResultsChanged++
+		}
+	}
+
+	for _, endpoint := range endpoints {
+		if endpoint.Compared > 0 {
+			endpoint.LoggedMean /= float64(endpoint.Compared)
+			endpoint.TargetMean /= float64(endpoint.Compared)
+		}
+		report.Endpoints = append(report.Endpoints, *endpoint)
+	}
+	sort.Slice(report.Endpoints, func(i, j int) bool {
+		if report.Endpoints[i].Requests != report.Endpoints[j].Requests {
+			return report.Endpoints[i].Requests > report.Endpoints[j].Requests
+		}
+		return report.Endpoints[i].Endpoint < report.Endpoints[j].Endpoint
+	})
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	report.LatencyP50 = percentile(latencies, 0.5)
+	report.LatencyP95 = percentile(latencies, 0.95)
+	return report
+}
+
+// percentile returns the p-th (0-1) of sorted latencies, nearest rank
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(float64(len(sorted))*p+0.5) - 1
+	return sorted[min(max(rank, 0), len(sorted)-1)]
+}
+
+// idSegment matches path segments that are IDs rather than route names:
+// numbers and UUID-like or otherwise long hex strings
+var idSegment = regexp.MustCompile(`^([0-9]+|[0-9a-fA-F-]{16,})$`)
+
+// Endpoint groups a request path with others of its route by replacing ID
+// segments, e.g. /api/v1/articles/19aaddc0-... becomes /api/v1/articles/:id
+func Endpoint(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if idSegment.MatchString(segment) {
+			segments[i] = ":id"
+		}
+	}
+	return strings.Join(segments, "/")
+}
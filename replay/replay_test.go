@@ -0,0 +1,153 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLine(t *testing.T) {
+	line := "2026/03/26 09:00:00 [GET] /api/v1/news/latest since=2026-03-20 | Status: 200 | Latency: 1.2ms | IP: 10.0.0.1 | Query: since=2026-03-20 | Results: 5"
+	entry, ok := ParseLine(line)
+	if !ok {
+		t.Fatalf("ParseLine(%q) not ok", line)
+	}
+	expected := time.Date(2026, 3, 26, 9, 0, 0, 0, time.Local)
+	if !entry.Time.Equal(expected) || entry.Method != "GET" || entry.Path != "/api/v1/news/latest" ||
+		entry.Query != "since=2026-03-20" || entry.Status != 200 || entry.Results != 5 {
+		t.Errorf("ParseLine() = %+v, unexpected fields", entry)
+	}
+
+	t.Run("Without query or result count", func(t *testing.T) {
+		entry, ok := ParseLine("2026/03/26 09:00:01 [GET] /api/v1/health  | Status: 200 | Latency: 10µs | IP: 10.0.0.1 | Query: ")
+		if !ok || entry.Query != "" || entry.Results != -1 {
+			t.Errorf("ParseLine() = %+v, %v, expected no query and Results -1", entry, ok)
+		}
+	})
+
+	t.Run("Other lines are skipped", func(t *testing.T) {
+		for _, line := range []string{
+			"2026/03/26 09:00:00 Database initialized",
+			"",
+			"[GET] /api/v1/health  | Status: 200",
+		} {
+			if _, ok := ParseLine(line); ok {
+				t.Errorf("ParseLine(%q) ok, expected skipped", line)
+			}
+		}
+	})
+}
+
+func TestLoad(t *testing.T) {
+	log := strings.Join([]string{
+		"2026/03/26 08:59:59 [GET] /api/v1/news/latest  | Status: 200 | Latency: 1ms | IP: x | Query: ",
+		"2026/03/26 09:00:00 [GET] /api/v1/news/latest  | Status: 200 | Latency: 1ms | IP: x | Query:  | Results: 5",
+		"2026/03/26 09:00:01 [POST] /api/v1/trending/event  | Status: 200 | Latency: 1ms | IP: x | Query: ",
+		"2026/03/26 09:00:02 [GET] /api/v1/admin/metrics  | Status: 200 | Latency: 1ms | IP: x | Query: ",
+		"Some other log line",
+		"2026/03/26 09:00:03 [GET] /api/v1/trending lat=1&lon=2 | Status: 200 | Latency: 1ms | IP: x | Query: lat=1&lon=2 | Results: 3",
+		"2026/03/26 10:00:00 [GET] /api/v1/news/latest  | Status: 200 | Latency: 1ms | IP: x | Query: ",
+	}, "\n")
+	from := time.Date(2026, 3, 26, 9, 0, 0, 0, time.Local)
+	to := time.Date(2026, 3, 26, 10, 0, 0, 0, time.Local)
+	rng := rand.New(rand.NewSource(1))
+
+	entries, err := Load(strings.NewReader(log), from, to, 1, rng)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Path != "/api/v1/news/latest" || entries[1].Path != "/api/v1/trending" {
+		t.Errorf("Load() = %+v, expected the two GETs outside admin within the window", entries)
+	}
+
+	t.Run("Sampling keeps a share", func(t *testing.T) {
+		var lines []string
+		for i := 0; i < 1000; i++ {
+			lines = append(lines, "2026/03/26 09:00:00 [GET] /api/v1/news/latest  | Status: 200 | Latency: 1ms | IP: x | Query: ")
+		}
+		entries, err := Load(strings.NewReader(strings.Join(lines, "\n")), time.Time{}, time.Time{}, 0.1, rng)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if len(entries) < 50 || len(entries) > 150 {
+			t.Errorf("Load() kept %d of 1000 at a 0.1 sample, expected about 100", len(entries))
+		}
+	})
+}
+
+func TestRunAndSummarize(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(Header) == "" {
+			t.Errorf("request without %s header", Header)
+		}
+		switch r.URL.Path {
+		case "/api/v1/news/latest":
+			fmt.Fprint(w, `{"articles": [{}, {}, {}], "metadata": {}}`)
+		case "/api/v1/trending":
+			fmt.Fprint(w, `{"articles": []}`)
+		default:
+			http.Error(w, `{"error": "Internal error"}`, http.StatusInternalServerError)
+		}
+	}))
+	defer target.Close()
+
+	at := time.Date(2026, 3, 26, 9, 0, 0, 0, time.Local)
+	entries := []Entry{
+		{Time: at, Method: "GET", Path: "/api/v1/news/latest", Status: 200, Results: 5},
+		{Time: at, Method: "GET", Path: "/api/v1/news/latest", Query: "source=News18", Status: 200, Results: 3},
+		{Time: at.Add(time.Millisecond), Method: "GET", Path: "/api/v1/trending", Status: 200, Results: 4},
+		{Time: at.Add(2 * time.Millisecond), Method: "GET", Path: "/api/v1/articles/19aaddc0-7508-4659-9c32-2216107f8604", Status: 200, Results: -1},
+	}
+
+	results, err := Run(context.Background(), entries, Options{Target: target.URL, Speed: 1, Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	report := Summarize(results)
+
+	if report.Requests != 4 || report.StatusMatched != 3 || report.StatusChanges["200 -> 500"] != 1 {
+		t.Errorf("Summarize() = %+v, expected 3 of 4 statuses matched and one 200 -> 500", report)
+	}
+	if rate := report.MismatchRate(); rate != 0.25 {
+		t.Errorf("MismatchRate() = %v, expected 0.25", rate)
+	}
+
+	latest := report.Endpoints[0]
+	if latest.Endpoint != "/api/v1/news/latest" || latest.Compared != 2 || latest.LoggedMean != 4 ||
+		latest.TargetMean != 3 || latest.ResultsChanged != 1 {
+		t.Errorf("latest endpoint = %+v, expected 2 compared, means 4 -> 3 and 1 changed", latest)
+	}
+	for _, endpoint := range report.Endpoints {
+		if endpoint.Endpoint == "/api/v1/trending" && (endpoint.LoggedEmpty != 0 || endpoint.TargetEmpty != 1) {
+			t.Errorf("trending endpoint = %+v, expected empty 0 -> 1", endpoint)
+		}
+		if strings.HasPrefix(endpoint.Endpoint, "/api/v1/articles/") && endpoint.Endpoint != "/api/v1/articles/:id" {
+			t.Errorf("article endpoint = %q, expected the ID replaced", endpoint.Endpoint)
+		}
+	}
+
+	t.Run("Invalid target", func(t *testing.T) {
+		if _, err := Run(context.Background(), entries, Options{Target: "not a url"}); err == nil {
+			t.Error("Run() with an invalid target succeeded, expected an error")
+		}
+	})
+}
+
+func TestEndpoint(t *testing.T) {
+	tests := map[string]string{
+		"/api/v1/news/latest": "/api/v1/news/latest",
+		"/api/v1/stories/42":  "/api/v1/stories/:id",
+		"/api/v1/articles/19aaddc0-7508-4659-9c32-2216107f8604": "/api/v1/articles/:id",
+		"/api/v1/editions/mumbai":                               "/api/v1/editions/mumbai",
+	}
+	for path, expected := range tests {
+		if got := Endpoint(path); got != expected {
+			t.Errorf("Endpoint(%q) = %q, expected %q", path, got, expected)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"time"
+
+	"news-backend/config"
+	"news-backend/utils"
+)
+
+// newCountPrivacy returns the protection for engagement counts published
+// outside the service, seeded by AGGREGATE_NOISE_SECRET so every instance
+// adds the same noise to a count (or at random when it's unset)
+func newCountPrivacy(cfg *config.Config) utils.CountPrivacy {
+	salt := rand.Uint64()
+	if cfg.AggregateNoiseSecret != "" {
+		hash := fnv.New64a()
+		hash.Write([]byte(cfg.AggregateNoiseSecret))
+		salt = hash.Sum64()
+	}
+	return utils.NewCountPrivacy(cfg.AggregateMinUsers, cfg.AggregateNoiseEpsilon, salt)
+}
+
+// suppressSmallCells drops the articles engaged with by fewer than
+// AGGREGATE_MIN_USERS users within the radius: listing them as trending
+// there would tell who read them
+func (s *TrendingService) suppressSmallCells(scores map[string]*articleScore) {
+	for id, score := range scores {
+		if s.privacy.Suppressed(score.users) {
+			delete(scores, id)
+		}
+	}
+}
+
+// addCountNoise perturbs the engagement counts of trending articles before
+// they are scored, so neither the counts shown nor the trending scores give
+// the exact ones away. The noise is fixed per article and location cell
+// (cacheKey), so nearby requests can't average it away.
+func (s *TrendingService) addCountNoise(scores map[string]*articleScore, cacheKey string) {
+	for id, score := range scores {
+		key := cacheKey + "|" + id
+		events := s.privacy.Noisy(key+"|events", score.events)
+		if score.events > 0 {
+			score.weight *= float64(events) / float64(score.events)
+		}
+		score.events = events
+		score.users = s.privacy.Noisy(key+"|users", score.users)
+		for eventType, count := range score.breakdown {
+			score.breakdown[eventType] = s.privacy.Noisy(key+"|"+eventType, count)
+		}
+	}
+}
+
+// protectRegionStats applies AGGREGATE_MIN_USERS and AGGREGATE_NOISE_EPSILON
+// to event stats filtered by region, which could otherwise place individual
+// users: too few users suppress the counts, the rest get noise
+func (s *TrendingService) protectRegionStats(stats map[string]interface{}, filter EventStatsFilter, users int64) {
+	countKeys := []string{"total_events", "unique_articles", "unique_users", "views", "clicks", "shares"}
+	if s.privacy.Suppressed(int(users)) {
+		for _, key := range countKeys {
+			stats[key] = int64(0)
+		}
+		if _, ok := stats["buckets"]; ok {
+			stats["buckets"] = []EventBucket{}
+		}
+		stats["suppressed"] = true
+		return
+	}
+
+	cell := utils.ToGridCell(filter.Region.Lat, filter.Region.Lon, cacheGridPrecision)
+	scope := fmt.Sprintf("stats|%d_%d_%d|%s|%s|%s|%s", cell.LatCell, cell.LonCell,
+		int(filter.Region.RadiusKm/cacheRadiusBucket), filter.From.Format(time.RFC3339),
+		filter.To.Format(time.RFC3339), filter.ArticleID, filter.EventType)
+	for _, key := range countKeys {
+		stats[key] = int64(s.privacy.Noisy(scope+"|"+key, int(stats[key].(int64))))
+	}
+	if buckets, ok := stats["buckets"].([]EventBucket); ok {
+		for i := range buckets {
+			bucketScope := scope + "|" + buckets[i].Start
+			buckets[i].Total = int64(s.privacy.Noisy(bucketScope, int(buckets[i].Total)))
+			for eventType, count := range buckets[i].ByType {
+				buckets[i].ByType[eventType] = int64(s.privacy.Noisy(bucketScope+"|"+eventType, int(count)))
+			}
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"news-backend/config"
+	"news-backend/database"
+	"news-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// archiveBatchSize is how many articles one archival transaction moves
+const archiveBatchSize = 500
+
+// ArchiveWorker moves articles published more than ARTICLE_ARCHIVE_AFTER_DAYS
+// ago from the articles table into articles_archive, so listings, trending
+// and search only scan recent content. Archived articles keep their category
+// and topic links; searches with include_archived=true still find them.
+type ArchiveWorker struct {
+	db               *gorm.DB
+	cfg              *config.Config
+	embeddingService *EmbeddingService
+	cdnService       *CDNService
+	trendingService  *TrendingService
+}
+
+// NewArchiveWorker creates a new archive worker
+func NewArchiveWorker(cfg *config.Config, embeddingService *EmbeddingService, cdnService *CDNService, trendingService *TrendingService) *ArchiveWorker {
+	return &ArchiveWorker{
+		db:               database.GetDB(),
+		cfg:              cfg,
+		embeddingService: embeddingService,
+		cdnService:       cdnService,
+		trendingService:  trendingService,
+	}
+}
+
+// Start runs a pass immediately and then on every configured interval until
+// ctx is cancelled. A non-positive interval or age disables the worker.
+func (w *ArchiveWorker) Start(ctx context.Context) {
+	if w.cfg.ArticleArchiveInterval <= 0 || w.cfg.ArticleArchiveAfterDays <= 0 {
+		log.Println("Article archive worker disabled")
+		return
+	}
+
+	interval := time.Duration(w.cfg.ArticleArchiveInterval) * time.Second
+	log.Printf("Article archive worker started (interval: %v, after: %d days)", interval, w.cfg.ArticleArchiveAfterDays)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Run(); err != nil {
+			log.Printf("Article archival failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Println("Article archive worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run moves every article published before the cutoff, deleted ones
+// included, into the archive in batches. Each batch is copied and removed
+// in one transaction, so an article is never in both tables or neither.
+func (w *ArchiveWorker) Run() error {
+	if w.cfg.ArticleArchiveAfterDays <= 0 {
+		return nil
+	}
+	cutoff := time.Now().UTC().AddDate(0, 0, -w.cfg.ArticleArchiveAfterDays)
+	columns := database.ArticleColumns()
+
+	archived := 0
+	for {
+		var ids []string
+		err := w.db.Unscoped().Model(&models.Article{}).
+			Where("publication_date < ?", cutoff).
+			Limit(archiveBatchSize).
+			Pluck("id", &ids).Error
+		if err != nil {
+			return fmt.Errorf("failed to select articles to archive: %w", err)
+		}
+		if len(ids) == 0 {
+			break
+		}
+
+		err = w.db.Transaction(func(tx *gorm.DB) error {
+			err := tx.Exec("INSERT INTO "+database.ArchiveTable+" ("+columns+", archived_at) SELECT "+columns+", ? FROM articles WHERE id IN ?",
+				time.Now().UTC(), ids).Error
+			if err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Where("id IN ?", ids).Delete(&models.Article{}).Error; err != nil {
+				return err
+			}
+			// Semantic search only covers the articles table
+			return tx.Where("article_id IN ?", ids).Delete(&models.ArticleEmbedding{}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to archive articles: %w", err)
+		}
+
+		keys := make([]string, 0, len(ids)+1)
+		for _, id := range ids {
+			w.embeddingService.Forget(id)
+			keys = append(keys, ArticleSurrogateKey(id))
+		}
+		w.cdnService.Purge(append(keys, SurrogateKeyNews)...)
+		archived += len(ids)
+	}
+
+	if archived > 0 {
+		w.trendingService.InvalidateCache()
+		log.Printf("Archived %d articles published before %s", archived, cutoff.Format(time.RFC3339))
+	}
+	return nil
+}
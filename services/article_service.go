@@ -0,0 +1,444 @@
+package services
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+
+	"news-backend/config"
+	"news-backend/database"
+	"news-backend/ingest"
+	"news-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrArticleExists is returned when creating an article whose ID is taken
+var ErrArticleExists = errors.New("article already exists")
+
+// ErrDuplicateArticle is returned when an article would share its URL with
+// another live article
+var ErrDuplicateArticle = errors.New("an article with the same URL exists")
+
+// checkDuplicate returns ErrDuplicateArticle, naming the other article,
+// when one other than article has its URL
+func checkDuplicate(tx *gorm.DB, article *models.Article) error {
+	duplicate, err := database.DuplicateArticleID(tx, article.URL, article.ID)
+	if err != nil {
+		return err
+	}
+	if duplicate != "" {
+		return fmt.Errorf("%w: %s", ErrDuplicateArticle, duplicate)
+	}
+	return nil
+}
+
+// importBatchSize is how many imported articles are inserted per transaction
+const importBatchSize = 100
+
+// ImportRowError explains why one imported record was rejected
+type ImportRowError struct {
+	Row   int    `json:"row"` // 1-based position among the records (CSV header excluded)
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error"`
+}
+
+// ImportReport summarizes a bulk import
+type ImportReport struct {
+	Total    int              `json:"total"`
+	Valid    int              `json:"valid"` // passed validation; all are imported unless DryRun or a store fails
+	Imported int              `json:"imported"`
+	Rejected int              `json:"rejected"`
+	DryRun   bool             `json:"dry_run"`
+	Errors   []ImportRowError `json:"errors"`
+}
+
+// ArticleService applies editorial changes to stored articles and keeps the
+// caches and derived data built from them consistent
+type ArticleService struct {
+	db               *gorm.DB
+	cfg              *config.Config
+	llmService       *LLMService
+	embeddingService *EmbeddingService
+	trendingService  *TrendingService
+	webhookService   *WebhookService
+	cdnService       *CDNService
+}
+
+// NewArticleService creates a new article service instance
+func NewArticleService(cfg *config.Config, llmService *LLMService, embeddingService *EmbeddingService,
+	trendingService *TrendingService, webhookService *WebhookService, cdnService *CDNService) *ArticleService {
+	return &ArticleService{
+		db:               database.GetDB(),
+		cfg:              cfg,
+		llmService:       llmService,
+		embeddingService: embeddingService,
+		trendingService:  trendingService,
+		webhookService:   webhookService,
+		cdnService:       cdnService,
+	}
+}
+
+// Create stores a new article, generating an ID when none is given.
+// Provenance the request leaves empty comes from the article's source.
+func (s *ArticleService) Create(article *models.Article) error {
+	if article.ID == "" {
+		article.ID = newArticleID()
+	}
+	var source models.Source
+	if err := s.db.Where("name = ?", article.SourceName).Limit(1).Find(&source).Error; err != nil {
+		return fmt.Errorf("failed to load source: %w", err)
+	}
+	article.ApplyProvenance(source, models.IngestSourceAdmin)
+	article.CurrentRelevance = article.RelevanceScore
+	article.ContentHash = article.ComputeContentHash()
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Deleted and archived articles keep their IDs
+		var count, archived int64
+		if err := tx.Unscoped().Model(&models.Article{}).Where("id = ?", article.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if err := tx.Table(database.ArchiveTable).Where("id = ?", article.ID).Count(&archived).Error; err != nil {
+			return err
+		}
+		if count+archived > 0 {
+			return ErrArticleExists
+		}
+		if err := checkDuplicate(tx, article); err != nil {
+			return err
+		}
+		if err := tx.Create(article).Error; err != nil {
+			return err
+		}
+		return database.SyncArticleCategories(tx, []models.Article{*article})
+	})
+	if errors.Is(err, ErrArticleExists) || errors.Is(err, ErrDuplicateArticle) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create article: %w", err)
+	}
+
+	// Cached listings don't include the new article yet
+	s.cdnService.Purge(SurrogateKeyNews)
+	s.trendingService.InvalidateCache()
+	return nil
+}
+
+// GetArticleByID returns a stored article, looking in the archive when it
+// was moved there, or gorm.ErrRecordNotFound
+func (s *ArticleService) GetArticleByID(id string) (*models.Article, error) {
+	var article models.Article
+	err := s.db.Where("id = ?", id).First(&article).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = s.db.Table(database.ArchiveTable).Where("id = ?", id).First(&article).Error
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to fetch article: %w", err)
+	}
+	return &article, nil
+}
+
+// Update applies edit to the stored article and saves it. A changed title or
+// description drops the summary and embedding computed from the old text.
+// It returns gorm.ErrRecordNotFound when the article does not exist and
+// ErrDuplicateArticle when another article has the new URL.
+func (s *ArticleService) Update(id string, edit func(article *models.Article)) (*models.Article, error) {
+	var article models.Article
+	if err := s.db.Where("id = ?", id).First(&article).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to fetch article: %w", err)
+	}
+
+	previousHash := article.ComputeContentHash()
+	edit(&article)
+	article.ID = id
+	article.ContentHash = article.ComputeContentHash()
+	contentChanged := article.ContentHash != previousHash
+	if contentChanged {
+		article.LLMSummary = ""
+		article.Sentiment, article.Tone = "", ""
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkDuplicate(tx, &article); err != nil {
+			return err
+		}
+		if err := tx.Omit("Categories").Save(&article).Error; err != nil {
+			return err
+		}
+		if contentChanged {
+			if err := tx.Where("article_id = ?", id).Delete(&models.ArticleEmbedding{}).Error; err != nil {
+				return err
+			}
+		}
+		return database.SyncArticleCategories(tx, []models.Article{article})
+	})
+	if errors.Is(err, ErrDuplicateArticle) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update article: %w", err)
+	}
+
+	if contentChanged {
+		s.llmService.InvalidateSummary(id)
+		s.embeddingService.Forget(id)
+	}
+	s.cdnService.Purge(ArticleSurrogateKey(id))
+	s.trendingService.InvalidateCache()
+	s.webhookService.Emit(WebhookArticleUpdated, articleUpdatedData(article))
+	return &article, nil
+}
+
+// Delete soft-deletes an article, dropping its category and topic links and
+// embedding; the row is kept so Restore can bring it back. It returns
+// gorm.ErrRecordNotFound when the article does not exist.
+func (s *ArticleService) Delete(id string) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		article := models.Article{ID: id}
+		if err := tx.Model(&article).Association("Categories").Clear(); err != nil {
+			return err
+		}
+		if err := tx.Model(&article).Association("Topics").Clear(); err != nil {
+			return err
+		}
+		if err := tx.Where("article_id = ?", id).Delete(&models.ArticleEmbedding{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&article)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete article: %w", err)
+	}
+
+	s.llmService.InvalidateSummary(id)
+	s.embeddingService.Forget(id)
+	s.cdnService.Purge(ArticleSurrogateKey(id))
+	s.trendingService.InvalidateCache()
+	log.Printf("Deleted article %s", id)
+	return nil
+}
+
+// Restore undoes Delete: category links are rebuilt, while topics, summary
+// and embedding are recomputed by their workers. It returns
+// gorm.ErrRecordNotFound when no deleted article has the ID and
+// ErrDuplicateArticle when a live article has its URL.
+func (s *ArticleService) Restore(id string) (*models.Article, error) {
+	var article models.Article
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var deleted models.Article
+		if err := tx.Unscoped().Select("id", "url", "title").Where("id = ? AND deleted_at IS NOT NULL", id).First(&deleted).Error; err != nil {
+			return err
+		}
+		if err := checkDuplicate(tx, &deleted); err != nil {
+			return err
+		}
+		result := tx.Unscoped().Model(&models.Article{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Updates(map[string]interface{}{"deleted_at": nil, "topics_hash": ""})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Where("id = ?", id).First(&article).Error; err != nil {
+			return err
+		}
+		return database.SyncArticleCategories(tx, []models.Article{article})
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, ErrDuplicateArticle) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore article: %w", err)
+	}
+
+	s.cdnService.Purge(ArticleSurrogateKey(id), SurrogateKeyNews)
+	s.trendingService.InvalidateCache()
+	log.Printf("Restored article %s", id)
+	return &article, nil
+}
+
+// Import transforms records with the same source rules as the startup data
+// load, validates them and stores the valid ones. Rows with bad dates,
+// missing fields, or IDs or URLs already in the file or database
+// are reported rather than skipped silently. With dryRun nothing is stored.
+func (s *ArticleService) Import(records []ingest.RawArticle, dryRun bool) (*ImportReport, error) {
+	sources, err := database.LoadSourcesByName()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{Total: len(records), DryRun: dryRun, Errors: []ImportRowError{}}
+	reject := func(row int, id string, err error) {
+		report.Errors = append(report.Errors, ImportRowError{Row: row + 1, ID: id, Error: err.Error()})
+	}
+
+	articles := make([]models.Article, 0, len(records))
+	rows := make([]int, 0, len(records))
+	seen := make(map[string]int, len(records))
+	seenURLs := make(map[string]int, len(records))
+	for i, record := range records {
+		source := ingest.SourceFor(record, sources)
+		article, err := ingest.Transform(record, source.Rules)
+		if err == nil {
+			err = ingest.Validate(article)
+		}
+		article.ApplyProvenance(source, models.IngestSourceAdmin)
+		if err != nil {
+			reject(i, article.ID, err)
+			continue
+		}
+		if first, ok := seen[article.ID]; ok {
+			reject(i, article.ID, fmt.Errorf("duplicate id (also row %d)", first+1))
+			continue
+		}
+		if first, ok := seenURLs[article.URL]; ok {
+			reject(i, article.ID, fmt.Errorf("duplicate URL (also row %d)", first+1))
+			continue
+		}
+		seen[article.ID] = i
+		seenURLs[article.URL] = i
+		articles = append(articles, article)
+		rows = append(rows, i)
+	}
+
+	// Drop records whose ID or URL is already stored
+	existing := make(map[string]bool)
+	duplicates := make(map[string]string)
+	for start := 0; start < len(articles); start += importBatchSize {
+		end := min(start+importBatchSize, len(articles))
+		ids := make([]string, 0, end-start)
+		urls := make([]string, 0, end-start)
+		for _, article := range articles[start:end] {
+			ids = append(ids, article.ID)
+			urls = append(urls, article.URL)
+		}
+		var live []models.Article
+		if err := s.db.Select("id", "url").Where("url IN ?", urls).Find(&live).Error; err != nil {
+			return nil, fmt.Errorf("failed to check for duplicate articles: %w", err)
+		}
+		for _, article := range live {
+			duplicates[article.URL] = article.ID
+		}
+		var stored []string
+		if err := s.db.Model(&models.Article{}).Where("id IN ?", ids).Pluck("id", &stored).Error; err != nil {
+			return nil, fmt.Errorf("failed to check existing articles: %w", err)
+		}
+		for _, id := range stored {
+			existing[id] = true
+		}
+		retired, err := database.RetiredArticleIDs(s.db, ids)
+		if err != nil {
+			return nil, err
+		}
+		for id := range retired {
+			existing[id] = true
+		}
+	}
+	valid := articles[:0]
+	validRows := rows[:0]
+	for i, article := range articles {
+		if existing[article.ID] {
+			reject(rows[i], article.ID, ErrArticleExists)
+			continue
+		}
+		if duplicate := duplicates[article.URL]; duplicate != "" {
+			reject(rows[i], article.ID, fmt.Errorf("%w: %s", ErrDuplicateArticle, duplicate))
+			continue
+		}
+		valid = append(valid, article)
+		validRows = append(validRows, rows[i])
+	}
+
+	report.Valid = len(valid)
+	if !dryRun {
+		for start := 0; start < len(valid); start += importBatchSize {
+			end := min(start+importBatchSize, len(valid))
+			batch := valid[start:end]
+			err := s.db.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Create(&batch).Error; err != nil {
+					return err
+				}
+				return database.SyncArticleCategories(tx, batch)
+			})
+			if err != nil {
+				for i := start; i < end; i++ {
+					reject(validRows[i], valid[i].ID, fmt.Errorf("failed to store: %w", err))
+				}
+				continue
+			}
+			report.Imported += len(batch)
+		}
+	}
+
+	sort.Slice(report.Errors, func(i, j int) bool {
+		return report.Errors[i].Row < report.Errors[j].Row
+	})
+	report.Rejected = len(report.Errors)
+
+	if report.Imported > 0 {
+		s.cdnService.Purge(SurrogateKeyNews)
+		s.trendingService.InvalidateCache()
+		log.Printf("Imported %d articles (%d rejected)", report.Imported, report.Rejected)
+	}
+	return report, nil
+}
+
+// Reload upserts the configured dataset into the database, updating only
+// articles whose content changed, and invalidates what was derived from the
+// old content. Updated articles are re-embedded by the embedding worker.
+func (s *ArticleService) Reload() (*database.ReloadResult, error) {
+	result, err := database.ReloadNewsData(s.cfg.NewsDataFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	if result.Inserted > 0 {
+		keys = append(keys, SurrogateKeyNews)
+	}
+	for _, id := range result.MergedIDs {
+		keys = append(keys, ArticleSurrogateKey(id))
+	}
+	for _, article := range result.Changed {
+		s.llmService.InvalidateSummary(article.ID)
+		s.embeddingService.Forget(article.ID)
+		keys = append(keys, ArticleSurrogateKey(article.ID))
+		s.webhookService.Emit(WebhookArticleUpdated, articleUpdatedData(article))
+	}
+	s.cdnService.Purge(keys...)
+	if result.Inserted+result.Updated+len(result.MergedIDs) > 0 {
+		s.trendingService.InvalidateCache()
+	}
+	return result, nil
+}
+
+// newArticleID returns a random (version 4) UUID, matching the dataset's IDs
+func newArticleID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
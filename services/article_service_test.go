@@ -0,0 +1,171 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"news-backend/cache"
+	"news-backend/config"
+	"news-backend/database"
+	"news-backend/ingest"
+	"news-backend/models"
+)
+
+// newTestArticleService stores a live, a deleted and an archived article and
+// returns an ArticleService over them
+func newTestArticleService(t *testing.T) *ArticleService {
+	t.Helper()
+	db := openTestDB(t)
+	cfg := &config.Config{}
+	invalidation, err := NewInvalidationService(cfg)
+	if err != nil {
+		t.Fatalf("NewInvalidationService() error = %v", err)
+	}
+	cdn := NewCDNService(cfg)
+	trending := NewTrendingService(cfg, nil, nil, cdn, invalidation, nil, cache.NewMemory(), nil, nil, nil)
+
+	published := time.Date(2025, 3, 22, 10, 0, 0, 0, time.UTC)
+	stored := []models.Article{
+		{ID: "live", Title: "Live", URL: "https://example.com/live", PublicationDate: published},
+		{ID: "deleted", Title: "Deleted", URL: "https://example.com/deleted", PublicationDate: published},
+		{ID: "archived", Title: "Archived", URL: "https://example.com/archived", PublicationDate: published},
+	}
+	if err := db.Create(&stored).Error; err != nil {
+		t.Fatalf("failed to store articles: %v", err)
+	}
+	if err := db.Delete(&models.Article{}, "id = ?", "deleted").Error; err != nil {
+		t.Fatalf("failed to delete article: %v", err)
+	}
+	columns := database.ArticleColumns()
+	err = db.Exec("INSERT INTO "+database.ArchiveTable+" ("+columns+", archived_at) SELECT "+columns+", ? FROM articles WHERE id = ?",
+		time.Now().UTC(), "archived").Error
+	if err == nil {
+		err = db.Unscoped().Delete(&models.Article{}, "id = ?", "archived").Error
+	}
+	if err != nil {
+		t.Fatalf("failed to archive article: %v", err)
+	}
+
+	return NewArticleService(cfg, nil, nil, trending, nil, cdn)
+}
+
+func importRecord(id, title, url string) ingest.RawArticle {
+	return ingest.RawArticle{"id": id, "title": title, "url": url, "publication_date": "2026-03-26T09:00:00Z",
+		"source_name": "Example Times", "relevance_score": 0.5}
+}
+
+func TestImportRejectsDuplicates(t *testing.T) {
+	tests := []struct {
+		name     string
+		records  []ingest.RawArticle
+		imported int
+		errors   map[int]string // row -> error substring
+	}{
+		{
+			name: "Duplicate ID in the batch",
+			records: []ingest.RawArticle{
+				importRecord("new", "First", "https://example.com/first"),
+				importRecord("new", "Second", "https://example.com/second"),
+			},
+			imported: 1,
+			errors:   map[int]string{2: "duplicate id (also row 1)"},
+		},
+		{
+			name: "Duplicate URL in the batch",
+			records: []ingest.RawArticle{
+				importRecord("first", "Minister resigns", "https://example.com/story"),
+				importRecord("second", "Minister resigns amid inquiry", "https://example.com/story"),
+			},
+			imported: 1,
+			errors:   map[int]string{2: "duplicate URL (also row 1)"},
+		},
+		{
+			name:     "Stored ID",
+			records:  []ingest.RawArticle{importRecord("live", "Live again", "https://example.com/live-again")},
+			imported: 0,
+			errors:   map[int]string{1: ErrArticleExists.Error()},
+		},
+		{
+			name:     "Deleted ID",
+			records:  []ingest.RawArticle{importRecord("deleted", "Deleted again", "https://example.com/deleted-again")},
+			imported: 0,
+			errors:   map[int]string{1: ErrArticleExists.Error()},
+		},
+		{
+			name:     "Archived ID",
+			records:  []ingest.RawArticle{importRecord("archived", "Archived again", "https://example.com/archived-again")},
+			imported: 0,
+			errors:   map[int]string{1: ErrArticleExists.Error()},
+		},
+		{
+			// Since duplicates are keyed on URL alone, a new headline doesn't help
+			name:     "Stored URL under a new ID and title",
+			records:  []ingest.RawArticle{importRecord("fresh", "A different headline", "https://example.com/live")},
+			imported: 0,
+			errors:   map[int]string{1: ErrDuplicateArticle.Error() + ": live"},
+		},
+		{
+			name:     "URL of a deleted article",
+			records:  []ingest.RawArticle{importRecord("reborn", "Deleted", "https://example.com/deleted")},
+			imported: 1,
+		},
+		{
+			name: "Valid rows around rejected ones",
+			records: []ingest.RawArticle{
+				importRecord("live", "Live again", "https://example.com/live-again"),
+				importRecord("one", "One", "https://example.com/one"),
+				importRecord("two", "Two", "https://example.com/live"),
+				importRecord("three", "Three", "https://example.com/three"),
+			},
+			imported: 2,
+			errors:   map[int]string{1: ErrArticleExists.Error(), 3: ErrDuplicateArticle.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestArticleService(t)
+			report, err := service.Import(tt.records, false)
+			if err != nil {
+				t.Fatalf("Import() error = %v", err)
+			}
+			if report.Imported != tt.imported || report.Rejected != len(tt.errors) {
+				t.Errorf("Import() imported %d and rejected %d (%+v), expected %d and %d",
+					report.Imported, report.Rejected, report.Errors, tt.imported, len(tt.errors))
+			}
+			for _, rowErr := range report.Errors {
+				want, ok := tt.errors[rowErr.Row]
+				if !ok || !strings.Contains(rowErr.Error, want) {
+					t.Errorf("row %d rejected with %q, expected %q", rowErr.Row, rowErr.Error, want)
+				}
+			}
+
+			var n int64
+			if err := service.db.Model(&models.Article{}).Count(&n).Error; err != nil {
+				t.Fatal(err)
+			}
+			if want := int64(1 + tt.imported); n != want {
+				t.Errorf("%d live articles stored, expected %d", n, want)
+			}
+		})
+	}
+}
+
+func TestImportDryRunStoresNothing(t *testing.T) {
+	service := newTestArticleService(t)
+	report, err := service.Import([]ingest.RawArticle{
+		importRecord("one", "One", "https://example.com/one"),
+		importRecord("two", "Two", "https://example.com/live"),
+	}, true)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if report.Valid != 1 || report.Imported != 0 || report.Rejected != 1 {
+		t.Errorf("dry run report = %+v, expected 1 valid, none imported and 1 rejected", report)
+	}
+	var n int64
+	if err := service.db.Model(&models.Article{}).Where("id = ?", "one").Count(&n).Error; err != nil || n != 0 {
+		t.Errorf("dry run stored %d articles (%v), expected none", n, err)
+	}
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"news-backend/config"
+)
+
+// Surrogate keys tag edge-cached responses so they can be purged as a group
+const (
+	SurrogateKeyNews     = "news"
+	SurrogateKeyTrending = "trending"
+)
+
+// ArticleSurrogateKey is the key attached to every cached response that
+// contains the article
+func ArticleSurrogateKey(articleID string) string {
+	return "article-" + articleID
+}
+
+// CDNService purges edge-cached responses by surrogate key through a
+// configured purge endpoint, and in-process caches through OnPurge
+type CDNService struct {
+	cfg    *config.Config
+	client *http.Client
+
+	listenersMu sync.RWMutex
+	listeners   []func(keys ...string)
+
+	// pending tracks in-flight purges so shutdown can wait for them
+	pending sync.WaitGroup
+}
+
+// NewCDNService creates a new CDN purge service instance
+func NewCDNService(cfg *config.Config) *CDNService {
+	return &CDNService{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// OnPurge registers fn to be called synchronously with the keys of every purge
+func (s *CDNService) OnPurge(fn func(keys ...string)) {
+	s.listenersMu.Lock()
+	defer s.listenersMu.Unlock()
+	s.listeners = append(s.listeners, fn)
+}
+
+// Purge invalidates every cached response tagged with any of keys: local
+// caches immediately, the edge asynchronously when a purge URL is configured
+func (s *CDNService) Purge(keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	s.listenersMu.RLock()
+	for _, fn := range s.listeners {
+		fn(keys...)
+	}
+	s.listenersMu.RUnlock()
+
+	if s.cfg.CDNPurgeURL == "" {
+		return
+	}
+
+	s.pending.Add(1)
+	go func() {
+		defer s.pending.Done()
+		s.purge(keys)
+	}()
+}
+
+// Wait blocks until in-flight purges finish or ctx is done
+func (s *CDNService) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// purge posts the keys both as a JSON body and as a space-separated
+// Surrogate-Key header, which covers most CDN purge APIs
+func (s *CDNService) purge(keys []string) {
+	body, err := json.Marshal(map[string][]string{"surrogate_keys": keys})
+	if err != nil {
+		log.Printf("Failed to encode CDN purge request: %v", err)
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.cfg.CDNPurgeURL, bytes.NewReader(body))
+	if err != nil {
+		log.Printf("Failed to build CDN purge request: %v", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Surrogate-Key", strings.Join(keys, " "))
+	if s.cfg.CDNPurgeToken != "" {
+		req.Header.Set("Authorization", "Bearer "+s.cfg.CDNPurgeToken)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		log.Printf("CDN purge of %v failed: %v", keys, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		log.Printf("CDN purge of %v returned status %d", keys, resp.StatusCode)
+	}
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerAllowsOneHalfOpenTrial(t *testing.T) {
+	breaker := newCircuitBreaker(2, time.Millisecond)
+	breaker.RecordFailure()
+	breaker.RecordFailure()
+	if breaker.Allow() {
+		t.Fatal("Allow() = true while open")
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if breaker.Allow() {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := allowed.Load(); got != 1 {
+		t.Fatalf("%d concurrent callers allowed while half-open, expected 1", got)
+	}
+
+	// A failed trial reopens the breaker
+	breaker.RecordFailure()
+	if breaker.Allow() {
+		t.Error("Allow() = true after the trial failed")
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	// A released trial lets the next caller try
+	if !breaker.Allow() {
+		t.Fatal("Allow() = false after the cooldown")
+	}
+	breaker.Release()
+	if !breaker.Allow() {
+		t.Fatal("Allow() = false after the trial was released")
+	}
+
+	// A successful trial closes the breaker for everyone
+	breaker.RecordSuccess()
+	if !breaker.Allow() || !breaker.Allow() {
+		t.Error("Allow() = false after the trial succeeded")
+	}
+	if state := breaker.State(); state != BreakerClosed {
+		t.Errorf("State() = %q after the trial succeeded, expected %q", state, BreakerClosed)
+	}
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"news-backend/config"
+	"news-backend/content"
+	"news-backend/database"
+	"news-backend/imageproxy"
+	"news-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// contentFetchHosts is how many hosts are fetched from at once; requests
+	// to one host are sequential and spaced by CONTENT_FETCH_DOMAIN_INTERVAL
+	contentFetchHosts = 4
+	// contentMaxAttempts is how often a page that failed to load is tried
+	contentMaxAttempts = 3
+	// contentRetryAfter is how long a failed page waits for its next attempt
+	contentRetryAfter = 6 * time.Hour
+)
+
+// ContentWorker fetches article pages, newest articles first, and stores
+// their readable body text in article_contents, where the content search
+// field and summaries use it. Articles without a lead image get the page's
+// og:image. Pages robots.txt disallows are recorded and not fetched again.
+type ContentWorker struct {
+	db              *gorm.DB
+	cfg             *config.Config
+	llmService      *LLMService
+	cdnService      *CDNService
+	trendingService *TrendingService
+	fetcher         *content.Fetcher
+}
+
+// contentResult is the outcome of fetching one article's page
+type contentResult struct {
+	article models.Article
+	page    *content.Page
+	err     error
+}
+
+// NewContentWorker creates a new content fetching worker
+func NewContentWorker(cfg *config.Config, llmService *LLMService, cdnService *CDNService, trendingService *TrendingService) *ContentWorker {
+	// Article URLs come from connectors and imports, so pages and robots.txt
+	// files are only fetched from public addresses, as images are
+	client := imageproxy.NewClient(time.Duration(cfg.ContentFetchTimeout) * time.Second)
+	interval := time.Duration(cfg.ContentFetchDomainInterval * float64(time.Second))
+	return &ContentWorker{
+		db:              database.GetDB(),
+		cfg:             cfg,
+		llmService:      llmService,
+		cdnService:      cdnService,
+		trendingService: trendingService,
+		fetcher:         content.NewFetcher(client, cfg.ContentFetchUserAgent, interval),
+	}
+}
+
+// Start fetches a batch immediately and then on every configured interval
+// until ctx is cancelled. A non-positive interval disables the worker.
+func (w *ContentWorker) Start(ctx context.Context) {
+	if w.cfg.ContentFetchInterval <= 0 {
+		log.Println("Content fetch worker disabled")
+		return
+	}
+
+	interval := time.Duration(w.cfg.ContentFetchInterval) * time.Second
+	log.Printf("Content fetch worker started (interval: %v, batch: %d)", interval, w.cfg.ContentFetchBatch)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		fetched, attempted, err := w.ProcessBatch(ctx)
+		if err != nil {
+			log.Printf("Content fetching failed: %v", err)
+		} else if attempted > 0 {
+			log.Printf("Fetched content of %d of %d articles", fetched, attempted)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Println("Content fetch worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessBatch fetches the pages of up to ContentFetchBatch articles that
+// have no content yet or whose last attempt failed long enough ago, and
+// returns how many yielded text out of how many were tried. A fetched body
+// clears the article's summary so the SummaryWorker rewrites it from the
+// body.
+func (w *ContentWorker) ProcessBatch(ctx context.Context) (int, int, error) {
+	var articles []models.Article
+	err := w.db.Model(&models.Article{}).
+		Select("articles.id", "articles.url", "articles.image_url").
+		Joins("LEFT JOIN article_contents ON article_contents.article_id = articles.id").
+		Where("articles.url <> ''").
+		Where("article_contents.article_id IS NULL OR (article_contents.status = ? AND article_contents.attempts < ? AND article_contents.fetched_at < ?)",
+			models.ContentStatusFailed, contentMaxAttempts, time.Now().Add(-contentRetryAfter)).
+		Order("articles.publication_date DESC").
+		Limit(w.cfg.ContentFetchBatch).
+		Find(&articles).Error
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to load articles without content: %w", err)
+	}
+
+	// One goroutine per host, so a slow host doesn't hold up the others
+	var hosts []string
+	byHost := make(map[string][]models.Article)
+	for _, article := range articles {
+		host := ""
+		if u, err := url.Parse(article.URL); err == nil {
+			host = strings.ToLower(u.Host)
+		}
+		if _, ok := byHost[host]; !ok {
+			hosts = append(hosts, host)
+		}
+		byHost[host] = append(byHost[host], article)
+	}
+
+	results := make(chan contentResult)
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, contentFetchHosts)
+	for _, host := range hosts {
+		wg.Add(1)
+		go func(articles []models.Article) {
+			defer wg.Done()
+			select {
+			case semaphore <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-semaphore }()
+			for _, article := range articles {
+				page, err := w.fetcher.Fetch(ctx, article.URL)
+				if ctx.Err() != nil {
+					return
+				}
+				results <- contentResult{article: article, page: page, err: err}
+			}
+		}(byHost[host])
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Stored one at a time, as SQLite takes one writer
+	fetched, attempted := 0, 0
+	var storeErr error
+	var imageKeys []string
+	for result := range results {
+		attempted++
+		if storeErr != nil {
+			continue
+		}
+		var imageSet bool
+		if imageSet, storeErr = w.store(result); storeErr == nil && result.err == nil {
+			fetched++
+		}
+		if imageSet {
+			imageKeys = append(imageKeys, ArticleSurrogateKey(result.article.ID))
+		}
+	}
+
+	// A lead image changes the article's responses and its layout hint
+	if len(imageKeys) > 0 {
+		w.cdnService.Purge(imageKeys...)
+		w.trendingService.InvalidateCache()
+	}
+	return fetched, attempted, storeErr
+}
+
+// store records a fetch outcome, counting the attempt, and reports whether
+// the article got the page's image
+func (w *ContentWorker) store(result contentResult) (bool, error) {
+	row := models.ArticleContent{
+		ArticleID: result.article.ID,
+		URL:       result.article.URL,
+		Status:    models.ContentStatusFetched,
+		Attempts:  1,
+		FetchedAt: time.Now().UTC(),
+	}
+	switch {
+	case errors.Is(result.err, content.ErrDisallowed):
+		row.Status, row.Error = models.ContentStatusDisallowed, result.err.Error()
+	case result.err != nil:
+		row.Status, row.Error = models.ContentStatusFailed, result.err.Error()
+	default:
+		row.Body = result.page.Text
+		row.WordCount = len(strings.Fields(row.Body))
+	}
+
+	// Images the source provided are kept
+	updates := map[string]interface{}{"llm_summary": "", "summary_attempts": 0}
+	imageSet := row.Status == models.ContentStatusFetched && result.article.ImageURL == "" && result.page.ImageURL != ""
+	if imageSet {
+		updates["image_url"] = result.page.ImageURL
+	}
+
+	err := w.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "article_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"url":        row.URL,
+				"body":       row.Body,
+				"word_count": row.WordCount,
+				"status":     row.Status,
+				"error":      row.Error,
+				"attempts":   gorm.Expr("article_contents.attempts + 1"),
+				"fetched_at": row.FetchedAt,
+			}),
+		}).Create(&row).Error
+		if err != nil || row.Status != models.ContentStatusFetched {
+			return err
+		}
+		return tx.Model(&models.Article{}).Where("id = ?", row.ArticleID).Updates(updates).Error
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to store content of article %s: %w", row.ArticleID, err)
+	}
+	if row.Status == models.ContentStatusFetched {
+		w.llmService.InvalidateSummary(row.ArticleID)
+	}
+	return imageSet, nil
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"path/filepath"
+	"testing"
+
+	"news-backend/database"
+	"news-backend/migrations"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB points database.DB at a fresh, fully migrated database for
+// the services a test creates
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get database handle: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	if _, err := migrations.Up(sqlDB); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	previous := database.DB
+	database.DB = db
+	t.Cleanup(func() { database.DB = previous })
+	return db
+}
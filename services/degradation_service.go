@@ -0,0 +1,290 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"news-backend/cache"
+	"news-backend/config"
+	"news-backend/database"
+	"news-backend/metrics"
+	"news-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// Subsystem states in the degradation matrix
+const (
+	SubsystemHealthy  = "healthy"
+	SubsystemDisabled = "disabled"
+	SubsystemDegraded = "degraded"
+)
+
+// cacheProbeKey is read to check that the cache backend answers
+const cacheProbeKey = "degradation:probe"
+
+// SubsystemStatus is one optional subsystem's state and the features that
+// are reduced while it isn't healthy
+type SubsystemStatus struct {
+	Name            string   `json:"name"`
+	Status          string   `json:"status"`
+	Detail          string   `json:"detail"`
+	ReducedFeatures []string `json:"reduced_features"`
+}
+
+// DegradationReport is the state of every optional subsystem. Status is
+// degraded when any subsystem is.
+type DegradationReport struct {
+	Status     string            `json:"status"`
+	CheckedAt  time.Time         `json:"checked_at"`
+	Subsystems []SubsystemStatus `json:"subsystems"`
+}
+
+// DegradationService reports which optional subsystems are healthy,
+// disabled or degraded, so support can tell a reduced feature from a bug
+type DegradationService struct {
+	db               *gorm.DB
+	cfg              *config.Config
+	llmService       *LLMService
+	embeddingService *EmbeddingService
+	geocoder         *GeocodingService
+	ingestService    *IngestService
+	cache            cache.Cache
+	registry         *metrics.Registry
+}
+
+// NewDegradationService creates a new degradation report service instance
+func NewDegradationService(cfg *config.Config, llmService *LLMService, embeddingService *EmbeddingService,
+	geocoder *GeocodingService, ingestService *IngestService, store cache.Cache, registry *metrics.Registry) *DegradationService {
+	return &DegradationService{
+		db:               database.GetDB(),
+		cfg:              cfg,
+		llmService:       llmService,
+		embeddingService: embeddingService,
+		geocoder:         geocoder,
+		ingestService:    ingestService,
+		cache:            store,
+		registry:         registry,
+	}
+}
+
+// Report checks every optional subsystem
+func (s *DegradationService) Report(ctx context.Context) DegradationReport {
+	report := DegradationReport{
+		Status:    SubsystemHealthy,
+		CheckedAt: time.Now().UTC(),
+		Subsystems: []SubsystemStatus{
+			s.llmStatus(),
+			s.embeddingStatus(),
+			s.geocoderStatus(),
+			s.cacheStatus(ctx),
+			s.ingestStatus(),
+			s.pipelineStatus(),
+		},
+	}
+	for i, sub := range report.Subsystems {
+		if sub.Status == SubsystemHealthy {
+			report.Subsystems[i].ReducedFeatures = []string{}
+		}
+		if sub.Status == SubsystemDegraded {
+			report.Status = SubsystemDegraded
+		}
+	}
+	return report
+}
+
+// llmStatus is degraded while every provider's breaker is open or the
+// daily token budget is spent, and disabled when LLM_PROVIDER is none
+func (s *DegradationService) llmStatus() SubsystemStatus {
+	if s.cfg.LLMOffline {
+		return SubsystemStatus{
+			Name:   "llm",
+			Status: SubsystemDisabled,
+			Detail: "LLM_PROVIDER is none",
+			ReducedFeatures: []string{
+				"intent parsing: queries not matched by a rule are treated as plain text search",
+				"summaries: articles without a stored summary show the start of their description",
+				"topics aren't extracted, digests have no briefing and questions can't be answered",
+			},
+		}
+	}
+	status := SubsystemStatus{
+		Name:   "llm",
+		Status: SubsystemHealthy,
+		ReducedFeatures: []string{
+			"intent parsing: queries not matched by a rule are treated as plain text search",
+			"summaries: only already generated summaries are shown",
+			"new articles aren't summarized by the summary worker",
+		},
+	}
+
+	var providers []string
+	for _, p := range s.llmService.ProviderStatuses() {
+		providers = append(providers, p.Name+" "+p.Breaker)
+	}
+	usage := s.llmService.Usage()
+	switch {
+	case !s.llmService.Available():
+		status.Status = SubsystemDegraded
+		status.Detail = "every provider's circuit breaker is open (" + strings.Join(providers, ", ") + ")"
+	case usage.DailyTokenBudget > 0 && usage.TokensUsed >= usage.DailyTokenBudget:
+		status.Status = SubsystemDegraded
+		status.Detail = fmt.Sprintf("daily token budget of %d is spent", usage.DailyTokenBudget)
+	default:
+		status.Detail = "providers: " + strings.Join(providers, ", ")
+	}
+	return status
+}
+
+// embeddingStatus is degraded until the index is loaded and while the LLM
+// can't embed queries
+func (s *DegradationService) embeddingStatus() SubsystemStatus {
+	status := SubsystemStatus{
+		Name:   "embeddings",
+		Status: SubsystemHealthy,
+		ReducedFeatures: []string{
+			"semantic search is unavailable",
+			"hybrid search ranks without the semantic signal",
+		},
+	}
+
+	stats, err := s.embeddingService.IndexStats()
+	switch {
+	case errors.Is(err, ErrEmbeddingsDisabled):
+		status.Status = SubsystemDisabled
+		status.Detail = "EMBEDDINGS_ENABLED is false"
+	case err != nil:
+		status.Status = SubsystemDegraded
+		status.Detail = err.Error()
+	case !stats.Loaded:
+		status.Status = SubsystemDegraded
+		status.Detail = "the embedding index is still loading"
+	case !s.llmService.Available():
+		status.Status = SubsystemDegraded
+		status.Detail = "queries can't be embedded while the LLM is unavailable"
+	default:
+		status.Detail = fmt.Sprintf("%d vectors indexed, %d articles pending", stats.Size, stats.PendingArticles)
+	}
+	return status
+}
+
+// geocoderStatus is degraded when the latest lookup failed
+func (s *DegradationService) geocoderStatus() SubsystemStatus {
+	status := SubsystemStatus{
+		Name:            "geocoder",
+		Status:          SubsystemHealthy,
+		ReducedFeatures: []string{"trending locations are reported as coordinates"},
+	}
+
+	geo := s.geocoder.Status()
+	switch {
+	case !geo.Enabled:
+		status.Status = SubsystemDisabled
+		status.Detail = "GEOCODER_URL is not set"
+	case geo.LastFailure.After(geo.LastSuccess):
+		status.Status = SubsystemDegraded
+		status.Detail = "last lookup failed at " + geo.LastFailure.UTC().Format(time.RFC3339) + ": " + geo.LastError
+	case geo.LastSuccess.IsZero():
+		status.Detail = "no lookups yet"
+	default:
+		status.Detail = "last lookup succeeded at " + geo.LastSuccess.UTC().Format(time.RFC3339)
+	}
+	return status
+}
+
+// cacheStatus is degraded when the cache backend doesn't answer a read
+func (s *DegradationService) cacheStatus(ctx context.Context) SubsystemStatus {
+	status := SubsystemStatus{
+		Name:   "cache",
+		Status: SubsystemHealthy,
+		ReducedFeatures: []string{
+			"trending results and summaries are recomputed on every request",
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	if _, _, err := s.cache.Get(ctx, cacheProbeKey); err != nil {
+		status.Status = SubsystemDegraded
+		status.Detail = fmt.Sprintf("%s backend unreachable: %v", s.cfg.CacheBackend, err)
+	} else {
+		status.Detail = s.cfg.CacheBackend + " backend reachable"
+	}
+	return status
+}
+
+// ingestStatus is degraded when a source failed in the latest run
+func (s *DegradationService) ingestStatus() SubsystemStatus {
+	status := SubsystemStatus{
+		Name:            "ingest",
+		Status:          SubsystemHealthy,
+		ReducedFeatures: []string{"articles from the affected sources stop updating"},
+	}
+
+	var sources int64
+	if err := s.db.Model(&models.Source{}).Where("enabled = ? AND connector <> ''", true).Count(&sources).Error; err != nil {
+		status.Status = SubsystemDegraded
+		status.Detail = "failed to load sources: " + err.Error()
+		return status
+	}
+	if s.cfg.IngestInterval <= 0 || sources == 0 {
+		status.Status = SubsystemDisabled
+		status.Detail = "no scheduled ingestion (INGEST_INTERVAL is 0 or no source has a connector)"
+		status.ReducedFeatures = []string{"articles only change through reloads and the admin API"}
+		return status
+	}
+
+	lastRun, results := s.ingestService.LastRun()
+	var failed []string
+	for _, result := range results {
+		if result.Error != "" {
+			failed = append(failed, result.Source+": "+result.Error)
+		}
+	}
+	switch {
+	case lastRun.IsZero():
+		status.Detail = fmt.Sprintf("%d sources, not run yet", sources)
+	case len(failed) > 0:
+		status.Status = SubsystemDegraded
+		status.Detail = fmt.Sprintf("%d of %d sources failed in the run at %s (%s)",
+			len(failed), len(results), lastRun.UTC().Format(time.RFC3339), strings.Join(failed, "; "))
+	default:
+		status.Detail = fmt.Sprintf("%d sources ingested at %s", len(results), lastRun.UTC().Format(time.RFC3339))
+	}
+	return status
+}
+
+// pipelineStatus is degraded while an async pipeline stage is at its limit
+// and applying backpressure
+func (s *DegradationService) pipelineStatus() SubsystemStatus {
+	status := SubsystemStatus{
+		Name:   "pipelines",
+		Status: SubsystemHealthy,
+		ReducedFeatures: []string{
+			"events are rejected with 429 while the event writer is full",
+			"scheduled ingestion is deferred while the summary backlog is full",
+		},
+	}
+
+	var stages, saturated []string
+	for _, queue := range s.registry.Queues() {
+		stage := fmt.Sprintf("%s %d", queue.Name, queue.Depth)
+		if queue.Limit > 0 {
+			stage += fmt.Sprintf("/%d", queue.Limit)
+		}
+		stages = append(stages, stage)
+		if queue.Saturated {
+			saturated = append(saturated, queue.Name)
+		}
+	}
+	if len(saturated) > 0 {
+		status.Status = SubsystemDegraded
+		status.Detail = "at their limit: " + strings.Join(saturated, ", ") + " (" + strings.Join(stages, ", ") + ")"
+	} else {
+		status.Detail = "queue depths: " + strings.Join(stages, ", ")
+	}
+	return status
+}
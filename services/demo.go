@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrDemoTier is returned for features the demo tier does not include
+var ErrDemoTier = errors.New("not available in the demo tier")
+
+type demoTierKey struct{}
+
+// WithDemoTier marks ctx as a request made with the demo API key. Such
+// requests get at most maxArticles articles and never call the LLM, so a
+// publicly shared instance can't run up spend.
+func WithDemoTier(ctx context.Context, maxArticles int) context.Context {
+	return context.WithValue(ctx, demoTierKey{}, maxArticles)
+}
+
+// IsDemoTier reports whether ctx belongs to a demo-tier request
+func IsDemoTier(ctx context.Context) bool {
+	_, ok := ctx.Value(demoTierKey{}).(int)
+	return ok
+}
+
+// TierLimit caps an article limit (0 meaning the default) for demo-tier
+// requests and returns it unchanged otherwise
+func TierLimit(ctx context.Context, limit int) int {
+	maxArticles, ok := ctx.Value(demoTierKey{}).(int)
+	if !ok || (limit > 0 && limit <= maxArticles) {
+		return limit
+	}
+	return maxArticles
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"news-backend/cache"
+	"news-backend/config"
+	"news-backend/database"
+	"news-backend/models"
+	"news-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// digestCachePrefix namespaces digests in the cache
+const digestCachePrefix = "digest:"
+
+// digestWindow is how far back from the newest article a digest looks
+const digestWindow = 24 * time.Hour
+
+// Digest is a briefing on the top articles of the day around a location
+type Digest struct {
+	Briefing    string // empty when the LLM couldn't write one
+	Articles    []models.Article
+	Categories  []string // lowercased, sorted; empty for every category
+	WindowStart time.Time
+	WindowEnd   time.Time
+	GeneratedAt time.Time
+	ExpiresAt   time.Time // start of the next cycle
+}
+
+// DigestService selects an area's top articles of the day and has the LLM
+// write a briefing on them, cached per grid cell and category set until
+// the next cycle
+type DigestService struct {
+	db              *gorm.DB
+	cfg             *config.Config
+	llmService      *LLMService
+	trendingService *TrendingService
+	cache           cache.Cache
+}
+
+// NewDigestService creates a new digest service instance
+func NewDigestService(cfg *config.Config, llmService *LLMService, trendingService *TrendingService, store cache.Cache) *DigestService {
+	return &DigestService{
+		db:              database.GetDB(),
+		cfg:             cfg,
+		llmService:      llmService,
+		trendingService: trendingService,
+		cache:           store,
+	}
+}
+
+// GetDigest returns the current cycle's digest for a location and set of
+// categories, and whether it came from the cache. Digests whose briefing
+// failed (LLM unavailable, demo tier) are returned but not cached.
+func (s *DigestService) GetDigest(ctx context.Context, lat, lon float64, categories []string) (*Digest, bool, error) {
+	categories = normalizeDigestCategories(categories)
+	now := time.Now().UTC()
+	cycle := time.Duration(max(s.cfg.DigestCycleHours, 1)) * time.Hour
+	cycleStart := now.Truncate(cycle)
+	key := digestCacheKey(cycleStart, utils.ToGridCell(lat, lon, cacheGridPrecision), categories)
+
+	if digest, ok := s.getFromCache(ctx, key); ok {
+		return digest, true, nil
+	}
+
+	articles, windowStart, windowEnd, err := s.selectArticles(lat, lon, categories, now)
+	if err != nil {
+		return nil, false, err
+	}
+	digest := &Digest{
+		Articles:    articles,
+		Categories:  categories,
+		WindowStart: windowStart,
+		WindowEnd:   windowEnd,
+		GeneratedAt: now,
+		ExpiresAt:   cycleStart.Add(cycle),
+	}
+	if len(articles) > 0 {
+		if digest.Briefing, err = s.llmService.GenerateDigest(ctx, articles); err != nil {
+			log.Printf("Digest briefing unavailable: %v", err)
+			return digest, false, nil
+		}
+	}
+
+	s.putInCache(key, digest)
+	return digest, false, nil
+}
+
+// selectArticles picks the top DigestArticles articles within DigestRadiusKm
+// published in the 24 hours up to the newest one (the last 24 hours on a
+// live feed), one per story, ranked by the mean of their engagement-adjusted
+// relevance and their trending score relative to the area's top article
+func (s *DigestService) selectArticles(lat, lon float64, categories []string, now time.Time) ([]models.Article, time.Time, time.Time, error) {
+	query := s.db.Where("publication_date <= ?", now)
+	if len(categories) > 0 {
+		query = query.Where("id IN (?)", s.db.Table("article_categories").
+			Select("article_categories.article_id").
+			Joins("JOIN categories ON categories.id = article_categories.category_id").
+			Where("LOWER(categories.name) IN ?", categories))
+	}
+	var articles []models.Article
+	if err := query.Find(&articles).Error; err != nil {
+		return nil, time.Time{}, time.Time{}, fmt.Errorf("failed to load digest candidates: %w", err)
+	}
+	articles = utils.FilterByDistance(articles, lat, lon, s.cfg.DigestRadiusKm)
+	if len(articles) == 0 {
+		return []models.Article{}, now.Add(-digestWindow), now, nil
+	}
+
+	windowEnd := articles[0].PublicationDate
+	for i := range articles {
+		if articles[i].PublicationDate.After(windowEnd) {
+			windowEnd = articles[i].PublicationDate
+		}
+	}
+	windowStart := windowEnd.Add(-digestWindow)
+	recent := articles[:0]
+	for _, article := range articles {
+		if !article.PublicationDate.Before(windowStart) {
+			recent = append(recent, article)
+		}
+	}
+
+	trending, _, err := s.trendingService.GetTrendingNews(lat, lon, s.cfg.DigestRadiusKm, 0)
+	if err != nil {
+		return nil, time.Time{}, time.Time{}, fmt.Errorf("failed to fetch trending: %w", err)
+	}
+	trendingScores := make(map[string]float64, len(trending))
+	maxTrending := 0.0
+	for _, article := range trending {
+		trendingScores[article.ID] = article.TrendingScore
+		maxTrending = max(maxTrending, article.TrendingScore)
+	}
+
+	scores := make(map[string]float64, len(recent))
+	for _, article := range recent {
+		score := article.CurrentRelevance
+		if maxTrending > 0 {
+			score += trendingScores[article.ID] / maxTrending
+		}
+		scores[article.ID] = score / 2
+	}
+	sort.Slice(recent, func(i, j int) bool {
+		if a, b := scores[recent[i].ID], scores[recent[j].ID]; a != b {
+			return a > b
+		}
+		return utils.LessOnTie(recent[i], recent[j])
+	})
+
+	recent = collapseStories(recent)
+	if len(recent) > s.cfg.DigestArticles {
+		recent = recent[:s.cfg.DigestArticles]
+	}
+	return recent, windowStart, windowEnd, nil
+}
+
+// normalizeDigestCategories lowercases, deduplicates and sorts categories so
+// equal sets share a cache entry
+func normalizeDigestCategories(categories []string) []string {
+	seen := make(map[string]bool, len(categories))
+	normalized := make([]string, 0, len(categories))
+	for _, category := range categories {
+		category = strings.ToLower(strings.TrimSpace(category))
+		if category != "" && !seen[category] {
+			seen[category] = true
+			normalized = append(normalized, category)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// digestCacheKey formats the cache key for a cycle, grid cell and category set
+func digestCacheKey(cycleStart time.Time, cell utils.GridCell, categories []string) string {
+	return fmt.Sprintf("%s%d:%d_%d:%s", digestCachePrefix, cycleStart.Unix(), cell.LatCell, cell.LonCell, strings.Join(categories, ","))
+}
+
+// getFromCache returns a cached digest. Cache errors are logged and treated
+// as a miss.
+func (s *DigestService) getFromCache(ctx context.Context, key string) (*Digest, bool) {
+	data, ok, err := s.cache.Get(ctx, key)
+	if err != nil {
+		log.Printf("Failed to read digest cache: %v", err)
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+
+	var digest Digest
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&digest); err != nil {
+		log.Printf("Ignoring malformed digest cache entry %s: %v", key, err)
+		return nil, false
+	}
+	// gob drops empty slices
+	if digest.Categories == nil {
+		digest.Categories = []string{}
+	}
+	return &digest, true
+}
+
+// putInCache stores a digest until the next cycle. Entries are gob-encoded
+// since Article's JSON decoding expects the dataset format.
+func (s *DigestService) putInCache(key string, digest *Digest) {
+	var data bytes.Buffer
+	if err := gob.NewEncoder(&data).Encode(digest); err != nil {
+		log.Printf("Failed to encode digest cache entry %s: %v", key, err)
+		return
+	}
+	if err := s.cache.Set(context.Background(), key, data.Bytes(), time.Until(digest.ExpiresAt)); err != nil {
+		log.Printf("Failed to write digest cache: %v", err)
+	}
+}
@@ -0,0 +1,471 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"news-backend/config"
+	"news-backend/database"
+	"news-backend/models"
+	"news-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidEdition is returned for malformed slugs, locations or radii
+var ErrInvalidEdition = errors.New("invalid edition")
+
+// editionSlugPattern accepts lowercase words joined by hyphens, e.g. "sf-bay-area"
+var editionSlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// EditionFeed is an edition's precomputed news feed and trending list
+type EditionFeed struct {
+	Edition        models.Edition
+	Articles       []models.Article
+	TotalAvailable int      // Articles within the radius before limiting, adjusted for overrides
+	Pinned         []string // IDs of the articles editors pinned into the feed
+	Trending       []models.TrendingArticle
+	ComputedAt     time.Time
+}
+
+// EditionService manages local editions and keeps each enabled edition's
+// feed precomputed in memory
+type EditionService struct {
+	db              *gorm.DB
+	cfg             *config.Config
+	newsService     *NewsService
+	trendingService *TrendingService
+
+	mu    sync.RWMutex
+	feeds map[string]*EditionFeed // by slug
+}
+
+// NewEditionService creates a new edition service instance
+func NewEditionService(cfg *config.Config, newsService *NewsService, trendingService *TrendingService) *EditionService {
+	return &EditionService{
+		db:              database.GetDB(),
+		cfg:             cfg,
+		newsService:     newsService,
+		trendingService: trendingService,
+		feeds:           make(map[string]*EditionFeed),
+	}
+}
+
+// Start precomputes every enabled edition immediately and then on every
+// configured interval until ctx is cancelled. A non-positive interval
+// computes them once; feeds are then only recomputed when an edition changes.
+func (s *EditionService) Start(ctx context.Context) {
+	if err := s.Refresh(); err != nil {
+		log.Printf("Edition refresh failed: %v", err)
+	}
+	if s.cfg.EditionRefreshInterval <= 0 {
+		log.Println("Edition worker disabled after initial refresh")
+		return
+	}
+
+	interval := time.Duration(s.cfg.EditionRefreshInterval) * time.Second
+	log.Printf("Edition worker started (interval: %v)", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Edition worker stopped")
+			return
+		case <-ticker.C:
+		}
+
+		if err := s.Refresh(); err != nil {
+			log.Printf("Edition refresh failed: %v", err)
+		}
+	}
+}
+
+// Refresh recomputes the feed of every enabled edition. An edition whose
+// computation fails keeps its previous feed.
+func (s *EditionService) Refresh() error {
+	var editions []models.Edition
+	if err := s.db.Where("enabled = ?", true).Find(&editions).Error; err != nil {
+		return fmt.Errorf("failed to load editions: %w", err)
+	}
+
+	start := time.Now()
+	feeds := make(map[string]*EditionFeed, len(editions))
+	for _, edition := range editions {
+		feed, err := s.compute(edition)
+		if err != nil {
+			log.Printf("Failed to compute edition %s: %v", edition.Slug, err)
+			s.mu.RLock()
+			feed = s.feeds[edition.Slug]
+			s.mu.RUnlock()
+			if feed == nil {
+				continue
+			}
+		}
+		feeds[edition.Slug] = feed
+	}
+
+	s.mu.Lock()
+	s.feeds = feeds
+	s.mu.Unlock()
+
+	log.Printf("Precomputed %d editions in %v", len(feeds), time.Since(start))
+	return nil
+}
+
+// compute builds an edition's feed: the freshest nearby articles and what is
+// trending within its radius, with the edition's overrides applied. Feeds are
+// shared by every client, so they are computed outside any request's tier limits.
+func (s *EditionService) compute(edition models.Edition) (*EditionFeed, error) {
+	result, err := s.newsService.FetchArticlesWithMetadata(context.Background(), FetchParams{
+		Intent: models.IntentNearby,
+		Lat:    edition.Latitude,
+		Lon:    edition.Longitude,
+		Radius: edition.RadiusKm,
+		Ranking: RankingOptions{
+			Profile:     RankingFresh,
+			Lat:         edition.Latitude,
+			Lon:         edition.Longitude,
+			HasLocation: true,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch articles: %w", err)
+	}
+
+	trending, _, err := s.trendingService.GetTrendingNews(edition.Latitude, edition.Longitude, edition.RadiusKm, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch trending: %w", err)
+	}
+
+	var overrides []models.EditionOverride
+	if err := s.db.Where("edition_id = ?", edition.ID).Order("position, updated_at").Find(&overrides).Error; err != nil {
+		return nil, fmt.Errorf("failed to load overrides: %w", err)
+	}
+	feed := &EditionFeed{
+		Edition:        edition,
+		Articles:       result.Articles,
+		TotalAvailable: result.TotalAvailable,
+		Trending:       trending,
+		ComputedAt:     time.Now(),
+	}
+	if len(overrides) > 0 {
+		if err := s.applyOverrides(feed, overrides); err != nil {
+			return nil, err
+		}
+	}
+	return feed, nil
+}
+
+// applyOverrides drops excluded articles from a feed and its trending list,
+// then inserts pinned articles at their positions in ascending order, so
+// each lands in its slot unless the feed is shorter
+func (s *EditionService) applyOverrides(feed *EditionFeed, overrides []models.EditionOverride) error {
+	overridden := make(map[string]bool, len(overrides))
+	excluded := make(map[string]bool)
+	var pinIDs []string
+	for _, o := range overrides {
+		overridden[o.ArticleID] = true
+		if o.Action == models.EditionOverridePin {
+			pinIDs = append(pinIDs, o.ArticleID)
+		} else {
+			excluded[o.ArticleID] = true
+		}
+	}
+
+	var pinnedArticles []models.Article
+	if len(pinIDs) > 0 {
+		if err := s.db.Where("id IN ?", pinIDs).Find(&pinnedArticles).Error; err != nil {
+			return fmt.Errorf("failed to load pinned articles: %w", err)
+		}
+	}
+	pinned := make(map[string]models.Article, len(pinnedArticles))
+	for _, article := range pinnedArticles {
+		pinned[article.ID] = article
+	}
+
+	articles := make([]models.Article, 0, len(feed.Articles)+len(pinned))
+	for _, article := range feed.Articles {
+		if !overridden[article.ID] {
+			articles = append(articles, article)
+		}
+	}
+	removed := len(feed.Articles) - len(articles)
+
+	for _, o := range overrides {
+		article, ok := pinned[o.ArticleID]
+		if o.Action != models.EditionOverridePin || !ok {
+			continue
+		}
+		i := o.Position - 1
+		if i < 0 {
+			i = 0
+		}
+		if i > len(articles) {
+			i = len(articles)
+		}
+		articles = append(articles, models.Article{})
+		copy(articles[i+1:], articles[i:])
+		articles[i] = article
+		feed.Pinned = append(feed.Pinned, article.ID)
+	}
+	feed.TotalAvailable += len(feed.Pinned) - removed
+	feed.Articles = articles
+
+	trending := make([]models.TrendingArticle, 0, len(feed.Trending))
+	for _, t := range feed.Trending {
+		if !excluded[t.ID] {
+			trending = append(trending, t)
+		}
+	}
+	feed.Trending = trending
+	return nil
+}
+
+// Feed returns an enabled edition's precomputed feed, computing it on the
+// spot if the worker hasn't yet
+func (s *EditionService) Feed(slug string) (*EditionFeed, error) {
+	s.mu.RLock()
+	feed := s.feeds[slug]
+	s.mu.RUnlock()
+	if feed != nil {
+		return feed, nil
+	}
+
+	var edition models.Edition
+	if err := s.db.Where("slug = ? AND enabled = ?", slug, true).First(&edition).Error; err != nil {
+		return nil, err
+	}
+	return s.recompute(edition)
+}
+
+// recompute builds and stores one edition's feed
+func (s *EditionService) recompute(edition models.Edition) (*EditionFeed, error) {
+	feed, err := s.compute(edition)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.feeds[edition.Slug] = feed
+	s.mu.Unlock()
+	return feed, nil
+}
+
+// forget drops an edition's precomputed feed
+func (s *EditionService) forget(slug string) {
+	s.mu.Lock()
+	delete(s.feeds, slug)
+	s.mu.Unlock()
+}
+
+// ListEditions returns editions ordered by name, only enabled ones unless all is set
+func (s *EditionService) ListEditions(all bool) ([]models.Edition, error) {
+	query := s.db.Order("name")
+	if !all {
+		query = query.Where("enabled = ?", true)
+	}
+	var editions []models.Edition
+	if err := query.Find(&editions).Error; err != nil {
+		return nil, fmt.Errorf("failed to load editions: %w", err)
+	}
+	return editions, nil
+}
+
+// validateEdition normalizes and checks an edition's fields. A missing
+// radius defaults to the trending radius.
+func (s *EditionService) validateEdition(edition *models.Edition) error {
+	edition.Slug = strings.ToLower(strings.TrimSpace(edition.Slug))
+	edition.Name = strings.TrimSpace(edition.Name)
+	if !editionSlugPattern.MatchString(edition.Slug) {
+		return fmt.Errorf("%w: slug must be lowercase letters, digits and hyphens", ErrInvalidEdition)
+	}
+	if edition.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidEdition)
+	}
+	if err := utils.ValidateLocation(edition.Latitude, edition.Longitude); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEdition, err)
+	}
+	if edition.RadiusKm == 0 {
+		edition.RadiusKm = s.cfg.TrendingRadius
+	}
+	if edition.RadiusKm < 0 {
+		return fmt.Errorf("%w: radius_km must be positive", ErrInvalidEdition)
+	}
+	return nil
+}
+
+// CreateEdition validates and stores an edition, precomputing its feed when enabled
+func (s *EditionService) CreateEdition(edition *models.Edition) error {
+	if err := s.validateEdition(edition); err != nil {
+		return err
+	}
+
+	var existing int64
+	if err := s.db.Model(&models.Edition{}).Where("slug = ?", edition.Slug).Count(&existing).Error; err != nil {
+		return fmt.Errorf("failed to check edition slug: %w", err)
+	}
+	if existing > 0 {
+		return fmt.Errorf("%w: slug %q is taken", ErrInvalidEdition, edition.Slug)
+	}
+
+	edition.ID = 0
+	if err := s.db.Create(edition).Error; err != nil {
+		return fmt.Errorf("failed to store edition: %w", err)
+	}
+	if edition.Enabled {
+		if _, err := s.recompute(*edition); err != nil {
+			log.Printf("Failed to compute edition %s: %v", edition.Slug, err)
+		}
+	}
+	return nil
+}
+
+// UpdateEdition replaces an edition's name, location, radius and status,
+// recomputing or dropping its feed to match
+func (s *EditionService) UpdateEdition(slug string, update models.Edition) (*models.Edition, error) {
+	var edition models.Edition
+	if err := s.db.Where("slug = ?", slug).First(&edition).Error; err != nil {
+		return nil, err
+	}
+
+	update.Slug = edition.Slug
+	if err := s.validateEdition(&update); err != nil {
+		return nil, err
+	}
+	edition.Name = update.Name
+	edition.Latitude = update.Latitude
+	edition.Longitude = update.Longitude
+	edition.RadiusKm = update.RadiusKm
+	edition.Enabled = update.Enabled
+	if err := s.db.Save(&edition).Error; err != nil {
+		return nil, fmt.Errorf("failed to update edition: %w", err)
+	}
+
+	if !edition.Enabled {
+		s.forget(edition.Slug)
+	} else if _, err := s.recompute(edition); err != nil {
+		log.Printf("Failed to compute edition %s: %v", edition.Slug, err)
+		s.forget(edition.Slug)
+	}
+	return &edition, nil
+}
+
+// DeleteEdition removes an edition, its overrides and its precomputed feed
+func (s *EditionService) DeleteEdition(slug string) error {
+	var edition models.Edition
+	if err := s.db.Where("slug = ?", slug).First(&edition).Error; err != nil {
+		return err
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("edition_id = ?", edition.ID).Delete(&models.EditionOverride{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&edition).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete edition: %w", err)
+	}
+	s.forget(slug)
+	return nil
+}
+
+// ListOverrides returns an edition's overrides, pins first in feed order
+func (s *EditionService) ListOverrides(slug string) ([]models.EditionOverride, error) {
+	var edition models.Edition
+	if err := s.db.Where("slug = ?", slug).First(&edition).Error; err != nil {
+		return nil, err
+	}
+
+	var overrides []models.EditionOverride
+	err := s.db.Where("edition_id = ?", edition.ID).
+		Order("action DESC, position, updated_at").
+		Find(&overrides).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load overrides: %w", err)
+	}
+	return overrides, nil
+}
+
+// SetOverride pins or excludes an article in an edition's feed, replacing
+// any earlier override of that article, and recomputes the feed. A pin
+// without a position goes to the top.
+func (s *EditionService) SetOverride(slug string, override models.EditionOverride) (*models.EditionOverride, error) {
+	var edition models.Edition
+	if err := s.db.Where("slug = ?", slug).First(&edition).Error; err != nil {
+		return nil, err
+	}
+
+	if !models.IsValidEditionOverrideAction(override.Action) {
+		return nil, fmt.Errorf("%w: action must be %q or %q", ErrInvalidEdition, models.EditionOverridePin, models.EditionOverrideExclude)
+	}
+	if override.Position < 0 {
+		return nil, fmt.Errorf("%w: position must be positive", ErrInvalidEdition)
+	}
+	if override.Action == models.EditionOverrideExclude {
+		override.Position = 0
+	} else if override.Position == 0 {
+		override.Position = 1
+	}
+
+	var articles int64
+	if err := s.db.Model(&models.Article{}).Where("id = ?", override.ArticleID).Count(&articles).Error; err != nil {
+		return nil, fmt.Errorf("failed to check article: %w", err)
+	}
+	if articles == 0 {
+		return nil, fmt.Errorf("%w: article %q not found", ErrInvalidEdition, override.ArticleID)
+	}
+
+	stored := models.EditionOverride{EditionID: edition.ID, ArticleID: override.ArticleID}
+	if err := s.db.Where(stored).FirstOrInit(&stored).Error; err != nil {
+		return nil, fmt.Errorf("failed to load override: %w", err)
+	}
+	stored.Action = override.Action
+	stored.Position = override.Position
+	if err := s.db.Save(&stored).Error; err != nil {
+		return nil, fmt.Errorf("failed to store override: %w", err)
+	}
+
+	s.refreshEdition(edition)
+	return &stored, nil
+}
+
+// DeleteOverride removes an article's override from an edition and
+// recomputes the feed
+func (s *EditionService) DeleteOverride(slug, articleID string) error {
+	var edition models.Edition
+	if err := s.db.Where("slug = ?", slug).First(&edition).Error; err != nil {
+		return err
+	}
+
+	result := s.db.Where("edition_id = ? AND article_id = ?", edition.ID, articleID).Delete(&models.EditionOverride{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete override: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	s.refreshEdition(edition)
+	return nil
+}
+
+// refreshEdition recomputes an enabled edition's feed after its overrides
+// changed, dropping the stale feed if that fails
+func (s *EditionService) refreshEdition(edition models.Edition) {
+	if !edition.Enabled {
+		return
+	}
+	if _, err := s.recompute(edition); err != nil {
+		log.Printf("Failed to compute edition %s: %v", edition.Slug, err)
+		s.forget(edition.Slug)
+	}
+}
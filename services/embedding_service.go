@@ -0,0 +1,505 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"news-backend/ann"
+	"news-backend/config"
+	"news-backend/database"
+	"news-backend/models"
+	"news-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// ErrEmbeddingsDisabled is returned when semantic search is requested but embeddings are off
+var ErrEmbeddingsDisabled = errors.New("embeddings are disabled")
+
+// embeddingBatchSize limits how many texts are sent per embeddings request
+const embeddingBatchSize = 100
+
+// embeddingIndexEfConstruction is the HNSW candidate list size used while
+// building the in-memory index
+const embeddingIndexEfConstruction = 200
+
+type EmbeddingService struct {
+	db         *gorm.DB
+	cfg        *config.Config
+	llmService *LLMService
+
+	// In-memory ANN index over stored embeddings, updated as embeddings are
+	// stored or dropped and rebuilt when too many entries are tombstoned.
+	// Nil until the first load, in which case searches scan the database.
+	indexMu     sync.RWMutex
+	index       *ann.Index
+	lastRebuild time.Time
+	lastUpdate  time.Time
+
+	// maintainMu orders incremental updates against rebuilds, so a rebuild
+	// never misses an embedding stored or dropped while it reads the table
+	maintainMu sync.Mutex
+
+	// interestMu serializes user interest vector updates
+	interestMu sync.Mutex
+}
+
+// EmbeddingIndexStats describes the in-memory index and how far it lags the database
+type EmbeddingIndexStats struct {
+	Loaded           bool      `json:"loaded"`
+	Size             int       `json:"size"`              // searchable vectors
+	Tombstones       int       `json:"tombstones"`        // removed vectors still in the graph
+	StoredEmbeddings int64     `json:"stored_embeddings"` // rows in article_embeddings
+	PendingArticles  int64     `json:"pending_articles"`  // articles without an embedding yet
+	LastRebuild      time.Time `json:"last_rebuild"`
+	LastUpdate       time.Time `json:"last_update"`
+}
+
+// NewEmbeddingService creates a new embedding service instance
+func NewEmbeddingService(cfg *config.Config, llmService *LLMService) *EmbeddingService {
+	return &EmbeddingService{
+		db:         database.GetDB(),
+		cfg:        cfg,
+		llmService: llmService,
+	}
+}
+
+// Enabled reports whether embeddings are configured
+func (s *EmbeddingService) Enabled() bool {
+	return s.cfg.EmbeddingsEnabled
+}
+
+// Start embeds articles missing an embedding and loads every embedding into
+// the in-memory index. Then, on every EMBEDDING_INDEX_REFRESH interval until
+// ctx is cancelled, it embeds new articles (which adds them to the index)
+// and rebuilds the index once tombstones exceed EMBEDDING_INDEX_COMPACT_RATIO
+// of it. A non-positive interval loads the index once.
+func (s *EmbeddingService) Start(ctx context.Context) {
+	if !s.Enabled() {
+		log.Println("Embedding index disabled")
+		return
+	}
+
+	if err := s.IndexArticles(ctx); err != nil {
+		log.Printf("Warning: Failed to index embeddings: %v", err)
+	}
+	if err := s.LoadIndex(); err != nil {
+		log.Printf("Warning: Failed to load embedding index: %v", err)
+	}
+
+	if s.cfg.EmbeddingIndexRefresh <= 0 {
+		return
+	}
+	interval := time.Duration(s.cfg.EmbeddingIndexRefresh) * time.Second
+	log.Printf("Embedding index worker started (refresh: %v)", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Embedding index worker stopped")
+			return
+		case <-ticker.C:
+		}
+
+		if err := s.IndexArticles(ctx); err != nil {
+			log.Printf("Warning: Failed to index embeddings: %v", err)
+		}
+		if s.needsCompaction() {
+			if err := s.LoadIndex(); err != nil {
+				log.Printf("Warning: Failed to rebuild embedding index: %v", err)
+			}
+		}
+	}
+}
+
+// needsCompaction reports whether tombstones make up too much of the index
+// (or it never loaded)
+func (s *EmbeddingService) needsCompaction() bool {
+	index := s.loadedIndex()
+	if index == nil {
+		return true
+	}
+	tombstones := index.Deleted()
+	total := index.Len() + tombstones
+	return total > 0 && float64(tombstones)/float64(total) > s.cfg.EmbeddingIndexCompactRatio
+}
+
+// LoadIndex builds an ANN index from the stored embeddings and swaps it in
+func (s *EmbeddingService) LoadIndex() error {
+	s.maintainMu.Lock()
+	defer s.maintainMu.Unlock()
+
+	start := time.Now()
+	index := ann.New(s.cfg.EmbeddingIndexM, embeddingIndexEfConstruction)
+
+	var batch []models.ArticleEmbedding
+	err := s.currentEmbeddings().FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+		for i := range batch {
+			index.Add(batch[i].ArticleID, batch[i].GetVector())
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to load embeddings: %w", err)
+	}
+
+	s.indexMu.Lock()
+	s.index = index
+	s.lastRebuild = time.Now()
+	s.lastUpdate = s.lastRebuild
+	s.indexMu.Unlock()
+
+	log.Printf("Loaded %d embeddings into memory index in %v", index.Len(), time.Since(start))
+	return nil
+}
+
+// currentEmbeddings scopes a query to embeddings computed with EMBEDDING_MODEL.
+// Vectors from another model aren't comparable, so those articles count as
+// not embedded and are re-embedded by the next IndexArticles.
+func (s *EmbeddingService) currentEmbeddings() *gorm.DB {
+	return s.db.Model(&models.ArticleEmbedding{}).Where("model = ?", s.cfg.EmbeddingModel)
+}
+
+// loadedIndex returns the current in-memory index, or nil before the first load
+func (s *EmbeddingService) loadedIndex() *ann.Index {
+	s.indexMu.RLock()
+	defer s.indexMu.RUnlock()
+	return s.index
+}
+
+// updateIndex applies an incremental change to the loaded index, if any.
+// Callers hold maintainMu.
+func (s *EmbeddingService) updateIndex(update func(index *ann.Index)) {
+	s.indexMu.Lock()
+	defer s.indexMu.Unlock()
+	if s.index == nil {
+		return
+	}
+	update(s.index)
+	s.lastUpdate = time.Now()
+}
+
+// Forget removes an article from the in-memory index after its embedding
+// has been deleted. It waits for an in-flight batch to store its vectors, so
+// one computed before the deletion can't be added back afterwards.
+func (s *EmbeddingService) Forget(articleID string) {
+	s.maintainMu.Lock()
+	defer s.maintainMu.Unlock()
+	s.updateIndex(func(index *ann.Index) {
+		index.Remove(articleID)
+	})
+}
+
+// IndexStats reports the in-memory index size and how far it lags the database
+func (s *EmbeddingService) IndexStats() (*EmbeddingIndexStats, error) {
+	if !s.Enabled() {
+		return nil, ErrEmbeddingsDisabled
+	}
+
+	stats := &EmbeddingIndexStats{}
+	if err := s.currentEmbeddings().Count(&stats.StoredEmbeddings).Error; err != nil {
+		return nil, fmt.Errorf("failed to count embeddings: %w", err)
+	}
+	err := s.db.Model(&models.Article{}).
+		Where("id NOT IN (?)", s.currentEmbeddings().Select("article_id")).
+		Count(&stats.PendingArticles).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count articles without embeddings: %w", err)
+	}
+
+	s.indexMu.RLock()
+	defer s.indexMu.RUnlock()
+	if s.index != nil {
+		stats.Loaded = true
+		stats.Size = s.index.Len()
+		stats.Tombstones = s.index.Deleted()
+	}
+	stats.LastRebuild = s.lastRebuild
+	stats.LastUpdate = s.lastUpdate
+	return stats, nil
+}
+
+// IndexArticles computes and stores embeddings for articles that don't have one
+// from EMBEDDING_MODEL yet
+func (s *EmbeddingService) IndexArticles(ctx context.Context) error {
+	if !s.Enabled() {
+		return ErrEmbeddingsDisabled
+	}
+
+	var articles []models.Article
+	err := s.db.Model(&models.Article{}).
+		Where("id NOT IN (?)", s.currentEmbeddings().Select("article_id")).
+		Find(&articles).Error
+	if err != nil {
+		return fmt.Errorf("failed to load articles without embeddings: %w", err)
+	}
+
+	if len(articles) == 0 {
+		log.Println("All articles already have embeddings")
+		return nil
+	}
+
+	log.Printf("Computing embeddings for %d articles", len(articles))
+
+	indexed := 0
+	for i := 0; i < len(articles); i += embeddingBatchSize {
+		end := i + embeddingBatchSize
+		if end > len(articles) {
+			end = len(articles)
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+
+		if err := s.indexBatch(ctx, articles[i:end]); err != nil {
+			log.Printf("Failed to embed batch: %v", err)
+			continue
+		}
+		indexed += end - i
+	}
+
+	log.Printf("Embedding index complete: %d of %d articles embedded", indexed, len(articles))
+	return nil
+}
+
+// indexBatch embeds and stores a batch of articles
+func (s *EmbeddingService) indexBatch(ctx context.Context, articles []models.Article) error {
+	texts := make([]string, len(articles))
+	for i, article := range articles {
+		texts[i] = embeddingText(article)
+	}
+
+	vectors, err := s.llmService.CreateEmbeddings(ctx, texts)
+	if err != nil {
+		return err
+	}
+
+	embeddings := make([]models.ArticleEmbedding, len(articles))
+	for i, article := range articles {
+		embeddings[i] = models.ArticleEmbedding{
+			ArticleID: article.ID,
+			Model:     s.cfg.EmbeddingModel,
+		}
+		embeddings[i].SetVector(vectors[i])
+	}
+
+	s.maintainMu.Lock()
+	defer s.maintainMu.Unlock()
+	if err := s.db.Save(&embeddings).Error; err != nil {
+		return err
+	}
+
+	// An article edited or deleted while its vector was computed has already
+	// dropped its embedding, so the one just saved is stale. Checking after the
+	// save covers every change: one committed later drops the new row itself
+	// and then calls Forget, which waits for maintainMu.
+	var current []models.Article
+	if err := s.db.Select("id", "title", "description").Where("id IN ?", articleIDs(articles)).Find(&current).Error; err != nil {
+		return fmt.Errorf("failed to recheck articles: %w", err)
+	}
+	currentText := make(map[string]string, len(current))
+	for _, article := range current {
+		currentText[article.ID] = embeddingText(article)
+	}
+	var stale []string
+	fresh := make(map[string]bool, len(articles))
+	for i, article := range articles {
+		if text, ok := currentText[article.ID]; ok && text == texts[i] {
+			fresh[article.ID] = true
+		} else {
+			stale = append(stale, article.ID)
+		}
+	}
+	if len(stale) > 0 {
+		if err := s.db.Where("article_id IN ?", stale).Delete(&models.ArticleEmbedding{}).Error; err != nil {
+			return fmt.Errorf("failed to drop stale embeddings: %w", err)
+		}
+	}
+
+	s.updateIndex(func(index *ann.Index) {
+		for i, article := range articles {
+			if fresh[article.ID] {
+				index.Add(article.ID, vectors[i])
+			}
+		}
+	})
+	return nil
+}
+
+// SemanticSearch ranks articles by cosine similarity between the query and article
+// embeddings, considering only articles published within dates
+func (s *EmbeddingService) SemanticSearch(ctx context.Context, query string, limit int, dates DateRange) (*FetchResult, error) {
+	if !s.Enabled() {
+		return nil, ErrEmbeddingsDisabled
+	}
+
+	vectors, err := s.llmService.CreateEmbeddings(ctx, []string{query})
+	if err != nil {
+		return nil, err
+	}
+	queryVector := vectors[0]
+
+	var ids []string
+	var scores map[string]float64
+	var total int
+	if index := s.loadedIndex(); index != nil {
+		ids, scores, total, err = s.searchIndex(index, queryVector, limit, dates)
+	} else {
+		ids, scores, total, err = s.searchDatabase(queryVector, limit, dates)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var articles []models.Article
+	if err := s.db.Where("id IN ?", ids).Find(&articles).Error; err != nil {
+		return nil, fmt.Errorf("failed to load articles: %w", err)
+	}
+
+	for i := range articles {
+		articles[i].Similarity = scores[articles[i].ID]
+	}
+	utils.SortByScoreMap(articles, scores, utils.Descending)
+
+	return &FetchResult{
+		Articles:       articles,
+		TotalAvailable: total,
+	}, nil
+}
+
+// searchIndex finds the limit articles most similar to queryVector in the
+// in-memory index. Date-filtered searches score the in-range articles exactly,
+// since a selective filter would starve the graph search of results.
+func (s *EmbeddingService) searchIndex(index *ann.Index, queryVector []float32, limit int, dates DateRange) ([]string, map[string]float64, int, error) {
+	var results []ann.Result
+	total := index.Len()
+	if dates.IsZero() {
+		results = index.Search(queryVector, limit, s.cfg.EmbeddingIndexEfSearch)
+	} else {
+		var inRange []string
+		if err := dates.apply(s.db.Model(&models.Article{})).Pluck("id", &inRange).Error; err != nil {
+			return nil, nil, 0, fmt.Errorf("failed to load articles in range: %w", err)
+		}
+		allowed := make(map[string]bool, len(inRange))
+		for _, id := range inRange {
+			allowed[id] = true
+		}
+
+		total = 0
+		results = index.Exact(queryVector, limit, func(id string) bool {
+			if allowed[id] {
+				total++
+				return true
+			}
+			return false
+		})
+	}
+
+	ids := make([]string, len(results))
+	scores := make(map[string]float64, len(results))
+	for i, result := range results {
+		ids[i] = result.ID
+		scores[result.ID] = result.Score
+	}
+	return ids, scores, total, nil
+}
+
+// searchDatabase scores every stored embedding, used until the in-memory
+// index has loaded
+func (s *EmbeddingService) searchDatabase(queryVector []float32, limit int, dates DateRange) ([]string, map[string]float64, int, error) {
+	embeddingQuery := s.currentEmbeddings()
+	if !dates.IsZero() {
+		inRange := dates.apply(s.db.Model(&models.Article{}).Select("id"))
+		embeddingQuery = embeddingQuery.Where("article_id IN (?)", inRange)
+	}
+
+	var embeddings []models.ArticleEmbedding
+	if err := embeddingQuery.Find(&embeddings).Error; err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to load embeddings: %w", err)
+	}
+
+	scores := make(map[string]float64, len(embeddings))
+	ids := make([]string, 0, len(embeddings))
+	for i := range embeddings {
+		id := embeddings[i].ArticleID
+		scores[id] = utils.CosineSimilarity(queryVector, embeddings[i].GetVector())
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		if scores[ids[i]] != scores[ids[j]] {
+			return scores[ids[i]] > scores[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids, scores, len(embeddings), nil
+}
+
+// SimilarityScores returns the cosine similarity between the query and each
+// of the given articles that has a stored embedding, along with the nearest
+// articles published within dates most similar to the query
+func (s *EmbeddingService) SimilarityScores(ctx context.Context, query string, articleIDs []string, nearest int, dates DateRange) (map[string]float64, error) {
+	if !s.Enabled() {
+		return nil, ErrEmbeddingsDisabled
+	}
+
+	vectors, err := s.llmService.CreateEmbeddings(ctx, []string{query})
+	if err != nil {
+		return nil, err
+	}
+
+	var scores map[string]float64
+	index := s.loadedIndex()
+	if index != nil {
+		scores = make(map[string]float64, len(articleIDs)+nearest)
+		for _, id := range articleIDs {
+			if score, ok := index.Similarity(vectors[0], id); ok {
+				scores[id] = score
+			}
+		}
+	} else {
+		var embeddings []models.ArticleEmbedding
+		if err := s.currentEmbeddings().Where("article_id IN ?", articleIDs).Find(&embeddings).Error; err != nil {
+			return nil, fmt.Errorf("failed to load embeddings: %w", err)
+		}
+		scores = make(map[string]float64, len(embeddings)+nearest)
+		for i := range embeddings {
+			scores[embeddings[i].ArticleID] = utils.CosineSimilarity(vectors[0], embeddings[i].GetVector())
+		}
+	}
+	if nearest <= 0 {
+		return scores, nil
+	}
+
+	var ids []string
+	var nearestScores map[string]float64
+	if index != nil {
+		ids, nearestScores, _, err = s.searchIndex(index, vectors[0], nearest, dates)
+	} else {
+		ids, nearestScores, _, err = s.searchDatabase(vectors[0], nearest, dates)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		scores[id] = nearestScores[id]
+	}
+	return scores, nil
+}
+
+// embeddingText builds the text that represents an article in vector space
+func embeddingText(article models.Article) string {
+	return article.Title + "\n" + article.Description
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"news-backend/cache"
+	"news-backend/config"
+	"news-backend/models"
+)
+
+// newTestEmbeddingService returns an EmbeddingService whose provider answers
+// each embeddings request with the data built by respond from its inputs
+func newTestEmbeddingService(t *testing.T, respond func(inputs []string) string) *EmbeddingService {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Input []string `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"object": "list", "data": [%s], "usage": {"total_tokens": 1}}`, respond(request.Input))
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := &config.Config{
+		LLMProviders:        []config.LLMProviderConfig{{Name: "test", APIKey: "key", BaseURL: server.URL}},
+		LLMBreakerThreshold: 3,
+		LLMBreakerCooldown:  60,
+		EmbeddingsEnabled:   true,
+		EmbeddingModel:      "embed-v2",
+		EmbeddingIndexM:     16,
+	}
+	invalidation, err := NewInvalidationService(cfg)
+	if err != nil {
+		t.Fatalf("NewInvalidationService() error = %v", err)
+	}
+	llmService := NewLLMService(cfg, invalidation, cache.NewMemory())
+	return NewEmbeddingService(cfg, llmService)
+}
+
+func embeddingItem(index int, vector string) string {
+	return fmt.Sprintf(`{"object": "embedding", "index": %d, "embedding": %s}`, index, vector)
+}
+
+func TestCreateEmbeddingsRejectsBadIndexes(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []string
+		err   string
+	}{
+		{"Index past the inputs", []string{embeddingItem(0, "[1, 0]"), embeddingItem(2, "[0, 1]")}, "out of range"},
+		{"Negative index", []string{embeddingItem(-1, "[1, 0]"), embeddingItem(0, "[0, 1]")}, "out of range"},
+		{"Duplicate index", []string{embeddingItem(1, "[1, 0]"), embeddingItem(1, "[0, 1]")}, "duplicate embedding index 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			openTestDB(t)
+			service := newTestEmbeddingService(t, func([]string) string { return strings.Join(tt.items, ",") })
+			vectors, err := service.llmService.CreateEmbeddings(context.Background(), []string{"first", "second"})
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("CreateEmbeddings() = %v, %v, expected an error containing %q", vectors, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestIndexArticlesReembedsOtherModels(t *testing.T) {
+	db := openTestDB(t)
+	var embedded []string
+	service := newTestEmbeddingService(t, func(inputs []string) string {
+		items := make([]string, len(inputs))
+		for i, input := range inputs {
+			embedded = append(embedded, input)
+			items[i] = embeddingItem(i, "[1, 0]")
+		}
+		return strings.Join(items, ",")
+	})
+
+	now := time.Now()
+	articles := []models.Article{
+		{ID: "current", Title: "Current", URL: "https://example.com/current", PublicationDate: now},
+		{ID: "stale", Title: "Stale", URL: "https://example.com/stale", PublicationDate: now},
+	}
+	if err := db.Create(&articles).Error; err != nil {
+		t.Fatalf("failed to create articles: %v", err)
+	}
+	stored := []models.ArticleEmbedding{
+		{ArticleID: "current", Model: "embed-v2"},
+		{ArticleID: "stale", Model: "embed-v1"},
+	}
+	stored[0].SetVector([]float32{0, 1})
+	stored[1].SetVector([]float32{1, 0, 0})
+	if err := db.Create(&stored).Error; err != nil {
+		t.Fatalf("failed to create embeddings: %v", err)
+	}
+
+	// Until re-embedded, the other model's vector is pending and never searched
+	stats, err := service.IndexStats()
+	if err != nil {
+		t.Fatalf("IndexStats() error = %v", err)
+	}
+	if stats.StoredEmbeddings != 1 || stats.PendingArticles != 1 {
+		t.Errorf("IndexStats() = %+v, expected 1 stored embedding and 1 pending article", stats)
+	}
+	result, err := service.SemanticSearch(context.Background(), "query", 10, DateRange{})
+	if err != nil {
+		t.Fatalf("SemanticSearch() error = %v", err)
+	}
+	if len(result.Articles) != 1 || result.Articles[0].ID != "current" {
+		t.Errorf("SemanticSearch() before re-embedding = %+v, expected only the current model's article", result.Articles)
+	}
+
+	embedded = nil
+	if err := service.IndexArticles(context.Background()); err != nil {
+		t.Fatalf("IndexArticles() error = %v", err)
+	}
+	if len(embedded) != 1 || embedded[0] != embeddingText(articles[1]) {
+		t.Errorf("IndexArticles() embedded %q, expected only the stale article", embedded)
+	}
+	var embedding models.ArticleEmbedding
+	if err := db.First(&embedding, "article_id = ?", "stale").Error; err != nil {
+		t.Fatal(err)
+	}
+	if embedding.Model != "embed-v2" || embedding.Dimensions != 2 {
+		t.Errorf("re-embedded article has model %q with %d dimensions, expected embed-v2 with 2", embedding.Model, embedding.Dimensions)
+	}
+
+	if err := service.LoadIndex(); err != nil {
+		t.Fatalf("LoadIndex() error = %v", err)
+	}
+	result, err = service.SemanticSearch(context.Background(), "query", 10, DateRange{})
+	if err != nil {
+		t.Fatalf("SemanticSearch() error = %v", err)
+	}
+	if len(result.Articles) != 2 || result.Articles[0].ID != "stale" || result.Articles[0].Similarity < 0.99 {
+		t.Errorf("SemanticSearch() after re-embedding = %+v, expected both articles, the re-embedded one first", result.Articles)
+	}
+}
+
+func TestIndexArticlesDropsVectorsOfArticlesChangedMeanwhile(t *testing.T) {
+	db := openTestDB(t)
+	service := newTestEmbeddingService(t, func(inputs []string) string {
+		// Edit one article and delete another while their vectors are computed
+		if err := db.Model(&models.Article{}).Where("id = ?", "edited").Update("title", "Edited headline").Error; err != nil {
+			t.Errorf("failed to edit article: %v", err)
+		}
+		if err := db.Delete(&models.Article{}, "id = ?", "deleted").Error; err != nil {
+			t.Errorf("failed to delete article: %v", err)
+		}
+		items := make([]string, len(inputs))
+		for i := range inputs {
+			items[i] = embeddingItem(i, "[1, 0]")
+		}
+		return strings.Join(items, ",")
+	})
+
+	now := time.Now()
+	articles := []models.Article{
+		{ID: "kept", Title: "Kept", URL: "https://example.com/kept", PublicationDate: now},
+		{ID: "edited", Title: "Original headline", URL: "https://example.com/edited", PublicationDate: now},
+		{ID: "deleted", Title: "Deleted", URL: "https://example.com/deleted", PublicationDate: now},
+	}
+	if err := db.Create(&articles).Error; err != nil {
+		t.Fatalf("failed to create articles: %v", err)
+	}
+	if err := service.LoadIndex(); err != nil {
+		t.Fatalf("LoadIndex() error = %v", err)
+	}
+
+	if err := service.IndexArticles(context.Background()); err != nil {
+		t.Fatalf("IndexArticles() error = %v", err)
+	}
+
+	var stored []string
+	if err := db.Model(&models.ArticleEmbedding{}).Pluck("article_id", &stored).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 1 || stored[0] != "kept" {
+		t.Errorf("stored embeddings = %v, expected only the unchanged article's", stored)
+	}
+	if size := service.loadedIndex().Len(); size != 1 {
+		t.Errorf("index size = %d, expected only the unchanged article", size)
+	}
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"news-backend/config"
+	"news-backend/database"
+	"news-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// eventRollupHourFormat is the SQLite strftime format of a rollup hour
+const eventRollupHourFormat = "%Y-%m-%dT%H:00:00Z"
+
+// EventRetentionWorker rolls user events older than the trending window up
+// into hourly per-article aggregates (event_rollups) and deletes raw events
+// older than EVENT_RETENTION_DAYS once they are rolled up
+type EventRetentionWorker struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+// NewEventRetentionWorker creates a new event retention worker
+func NewEventRetentionWorker(cfg *config.Config) *EventRetentionWorker {
+	return &EventRetentionWorker{
+		db:  database.GetDB(),
+		cfg: cfg,
+	}
+}
+
+// Start runs a pass immediately and then on every configured interval until
+// ctx is cancelled. A non-positive interval disables the worker.
+func (w *EventRetentionWorker) Start(ctx context.Context) {
+	if w.cfg.EventRollupInterval <= 0 {
+		log.Println("Event retention worker disabled")
+		return
+	}
+
+	interval := time.Duration(w.cfg.EventRollupInterval) * time.Second
+	log.Printf("Event retention worker started (interval: %v)", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Run(); err != nil {
+			log.Printf("Event retention failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Println("Event retention worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run rolls up the complete hours of events that have left the trending
+// window and weren't rolled up yet, then deletes rolled-up raw events past
+// the retention period
+func (w *EventRetentionWorker) Run() error {
+	watermark, err := rollupWatermark(w.db)
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Now().UTC().Add(-time.Duration(w.cfg.TrendingTimeWindow) * time.Hour).Truncate(time.Hour)
+	if cutoff.After(watermark) {
+		var rows []struct {
+			ArticleID string
+			Hour      string
+			EventType string
+			Count     int
+			Users     int
+		}
+		err := w.db.Model(&models.UserEvent{}).
+			Select("article_id, strftime(?, timestamp) AS hour, event_type, COUNT(*) AS count, COUNT(DISTINCT user_id) AS users", eventRollupHourFormat).
+			Where("timestamp >= ? AND timestamp < ?", watermark, cutoff).
+			Group("article_id, hour, event_type").
+			Scan(&rows).Error
+		if err != nil {
+			return fmt.Errorf("failed to aggregate events: %w", err)
+		}
+
+		rollups := make([]models.EventRollup, 0, len(rows))
+		for _, row := range rows {
+			hour, err := time.Parse(time.RFC3339, row.Hour)
+			if err != nil {
+				return fmt.Errorf("failed to parse event hour %q: %w", row.Hour, err)
+			}
+			rollups = append(rollups, models.EventRollup{
+				ArticleID: row.ArticleID,
+				Hour:      hour,
+				EventType: row.EventType,
+				Count:     row.Count,
+				Users:     row.Users,
+			})
+		}
+		if len(rollups) > 0 {
+			err := w.db.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "article_id"}, {Name: "hour"}, {Name: "event_type"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"count": gorm.Expr("event_rollups.count + excluded.count"),
+					"users": gorm.Expr("event_rollups.users + excluded.users"),
+				}),
+			}).CreateInBatches(&rollups, 500).Error
+			if err != nil {
+				return fmt.Errorf("failed to store event rollups: %w", err)
+			}
+		}
+		log.Printf("Rolled up events before %s into %d hourly aggregates", cutoff.Format(time.RFC3339), len(rollups))
+
+		if watermark, err = rollupWatermark(w.db); err != nil {
+			return err
+		}
+	}
+
+	if w.cfg.EventRetentionDays <= 0 {
+		return nil
+	}
+	// Only events already reflected in the rollups may go
+	deleteBefore := time.Now().UTC().AddDate(0, 0, -w.cfg.EventRetentionDays)
+	if watermark.Before(deleteBefore) {
+		deleteBefore = watermark
+	}
+	result := w.db.Where("timestamp < ?", deleteBefore).Delete(&models.UserEvent{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete old events: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("Deleted %d user events older than %s", result.RowsAffected, deleteBefore.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// rollupWatermark returns the end of the last rolled-up hour: events before
+// it are counted in event_rollups, later ones only in user_events. Zero when
+// nothing was rolled up yet.
+func rollupWatermark(db *gorm.DB) (time.Time, error) {
+	var latest []models.EventRollup
+	if err := db.Select("hour").Order("hour DESC").Limit(1).Find(&latest).Error; err != nil {
+		return time.Time{}, fmt.Errorf("failed to load rollup watermark: %w", err)
+	}
+	if len(latest) == 0 {
+		return time.Time{}, nil
+	}
+	return latest[0].Hour.UTC().Add(time.Hour), nil
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"news-backend/config"
+	"news-backend/models"
+)
+
+func TestEventRetentionWithLocalTimeZone(t *testing.T) {
+	// Ahead of UTC, so local timestamps sort after the UTC bounds as text
+	local := time.Local
+	time.Local = time.FixedZone("IST", 5*3600+1800)
+	t.Cleanup(func() { time.Local = local })
+
+	db := openTestDB(t)
+	worker := NewEventRetentionWorker(&config.Config{TrendingTimeWindow: 1, EventRetentionDays: 1})
+
+	now := time.Now()
+	events := []models.UserEvent{
+		{ArticleID: "expired", UserID: "u1", EventType: models.EventTypeView, Timestamp: now.Add(-50 * time.Hour)},
+		{ArticleID: "rolled", UserID: "u1", EventType: models.EventTypeView, Timestamp: now.Add(-3 * time.Hour)},
+		{ArticleID: "recent", UserID: "u1", EventType: models.EventTypeView, Timestamp: now.Add(-10 * time.Minute)},
+	}
+	if err := db.Create(&events).Error; err != nil {
+		t.Fatalf("failed to create events: %v", err)
+	}
+
+	// A second pass must not count anything twice
+	for range 2 {
+		if err := worker.Run(); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	}
+
+	var rollups []models.EventRollup
+	if err := db.Order("hour").Find(&rollups).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(rollups) != 2 || rollups[0].ArticleID != "expired" || rollups[1].ArticleID != "rolled" {
+		t.Fatalf("rollups = %+v, expected the expired and rolled events, once each", rollups)
+	}
+	for i, rollup := range rollups {
+		if want := events[i].Timestamp.UTC().Truncate(time.Hour); !rollup.Hour.Equal(want) || rollup.Count != 1 {
+			t.Errorf("rollup of %s = %d at %v, expected 1 at %v", rollup.ArticleID, rollup.Count, rollup.Hour, want)
+		}
+	}
+
+	var kept []string
+	if err := db.Model(&models.UserEvent{}).Order("timestamp").Pluck("article_id", &kept).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(kept) != 2 || kept[0] != "rolled" || kept[1] != "recent" {
+		t.Errorf("raw events kept = %v, expected the rolled and recent ones", kept)
+	}
+}
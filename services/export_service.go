@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"news-backend/config"
+	"news-backend/database"
+	"news-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// Article export formats
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+)
+
+// exportChunkSize is how many articles are loaded and written at a time
+const exportChunkSize = 500
+
+// IsExportFormat reports whether format is a supported export format
+func IsExportFormat(format string) bool {
+	return format == ExportFormatCSV || format == ExportFormatNDJSON
+}
+
+// ExportedArticle is a row of the article export: the stored article with
+// its engagement over all time
+type ExportedArticle struct {
+	ID               string    `json:"id"`
+	Title            string    `json:"title"`
+	Description      string    `json:"description"`
+	URL              string    `json:"url"`
+	PublicationDate  time.Time `json:"publication_date"`
+	SourceName       string    `json:"source_name"`
+	Category         string    `json:"category"`
+	RelevanceScore   float64   `json:"relevance_score"`
+	CurrentRelevance float64   `json:"current_relevance"`
+	Latitude         float64   `json:"latitude"`
+	Longitude        float64   `json:"longitude"`
+	LLMSummary       string    `json:"llm_summary"`
+	StoryID          string    `json:"story_id"`
+	Sentiment        string    `json:"sentiment"`
+	Tone             string    `json:"tone"`
+	Publisher        string    `json:"publisher"`
+	License          string    `json:"license"`
+	IngestSource     string    `json:"ingest_source"`
+	EngagementCounts
+	UniqueUsers int64 `json:"unique_users"` // over the raw events still kept
+}
+
+// exportColumns are the CSV columns, in the order of the NDJSON fields
+var exportColumns = []string{
+	"id", "title", "description", "url", "publication_date", "source_name", "category",
+	"relevance_score", "current_relevance", "latitude", "longitude", "llm_summary",
+	"story_id", "sentiment", "tone", "publisher", "license", "ingest_source",
+	"views", "clicks", "shares", "unique_users",
+}
+
+func (a *ExportedArticle) csvRecord() []string {
+	float := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	return []string{
+		a.ID, a.Title, a.Description, a.URL, a.PublicationDate.UTC().Format(time.RFC3339), a.SourceName, a.Category,
+		float(a.RelevanceScore), float(a.CurrentRelevance), float(a.Latitude), float(a.Longitude), a.LLMSummary,
+		a.StoryID, a.Sentiment, a.Tone, a.Publisher, a.License, a.IngestSource,
+		strconv.FormatInt(a.Views, 10), strconv.FormatInt(a.Clicks, 10), strconv.FormatInt(a.Shares, 10),
+		strconv.FormatInt(a.UniqueUsers, 10),
+	}
+}
+
+// ExportService writes the article table out for offline analysis
+type ExportService struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+// NewExportService creates a new export service instance
+func NewExportService(cfg *config.Config) *ExportService {
+	return &ExportService{
+		db:  database.GetDB(),
+		cfg: cfg,
+	}
+}
+
+// ExportArticles writes the articles published within dates to w in
+// format, ordered by ID. Articles are read and written chunk by chunk,
+// each in its own short query so writers aren't held up for the whole
+// export, and w is flushed after each chunk when it can be. It returns the
+// number of articles written.
+func (s *ExportService) ExportArticles(ctx context.Context, w io.Writer, format string, dates DateRange) (int64, error) {
+	if !IsExportFormat(format) {
+		return 0, fmt.Errorf("unknown export format %q", format)
+	}
+	watermark, err := rollupWatermark(s.db)
+	if err != nil {
+		return 0, err
+	}
+
+	var writeRow func(*ExportedArticle) error
+	var flush func() error
+	if format == ExportFormatCSV {
+		cw := csv.NewWriter(w)
+		if err := cw.Write(exportColumns); err != nil {
+			return 0, fmt.Errorf("failed to write export: %w", err)
+		}
+		writeRow = func(a *ExportedArticle) error { return cw.Write(a.csvRecord()) }
+		flush = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+	} else {
+		enc := json.NewEncoder(w)
+		writeRow = func(a *ExportedArticle) error { return enc.Encode(a) }
+		flush = func() error { return nil }
+	}
+
+	var written int64
+	lastID := ""
+	for {
+		var articles []models.Article
+		query := dates.apply(s.db.WithContext(ctx).Model(&models.Article{})).Where("id > ?", lastID)
+		if err := query.Order("id").Limit(exportChunkSize).Find(&articles).Error; err != nil {
+			return written, fmt.Errorf("failed to load articles: %w", err)
+		}
+		if len(articles) == 0 {
+			return written, nil
+		}
+		rows, err := s.exportRows(articles, watermark)
+		if err != nil {
+			return written, err
+		}
+		for i := range rows {
+			if err := writeRow(&rows[i]); err != nil {
+				return written, fmt.Errorf("failed to write export: %w", err)
+			}
+			written++
+		}
+		if err := flush(); err != nil {
+			return written, fmt.Errorf("failed to write export: %w", err)
+		}
+		if f, ok := w.(interface{ Flush() }); ok {
+			f.Flush()
+		}
+		lastID = articles[len(articles)-1].ID
+	}
+}
+
+// exportRows attaches engagement counts to a chunk of articles, from the
+// rollups before watermark and the raw events after it
+func (s *ExportService) exportRows(articles []models.Article, watermark time.Time) ([]ExportedArticle, error) {
+	ids := articleIDs(articles)
+	var typeCounts []struct {
+		ArticleID string
+		EventType string
+		Count     int64
+	}
+	err := s.db.Raw(`SELECT article_id, event_type, SUM(n) AS count FROM (
+			SELECT article_id, event_type, count AS n FROM event_rollups WHERE article_id IN ?
+			UNION ALL
+			SELECT article_id, event_type, 1 AS n FROM user_events WHERE article_id IN ? AND timestamp >= ?
+		) GROUP BY article_id, event_type`, ids, ids, watermark).Scan(&typeCounts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count events: %w", err)
+	}
+	var userCounts []struct {
+		ArticleID string
+		Users     int64
+	}
+	err = s.db.Model(&models.UserEvent{}).
+		Select("article_id, COUNT(DISTINCT user_id) AS users").
+		Where("article_id IN ?", ids).
+		Group("article_id").
+		Scan(&userCounts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count article users: %w", err)
+	}
+
+	rows := make([]ExportedArticle, len(articles))
+	byID := make(map[string]*ExportedArticle, len(articles))
+	for i := range articles {
+		a := &articles[i]
+		rows[i] = ExportedArticle{
+			ID:               a.ID,
+			Title:            a.Title,
+			Description:      a.Description,
+			URL:              a.URL,
+			PublicationDate:  a.PublicationDate,
+			SourceName:       a.SourceName,
+			Category:         a.Category,
+			RelevanceScore:   a.RelevanceScore,
+			CurrentRelevance: a.CurrentRelevance,
+			Latitude:         a.Latitude,
+			Longitude:        a.Longitude,
+			LLMSummary:       a.LLMSummary,
+			StoryID:          a.StoryID,
+			Sentiment:        a.Sentiment,
+			Tone:             a.Tone,
+			Publisher:        a.Publisher,
+			License:          a.License,
+			IngestSource:     a.IngestSource,
+		}
+		byID[a.ID] = &rows[i]
+	}
+	for _, tc := range typeCounts {
+		row := byID[tc.ArticleID]
+		switch tc.EventType {
+		case models.EventTypeView:
+			row.Views += tc.Count
+		case models.EventTypeClick:
+			row.Clicks += tc.Count
+		case models.EventTypeShare:
+			row.Shares += tc.Count
+		}
+	}
+	for _, uc := range userCounts {
+		byID[uc.ArticleID].UniqueUsers = uc.Users
+	}
+	return rows, nil
+}
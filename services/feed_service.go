@@ -0,0 +1,374 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"news-backend/config"
+	"news-backend/database"
+	"news-backend/models"
+	"news-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidTimezone is returned for a tz that isn't an IANA time zone name
+var ErrInvalidTimezone = errors.New("invalid time zone")
+
+// feedDigestKind marks a FEED_COMPOSITION slot that leads with the daily digest
+const feedDigestKind = "digest"
+
+// FeedSlot is a part of the user's local day and the mix of article kinds
+// feeds composed during it get
+type FeedSlot struct {
+	Name   string
+	Start  int                // hour of day, inclusive
+	End    int                // hour of day, exclusive; at or before Start when the slot wraps midnight
+	Kinds  []string           // in the order given, which breaks ties between equal shares
+	Shares map[string]float64 // Kind -> share of the feed, summing to 1 (to 4 decimals)
+	Digest bool               // whether the feed leads with the daily digest
+}
+
+// contains reports whether the slot covers an hour of the day
+func (s FeedSlot) contains(hour int) bool {
+	if s.Start < s.End {
+		return hour >= s.Start && hour < s.End
+	}
+	return hour >= s.Start || hour < s.End
+}
+
+// ParseFeedComposition parses FEED_COMPOSITION: semicolon-separated slots
+// written name@start-end:kind=share,..., hours in the user's local time.
+// Kinds are briefing, long_read and trending; shares are relative and
+// needn't sum to 1. A bare "digest" in the list leads the slot's feeds with
+// the daily digest. Every hour of the day must fall in exactly one slot.
+func ParseFeedComposition(spec string) ([]FeedSlot, error) {
+	var slots []FeedSlot
+	var covered [24]string
+	for _, part := range strings.Split(spec, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		slot, err := parseFeedSlot(part)
+		if err != nil {
+			return nil, err
+		}
+		for hour := range covered {
+			if !slot.contains(hour) {
+				continue
+			}
+			if covered[hour] != "" {
+				return nil, fmt.Errorf("slots %q and %q both cover hour %d", covered[hour], slot.Name, hour)
+			}
+			covered[hour] = slot.Name
+		}
+		slots = append(slots, slot)
+	}
+	for hour, name := range covered {
+		if name == "" {
+			return nil, fmt.Errorf("no slot covers hour %d", hour)
+		}
+	}
+	return slots, nil
+}
+
+// parseFeedSlot parses one name@start-end:kind=share,... slot
+func parseFeedSlot(spec string) (FeedSlot, error) {
+	head, mix, ok := strings.Cut(spec, ":")
+	name, hours, ok2 := strings.Cut(head, "@")
+	startText, endText, ok3 := strings.Cut(hours, "-")
+	name = strings.TrimSpace(name)
+	if !ok || !ok2 || !ok3 || name == "" {
+		return FeedSlot{}, fmt.Errorf("slot %q: expected name@start-end:kind=share,...", spec)
+	}
+	start, err := strconv.Atoi(strings.TrimSpace(startText))
+	if err != nil || start < 0 || start > 23 {
+		return FeedSlot{}, fmt.Errorf("slot %q: start must be an hour from 0 to 23", name)
+	}
+	end, err := strconv.Atoi(strings.TrimSpace(endText))
+	if err != nil || end < 0 || end > 24 {
+		return FeedSlot{}, fmt.Errorf("slot %q: end must be an hour from 0 to 24", name)
+	}
+
+	slot := FeedSlot{Name: name, Start: start, End: end % 24, Shares: make(map[string]float64)}
+	total := 0.0
+	for _, item := range strings.Split(mix, ",") {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if item == "" {
+			continue
+		}
+		if item == feedDigestKind {
+			slot.Digest = true
+			continue
+		}
+		kind, shareText, _ := strings.Cut(item, "=")
+		kind = strings.TrimSpace(kind)
+		switch kind {
+		case models.FeedKindBriefing, models.FeedKindLongRead, models.FeedKindTrending:
+		default:
+			return FeedSlot{}, fmt.Errorf("slot %q: unknown kind %q (expected briefing, long_read, trending or digest)", name, kind)
+		}
+		if _, dup := slot.Shares[kind]; dup {
+			return FeedSlot{}, fmt.Errorf("slot %q: %s given twice", name, kind)
+		}
+		share, err := strconv.ParseFloat(strings.TrimSpace(shareText), 64)
+		if err != nil || share <= 0 || math.IsInf(share, 0) {
+			return FeedSlot{}, fmt.Errorf("slot %q: %s share must be a positive number", name, kind)
+		}
+		slot.Kinds = append(slot.Kinds, kind)
+		slot.Shares[kind] = share
+		total += share
+	}
+	if len(slot.Kinds) == 0 {
+		return FeedSlot{}, fmt.Errorf("slot %q: no article kinds", name)
+	}
+	for kind := range slot.Shares {
+		slot.Shares[kind] = math.Round(slot.Shares[kind]/total*1e4) / 1e4
+	}
+	return slot, nil
+}
+
+// FeedTimezone returns the time zone a feed is composed in: the IANA zone
+// tz names or, without one, a whole-hour offset estimated from lon
+func FeedTimezone(tz string, lon float64) (*time.Location, error) {
+	if tz == "" {
+		offset := int(math.Round(lon / 15))
+		if offset == 0 {
+			return time.UTC, nil
+		}
+		return time.FixedZone(fmt.Sprintf("UTC%+d", offset), offset*3600), nil
+	}
+	// "Local" would be the server's zone, which says nothing about the user
+	if tz == "Local" {
+		return nil, fmt.Errorf("%w %q", ErrInvalidTimezone, tz)
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q", ErrInvalidTimezone, tz)
+	}
+	return loc, nil
+}
+
+// Feed is a mix of article kinds chosen by the user's local time of day
+type Feed struct {
+	Articles  []models.Article
+	Kinds     []string // Articles[i]'s kind
+	Digest    *Digest  // nil unless the slot leads with one and it could be built
+	Slot      FeedSlot
+	LocalTime time.Time
+	Mix       map[string]int // Kind -> articles included
+	Summaries string         // summary enrichment status
+	// TrendingScores holds the trending scores of the area's trending
+	// articles, whatever kind they were included as
+	TrendingScores map[string]float64
+}
+
+// FeedService composes feeds whose mix of briefings, long reads and
+// trending articles follows the FEED_COMPOSITION slot of the user's local
+// time
+type FeedService struct {
+	db              *gorm.DB
+	cfg             *config.Config
+	slots           []FeedSlot // FEED_COMPOSITION, validated at startup
+	llmService      *LLMService
+	trendingService *TrendingService
+	digestService   *DigestService
+}
+
+// NewFeedService creates a new feed service instance
+func NewFeedService(cfg *config.Config, llmService *LLMService, trendingService *TrendingService, digestService *DigestService) *FeedService {
+	slots, _ := ParseFeedComposition(cfg.FeedComposition)
+	return &FeedService{
+		db:              database.GetDB(),
+		cfg:             cfg,
+		slots:           slots,
+		llmService:      llmService,
+		trendingService: trendingService,
+		digestService:   digestService,
+	}
+}
+
+// SlotAt returns the slot covering a local time
+func (s *FeedService) SlotAt(local time.Time) FeedSlot {
+	for _, slot := range s.slots {
+		if slot.contains(local.Hour()) {
+			return slot
+		}
+	}
+	// Unreachable with a validated composition
+	return s.slots[0]
+}
+
+// Compose builds a feed of up to limit articles (FEED_SIZE for 0) around a
+// location for the slot covering localNow. Kinds are interleaved in
+// proportion to their shares, one article per story; when a kind runs out
+// the others fill its places. Articles muted for ctx are left out.
+func (s *FeedService) Compose(ctx context.Context, lat, lon, radius float64, localNow time.Time, limit int, includeSummaries bool) (*Feed, error) {
+	if radius == 0 {
+		radius = s.cfg.TrendingRadius
+	}
+	if limit == 0 {
+		limit = s.cfg.FeedSize
+	}
+	limit = TierLimit(ctx, limit)
+	slot := s.SlotAt(localNow)
+
+	pools, trendingScores, err := s.candidates(lat, lon, radius)
+	if err != nil {
+		return nil, err
+	}
+	for kind, pool := range pools {
+		pools[kind] = Mutes(ctx).Filter(pool)
+	}
+	articles, kinds := composeFeed(slot, pools, limit)
+	feed := &Feed{
+		Articles:       articles,
+		Kinds:          kinds,
+		Slot:           slot,
+		LocalTime:      localNow,
+		Mix:            make(map[string]int, len(slot.Kinds)),
+		TrendingScores: trendingScores,
+	}
+	for _, kind := range slot.Kinds {
+		feed.Mix[kind] = 0
+	}
+	for _, kind := range kinds {
+		feed.Mix[kind]++
+	}
+
+	if slot.Digest {
+		digest, _, err := s.digestService.GetDigest(ctx, lat, lon, nil)
+		if err != nil {
+			log.Printf("Serving feed without digest: %v", err)
+		} else {
+			feed.Digest = digest
+		}
+	}
+
+	switch {
+	case !includeSummaries:
+		feed.Summaries = SummariesOmitted
+	case !s.llmService.Available():
+		feed.Summaries = SummariesLLMUnavailable
+	default:
+		s.llmService.GenerateSummariesBatch(ctx, feed.Articles)
+		feed.Summaries = SummariesIncluded
+	}
+	return feed, nil
+}
+
+// candidates returns each kind's articles in the order the feed takes them.
+// Briefings (freshest first) and long reads (most relevant first) come from
+// the FEED_WINDOW_HOURS up to the newest article within radius; analytical
+// and opinion pieces are long reads, everything else, untagged articles
+// included, a briefing. Trending articles are the area's trending list,
+// whose scores are returned by article ID.
+func (s *FeedService) candidates(lat, lon, radius float64) (map[string][]models.Article, map[string]float64, error) {
+	var articles []models.Article
+	if err := s.db.Where("publication_date <= ?", time.Now().UTC()).Find(&articles).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to load feed candidates: %w", err)
+	}
+	articles = utils.FilterByDistance(articles, lat, lon, radius)
+
+	var windowEnd time.Time
+	for i := range articles {
+		if articles[i].PublicationDate.After(windowEnd) {
+			windowEnd = articles[i].PublicationDate
+		}
+	}
+	windowStart := windowEnd.Add(-time.Duration(s.cfg.FeedWindowHours) * time.Hour)
+
+	pools := make(map[string][]models.Article, 3)
+	for _, article := range articles {
+		if article.PublicationDate.Before(windowStart) {
+			continue
+		}
+		switch article.Tone {
+		case utils.ToneAnalytical, utils.ToneOpinion:
+			pools[models.FeedKindLongRead] = append(pools[models.FeedKindLongRead], article)
+		default:
+			pools[models.FeedKindBriefing] = append(pools[models.FeedKindBriefing], article)
+		}
+	}
+	briefings := pools[models.FeedKindBriefing]
+	sort.Slice(briefings, func(i, j int) bool {
+		if !briefings[i].PublicationDate.Equal(briefings[j].PublicationDate) {
+			return briefings[i].PublicationDate.After(briefings[j].PublicationDate)
+		}
+		return utils.LessOnTie(briefings[i], briefings[j])
+	})
+	longReads := pools[models.FeedKindLongRead]
+	sort.Slice(longReads, func(i, j int) bool {
+		if longReads[i].CurrentRelevance != longReads[j].CurrentRelevance {
+			return longReads[i].CurrentRelevance > longReads[j].CurrentRelevance
+		}
+		return utils.LessOnTie(longReads[i], longReads[j])
+	})
+
+	trending, _, err := s.trendingService.GetTrendingNews(lat, lon, radius, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch trending: %w", err)
+	}
+	trendingScores := make(map[string]float64, len(trending))
+	for i := range trending {
+		pools[models.FeedKindTrending] = append(pools[models.FeedKindTrending], trending[i].Article)
+		trendingScores[trending[i].ID] = trending[i].TrendingScore
+	}
+	return pools, trendingScores, nil
+}
+
+// composeFeed takes up to limit articles from the slot's pools with smooth
+// weighted round-robin, so kinds are spread through the feed rather than
+// grouped. Articles already taken, or from a story already taken, are
+// skipped; an exhausted kind drops out and the others share its places.
+func composeFeed(slot FeedSlot, pools map[string][]models.Article, limit int) ([]models.Article, []string) {
+	articles := make([]models.Article, 0, limit)
+	kinds := make([]string, 0, limit)
+	seen := make(map[string]bool)
+	next := make(map[string]int, len(slot.Kinds))
+	current := make(map[string]float64, len(slot.Kinds))
+	active := append([]string(nil), slot.Kinds...)
+
+	for len(articles) < limit && len(active) > 0 {
+		pick := ""
+		total := 0.0
+		for _, kind := range active {
+			current[kind] += slot.Shares[kind]
+			total += slot.Shares[kind]
+			if pick == "" || current[kind] > current[pick] {
+				pick = kind
+			}
+		}
+		current[pick] -= total
+
+		pool := pools[pick]
+		for next[pick] < len(pool) && (seen[pool[next[pick]].ID] || seen[storyKey(&pool[next[pick]])]) {
+			next[pick]++
+		}
+		if next[pick] == len(pool) {
+			for i, kind := range active {
+				if kind == pick {
+					active = append(active[:i], active[i+1:]...)
+					break
+				}
+			}
+			continue
+		}
+
+		article := pool[next[pick]]
+		next[pick]++
+		seen[article.ID] = true
+		seen[storyKey(&article)] = true
+		articles = append(articles, article)
+		kinds = append(kinds, pick)
+	}
+	return articles, kinds
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"news-backend/config"
+	"news-backend/database"
+	"news-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidFeedback is returned for unknown feedback types or review actions
+var ErrInvalidFeedback = errors.New("invalid feedback")
+
+// Review actions for queued feedback
+const (
+	FeedbackActionResolve          = "resolve"
+	FeedbackActionDismiss          = "dismiss"
+	FeedbackActionBlocklistSummary = "blocklist_summary"
+)
+
+// feedbackAutoBlocklist is the resolution recorded when the threshold drops a summary
+const feedbackAutoBlocklist = "auto_blocklist_summary"
+
+// FeedbackService stores user feedback and runs the admin review queue
+type FeedbackService struct {
+	db                *gorm.DB
+	cfg               *config.Config
+	llmService        *LLMService
+	cdnService        *CDNService
+	summaryExperiment *SummaryExperimentService
+}
+
+// NewFeedbackService creates a new feedback service instance
+func NewFeedbackService(cfg *config.Config, llmService *LLMService, cdnService *CDNService, summaryExperiment *SummaryExperimentService) *FeedbackService {
+	return &FeedbackService{
+		db:                database.GetDB(),
+		cfg:               cfg,
+		llmService:        llmService,
+		cdnService:        cdnService,
+		summaryExperiment: summaryExperiment,
+	}
+}
+
+// Submit validates and stores feedback. Once enough open bad_summary reports
+// accumulate for an article (FEEDBACK_BLOCKLIST_THRESHOLD), its summary is dropped.
+func (s *FeedbackService) Submit(feedback *models.Feedback) error {
+	if !models.IsValidFeedbackType(feedback.Type) {
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidFeedback, feedback.Type)
+	}
+
+	var article models.Article
+	if err := s.db.Select("id").Where("id = ?", feedback.ArticleID).First(&article).Error; err != nil {
+		return err
+	}
+
+	feedback.ID = 0
+	feedback.Status = models.FeedbackStatusOpen
+	feedback.Resolution = ""
+	feedback.ResolvedAt = nil
+	if err := s.db.Create(feedback).Error; err != nil {
+		return fmt.Errorf("failed to store feedback: %w", err)
+	}
+
+	if feedback.Type == models.FeedbackBadSummary {
+		s.summaryExperiment.RecordBadSummary(feedback.UserID)
+	}
+	if feedback.Type == models.FeedbackBadSummary && s.cfg.FeedbackBlocklistThreshold > 0 {
+		if err := s.checkBlocklistThreshold(feedback.ArticleID); err != nil {
+			log.Printf("Failed to apply summary blocklist for article %s: %v", feedback.ArticleID, err)
+		}
+	}
+	return nil
+}
+
+// checkBlocklistThreshold drops a summary reported bad often enough and
+// resolves the reports that triggered it
+func (s *FeedbackService) checkBlocklistThreshold(articleID string) error {
+	open := s.db.Model(&models.Feedback{}).
+		Where("article_id = ? AND type = ? AND status = ?",
+			articleID, models.FeedbackBadSummary, models.FeedbackStatusOpen)
+
+	var count int64
+	if err := open.Count(&count).Error; err != nil {
+		return err
+	}
+	if count < int64(s.cfg.FeedbackBlocklistThreshold) {
+		return nil
+	}
+
+	if err := s.blocklistSummary(articleID); err != nil {
+		return err
+	}
+	log.Printf("Dropped summary for article %s after %d bad_summary reports", articleID, count)
+
+	return s.db.Model(&models.Feedback{}).
+		Where("article_id = ? AND type = ? AND status = ?",
+			articleID, models.FeedbackBadSummary, models.FeedbackStatusOpen).
+		Updates(map[string]interface{}{
+			"status":      models.FeedbackStatusResolved,
+			"resolution":  feedbackAutoBlocklist,
+			"resolved_at": time.Now(),
+		}).Error
+}
+
+// blocklistSummary discards an article's stored and cached summary and
+// flags it, so the summary worker and request paths don't write a new one
+func (s *FeedbackService) blocklistSummary(articleID string) error {
+	err := s.db.Model(&models.Article{}).
+		Where("id = ?", articleID).
+		Updates(map[string]interface{}{"llm_summary": "", "summary_blocked": true}).Error
+	if err != nil {
+		return fmt.Errorf("failed to clear summary: %w", err)
+	}
+	s.llmService.InvalidateSummary(articleID)
+	s.cdnService.Purge(ArticleSurrogateKey(articleID))
+	return nil
+}
+
+// List returns feedback newest first, optionally filtered by status and type,
+// along with the total number of matching entries
+func (s *FeedbackService) List(status, feedbackType string, limit int) ([]models.Feedback, int64, error) {
+	query := s.db.Model(&models.Feedback{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if feedbackType != "" {
+		query = query.Where("type = ?", feedbackType)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count feedback: %w", err)
+	}
+
+	var feedback []models.Feedback
+	if err := query.Order("created_at DESC").Limit(limit).Find(&feedback).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to load feedback: %w", err)
+	}
+	return feedback, total, nil
+}
+
+// Resolve closes a feedback entry with the given review action
+func (s *FeedbackService) Resolve(id uint, action string) (*models.Feedback, error) {
+	switch action {
+	case FeedbackActionResolve, FeedbackActionDismiss, FeedbackActionBlocklistSummary:
+	default:
+		return nil, fmt.Errorf("%w: unknown action %q", ErrInvalidFeedback, action)
+	}
+
+	var feedback models.Feedback
+	if err := s.db.First(&feedback, id).Error; err != nil {
+		return nil, err
+	}
+
+	if action == FeedbackActionBlocklistSummary {
+		if err := s.blocklistSummary(feedback.ArticleID); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	feedback.Status = models.FeedbackStatusResolved
+	feedback.Resolution = action
+	feedback.ResolvedAt = &now
+	if err := s.db.Save(&feedback).Error; err != nil {
+		return nil, fmt.Errorf("failed to update feedback: %w", err)
+	}
+	return &feedback, nil
+}
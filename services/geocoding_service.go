@@ -0,0 +1,183 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"news-backend/cache"
+	"news-backend/config"
+	"news-backend/utils"
+)
+
+// geocodeCachePrefix namespaces reverse geocoding results by grid cell
+const geocodeCachePrefix = "geocode:"
+
+// GeocodingService turns coordinates into place names such as
+// "San Francisco, CA" through a Nominatim-compatible reverse geocoding
+// endpoint. Names are cached per trending grid cell, so a cell is looked up
+// once per GEOCODE_CACHE_TTL.
+type GeocodingService struct {
+	cfg    *config.Config
+	client *http.Client
+	cache  cache.Cache
+
+	mu     sync.Mutex
+	status GeocoderStatus
+}
+
+// GeocoderStatus reports the outcome of recent reverse geocoding lookups
+type GeocoderStatus struct {
+	Enabled     bool      `json:"enabled"`
+	LastSuccess time.Time `json:"last_success,omitempty"`
+	LastFailure time.Time `json:"last_failure,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+// NewGeocodingService creates a new reverse geocoding service instance
+func NewGeocodingService(cfg *config.Config, store cache.Cache) *GeocodingService {
+	return &GeocodingService{
+		cfg:    cfg,
+		client: &http.Client{Timeout: time.Duration(cfg.GeocoderTimeoutMs) * time.Millisecond},
+		cache:  store,
+	}
+}
+
+// LocationName returns a human-readable name for a coordinate, or the
+// coordinate itself ("37.7749,-122.4194") when no geocoder is configured or
+// the lookup fails
+func (s *GeocodingService) LocationName(ctx context.Context, lat, lon float64) string {
+	fallback := fmt.Sprintf("%.4f,%.4f", lat, lon)
+	if s.cfg.GeocoderURL == "" {
+		return fallback
+	}
+
+	cell := utils.ToGridCell(lat, lon, cacheGridPrecision)
+	key := fmt.Sprintf("%s%d_%d", geocodeCachePrefix, cell.LatCell, cell.LonCell)
+	if name, ok, err := s.cache.Get(ctx, key); err != nil {
+		log.Printf("Failed to read geocode cache: %v", err)
+	} else if ok {
+		return string(name)
+	}
+
+	name, err := s.reverse(ctx, lat, lon)
+	s.recordLookup(err)
+	if err != nil {
+		log.Printf("Reverse geocoding (%.4f, %.4f) failed: %v", lat, lon, err)
+		return fallback
+	}
+	if name == "" {
+		// Open sea and the like; don't ask again for this cell
+		name = fallback
+	}
+	ttl := time.Duration(s.cfg.GeocodeCacheTTL) * time.Second
+	if err := s.cache.Set(ctx, key, []byte(name), ttl); err != nil {
+		log.Printf("Failed to write geocode cache: %v", err)
+	}
+	return name
+}
+
+// Status returns the outcome of the latest lookups
+func (s *GeocodingService) Status() GeocoderStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := s.status
+	status.Enabled = s.cfg.GeocoderURL != ""
+	return status
+}
+
+// recordLookup notes a lookup's outcome for Status
+func (s *GeocodingService) recordLookup(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.status.LastFailure = time.Now()
+		s.status.LastError = err.Error()
+	} else {
+		s.status.LastSuccess = time.Now()
+	}
+}
+
+// nominatimResult is the part of a Nominatim reverse lookup we use
+type nominatimResult struct {
+	Error   string            `json:"error"`
+	Address map[string]string `json:"address"`
+}
+
+// reverse asks the geocoder for the place at a coordinate
+func (s *GeocodingService) reverse(ctx context.Context, lat, lon float64) (string, error) {
+	params := url.Values{
+		"format": {"jsonv2"},
+		"lat":    {strconv.FormatFloat(lat, 'f', 6, 64)},
+		"lon":    {strconv.FormatFloat(lon, 'f', 6, 64)},
+		"zoom":   {"10"}, // city level
+	}
+	endpoint := s.cfg.GeocoderURL
+	if strings.Contains(endpoint, "?") {
+		endpoint += "&" + params.Encode()
+	} else {
+		endpoint += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build geocoding request: %w", err)
+	}
+	// Nominatim's usage policy requires an identifying User-Agent
+	req.Header.Set("User-Agent", "news-backend")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call geocoder: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("geocoder returned status %d", resp.StatusCode)
+	}
+
+	var result nominatimResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode geocoder response: %w", err)
+	}
+	if result.Error != "" {
+		// Nominatim answers 200 with an error for points it can't place
+		return "", nil
+	}
+	return formatPlace(result.Address), nil
+}
+
+// formatPlace names an address as "City, Region": US states by their postal
+// code ("San Francisco, CA"), other regions by name ("Hyderabad, Telangana"),
+// and the country when there is no region
+func formatPlace(address map[string]string) string {
+	place := cmp.Or(address["city"], address["town"], address["village"],
+		address["municipality"], address["suburb"], address["county"])
+
+	region := address["state"]
+	if address["country_code"] == "us" {
+		if code, ok := strings.CutPrefix(address["ISO3166-2-lvl4"], "US-"); ok {
+			region = code
+		}
+	}
+	if region == "" {
+		region = address["country"]
+	}
+
+	switch {
+	case place == "":
+		return region
+	case region == "" || region == place:
+		return place
+	default:
+		return place + ", " + region
+	}
+}
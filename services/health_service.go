@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"news-backend/cache"
+	"news-backend/config"
+	"news-backend/database"
+	"news-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// Service name and version reported by health checks
+const (
+	ServiceName    = "news-backend"
+	ServiceVersion = "1.0.0"
+)
+
+// Overall health. Only a failed required dependency makes the service
+// unhealthy, and so not ready; the others degrade features.
+const (
+	HealthHealthy   = "healthy"
+	HealthDegraded  = "degraded"
+	HealthUnhealthy = "unhealthy"
+)
+
+// Dependency check results
+const (
+	CheckOK       = "ok"
+	CheckFailed   = "failed"
+	CheckDisabled = "disabled"
+	CheckPending  = "pending" // not checked yet
+)
+
+// healthCheckTimeout bounds the database and cache checks, well inside a
+// probe's timeout
+const healthCheckTimeout = 800 * time.Millisecond
+
+// DependencyCheck is the state of one dependency
+type DependencyCheck struct {
+	Name      string     `json:"name"`
+	Status    string     `json:"status"`
+	Required  bool       `json:"required"` // the service isn't ready while it fails
+	Detail    string     `json:"detail"`
+	LatencyMs int64      `json:"latency_ms"`
+	CheckedAt *time.Time `json:"checked_at,omitempty"` // unset until checked
+}
+
+// HealthReport is the readiness of the service and its dependencies
+type HealthReport struct {
+	Status   string            `json:"status"`
+	Ready    bool              `json:"ready"`
+	Service  string            `json:"service"`
+	Version  string            `json:"version"`
+	Articles int64             `json:"articles"`
+	Checks   []DependencyCheck `json:"checks"`
+}
+
+// HealthService checks the dependencies a readiness probe cares about:
+// the database (required), the cache backend and the LLM providers
+type HealthService struct {
+	db         *gorm.DB
+	cfg        *config.Config
+	llmService *LLMService
+	cache      cache.Cache
+
+	// The LLM check makes network calls, so it runs in the background at
+	// most every HEALTH_LLM_CHECK_INTERVAL and probes read the last result
+	mu          sync.Mutex
+	llmCheck    DependencyCheck
+	llmChecking bool
+}
+
+// NewHealthService creates a new health check service instance
+func NewHealthService(cfg *config.Config, llmService *LLMService, store cache.Cache) *HealthService {
+	return &HealthService{
+		db:         database.GetDB(),
+		cfg:        cfg,
+		llmService: llmService,
+		cache:      store,
+		llmCheck:   DependencyCheck{Name: "llm", Status: CheckPending, Detail: "not checked yet"},
+	}
+}
+
+// Readiness checks every dependency
+func (s *HealthService) Readiness(ctx context.Context) HealthReport {
+	report := HealthReport{Status: HealthHealthy, Service: ServiceName, Version: ServiceVersion}
+	var dbCheck DependencyCheck
+	dbCheck, report.Articles = s.databaseCheck(ctx)
+	report.Checks = []DependencyCheck{dbCheck, s.cacheCheck(ctx), s.llmStatus()}
+
+	for _, check := range report.Checks {
+		switch {
+		case check.Status != CheckFailed:
+		case check.Required:
+			report.Status = HealthUnhealthy
+		case report.Status == HealthHealthy:
+			report.Status = HealthDegraded
+		}
+	}
+	report.Ready = report.Status != HealthUnhealthy
+	return report
+}
+
+// databaseCheck pings the database and counts the articles
+func (s *HealthService) databaseCheck(ctx context.Context) (DependencyCheck, int64) {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	check := DependencyCheck{Name: "database", Required: true}
+	started := time.Now()
+	var articles int64
+	err := s.pingDatabase(ctx)
+	if err == nil {
+		err = s.db.WithContext(ctx).Model(&models.Article{}).Count(&articles).Error
+	}
+	check.LatencyMs, check.CheckedAt = time.Since(started).Milliseconds(), checkedNow()
+	if err != nil {
+		check.Status, check.Detail = CheckFailed, err.Error()
+		return check, 0
+	}
+	check.Status, check.Detail = CheckOK, fmt.Sprintf("%d articles", articles)
+	return check, articles
+}
+
+func (s *HealthService) pingDatabase(ctx context.Context) error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database handle: %w", err)
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// cacheCheck reads a key to check that the cache backend answers
+func (s *HealthService) cacheCheck(ctx context.Context) DependencyCheck {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	check := DependencyCheck{Name: "cache"}
+	started := time.Now()
+	_, _, err := s.cache.Get(ctx, cacheProbeKey)
+	check.LatencyMs, check.CheckedAt = time.Since(started).Milliseconds(), checkedNow()
+	if err != nil {
+		check.Status, check.Detail = CheckFailed, fmt.Sprintf("%s backend unreachable: %v", s.cfg.CacheBackend, err)
+	} else {
+		check.Status, check.Detail = CheckOK, s.cfg.CacheBackend+" backend reachable"
+	}
+	return check
+}
+
+// llmStatus returns the last LLM check, starting a new one in the
+// background once it is HEALTH_LLM_CHECK_INTERVAL old
+func (s *HealthService) llmStatus() DependencyCheck {
+	if s.cfg.LLMOffline {
+		return DependencyCheck{Name: "llm", Status: CheckDisabled, Detail: "LLM_PROVIDER is none"}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	interval := time.Duration(s.cfg.HealthLLMCheckInterval) * time.Second
+	if !s.llmChecking && (s.llmCheck.CheckedAt == nil || time.Since(*s.llmCheck.CheckedAt) >= interval) {
+		s.llmChecking = true
+		go s.checkLLM()
+	}
+	return s.llmCheck
+}
+
+// checkLLM contacts every provider; the LLM is reachable when one answers
+func (s *HealthService) checkLLM() {
+	started := time.Now()
+	results := s.llmService.CheckProviders(context.Background())
+	check := DependencyCheck{
+		Name:      "llm",
+		Status:    CheckFailed,
+		LatencyMs: time.Since(started).Milliseconds(),
+		CheckedAt: checkedNow(),
+	}
+	details := make([]string, len(results))
+	for i, result := range results {
+		if result.Reachable {
+			check.Status = CheckOK
+			details[i] = fmt.Sprintf("%s reachable (%dms)", result.Name, result.LatencyMs)
+		} else {
+			details[i] = result.Name + " unreachable: " + result.Error
+		}
+	}
+	check.Detail = strings.Join(details, "; ")
+
+	s.mu.Lock()
+	s.llmCheck, s.llmChecking = check, false
+	s.mu.Unlock()
+}
+
+// checkedNow is a CheckedAt of now
+func checkedNow() *time.Time {
+	now := time.Now().UTC()
+	return &now
+}
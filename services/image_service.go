@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"news-backend/config"
+	"news-backend/imageproxy"
+)
+
+// ImageService proxies article lead images so clients load thumbnails from
+// the API, at the size they render them, rather than from each publisher
+type ImageService struct {
+	cfg    *config.Config
+	client *http.Client
+}
+
+// NewImageService creates a new image proxy service
+func NewImageService(cfg *config.Config) *ImageService {
+	return &ImageService{
+		cfg:    cfg,
+		client: imageproxy.NewClient(time.Duration(cfg.ImageProxyTimeout) * time.Second),
+	}
+}
+
+// Image downloads the image at imageURL and, given a positive width, scales
+// it down to that width. Formats that can't be resized (WebP, AVIF) are
+// returned as they are.
+func (s *ImageService) Image(ctx context.Context, imageURL string, width int) (*imageproxy.Image, error) {
+	img, err := imageproxy.Fetch(ctx, s.client, imageURL, int64(s.cfg.ImageProxyMaxBytes))
+	if err != nil || width <= 0 {
+		return img, err
+	}
+	resized, err := imageproxy.Resize(img, width)
+	if errors.Is(err, imageproxy.ErrCannotResize) {
+		return img, nil
+	}
+	return resized, err
+}
@@ -0,0 +1,326 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"news-backend/config"
+	"news-backend/database"
+	"news-backend/ingest"
+	"news-backend/metrics"
+	"news-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// IngestService pulls articles from sources configured with a connector
+type IngestService struct {
+	db               *gorm.DB
+	cfg              *config.Config
+	llmService       *LLMService
+	embeddingService *EmbeddingService
+	webhookService   *WebhookService
+	cdnService       *CDNService
+	alertService     *KeywordAlertService
+	topicService     *TopicService
+	queue            *metrics.Queue // fetched articles awaiting storage, bounded by INGEST_QUEUE_MAX
+	summaryQueue     *metrics.Queue // scheduled runs wait while it is saturated
+
+	mu          sync.Mutex
+	lastRun     time.Time
+	lastResults []IngestResult
+}
+
+// IngestResult summarizes a single source run
+type IngestResult struct {
+	Source   string `json:"source"`
+	Fetched  int    `json:"fetched"`
+	Inserted int    `json:"inserted"`
+	Updated  int    `json:"updated"`
+	Merged   int    `json:"merged"` // same URL as a stored article
+	Skipped  int    `json:"skipped"`
+	Dropped  int    `json:"dropped"` // over INGEST_QUEUE_MAX
+	Error    string `json:"error,omitempty"`
+}
+
+// NewIngestService creates a new ingest service instance
+func NewIngestService(cfg *config.Config, llmService *LLMService, embeddingService *EmbeddingService, webhookService *WebhookService, cdnService *CDNService, alertService *KeywordAlertService, topicService *TopicService, queue, summaryQueue *metrics.Queue) *IngestService {
+	return &IngestService{
+		db:               database.GetDB(),
+		cfg:              cfg,
+		llmService:       llmService,
+		embeddingService: embeddingService,
+		webhookService:   webhookService,
+		cdnService:       cdnService,
+		alertService:     alertService,
+		topicService:     topicService,
+		queue:            queue,
+		summaryQueue:     summaryQueue,
+	}
+}
+
+// Start runs all connector-backed sources on every configured interval
+// until ctx is cancelled. A non-positive interval disables scheduled ingestion.
+// Runs are deferred to the next interval while the summary backlog is over
+// SUMMARY_QUEUE_MAX, so new articles don't pile up faster than they are
+// summarized.
+func (s *IngestService) Start(ctx context.Context) {
+	if s.cfg.IngestInterval <= 0 {
+		log.Println("Scheduled ingestion disabled")
+		return
+	}
+
+	interval := time.Duration(s.cfg.IngestInterval) * time.Second
+	log.Printf("Ingest worker started (interval: %v, connectors: %v)", interval, ingest.Connectors())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if s.summaryQueue.Saturated() {
+			s.queue.Reject()
+			log.Printf("Ingest run deferred: %d articles await a summary", s.summaryQueue.Snapshot().Depth)
+		} else {
+			s.RunAll(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Println("Ingest worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunAll ingests every enabled source that has a connector configured
+func (s *IngestService) RunAll(ctx context.Context) []IngestResult {
+	var sources []models.Source
+	if err := s.db.Where("enabled = ? AND connector <> ''", true).Find(&sources).Error; err != nil {
+		log.Printf("Failed to load sources for ingestion: %v", err)
+		return nil
+	}
+
+	results := make([]IngestResult, 0, len(sources))
+	changed := 0
+	for _, source := range sources {
+		result := s.RunSource(ctx, source)
+		if result.Error != "" {
+			log.Printf("Ingest failed for source %s: %s", source.Name, result.Error)
+		} else {
+			log.Printf("Ingested source %s: %d fetched, %d inserted, %d updated, %d merged, %d skipped, %d dropped",
+				source.Name, result.Fetched, result.Inserted, result.Updated, result.Merged, result.Skipped, result.Dropped)
+		}
+		changed += result.Inserted + result.Updated
+		results = append(results, result)
+	}
+
+	s.mu.Lock()
+	s.lastRun, s.lastResults = time.Now(), results
+	s.mu.Unlock()
+
+	// Embed new and updated articles
+	if changed > 0 && s.embeddingService.Enabled() {
+		if err := s.embeddingService.IndexArticles(ctx); err != nil {
+			log.Printf("Failed to index embeddings after ingest: %v", err)
+		}
+	}
+	return results
+}
+
+// LastRun returns when RunAll last finished and its per-source results,
+// zero before the first run
+func (s *IngestService) LastRun() (time.Time, []IngestResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastRun, append([]IngestResult(nil), s.lastResults...)
+}
+
+// RunSource fetches, transforms and stores articles for a single source
+func (s *IngestService) RunSource(ctx context.Context, source models.Source) IngestResult {
+	result := IngestResult{Source: source.Name}
+
+	connector, err := ingest.NewConnector(source)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	rawArticles, err := connector.Fetch(ctx)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Fetched = len(rawArticles)
+	if limit := s.cfg.IngestQueueMax; limit > 0 && len(rawArticles) > limit {
+		result.Dropped = len(rawArticles) - limit
+		rawArticles = rawArticles[:limit]
+		s.queue.Drop(result.Dropped)
+		log.Printf("Dropped %d articles from %s over INGEST_QUEUE_MAX", result.Dropped, source.Name)
+	}
+	s.queue.SetDepth(int64(len(rawArticles)))
+	defer s.queue.SetDepth(0)
+
+	articles := make([]models.Article, 0, len(rawArticles))
+	for _, raw := range rawArticles {
+		article, err := ingest.Transform(raw, source.Rules)
+		if err != nil {
+			log.Printf("Skipping article from %s: %v", source.Name, err)
+			result.Skipped++
+			continue
+		}
+		if article.SourceName == "" {
+			article.SourceName = source.Name
+		}
+		article.ApplyProvenance(source, models.IngestSourceConnector+source.Connector)
+		articles = append(articles, article)
+	}
+
+	if len(articles) == 0 {
+		return result
+	}
+
+	existing, err := s.loadExisting(articles)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	ids := make([]string, len(articles))
+	for i := range articles {
+		ids[i] = articles[i].ID
+	}
+	retired, err := database.RetiredArticleIDs(s.db, ids)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	var newArticles, updatedArticles []models.Article
+	for _, article := range articles {
+		current, found := existing[article.ID]
+		switch {
+		case retired[article.ID]:
+			result.Skipped++
+		case !found:
+			newArticles = append(newArticles, article)
+		case current.ContentHash != article.ContentHash:
+			if err := s.updateArticle(article); err != nil {
+				log.Printf("Failed to update article %s: %v", article.ID, err)
+				result.Skipped++
+				continue
+			}
+			result.Updated++
+			updatedArticles = append(updatedArticles, article)
+		default:
+			result.Skipped++
+		}
+	}
+
+	if len(newArticles) > 0 {
+		// Feeds re-publish stories under new IDs; repeats are merged into
+		// the stored article rather than listed twice
+		var merged []string
+		received := len(newArticles)
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			var err error
+			if newArticles, merged, err = database.MergeDuplicates(tx, newArticles); err != nil || len(newArticles) == 0 {
+				return err
+			}
+			if err := tx.CreateInBatches(&newArticles, 100).Error; err != nil {
+				return err
+			}
+			return database.SyncArticleCategories(tx, newArticles)
+		})
+		if err != nil {
+			result.Error = fmt.Sprintf("failed to store articles: %v", err)
+			return result
+		}
+		result.Inserted = len(newArticles)
+		result.Merged = received - len(newArticles)
+		keys := make([]string, 0, len(merged)+1)
+		for _, id := range merged {
+			keys = append(keys, ArticleSurrogateKey(id))
+		}
+		// Cached listings don't include the new articles yet
+		if result.Inserted > 0 {
+			keys = append(keys, SurrogateKeyNews)
+		}
+		s.cdnService.Purge(keys...)
+	}
+
+	// Changed content may match alerts the old version didn't
+	changed := append(newArticles, updatedArticles...)
+	s.alertService.Check(changed)
+
+	// Articles left untagged (e.g. LLM budget exhausted) are picked up by
+	// the topic worker
+	if _, err := s.topicService.Extract(ctx, changed); err != nil {
+		log.Printf("Failed to extract topics after ingesting %s: %v", source.Name, err)
+	}
+	return result
+}
+
+// loadExisting returns stored articles matching the given IDs, keyed by ID
+func (s *IngestService) loadExisting(articles []models.Article) (map[string]models.Article, error) {
+	ids := make([]string, len(articles))
+	for i := range articles {
+		ids[i] = articles[i].ID
+	}
+
+	var stored []models.Article
+	if err := s.db.Select("id", "title", "description", "content_hash").
+		Where("id IN ?", ids).Find(&stored).Error; err != nil {
+		return nil, fmt.Errorf("failed to load existing articles: %w", err)
+	}
+
+	existing := make(map[string]models.Article, len(stored))
+	for _, article := range stored {
+		// Rows stored before hashing was introduced
+		if article.ContentHash == "" {
+			article.ContentHash = article.ComputeContentHash()
+		}
+		existing[article.ID] = article
+	}
+	return existing, nil
+}
+
+// updateArticle stores changed content and invalidates derived data
+func (s *IngestService) updateArticle(article models.Article) error {
+	err := s.db.Model(&models.Article{}).Where("id = ?", article.ID).Updates(map[string]interface{}{
+		"title":            article.Title,
+		"description":      article.Description,
+		"url":              article.URL,
+		"category":         article.Category,
+		"content_hash":     article.ContentHash,
+		"llm_summary":      "",
+		"summary_attempts": 0,
+		"sentiment":        "",
+		"tone":             "",
+		"publisher":        article.Publisher,
+		"license":          article.License,
+		"attribution":      article.Attribution,
+		"ingest_source":    article.IngestSource,
+		"image_url":        article.ImageURL,
+	}).Error
+	if err != nil {
+		return err
+	}
+	if err := database.SyncArticleCategories(s.db, []models.Article{article}); err != nil {
+		return err
+	}
+
+	// Summary and embedding were computed from the old content
+	s.llmService.InvalidateSummary(article.ID)
+	if err := s.db.Where("article_id = ?", article.ID).Delete(&models.ArticleEmbedding{}).Error; err != nil {
+		log.Printf("Failed to drop embedding for article %s: %v", article.ID, err)
+	} else {
+		s.embeddingService.Forget(article.ID)
+	}
+
+	s.cdnService.Purge(ArticleSurrogateKey(article.ID))
+	s.webhookService.Emit(WebhookArticleUpdated, articleUpdatedData(article))
+	return nil
+}
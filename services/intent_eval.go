@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"news-backend/models"
+)
+
+// Intent evaluation modes: live calls the LLM, replay parses each case's
+// recorded answer without calling it, and record calls the LLM and keeps
+// its answers for later replays
+const (
+	IntentEvalLive   = "live"
+	IntentEvalReplay = "replay"
+	IntentEvalRecord = "record"
+)
+
+// IsIntentEvalMode reports whether mode is a known evaluation mode
+func IsIntentEvalMode(mode string) bool {
+	return mode == IntentEvalLive || mode == IntentEvalReplay || mode == IntentEvalRecord
+}
+
+// IntentEvalCase is a query with the intent and entities it should parse
+// to. Only the listed entities are checked, so extra ones the LLM adds
+// don't fail a case.
+type IntentEvalCase struct {
+	Query    string          `json:"query"`
+	Intent   string          `json:"intent"`
+	Entities models.Entities `json:"entities,omitempty"`
+	Recorded string          `json:"recorded,omitempty"` // LLM answer replayed in replay mode
+}
+
+// IntentEvalResult is how one case was parsed
+type IntentEvalResult struct {
+	Query          string          `json:"query"`
+	ExpectedIntent string          `json:"expected_intent"`
+	Intent         string          `json:"intent"`
+	Entities       models.Entities `json:"entities"`
+	Mismatched     []string        `json:"mismatched_entities,omitempty"` // expected entities that were missing or differed
+	Source         string          `json:"source"`                        // "rules", "llm" or "recorded"
+	Error          string          `json:"error,omitempty"`
+}
+
+// Passed reports whether the intent and every expected entity matched
+func (r IntentEvalResult) Passed() bool {
+	return r.Error == "" && r.Intent == r.ExpectedIntent && len(r.Mismatched) == 0
+}
+
+// IntentEvalReport summarizes an evaluation run
+type IntentEvalReport struct {
+	Cases         int                `json:"cases"`
+	IntentCorrect int                `json:"intent_correct"`
+	Passed        int                `json:"passed"` // intent and entities correct
+	Failures      []IntentEvalResult `json:"failures"`
+}
+
+// IntentAccuracy is the share of cases with the expected intent
+func (r IntentEvalReport) IntentAccuracy() float64 {
+	if r.Cases == 0 {
+		return 0
+	}
+	return float64(r.IntentCorrect) / float64(r.Cases)
+}
+
+// Accuracy is the share of cases with the expected intent and entities
+func (r IntentEvalReport) Accuracy() float64 {
+	if r.Cases == 0 {
+		return 0
+	}
+	return float64(r.Passed) / float64(r.Cases)
+}
+
+// LoadIntentEvalCases reads evaluation cases from a JSON array file
+func LoadIntentEvalCases(path string) ([]IntentEvalCase, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read intent eval cases: %w", err)
+	}
+	var cases []IntentEvalCase
+	if err := json.Unmarshal(data, &cases); err != nil {
+		return nil, fmt.Errorf("failed to parse intent eval cases: %w", err)
+	}
+	return cases, nil
+}
+
+// SaveIntentEvalCases writes evaluation cases back, e.g. after recording
+func SaveIntentEvalCases(path string, cases []IntentEvalCase) error {
+	data, err := json.MarshalIndent(cases, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode intent eval cases: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write intent eval cases: %w", err)
+	}
+	return nil
+}
+
+// EvaluateIntents runs every case through intent parsing the way
+// ParseIntent does, rules first, and compares the results with the
+// expectations. In record mode the LLM's answers are stored in cases.
+func (s *LLMService) EvaluateIntents(ctx context.Context, cases []IntentEvalCase, mode string) IntentEvalReport {
+	report := IntentEvalReport{Cases: len(cases), Failures: []IntentEvalResult{}}
+	for i := range cases {
+		result := s.evaluateIntent(ctx, &cases[i], mode)
+		if result.Intent == result.ExpectedIntent {
+			report.IntentCorrect++
+		}
+		if result.Passed() {
+			report.Passed++
+		} else {
+			report.Failures = append(report.Failures, result)
+		}
+	}
+	return report
+}
+
+// evaluateIntent parses one case
+func (s *LLMService) evaluateIntent(ctx context.Context, c *IntentEvalCase, mode string) IntentEvalResult {
+	result := IntentEvalResult{Query: c.Query, ExpectedIntent: c.Intent}
+
+	var intentResp models.IntentResponse
+	matched := false
+	if s.intentRules != nil {
+		intentResp, matched = s.intentRules.match(c.Query)
+	}
+	switch {
+	case matched:
+		result.Source = "rules"
+	case mode == IntentEvalReplay:
+		result.Source = "recorded"
+		if c.Recorded == "" {
+			result.Error = "no recorded response"
+			return result
+		}
+		intentResp = parseIntentContent(c.Query, c.Recorded)
+	default:
+		result.Source = "llm"
+		content, err := s.completeIntent(ctx, c.Query)
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		if mode == IntentEvalRecord {
+			c.Recorded = strings.TrimSpace(content)
+		}
+		intentResp = parseIntentContent(c.Query, content)
+	}
+
+	result.Intent = intentResp.Intent
+	result.Entities = intentResp.Entities
+	for key, expected := range c.Entities {
+		if !entityMatches(expected, intentResp.Entities[key]) {
+			result.Mismatched = append(result.Mismatched, key)
+		}
+	}
+	sort.Strings(result.Mismatched)
+	return result
+}
+
+// entityMatches compares an expected entity with a parsed one, ignoring
+// case and surrounding space in strings and the order of lists. An
+// expected list only needs its items present.
+func entityMatches(expected, actual interface{}) bool {
+	switch want := expected.(type) {
+	case string:
+		got, ok := actual.(string)
+		return ok && strings.EqualFold(strings.TrimSpace(got), strings.TrimSpace(want))
+	case []interface{}:
+		got, ok := actual.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, w := range want {
+			found := false
+			for _, g := range got {
+				if entityMatches(w, g) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(expected, actual)
+	}
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"news-backend/models"
+	"news-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// intentRulesRefresh is how often known category and source names are reloaded
+const intentRulesRefresh = 5 * time.Minute
+
+// intentFillerWords are stripped from the edges of a query before it is
+// compared with category and source names ("latest sports news" -> "sports")
+var intentFillerWords = map[string]bool{
+	"news": true, "latest": true, "recent": true, "headlines": true, "stories": true,
+	"articles": true, "updates": true, "show": true, "me": true, "get": true, "give": true,
+	"all": true, "the": true, "about": true, "on": true, "in": true,
+}
+
+// intentSourceCues introduce a source name ("news from reuters")
+var intentSourceCues = map[string]bool{"from": true, "by": true, "via": true}
+
+// intentNearbyPhrases ask for news around the caller's location
+var intentNearbyPhrases = []string{"near me", "nearby", "around me", "close to me", "in my area", "near my location", "local"}
+
+// intentRules resolves obviously structured queries without the LLM: exact
+// category names, "near me", source names introduced by "from" or "by", and
+// generic requests for the news. Everything else, including queries with
+// dates, goes to the LLM.
+type intentRules struct {
+	db *gorm.DB
+
+	mu         sync.RWMutex
+	categories map[string]string // normalized name -> stored name
+	sources    map[string]string // normalized name -> stored source_name
+	loadedAt   time.Time
+}
+
+func newIntentRules(db *gorm.DB) *intentRules {
+	return &intentRules{db: db}
+}
+
+// match returns the intent for query when a rule applies
+func (r *intentRules) match(query string) (models.IntentResponse, bool) {
+	words := strings.Fields(utils.NormalizeTitle(query))
+	if len(words) == 0 {
+		return models.IntentResponse{}, false
+	}
+	categories, sources := r.names()
+
+	// "near me", "local news": only when nothing else is asked for
+	for _, phrase := range intentNearbyPhrases {
+		rest, found := cutPhrase(words, strings.Fields(phrase))
+		if found && len(trimFillers(rest)) == 0 {
+			return models.IntentResponse{
+				Intent:   models.IntentNearby,
+				Entities: models.Entities{"query": ""},
+			}, true
+		}
+	}
+
+	// "news from reuters", "by the hindu"
+	for i, word := range words {
+		if !intentSourceCues[word] || len(trimFillers(words[:i])) > 0 {
+			continue
+		}
+		name := words[i+1:]
+		source, ok := lookupName(sources, name, trimTrailingFillers(name))
+		if !ok && len(name) > 1 && name[0] == "the" {
+			source, ok = lookupName(sources, name[1:], trimTrailingFillers(name[1:]))
+		}
+		if ok {
+			return models.IntentResponse{
+				Intent:   models.IntentSource,
+				Entities: models.Entities{"source": source, "query": query},
+			}, true
+		}
+		break
+	}
+
+	// "sports", "latest technology news"
+	if category, ok := lookupName(categories, words, trimFillers(words)); ok {
+		return models.IntentResponse{
+			Intent:   models.IntentCategory,
+			Entities: models.Entities{"category": category, "query": query},
+		}, true
+	}
+
+	// "show me the news", "what's happening today"
+	if utils.IsGenericQuery(query) {
+		return models.IntentResponse{
+			Intent:   models.IntentDiscovery,
+			Entities: models.Entities{"query": ""},
+		}, true
+	}
+	return models.IntentResponse{}, false
+}
+
+// names returns the known category and source names, reloading them when stale
+func (r *intentRules) names() (map[string]string, map[string]string) {
+	r.mu.RLock()
+	categories, sources, fresh := r.categories, r.sources, time.Since(r.loadedAt) < intentRulesRefresh
+	r.mu.RUnlock()
+	if fresh {
+		return categories, sources
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if time.Since(r.loadedAt) < intentRulesRefresh {
+		return r.categories, r.sources
+	}
+	// Retry after the refresh interval even if loading fails
+	r.loadedAt = time.Now()
+
+	var categoryNames []string
+	if err := r.db.Model(&models.Category{}).Pluck("name", &categoryNames).Error; err != nil {
+		log.Printf("Failed to load categories for intent rules: %v", err)
+		return r.categories, r.sources
+	}
+	// Most used spelling first, so it wins when variants normalize alike
+	var sourceNames []string
+	err := r.db.Model(&models.Article{}).
+		Where("source_name <> ''").
+		Group("source_name").
+		Order("COUNT(*) DESC").
+		Pluck("source_name", &sourceNames).Error
+	if err != nil {
+		log.Printf("Failed to load sources for intent rules: %v", err)
+		return r.categories, r.sources
+	}
+
+	r.categories = normalizedNames(categoryNames)
+	r.sources = normalizedNames(sourceNames)
+	return r.categories, r.sources
+}
+
+// normalizedNames maps each name's normalized form to the first name with it
+func normalizedNames(names []string) map[string]string {
+	byKey := make(map[string]string, len(names))
+	for _, name := range names {
+		key := utils.NormalizeTitle(name)
+		if _, seen := byKey[key]; key != "" && !seen {
+			byKey[key] = strings.TrimSpace(name)
+		}
+	}
+	return byKey
+}
+
+// lookupName returns the stored name for the first candidate word list that
+// matches a known name
+func lookupName(names map[string]string, candidates ...[]string) (string, bool) {
+	for _, words := range candidates {
+		if name, ok := names[strings.Join(words, " ")]; ok && len(words) > 0 {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// cutPhrase removes the first occurrence of phrase from words
+func cutPhrase(words, phrase []string) ([]string, bool) {
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		if strings.Join(words[i:i+len(phrase)], " ") == strings.Join(phrase, " ") {
+			rest := append(append([]string{}, words[:i]...), words[i+len(phrase):]...)
+			return rest, true
+		}
+	}
+	return words, false
+}
+
+// trimFillers strips filler words from both ends
+func trimFillers(words []string) []string {
+	for len(words) > 0 && intentFillerWords[words[0]] {
+		words = words[1:]
+	}
+	return trimTrailingFillers(words)
+}
+
+// trimTrailingFillers strips filler words from the end
+func trimTrailingFillers(words []string) []string {
+	for len(words) > 0 && intentFillerWords[words[len(words)-1]] {
+		words = words[:len(words)-1]
+	}
+	return words
+}
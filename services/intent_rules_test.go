@@ -0,0 +1,83 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"news-backend/models"
+)
+
+func TestIntentRulesMatch(t *testing.T) {
+	rules := &intentRules{
+		categories: normalizedNames([]string{"Sports", "Technology", "Business", "Local", "business"}),
+		sources:    normalizedNames([]string{"Reuters", "The Hindu", "Business Standard", "Sports"}),
+		loadedAt:   time.Now(),
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		matched  bool
+		intent   string
+		entity   string // entity checked, besides the intent
+		expected string
+	}{
+		// Matches
+		{name: "Category name", query: "sports", matched: true,
+			intent: models.IntentCategory, entity: "category", expected: "Sports"},
+		{name: "Category between fillers", query: "show me the latest technology news", matched: true,
+			intent: models.IntentCategory, entity: "category", expected: "Technology"},
+		{name: "Source after cue", query: "news from reuters", matched: true,
+			intent: models.IntentSource, entity: "source", expected: "Reuters"},
+		{name: "Source with leading article", query: "by the hindu", matched: true,
+			intent: models.IntentSource, entity: "source", expected: "The Hindu"},
+		{name: "Near me", query: "news near me", matched: true,
+			intent: models.IntentNearby, entity: "query", expected: ""},
+		{name: "Generic request", query: "what's happening today", matched: true,
+			intent: models.IntentDiscovery, entity: "query", expected: ""},
+
+		// No match
+		{name: "Empty query", query: "   ", matched: false},
+		{name: "Free text", query: "cricket world cup final", matched: false},
+		{name: "Unknown source", query: "news from the guardian", matched: false},
+		{name: "Source without cue", query: "reuters", matched: false},
+		{name: "Near me with a topic", query: "cricket near me", matched: false},
+		{name: "Category with more words", query: "sports injuries in football", matched: false},
+
+		// Precedence
+		{name: "Nearby before category", query: "local news", matched: true,
+			intent: models.IntentNearby, entity: "query", expected: ""},
+		{name: "Source before category", query: "from business standard", matched: true,
+			intent: models.IntentSource, entity: "source", expected: "Business Standard"},
+		{name: "Cue makes a source of a category name", query: "news from sports", matched: true,
+			intent: models.IntentSource, entity: "source", expected: "Sports"},
+		{name: "First spelling wins", query: "business", matched: true,
+			intent: models.IntentCategory, entity: "category", expected: "Business"},
+
+		// Case and punctuation
+		{name: "Upper case category", query: "SPORTS", matched: true,
+			intent: models.IntentCategory, entity: "category", expected: "Sports"},
+		{name: "Mixed case source", query: "News From REUTERS!", matched: true,
+			intent: models.IntentSource, entity: "source", expected: "Reuters"},
+		{name: "Upper case nearby", query: "Near Me", matched: true,
+			intent: models.IntentNearby, entity: "query", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, matched := rules.match(tt.query)
+			if matched != tt.matched {
+				t.Fatalf("match(%q) matched = %v (%+v), expected %v", tt.query, matched, result, tt.matched)
+			}
+			if !tt.matched {
+				return
+			}
+			if result.Intent != tt.intent {
+				t.Errorf("match(%q) intent = %q, expected %q", tt.query, result.Intent, tt.intent)
+			}
+			if got := result.Entities[tt.entity]; got != tt.expected {
+				t.Errorf("match(%q) %s = %v, expected %q", tt.query, tt.entity, got, tt.expected)
+			}
+		})
+	}
+}
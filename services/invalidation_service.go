@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"news-backend/config"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Cache kinds broadcast between instances
+const (
+	InvalidationTrending     = "trending"      // whole trending cache
+	InvalidationTrendingNear = "trending_near" // trending results around a point, key = TrendingLocationKey
+	InvalidationSummary      = "summary"       // one article's cached summary, key = article ID
+	InvalidationUserLinks    = "user_links"    // resolved canonical user IDs
+)
+
+// invalidationPublishTimeout bounds how long a write waits on Redis
+const invalidationPublishTimeout = 2 * time.Second
+
+// InvalidationMessage is broadcast on the pub/sub channel
+type InvalidationMessage struct {
+	Origin string `json:"origin"` // instance that made the change
+	Kind   string `json:"kind"`
+	Key    string `json:"key,omitempty"`
+}
+
+// InvalidationService keeps in-process caches coherent across instances by
+// broadcasting invalidations over Redis pub/sub. Without REDIS_URL it is
+// single-instance: publishing is a no-op and nothing is received.
+type InvalidationService struct {
+	cfg        *config.Config
+	client     *redis.Client
+	instanceID string
+
+	mu       sync.RWMutex
+	handlers map[string][]func(key string)
+}
+
+// NewInvalidationService creates a new invalidation service instance
+func NewInvalidationService(cfg *config.Config) (*InvalidationService, error) {
+	s := &InvalidationService{
+		cfg:        cfg,
+		instanceID: newInstanceID(),
+		handlers:   make(map[string][]func(key string)),
+	}
+
+	if cfg.RedisURL != "" {
+		options, err := redis.ParseURL(cfg.RedisURL)
+		if err != nil {
+			return nil, err
+		}
+		s.client = redis.NewClient(options)
+	}
+	return s, nil
+}
+
+// newInstanceID returns a random identifier for this process
+func newInstanceID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Subscribe registers a handler that drops local cache entries when another
+// instance invalidates kind
+func (s *InvalidationService) Subscribe(kind string, handler func(key string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[kind] = append(s.handlers[kind], handler)
+}
+
+// Publish tells other instances to drop their cached kind/key. The caller is
+// responsible for invalidating its own cache.
+func (s *InvalidationService) Publish(kind, key string) {
+	if s.client == nil {
+		return
+	}
+
+	payload, err := json.Marshal(InvalidationMessage{Origin: s.instanceID, Kind: kind, Key: key})
+	if err != nil {
+		log.Printf("Failed to encode invalidation %s/%s: %v", kind, key, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), invalidationPublishTimeout)
+	defer cancel()
+	if err := s.client.Publish(ctx, s.cfg.InvalidationChannel, payload).Err(); err != nil {
+		log.Printf("Failed to publish invalidation %s/%s: %v", kind, key, err)
+	}
+}
+
+// Start consumes invalidations from other instances until ctx is cancelled.
+// It returns immediately when no Redis URL is configured.
+func (s *InvalidationService) Start(ctx context.Context) {
+	if s.client == nil {
+		log.Println("Cross-instance cache invalidation disabled")
+		return
+	}
+	defer s.client.Close()
+
+	sub := s.client.Subscribe(ctx, s.cfg.InvalidationChannel)
+	defer sub.Close()
+	log.Printf("Cache invalidation subscriber started (channel: %s, instance: %s)",
+		s.cfg.InvalidationChannel, s.instanceID)
+
+	messages := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Cache invalidation subscriber stopped")
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			s.dispatch(msg.Payload)
+		}
+	}
+}
+
+// dispatch runs the handlers for a message published by another instance
+func (s *InvalidationService) dispatch(payload string) {
+	var msg InvalidationMessage
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		log.Printf("Ignoring malformed invalidation message: %v", err)
+		return
+	}
+	if msg.Origin == s.instanceID {
+		return
+	}
+
+	s.mu.RLock()
+	handlers := s.handlers[msg.Kind]
+	s.mu.RUnlock()
+	for _, handler := range handlers {
+		handler(msg.Key)
+	}
+}
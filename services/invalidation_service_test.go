@@ -0,0 +1,113 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"news-backend/cache"
+	"news-backend/config"
+)
+
+func newTestInvalidationService(t *testing.T) *InvalidationService {
+	t.Helper()
+	invalidation, err := NewInvalidationService(&config.Config{})
+	if err != nil {
+		t.Fatalf("NewInvalidationService() error = %v", err)
+	}
+	return invalidation
+}
+
+func invalidationPayload(t *testing.T, msg InvalidationMessage) string {
+	t.Helper()
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(payload)
+}
+
+func TestNewInvalidationServiceRejectsBadRedisURL(t *testing.T) {
+	if _, err := NewInvalidationService(&config.Config{RedisURL: "not-a-redis-url"}); err == nil {
+		t.Error("NewInvalidationService() succeeded, expected an error for a bad REDIS_URL")
+	}
+}
+
+func TestInvalidationDispatch(t *testing.T) {
+	invalidation := newTestInvalidationService(t)
+	var got []string
+	invalidation.Subscribe(InvalidationSummary, func(key string) { got = append(got, "first:"+key) })
+	invalidation.Subscribe(InvalidationSummary, func(key string) { got = append(got, "second:"+key) })
+
+	tests := []struct {
+		name    string
+		payload string
+		want    int
+	}{
+		{"other instance", invalidationPayload(t, InvalidationMessage{Origin: "other", Kind: InvalidationSummary, Key: "article-1"}), 2},
+		{"own instance", invalidationPayload(t, InvalidationMessage{Origin: invalidation.instanceID, Kind: InvalidationSummary, Key: "article-1"}), 0},
+		{"no handler", invalidationPayload(t, InvalidationMessage{Origin: "other", Kind: InvalidationUserLinks}), 0},
+		{"malformed", "{not json", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			invalidation.dispatch(tt.payload)
+			if len(got) != tt.want {
+				t.Fatalf("dispatch() ran handlers %v, expected %d", got, tt.want)
+			}
+			if tt.want > 0 && (got[0] != "first:article-1" || got[1] != "second:article-1") {
+				t.Errorf("dispatch() ran handlers %v, expected both in order with the key", got)
+			}
+		})
+	}
+}
+
+func TestInvalidationPublishWithoutRedis(t *testing.T) {
+	invalidation := newTestInvalidationService(t)
+	called := false
+	invalidation.Subscribe(InvalidationTrending, func(string) { called = true })
+
+	// Single-instance: nothing is sent, and the publisher's own handlers
+	// are the caller's business
+	invalidation.Publish(InvalidationTrending, "")
+	if called {
+		t.Error("Publish() ran a local handler, expected a no-op without Redis")
+	}
+}
+
+func TestTrendingLocationKeyRoundTrip(t *testing.T) {
+	lat, lon, err := parseTrendingLocationKey(TrendingLocationKey(17.385, -78.4867))
+	if err != nil || lat != 17.385 || lon != -78.4867 {
+		t.Errorf("parseTrendingLocationKey() = %v, %v, %v, expected the formatted point back", lat, lon, err)
+	}
+	for _, key := range []string{"", "17.385", "north,78.4867", "17.385,east"} {
+		if _, _, err := parseTrendingLocationKey(key); err == nil {
+			t.Errorf("parseTrendingLocationKey(%q) succeeded, expected an error", key)
+		}
+	}
+}
+
+func TestTrendingNearInvalidationDropsNearbyCells(t *testing.T) {
+	invalidation := newTestInvalidationService(t)
+	store := cache.NewMemory()
+	service := NewTrendingService(&config.Config{TrendingCacheTTL: 300}, nil, nil, nil, invalidation, nil, store, nil, nil, nil)
+
+	lat, lon := 17.385, 78.4867
+	nearKey := service.getCacheKey(lat, lon, 25)
+	farKey := service.getCacheKey(28.6139, 77.209, 25)
+	for _, key := range []string{nearKey, farKey} {
+		service.putInCache(key, &TrendingCache{CachedAt: time.Now(), RadiusKm: 25})
+	}
+
+	// Another instance recorded an event next to the first cell
+	invalidation.dispatch(invalidationPayload(t, InvalidationMessage{Origin: "other",
+		Kind: InvalidationTrendingNear, Key: TrendingLocationKey(lat+0.01, lon)}))
+
+	if _, ok := service.getFromCache(nearKey); ok {
+		t.Error("trending cell near the event is still cached, expected the broadcast to drop it")
+	}
+	if _, ok := service.getFromCache(farKey); !ok {
+		t.Error("trending cell far from the event was dropped, expected it to stay cached")
+	}
+}
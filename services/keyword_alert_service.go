@@ -0,0 +1,199 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"news-backend/config"
+	"news-backend/database"
+	"news-backend/models"
+	"news-backend/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidAlert is returned for empty keywords or unknown alert kinds
+var ErrInvalidAlert = errors.New("invalid keyword alert")
+
+// KeywordAlertService manages editors' keyword alerts and checks incoming
+// articles against them
+type KeywordAlertService struct {
+	db             *gorm.DB
+	cfg            *config.Config
+	webhookService *WebhookService
+}
+
+// alertNotification describes the alerts one article matched in a
+// keyword_alert.matched webhook
+type alertNotification struct {
+	ArticleID  string                `json:"article_id"`
+	Title      string                `json:"title"`
+	URL        string                `json:"url"`
+	SourceName string                `json:"source_name"`
+	Alerts     []models.KeywordAlert `json:"alerts"`
+}
+
+// NewKeywordAlertService creates a new keyword alert service instance
+func NewKeywordAlertService(cfg *config.Config, webhookService *WebhookService) *KeywordAlertService {
+	return &KeywordAlertService{
+		db:             database.GetDB(),
+		cfg:            cfg,
+		webhookService: webhookService,
+	}
+}
+
+// ListAlerts returns all keyword alerts, oldest first
+func (s *KeywordAlertService) ListAlerts() ([]models.KeywordAlert, error) {
+	var alerts []models.KeywordAlert
+	if err := s.db.Order("id").Find(&alerts).Error; err != nil {
+		return nil, fmt.Errorf("failed to load keyword alerts: %w", err)
+	}
+	return alerts, nil
+}
+
+// CreateAlert validates and stores a keyword alert. Kind defaults to keyword.
+func (s *KeywordAlertService) CreateAlert(alert *models.KeywordAlert) error {
+	alert.Keyword = strings.TrimSpace(alert.Keyword)
+	if utils.NormalizeTitle(alert.Keyword) == "" {
+		return fmt.Errorf("%w: keyword must contain letters or digits", ErrInvalidAlert)
+	}
+	if alert.Kind == "" {
+		alert.Kind = models.AlertKindKeyword
+	}
+	if !models.IsValidAlertKind(alert.Kind) {
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidAlert, alert.Kind)
+	}
+
+	alert.ID = 0
+	if err := s.db.Create(alert).Error; err != nil {
+		return fmt.Errorf("failed to store keyword alert: %w", err)
+	}
+	return nil
+}
+
+// SetAlertEnabled pauses or resumes a keyword alert
+func (s *KeywordAlertService) SetAlertEnabled(id uint, enabled bool) (*models.KeywordAlert, error) {
+	var alert models.KeywordAlert
+	if err := s.db.First(&alert, id).Error; err != nil {
+		return nil, err
+	}
+	alert.Enabled = enabled
+	if err := s.db.Save(&alert).Error; err != nil {
+		return nil, fmt.Errorf("failed to update keyword alert: %w", err)
+	}
+	return &alert, nil
+}
+
+// DeleteAlert removes a keyword alert. Its matches stay in the triage queue.
+func (s *KeywordAlertService) DeleteAlert(id uint) error {
+	result := s.db.Delete(&models.KeywordAlert{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete keyword alert: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Check matches articles' titles and descriptions against enabled alerts,
+// tags each new match for triage and sends one keyword_alert.matched webhook
+// per article. An article is only reported once per alert, even if it is
+// updated later.
+func (s *KeywordAlertService) Check(articles []models.Article) {
+	if len(articles) == 0 {
+		return
+	}
+
+	var alerts []models.KeywordAlert
+	if err := s.db.Where("enabled = ?", true).Find(&alerts).Error; err != nil {
+		log.Printf("Failed to load keyword alerts: %v", err)
+		return
+	}
+	if len(alerts) == 0 {
+		return
+	}
+
+	for _, article := range articles {
+		text := article.Title + "\n" + article.Description
+		var matched []models.KeywordAlert
+		for _, alert := range alerts {
+			if !utils.ContainsPhrase(text, alert.Keyword, alert.Kind == models.AlertKindEntity) {
+				continue
+			}
+
+			match := models.AlertMatch{
+				AlertID:    alert.ID,
+				ArticleID:  article.ID,
+				Keyword:    alert.Keyword,
+				Editor:     alert.Editor,
+				Title:      article.Title,
+				URL:        article.URL,
+				SourceName: article.SourceName,
+				Status:     models.AlertMatchStatusNew,
+			}
+			result := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&match)
+			if result.Error != nil {
+				log.Printf("Failed to tag article %s for keyword alert %d: %v", article.ID, alert.ID, result.Error)
+				continue
+			}
+			if result.RowsAffected > 0 {
+				matched = append(matched, alert)
+			}
+		}
+
+		if len(matched) > 0 {
+			log.Printf("Article %s matched %d keyword alert(s)", article.ID, len(matched))
+			s.webhookService.Emit(WebhookKeywordAlert, alertNotification{
+				ArticleID:  article.ID,
+				Title:      article.Title,
+				URL:        article.URL,
+				SourceName: article.SourceName,
+				Alerts:     matched,
+			})
+		}
+	}
+}
+
+// ListMatches returns tagged articles newest first, optionally filtered by
+// status and alert, along with the total number of matching entries
+func (s *KeywordAlertService) ListMatches(status string, alertID uint, limit, offset int) ([]models.AlertMatch, int64, error) {
+	query := s.db.Model(&models.AlertMatch{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if alertID != 0 {
+		query = query.Where("alert_id = ?", alertID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count alert matches: %w", err)
+	}
+
+	var matches []models.AlertMatch
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&matches).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to load alert matches: %w", err)
+	}
+	return matches, total, nil
+}
+
+// TriageMatch marks a tagged article as handled
+func (s *KeywordAlertService) TriageMatch(id uint) (*models.AlertMatch, error) {
+	var match models.AlertMatch
+	if err := s.db.First(&match, id).Error; err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	match.Status = models.AlertMatchStatusTriaged
+	match.TriagedAt = &now
+	if err := s.db.Save(&match).Error; err != nil {
+		return nil, fmt.Errorf("failed to update alert match: %w", err)
+	}
+	return &match, nil
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"news-backend/models"
+	"news-backend/utils"
+
+	openai "github.com/sashabaranov/go-openai"
+	"gorm.io/gorm"
+)
+
+const (
+	// llmAuditPruneInterval is how often expired audit records are deleted
+	llmAuditPruneInterval = 10 * time.Minute
+	// llmAuditMaxText caps the stored prompt and response
+	llmAuditMaxText = 8000
+)
+
+// llmAuditLog records a sample of chat completions for debugging output
+// quality. Percent 0 disables it.
+type llmAuditLog struct {
+	db         *gorm.DB
+	percent    float64
+	retention  time.Duration
+	maxEntries int
+
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+
+func newLLMAuditLog(db *gorm.DB, percent float64, retentionDays, maxEntries int) *llmAuditLog {
+	return &llmAuditLog{
+		db:         db,
+		percent:    percent,
+		retention:  time.Duration(retentionDays) * 24 * time.Hour,
+		maxEntries: maxEntries,
+	}
+}
+
+// sample decides whether to record the next call
+func (a *llmAuditLog) sample() bool {
+	return a.percent > 0 && rand.Float64()*100 < a.percent
+}
+
+// record scrubs and stores an audit entry, pruning old entries from time to time
+func (a *llmAuditLog) record(entry *models.LLMAudit) {
+	entry.Prompt = truncateText(utils.ScrubPII(entry.Prompt), llmAuditMaxText)
+	entry.Response = truncateText(utils.ScrubPII(entry.Response), llmAuditMaxText)
+	if err := a.db.Create(entry).Error; err != nil {
+		log.Printf("Failed to record LLM audit entry: %v", err)
+		return
+	}
+
+	a.mu.Lock()
+	due := time.Since(a.lastPrune) > llmAuditPruneInterval
+	if due {
+		a.lastPrune = time.Now()
+	}
+	a.mu.Unlock()
+	if due {
+		a.prune()
+	}
+}
+
+// prune drops entries past the retention period or beyond the newest maxEntries
+func (a *llmAuditLog) prune() {
+	if a.retention > 0 {
+		cutoff := time.Now().Add(-a.retention)
+		if err := a.db.Where("created_at < ?", cutoff).Delete(&models.LLMAudit{}).Error; err != nil {
+			log.Printf("Failed to prune LLM audit entries: %v", err)
+		}
+	}
+	if a.maxEntries > 0 {
+		newest := a.db.Model(&models.LLMAudit{}).Select("id").Order("id DESC").Limit(a.maxEntries)
+		if err := a.db.Where("id NOT IN (?)", newest).Delete(&models.LLMAudit{}).Error; err != nil {
+			log.Printf("Failed to cap LLM audit entries: %v", err)
+		}
+	}
+}
+
+// chatTranscript renders chat messages as "role: content" blocks
+func chatTranscript(messages []openai.ChatCompletionMessage) string {
+	parts := make([]string, len(messages))
+	for i, m := range messages {
+		parts[i] = m.Role + ": " + m.Content
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// truncateText cuts text to at most max bytes without splitting a UTF-8 rune
+func truncateText(text string, max int) string {
+	if len(text) <= max {
+		return text
+	}
+	for max > 0 && !utf8.RuneStart(text[max]) {
+		max--
+	}
+	return text[:max] + "…"
+}
+
+// AuditEntries lists recorded LLM interactions, newest first, optionally
+// for one purpose, along with the total matching
+func (s *LLMService) AuditEntries(purpose string, limit, offset int) ([]models.LLMAudit, int64, error) {
+	query := s.audit.db.Model(&models.LLMAudit{})
+	if purpose != "" {
+		query = query.Where("purpose = ?", purpose)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count LLM audit entries: %w", err)
+	}
+
+	var entries []models.LLMAudit
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&entries).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to load LLM audit entries: %w", err)
+	}
+	return entries, total, nil
+}
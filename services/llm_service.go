@@ -3,59 +3,271 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"strings"
 	"sync"
+	"time"
 
+	"news-backend/cache"
 	"news-backend/config"
+	"news-backend/database"
 	"news-backend/models"
 	"news-backend/prompts"
+	"news-backend/utils"
 
 	openai "github.com/sashabaranov/go-openai"
+	"gorm.io/gorm"
 )
 
 type LLMService struct {
-	client       *openai.Client
+	providers    []*llmProvider // Fallback chain in priority order
 	cfg          *config.Config
-	summaryCache sync.Map // Cache for article summaries
+	usage        *llmUsageTracker
+	summaryCache cache.Cache // Article summaries, possibly shared between instances
+	audit        *llmAuditLog
+	intentRules  *intentRules // nil when INTENT_RULES is off and an LLM is configured
+	invalidation *InvalidationService
+	db           *gorm.DB // fetched article bodies to summarize
+}
+
+// llmProvider is a single OpenAI-compatible endpoint in the fallback chain
+type llmProvider struct {
+	name         string
+	client       *openai.Client
+	intentModel  string
+	summaryModel string
+	breaker      *circuitBreaker
 }
 
+// ProviderStatus describes the health of a provider in the chain
+type ProviderStatus struct {
+	Name    string `json:"name"`
+	Breaker string `json:"breaker"`
+}
+
+// errAllProvidersFailed is returned when no provider in the chain succeeded
+var errAllProvidersFailed = errors.New("all LLM providers failed or are unavailable")
+
+// ErrLLMOffline is returned for LLM calls when LLM_PROVIDER is none
+var ErrLLMOffline = errors.New("no LLM provider is configured")
+
+// offlineSummaryLength bounds, in bytes, the description excerpt served as
+// the summary when LLM_PROVIDER is none
+const offlineSummaryLength = 200
+
 // NewLLMService creates a new LLM service instance
-func NewLLMService(cfg *config.Config) *LLMService {
-	var client *openai.Client
+func NewLLMService(cfg *config.Config, invalidation *InvalidationService, store cache.Cache) *LLMService {
+	cooldown := time.Duration(cfg.LLMBreakerCooldown) * time.Second
 
-	switch cfg.LLMProvider {
-	case "openai":
-		clientConfig := openai.DefaultConfig(cfg.OpenAIKey)
-		client = openai.NewClientWithConfig(clientConfig)
-	case "groq":
-		clientConfig := openai.DefaultConfig(cfg.GroqKey)
-		clientConfig.BaseURL = cfg.LLMBaseURL
-		client = openai.NewClientWithConfig(clientConfig)
-	default:
-		log.Fatalf("Invalid LLM provider: %s", cfg.LLMProvider)
+	providers := make([]*llmProvider, 0, len(cfg.LLMProviders))
+	for _, p := range cfg.LLMProviders {
+		clientConfig := openai.DefaultConfig(p.APIKey)
+		if p.BaseURL != "" {
+			clientConfig.BaseURL = p.BaseURL
+		}
+		providers = append(providers, &llmProvider{
+			name:         p.Name,
+			client:       openai.NewClientWithConfig(clientConfig),
+			intentModel:  p.IntentModel,
+			summaryModel: p.SummaryModel,
+			breaker:      newCircuitBreaker(cfg.LLMBreakerThreshold, cooldown),
+		})
 	}
 
-	return &LLMService{
-		client: client,
-		cfg:    cfg,
+	s := &LLMService{
+		providers:    providers,
+		cfg:          cfg,
+		usage:        newLLMUsageTracker(cfg.LLMMaxRPM, cfg.LLMDailyTokenBudget),
+		summaryCache: store,
+		audit:        newLLMAuditLog(database.GetDB(), cfg.LLMAuditPercent, cfg.LLMAuditRetentionDays, cfg.LLMAuditMaxEntries),
+		invalidation: invalidation,
+		db:           database.GetDB(),
 	}
+	// Without an LLM the rules are the only way to resolve structured queries
+	if cfg.IntentRules || cfg.LLMOffline {
+		s.intentRules = newIntentRules(database.GetDB())
+	}
+	invalidation.Subscribe(InvalidationSummary, func(articleID string) {
+		s.deleteCachedSummary(articleID)
+	})
+	return s
 }
 
-// ParseIntent analyzes user query and extracts intent and entities using LLM
-func (s *LLMService) ParseIntent(query string) models.IntentResponse {
-	ctx := context.Background()
+// createChatCompletion tries each provider in order until one succeeds
+// buildReq receives the provider so it can choose the provider's model.
+// A sample of calls is recorded in the audit log.
+func (s *LLMService) createChatCompletion(ctx context.Context, purpose string, buildReq func(p *llmProvider) openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	if IsDemoTier(ctx) || !s.audit.sample() {
+		return s.completeChat(ctx, purpose, buildReq)
+	}
 
-	resp, err := s.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
-		Model: s.cfg.IntentModel,
-		Messages: []openai.ChatCompletionMessage{
-			{Role: "system", Content: prompts.IntentParsingPrompt},
-			{Role: "user", Content: query},
-		},
-		Temperature: 0.0,
-		MaxTokens:   200,
+	// The last provider tried is the one that answered or failed last
+	entry := models.LLMAudit{Purpose: purpose}
+	started := time.Now()
+	resp, err := s.completeChat(ctx, purpose, func(p *llmProvider) openai.ChatCompletionRequest {
+		req := buildReq(p)
+		entry.Provider, entry.Model, entry.Prompt = p.name, req.Model, chatTranscript(req.Messages)
+		return req
 	})
+	entry.LatencyMs = time.Since(started).Milliseconds()
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		entry.Response = resp.Choices[0].Message.Content
+		entry.TotalTokens = resp.Usage.TotalTokens
+	}
+	s.audit.record(&entry)
+	return resp, err
+}
+
+// completeChat makes the chat completion call behind createChatCompletion
+func (s *LLMService) completeChat(ctx context.Context, purpose string, buildReq func(p *llmProvider) openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	if IsDemoTier(ctx) {
+		return openai.ChatCompletionResponse{}, ErrDemoTier
+	}
+	if s.cfg.LLMOffline {
+		return openai.ChatCompletionResponse{}, ErrLLMOffline
+	}
+	if err := s.usage.acquire(ctx); err != nil {
+		return openai.ChatCompletionResponse{}, err
+	}
+
+	for _, p := range s.providers {
+		if !p.breaker.Allow() {
+			continue
+		}
+
+		callCtx, cancel := s.callContext(ctx)
+		resp, err := p.client.CreateChatCompletion(callCtx, buildReq(p))
+		cancel()
+		if err == nil && len(resp.Choices) == 0 {
+			err = errors.New("empty completion")
+		}
+		if err != nil {
+			// The caller went away; that says nothing about the provider
+			if ctx.Err() != nil {
+				p.breaker.Release()
+				return openai.ChatCompletionResponse{}, ctx.Err()
+			}
+			p.breaker.RecordFailure()
+			log.Printf("LLM provider %s failed, trying next: %v", p.name, err)
+			continue
+		}
+
+		p.breaker.RecordSuccess()
+		s.usage.record(p.name, purpose, resp.Usage.TotalTokens)
+		return resp, nil
+	}
+	return openai.ChatCompletionResponse{}, errAllProvidersFailed
+}
+
+// callContext bounds a single provider call by LLM_TIMEOUT_MS
+func (s *LLMService) callContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.cfg.LLMTimeoutMs <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Duration(s.cfg.LLMTimeoutMs)*time.Millisecond)
+}
+
+// Available reports whether summaries can currently be generated: at least
+// one provider can be called, or LLM_PROVIDER is none and they come from
+// descriptions
+func (s *LLMService) Available() bool {
+	if s.cfg.LLMOffline {
+		return true
+	}
+	for _, p := range s.providers {
+		if p.breaker.State() != BreakerOpen {
+			return true
+		}
+	}
+	return false
+}
+
+// ProviderReachability is whether a provider answered a request
+type ProviderReachability struct {
+	Name      string `json:"name"`
+	Reachable bool   `json:"reachable"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// CheckProviders lists each provider's models, which costs no tokens, to
+// tell whether it can be reached with its credentials. Breakers and usage
+// are left alone.
+func (s *LLMService) CheckProviders(ctx context.Context) []ProviderReachability {
+	results := make([]ProviderReachability, len(s.providers))
+	for i, p := range s.providers {
+		callCtx, cancel := s.callContext(ctx)
+		started := time.Now()
+		_, err := p.client.ListModels(callCtx)
+		cancel()
+		results[i] = ProviderReachability{Name: p.name, Reachable: err == nil, LatencyMs: time.Since(started).Milliseconds()}
+		if err != nil {
+			results[i].Error = err.Error()
+		}
+	}
+	return results
+}
+
+// PingProviders asks each provider's intent model for a single token, which
+// also catches model names the provider doesn't serve. Like CheckProviders
+// it bypasses breakers, usage limits and the audit log.
+func (s *LLMService) PingProviders(ctx context.Context) []ProviderReachability {
+	results := make([]ProviderReachability, len(s.providers))
+	for i, p := range s.providers {
+		callCtx, cancel := s.callContext(ctx)
+		started := time.Now()
+		_, err := p.client.CreateChatCompletion(callCtx, openai.ChatCompletionRequest{
+			Model:     p.intentModel,
+			Messages:  []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "ping"}},
+			MaxTokens: 1,
+		})
+		cancel()
+		results[i] = ProviderReachability{Name: p.name, Reachable: err == nil, LatencyMs: time.Since(started).Milliseconds()}
+		if err != nil {
+			results[i].Error = fmt.Sprintf("model %s: %v", p.intentModel, err)
+		}
+	}
+	return results
+}
+
+// Usage returns current LLM token spend and rate limit state
+func (s *LLMService) Usage() LLMUsage {
+	return s.usage.snapshot()
+}
+
+// ProviderStatuses returns the breaker state of every provider in the chain
+func (s *LLMService) ProviderStatuses() []ProviderStatus {
+	statuses := make([]ProviderStatus, len(s.providers))
+	for i, p := range s.providers {
+		statuses[i] = ProviderStatus{Name: p.name, Breaker: p.breaker.State()}
+	}
+	return statuses
+}
+
+// ParseIntent analyzes user query and extracts intent and entities using LLM
+func (s *LLMService) ParseIntent(ctx context.Context, query string) models.IntentResponse {
+	// Exact category names, "near me" and "from <source>" need no LLM
+	if s.intentRules != nil {
+		if intentResp, ok := s.intentRules.match(query); ok {
+			s.usage.recordIntentRule()
+			return intentResp
+		}
+	}
+
+	// The demo tier never spends LLM calls and LLM_PROVIDER=none has none to
+	// spend; plain search needs no parsing
+	if IsDemoTier(ctx) || s.cfg.LLMOffline {
+		return models.IntentResponse{
+			Intent:   models.IntentSearch,
+			Entities: models.Entities{"query": query},
+		}
+	}
 
+	content, err := s.completeIntent(ctx, query)
 	if err != nil {
 		log.Printf("LLM intent parsing error: %v", err)
 		// Fallback to search intent
@@ -64,8 +276,32 @@ func (s *LLMService) ParseIntent(query string) models.IntentResponse {
 			Entities: models.Entities{"query": query},
 		}
 	}
+	return parseIntentContent(query, content)
+}
 
-	content := strings.TrimSpace(resp.Choices[0].Message.Content)
+// completeIntent asks the LLM to classify query and returns its raw answer
+func (s *LLMService) completeIntent(ctx context.Context, query string) (string, error) {
+	resp, err := s.createChatCompletion(ctx, llmPurposeIntent, func(p *llmProvider) openai.ChatCompletionRequest {
+		return openai.ChatCompletionRequest{
+			Model: p.intentModel,
+			Messages: []openai.ChatCompletionMessage{
+				{Role: "system", Content: prompts.IntentParsingPromptFor(time.Now().UTC())},
+				{Role: "user", Content: query},
+			},
+			Temperature: 0.0,
+			MaxTokens:   200,
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+// parseIntentContent turns the LLM's answer to query into an intent
+// response, falling back to search when the answer is unusable
+func parseIntentContent(query, content string) models.IntentResponse {
+	content = strings.TrimSpace(content)
 
 	// Clean up markdown code blocks if present
 	content = strings.TrimPrefix(content, "```json")
@@ -85,11 +321,12 @@ func (s *LLMService) ParseIntent(query string) models.IntentResponse {
 
 	// Validate intent
 	validIntents := map[string]bool{
-		models.IntentCategory: true,
-		models.IntentSource:   true,
-		models.IntentSearch:   true,
-		models.IntentNearby:   true,
-		models.IntentScore:    true,
+		models.IntentCategory:  true,
+		models.IntentSource:    true,
+		models.IntentSearch:    true,
+		models.IntentNearby:    true,
+		models.IntentScore:     true,
+		models.IntentDiscovery: true,
 	}
 
 	if !validIntents[intentResp.Intent] {
@@ -102,74 +339,519 @@ func (s *LLMService) ParseIntent(query string) models.IntentResponse {
 		intentResp.Entities = make(models.Entities)
 	}
 
-	// Add query to entities if not present
-	if _, ok := intentResp.Entities["query"]; !ok {
+	// Add query to entities if not present; discovery has no topic to search for
+	if intentResp.Intent == models.IntentDiscovery {
+		intentResp.Entities["query"] = ""
+	} else if _, ok := intentResp.Entities["query"]; !ok {
 		intentResp.Entities["query"] = query
 	}
 
 	return intentResp
 }
 
+// RefineIntent resolves a follow-up query in a conversation into the
+// complete intent after applying it to the previous one. Without the LLM
+// (demo tier, LLM_PROVIDER=none, failures), a follow-up naming a known
+// source or category narrows the previous intent and anything else is
+// parsed on its own.
+func (s *LLMService) RefineIntent(ctx context.Context, query string, previous models.IntentResponse) models.IntentResponse {
+	if IsDemoTier(ctx) || s.cfg.LLMOffline {
+		return s.refineWithRules(ctx, query, previous)
+	}
+
+	prior, err := json.Marshal(previous)
+	if err != nil {
+		return s.refineWithRules(ctx, query, previous)
+	}
+	resp, err := s.createChatCompletion(ctx, llmPurposeIntent, func(p *llmProvider) openai.ChatCompletionRequest {
+		return openai.ChatCompletionRequest{
+			Model: p.intentModel,
+			Messages: []openai.ChatCompletionMessage{
+				{Role: "system", Content: prompts.FollowUpIntentPromptFor(time.Now().UTC())},
+				{Role: "user", Content: fmt.Sprintf("Previous: %s\nFollow-up: %q", prior, query)},
+			},
+			Temperature: 0.0,
+			MaxTokens:   250,
+		}
+	})
+	if err != nil {
+		log.Printf("LLM follow-up parsing error: %v", err)
+		return s.refineWithRules(ctx, query, previous)
+	}
+
+	// Search terms the LLM leaves out carry over from the previous turn
+	previousQuery, _ := previous.Entities["query"].(string)
+	return parseIntentContent(previousQuery, resp.Choices[0].Message.Content)
+}
+
+// refineWithRules narrows the previous intent to the source or category a
+// follow-up names, or parses the follow-up on its own
+func (s *LLMService) refineWithRules(ctx context.Context, query string, previous models.IntentResponse) models.IntentResponse {
+	if s.intentRules != nil {
+		match, ok := s.intentRules.match(query)
+		if ok && (match.Intent == models.IntentSource || match.Intent == models.IntentCategory) {
+			s.usage.recordIntentRule()
+			refined := models.IntentResponse{Intent: previous.Intent, Entities: make(models.Entities, len(previous.Entities)+1)}
+			for key, value := range previous.Entities {
+				refined.Entities[key] = value
+			}
+			// The source and category entities are named after their intents
+			refined.Entities[match.Intent] = match.Entities[match.Intent]
+			return refined
+		}
+	}
+	return s.ParseIntent(ctx, query)
+}
+
+// Summary placeholders returned when no summary could be generated
+const (
+	summaryInsufficientContent = "Summary unavailable - insufficient content."
+	summaryUnavailable         = "Summary unavailable."
+)
+
+// summaryMaxInput is how much of an article's text a summary request sends
+const summaryMaxInput = 4000
+
+// SummaryText is the text an article is summarized from: the body the
+// ContentWorker fetched from its page when there is one, else its description
+func (s *LLMService) SummaryText(article *models.Article) string {
+	if body := s.fetchedBodies([]string{article.ID})[article.ID]; body != "" {
+		return body
+	}
+	return article.Description
+}
+
+// fetchedBodies loads the bodies the ContentWorker fetched for ids in one
+// query, by article ID
+func (s *LLMService) fetchedBodies(ids []string) map[string]string {
+	var contents []models.ArticleContent
+	err := s.db.Select("article_id", "body").
+		Where("article_id IN ? AND status = ? AND body <> ''", ids, models.ContentStatusFetched).
+		Find(&contents).Error
+	if err != nil {
+		log.Printf("Failed to load content of %d articles: %v", len(ids), err)
+	}
+	bodies := make(map[string]string, len(contents))
+	for _, content := range contents {
+		bodies[content.ArticleID] = content.Body
+	}
+	return bodies
+}
+
 // GenerateSummary creates a concise summary of article content using LLM
-func (s *LLMService) GenerateSummary(articleID, text string) string {
+func (s *LLMService) GenerateSummary(ctx context.Context, articleID, text string) string {
+	summary, err := s.TryGenerateSummary(ctx, articleID, text)
+	if err != nil {
+		log.Printf("LLM summarization error for article %s: %v", articleID, err)
+		return summaryUnavailable
+	}
+	return summary
+}
+
+// TryGenerateSummary is like GenerateSummary but reports LLM failures as errors
+// instead of a placeholder, so callers can decide whether to persist the result.
+// The summary is written in the tone requested for ctx (see WithSummaryTone).
+// With LLM_PROVIDER=none it is the start of text, in no particular tone.
+func (s *LLMService) TryGenerateSummary(ctx context.Context, articleID, text string) (string, error) {
+	if s.cfg.LLMOffline {
+		return offlineSummary(text), nil
+	}
+	tone := SummaryTone(ctx)
+
 	// Check cache first
-	if cached, ok := s.summaryCache.Load(articleID); ok {
-		return cached.(string)
+	if cached, ok := s.cachedSummary(ctx, articleID, tone); ok {
+		return cached, nil
 	}
 
 	// Validate input
 	if len(text) < 20 {
-		return "Summary unavailable - insufficient content."
+		return summaryInsufficientContent, nil
 	}
 
 	// Truncate very long text to save tokens
-	if len(text) > 1000 {
-		text = text[:1000]
+	if len(text) > summaryMaxInput {
+		text = text[:summaryMaxInput]
+	}
+
+	resp, err := s.createChatCompletion(ctx, llmPurposeSummary, func(p *llmProvider) openai.ChatCompletionRequest {
+		return summaryRequest(p, tone, text)
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	summary := strings.TrimSpace(resp.Choices[0].Message.Content)
+
+	// Cache the summary; an empty one is worth another try
+	if summary != "" {
+		s.cacheSummary(articleID, tone, summary)
 	}
 
-	ctx := context.Background()
+	return summary, nil
+}
+
+// offlineSummary stands in for an LLM summary of text when LLM_PROVIDER is
+// none: its first offlineSummaryLength bytes
+func offlineSummary(text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return summaryInsufficientContent
+	}
+	return truncateText(text, offlineSummaryLength)
+}
 
-	resp, err := s.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
-		Model: s.cfg.SummaryModel,
+// summaryRequest builds the request summarizing text in a tone
+func summaryRequest(p *llmProvider, tone, text string) openai.ChatCompletionRequest {
+	maxTokens, ok := summaryToneMaxTokens[tone]
+	if !ok {
+		maxTokens = defaultSummaryMaxTokens
+	}
+	return openai.ChatCompletionRequest{
+		Model: p.summaryModel,
 		Messages: []openai.ChatCompletionMessage{
-			{Role: "system", Content: prompts.SummaryPrompt},
+			{Role: "system", Content: prompts.SummaryPromptFor(tone)},
 			{Role: "user", Content: text},
 		},
 		Temperature: 0.3,
-		MaxTokens:   100,
-	})
+		MaxTokens:   maxTokens,
+	}
+}
 
+// summaryCachePrefix namespaces summaries in the cache
+const summaryCachePrefix = "summary:"
+
+// summaryCacheTTL bounds how long an unchanged article's summary is kept
+const summaryCacheTTL = 7 * 24 * time.Hour
+
+// cachedSummary returns the cached summary for an article in a tone. Cache
+// errors are logged and treated as a miss.
+func (s *LLMService) cachedSummary(ctx context.Context, articleID, tone string) (string, bool) {
+	summary, ok, err := s.summaryCache.Get(ctx, summaryCacheKey(articleID, tone))
 	if err != nil {
-		log.Printf("LLM summarization error for article %s: %v", articleID, err)
-		return "Summary unavailable."
+		log.Printf("Failed to read cached summary for article %s: %v", articleID, err)
+		return "", false
 	}
+	return string(summary), ok
+}
 
-	summary := strings.TrimSpace(resp.Choices[0].Message.Content)
+// CacheSummary stores an already generated (e.g. persisted) neutral summary
+func (s *LLMService) CacheSummary(articleID, summary string) {
+	s.cacheSummary(articleID, models.SummaryToneNeutral, summary)
+}
+
+// cacheSummary stores an article's summary in a tone
+func (s *LLMService) cacheSummary(articleID, tone, summary string) {
+	err := s.summaryCache.Set(context.Background(), summaryCacheKey(articleID, tone), []byte(summary), summaryCacheTTL)
+	if err != nil {
+		log.Printf("Failed to cache summary for article %s: %v", articleID, err)
+	}
+}
 
-	// Cache the summary
-	s.summaryCache.Store(articleID, summary)
+// InvalidateSummary drops the cached summary for an article on every instance
+func (s *LLMService) InvalidateSummary(articleID string) {
+	s.deleteCachedSummary(articleID)
+	s.invalidation.Publish(InvalidationSummary, articleID)
+}
 
-	return summary
+// deleteCachedSummary drops an article's summaries in every tone from the cache
+func (s *LLMService) deleteCachedSummary(articleID string) {
+	keys := make([]string, len(summaryTones))
+	for i, tone := range summaryTones {
+		keys[i] = summaryCacheKey(articleID, tone)
+	}
+	if _, err := s.summaryCache.Delete(context.Background(), keys...); err != nil {
+		log.Printf("Failed to drop cached summary for article %s: %v", articleID, err)
+	}
 }
 
-// GenerateSummariesBatch generates summaries for multiple articles concurrently
-func (s *LLMService) GenerateSummariesBatch(articles []models.Article) {
+// GenerateSummariesBatch generates summaries for multiple articles concurrently,
+// in the tone requested for ctx. Articles still queued when ctx is done, or
+// missing a cached summary on a demo-tier request, keep the summary they have
+// (the persisted neutral one, if any). With LLM_PROVIDER=none, articles
+// without a persisted summary get the start of their description. Articles
+// whose summary was blocklisted get none. Cached summaries are used first,
+// and the texts of the rest are loaded in one query.
+func (s *LLMService) GenerateSummariesBatch(ctx context.Context, articles []models.Article) {
 	var wg sync.WaitGroup
 	semaphore := make(chan struct{}, 5) // Limit concurrent LLM calls
+	tone := SummaryTone(ctx)
+	var pending []int // articles to summarize
 
 	for i := range articles {
+		if articles[i].SummaryBlocked {
+			articles[i].LLMSummary = ""
+			continue
+		}
+		// Summaries persisted by the pre-generation worker need no LLM call
+		if articles[i].LLMSummary != "" && tone == models.SummaryToneNeutral {
+			continue
+		}
+		if s.cfg.LLMOffline {
+			if articles[i].LLMSummary == "" {
+				articles[i].LLMSummary = offlineSummary(articles[i].Description)
+			}
+			continue
+		}
+		if cached, ok := s.cachedSummary(ctx, articles[i].ID, tone); ok {
+			articles[i].LLMSummary = cached
+			continue
+		}
+		// The demo tier only gets summaries that already exist
+		if IsDemoTier(ctx) {
+			continue
+		}
+		pending = append(pending, i)
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	// The remaining articles' texts are loaded together
+	ids := make([]string, len(pending))
+	for j, i := range pending {
+		ids[j] = articles[i].ID
+	}
+	bodies := s.fetchedBodies(ids)
+
+	for _, i := range pending {
+		text := bodies[articles[i].ID]
+		if text == "" {
+			text = articles[i].Description
+		}
 		wg.Add(1)
-		go func(idx int) {
+		go func(idx int, text string) {
 			defer wg.Done()
-			semaphore <- struct{}{}        // Acquire
+			select {
+			case semaphore <- struct{}{}: // Acquire
+			case <-ctx.Done():
+				return
+			}
 			defer func() { <-semaphore }() // Release
 
-			articles[idx].LLMSummary = s.GenerateSummary(
-				articles[idx].ID,
-				articles[idx].Description,
-			)
-		}(i)
+			summary, err := s.TryGenerateSummary(ctx, articles[idx].ID, text)
+			if err != nil {
+				log.Printf("LLM summarization error for article %s: %v", articles[idx].ID, err)
+				// Fall back to the neutral summary rather than a placeholder
+				if articles[idx].LLMSummary != "" {
+					return
+				}
+				summary = summaryUnavailable
+			}
+			articles[idx].LLMSummary = summary
+		}(i, text)
 	}
 
 	wg.Wait()
 }
+
+// sentimentAnswer is the JSON the sentiment prompt asks for
+type sentimentAnswer struct {
+	Sentiment string `json:"sentiment"`
+	Tone      string `json:"tone"`
+}
+
+// ClassifySentiment asks the LLM for an article's sentiment and tone
+func (s *LLMService) ClassifySentiment(ctx context.Context, text string) (string, string, error) {
+	if len(text) > 1000 {
+		text = text[:1000]
+	}
+
+	resp, err := s.createChatCompletion(ctx, llmPurposeSentiment, func(p *llmProvider) openai.ChatCompletionRequest {
+		return openai.ChatCompletionRequest{
+			Model: p.intentModel,
+			Messages: []openai.ChatCompletionMessage{
+				{Role: "system", Content: prompts.SentimentPrompt},
+				{Role: "user", Content: text},
+			},
+			Temperature: 0.0,
+			MaxTokens:   30,
+		}
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	content := strings.TrimSpace(resp.Choices[0].Message.Content)
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimSuffix(content, "```")
+
+	var answer sentimentAnswer
+	if err := json.Unmarshal([]byte(strings.TrimSpace(content)), &answer); err != nil {
+		return "", "", fmt.Errorf("failed to parse sentiment response %q: %w", content, err)
+	}
+	answer.Sentiment = strings.ToLower(strings.TrimSpace(answer.Sentiment))
+	answer.Tone = strings.ToLower(strings.TrimSpace(answer.Tone))
+	if !utils.IsSentiment(answer.Sentiment) || !utils.IsTone(answer.Tone) {
+		return "", "", fmt.Errorf("invalid sentiment response %q", content)
+	}
+	return answer.Sentiment, answer.Tone, nil
+}
+
+// ExtractTopics asks the LLM for an article's topics, most important first
+func (s *LLMService) ExtractTopics(ctx context.Context, text string) ([]string, error) {
+	if len(text) > 1000 {
+		text = text[:1000]
+	}
+
+	resp, err := s.createChatCompletion(ctx, llmPurposeTopics, func(p *llmProvider) openai.ChatCompletionRequest {
+		return openai.ChatCompletionRequest{
+			Model: p.intentModel,
+			Messages: []openai.ChatCompletionMessage{
+				{Role: "system", Content: prompts.TopicPrompt},
+				{Role: "user", Content: text},
+			},
+			Temperature: 0.0,
+			MaxTokens:   80,
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	content := strings.TrimSpace(resp.Choices[0].Message.Content)
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimSuffix(content, "```")
+
+	var topics []string
+	if err := json.Unmarshal([]byte(strings.TrimSpace(content)), &topics); err != nil {
+		return nil, fmt.Errorf("failed to parse topics response %q: %w", content, err)
+	}
+	return topics, nil
+}
+
+// GenerateDigest asks the LLM for a multi-paragraph briefing covering the
+// articles, most important first
+func (s *LLMService) GenerateDigest(ctx context.Context, articles []models.Article) (string, error) {
+	var b strings.Builder
+	for i := range articles {
+		fmt.Fprintf(&b, "%d. %s (%s)\n%s\n\n", i+1, articles[i].Title, articles[i].SourceName, articles[i].Description)
+	}
+
+	resp, err := s.createChatCompletion(ctx, llmPurposeDigest, func(p *llmProvider) openai.ChatCompletionRequest {
+		return openai.ChatCompletionRequest{
+			Model: p.summaryModel,
+			Messages: []openai.ChatCompletionMessage{
+				{Role: "system", Content: prompts.DigestPrompt},
+				{Role: "user", Content: b.String()},
+			},
+			Temperature: 0.4,
+			MaxTokens:   700,
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(resp.Choices[0].Message.Content), nil
+}
+
+// answerContent is the JSON the answer prompt asks for
+type answerContent struct {
+	Answer    string `json:"answer"`
+	Citations []int  `json:"citations"`
+}
+
+// AnswerQuestion asks the LLM to answer a question from the articles only.
+// It returns the answer and the indexes of the articles it cites, without
+// duplicates or numbers outside the list.
+func (s *LLMService) AnswerQuestion(ctx context.Context, question string, articles []models.Article) (string, []int, error) {
+	var b strings.Builder
+	for i := range articles {
+		fmt.Fprintf(&b, "[%d] %s (%s, %s)\n%s\n\n", i+1, articles[i].Title, articles[i].SourceName,
+			articles[i].PublicationDate.UTC().Format("2006-01-02"), articles[i].Description)
+	}
+	fmt.Fprintf(&b, "Question: %s", question)
+
+	resp, err := s.createChatCompletion(ctx, llmPurposeAnswer, func(p *llmProvider) openai.ChatCompletionRequest {
+		return openai.ChatCompletionRequest{
+			Model: p.summaryModel,
+			Messages: []openai.ChatCompletionMessage{
+				{Role: "system", Content: prompts.AnswerPrompt},
+				{Role: "user", Content: b.String()},
+			},
+			Temperature: 0.0,
+			MaxTokens:   400,
+		}
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	content := strings.TrimSpace(resp.Choices[0].Message.Content)
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimSuffix(content, "```")
+
+	var answer answerContent
+	if err := json.Unmarshal([]byte(strings.TrimSpace(content)), &answer); err != nil {
+		return "", nil, fmt.Errorf("failed to parse answer response %q: %w", content, err)
+	}
+	if answer.Answer = strings.TrimSpace(answer.Answer); answer.Answer == "" {
+		return "", nil, fmt.Errorf("empty answer response %q", content)
+	}
+
+	seen := make(map[int]bool)
+	cited := []int{}
+	for _, n := range answer.Citations {
+		if n >= 1 && n <= len(articles) && !seen[n] {
+			seen[n] = true
+			cited = append(cited, n-1)
+		}
+	}
+	return answer.Answer, cited, nil
+}
+
+// CreateEmbeddings returns one embedding vector per input text
+func (s *LLMService) CreateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	if IsDemoTier(ctx) {
+		return nil, ErrDemoTier
+	}
+	if err := s.usage.acquire(ctx); err != nil {
+		return nil, err
+	}
+
+	var resp openai.EmbeddingResponse
+	err := errAllProvidersFailed
+	for _, p := range s.providers {
+		if !p.breaker.Allow() {
+			continue
+		}
+		callCtx, cancel := s.callContext(ctx)
+		resp, err = p.client.CreateEmbeddings(callCtx, openai.EmbeddingRequest{
+			Input: texts,
+			Model: openai.EmbeddingModel(s.cfg.EmbeddingModel),
+		})
+		cancel()
+		// Not every provider offers embeddings, so the outcome doesn't move the breaker
+		p.breaker.Release()
+		if err == nil {
+			s.usage.record(p.name, llmPurposeEmbedding, resp.Usage.TotalTokens)
+			break
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		log.Printf("Embedding request to %s failed, trying next: %v", p.name, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("embedding request failed: %w", err)
+	}
+
+	if len(resp.Data) != len(texts) {
+		return nil, fmt.Errorf("embedding count mismatch: got %d, expected %d", len(resp.Data), len(texts))
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, item := range resp.Data {
+		if item.Index < 0 || item.Index >= len(texts) {
+			return nil, fmt.Errorf("embedding index %d out of range for %d inputs", item.Index, len(texts))
+		}
+		if vectors[item.Index] != nil {
+			return nil, fmt.Errorf("duplicate embedding index %d", item.Index)
+		}
+		vectors[item.Index] = item.Embedding
+	}
+	return vectors, nil
+}
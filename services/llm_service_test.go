@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"news-backend/config"
+	"news-backend/models"
+
+	"gorm.io/gorm"
+)
+
+func TestGenerateSummariesBatchLoadsTextsOnce(t *testing.T) {
+	db := openTestDB(t)
+	var mu sync.Mutex
+	var prompts []string
+	llmService, calls := newTestLLMServiceFunc(t, &config.Config{}, func(request string) (string, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		prompts = append(prompts, request)
+		return "A simple summary.", true
+	})
+
+	now := time.Now()
+	articles := []models.Article{
+		{ID: "cached", Title: "Cached", URL: "https://example.com/cached", PublicationDate: now,
+			Description: "A description that was summarized before."},
+		{ID: "fetched", Title: "Fetched", URL: "https://example.com/fetched", PublicationDate: now,
+			Description: "A description standing in for the page."},
+		{ID: "described", Title: "Described", URL: "https://example.com/described", PublicationDate: now,
+			Description: "A description with no page fetched for it."},
+	}
+	if err := db.Create(&articles).Error; err != nil {
+		t.Fatalf("failed to create articles: %v", err)
+	}
+	content := models.ArticleContent{ArticleID: "fetched", Status: models.ContentStatusFetched,
+		Body: "The body of the page the content worker fetched."}
+	if err := db.Create(&content).Error; err != nil {
+		t.Fatalf("failed to store content: %v", err)
+	}
+	llmService.cacheSummary("cached", models.SummaryToneSimple, "A cached summary.")
+
+	var queries atomic.Int32
+	db.Callback().Query().After("gorm:query").Register("test:count_queries", func(*gorm.DB) { queries.Add(1) })
+	t.Cleanup(func() { db.Callback().Query().Remove("test:count_queries") })
+
+	ctx := WithSummaryTone(context.Background(), models.SummaryToneSimple)
+	llmService.GenerateSummariesBatch(ctx, articles)
+
+	if n := queries.Load(); n != 1 {
+		t.Errorf("GenerateSummariesBatch() ran %d queries, expected the texts loaded in one", n)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("GenerateSummariesBatch() made %d LLM calls, expected none for the cached summary", n)
+	}
+	if articles[0].LLMSummary != "A cached summary." {
+		t.Errorf("cached article summary = %q, expected the cached one", articles[0].LLMSummary)
+	}
+	var fromBody, fromDescription bool
+	for _, prompt := range prompts {
+		fromBody = fromBody || strings.Contains(prompt, "The body of the page")
+		fromDescription = fromDescription || strings.Contains(prompt, "no page fetched")
+	}
+	if !fromBody || !fromDescription {
+		t.Errorf("summarized from body %v, description %v, expected the fetched body and the other's description", fromBody, fromDescription)
+	}
+
+	// Fully cached batches don't touch the database
+	queries.Store(0)
+	llmService.GenerateSummariesBatch(ctx, articles)
+	if n := queries.Load(); n != 0 {
+		t.Errorf("cached GenerateSummariesBatch() ran %d queries, expected none", n)
+	}
+}
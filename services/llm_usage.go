@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"news-backend/utils"
+)
+
+// LLM call purposes used for usage accounting
+const (
+	llmPurposeIntent    = "intent"
+	llmPurposeSummary   = "summary"
+	llmPurposeEmbedding = "embedding"
+	llmPurposeSentiment = "sentiment"
+	llmPurposeTopics    = "topics"
+	llmPurposeDigest    = "digest"
+	llmPurposeAnswer    = "answer"
+)
+
+var (
+	errLLMRateLimited     = errors.New("LLM request rate limit reached")
+	errLLMBudgetExhausted = errors.New("LLM daily token budget exhausted")
+)
+
+// maxRateLimitWait bounds how long a call waits for a rate limit token
+const maxRateLimitWait = 2 * time.Second
+
+// LLMUsage reports current LLM spend against configured limits
+type LLMUsage struct {
+	Date              string         `json:"date"`
+	TokensUsed        int            `json:"tokens_used"`
+	DailyTokenBudget  int            `json:"daily_token_budget"` // 0 = unlimited
+	TokensRemaining   int            `json:"tokens_remaining,omitempty"`
+	Requests          int            `json:"requests"`
+	RateLimited       int            `json:"rate_limited"`
+	BudgetRejected    int            `json:"budget_rejected"`
+	IntentRuleHits    int            `json:"intent_rule_hits"` // Intents resolved without the LLM
+	MaxRPM            int            `json:"max_rpm"`          // 0 = unlimited
+	AvailableRequests float64        `json:"available_requests,omitempty"`
+	TokensByProvider  map[string]int `json:"tokens_by_provider"`
+	TokensByPurpose   map[string]int `json:"tokens_by_purpose"`
+}
+
+// llmUsageTracker enforces request rate and daily token budget for LLM calls
+type llmUsageTracker struct {
+	mu          sync.Mutex
+	limiter     *utils.TokenBucket // nil when unlimited
+	maxRPM      int
+	dailyBudget int
+	usage       LLMUsage
+}
+
+// newLLMUsageTracker creates a tracker; zero limits disable enforcement
+func newLLMUsageTracker(maxRPM, dailyBudget int) *llmUsageTracker {
+	t := &llmUsageTracker{
+		maxRPM:      maxRPM,
+		dailyBudget: dailyBudget,
+	}
+	if maxRPM > 0 {
+		t.limiter = utils.NewTokenBucket(float64(maxRPM), float64(maxRPM)/60.0)
+	}
+	t.resetIfNewDay()
+	return t
+}
+
+// acquire checks the daily budget and waits (bounded) for a rate limit token
+func (t *llmUsageTracker) acquire(ctx context.Context) error {
+	t.mu.Lock()
+	t.resetIfNewDay()
+	if t.dailyBudget > 0 && t.usage.TokensUsed >= t.dailyBudget {
+		t.usage.BudgetRejected++
+		t.mu.Unlock()
+		return errLLMBudgetExhausted
+	}
+	t.mu.Unlock()
+
+	if t.limiter == nil || t.limiter.Allow() {
+		return nil
+	}
+
+	wait := t.limiter.WaitTime()
+	if wait <= maxRateLimitWait {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+		if t.limiter.Allow() {
+			return nil
+		}
+	}
+
+	t.mu.Lock()
+	t.usage.RateLimited++
+	t.mu.Unlock()
+	return errLLMRateLimited
+}
+
+// record adds the tokens consumed by a completed call
+func (t *llmUsageTracker) record(provider, purpose string, tokens int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.resetIfNewDay()
+	t.usage.Requests++
+	t.usage.TokensUsed += tokens
+	t.usage.TokensByProvider[provider] += tokens
+	t.usage.TokensByPurpose[purpose] += tokens
+}
+
+// recordIntentRule counts an intent resolved by the rules fast path
+func (t *llmUsageTracker) recordIntentRule() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.resetIfNewDay()
+	t.usage.IntentRuleHits++
+}
+
+// snapshot returns a copy of the current usage
+func (t *llmUsageTracker) snapshot() LLMUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.resetIfNewDay()
+	usage := t.usage
+	usage.TokensByProvider = copyCounts(t.usage.TokensByProvider)
+	usage.TokensByPurpose = copyCounts(t.usage.TokensByPurpose)
+	usage.DailyTokenBudget = t.dailyBudget
+	usage.MaxRPM = t.maxRPM
+	if t.dailyBudget > 0 {
+		usage.TokensRemaining = t.dailyBudget - t.usage.TokensUsed
+		if usage.TokensRemaining < 0 {
+			usage.TokensRemaining = 0
+		}
+	}
+	if t.limiter != nil {
+		usage.AvailableRequests = t.limiter.Tokens()
+	}
+	return usage
+}
+
+// resetIfNewDay starts a fresh accounting period at UTC midnight (lock must be held)
+func (t *llmUsageTracker) resetIfNewDay() {
+	today := time.Now().UTC().Format("2006-01-02")
+	if t.usage.Date == today {
+		return
+	}
+	t.usage = LLMUsage{
+		Date:             today,
+		TokensByProvider: make(map[string]int),
+		TokensByPurpose:  make(map[string]int),
+	}
+}
+
+// copyCounts returns a copy of a counter map
+func copyCounts(counts map[string]int) map[string]int {
+	copied := make(map[string]int, len(counts))
+	for k, v := range counts {
+		copied[k] = v
+	}
+	return copied
+}
@@ -1,6 +1,11 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
 	"time"
 
 	"news-backend/config"
@@ -12,38 +17,125 @@ import (
 )
 
 type NewsService struct {
-	db         *gorm.DB
-	cfg        *config.Config
-	llmService *LLMService
+	db               *gorm.DB
+	cfg              *config.Config
+	llmService       *LLMService
+	embeddingService *EmbeddingService
+	userService      *UserService
+	searchFields     []string // SEARCH_FIELDS, validated at startup
 }
 
+// Search modes
+const (
+	SearchModeKeyword = "keyword"
+	SearchModeHybrid  = "hybrid"
+)
+
 // FetchResult contains articles and metadata about the fetch operation
 type FetchResult struct {
 	Articles       []models.Article
-	TotalAvailable int // Total matching articles before limiting
+	TotalAvailable int                 // Total matching articles before limiting
+	Facets         *models.Facets      // Counts over all matching articles, when requested
+	Ranking        *models.RankingInfo // Profile applied, when one was requested
+	Page           int                 // 1-based page returned
+	PageSize       int                 // Articles per page
 }
 
 // FetchParams contains parameters for fetching articles
 type FetchParams struct {
-	Intent   string
-	Entities models.Entities
-	Lat      float64
-	Lon      float64
-	Radius   float64
+	Intent    string
+	Entities  models.Entities
+	Lat       float64
+	Lon       float64
+	Radius    float64
+	Mode      string // SearchModeKeyword (default) or SearchModeHybrid
+	Facets    bool   // Compute facet counts over the full matching set
+	Dates     DateRange
+	Ranking   RankingOptions // Overrides the intent's ordering when a profile is set
+	Sentiment string         // Only articles tagged with this sentiment; empty for all
+	// Article text the query is matched against; empty for SEARCH_FIELDS
+	SearchFields []string
+	// Only articles from this source or in this category, whatever the
+	// intent; empty for all
+	Source   string
+	Category string
+	// Only articles from sources rated at least this reliable; 0 for all
+	MinSourceScore float64
+	// Also match articles the ArchiveWorker moved out of the articles table
+	IncludeArchived bool
+	// Hidden from the results; FetchArticlesWithMetadata fills it in from
+	// the context when nil
+	Mutes *MuteSet
+	Paging
+}
+
+// Paging selects a page of results
+type Paging struct {
+	Limit int // articles per page; 0 for MAX_ARTICLES
+	Page  int // 1-based; 0 for the first
+}
+
+// DateRange bounds publication_date; zero bounds are open
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// IsZero reports whether neither bound is set
+func (d DateRange) IsZero() bool {
+	return d.From.IsZero() && d.To.IsZero()
+}
+
+// apply restricts an article query to the range
+func (d DateRange) apply(query *gorm.DB) *gorm.DB {
+	switch {
+	case !d.From.IsZero() && !d.To.IsZero():
+		return query.Where("publication_date BETWEEN ? AND ?", d.From, d.To)
+	case !d.From.IsZero():
+		return query.Where("publication_date >= ?", d.From)
+	case !d.To.IsZero():
+		return query.Where("publication_date <= ?", d.To)
+	default:
+		return query
+	}
+}
+
+// withEntityDefaults fills unset bounds from LLM-extracted "from"/"to"
+// entities (e.g. resolved from "last week"); explicit bounds win
+func (d DateRange) withEntityDefaults(entities models.Entities) DateRange {
+	if d.From.IsZero() {
+		if value, ok := entities["from"].(string); ok {
+			if from, err := utils.ParseDate(value, false); err == nil {
+				d.From = from
+			}
+		}
+	}
+	if d.To.IsZero() {
+		if value, ok := entities["to"].(string); ok {
+			if to, err := utils.ParseDate(value, true); err == nil {
+				d.To = to
+			}
+		}
+	}
+	return d
 }
 
 // NewNewsService creates a new news service instance
-func NewNewsService(cfg *config.Config, llmService *LLMService) *NewsService {
+func NewNewsService(cfg *config.Config, llmService *LLMService, embeddingService *EmbeddingService, userService *UserService) *NewsService {
+	searchFields, _ := ParseSearchFields(cfg.SearchFields)
 	return &NewsService{
-		db:         database.GetDB(),
-		cfg:        cfg,
-		llmService: llmService,
+		db:               database.GetDB(),
+		cfg:              cfg,
+		llmService:       llmService,
+		embeddingService: embeddingService,
+		userService:      userService,
+		searchFields:     searchFields,
 	}
 }
 
 // FetchArticles retrieves articles based on intent and entities
-func (s *NewsService) FetchArticles(intent string, entities models.Entities, lat, lon, radius float64) ([]models.Article, error) {
-	result, err := s.FetchArticlesWithMetadata(FetchParams{
+func (s *NewsService) FetchArticles(ctx context.Context, intent string, entities models.Entities, lat, lon, radius float64) ([]models.Article, error) {
+	result, err := s.FetchArticlesWithMetadata(ctx, FetchParams{
 		Intent:   intent,
 		Entities: entities,
 		Lat:      lat,
@@ -56,17 +148,79 @@ func (s *NewsService) FetchArticles(intent string, entities models.Entities, lat
 	return result.Articles, nil
 }
 
-// FetchArticlesWithMetadata retrieves articles with total count metadata
-func (s *NewsService) FetchArticlesWithMetadata(params FetchParams) (*FetchResult, error) {
-	articles, sortType, err := s.fetchArticlesByIntent(params)
+// FetchArticlesWithMetadata retrieves articles with total count metadata,
+// leaving out what the requesting user muted
+func (s *NewsService) FetchArticlesWithMetadata(ctx context.Context, params FetchParams) (*FetchResult, error) {
+	if params.Mutes == nil {
+		params.Mutes = Mutes(ctx)
+	}
+	limit := params.Limit
+	if limit <= 0 {
+		limit = s.cfg.MaxArticlesReturn
+	}
+	limit = TierLimit(ctx, limit)
+	page := max(params.Page, 1)
+	offset := (page - 1) * limit
+
+	// Listings by date or score are ordered, collapsed and paged in SQL, so
+	// only the returned page is loaded
+	var result *FetchResult
+	var err error
+	query, order, listed := s.listQuery(s.filterQuery(params), params)
+	if listed && !params.rankedInMemory() {
+		result, err = s.fetchListPage(query, order, limit, offset, params)
+	} else {
+		result, err = s.fetchRanked(ctx, params, limit, offset)
+	}
 	if err != nil {
 		return nil, err
 	}
+	result.Page, result.PageSize = page, limit
+	return result, nil
+}
 
-	// Apply sorting based on intent
-	s.applySorting(articles, sortType, params)
+// fetchRanked loads every matching article and orders them in memory, for
+// rankings SQL can't compute (text relevance, distance, hybrid scores and
+// ranking profiles), then cuts out the page
+func (s *NewsService) fetchRanked(ctx context.Context, params FetchParams, limit, offset int) (*FetchResult, error) {
+	articles, sortType, err := s.fetchArticlesByIntent(params, offset+limit)
+	if err != nil {
+		return nil, err
+	}
 
-	return s.limitArticlesWithTotal(articles), nil
+	// Apply sorting based on intent, or blend all signals in hybrid mode. A
+	// ranking profile replaces both.
+	var ranking *models.RankingInfo
+	switch {
+	case params.Ranking.Profile != "":
+		ranking = s.applyRanking(articles, params)
+	case params.Mode == SearchModeHybrid && params.Intent != models.IntentDiscovery:
+		articles = s.applyHybridSorting(ctx, articles, params, offset+limit)
+	default:
+		s.applySorting(articles, sortType, params)
+	}
+
+	// Show one article per story so near-duplicates don't crowd the results
+	if s.cfg.StoryCollapse {
+		articles = collapseStories(articles)
+	}
+
+	result := pageArticles(articles, limit, offset)
+	result.Ranking = ranking
+	if params.Facets {
+		facets, err := s.computeFacets(articleIDs(articles), params.IncludeArchived)
+		if err != nil {
+			return nil, err
+		}
+		result.Facets = facets
+	}
+	return result, nil
+}
+
+// rankedInMemory reports whether the results are ordered by a ranking
+// profile or hybrid scores, which SQL can't compute
+func (p FetchParams) rankedInMemory() bool {
+	return p.Ranking.Profile != "" || (p.Mode == SearchModeHybrid && p.Intent != models.IntentDiscovery)
 }
 
 // sortType defines how articles should be sorted
@@ -79,17 +233,37 @@ const (
 	sortBySearchRelevance
 )
 
-// fetchArticlesByIntent retrieves articles based on intent and returns the appropriate sort type
-func (s *NewsService) fetchArticlesByIntent(params FetchParams) ([]models.Article, sortType, error) {
-	query := s.db.Model(&models.Article{})
+// filterQuery returns an article query with the filters every intent
+// shares: dates, sentiment, source, category, source reliability and mutes
+func (s *NewsService) filterQuery(params FetchParams) *gorm.DB {
+	query := params.Dates.apply(s.articleTable(params.IncludeArchived))
+	if params.Sentiment != "" {
+		query = query.Where("sentiment = ?", params.Sentiment)
+	}
+	if params.Source != "" {
+		query = query.Where("LOWER(source_name) = ?", strings.ToLower(strings.TrimSpace(params.Source)))
+	}
+	if params.Category != "" {
+		query = query.Where("id IN (?)", s.categoryArticleIDs(params.Category))
+	}
+	if params.MinSourceScore > 0 {
+		query = filterMinSourceScore(s.db, s.cfg, query, params.MinSourceScore)
+	}
+	return params.Mutes.apply(s.db, query)
+}
+
+// fetchArticlesByIntent retrieves articles based on intent and returns the
+// appropriate sort type. Fallbacks to the latest articles load at most limit.
+func (s *NewsService) fetchArticlesByIntent(params FetchParams, limit int) ([]models.Article, sortType, error) {
+	query := s.filterQuery(params)
 
 	switch params.Intent {
 	case models.IntentCategory:
-		articles, err := s.fetchByCategory(query, params.Entities)
+		articles, err := s.fetchByCategory(query, params.Entities, limit)
 		return articles, sortByDateDesc, err
 
 	case models.IntentSource:
-		articles, err := s.fetchBySource(query, params.Entities)
+		articles, err := s.fetchBySource(query, params.Entities, limit)
 		return articles, sortByDateDesc, err
 
 	case models.IntentScore:
@@ -101,15 +275,19 @@ func (s *NewsService) fetchArticlesByIntent(params FetchParams) ([]models.Articl
 		if radius == 0 {
 			radius = s.cfg.DefaultRadius
 		}
-		articles, err := s.fetchNearby(params.Lat, params.Lon, radius, params.Entities)
+		articles, err := s.fetchNearby(query, params.Lat, params.Lon, radius, params.Entities, params.SearchFields)
 		return articles, sortByDistance, err
 
 	case models.IntentSearch:
-		articles, err := s.fetchBySearch(query, params.Entities)
+		articles, err := s.fetchBySearch(query, params.Entities, params.SearchFields, limit)
 		return articles, sortBySearchRelevance, err
 
+	case models.IntentDiscovery:
+		articles, err := s.fetchLatestArticles(query, limit)
+		return articles, sortByDateDesc, err
+
 	default:
-		articles, err := s.fetchBySearch(query, params.Entities)
+		articles, err := s.fetchBySearch(query, params.Entities, params.SearchFields, limit)
 		return articles, sortByDateDesc, err
 	}
 }
@@ -120,46 +298,168 @@ func (s *NewsService) applySorting(articles []models.Article, st sortType, param
 	case sortByDateDesc:
 		utils.SortArticles(articles, utils.SortDateDesc)
 	case sortByScoreDesc:
-		utils.SortArticles(articles, utils.SortScoreDesc)
+		// Score intent ranks by current (engagement-adjusted) relevance
+		scores := make(map[string]float64, len(articles))
+		for _, article := range articles {
+			scores[article.ID] = article.CurrentRelevance
+		}
+		utils.SortByScoreMap(articles, scores, utils.Descending)
 	case sortByDistance:
 		utils.SortByDistanceFrom(articles, params.Lat, params.Lon)
 	case sortBySearchRelevance:
-		// Requirement: rank by combination of relevance_score and text matching score
+		// Requirement: rank by combination of relevance_score and text matching score,
+		// scaled by source reliability
 		query, _ := params.Entities["query"].(string)
-		utils.SortBySearchRelevance(articles, query)
+		ratings, err := loadSourceRatings(s.db)
+		if err != nil {
+			log.Printf("Search ranking without source reliability: %v", err)
+		}
+		utils.SortBySearchRelevance(articles, query, ratings.articleFactors(s.cfg, articles))
 	}
 }
 
+// applyHybridSorting ranks articles by text match, relevance and embedding similarity,
+// attaching the per-signal breakdown to each article. Text searches also take
+// in the candidates most similar to the query, up to the requested depth, so
+// articles without a keyword match can still rank.
+func (s *NewsService) applyHybridSorting(ctx context.Context, articles []models.Article, params FetchParams, candidates int) []models.Article {
+	query, _ := params.Entities["query"].(string)
+
+	ids := make([]string, len(articles))
+	for i := range articles {
+		ids[i] = articles[i].ID
+	}
+	if !params.searchesText() {
+		candidates = 0
+	}
+
+	similarity, err := s.embeddingService.SimilarityScores(ctx, query, ids, candidates, params.Dates)
+	if err != nil {
+		log.Printf("Hybrid search without semantic scores: %v", err)
+	}
+	articles, err = s.mergeSemanticCandidates(articles, similarity, params)
+	if err != nil {
+		log.Printf("Hybrid search without semantic candidates: %v", err)
+	}
+
+	breakdown := utils.SortByHybridRelevance(articles, query, similarity, utils.HybridWeights{
+		Text:      s.cfg.HybridTextWeight,
+		Relevance: s.cfg.HybridRelevanceWeight,
+		Semantic:  s.cfg.HybridSemanticWeight,
+	}, s.cfg.ScoreNormalization)
+
+	for i := range articles {
+		score := breakdown[articles[i].ID]
+		articles[i].Similarity = similarity[articles[i].ID]
+		articles[i].ScoreBreakdown = map[string]float64{
+			"text":      score.Text,
+			"relevance": score.Relevance,
+			"semantic":  score.Semantic,
+			"combined":  score.Combined,
+		}
+	}
+	return articles
+}
+
+// mergeSemanticCandidates appends the scored articles the keyword search
+// missed, keeping only those that pass the request's filters
+func (s *NewsService) mergeSemanticCandidates(articles []models.Article, similarity map[string]float64, params FetchParams) ([]models.Article, error) {
+	found := make(map[string]bool, len(articles))
+	for _, article := range articles {
+		found[article.ID] = true
+	}
+	var missing []string
+	for id := range similarity {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return articles, nil
+	}
+
+	var candidates []models.Article
+	if err := s.filterQuery(params).Where("id IN ?", missing).Find(&candidates).Error; err != nil {
+		return articles, fmt.Errorf("failed to load semantic candidates: %w", err)
+	}
+	return append(articles, candidates...), nil
+}
+
+// searchesText reports whether the intent matches articles by the query text
+// alone, so semantic matches may stand in for keyword ones
+func (p FetchParams) searchesText() bool {
+	switch p.Intent {
+	case models.IntentCategory, models.IntentSource, models.IntentNearby, models.IntentScore, models.IntentDiscovery:
+		return false
+	}
+	return true
+}
+
+// MaxArticles returns the configured maximum number of articles per response
+func (s *NewsService) MaxArticles() int {
+	return s.cfg.MaxArticlesReturn
+}
+
+// LatestCacheTTL returns how long shared caches may keep the first page of
+// the latest news, which changes with every new article
+func (s *NewsService) LatestCacheTTL() int {
+	return s.cfg.EdgeCacheLatestTTL
+}
+
 // EnrichWithSummaries adds LLM-generated summaries to articles
-func (s *NewsService) EnrichWithSummaries(articles []models.Article) []models.Article {
-	s.llmService.GenerateSummariesBatch(articles)
+func (s *NewsService) EnrichWithSummaries(ctx context.Context, articles []models.Article) []models.Article {
+	s.llmService.GenerateSummariesBatch(ctx, articles)
 	return articles
 }
 
+// StreamSummary streams an article's summary to onDelta as the LLM writes it
+func (s *NewsService) StreamSummary(ctx context.Context, article *models.Article, onDelta func(delta string) error) (string, error) {
+	return s.llmService.StreamSummary(ctx, article, onDelta)
+}
+
 // SearchWithIntent performs search with LLM intent parsing
-func (s *NewsService) SearchWithIntent(query string) (*FetchResult, *models.IntentResponse, error) {
+func (s *NewsService) SearchWithIntent(ctx context.Context, query string, dates DateRange, ranking RankingOptions, sentiment string, searchFields []string, minSourceScore float64, paging Paging) (*FetchResult, *models.IntentResponse, error) {
+	return s.SearchWithIntentMode(ctx, query, SearchModeKeyword, dates, ranking, sentiment, searchFields, minSourceScore, false, paging)
+}
+
+// SearchWithIntentMode performs search with LLM intent parsing using the given ranking mode.
+// Unset date bounds fall back to dates the LLM extracted from the query. A
+// non-empty sentiment keeps only articles tagged with it, non-empty
+// searchFields override SEARCH_FIELDS, a positive minSourceScore keeps
+// only articles from sources rated at least that reliable, and
+// includeArchived also searches the article archive.
+func (s *NewsService) SearchWithIntentMode(ctx context.Context, query, mode string, dates DateRange, ranking RankingOptions, sentiment string, searchFields []string, minSourceScore float64, includeArchived bool, paging Paging) (*FetchResult, *models.IntentResponse, error) {
 	// Parse intent and entities using LLM
-	intentResp := s.llmService.ParseIntent(query)
+	intentResp := s.llmService.ParseIntent(ctx, query)
 
 	// Fetch articles based on parsed intent
-	result, err := s.FetchArticlesWithMetadata(FetchParams{
-		Intent:   intentResp.Intent,
-		Entities: intentResp.Entities,
+	result, err := s.FetchArticlesWithMetadata(ctx, FetchParams{
+		Intent:          intentResp.Intent,
+		Entities:        intentResp.Entities,
+		Mode:            mode,
+		Facets:          true,
+		Dates:           dates.withEntityDefaults(intentResp.Entities),
+		Ranking:         ranking,
+		Sentiment:       sentiment,
+		SearchFields:    searchFields,
+		MinSourceScore:  minSourceScore,
+		IncludeArchived: includeArchived,
+		Paging:          paging,
 	})
 	if err != nil {
 		return nil, &intentResp, err
 	}
 
 	// Enrich with summaries
-	result.Articles = s.EnrichWithSummaries(result.Articles)
+	result.Articles = s.EnrichWithSummaries(ctx, result.Articles)
 
 	return result, &intentResp, nil
 }
 
 // QueryWithIntent handles generic queries with intent parsing and location
-func (s *NewsService) QueryWithIntent(query string, lat, lon, radius float64) ([]models.Article, *models.IntentResponse, error) {
+func (s *NewsService) QueryWithIntent(ctx context.Context, query string, lat, lon, radius float64, dates DateRange, ranking RankingOptions, paging Paging) (*FetchResult, *models.IntentResponse, error) {
 	// Parse intent and entities using LLM
-	intentResp := s.llmService.ParseIntent(query)
+	intentResp := s.llmService.ParseIntent(ctx, query)
 
 	// Add location context to entities
 	intentResp.Entities["lat"] = lat
@@ -169,28 +469,109 @@ func (s *NewsService) QueryWithIntent(query string, lat, lon, radius float64) ([
 	}
 
 	// Fetch articles
-	articles, err := s.FetchArticles(intentResp.Intent, intentResp.Entities, lat, lon, radius)
+	result, err := s.FetchArticlesWithMetadata(ctx, FetchParams{
+		Intent:   intentResp.Intent,
+		Entities: intentResp.Entities,
+		Lat:      lat,
+		Lon:      lon,
+		Radius:   radius,
+		Facets:   true,
+		Dates:    dates.withEntityDefaults(intentResp.Entities),
+		Ranking:  ranking,
+		Paging:   paging,
+	})
 	if err != nil {
 		return nil, &intentResp, err
 	}
 
 	// Enrich with summaries
-	articles = s.EnrichWithSummaries(articles)
+	result.Articles = s.EnrichWithSummaries(ctx, result.Articles)
+
+	return result, &intentResp, nil
+}
+
+// LatestParams filters the chronological article listing. Zero values
+// mean "no filter".
+type LatestParams struct {
+	Category string
+	Source   string
+	Since    time.Time // earliest publication date
+	Cursor   utils.Cursor
+	Limit    int // articles per page; 0 for MAX_ARTICLES
+}
 
-	return articles, &intentResp, nil
+// LatestPage is one page of the chronological article listing
+type LatestPage struct {
+	Articles   []models.Article
+	NextCursor string // empty on the last page
+}
+
+// Latest returns the newest articles matching params after its cursor, by
+// publication date and then ID, newest first. Pages are keyset-based rather
+// than offsets, so articles added while a client pages don't shift what
+// comes next.
+func (s *NewsService) Latest(ctx context.Context, params LatestParams) (*LatestPage, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = s.cfg.MaxArticlesReturn
+	}
+	limit = TierLimit(ctx, limit)
+	query := s.db.Model(&models.Article{})
+	if params.Category != "" {
+		query = query.Where("id IN (?)", s.categoryArticleIDs(params.Category))
+	}
+	if params.Source != "" {
+		query = query.Where("LOWER(source_name) = ?", strings.ToLower(strings.TrimSpace(params.Source)))
+	}
+	if !params.Since.IsZero() {
+		query = query.Where("publication_date >= ?", params.Since)
+	}
+	query = Mutes(ctx).apply(s.db, query)
+	if cursor := params.Cursor; !cursor.IsZero() {
+		query = afterCursor(query, "publication_date", cursor.Time, cursor.ID)
+	}
+
+	var articles []models.Article
+	if err := query.Order("publication_date DESC, id DESC").Limit(limit + 1).Find(&articles).Error; err != nil {
+		return nil, fmt.Errorf("failed to load latest articles: %w", err)
+	}
+
+	page := &LatestPage{Articles: articles}
+	if len(articles) > limit {
+		page.Articles = articles[:limit]
+		last := page.Articles[limit-1]
+		page.NextCursor = utils.EncodeCursor(last.PublicationDate, last.ID)
+	}
+	page.Articles = s.EnrichWithSummaries(ctx, page.Articles)
+	return page, nil
+}
+
+// GetCategories lists categories with the number of articles in each, most used first
+func (s *NewsService) GetCategories() ([]models.CategoryCount, error) {
+	var counts []models.CategoryCount
+	err := s.db.Model(&models.Category{}).
+		Select("categories.name AS name, COUNT(article_categories.article_id) AS article_count").
+		Joins("LEFT JOIN article_categories ON article_categories.category_id = categories.id").
+		Group("categories.id").
+		Order("article_count DESC, categories.name").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count categories: %w", err)
+	}
+	return counts, nil
 }
 
 // GetArticleStats returns statistics about the article database
 func (s *NewsService) GetArticleStats() (map[string]interface{}, error) {
 	var totalCount int64
-	var categories []string
+	var categories int64
 	var sources []string
 
 	// Total articles
 	s.db.Model(&models.Article{}).Count(&totalCount)
 
 	// Unique categories
-	s.db.Model(&models.Article{}).Distinct("category").Pluck("category", &categories)
+	s.db.Model(&models.Category{}).Count(&categories)
 
 	// Unique sources
 	s.db.Model(&models.Article{}).Distinct("source_name").Pluck("source_name", &sources)
@@ -202,7 +583,7 @@ func (s *NewsService) GetArticleStats() (map[string]interface{}, error) {
 
 	stats := map[string]interface{}{
 		"total_articles":    totalCount,
-		"unique_categories": len(categories),
+		"unique_categories": categories,
 		"unique_sources":    len(sources),
 		"oldest_article":    oldestArticle.PublicationDate.Format(time.RFC3339),
 		"newest_article":    newestArticle.PublicationDate.Format(time.RFC3339),
@@ -210,3 +591,31 @@ func (s *NewsService) GetArticleStats() (map[string]interface{}, error) {
 
 	return stats, nil
 }
+
+// PublicStats holds coarse corpus aggregates that are safe to show without auth
+type PublicStats struct {
+	TotalArticles int64      `json:"total_articles"`
+	Categories    int64      `json:"categories"`
+	LastUpdated   *time.Time `json:"last_updated"` // Newest publication date; null for an empty corpus
+}
+
+// GetPublicStats returns corpus health for status pages and the demo UI
+func (s *NewsService) GetPublicStats() (*PublicStats, error) {
+	stats := &PublicStats{}
+	if err := s.db.Model(&models.Article{}).Count(&stats.TotalArticles).Error; err != nil {
+		return nil, fmt.Errorf("failed to count articles: %w", err)
+	}
+	if err := s.db.Model(&models.Category{}).Count(&stats.Categories).Error; err != nil {
+		return nil, fmt.Errorf("failed to count categories: %w", err)
+	}
+
+	var newest models.Article
+	err := s.db.Select("publication_date").Order("publication_date DESC").Take(&newest).Error
+	switch {
+	case err == nil:
+		stats.LastUpdated = &newest.PublicationDate
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, fmt.Errorf("failed to load newest article: %w", err)
+	}
+	return stats, nil
+}
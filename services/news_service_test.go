@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"news-backend/cache"
+	"news-backend/config"
+	"news-backend/database"
+	"news-backend/models"
+)
+
+func TestHybridSearchIncludesSemanticMatches(t *testing.T) {
+	db := openTestDB(t)
+	// Every query embeds to the same vector as the semantic article
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"object": "list", "data": [{"object": "embedding", "index": 0, "embedding": [1, 0]}],
+			"usage": {"total_tokens": 1}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := &config.Config{
+		LLMProviders:          []config.LLMProviderConfig{{Name: "test", APIKey: "key", BaseURL: server.URL}},
+		LLMBreakerThreshold:   3,
+		LLMBreakerCooldown:    60,
+		EmbeddingsEnabled:     true,
+		MaxArticlesReturn:     10,
+		SearchFields:          "title,description",
+		HybridTextWeight:      0.4,
+		HybridRelevanceWeight: 0.2,
+		HybridSemanticWeight:  0.4,
+	}
+	invalidation, err := NewInvalidationService(cfg)
+	if err != nil {
+		t.Fatalf("NewInvalidationService() error = %v", err)
+	}
+	llmService := NewLLMService(cfg, invalidation, cache.NewMemory())
+	service := NewNewsService(cfg, llmService, NewEmbeddingService(cfg, llmService), nil)
+
+	now := time.Now()
+	articles := []models.Article{
+		{ID: "keyword", Title: "Election results announced", URL: "https://example.com/keyword", PublicationDate: now},
+		{ID: "semantic", Title: "Voters head to the polls", URL: "https://example.com/semantic", PublicationDate: now},
+	}
+	if err := db.Create(&articles).Error; err != nil {
+		t.Fatalf("failed to create articles: %v", err)
+	}
+	vectors := map[string][]float32{"keyword": {0, 1}, "semantic": {1, 0}}
+	for id, vector := range vectors {
+		embedding := models.ArticleEmbedding{ArticleID: id}
+		embedding.SetVector(vector)
+		if err := db.Create(&embedding).Error; err != nil {
+			t.Fatalf("failed to create embedding: %v", err)
+		}
+	}
+
+	result, err := service.FetchArticlesWithMetadata(context.Background(), FetchParams{
+		Intent:   models.IntentSearch,
+		Mode:     SearchModeHybrid,
+		Entities: models.Entities{"query": "election"},
+	})
+	if err != nil {
+		t.Fatalf("FetchArticlesWithMetadata() error = %v", err)
+	}
+	similarity := map[string]float64{}
+	for _, article := range result.Articles {
+		similarity[article.ID] = article.Similarity
+	}
+	if _, ok := similarity["keyword"]; !ok {
+		t.Error("hybrid results are missing the keyword match")
+	}
+	if similarity["semantic"] != 1 {
+		t.Errorf("hybrid results = %v, expected the semantic match with similarity 1", similarity)
+	}
+}
+
+func TestFetchByCategoryMatchesWholeNames(t *testing.T) {
+	db := openTestDB(t)
+	service := NewNewsService(&config.Config{MaxArticlesReturn: 10}, nil, nil, nil)
+
+	now := time.Now()
+	articles := []models.Article{
+		{ID: "tech", Title: "Tech", URL: "https://example.com/tech", PublicationDate: now, Category: "Tech"},
+		{ID: "listed", Title: "Listed", URL: "https://example.com/listed", PublicationDate: now, Category: "health, TECH"},
+		{ID: "biotech", Title: "Biotech", URL: "https://example.com/biotech", PublicationDate: now, Category: "Biotech,Health"},
+		{ID: "fintech", Title: "Fintech", URL: "https://example.com/fintech", PublicationDate: now, Category: "FinTech"},
+	}
+	if err := db.Create(&articles).Error; err != nil {
+		t.Fatalf("failed to create articles: %v", err)
+	}
+	if err := database.SyncArticleCategories(db, articles); err != nil {
+		t.Fatalf("SyncArticleCategories() error = %v", err)
+	}
+
+	tests := []struct {
+		category string
+		expected []string
+	}{
+		{"tech", []string{"listed", "tech"}},
+		{" Tech ", []string{"listed", "tech"}},
+		{"health", []string{"biotech", "listed"}},
+		{"biotech", []string{"biotech"}},
+		{"ech", nil},
+	}
+	for _, tt := range tests {
+		found, err := service.fetchByCategory(db.Order("id"), models.Entities{"category": tt.category}, 10)
+		if err != nil {
+			t.Fatalf("fetchByCategory(%q) error = %v", tt.category, err)
+		}
+		ids := make([]string, len(found))
+		for i, article := range found {
+			ids[i] = article.ID
+		}
+		if strings.Join(ids, ",") != strings.Join(tt.expected, ",") {
+			t.Errorf("fetchByCategory(%q) = %v, expected %v", tt.category, ids, tt.expected)
+		}
+	}
+}
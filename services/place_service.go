@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"news-backend/config"
+	"news-backend/database"
+	"news-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Saved place errors
+var (
+	ErrPlacesNotEnabled = errors.New("saving places requires opting in with the save_places preference")
+	ErrInvalidPlace     = errors.New("invalid place name")
+	ErrPlaceNotFound    = errors.New("place not found")
+)
+
+// maxPlaceNameLength bounds a place name in bytes
+const maxPlaceNameLength = 50
+
+// PlaceService stores the named places users opted in to saving and
+// resolves them to coordinates
+type PlaceService struct {
+	db          *gorm.DB
+	cfg         *config.Config
+	userService *UserService
+}
+
+// NewPlaceService creates a new place service instance
+func NewPlaceService(cfg *config.Config, userService *UserService) *PlaceService {
+	return &PlaceService{
+		db:          database.GetDB(),
+		cfg:         cfg,
+		userService: userService,
+	}
+}
+
+type nearPlaceKey struct{}
+
+// WithNearPlace marks ctx's request as located at the user's saved place
+// name, so responses don't reveal its coordinates
+func WithNearPlace(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, nearPlaceKey{}, name)
+}
+
+// NearPlace returns the saved place ctx's request is located at, or ""
+func NearPlace(ctx context.Context) string {
+	name, _ := ctx.Value(nearPlaceKey{}).(string)
+	return name
+}
+
+// normalizePlaceName lowercases and trims a place name
+func normalizePlaceName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || len(name) > maxPlaceNameLength {
+		return "", fmt.Errorf("%w: expected 1 to %d characters", ErrInvalidPlace, maxPlaceNameLength)
+	}
+	return name, nil
+}
+
+// Save stores or moves one of the canonical user's places. The user must
+// have opted in with the save_places preference.
+func (s *PlaceService) Save(userID, name string, lat, lon float64) (*models.SavedPlace, error) {
+	name, err := normalizePlaceName(name)
+	if err != nil {
+		return nil, err
+	}
+	prefs, err := s.userService.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !prefs.SavePlaces {
+		return nil, ErrPlacesNotEnabled
+	}
+
+	place := models.SavedPlace{UserID: prefs.UserID, Name: name, Latitude: lat, Longitude: lon}
+	err = s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"latitude", "longitude", "updated_at"}),
+	}).Create(&place).Error
+	if err == nil {
+		err = s.db.Where("user_id = ? AND name = ?", place.UserID, place.Name).First(&place).Error
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save place: %w", err)
+	}
+	return &place, nil
+}
+
+// List returns the canonical user's places by name
+func (s *PlaceService) List(userID string) ([]models.SavedPlace, error) {
+	var places []models.SavedPlace
+	err := s.db.Where("user_id = ?", s.userService.ResolveUserID(userID)).Order("name").Find(&places).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load places: %w", err)
+	}
+	return places, nil
+}
+
+// Delete removes one of the canonical user's places
+func (s *PlaceService) Delete(userID, name string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	result := s.db.Where("user_id = ? AND name = ?", s.userService.ResolveUserID(userID), name).
+		Delete(&models.SavedPlace{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete place: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrPlaceNotFound
+	}
+	return nil
+}
+
+// Resolve returns the coordinates of one of the canonical user's places
+func (s *PlaceService) Resolve(userID, name string) (float64, float64, error) {
+	var place models.SavedPlace
+	err := s.db.Where("user_id = ? AND name = ?", s.userService.ResolveUserID(userID), strings.ToLower(strings.TrimSpace(name))).
+		First(&place).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, 0, ErrPlaceNotFound
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to resolve place: %w", err)
+	}
+	return place.Latitude, place.Longitude, nil
+}
@@ -1,8 +1,11 @@
 package services
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
+	"news-backend/database"
 	"news-backend/models"
 	"news-backend/utils"
 
@@ -21,39 +24,50 @@ func (s *NewsService) fetchByField(query *gorm.DB, field, value string) ([]model
 }
 
 // fetchByCategory fetches articles by category
-func (s *NewsService) fetchByCategory(query *gorm.DB, entities models.Entities) ([]models.Article, error) {
+func (s *NewsService) fetchByCategory(query *gorm.DB, entities models.Entities, limit int) ([]models.Article, error) {
 	category, _ := entities["category"].(string)
 	if category == "" {
-		return s.fetchLatestArticles(query)
+		return s.fetchLatestArticles(query, limit)
 	}
-	return s.fetchByField(query, "category", category)
+
+	var articles []models.Article
+	err := query.Where("id IN (?)", s.categoryArticleIDs(category)).Find(&articles).Error
+	return articles, err
+}
+
+// categoryArticleIDs selects the IDs of articles with a category, matched
+// exactly (case-insensitive) against any of the article's categories
+func (s *NewsService) categoryArticleIDs(category string) *gorm.DB {
+	return s.db.Table("article_categories").
+		Select("article_categories.article_id").
+		Joins("JOIN categories ON categories.id = article_categories.category_id").
+		Where("LOWER(categories.name) = ?", strings.ToLower(strings.TrimSpace(category)))
 }
 
 // fetchBySource fetches articles by source name
-func (s *NewsService) fetchBySource(query *gorm.DB, entities models.Entities) ([]models.Article, error) {
+func (s *NewsService) fetchBySource(query *gorm.DB, entities models.Entities, limit int) ([]models.Article, error) {
 	source, _ := entities["source"].(string)
 	if source == "" {
-		return s.fetchLatestArticles(query)
+		return s.fetchLatestArticles(query, limit)
 	}
 	// Map API parameter 'source' to DB column 'source_name'
 	return s.fetchByField(query, "source_name", source)
 }
 
-// fetchByScore fetches high-scoring articles
+// fetchByScore fetches high-scoring articles using the engagement-adjusted relevance
 func (s *NewsService) fetchByScore(query *gorm.DB) ([]models.Article, error) {
 	var articles []models.Article
-	err := query.Where("relevance_score >= ?", s.cfg.ScoreThreshold).Find(&articles).Error
+	err := query.Where("current_relevance >= ?", s.cfg.ScoreThreshold).Find(&articles).Error
 	return articles, err
 }
 
 // fetchNearby fetches articles near a geographic location
-func (s *NewsService) fetchNearby(lat, lon, radius float64, entities models.Entities) ([]models.Article, error) {
+func (s *NewsService) fetchNearby(query *gorm.DB, lat, lon, radius float64, entities models.Entities, fields []string) ([]models.Article, error) {
 	var articles []models.Article
-	query := s.db.Model(&models.Article{})
 
 	// Apply text search if query provided
 	if queryText, ok := entities["query"].(string); ok && queryText != "" {
-		query = s.applyTextSearch(query, queryText)
+		query = s.applyTextSearch(query, queryText, fields)
 	}
 
 	// Get all articles and filter by distance
@@ -67,15 +81,15 @@ func (s *NewsService) fetchNearby(lat, lon, radius float64, entities models.Enti
 	return filtered, nil
 }
 
-// fetchBySearch performs text search across title and description
-func (s *NewsService) fetchBySearch(query *gorm.DB, entities models.Entities) ([]models.Article, error) {
+// fetchBySearch performs text search across the given search fields
+func (s *NewsService) fetchBySearch(query *gorm.DB, entities models.Entities, fields []string, limit int) ([]models.Article, error) {
 	searchQuery, _ := entities["query"].(string)
 	if searchQuery == "" {
-		return s.fetchLatestArticles(query)
+		return s.fetchLatestArticles(query, limit)
 	}
 
 	var articles []models.Article
-	err := s.applyTextSearch(query, searchQuery).Find(&articles).Error
+	err := s.applyTextSearch(query, searchQuery, fields).Find(&articles).Error
 	return articles, err
 }
 
@@ -83,36 +97,188 @@ func (s *NewsService) fetchBySearch(query *gorm.DB, entities models.Entities) ([
 // Query Building Helpers
 // =============================================================================
 
-// applyTextSearch adds text search conditions to a query
-func (s *NewsService) applyTextSearch(query *gorm.DB, searchText string) *gorm.DB {
-	pattern := "%" + strings.ToLower(searchText) + "%"
-	return query.Where("LOWER(title) LIKE ? OR LOWER(description) LIKE ?", pattern, pattern)
+// applyTextSearch adds text search conditions to a query, matching any of
+// the given search fields (SEARCH_FIELDS when empty)
+func (s *NewsService) applyTextSearch(query *gorm.DB, searchText string, fields []string) *gorm.DB {
+	if len(fields) == 0 {
+		fields = s.searchFields
+	}
+	conditions := make([]string, len(fields))
+	var args []interface{}
+	for i, field := range fields {
+		condition, fieldArgs := s.searchFieldCondition(field, searchText)
+		conditions[i] = condition
+		args = append(args, fieldArgs...)
+	}
+	return query.Where(strings.Join(conditions, " OR "), args...)
 }
 
 // fetchLatestArticles fetches the most recent articles as a fallback
-func (s *NewsService) fetchLatestArticles(query *gorm.DB) ([]models.Article, error) {
+func (s *NewsService) fetchLatestArticles(query *gorm.DB, limit int) ([]models.Article, error) {
 	var articles []models.Article
-	err := query.Order("publication_date DESC").Limit(s.cfg.MaxArticlesReturn).Find(&articles).Error
+	err := query.Order(orderByDate).Limit(limit).Find(&articles).Error
 	return articles, err
 }
 
-// =============================================================================
-// Result Limiting Helpers
-// =============================================================================
+// SQL orders matching the in-memory sorts, ties broken as utils.LessOnTie does
+const (
+	orderByDate  = "publication_date DESC, id"
+	orderByScore = "current_relevance DESC, publication_date DESC, id"
+)
+
+// listQuery narrows query to an intent's articles when the intent lists
+// them by date or score, returning that order; ok is false for intents
+// ranked in memory (by distance or text relevance)
+func (s *NewsService) listQuery(query *gorm.DB, params FetchParams) (_ *gorm.DB, order string, ok bool) {
+	switch params.Intent {
+	case models.IntentCategory:
+		if category, _ := params.Entities["category"].(string); category != "" {
+			query = query.Where("id IN (?)", s.categoryArticleIDs(category))
+		}
+		return query, orderByDate, true
+	case models.IntentSource:
+		if source, _ := params.Entities["source"].(string); source != "" {
+			query = query.Where("source_name = ?", source)
+		}
+		return query, orderByDate, true
+	case models.IntentScore:
+		return query.Where("current_relevance >= ?", s.cfg.ScoreThreshold), orderByScore, true
+	case models.IntentDiscovery:
+		return query, orderByDate, true
+	case models.IntentNearby, models.IntentSearch:
+		return query, "", false
+	default:
+		// Other intents match the query text but list by date
+		if searchQuery, _ := params.Entities["query"].(string); searchQuery != "" {
+			query = s.applyTextSearch(query, searchQuery, params.SearchFields)
+		}
+		return query, orderByDate, true
+	}
+}
+
+// fetchListPage loads limit articles of query in order from offset on, one
+// per story with STORY_COLLAPSE, counting the full matching set for
+// TotalAvailable and, with params.Facets, facets without loading it
+func (s *NewsService) fetchListPage(query *gorm.DB, order string, limit, offset int, params FetchParams) (*FetchResult, error) {
+	if s.cfg.StoryCollapse {
+		query = s.storyLeads(query, order, params.IncludeArchived)
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count articles: %w", err)
+	}
+	var articles []models.Article
+	if err := query.Order(order).Limit(limit).Offset(offset).Find(&articles).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch articles: %w", err)
+	}
+
+	result := &FetchResult{Articles: articles, TotalAvailable: int(total)}
+	if params.Facets {
+		facets, err := s.computeFacets(query.Select("id"), params.IncludeArchived)
+		if err != nil {
+			return nil, err
+		}
+		result.Facets = facets
+	}
+	return result, nil
+}
+
+// storyLeads restricts query to the first article of each story in order,
+// as collapseStories does for results ordered in memory
+func (s *NewsService) storyLeads(query *gorm.DB, order string, includeArchived bool) *gorm.DB {
+	ranked := query.Select("id, ROW_NUMBER() OVER (PARTITION BY COALESCE(NULLIF(story_id, ''), id) ORDER BY " + order + ") AS story_rank")
+	leads := s.db.Table("(?) AS ranked", ranked).Select("id").Where("story_rank = 1")
+	return s.articleTable(includeArchived).Where("id IN (?)", leads)
+}
 
-// limitArticles limits the number of articles returned
-func (s *NewsService) limitArticles(articles []models.Article) []models.Article {
-	if len(articles) > s.cfg.MaxArticlesReturn {
-		return articles[:s.cfg.MaxArticlesReturn]
+// articleTable starts an article query, over the archive too when
+// includeArchived is set
+func (s *NewsService) articleTable(includeArchived bool) *gorm.DB {
+	if includeArchived {
+		return database.WithArchive(s.db)
 	}
-	return articles
+	return s.db.Model(&models.Article{})
+}
+
+// afterCursor restricts a query ordered by column, then id, both descending,
+// to the rows after the cursor's, so pages don't shift as rows are added
+func afterCursor(query *gorm.DB, column string, at time.Time, id interface{}) *gorm.DB {
+	return query.Where("("+column+" < ? OR ("+column+" = ? AND id < ?))", at, at, id)
 }
 
-// limitArticlesWithTotal returns a FetchResult with total count and limited articles
-func (s *NewsService) limitArticlesWithTotal(articles []models.Article) *FetchResult {
+// =============================================================================
+// Result Limiting Helpers
+// =============================================================================
+
+// pageArticles returns a FetchResult with the total count and the page of
+// at most limit articles from offset on, for results ordered in memory
+func pageArticles(articles []models.Article, limit, offset int) *FetchResult {
 	total := len(articles)
+	articles = articles[min(offset, total):min(offset+limit, total)]
 	return &FetchResult{
-		Articles:       s.limitArticles(articles),
+		Articles:       articles,
 		TotalAvailable: total,
 	}
 }
+
+// =============================================================================
+// Facet Helpers
+// =============================================================================
+
+// articleIDs returns the IDs of articles, in order
+func articleIDs(articles []models.Article) []string {
+	ids := make([]string, len(articles))
+	for i := range articles {
+		ids[i] = articles[i].ID
+	}
+	return ids
+}
+
+// computeFacets counts categories, sources and publication days across the
+// full matching set using GROUP BY queries over the matched IDs, given as a
+// slice or a subquery selecting them
+func (s *NewsService) computeFacets(ids interface{}, includeArchived bool) (*models.Facets, error) {
+	facets := &models.Facets{
+		Categories: []models.FacetCount{},
+		Sources:    []models.FacetCount{},
+		Days:       []models.FacetCount{},
+	}
+	if list, ok := ids.([]string); ok && len(list) == 0 {
+		return facets, nil
+	}
+
+	err := s.db.Table("article_categories").
+		Select("categories.name AS value, COUNT(*) AS count").
+		Joins("JOIN categories ON categories.id = article_categories.category_id").
+		Where("article_categories.article_id IN (?)", ids).
+		Group("categories.name").
+		Order("count DESC, value").
+		Scan(&facets.Categories).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute category facets: %w", err)
+	}
+
+	err = s.articleTable(includeArchived).
+		Select("source_name AS value, COUNT(*) AS count").
+		Where("id IN (?)", ids).
+		Group("source_name").
+		Order("count DESC, value").
+		Scan(&facets.Sources).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute source facets: %w", err)
+	}
+
+	err = s.articleTable(includeArchived).
+		Select("strftime('%Y-%m-%d', publication_date) AS value, COUNT(*) AS count").
+		Where("id IN (?)", ids).
+		Group("value").
+		Order("value DESC").
+		Scan(&facets.Days).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute day facets: %w", err)
+	}
+
+	return facets, nil
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"news-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// Conversational query errors
+var (
+	ErrSessionNotFound  = errors.New("query session not found or expired")
+	ErrLocationRequired = errors.New("lat and lon are required for nearby queries")
+)
+
+// QueryInSession answers a query as a turn of a conversation. With an empty
+// sessionID it starts a new session; otherwise the query is merged into the
+// session's previous intent, so "only from Reuters" narrows the last
+// results. Source and category entities filter the results whatever the
+// intent. A location given with the query replaces the session's.
+func (s *NewsService) QueryInSession(ctx context.Context, sessionID, query string, lat, lon, radius float64, dates DateRange, ranking RankingOptions, paging Paging) (*FetchResult, *models.IntentResponse, *models.QuerySession, error) {
+	ttl := time.Duration(s.cfg.QuerySessionTTL) * time.Minute
+	session := &models.QuerySession{}
+	if sessionID != "" {
+		err := s.db.Where("id = ? AND updated_at >= ?", sessionID, time.Now().Add(-ttl)).First(session).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, nil, ErrSessionNotFound
+		}
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to load query session: %w", err)
+		}
+	} else {
+		s.pruneQuerySessions(ttl)
+		session.ID = newArticleID()
+	}
+
+	var intentResp models.IntentResponse
+	if session.Turns > 0 {
+		intentResp = s.llmService.RefineIntent(ctx, query, models.IntentResponse{Intent: session.Intent, Entities: session.Entities})
+	} else {
+		intentResp = s.llmService.ParseIntent(ctx, query)
+	}
+
+	// The location comes from the request or the session, never the LLM
+	delete(intentResp.Entities, "lat")
+	delete(intentResp.Entities, "lon")
+	delete(intentResp.Entities, "radius")
+	if lat == 0 && lon == 0 && session.Entities != nil {
+		lat, _ = session.Entities["lat"].(float64)
+		lon, _ = session.Entities["lon"].(float64)
+		radius, _ = session.Entities["radius"].(float64)
+	}
+	if lat != 0 || lon != 0 {
+		intentResp.Entities["lat"] = lat
+		intentResp.Entities["lon"] = lon
+		if radius > 0 {
+			intentResp.Entities["radius"] = radius
+		}
+	} else if intentResp.Intent == models.IntentNearby {
+		return nil, &intentResp, nil, ErrLocationRequired
+	}
+
+	source, _ := intentResp.Entities["source"].(string)
+	category, _ := intentResp.Entities["category"].(string)
+	result, err := s.FetchArticlesWithMetadata(ctx, FetchParams{
+		Intent:   intentResp.Intent,
+		Entities: intentResp.Entities,
+		Lat:      lat,
+		Lon:      lon,
+		Radius:   radius,
+		Facets:   true,
+		Dates:    dates.withEntityDefaults(intentResp.Entities),
+		Ranking:  ranking,
+		Source:   source,
+		Category: category,
+		Paging:   paging,
+	})
+	if err != nil {
+		return nil, &intentResp, nil, err
+	}
+	result.Articles = s.EnrichWithSummaries(ctx, result.Articles)
+
+	session.Query = query
+	session.Intent = intentResp.Intent
+	session.Entities = intentResp.Entities
+	session.Turns++
+	if session.Turns == 1 {
+		err = s.db.Create(session).Error
+	} else {
+		err = s.db.Save(session).Error
+	}
+	if err != nil {
+		return nil, &intentResp, nil, fmt.Errorf("failed to save query session: %w", err)
+	}
+	session.ExpiresAt = session.UpdatedAt.Add(ttl)
+
+	return result, &intentResp, session, nil
+}
+
+// pruneQuerySessions deletes sessions idle for longer than ttl
+func (s *NewsService) pruneQuerySessions(ttl time.Duration) {
+	err := s.db.Where("updated_at < ?", time.Now().Add(-ttl)).Delete(&models.QuerySession{}).Error
+	if err != nil {
+		log.Printf("Failed to prune query sessions: %v", err)
+	}
+}
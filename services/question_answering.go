@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"news-backend/models"
+	"news-backend/utils"
+
+	"gorm.io/gorm/clause"
+)
+
+// Question answering errors
+var (
+	ErrInvalidQuestion   = errors.New("invalid question")
+	ErrAnswerUnavailable = errors.New("answering is temporarily unavailable")
+)
+
+// maxQuestionLength bounds a question in bytes
+const maxQuestionLength = 500
+
+// noMatchingArticlesAnswer is the answer when retrieval finds nothing
+const noMatchingArticlesAnswer = "No articles matched the question."
+
+// Answer is an LLM answer to a question about the news, grounded in the
+// retrieved articles it cites
+type Answer struct {
+	Question  string
+	Answer    string
+	Citations []models.Article // cited articles, most important first
+	Context   int              // articles the LLM was given
+}
+
+// Ask answers a question from the articles most relevant to it: keyword
+// matches and, with embeddings enabled, the nearest articles in vector
+// space, ranked with the hybrid search weights. Only articles published
+// within dates are considered.
+func (s *NewsService) Ask(ctx context.Context, question string, dates DateRange) (*Answer, error) {
+	question = strings.TrimSpace(question)
+	if question == "" || len(question) > maxQuestionLength {
+		return nil, fmt.Errorf("%w: expected 1 to %d characters", ErrInvalidQuestion, maxQuestionLength)
+	}
+	if IsDemoTier(ctx) {
+		return nil, ErrDemoTier
+	}
+
+	articles, err := s.retrieveForQuestion(ctx, question, dates)
+	if err != nil {
+		return nil, err
+	}
+	answer := &Answer{Question: question, Citations: []models.Article{}, Context: len(articles)}
+	if len(articles) == 0 {
+		answer.Answer = noMatchingArticlesAnswer
+		return answer, nil
+	}
+
+	text, cited, err := s.llmService.AnswerQuestion(ctx, question, articles)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("%w: %v", ErrAnswerUnavailable, err)
+	}
+	answer.Answer = text
+	for _, i := range cited {
+		answer.Citations = append(answer.Citations, articles[i])
+	}
+	return answer, nil
+}
+
+// retrieveForQuestion returns the AskContextArticles articles most relevant
+// to a question, one per story
+func (s *NewsService) retrieveForQuestion(ctx context.Context, question string, dates DateRange) ([]models.Article, error) {
+	candidates := make(map[string]models.Article)
+
+	terms := utils.KeywordTerms(question)
+	if len(terms) > 0 {
+		// Candidates matching the most terms first; whole-word coverage is
+		// scored below
+		matches := make([]string, len(terms))
+		args := make([]interface{}, 0, 2*len(terms))
+		for i, term := range terms {
+			matches[i] = "(CASE WHEN LOWER(title) LIKE ? OR LOWER(description) LIKE ? THEN 1 ELSE 0 END)"
+			args = append(args, "%"+term+"%", "%"+term+"%")
+		}
+		var keyword []models.Article
+		err := dates.apply(s.db.Model(&models.Article{})).
+			Where(strings.Join(matches, " + ")+" > 0", args...).
+			Order(clause.Expr{SQL: strings.Join(matches, " + ") + " DESC, publication_date DESC", Vars: args}).
+			Limit(s.cfg.AskCandidates).
+			Find(&keyword).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to search articles: %w", err)
+		}
+		for _, article := range keyword {
+			candidates[article.ID] = article
+		}
+	}
+
+	if s.embeddingService.Enabled() {
+		result, err := s.embeddingService.SemanticSearch(ctx, question, s.cfg.AskCandidates, dates)
+		if err != nil {
+			log.Printf("Answering without semantic retrieval: %v", err)
+		} else {
+			for _, article := range result.Articles {
+				candidates[article.ID] = article
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return []models.Article{}, nil
+	}
+
+	// Keyword-only candidates are less similar than every semantic one, so
+	// they count as 0
+	articles := make([]models.Article, 0, len(candidates))
+	for _, article := range candidates {
+		articles = append(articles, article)
+	}
+	text := make([]float64, len(articles))
+	relevance := make([]float64, len(articles))
+	semantic := make([]float64, len(articles))
+	for i := range articles {
+		text[i] = utils.TermCoverage(articles[i].Title+" "+articles[i].Description, terms)
+		relevance[i] = articles[i].RelevanceScore
+		semantic[i] = articles[i].Similarity
+	}
+	utils.NormalizeScores(text, s.cfg.ScoreNormalization)
+	utils.NormalizeScores(relevance, s.cfg.ScoreNormalization)
+	utils.NormalizeScores(semantic, s.cfg.ScoreNormalization)
+
+	scores := make(map[string]float64, len(articles))
+	for i := range articles {
+		scores[articles[i].ID] = text[i]*s.cfg.HybridTextWeight +
+			relevance[i]*s.cfg.HybridRelevanceWeight +
+			semantic[i]*s.cfg.HybridSemanticWeight
+	}
+	utils.SortByScoreMap(articles, scores, utils.Descending)
+
+	articles = collapseStories(articles)
+	if len(articles) > s.cfg.AskContextArticles {
+		articles = articles[:s.cfg.AskContextArticles]
+	}
+	return articles, nil
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"news-backend/models"
+	"news-backend/utils"
+)
+
+// Ranking profiles blend recency, engagement, distance and personal affinity
+// with predefined weights at query time
+const (
+	RankingBalanced = "balanced"
+	RankingFresh    = "fresh"
+	RankingPersonal = "personal"
+	RankingLocal    = "local"
+)
+
+// rankingProfiles maps each profile to its weights; every set sums to 1
+var rankingProfiles = map[string]utils.RankingWeights{
+	RankingBalanced: {Recency: 0.3, Engagement: 0.3, Distance: 0.2, Personal: 0.2},
+	RankingFresh:    {Recency: 0.6, Engagement: 0.2, Distance: 0.1, Personal: 0.1},
+	RankingPersonal: {Recency: 0.2, Engagement: 0.1, Distance: 0.1, Personal: 0.6},
+	RankingLocal:    {Recency: 0.2, Engagement: 0.1, Distance: 0.6, Personal: 0.1},
+}
+
+// rankingFreshnessHours is the age, behind the newest candidate, at which
+// the recency signal falls to 1/e
+const rankingFreshnessHours = 24.0
+
+// IsRankingProfile reports whether name is a known ranking profile
+func IsRankingProfile(name string) bool {
+	_, ok := rankingProfiles[name]
+	return ok
+}
+
+// RankingProfiles lists the ranking profile names, sorted
+func RankingProfiles() []string {
+	names := make([]string, 0, len(rankingProfiles))
+	for name := range rankingProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RankingOptions selects a ranking profile and the context its signals need
+type RankingOptions struct {
+	Profile     string // Empty keeps the intent's own ordering
+	UserID      string // Source of the personal signal; optional
+	Lat         float64
+	Lon         float64
+	HasLocation bool // Lat/Lon are set; nearby queries fall back to their own location
+}
+
+// applyRanking orders articles by the weighted blend of the profile's
+// signals, attaching the per-signal breakdown to each article
+func (s *NewsService) applyRanking(articles []models.Article, params FetchParams) *models.RankingInfo {
+	opts := params.Ranking
+	weights := rankingProfiles[opts.Profile]
+	info := &models.RankingInfo{Profile: opts.Profile, Weights: weights.Map(), Normalization: s.cfg.ScoreNormalization}
+	if len(articles) == 0 {
+		return info
+	}
+
+	lat, lon, hasLocation := opts.Lat, opts.Lon, opts.HasLocation
+	if !hasLocation && params.Intent == models.IntentNearby {
+		lat, lon, hasLocation = params.Lat, params.Lon, true
+	}
+
+	// Recency is relative to the newest candidate, so an older dataset
+	// still spreads across the signal
+	newest := articles[0].PublicationDate
+	for _, article := range articles[1:] {
+		if article.PublicationDate.After(newest) {
+			newest = article.PublicationDate
+		}
+	}
+
+	since := time.Now().Add(-time.Duration(s.cfg.RelevanceLookbackHours) * time.Hour)
+	engagement, err := engagementSince(s.db, since)
+	if err != nil {
+		log.Printf("Ranking without engagement: %v", err)
+	}
+	maxEngagement := 0.0
+	for _, article := range articles {
+		maxEngagement = max(maxEngagement, engagement[article.ID])
+	}
+
+	var affinity *UserAffinity
+	var interest map[string]float64
+	if opts.UserID != "" {
+		if affinity, err = s.userService.Affinity(opts.UserID); err != nil {
+			log.Printf("Ranking without personal affinity: %v", err)
+		} else if err := s.userService.MatchFollowedTopics(affinity, opts.UserID, articles); err != nil {
+			log.Printf("Ranking without followed topics: %v", err)
+		}
+		interest = s.interestScores(opts.UserID, articles)
+	}
+	info.Personalized = (affinity != nil && affinity.Personalized()) || len(interest) > 0
+
+	signals := make([]utils.RankingSignals, len(articles))
+	for i := range articles {
+		article := &articles[i]
+		signals[i].Recency = utils.CalculateFreshnessFactor(newest.Sub(article.PublicationDate).Hours(), rankingFreshnessHours)
+		if maxEngagement > 0 {
+			signals[i].Engagement = engagement[article.ID] / maxEngagement
+		}
+		if hasLocation {
+			distance := utils.HaversineDistance(lat, lon, article.Latitude, article.Longitude)
+			signals[i].Distance = utils.ProximityScore(distance, s.cfg.DefaultRadius)
+		}
+		if affinity != nil {
+			signals[i].Personal = affinity.Score(article)
+		}
+		if similarity, ok := interest[article.ID]; ok {
+			signals[i].Personal = (1-s.cfg.UserInterestWeight)*signals[i].Personal + s.cfg.UserInterestWeight*similarity
+		}
+	}
+	utils.NormalizeRankingSignals(signals, s.cfg.ScoreNormalization)
+
+	scores := make(map[string]float64, len(articles))
+	for i := range articles {
+		article := &articles[i]
+		combined := signals[i].Blend(weights)
+		scores[article.ID] = combined
+		article.ScoreBreakdown = map[string]float64{
+			"recency":    signals[i].Recency,
+			"engagement": signals[i].Engagement,
+			"distance":   signals[i].Distance,
+			"personal":   signals[i].Personal,
+			"combined":   combined,
+		}
+	}
+
+	utils.SortByScoreMap(articles, scores, utils.Descending)
+	return info
+}
+
+// interestScores returns the similarity of the articles to the user's
+// interest vector, empty without embeddings or an interest vector
+func (s *NewsService) interestScores(userID string, articles []models.Article) map[string]float64 {
+	ids := make([]string, len(articles))
+	for i := range articles {
+		ids[i] = articles[i].ID
+	}
+	scores, err := s.embeddingService.InterestScores(s.userService.ResolveUserID(userID), ids)
+	if err != nil && !errors.Is(err, ErrEmbeddingsDisabled) {
+		log.Printf("Ranking without interest vector: %v", err)
+	}
+	return scores
+}
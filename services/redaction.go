@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"news-backend/models"
+)
+
+// Article response fields that redaction rules can hide
+const (
+	RedactCoordinates = "coordinates" // latitude, longitude and distance
+	RedactURL         = "url"         // url, image_url and thumbnail_url, all links to the source
+	RedactDescription = "description"
+	RedactSummary     = "summary"
+)
+
+// Redaction rule subjects besides full-access API keys
+const (
+	RedactionSubjectAll  = "*"    // every request
+	RedactionSubjectDemo = "demo" // demo-tier requests
+)
+
+// RedactionRule hides one field, for every source or only the listed ones
+type RedactionRule struct {
+	Field   string
+	Sources map[string]bool // lowercased source names, nil for every source
+}
+
+// RedactionRules maps a subject (an API key, "demo" or "*") to its rules
+type RedactionRules map[string][]RedactionRule
+
+// ParseRedactionRules parses REDACTION_RULES: semicolon-separated
+// "<subject>:<field>,...[@<source>,...]" entries, e.g.
+// "demo:coordinates;*:url@Bloomberg,Reuters"
+func ParseRedactionRules(spec string) (RedactionRules, error) {
+	rules := make(RedactionRules)
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		subject, rest, ok := strings.Cut(entry, ":")
+		subject = strings.TrimSpace(subject)
+		if !ok || subject == "" {
+			return nil, fmt.Errorf("redaction rule %q: expected <subject>:<fields>", entry)
+		}
+
+		fields, sourceList, scoped := strings.Cut(rest, "@")
+		var sources map[string]bool
+		if scoped {
+			sources = make(map[string]bool)
+			for _, source := range strings.Split(sourceList, ",") {
+				if source = strings.ToLower(strings.TrimSpace(source)); source != "" {
+					sources[source] = true
+				}
+			}
+			if len(sources) == 0 {
+				return nil, fmt.Errorf("redaction rule %q: no sources after @", entry)
+			}
+		}
+
+		for _, field := range strings.Split(fields, ",") {
+			field = strings.ToLower(strings.TrimSpace(field))
+			switch field {
+			case RedactCoordinates, RedactURL, RedactDescription, RedactSummary:
+				rules[subject] = append(rules[subject], RedactionRule{Field: field, Sources: sources})
+			default:
+				return nil, fmt.Errorf("redaction rule %q: unknown field %q (expected coordinates, url, description or summary)", entry, field)
+			}
+		}
+	}
+	return rules, nil
+}
+
+// For returns the rules that apply to a subject, including the rules for
+// every request
+func (r RedactionRules) For(subject string) []RedactionRule {
+	rules := r[RedactionSubjectAll]
+	if subject != "" && subject != RedactionSubjectAll {
+		rules = append(rules[:len(rules):len(rules)], r[subject]...)
+	}
+	return rules
+}
+
+type redactionKey struct{}
+
+// WithRedaction sets the redaction rules for the request ctx belongs to
+func WithRedaction(ctx context.Context, rules []RedactionRule) context.Context {
+	if len(rules) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, redactionKey{}, rules)
+}
+
+// RedactionSignature identifies the redaction rules of ctx's request, so
+// cached responses are only shared between requests redacted alike. It is
+// empty when nothing is redacted.
+func RedactionSignature(ctx context.Context) string {
+	rules, _ := ctx.Value(redactionKey{}).([]RedactionRule)
+	if len(rules) == 0 {
+		return ""
+	}
+	parts := make([]string, len(rules))
+	for i, rule := range rules {
+		sources := make([]string, 0, len(rule.Sources))
+		for source := range rule.Sources {
+			sources = append(sources, source)
+		}
+		sort.Strings(sources)
+		parts[i] = rule.Field + "@" + strings.Join(sources, ",")
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ";")
+}
+
+// RedactArticle blanks the fields ctx's redaction rules hide for the
+// article's source and lists them in Redacted. Distances from a saved place
+// are rounded to whole kilometres, so a few of them can't pinpoint it.
+func RedactArticle(ctx context.Context, resp *models.ArticleResponse) {
+	if NearPlace(ctx) != "" {
+		resp.Distance = math.Round(resp.Distance)
+	}
+	rules, _ := ctx.Value(redactionKey{}).([]RedactionRule)
+	source := strings.ToLower(resp.SourceName)
+	for _, rule := range rules {
+		if rule.Sources != nil && !rule.Sources[source] {
+			continue
+		}
+		switch rule.Field {
+		case RedactCoordinates:
+			resp.Latitude, resp.Longitude, resp.Distance = 0, 0, 0
+		case RedactURL:
+			resp.URL, resp.ImageURL, resp.ThumbnailURL = "", "", ""
+		case RedactDescription:
+			resp.Description = ""
+		case RedactSummary:
+			resp.LLMSummary = ""
+		}
+		if !slices.Contains(resp.Redacted, rule.Field) {
+			resp.Redacted = append(resp.Redacted, rule.Field)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"news-backend/config"
+	"news-backend/database"
+	"news-backend/models"
+	"news-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// RelevanceWorker periodically recomputes Article.CurrentRelevance by blending
+// the dataset relevance score with recency-decayed user engagement
+type RelevanceWorker struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+// NewRelevanceWorker creates a new relevance refresh worker
+func NewRelevanceWorker(cfg *config.Config) *RelevanceWorker {
+	return &RelevanceWorker{
+		db:  database.GetDB(),
+		cfg: cfg,
+	}
+}
+
+// Start runs a refresh immediately and then on every configured interval
+// until ctx is cancelled. A non-positive interval disables the worker.
+func (w *RelevanceWorker) Start(ctx context.Context) {
+	if w.cfg.RelevanceRefreshInterval <= 0 {
+		log.Println("Relevance refresh worker disabled")
+		return
+	}
+
+	interval := time.Duration(w.cfg.RelevanceRefreshInterval) * time.Second
+	log.Printf("Relevance refresh worker started (interval: %v)", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Refresh(); err != nil {
+			log.Printf("Relevance refresh failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Println("Relevance refresh worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh recomputes current_relevance for every article
+func (w *RelevanceWorker) Refresh() error {
+	engagement, err := w.engagementByArticle()
+	if err != nil {
+		return err
+	}
+
+	// Normalize engagement against the most engaged article
+	maxEngagement := 0.0
+	for _, value := range engagement {
+		if value > maxEngagement {
+			maxEngagement = value
+		}
+	}
+
+	var articles []models.Article
+	if err := w.db.Select("id", "relevance_score").Find(&articles).Error; err != nil {
+		return fmt.Errorf("failed to load articles: %w", err)
+	}
+
+	weight := w.cfg.RelevanceEngagementWeight
+	err = w.db.Transaction(func(tx *gorm.DB) error {
+		for _, article := range articles {
+			normalized := 0.0
+			if maxEngagement > 0 {
+				normalized = engagement[article.ID] / maxEngagement
+			}
+
+			current := utils.BlendRelevance(article.RelevanceScore, normalized, weight)
+			if err := tx.Model(&models.Article{}).
+				Where("id = ?", article.ID).
+				Update("current_relevance", current).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update current relevance: %w", err)
+	}
+
+	log.Printf("Refreshed current relevance for %d articles (%d with engagement)",
+		len(articles), len(engagement))
+	return nil
+}
+
+// engagementByArticle sums event weights with recency decay per article
+func (w *RelevanceWorker) engagementByArticle() (map[string]float64, error) {
+	since := time.Now().Add(-time.Duration(w.cfg.RelevanceLookbackHours) * time.Hour)
+	return engagementSince(w.db, since)
+}
+
+// engagementSince sums recency-decayed event weights per article for events
+// after since. Hours already rolled up are read from event_rollups, as their
+// raw events may have been deleted.
+func engagementSince(db *gorm.DB, since time.Time) (map[string]float64, error) {
+	watermark, err := rollupWatermark(db)
+	if err != nil {
+		return nil, err
+	}
+	rawSince := since.UTC()
+	if watermark.After(since) {
+		rawSince = watermark
+	}
+
+	var events []models.UserEvent
+	err = db.Select("article_id", "event_type", "timestamp").
+		Where("timestamp >= ?", rawSince).
+		Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user events: %w", err)
+	}
+
+	now := time.Now()
+	engagement := make(map[string]float64)
+	for _, event := range events {
+		hoursAgo := now.Sub(event.Timestamp).Hours()
+		engagement[event.ArticleID] += models.GetEventWeight(event.EventType) *
+			utils.CalculateRecencyFactor(hoursAgo)
+	}
+
+	if watermark.After(since) {
+		var rollups []models.EventRollup
+		err := db.Where("hour >= ? AND hour < ?", since.UTC().Truncate(time.Hour), watermark).
+			Find(&rollups).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch event rollups: %w", err)
+		}
+		for _, rollup := range rollups {
+			// Events are spread over the hour; decay from its middle
+			hoursAgo := now.Sub(rollup.Hour.Add(30 * time.Minute)).Hours()
+			engagement[rollup.ArticleID] += float64(rollup.Count) * models.GetEventWeight(rollup.EventType) *
+				utils.CalculateRecencyFactor(hoursAgo)
+		}
+	}
+
+	return engagement, nil
+}
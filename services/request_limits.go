@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ErrRequestLimit is returned for a parameter beyond the tier's limits when
+// the limits reject rather than clamp
+var ErrRequestLimit = errors.New("request exceeds the limits of this API key")
+
+// RequestLimits caps how much work one request may ask for, so a client
+// can't have the whole corpus scored with radius=20000. Zero caps are
+// unlimited.
+type RequestLimits struct {
+	MaxRadiusKm   float64
+	MaxWindowDays int  // widest from/to span
+	MaxArticles   int  // largest limit parameter
+	Reject        bool // reject excessive values instead of clamping them
+}
+
+// requestGuard applies a request's limits and remembers what it clamped.
+// Limits are applied on the request goroutine, so it needs no locking.
+type requestGuard struct {
+	limits  RequestLimits
+	clamped map[string]string
+}
+
+type requestLimitsKey struct{}
+
+// WithRequestLimits sets the limits for the request ctx belongs to
+func WithRequestLimits(ctx context.Context, limits RequestLimits) context.Context {
+	return context.WithValue(ctx, requestLimitsKey{}, &requestGuard{limits: limits})
+}
+
+// ClampedParams returns the parameters clamped so far for ctx's request,
+// with the values applied instead, or nil when nothing was clamped
+func ClampedParams(ctx context.Context) map[string]string {
+	guard, ok := ctx.Value(requestLimitsKey{}).(*requestGuard)
+	if !ok || len(guard.clamped) == 0 {
+		return nil
+	}
+	clamped := make(map[string]string, len(guard.clamped))
+	for param, value := range guard.clamped {
+		clamped[param] = value
+	}
+	return clamped
+}
+
+// LimitRadius caps a radius in km (0 meaning the default) to the request's
+// limit
+func LimitRadius(ctx context.Context, radius float64) (float64, error) {
+	guard, ok := ctx.Value(requestLimitsKey{}).(*requestGuard)
+	if !ok || guard.limits.MaxRadiusKm <= 0 || radius <= guard.limits.MaxRadiusKm {
+		return radius, nil
+	}
+	maxRadius := guard.limits.MaxRadiusKm
+	if err := guard.exceeded("radius", strconv.FormatFloat(maxRadius, 'f', -1, 64),
+		fmt.Sprintf("radius may be at most %g km", maxRadius)); err != nil {
+		return radius, err
+	}
+	return maxRadius, nil
+}
+
+// LimitArticles caps an article limit (0 meaning the default) to the
+// request's limit
+func LimitArticles(ctx context.Context, limit int) (int, error) {
+	guard, ok := ctx.Value(requestLimitsKey{}).(*requestGuard)
+	if !ok || guard.limits.MaxArticles <= 0 || limit <= guard.limits.MaxArticles {
+		return limit, nil
+	}
+	maxArticles := guard.limits.MaxArticles
+	if err := guard.exceeded("limit", strconv.Itoa(maxArticles),
+		fmt.Sprintf("limit may be at most %d", maxArticles)); err != nil {
+		return limit, err
+	}
+	return maxArticles, nil
+}
+
+// LimitWindow narrows a from/to window to the request's limit by moving
+// from forward; an open end counts as now. Windows without from are left
+// alone.
+func LimitWindow(ctx context.Context, from, to time.Time) (time.Time, error) {
+	guard, ok := ctx.Value(requestLimitsKey{}).(*requestGuard)
+	if !ok || guard.limits.MaxWindowDays <= 0 || from.IsZero() {
+		return from, nil
+	}
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+	earliest := to.AddDate(0, 0, -guard.limits.MaxWindowDays)
+	if !from.Before(earliest) {
+		return from, nil
+	}
+	if err := guard.exceeded("from", earliest.Format(time.RFC3339),
+		fmt.Sprintf("from/to may span at most %d days", guard.limits.MaxWindowDays)); err != nil {
+		return from, err
+	}
+	return earliest, nil
+}
+
+// exceeded rejects an excessive parameter or records that it was clamped
+// to value
+func (g *requestGuard) exceeded(param, value, reason string) error {
+	if g.limits.Reject {
+		return fmt.Errorf("%w: %s", ErrRequestLimit, reason)
+	}
+	if g.clamped == nil {
+		g.clamped = make(map[string]string)
+	}
+	g.clamped[param] = value
+	return nil
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Article text that keyword search can match against
+const (
+	SearchFieldTitle       = "title"
+	SearchFieldDescription = "description"
+	SearchFieldSummary     = "summary" // the stored LLM summary
+	SearchFieldTopics      = "topics"  // names of the topics extracted from the article
+	SearchFieldContent     = "content" // body text fetched from the article page
+)
+
+// ParseSearchFields parses a comma-separated list of search fields, e.g.
+// SEARCH_FIELDS or the search_fields parameter. Duplicates are dropped.
+func ParseSearchFields(spec string) ([]string, error) {
+	var fields []string
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field == "" || seen[field] {
+			continue
+		}
+		switch field {
+		case SearchFieldTitle, SearchFieldDescription, SearchFieldSummary, SearchFieldTopics, SearchFieldContent:
+			seen[field] = true
+			fields = append(fields, field)
+		default:
+			return nil, fmt.Errorf("unknown search field %q (expected title, description, summary, topics or content)", field)
+		}
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no search fields given")
+	}
+	return fields, nil
+}
+
+// searchFieldCondition returns the SQL condition matching searchText
+// against one search field: a case-insensitive substring match, or a phrase
+// match in the full-text index for content
+func (s *NewsService) searchFieldCondition(field, searchText string) (string, []interface{}) {
+	pattern := "%" + strings.ToLower(searchText) + "%"
+	switch field {
+	case SearchFieldDescription:
+		return "LOWER(description) LIKE ?", []interface{}{pattern}
+	case SearchFieldSummary:
+		return "LOWER(llm_summary) LIKE ?", []interface{}{pattern}
+	case SearchFieldTopics:
+		return "id IN (?)", []interface{}{s.topicMatches(pattern)}
+	case SearchFieldContent:
+		return "id IN (?)", []interface{}{s.contentMatches(searchText)}
+	default:
+		return "LOWER(title) LIKE ?", []interface{}{pattern}
+	}
+}
+
+// contentMatches selects the IDs of articles whose fetched body contains
+// searchText as a phrase, ignoring case and punctuation
+func (s *NewsService) contentMatches(searchText string) *gorm.DB {
+	phrase := `"` + strings.ReplaceAll(searchText, `"`, " ") + `"`
+	return s.db.Table("article_contents").
+		Select("article_id").
+		Where("rowid IN (SELECT docid FROM article_contents_fts WHERE article_contents_fts MATCH ?)", phrase)
+}
+
+// topicMatches selects the IDs of articles tagged with a topic whose name
+// matches a lowercased LIKE pattern
+func (s *NewsService) topicMatches(pattern string) *gorm.DB {
+	return s.db.Table("article_topics").
+		Select("article_topics.article_id").
+		Joins("JOIN topics ON topics.id = article_topics.topic_id").
+		Where("LOWER(topics.name) LIKE ?", pattern)
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"news-backend/config"
+	"news-backend/database"
+	"news-backend/models"
+	"news-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// Sentiment classifiers selectable with SENTIMENT_CLASSIFIER
+const (
+	SentimentClassifierLexicon = "lexicon"
+	SentimentClassifierLLM     = "llm"
+)
+
+// SentimentWorker tags articles without a sentiment with their sentiment
+// and tone, newest first, so sentiment= filters can match them
+type SentimentWorker struct {
+	db         *gorm.DB
+	cfg        *config.Config
+	llmService *LLMService
+}
+
+// NewSentimentWorker creates a new sentiment tagging worker
+func NewSentimentWorker(cfg *config.Config, llmService *LLMService) *SentimentWorker {
+	return &SentimentWorker{
+		db:         database.GetDB(),
+		cfg:        cfg,
+		llmService: llmService,
+	}
+}
+
+// Start processes one batch per configured interval until ctx is cancelled
+// A non-positive interval disables the worker.
+func (w *SentimentWorker) Start(ctx context.Context) {
+	if w.cfg.SentimentWorkerInterval <= 0 {
+		log.Println("Sentiment tagging worker disabled")
+		return
+	}
+
+	interval := time.Duration(w.cfg.SentimentWorkerInterval) * time.Second
+	log.Printf("Sentiment tagging worker started (classifier: %s, interval: %v, batch: %d)",
+		w.cfg.SentimentClassifier, interval, w.cfg.SentimentWorkerBatch)
+
+	// Tag what is already there without waiting a full interval
+	w.runBatch(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Sentiment tagging worker stopped")
+			return
+		case <-ticker.C:
+			w.runBatch(ctx)
+		}
+	}
+}
+
+// runBatch processes a batch and logs the outcome
+func (w *SentimentWorker) runBatch(ctx context.Context) {
+	if tagged, err := w.ProcessBatch(ctx); err != nil {
+		log.Printf("Sentiment tagging failed: %v", err)
+	} else if tagged > 0 {
+		log.Printf("Tagged sentiment of %d articles", tagged)
+	}
+}
+
+// ProcessBatch tags up to SentimentWorkerBatch articles without a sentiment
+// With the LLM classifier it stops early on the first LLM failure.
+func (w *SentimentWorker) ProcessBatch(ctx context.Context) (int, error) {
+	var articles []models.Article
+	err := w.db.Select("id", "title", "description").
+		Where("sentiment = '' OR sentiment IS NULL").
+		Order("publication_date DESC").
+		Limit(w.cfg.SentimentWorkerBatch).
+		Find(&articles).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to load articles without sentiment: %w", err)
+	}
+
+	tagged := 0
+	for _, article := range articles {
+		if ctx.Err() != nil {
+			break
+		}
+
+		sentiment, tone, err := w.classify(ctx, article.Title+". "+article.Description)
+		if err != nil {
+			return tagged, err
+		}
+
+		if err := w.db.Model(&models.Article{}).
+			Where("id = ?", article.ID).
+			Updates(map[string]interface{}{"sentiment": sentiment, "tone": tone}).Error; err != nil {
+			return tagged, fmt.Errorf("failed to store sentiment for %s: %w", article.ID, err)
+		}
+		tagged++
+	}
+
+	return tagged, nil
+}
+
+// classify tags text with the configured classifier
+func (w *SentimentWorker) classify(ctx context.Context, text string) (string, string, error) {
+	if w.cfg.SentimentClassifier == SentimentClassifierLLM {
+		return w.llmService.ClassifySentiment(ctx, text)
+	}
+	return utils.ClassifySentiment(text), utils.ClassifyTone(text), nil
+}
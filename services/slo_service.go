@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"news-backend/config"
+	"news-backend/metrics"
+)
+
+// Burn rates are evaluated over a short and a long window; an alert needs
+// both to exceed the threshold so brief spikes and stale incidents don't page
+const (
+	sloShortWindow = 5 * time.Minute
+	sloLongWindow  = time.Hour
+)
+
+// SLI names
+const (
+	SLIAvailability = "availability"
+	SLILatency      = "latency"
+)
+
+// SLOStatus is the current compliance of one endpoint with one SLI
+type SLOStatus struct {
+	Endpoint      string  `json:"endpoint"`
+	SLI           string  `json:"sli"`
+	Target        float64 `json:"target"`
+	Requests      int64   `json:"requests"`     // in the long window
+	Compliance    float64 `json:"compliance"`   // good / total over the long window
+	BudgetLeft    float64 `json:"budget_left"`  // share of the error budget remaining
+	BurnRateShort float64 `json:"burn_rate_5m"` // budget consumption speed, 1 = exactly on budget
+	BurnRateLong  float64 `json:"burn_rate_1h"`
+	Alerting      bool    `json:"alerting"`
+	// TooFewRequests is set when a window has fewer than SLO_MIN_REQUESTS
+	// requests, so a handful of failures can't page
+	TooFewRequests bool `json:"too_few_requests,omitempty"`
+}
+
+// SLOService tracks per-endpoint availability and latency SLOs from the
+// metrics registry and alerts on fast error-budget burn
+type SLOService struct {
+	cfg            *config.Config
+	registry       *metrics.Registry
+	webhookService *WebhookService
+
+	mu          sync.Mutex
+	lastAlerted map[string]time.Time // endpoint+SLI -> last alert time
+}
+
+// NewSLOService creates a new SLO tracking service
+func NewSLOService(cfg *config.Config, registry *metrics.Registry, webhookService *WebhookService) *SLOService {
+	return &SLOService{
+		cfg:            cfg,
+		registry:       registry,
+		webhookService: webhookService,
+		lastAlerted:    make(map[string]time.Time),
+	}
+}
+
+// Start evaluates burn rates on every configured interval until ctx is
+// cancelled. A non-positive interval disables alerting.
+func (s *SLOService) Start(ctx context.Context) {
+	if s.cfg.SLOCheckInterval <= 0 {
+		log.Println("SLO burn-rate alerting disabled")
+		return
+	}
+
+	interval := time.Duration(s.cfg.SLOCheckInterval) * time.Second
+	log.Printf("SLO burn-rate alerting started (interval: %v)", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("SLO burn-rate alerting stopped")
+			return
+		case <-ticker.C:
+			s.CheckBurnRates()
+		}
+	}
+}
+
+// Report returns the current SLO status of every observed endpoint
+func (s *SLOService) Report() []SLOStatus {
+	var statuses []SLOStatus
+	for _, endpoint := range s.registry.Endpoints() {
+		short := s.registry.Window(endpoint, sloShortWindow)
+		long := s.registry.Window(endpoint, sloLongWindow)
+
+		threshold := time.Duration(s.cfg.SLOLatencyThresholdMs) * time.Millisecond
+		statuses = append(statuses,
+			s.status(endpoint, SLIAvailability, s.cfg.SLOAvailabilityTarget,
+				short.Total-short.Errors, short.Total, long.Total-long.Errors, long.Total),
+			s.status(endpoint, SLILatency, s.cfg.SLOLatencyTarget,
+				short.CountWithin(threshold), short.Total, long.CountWithin(threshold), long.Total),
+		)
+	}
+	return statuses
+}
+
+// status computes compliance and burn rates from good/total counts
+func (s *SLOService) status(endpoint, sli string, target float64, shortGood, shortTotal, longGood, longTotal int64) SLOStatus {
+	status := SLOStatus{
+		Endpoint:   endpoint,
+		SLI:        sli,
+		Target:     target,
+		Requests:   longTotal,
+		Compliance: 1,
+		BudgetLeft: 1,
+	}
+
+	budget := 1 - target
+	if longTotal > 0 {
+		status.Compliance = float64(longGood) / float64(longTotal)
+		status.BurnRateLong = burnRate(longGood, longTotal, budget)
+		if budget > 0 {
+			status.BudgetLeft = 1 - (1-status.Compliance)/budget
+		}
+	}
+	status.BurnRateShort = burnRate(shortGood, shortTotal, budget)
+
+	threshold := s.cfg.SLOBurnRateThreshold
+	burning := status.BurnRateShort >= threshold && status.BurnRateLong >= threshold
+	status.TooFewRequests = min(shortTotal, longTotal) < int64(s.cfg.SLOMinRequests)
+	status.Alerting = burning && !status.TooFewRequests
+	return status
+}
+
+// burnRate is the observed bad-event ratio divided by the allowed ratio
+func burnRate(good, total int64, budget float64) float64 {
+	if total == 0 || budget <= 0 {
+		return 0
+	}
+	return (float64(total-good) / float64(total)) / budget
+}
+
+// CheckBurnRates emits an slo.burn_rate webhook for every alerting endpoint,
+// at most once per long window per endpoint and SLI
+func (s *SLOService) CheckBurnRates() {
+	now := time.Now()
+	for _, status := range s.Report() {
+		if !status.Alerting {
+			continue
+		}
+
+		key := status.Endpoint + "|" + status.SLI
+		s.mu.Lock()
+		recent := now.Sub(s.lastAlerted[key]) < sloLongWindow
+		if !recent {
+			s.lastAlerted[key] = now
+		}
+		s.mu.Unlock()
+		if recent {
+			continue
+		}
+
+		log.Printf("SLO alert: %s %s burning error budget at %.1fx (5m) / %.1fx (1h)",
+			status.Endpoint, status.SLI, status.BurnRateShort, status.BurnRateLong)
+		s.webhookService.Emit(WebhookSLOBurnRate, status)
+	}
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"news-backend/config"
+	"news-backend/metrics"
+)
+
+func TestSLOStatusNeedsMinimumRequests(t *testing.T) {
+	service := NewSLOService(&config.Config{SLOBurnRateThreshold: 14.4, SLOMinRequests: 20}, nil, nil)
+
+	tests := []struct {
+		name                  string
+		shortGood, shortTotal int64
+		longGood, longTotal   int64
+		alerting, tooFew      bool
+	}{
+		{"Burning with enough requests", 10, 20, 80, 100, true, false},
+		{"One failure in a few requests", 2, 3, 2, 3, false, true},
+		{"Short window below the minimum", 10, 19, 80, 100, false, true},
+		{"Long window below the minimum", 10, 20, 10, 19, false, true},
+		{"Enough requests, within budget", 20, 20, 100, 100, false, false},
+		{"No requests", 0, 0, 0, 0, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := service.status("/news", SLIAvailability, 0.99, tt.shortGood, tt.shortTotal, tt.longGood, tt.longTotal)
+			if status.Alerting != tt.alerting || status.TooFewRequests != tt.tooFew {
+				t.Errorf("status() alerting = %v, too few requests = %v (burn %.1f/%.1f), expected %v and %v",
+					status.Alerting, status.TooFewRequests, status.BurnRateShort, status.BurnRateLong, tt.alerting, tt.tooFew)
+			}
+		})
+	}
+
+	// Without a minimum, a single failing request can alert
+	service.cfg.SLOMinRequests = 0
+	if status := service.status("/news", SLIAvailability, 0.99, 0, 1, 0, 1); !status.Alerting {
+		t.Error("status() with SLO_MIN_REQUESTS=0 didn't alert on a failed request")
+	}
+}
+
+func TestSLOReportCountsRegistryWindows(t *testing.T) {
+	registry := metrics.NewRegistry()
+	cfg := &config.Config{SLOAvailabilityTarget: 0.99, SLOLatencyTarget: 0.95, SLOLatencyThresholdMs: 1000,
+		SLOBurnRateThreshold: 14.4, SLOMinRequests: 20}
+	service := NewSLOService(cfg, registry, nil)
+
+	// A quiet route failing its only requests, and a busy one failing half
+	for range 2 {
+		registry.Observe("/quiet", http.StatusInternalServerError, time.Millisecond)
+	}
+	for i := range 40 {
+		status := http.StatusOK
+		if i%2 == 0 {
+			status = http.StatusInternalServerError
+		}
+		registry.Observe("/busy", status, time.Millisecond)
+	}
+
+	alerting := map[string]bool{}
+	for _, status := range service.Report() {
+		if status.SLI == SLIAvailability {
+			alerting[status.Endpoint] = status.Alerting
+		}
+	}
+	if alerting["/quiet"] || !alerting["/busy"] {
+		t.Errorf("availability alerting = %v, expected only the busy route", alerting)
+	}
+}
@@ -0,0 +1,349 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"news-backend/config"
+	"news-backend/database"
+	"news-backend/eventschema"
+	"news-backend/models"
+	"news-backend/objectstore"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// SnapshotFormatVersion changes whenever a snapshot file's layout does
+const SnapshotFormatVersion = 1
+
+// Snapshot files, in the order they are written. Each is gzipped JSON
+// Lines, one row per line, in a stable order.
+const (
+	SnapshotArticlesFile   = "articles.jsonl.gz"
+	SnapshotEventsFile     = "user_events.jsonl.gz"
+	SnapshotRollupsFile    = "event_rollups.jsonl.gz"
+	SnapshotEngagementFile = "engagement.jsonl.gz"
+	snapshotManifestFile   = "manifest.json"
+)
+
+// ErrSnapshotRunning is returned while another snapshot is being taken
+var ErrSnapshotRunning = errors.New("a snapshot is already running")
+
+// SnapshotFile describes one exported file
+type SnapshotFile struct {
+	Name   string `json:"name"`
+	Rows   int64  `json:"rows"`
+	Bytes  int64  `json:"bytes"`
+	SHA256 string `json:"sha256"` // of the gzipped file
+}
+
+// SnapshotManifest describes a snapshot. It is uploaded after every file,
+// so a snapshot without one is incomplete.
+type SnapshotManifest struct {
+	ID                 string    `json:"id"`
+	CreatedAt          time.Time `json:"created_at"`
+	AsOf               time.Time `json:"as_of"` // every row committed before this is included, none after
+	FormatVersion      int       `json:"format_version"`
+	EventSchemaVersion int       `json:"event_schema_version"`
+	// RollupWatermark is where raw events take over from event_rollups in
+	// engagement counts; events before it may only be in the rollups
+	RollupWatermark *time.Time `json:"rollup_watermark,omitempty"`
+	// StableUserIDs is set when user and device IDs are pseudonymized with
+	// SNAPSHOT_PSEUDONYM_KEY, so they match across snapshots
+	StableUserIDs bool           `json:"stable_user_ids"`
+	Location      string         `json:"location"` // of the manifest
+	Files         []SnapshotFile `json:"files"`
+}
+
+// snapshotEngagement is a row of engagement.jsonl.gz: an article's
+// engagement over all time, as in GetArticleEngagement
+type snapshotEngagement struct {
+	ArticleID string `json:"article_id"`
+	EngagementCounts
+	UniqueUsers int64 `json:"unique_users"` // over the raw events in the snapshot
+}
+
+// SnapshotService exports a point-in-time consistent copy of articles,
+// user events and engagement aggregates to object storage, so ranking
+// models can be trained on reproducible datasets. User and device IDs are
+// pseudonymized.
+type SnapshotService struct {
+	db    *gorm.DB
+	cfg   *config.Config
+	store objectstore.Store
+	mu    sync.Mutex // held while a snapshot is taken
+}
+
+// NewSnapshotService creates a new snapshot service instance writing to store
+func NewSnapshotService(cfg *config.Config, store objectstore.Store) *SnapshotService {
+	return &SnapshotService{
+		db:    database.GetDB(),
+		cfg:   cfg,
+		store: store,
+	}
+}
+
+// Create takes a snapshot. The database is first copied with VACUUM INTO,
+// which reads it in one transaction, so the files agree with each other
+// and writers are only held up for the copy, not the export. Files are
+// uploaded under the snapshot ID, then the manifest.
+func (s *SnapshotService) Create(ctx context.Context) (*SnapshotManifest, error) {
+	if !s.mu.TryLock() {
+		return nil, ErrSnapshotRunning
+	}
+	defer s.mu.Unlock()
+
+	key, stable, err := s.pseudonymKey()
+	if err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp("", "snapshot-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	asOf := time.Now().UTC()
+	copyPath := filepath.Join(dir, "news.db")
+	if err := s.db.WithContext(ctx).Exec("VACUUM INTO ?", copyPath).Error; err != nil {
+		return nil, fmt.Errorf("failed to copy database: %w", err)
+	}
+	snapshotDB, err := gorm.Open(sqlite.Open(copyPath), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database copy: %w", err)
+	}
+	sqlDB, err := snapshotDB.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database copy: %w", err)
+	}
+	defer sqlDB.Close()
+	snapshotDB = snapshotDB.WithContext(ctx)
+
+	manifest := &SnapshotManifest{
+		ID:                 asOf.Format("20060102T150405Z"),
+		AsOf:               asOf,
+		FormatVersion:      SnapshotFormatVersion,
+		EventSchemaVersion: eventschema.CurrentVersion,
+		StableUserIDs:      stable,
+	}
+	watermark, err := rollupWatermark(snapshotDB)
+	if err != nil {
+		return nil, err
+	}
+	if !watermark.IsZero() {
+		manifest.RollupWatermark = &watermark
+	}
+
+	pseudonym := func(id string) string {
+		if id == "" {
+			return ""
+		}
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(id))
+		return hex.EncodeToString(mac.Sum(nil)[:16])
+	}
+	exports := []struct {
+		name  string
+		write func(*snapshotWriter) error
+	}{
+		{SnapshotArticlesFile, func(w *snapshotWriter) error {
+			return exportRows(snapshotDB.Order("id"), w, func(article *models.Article) interface{} { return article })
+		}},
+		{SnapshotEventsFile, func(w *snapshotWriter) error {
+			return exportRows(snapshotDB.Order("id"), w, func(event *models.UserEvent) interface{} {
+				event.UserID = pseudonym(event.UserID)
+				event.DeviceID = pseudonym(event.DeviceID)
+				return event
+			})
+		}},
+		{SnapshotRollupsFile, func(w *snapshotWriter) error {
+			return exportRows(snapshotDB.Order("hour, article_id, event_type"), w,
+				func(rollup *models.EventRollup) interface{} { return rollup })
+		}},
+		{SnapshotEngagementFile, func(w *snapshotWriter) error {
+			return exportEngagement(snapshotDB, watermark, w)
+		}},
+	}
+	for _, export := range exports {
+		file, err := s.export(ctx, dir, manifest.ID, export.name, export.write)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Files = append(manifest.Files, file)
+	}
+
+	manifest.CreatedAt = time.Now().UTC()
+	manifestKey := manifest.ID + "/" + snapshotManifestFile
+	manifest.Location = s.store.Location(manifestKey)
+	body, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := s.store.Put(ctx, manifestKey, bytes.NewReader(body), int64(len(body)), "application/json"); err != nil {
+		return nil, fmt.Errorf("failed to upload manifest: %w", err)
+	}
+	log.Printf("Snapshot %s written to %s", manifest.ID, manifest.Location)
+	return manifest, nil
+}
+
+// pseudonymKey returns SNAPSHOT_PSEUDONYM_KEY, or a random key and false
+// when it isn't set, so IDs can't be linked across snapshots
+func (s *SnapshotService) pseudonymKey() ([]byte, bool, error) {
+	if s.cfg.SnapshotPseudonymKey != "" {
+		return []byte(s.cfg.SnapshotPseudonymKey), true, nil
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, false, fmt.Errorf("failed to generate pseudonym key: %w", err)
+	}
+	return key, false, nil
+}
+
+// export writes one file with write and uploads it
+func (s *SnapshotService) export(ctx context.Context, dir, id, name string, write func(*snapshotWriter) error) (SnapshotFile, error) {
+	f, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		return SnapshotFile{}, fmt.Errorf("failed to create %s: %w", name, err)
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	counter := &countingWriter{w: io.MultiWriter(f, hash)}
+	w := &snapshotWriter{gz: gzip.NewWriter(counter)}
+	w.enc = json.NewEncoder(w.gz)
+	if err := write(w); err != nil {
+		return SnapshotFile{}, fmt.Errorf("failed to export %s: %w", name, err)
+	}
+	if err := w.gz.Close(); err != nil {
+		return SnapshotFile{}, fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	file := SnapshotFile{Name: name, Rows: w.rows, Bytes: counter.n, SHA256: hex.EncodeToString(hash.Sum(nil))}
+	if err := s.store.Put(ctx, id+"/"+name, f, file.Bytes, "application/gzip"); err != nil {
+		return SnapshotFile{}, fmt.Errorf("failed to upload %s: %w", name, err)
+	}
+	return file, nil
+}
+
+// snapshotWriter writes rows of one file
+type snapshotWriter struct {
+	gz   *gzip.Writer
+	enc  *json.Encoder
+	rows int64
+}
+
+func (w *snapshotWriter) write(row interface{}) error {
+	w.rows++
+	return w.enc.Encode(row)
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// exportRows streams the rows of T's table in query's order, writing each
+// as row returns it
+func exportRows[T any](query *gorm.DB, w *snapshotWriter, row func(*T) interface{}) error {
+	rows, err := query.Model(new(T)).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var record T
+		if err := query.ScanRows(rows, &record); err != nil {
+			return err
+		}
+		if err := w.write(row(&record)); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// exportEngagement writes each article's engagement counts, from the
+// rollups before watermark and the raw events after it, ordered by article
+func exportEngagement(db *gorm.DB, watermark time.Time, w *snapshotWriter) error {
+	var typeCounts []struct {
+		ArticleID string
+		EventType string
+		Count     int64
+	}
+	err := db.Raw(`SELECT article_id, event_type, SUM(n) AS count FROM (
+			SELECT article_id, event_type, count AS n FROM event_rollups
+			UNION ALL
+			SELECT article_id, event_type, 1 AS n FROM user_events WHERE timestamp >= ?
+		) GROUP BY article_id, event_type`, watermark).Scan(&typeCounts).Error
+	if err != nil {
+		return fmt.Errorf("failed to count events: %w", err)
+	}
+	var userCounts []struct {
+		ArticleID string
+		Users     int64
+	}
+	err = db.Model(&models.UserEvent{}).
+		Select("article_id, COUNT(DISTINCT user_id) AS users").
+		Group("article_id").
+		Scan(&userCounts).Error
+	if err != nil {
+		return fmt.Errorf("failed to count article users: %w", err)
+	}
+
+	byArticle := map[string]*snapshotEngagement{}
+	get := func(articleID string) *snapshotEngagement {
+		if byArticle[articleID] == nil {
+			byArticle[articleID] = &snapshotEngagement{ArticleID: articleID}
+		}
+		return byArticle[articleID]
+	}
+	for _, tc := range typeCounts {
+		engagement := get(tc.ArticleID)
+		switch tc.EventType {
+		case models.EventTypeView:
+			engagement.Views += tc.Count
+		case models.EventTypeClick:
+			engagement.Clicks += tc.Count
+		case models.EventTypeShare:
+			engagement.Shares += tc.Count
+		}
+	}
+	for _, uc := range userCounts {
+		get(uc.ArticleID).UniqueUsers = uc.Users
+	}
+
+	articleIDs := make([]string, 0, len(byArticle))
+	for articleID := range byArticle {
+		articleIDs = append(articleIDs, articleID)
+	}
+	sort.Strings(articleIDs)
+	for _, articleID := range articleIDs {
+		if err := w.write(byArticle[articleID]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
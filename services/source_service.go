@@ -0,0 +1,145 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"news-backend/config"
+	"news-backend/database"
+	"news-backend/models"
+	"news-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidReliability is returned for reliability scores outside [0, 1]
+var ErrInvalidReliability = errors.New("reliability_score must be between 0 and 1")
+
+// SourceRating is a source's reliability as ranking sees it
+type SourceRating struct {
+	Name                string   `json:"name"`
+	Enabled             bool     `json:"enabled"`
+	ExcludeFromTrending bool     `json:"exclude_from_trending"`
+	ReliabilityScore    *float64 `json:"reliability_score"` // nil when unrated
+	Reliability         float64  `json:"reliability"`       // the score, or SOURCE_DEFAULT_RELIABILITY
+	Factor              float64  `json:"factor"`            // search and trending score multiplier
+}
+
+// SourceService manages source reliability ratings
+type SourceService struct {
+	db              *gorm.DB
+	cfg             *config.Config
+	trendingService *TrendingService
+}
+
+// NewSourceService creates a new source service instance
+func NewSourceService(cfg *config.Config, trendingService *TrendingService) *SourceService {
+	return &SourceService{
+		db:              database.GetDB(),
+		cfg:             cfg,
+		trendingService: trendingService,
+	}
+}
+
+// List returns every known source's rating, by name
+func (s *SourceService) List() ([]SourceRating, error) {
+	var sources []models.Source
+	if err := s.db.Order("name").Find(&sources).Error; err != nil {
+		return nil, fmt.Errorf("failed to load sources: %w", err)
+	}
+
+	ratings := make([]SourceRating, len(sources))
+	for i := range sources {
+		ratings[i] = s.rating(&sources[i])
+	}
+	return ratings, nil
+}
+
+// SetReliability rates a source, creating its record if it doesn't exist
+// yet; nil clears the rating. Trending is recomputed with the new rating.
+func (s *SourceService) SetReliability(name string, score *float64) (*SourceRating, error) {
+	if score != nil && (*score < 0 || *score > 1) {
+		return nil, ErrInvalidReliability
+	}
+
+	source := models.Source{Name: name, Enabled: true}
+	err := s.db.Where(models.Source{Name: name}).
+		Attrs(source).
+		FirstOrCreate(&source).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load source: %w", err)
+	}
+	if err := s.db.Model(&source).Update("reliability_score", score).Error; err != nil {
+		return nil, fmt.Errorf("failed to rate source: %w", err)
+	}
+	source.ReliabilityScore = score
+
+	s.trendingService.InvalidateCache()
+	rating := s.rating(&source)
+	return &rating, nil
+}
+
+// rating describes a source's reliability
+func (s *SourceService) rating(source *models.Source) SourceRating {
+	reliability := s.cfg.SourceDefaultReliability
+	if source.ReliabilityScore != nil {
+		reliability = *source.ReliabilityScore
+	}
+	return SourceRating{
+		Name:                source.Name,
+		Enabled:             source.Enabled,
+		ExcludeFromTrending: source.ExcludeFromTrending,
+		ReliabilityScore:    source.ReliabilityScore,
+		Reliability:         reliability,
+		Factor:              utils.ReliabilityFactor(reliability, s.cfg.SourceDefaultReliability, s.cfg.SourceReliabilityWeight),
+	}
+}
+
+// sourceRatings maps rated sources' names to their reliability score
+type sourceRatings map[string]float64
+
+// loadSourceRatings returns the reliability score of every rated source
+func loadSourceRatings(db *gorm.DB) (sourceRatings, error) {
+	var sources []models.Source
+	if err := db.Select("name", "reliability_score").Where("reliability_score IS NOT NULL").Find(&sources).Error; err != nil {
+		return nil, fmt.Errorf("failed to load source ratings: %w", err)
+	}
+
+	ratings := make(sourceRatings, len(sources))
+	for _, source := range sources {
+		ratings[source.Name] = *source.ReliabilityScore
+	}
+	return ratings, nil
+}
+
+// factor returns the score multiplier of a source's articles; unrated
+// sources are rated SOURCE_DEFAULT_RELIABILITY, which leaves scores alone
+func (r sourceRatings) factor(cfg *config.Config, sourceName string) float64 {
+	reliability, ok := r[sourceName]
+	if !ok {
+		return 1
+	}
+	return utils.ReliabilityFactor(reliability, cfg.SourceDefaultReliability, cfg.SourceReliabilityWeight)
+}
+
+// articleFactors returns the score multiplier of each article from a rated
+// source, keyed by article ID
+func (r sourceRatings) articleFactors(cfg *config.Config, articles []models.Article) map[string]float64 {
+	factors := make(map[string]float64)
+	for _, article := range articles {
+		if _, ok := r[article.SourceName]; ok {
+			factors[article.ID] = r.factor(cfg, article.SourceName)
+		}
+	}
+	return factors
+}
+
+// filterMinSourceScore restricts an article query to sources rated at least
+// minScore, unrated sources counting as SOURCE_DEFAULT_RELIABILITY
+func filterMinSourceScore(db *gorm.DB, cfg *config.Config, query *gorm.DB, minScore float64) *gorm.DB {
+	sources := db.Model(&models.Source{}).Select("name")
+	if minScore <= cfg.SourceDefaultReliability {
+		return query.Where("source_name NOT IN (?)", sources.Where("reliability_score < ?", minScore))
+	}
+	return query.Where("source_name IN (?)", sources.Where("reliability_score >= ?", minScore))
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"news-backend/config"
+	"news-backend/database"
+	"news-backend/models"
+	"news-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// storyShingleSize is the character shingle length used to compare articles
+const storyShingleSize = 5
+
+// Story is a cluster of near-duplicate articles covering the same event
+type Story struct {
+	ID             string
+	Representative models.Article   // highest-relevance article in the story
+	Articles       []models.Article // all articles, representative first
+}
+
+// StoryService groups near-duplicate articles into stories. Articles whose
+// title+description shingles overlap by at least STORY_SIMILARITY_THRESHOLD
+// (Jaccard) and were published within STORY_WINDOW_HOURS of each other share
+// a story_id, which is the ID of the story's earliest article.
+type StoryService struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+// NewStoryService creates a new story clustering service
+func NewStoryService(cfg *config.Config) *StoryService {
+	return &StoryService{
+		db:  database.GetDB(),
+		cfg: cfg,
+	}
+}
+
+// Start clusters immediately and then on every configured interval until ctx
+// is cancelled. A non-positive interval disables the worker.
+func (s *StoryService) Start(ctx context.Context) {
+	if s.cfg.StoryClusterInterval <= 0 {
+		log.Println("Story clustering worker disabled")
+		return
+	}
+
+	interval := time.Duration(s.cfg.StoryClusterInterval) * time.Second
+	log.Printf("Story clustering worker started (interval: %v)", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Cluster(); err != nil {
+			log.Printf("Story clustering failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Println("Story clustering worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Cluster recomputes story_id for every article and saves the ones that changed
+func (s *StoryService) Cluster() error {
+	var articles []models.Article
+	err := s.db.Select("id", "title", "description", "publication_date", "story_id").
+		Order("publication_date ASC, id ASC").
+		Find(&articles).Error
+	if err != nil {
+		return fmt.Errorf("failed to load articles: %w", err)
+	}
+
+	roots := s.clusterArticles(articles)
+
+	// Group changed articles by their new story so each story is one UPDATE
+	changed := make(map[string][]string)
+	stories := make(map[string]bool)
+	for i, article := range articles {
+		storyID := articles[roots[i]].ID
+		stories[storyID] = true
+		if article.StoryID != storyID {
+			changed[storyID] = append(changed[storyID], article.ID)
+		}
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		for storyID, ids := range changed {
+			if err := tx.Model(&models.Article{}).
+				Where("id IN ?", ids).
+				Update("story_id", storyID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update story ids: %w", err)
+	}
+
+	log.Printf("Clustered %d articles into %d stories (%d stories changed)",
+		len(articles), len(stories), len(changed))
+	return nil
+}
+
+// clusterArticles returns, for each article, the index of the earliest
+// article in its story. articles must be sorted by publication date.
+func (s *StoryService) clusterArticles(articles []models.Article) []int {
+	window := time.Duration(s.cfg.StoryWindowHours) * time.Hour
+	threshold := s.cfg.StorySimilarityThreshold
+
+	parent := make([]int, len(articles))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	// Inverted index from shingle to earlier articles containing it, so only
+	// articles sharing at least one shingle are compared
+	shingles := make([][]string, len(articles))
+	index := make(map[string][]int)
+	for i, article := range articles {
+		shingles[i] = utils.Shingles(article.Title+" "+article.Description, storyShingleSize)
+
+		shared := make(map[int]int)
+		for _, shingle := range shingles[i] {
+			postings := index[shingle]
+			// Postings are in publication order; drop those outside the window
+			start := 0
+			for start < len(postings) &&
+				article.PublicationDate.Sub(articles[postings[start]].PublicationDate) > window {
+				start++
+			}
+			postings = postings[start:]
+			for _, j := range postings {
+				shared[j]++
+			}
+			index[shingle] = append(postings, i)
+		}
+
+		for j, count := range shared {
+			union := len(shingles[i]) + len(shingles[j]) - count
+			if float64(count)/float64(union) < threshold {
+				continue
+			}
+			// Keep the earliest article as the root
+			ri, rj := find(i), find(j)
+			if ri < rj {
+				parent[rj] = ri
+			} else if rj < ri {
+				parent[ri] = rj
+			}
+		}
+	}
+
+	roots := make([]int, len(articles))
+	for i := range articles {
+		roots[i] = find(i)
+	}
+	return roots
+}
+
+// GetStory returns the articles in a story, most relevant first. It returns
+// gorm.ErrRecordNotFound when no article belongs to the story.
+func (s *StoryService) GetStory(storyID string) (*Story, error) {
+	var articles []models.Article
+	err := s.db.Where("story_id = ?", storyID).
+		Order("relevance_score DESC, publication_date DESC, id").
+		Find(&articles).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch story: %w", err)
+	}
+	if len(articles) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &Story{
+		ID:             storyID,
+		Representative: articles[0],
+		Articles:       articles,
+	}, nil
+}
+
+// storyKey identifies an article's story; articles not yet clustered are
+// their own story
+func storyKey(article *models.Article) string {
+	if article.StoryID != "" {
+		return article.StoryID
+	}
+	return article.ID
+}
+
+// collapseStories keeps the first article of each story, preserving order
+func collapseStories(articles []models.Article) []models.Article {
+	seen := make(map[string]bool, len(articles))
+	collapsed := articles[:0]
+	for i := range articles {
+		key := storyKey(&articles[i])
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		collapsed = append(collapsed, articles[i])
+	}
+	return collapsed
+}
@@ -0,0 +1,389 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"news-backend/config"
+	"news-backend/database"
+	"news-backend/models"
+	"news-backend/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// experimentShareDraws is how many simulated draws estimate an arm's share
+// of new assignments
+const experimentShareDraws = 2000
+
+// ParseExperimentArms parses SUMMARY_EXPERIMENT_ARMS: two or more distinct
+// summary tones, comma-separated, the first being the control. Empty
+// disables the experiment.
+func ParseExperimentArms(spec string) ([]string, error) {
+	var arms []string
+	for _, tone := range strings.Split(spec, ",") {
+		tone = strings.ToLower(strings.TrimSpace(tone))
+		if tone == "" {
+			continue
+		}
+		if !models.IsValidSummaryTone(tone) {
+			return nil, fmt.Errorf("unknown summary tone %q (expected simple, neutral, detailed or kid_friendly)", tone)
+		}
+		if slices.Contains(arms, tone) {
+			return nil, fmt.Errorf("summary tone %q given twice", tone)
+		}
+		arms = append(arms, tone)
+	}
+	if len(arms) == 1 {
+		return nil, fmt.Errorf("an experiment needs at least two tones")
+	}
+	return arms, nil
+}
+
+// SummaryExperimentArmReport is one tone's results in the experiment
+type SummaryExperimentArmReport struct {
+	models.SummaryExperimentArm
+	Control        bool    `json:"control"`
+	EngagementRate float64 `json:"engagement_rate"`  // engagements per view
+	BadSummaryRate float64 `json:"bad_summary_rate"` // bad_summary reports per view
+	TrafficShare   float64 `json:"traffic_share"`    // expected share of new assignments
+}
+
+// SummaryExperimentReport is the state of the summary tone experiment
+type SummaryExperimentReport struct {
+	Enabled         bool                         `json:"enabled"`
+	WarmingUp       bool                         `json:"warming_up"` // traffic is split evenly until every running arm has Warmup views
+	Warmup          int                          `json:"warmup"`
+	MinShare        float64                      `json:"min_share"`
+	MaxBadRate      float64                      `json:"max_bad_rate"`
+	AssignmentHours int                          `json:"assignment_hours"`
+	Arms            []SummaryExperimentArmReport `json:"arms"`
+}
+
+// SummaryExperimentService splits users without a stored summary tone
+// between the SUMMARY_EXPERIMENT_ARMS tones, credits their views, clicks
+// and shares to the tone they were served, and shifts new assignments
+// toward the tones that engage best (Thompson sampling), within guardrails
+type SummaryExperimentService struct {
+	db          *gorm.DB
+	cfg         *config.Config
+	arms        []string // SUMMARY_EXPERIMENT_ARMS, validated at startup
+	userService *UserService
+	mu          sync.Mutex // guards rng
+	rng         *rand.Rand
+}
+
+// NewSummaryExperimentService creates a new summary tone experiment service
+// instance
+func NewSummaryExperimentService(cfg *config.Config, userService *UserService) *SummaryExperimentService {
+	arms, _ := ParseExperimentArms(cfg.SummaryExperimentArms)
+	s := &SummaryExperimentService{
+		db:          database.GetDB(),
+		cfg:         cfg,
+		arms:        arms,
+		userService: userService,
+		rng:         rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	if err := s.createArms(); err != nil {
+		log.Printf("Warning: Failed to create summary experiment arms: %v", err)
+	}
+	return s
+}
+
+// Enabled reports whether the experiment runs
+func (s *SummaryExperimentService) Enabled() bool {
+	return len(s.arms) > 0
+}
+
+// createArms stores a zero record for every configured arm missing one
+func (s *SummaryExperimentService) createArms() error {
+	if !s.Enabled() {
+		return nil
+	}
+	rows := make([]models.SummaryExperimentArm, len(s.arms))
+	for i, tone := range s.arms {
+		rows[i] = models.SummaryExperimentArm{Tone: tone}
+	}
+	return s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
+
+// PreferredSummaryTone returns the tone to summarize in for a user: their
+// stored preference, else, while the experiment runs, the tone they are
+// assigned. Users who stored any preference stay out of the experiment.
+func (s *SummaryExperimentService) PreferredSummaryTone(userID string) string {
+	canonicalID := s.userService.ResolveUserID(userID)
+	var prefs models.UserPreference
+	err := s.db.Where("user_id = ?", canonicalID).First(&prefs).Error
+	switch {
+	case err == nil:
+		return prefs.SummaryTone
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		log.Printf("Failed to load summary tone for user %s: %v", userID, err)
+		return models.SummaryToneNeutral
+	case !s.Enabled():
+		return models.SummaryToneNeutral
+	}
+
+	tone, err := s.assign(canonicalID)
+	if err != nil {
+		log.Printf("Failed to assign summary tone for user %s: %v", userID, err)
+		return models.SummaryToneNeutral
+	}
+	return tone
+}
+
+// assign returns a canonical user's tone, drawing a new one when they have
+// none, it has expired, or its arm was stopped or removed
+func (s *SummaryExperimentService) assign(userID string) (string, error) {
+	var assignment models.SummaryExperimentAssignment
+	err := s.db.Where("user_id = ?", userID).First(&assignment).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", fmt.Errorf("failed to load assignment: %w", err)
+	}
+	arms, err := s.loadArms()
+	if err != nil {
+		return "", err
+	}
+
+	expiry := time.Duration(s.cfg.SummaryExperimentAssignmentHours) * time.Hour
+	if assignment.UserID != "" && time.Since(assignment.AssignedAt) < expiry {
+		for _, arm := range arms {
+			if arm.Tone == assignment.Tone && !arm.Stopped {
+				return assignment.Tone, nil
+			}
+		}
+	}
+
+	assignment = models.SummaryExperimentAssignment{
+		UserID:     userID,
+		Tone:       s.draw(arms),
+		AssignedAt: time.Now().UTC(),
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&assignment).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.SummaryExperimentArm{}).Where("tone = ?", assignment.Tone).
+			Update("assignments", gorm.Expr("assignments + 1")).Error
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to store assignment: %w", err)
+	}
+	return assignment.Tone, nil
+}
+
+// loadArms returns the configured arms' records in configuration order
+func (s *SummaryExperimentService) loadArms() ([]models.SummaryExperimentArm, error) {
+	var rows []models.SummaryExperimentArm
+	if err := s.db.Where("tone IN ?", s.arms).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load experiment arms: %w", err)
+	}
+	arms := make([]models.SummaryExperimentArm, len(s.arms))
+	for i, tone := range s.arms {
+		arms[i] = models.SummaryExperimentArm{Tone: tone}
+		for _, row := range rows {
+			if row.Tone == tone {
+				arms[i] = row
+			}
+		}
+	}
+	return arms, nil
+}
+
+// runningArms returns the arms not stopped by the guardrail
+func runningArms(arms []models.SummaryExperimentArm) []models.SummaryExperimentArm {
+	var result []models.SummaryExperimentArm
+	for _, arm := range arms {
+		if !arm.Stopped {
+			result = append(result, arm)
+		}
+	}
+	return result
+}
+
+// warmingUp reports whether a running arm still lacks warmup views
+func (s *SummaryExperimentService) warmingUp(running []models.SummaryExperimentArm) bool {
+	for _, arm := range running {
+		if arm.Views < int64(s.cfg.SummaryExperimentWarmup) {
+			return true
+		}
+	}
+	return false
+}
+
+// banditArms turns arm records into engagements out of views
+func banditArms(arms []models.SummaryExperimentArm) []utils.BanditArm {
+	result := make([]utils.BanditArm, len(arms))
+	for i, arm := range arms {
+		result[i] = utils.BanditArm{Successes: arm.Engagements, Trials: arm.Views}
+	}
+	return result
+}
+
+// draw picks the tone of a new assignment. Until every running arm has
+// SUMMARY_EXPERIMENT_WARMUP views, tones are drawn evenly; then each running
+// arm keeps SUMMARY_EXPERIMENT_MIN_SHARE of draws and the rest go to
+// Thompson sampling on engagements per view. Stopped arms get no draws;
+// with every arm stopped, the control is served.
+func (s *SummaryExperimentService) draw(arms []models.SummaryExperimentArm) string {
+	candidates := runningArms(arms)
+	if len(candidates) == 0 {
+		return s.arms[0]
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.warmingUp(candidates) || s.rng.Float64() < s.cfg.SummaryExperimentMinShare*float64(len(candidates)) {
+		return candidates[s.rng.Intn(len(candidates))].Tone
+	}
+	return candidates[utils.ThompsonPick(s.rng, banditArms(candidates))].Tone
+}
+
+// trafficShares estimates the share of new assignments draw gives each
+// running arm
+func (s *SummaryExperimentService) trafficShares(arms []models.SummaryExperimentArm) map[string]float64 {
+	candidates := runningArms(arms)
+	shares := make(map[string]float64, len(arms))
+	if len(candidates) == 0 {
+		shares[s.arms[0]] = 1
+		return shares
+	}
+	if s.warmingUp(candidates) {
+		for _, arm := range candidates {
+			shares[arm.Tone] = 1 / float64(len(candidates))
+		}
+		return shares
+	}
+
+	s.mu.Lock()
+	thompson := utils.ThompsonShares(s.rng, banditArms(candidates), experimentShareDraws)
+	s.mu.Unlock()
+	floor := s.cfg.SummaryExperimentMinShare
+	rest := 1 - floor*float64(len(candidates))
+	for i, arm := range candidates {
+		shares[arm.Tone] = floor + rest*thompson[i]
+	}
+	return shares
+}
+
+// RecordEvent credits a canonical user's view, click or share to the tone
+// they are assigned
+func (s *SummaryExperimentService) RecordEvent(userID, eventType string) {
+	if !s.Enabled() {
+		return
+	}
+	column := "engagements"
+	if eventType == models.EventTypeView {
+		column = "views"
+	}
+	s.credit(userID, column)
+}
+
+// RecordBadSummary counts a bad_summary report against the tone the user
+// is assigned and stops the tone once its reports per view exceed
+// SUMMARY_EXPERIMENT_MAX_BAD_RATE after warmup
+func (s *SummaryExperimentService) RecordBadSummary(userID string) {
+	if !s.Enabled() || userID == "" {
+		return
+	}
+	tone, ok := s.credit(s.userService.ResolveUserID(userID), "bad_summaries")
+	if !ok || s.cfg.SummaryExperimentMaxBadRate <= 0 {
+		return
+	}
+
+	var arm models.SummaryExperimentArm
+	if err := s.db.Where("tone = ?", tone).First(&arm).Error; err != nil {
+		log.Printf("Failed to load summary experiment arm %s: %v", tone, err)
+		return
+	}
+	if arm.Stopped || arm.Views < int64(s.cfg.SummaryExperimentWarmup) ||
+		float64(arm.BadSummaries)/float64(arm.Views) <= s.cfg.SummaryExperimentMaxBadRate {
+		return
+	}
+	if err := s.db.Model(&arm).Update("stopped", true).Error; err != nil {
+		log.Printf("Failed to stop summary experiment arm %s: %v", tone, err)
+		return
+	}
+	log.Printf("Stopped summary experiment arm %s: %d bad_summary reports in %d views", tone, arm.BadSummaries, arm.Views)
+}
+
+// credit increments a counter of the tone a canonical user is assigned and
+// returns the tone, if they have one in the experiment
+func (s *SummaryExperimentService) credit(userID, column string) (string, bool) {
+	var assignment models.SummaryExperimentAssignment
+	err := s.db.Where("user_id = ?", userID).First(&assignment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", false
+	}
+	if err != nil {
+		log.Printf("Failed to load summary experiment assignment for user %s: %v", userID, err)
+		return "", false
+	}
+	if !slices.Contains(s.arms, assignment.Tone) {
+		return "", false
+	}
+
+	err = s.db.Model(&models.SummaryExperimentArm{}).Where("tone = ?", assignment.Tone).
+		Update(column, gorm.Expr(column+" + 1")).Error
+	if err != nil {
+		log.Printf("Failed to credit summary experiment arm %s: %v", assignment.Tone, err)
+		return "", false
+	}
+	return assignment.Tone, true
+}
+
+// Report returns every configured arm's results and expected traffic share
+func (s *SummaryExperimentService) Report() (*SummaryExperimentReport, error) {
+	report := &SummaryExperimentReport{
+		Enabled:         s.Enabled(),
+		Warmup:          s.cfg.SummaryExperimentWarmup,
+		MinShare:        s.cfg.SummaryExperimentMinShare,
+		MaxBadRate:      s.cfg.SummaryExperimentMaxBadRate,
+		AssignmentHours: s.cfg.SummaryExperimentAssignmentHours,
+		Arms:            []SummaryExperimentArmReport{},
+	}
+	if !s.Enabled() {
+		return report, nil
+	}
+
+	arms, err := s.loadArms()
+	if err != nil {
+		return nil, err
+	}
+	report.WarmingUp = s.warmingUp(runningArms(arms))
+	shares := s.trafficShares(arms)
+	for i, arm := range arms {
+		armReport := SummaryExperimentArmReport{
+			SummaryExperimentArm: arm,
+			Control:              i == 0,
+			TrafficShare:         shares[arm.Tone],
+		}
+		if arm.Views > 0 {
+			armReport.EngagementRate = float64(arm.Engagements) / float64(arm.Views)
+			armReport.BadSummaryRate = float64(arm.BadSummaries) / float64(arm.Views)
+		}
+		report.Arms = append(report.Arms, armReport)
+	}
+	return report, nil
+}
+
+// Reset drops every assignment and result, starting the experiment over
+func (s *SummaryExperimentService) Reset() error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.SummaryExperimentAssignment{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("1 = 1").Delete(&models.SummaryExperimentArm{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reset summary experiment: %w", err)
+	}
+	return s.createArms()
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"news-backend/models"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// Summary streaming errors
+var (
+	// ErrSummaryBlocked is returned for articles whose summary was
+	// blocklisted after bad_summary feedback
+	ErrSummaryBlocked = errors.New("summary withdrawn after reports")
+	// errSummaryStreamInterrupted is returned when a provider fails after
+	// part of the summary was delivered, so no other provider can take over
+	errSummaryStreamInterrupted = errors.New("summary stream interrupted")
+	// errClientGone wraps onDelta failures, which say nothing about the provider
+	errClientGone = errors.New("summary stream client gone")
+)
+
+// StreamSummary writes an article's summary in ctx's tone to onDelta as the
+// LLM generates it and returns the complete summary, cached like
+// TryGenerateSummary's. Stored and cached summaries, and with
+// LLM_PROVIDER=none the start of the description, are delivered in one
+// piece without an LLM call; the demo tier gets nothing else, and
+// blocklisted summaries get ErrSummaryBlocked. Providers are
+// tried in order until one starts streaming. onDelta errors (the client
+// went away) stop the stream.
+func (s *LLMService) StreamSummary(ctx context.Context, article *models.Article, onDelta func(delta string) error) (string, error) {
+	if article.SummaryBlocked {
+		return "", ErrSummaryBlocked
+	}
+	tone := SummaryTone(ctx)
+	if article.LLMSummary != "" && tone == models.SummaryToneNeutral {
+		return article.LLMSummary, onDelta(article.LLMSummary)
+	}
+	if cached, ok := s.cachedSummary(ctx, article.ID, tone); ok {
+		return cached, onDelta(cached)
+	}
+	if s.cfg.LLMOffline {
+		summary := offlineSummary(article.Description)
+		return summary, onDelta(summary)
+	}
+	text := s.SummaryText(article)
+	if len(text) < 20 {
+		return summaryInsufficientContent, onDelta(summaryInsufficientContent)
+	}
+	if IsDemoTier(ctx) {
+		return "", ErrDemoTier
+	}
+	if len(text) > summaryMaxInput {
+		text = text[:summaryMaxInput]
+	}
+	if err := s.usage.acquire(ctx); err != nil {
+		return "", err
+	}
+
+	for _, p := range s.providers {
+		if !p.breaker.Allow() {
+			continue
+		}
+
+		req := summaryRequest(p, tone, text)
+		summary, tokens, started, err := s.streamCompletion(ctx, p, req, onDelta)
+		s.auditStream(p, req, summary, tokens, started, err)
+		if err != nil {
+			// The caller went away; that says nothing about the provider
+			if ctx.Err() != nil {
+				p.breaker.Release()
+				return "", ctx.Err()
+			}
+			if errors.Is(err, errClientGone) {
+				p.breaker.Release()
+				return "", err
+			}
+			p.breaker.RecordFailure()
+			if summary != "" {
+				return "", errSummaryStreamInterrupted
+			}
+			log.Printf("LLM provider %s failed, trying next: %v", p.name, err)
+			continue
+		}
+
+		p.breaker.RecordSuccess()
+		s.usage.record(p.name, llmPurposeSummary, tokens)
+		summary = strings.TrimSpace(summary)
+		s.cacheSummary(article.ID, tone, summary)
+		return summary, nil
+	}
+	return "", errAllProvidersFailed
+}
+
+// streamCompletion streams one provider's completion to onDelta, returning
+// the text delivered, the tokens used when the provider reports them and
+// when the call started
+func (s *LLMService) streamCompletion(ctx context.Context, p *llmProvider, req openai.ChatCompletionRequest, onDelta func(string) error) (string, int, time.Time, error) {
+	started := time.Now()
+	callCtx, cancel := s.callContext(ctx)
+	defer cancel()
+
+	stream, err := p.client.CreateChatCompletionStream(callCtx, req)
+	if err != nil {
+		return "", 0, started, err
+	}
+	defer stream.Close()
+
+	var text strings.Builder
+	tokens := 0
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			if text.Len() == 0 {
+				return "", tokens, started, errors.New("empty completion")
+			}
+			return text.String(), tokens, started, nil
+		}
+		if err != nil {
+			return text.String(), tokens, started, err
+		}
+		if resp.Usage != nil {
+			tokens = resp.Usage.TotalTokens
+		}
+		if len(resp.Choices) == 0 || resp.Choices[0].Delta.Content == "" {
+			continue
+		}
+		delta := resp.Choices[0].Delta.Content
+		text.WriteString(delta)
+		if err := onDelta(delta); err != nil {
+			return text.String(), tokens, started, errors.Join(errClientGone, err)
+		}
+	}
+}
+
+// auditStream records a sample of streamed completions in the audit log
+// like createChatCompletion does for the others
+func (s *LLMService) auditStream(p *llmProvider, req openai.ChatCompletionRequest, response string, tokens int, started time.Time, err error) {
+	if !s.audit.sample() {
+		return
+	}
+	entry := models.LLMAudit{
+		Purpose:     llmPurposeSummary,
+		Provider:    p.name,
+		Model:       req.Model,
+		Prompt:      chatTranscript(req.Messages),
+		Response:    response,
+		TotalTokens: tokens,
+		LatencyMs:   time.Since(started).Milliseconds(),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	s.audit.record(&entry)
+}
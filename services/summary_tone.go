@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+
+	"news-backend/models"
+)
+
+type summaryToneKey struct{}
+
+// summaryToneMaxTokens overrides the summary token limit for longer tones
+var summaryToneMaxTokens = map[string]int{
+	models.SummaryToneDetailed: 250,
+}
+
+// defaultSummaryMaxTokens bounds a neutral, simple or kid-friendly summary
+const defaultSummaryMaxTokens = 100
+
+// WithSummaryTone makes summaries generated for ctx use tone
+func WithSummaryTone(ctx context.Context, tone string) context.Context {
+	return context.WithValue(ctx, summaryToneKey{}, tone)
+}
+
+// SummaryTone returns the summary tone requested for ctx, neutral by default
+func SummaryTone(ctx context.Context) string {
+	if tone, ok := ctx.Value(summaryToneKey{}).(string); ok && tone != "" {
+		return tone
+	}
+	return models.SummaryToneNeutral
+}
+
+// summaryCacheKey keys an article's summary in a tone. Neutral summaries keep
+// the bare article key they are persisted and pre-generated under.
+func summaryCacheKey(articleID, tone string) string {
+	if tone == models.SummaryToneNeutral {
+		return summaryCachePrefix + articleID
+	}
+	return summaryCachePrefix + articleID + ":" + tone
+}
+
+// summaryTones lists every tone, for invalidating all of an article's summaries
+var summaryTones = []string{
+	models.SummaryToneNeutral,
+	models.SummaryToneSimple,
+	models.SummaryToneDetailed,
+	models.SummaryToneKidFriendly,
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"news-backend/config"
+	"news-backend/database"
+	"news-backend/metrics"
+	"news-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// SummaryWorker pre-generates and persists LLM summaries for articles that
+// lack one, newest first, so request paths can serve stored summaries
+type SummaryWorker struct {
+	db         *gorm.DB
+	cfg        *config.Config
+	llmService *LLMService
+	queue      *metrics.Queue // articles awaiting a summary, bounded by SUMMARY_QUEUE_MAX
+}
+
+// NewSummaryWorker creates a new summary pre-generation worker
+func NewSummaryWorker(cfg *config.Config, llmService *LLMService, queue *metrics.Queue) *SummaryWorker {
+	return &SummaryWorker{
+		db:         database.GetDB(),
+		cfg:        cfg,
+		llmService: llmService,
+		queue:      queue,
+	}
+}
+
+// Start processes one batch per configured interval until ctx is cancelled
+// A non-positive interval disables the worker, as does LLM_PROVIDER=none:
+// description excerpts aren't worth persisting as summaries.
+func (w *SummaryWorker) Start(ctx context.Context) {
+	if w.cfg.SummaryWorkerInterval <= 0 || w.cfg.LLMOffline {
+		log.Println("Summary pre-generation worker disabled")
+		return
+	}
+
+	interval := time.Duration(w.cfg.SummaryWorkerInterval) * time.Second
+	log.Printf("Summary pre-generation worker started (interval: %v, batch: %d)",
+		interval, w.cfg.SummaryWorkerBatch)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	w.queue.SetDepth(w.Pending())
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Summary pre-generation worker stopped")
+			return
+		case <-ticker.C:
+			generated, err := w.ProcessBatch(ctx)
+			if err != nil {
+				log.Printf("Summary pre-generation failed: %v", err)
+			} else if generated > 0 {
+				log.Printf("Pre-generated %d article summaries", generated)
+			}
+			if generated > 0 {
+				w.queue.Progress()
+			}
+			w.queue.SetDepth(w.Pending())
+		}
+	}
+}
+
+// ProcessBatch summarizes up to SummaryWorkerBatch articles without a summary,
+// leaving out those whose summary was blocklisted or failed
+// SummaryMaxAttempts times. A failed or empty summary is logged and counted
+// against its article; the batch stops early only when the LLM is out of
+// budget or rate limited, which no article is to blame for.
+func (w *SummaryWorker) ProcessBatch(ctx context.Context) (int, error) {
+	var articles []models.Article
+	err := w.pendingArticles().Select("id", "description").
+		Order("publication_date DESC").
+		Limit(w.cfg.SummaryWorkerBatch).
+		Find(&articles).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to load articles without summaries: %w", err)
+	}
+
+	generated := 0
+	for _, article := range articles {
+		if ctx.Err() != nil {
+			break
+		}
+
+		summary, err := w.llmService.TryGenerateSummary(ctx, article.ID, w.llmService.SummaryText(&article))
+		if errors.Is(err, errLLMBudgetExhausted) || errors.Is(err, errLLMRateLimited) || ctx.Err() != nil {
+			return generated, err
+		}
+		if err == nil && summary == "" {
+			err = errors.New("empty summary")
+		}
+		if err != nil {
+			log.Printf("Summary for article %s failed: %v", article.ID, err)
+			if err := w.db.Model(&models.Article{}).
+				Where("id = ?", article.ID).
+				Update("summary_attempts", gorm.Expr("summary_attempts + 1")).Error; err != nil {
+				return generated, fmt.Errorf("failed to record summary attempt for %s: %w", article.ID, err)
+			}
+			continue
+		}
+
+		if err := w.db.Model(&models.Article{}).
+			Where("id = ?", article.ID).
+			Update("llm_summary", summary).Error; err != nil {
+			return generated, fmt.Errorf("failed to store summary for %s: %w", article.ID, err)
+		}
+		generated++
+	}
+
+	return generated, nil
+}
+
+// Pending returns the number of articles still waiting for a summary
+func (w *SummaryWorker) Pending() int64 {
+	var count int64
+	w.pendingArticles().Count(&count)
+	return count
+}
+
+// pendingArticles selects the articles the worker still has to summarize
+func (w *SummaryWorker) pendingArticles() *gorm.DB {
+	return w.db.Model(&models.Article{}).
+		Where("llm_summary = '' OR llm_summary IS NULL").
+		Where("summary_blocked = ?", false).
+		Where("summary_attempts < ?", w.cfg.SummaryMaxAttempts)
+}
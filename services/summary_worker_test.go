@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"news-backend/cache"
+	"news-backend/config"
+	"news-backend/models"
+)
+
+// newTestLLMService creates an LLM service whose only provider answers
+// every chat completion with content
+func newTestLLMService(t *testing.T, cfg *config.Config, content string) (*LLMService, *atomic.Int32) {
+	t.Helper()
+	return newTestLLMServiceFunc(t, cfg, func(string) (string, bool) { return content, true })
+}
+
+// newTestLLMServiceFunc creates an LLM service whose only provider answers a
+// chat completion with the content answer returns for the request body, or
+// fails it with 500
+func newTestLLMServiceFunc(t *testing.T, cfg *config.Config, answer func(request string) (string, bool)) (*LLMService, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		request, _ := io.ReadAll(r.Body)
+		content, ok := answer(string(request))
+		if !ok {
+			http.Error(w, `{"error": {"message": "upstream failure"}}`, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "test", "object": "chat.completion", "choices": [{"index": 0,
+			"message": {"role": "assistant", "content": "` + content + `"}, "finish_reason": "stop"}],
+			"usage": {"total_tokens": 10}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	cfg.LLMProviders = []config.LLMProviderConfig{{Name: "test", APIKey: "key", BaseURL: server.URL, SummaryModel: "test"}}
+	cfg.LLMBreakerThreshold = 3
+	cfg.LLMBreakerCooldown = 60
+	invalidation, err := NewInvalidationService(cfg)
+	if err != nil {
+		t.Fatalf("NewInvalidationService() error = %v", err)
+	}
+	return NewLLMService(cfg, invalidation, cache.NewMemory()), &calls
+}
+
+func TestSummaryWorkerSkipsBlocklistedSummaries(t *testing.T) {
+	db := openTestDB(t)
+	cfg := &config.Config{SummaryWorkerBatch: 10, SummaryMaxAttempts: 3}
+	llmService, calls := newTestLLMService(t, cfg, "A fresh summary.")
+	feedback := NewFeedbackService(cfg, llmService, NewCDNService(cfg), nil)
+	worker := NewSummaryWorker(cfg, llmService, nil)
+
+	now := time.Now()
+	articles := []models.Article{
+		{ID: "reported", Title: "Reported", URL: "https://example.com/reported", PublicationDate: now,
+			Description: "A description long enough to be summarized.", LLMSummary: "A misleading summary."},
+		{ID: "fresh", Title: "Fresh", URL: "https://example.com/fresh", PublicationDate: now.Add(-time.Hour),
+			Description: "Another description long enough to be summarized."},
+	}
+	if err := db.Create(&articles).Error; err != nil {
+		t.Fatalf("failed to create articles: %v", err)
+	}
+	if err := feedback.blocklistSummary("reported"); err != nil {
+		t.Fatalf("blocklistSummary() error = %v", err)
+	}
+
+	if pending := worker.Pending(); pending != 1 {
+		t.Errorf("Pending() = %d, expected only the unreported article", pending)
+	}
+	// Runs twice, as a summary regenerated once would be written by the second
+	for run := 1; run <= 2; run++ {
+		if _, err := worker.ProcessBatch(context.Background()); err != nil {
+			t.Fatalf("ProcessBatch() run %d error = %v", run, err)
+		}
+	}
+
+	summaries := map[string]string{}
+	var stored []models.Article
+	db.Order("id").Find(&stored)
+	for _, article := range stored {
+		summaries[article.ID] = article.LLMSummary
+	}
+	if summaries["reported"] != "" {
+		t.Errorf("blocklisted summary = %q after worker runs, expected none", summaries["reported"])
+	}
+	if summaries["fresh"] != "A fresh summary." {
+		t.Errorf("unreported summary = %q, expected it generated", summaries["fresh"])
+	}
+
+	calls.Store(0)
+	llmService.GenerateSummariesBatch(context.Background(), stored)
+	for _, article := range stored {
+		if article.ID == "reported" && article.LLMSummary != "" {
+			t.Errorf("GenerateSummariesBatch() summary = %q, expected none for the blocklisted article", article.LLMSummary)
+		}
+	}
+	if _, err := llmService.StreamSummary(context.Background(), &stored[1], func(string) error { return nil }); !errors.Is(err, ErrSummaryBlocked) {
+		t.Errorf("StreamSummary() error = %v, expected ErrSummaryBlocked", err)
+	}
+	if n := calls.Load(); n != 0 {
+		t.Errorf("blocklisted article made %d LLM calls, expected none", n)
+	}
+}
+
+func TestSummaryWorkerSkipsFailingArticles(t *testing.T) {
+	db := openTestDB(t)
+	cfg := &config.Config{SummaryWorkerBatch: 10, SummaryMaxAttempts: 2}
+	llmService, calls := newTestLLMServiceFunc(t, cfg, func(request string) (string, bool) {
+		switch {
+		case strings.Contains(request, "provider rejects"):
+			return "", false
+		case strings.Contains(request, "nothing to say"):
+			return "", true
+		}
+		return "A fresh summary.", true
+	})
+	worker := NewSummaryWorker(cfg, llmService, nil)
+
+	now := time.Now()
+	articles := []models.Article{
+		{ID: "failing", Title: "Failing", URL: "https://example.com/failing", PublicationDate: now,
+			Description: "A description the provider rejects every time."},
+		{ID: "empty", Title: "Empty", URL: "https://example.com/empty", PublicationDate: now.Add(-time.Minute),
+			Description: "A description the model has nothing to say about."},
+		{ID: "fine", Title: "Fine", URL: "https://example.com/fine", PublicationDate: now.Add(-time.Hour),
+			Description: "Another description long enough to be summarized."},
+	}
+	if err := db.Create(&articles).Error; err != nil {
+		t.Fatalf("failed to create articles: %v", err)
+	}
+
+	// The newer failures don't stop the batch before the last article
+	generated, err := worker.ProcessBatch(context.Background())
+	if err != nil || generated != 1 {
+		t.Fatalf("ProcessBatch() = %d, %v, expected 1 summary and no error", generated, err)
+	}
+	if _, err := worker.ProcessBatch(context.Background()); err != nil {
+		t.Fatalf("second ProcessBatch() error = %v", err)
+	}
+
+	attempts := map[string]int{}
+	summaries := map[string]string{}
+	var stored []models.Article
+	db.Find(&stored)
+	for _, article := range stored {
+		attempts[article.ID], summaries[article.ID] = article.SummaryAttempts, article.LLMSummary
+	}
+	if attempts["failing"] != 2 || attempts["empty"] != 2 || attempts["fine"] != 0 {
+		t.Errorf("attempts = %v, expected 2 for each failing article and none for the summarized one", attempts)
+	}
+	if summaries["fine"] != "A fresh summary." || summaries["empty"] != "" {
+		t.Errorf("summaries = %v, expected only the fine article summarized", summaries)
+	}
+
+	// After SummaryMaxAttempts the failing articles are skipped
+	if pending := worker.Pending(); pending != 0 {
+		t.Errorf("Pending() = %d, expected the failing articles given up on", pending)
+	}
+	calls.Store(0)
+	if generated, err := worker.ProcessBatch(context.Background()); err != nil || generated != 0 || calls.Load() != 0 {
+		t.Errorf("third ProcessBatch() = %d, %v with %d LLM calls, expected nothing tried", generated, err, calls.Load())
+	}
+}
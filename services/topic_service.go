@@ -0,0 +1,326 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"news-backend/config"
+	"news-backend/database"
+	"news-backend/models"
+	"news-backend/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidTopic is returned for topic names that normalize to nothing
+var ErrInvalidTopic = errors.New("invalid topic")
+
+// maxTopicsPerArticle caps how many extracted topics an article is tagged with
+const maxTopicsPerArticle = 5
+
+// TopicService extracts topics from articles with the LLM and serves
+// trending topics and topic follows
+type TopicService struct {
+	db          *gorm.DB
+	cfg         *config.Config
+	llmService  *LLMService
+	userService *UserService
+
+	extractMu sync.Mutex // one extraction pass at a time, worker or ingest
+}
+
+// NewTopicService creates a new topic service instance
+func NewTopicService(cfg *config.Config, llmService *LLMService, userService *UserService) *TopicService {
+	return &TopicService{
+		db:          database.GetDB(),
+		cfg:         cfg,
+		llmService:  llmService,
+		userService: userService,
+	}
+}
+
+// Start extracts topics of articles stored without them (dataset loads,
+// admin changes, failed extractions) once per configured interval until ctx
+// is cancelled. Ingest runs extract their own articles right away. A
+// non-positive interval or LLM_PROVIDER=none disables topic extraction.
+func (s *TopicService) Start(ctx context.Context) {
+	if s.cfg.TopicWorkerInterval <= 0 || s.cfg.LLMOffline {
+		log.Println("Topic extraction worker disabled")
+		return
+	}
+
+	interval := time.Duration(s.cfg.TopicWorkerInterval) * time.Second
+	log.Printf("Topic extraction worker started (interval: %v, batch: %d)", interval, s.cfg.TopicWorkerBatch)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Topic extraction worker stopped")
+			return
+		case <-ticker.C:
+			if extracted, err := s.ExtractPending(ctx); err != nil {
+				log.Printf("Topic extraction failed: %v", err)
+			} else if extracted > 0 {
+				log.Printf("Extracted topics of %d articles", extracted)
+			}
+		}
+	}
+}
+
+// ExtractPending tags up to TopicWorkerBatch articles whose topics are
+// missing or were extracted from older content, newest first
+func (s *TopicService) ExtractPending(ctx context.Context) (int, error) {
+	var articles []models.Article
+	err := s.db.Select("id", "title", "description", "content_hash").
+		Where("topics_hash = '' OR topics_hash IS NULL OR topics_hash <> content_hash").
+		Order("publication_date DESC").
+		Limit(s.cfg.TopicWorkerBatch).
+		Find(&articles).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to load articles without topics: %w", err)
+	}
+	return s.Extract(ctx, articles)
+}
+
+// Extract tags articles with the topics the LLM finds in their title and
+// description. It stops early on the first LLM failure; the worker retries
+// the rest.
+func (s *TopicService) Extract(ctx context.Context, articles []models.Article) (int, error) {
+	if s.cfg.TopicWorkerInterval <= 0 || s.cfg.LLMOffline {
+		return 0, nil
+	}
+	s.extractMu.Lock()
+	defer s.extractMu.Unlock()
+
+	extracted := 0
+	for i := range articles {
+		if ctx.Err() != nil {
+			break
+		}
+		names, err := s.llmService.ExtractTopics(ctx, articles[i].Title+". "+articles[i].Description)
+		if err != nil {
+			return extracted, err
+		}
+		if err := s.tagArticle(&articles[i], names); err != nil {
+			return extracted, err
+		}
+		extracted++
+	}
+	return extracted, nil
+}
+
+// tagArticle replaces an article's topics with the normalized names and
+// marks its current content as extracted
+func (s *TopicService) tagArticle(article *models.Article, names []string) error {
+	// Rows stored before hashing was introduced
+	if article.ContentHash == "" {
+		article.ContentHash = article.ComputeContentHash()
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		seen := make(map[string]bool)
+		var topics []models.Topic
+		for _, name := range names {
+			name = utils.NormalizeTopic(name)
+			if name == "" || seen[name] || len(topics) == maxTopicsPerArticle {
+				continue
+			}
+			seen[name] = true
+			topic, err := findOrCreateTopic(tx, name)
+			if err != nil {
+				return err
+			}
+			topics = append(topics, topic)
+		}
+
+		if err := tx.Model(&models.Article{ID: article.ID}).Association("Topics").Replace(topics); err != nil {
+			return err
+		}
+		return tx.Model(&models.Article{}).Where("id = ?", article.ID).Updates(map[string]interface{}{
+			"content_hash": article.ContentHash,
+			"topics_hash":  article.ContentHash,
+		}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store topics for %s: %w", article.ID, err)
+	}
+	return nil
+}
+
+// findOrCreateTopic returns the topic with a normalized name, creating it
+// when it doesn't exist yet
+func findOrCreateTopic(db *gorm.DB, name string) (models.Topic, error) {
+	topic := models.Topic{Name: name}
+	err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&topic).Error
+	if err != nil {
+		return topic, err
+	}
+	err = db.Where("name = ?", name).First(&topic).Error
+	return topic, err
+}
+
+// Trending ranks topics by the articles tagged with them that were
+// published in the last hours plus the engagement on tagged articles in
+// that time, weighted by event type and recency like trending articles
+func (s *TopicService) Trending(hours, limit int) ([]models.TrendingTopic, error) {
+	since := time.Now().Add(-time.Duration(hours) * time.Hour)
+	byID := make(map[uint]*models.TrendingTopic)
+	entry := func(id uint) *models.TrendingTopic {
+		if byID[id] == nil {
+			byID[id] = &models.TrendingTopic{}
+		}
+		return byID[id]
+	}
+
+	var published []struct {
+		TopicID uint
+		Count   int64
+	}
+	err := s.db.Table("article_topics").
+		Select("article_topics.topic_id AS topic_id, COUNT(*) AS count").
+		Joins("JOIN articles ON articles.id = article_topics.article_id").
+		Where("articles.publication_date >= ?", since).
+		Group("article_topics.topic_id").
+		Scan(&published).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count recent articles per topic: %w", err)
+	}
+	for _, row := range published {
+		entry(row.TopicID).Articles = row.Count
+	}
+
+	engagement, err := engagementSince(s.db, since)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(engagement))
+	for id := range engagement {
+		ids = append(ids, id)
+	}
+	for start := 0; start < len(ids); start += 500 {
+		var links []struct {
+			ArticleID string
+			TopicID   uint
+		}
+		err := s.db.Table("article_topics").
+			Select("article_id, topic_id").
+			Where("article_id IN ?", ids[start:min(start+500, len(ids))]).
+			Scan(&links).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to load topics of engaged articles: %w", err)
+		}
+		for _, link := range links {
+			entry(link.TopicID).Engagement += engagement[link.ArticleID]
+		}
+	}
+
+	if len(byID) == 0 {
+		return []models.TrendingTopic{}, nil
+	}
+	topicIDs := make([]uint, 0, len(byID))
+	for id := range byID {
+		topicIDs = append(topicIDs, id)
+	}
+	var topics []models.Topic
+	if err := s.db.Where("id IN ?", topicIDs).Find(&topics).Error; err != nil {
+		return nil, fmt.Errorf("failed to load topics: %w", err)
+	}
+
+	trending := make([]models.TrendingTopic, 0, len(topics))
+	for _, topic := range topics {
+		t := byID[topic.ID]
+		t.Name = topic.Name
+		t.Score = float64(t.Articles) + t.Engagement
+		trending = append(trending, *t)
+	}
+	sort.Slice(trending, func(i, j int) bool {
+		if trending[i].Score != trending[j].Score {
+			return trending[i].Score > trending[j].Score
+		}
+		return trending[i].Name < trending[j].Name
+	})
+	if len(trending) > limit {
+		trending = trending[:limit]
+	}
+	return trending, nil
+}
+
+// Follow makes the canonical user follow a topic. Topics no article has
+// been tagged with yet can be followed too.
+func (s *TopicService) Follow(userID, name string) (*models.TopicFollow, error) {
+	name = utils.NormalizeTopic(name)
+	if name == "" {
+		return nil, ErrInvalidTopic
+	}
+
+	topic, err := findOrCreateTopic(s.db, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create topic: %w", err)
+	}
+	follow := models.TopicFollow{UserID: s.userService.ResolveUserID(userID), TopicID: topic.ID}
+	err = s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&follow).Error
+	if err == nil {
+		err = s.db.Where("user_id = ? AND topic_id = ?", follow.UserID, follow.TopicID).First(&follow).Error
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to follow topic: %w", err)
+	}
+	follow.Topic = topic.Name
+	return &follow, nil
+}
+
+// Unfollow stops the canonical user following a topic. It returns
+// gorm.ErrRecordNotFound when the user doesn't follow it.
+func (s *TopicService) Unfollow(userID, name string) error {
+	name = utils.NormalizeTopic(name)
+	var topic models.Topic
+	if err := s.db.Where("name = ?", name).First(&topic).Error; err != nil {
+		return err
+	}
+	result := s.db.Where("user_id = ? AND topic_id = ?", s.userService.ResolveUserID(userID), topic.ID).
+		Delete(&models.TopicFollow{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to unfollow topic: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Followed lists the topics the canonical user follows, oldest first
+func (s *TopicService) Followed(userID string) ([]models.TopicFollow, error) {
+	var follows []models.TopicFollow
+	err := s.db.Where("user_id = ?", s.userService.ResolveUserID(userID)).
+		Order("created_at").
+		Find(&follows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load followed topics: %w", err)
+	}
+
+	topicIDs := make([]uint, len(follows))
+	for i := range follows {
+		topicIDs[i] = follows[i].TopicID
+	}
+	var topics []models.Topic
+	if err := s.db.Where("id IN ?", topicIDs).Find(&topics).Error; err != nil {
+		return nil, fmt.Errorf("failed to load followed topics: %w", err)
+	}
+	names := make(map[uint]string, len(topics))
+	for _, topic := range topics {
+		names[topic.ID] = topic.Name
+	}
+	for i := range follows {
+		follows[i].Topic = names[follows[i].TopicID]
+	}
+	return follows, nil
+}
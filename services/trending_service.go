@@ -1,34 +1,103 @@
 package services
 
 import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
 	"fmt"
 	"log"
+	"math"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"news-backend/cache"
 	"news-backend/config"
 	"news-backend/database"
+	"news-backend/eventschema"
+	"news-backend/metrics"
 	"news-backend/models"
 	"news-backend/utils"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TrendingService struct {
-	db         *gorm.DB
-	cfg        *config.Config
-	llmService *LLMService
-	cache      sync.Map // Location-based cache
-	cacheTimes sync.Map // Track cache timestamps
+	db                *gorm.DB
+	cfg               *config.Config
+	llmService        *LLMService
+	userService       *UserService
+	cdnService        *CDNService
+	invalidation      *InvalidationService
+	geocoder          *GeocodingService
+	cache             cache.Cache // Location-based results, possibly shared between instances
+	summaryExperiment *SummaryExperimentService
+	embeddingService  *EmbeddingService  // folds engaged articles into user interest vectors
+	eventQueue        *metrics.Queue     // event writes in flight, bounded by EVENT_QUEUE_MAX
+	privacy           utils.CountPrivacy // applied to the engagement counts it publishes
+
+	// radiusBuckets are the radius buckets this instance cached results
+	// for, until the last one expires, so invalidating around a point
+	// needn't list the cache
+	radiusMu      sync.Mutex
+	radiusBuckets map[int]time.Time
 }
 
+// ErrEventQueueFull is returned when EVENT_QUEUE_MAX event writes are
+// already in flight
+var ErrEventQueueFull = errors.New("too many events are being written, retry shortly")
+
+// Cache grid configuration
+const (
+	cacheGridPrecision = 0.05 // Grid size ~5km
+	cacheRadiusBucket  = 10.0 // Group by 10km radius increments
+
+	trendingCachePrefix = "trending:" // results, by grid cell and radius bucket
+)
+
 // NewTrendingService creates a new trending service instance
-func NewTrendingService(cfg *config.Config, llmService *LLMService) *TrendingService {
-	return &TrendingService{
-		db:         database.GetDB(),
-		cfg:        cfg,
-		llmService: llmService,
+func NewTrendingService(cfg *config.Config, llmService *LLMService, userService *UserService,
+	cdnService *CDNService, invalidation *InvalidationService, geocoder *GeocodingService, store cache.Cache,
+	summaryExperiment *SummaryExperimentService, embeddingService *EmbeddingService, eventQueue *metrics.Queue) *TrendingService {
+	s := &TrendingService{
+		db:                database.GetDB(),
+		cfg:               cfg,
+		llmService:        llmService,
+		userService:       userService,
+		cdnService:        cdnService,
+		invalidation:      invalidation,
+		geocoder:          geocoder,
+		cache:             store,
+		summaryExperiment: summaryExperiment,
+		embeddingService:  embeddingService,
+		eventQueue:        eventQueue,
+		privacy:           newCountPrivacy(cfg),
+		radiusBuckets:     make(map[int]time.Time),
+	}
+	invalidation.Subscribe(InvalidationTrending, func(string) {
+		s.clearCache()
+	})
+	invalidation.Subscribe(InvalidationTrendingNear, func(key string) {
+		lat, lon, err := parseTrendingLocationKey(key)
+		if err != nil {
+			log.Printf("Ignoring trending invalidation %q: %v", key, err)
+			return
+		}
+		s.invalidateCacheNear(lat, lon)
+	})
+	return s
+}
+
+// proximityCurve returns the configured boost for nearby articles
+func (s *TrendingService) proximityCurve() utils.ProximityCurve {
+	return utils.ProximityCurve{
+		Kind:     s.cfg.TrendingProximityCurve,
+		MaxBoost: s.cfg.TrendingProximityBoost,
+		ScaleKm:  s.cfg.TrendingProximityScaleKm,
 	}
 }
 
@@ -46,9 +115,10 @@ func (s *TrendingService) GetTrendingNews(lat, lon, radius float64, limit int) (
 		radius = s.cfg.TrendingRadius
 	}
 
-	if limit == 0 || limit > s.cfg.MaxArticlesReturn {
+	if limit == 0 {
 		limit = s.cfg.MaxArticlesReturn
 	}
+	limit = min(limit, s.cfg.MaxLimit)
 
 	// Generate cache key based on location grid
 	cacheKey := s.getCacheKey(lat, lon, radius)
@@ -65,36 +135,76 @@ func (s *TrendingService) GetTrendingNews(lat, lon, radius float64, limit int) (
 		return nil, nil, fmt.Errorf("failed to calculate trending scores: %w", err)
 	}
 
-	// Sort by trending score
-	sort.Slice(trendingArticles, func(i, j int) bool {
-		return trendingArticles[i].TrendingScore > trendingArticles[j].TrendingScore
-	})
-
-	// Limit results
-	if len(trendingArticles) > limit {
-		trendingArticles = trendingArticles[:limit]
-	}
+	trendingArticles = s.orderTrending(trendingArticles, limit)
 
 	// Cache results
-	cache := &TrendingCache{
+	cached := &TrendingCache{
 		Articles: trendingArticles,
 		CachedAt: time.Now(),
-		Location: fmt.Sprintf("%.4f,%.4f", lat, lon),
+		// Named after the grid cell, as the entry is shared by every
+		// request in it and must not carry one user's (saved) location
+		Location: s.geocoder.LocationName(context.Background(), roundToGrid(lat), roundToGrid(lon)),
 		RadiusKm: radius,
 	}
-	s.putInCache(cacheKey, cache)
+	s.putInCache(cacheKey, cached)
 
 	log.Printf("Calculated and cached %d trending articles for location (%.4f, %.4f)",
 		len(trendingArticles), lat, lon)
 
-	return trendingArticles, cache, nil
+	return trendingArticles, cached, nil
+}
+
+// orderTrending sorts articles by trending score, keeps the top article of
+// each story when STORY_COLLAPSE is set, and limits the result
+func (s *TrendingService) orderTrending(trendingArticles []models.TrendingArticle, limit int) []models.TrendingArticle {
+	sort.Slice(trendingArticles, func(i, j int) bool {
+		a, b := trendingArticles[i], trendingArticles[j]
+		if a.TrendingScore != b.TrendingScore {
+			return a.TrendingScore > b.TrendingScore
+		}
+		return utils.LessOnTie(a, b)
+	})
+
+	if s.cfg.StoryCollapse {
+		seen := make(map[string]bool, len(trendingArticles))
+		collapsed := trendingArticles[:0]
+		for _, article := range trendingArticles {
+			if key := storyKey(&article.Article); !seen[key] {
+				seen[key] = true
+				collapsed = append(collapsed, article)
+			}
+		}
+		trendingArticles = collapsed
+	}
+
+	if len(trendingArticles) > limit {
+		trendingArticles = trendingArticles[:limit]
+	}
+	return trendingArticles
 }
 
-// GetTrendingNewsWithSummaries retrieves trending news with LLM summaries
-func (s *TrendingService) GetTrendingNewsWithSummaries(lat, lon, radius float64, limit int) ([]models.TrendingArticle, *TrendingCache, error) {
-	trendingArticles, cache, err := s.GetTrendingNews(lat, lon, radius, limit)
+// Summary enrichment outcomes reported alongside trending results
+const (
+	SummariesIncluded       = "included"
+	SummariesOmitted        = "omitted"
+	SummariesLLMUnavailable = "skipped_llm_unavailable"
+)
+
+// GetTrendingNewsWithSummaries retrieves trending news with LLM summaries.
+// Enrichment is skipped when not requested or when every LLM provider's
+// circuit breaker is open; the returned status says which happened.
+func (s *TrendingService) GetTrendingNewsWithSummaries(ctx context.Context, lat, lon, radius float64, limit int, includeSummaries bool) ([]models.TrendingArticle, *TrendingCache, string, error) {
+	trendingArticles, cache, err := s.GetTrendingNews(lat, lon, radius, TierLimit(ctx, limit))
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, "", err
+	}
+
+	if !includeSummaries {
+		return trendingArticles, cache, SummariesOmitted, nil
+	}
+	if !s.llmService.Available() {
+		log.Println("LLM unavailable, serving trending without generating summaries")
+		return trendingArticles, cache, SummariesLLMUnavailable, nil
 	}
 
 	// Convert TrendingArticle to Article for batch processing
@@ -108,100 +218,390 @@ func (s *TrendingService) GetTrendingNewsWithSummaries(lat, lon, radius float64,
 	}
 
 	// Batch generate summaries
-	s.llmService.GenerateSummariesBatch(articles)
+	s.llmService.GenerateSummariesBatch(ctx, articles)
 
 	// Copy summaries back to trending articles
 	for i := range trendingArticles {
 		trendingArticles[i].LLMSummary = articles[i].LLMSummary
 	}
 
-	return trendingArticles, cache, nil
+	return trendingArticles, cache, SummariesIncluded, nil
 }
 
-// calculateTrendingScores computes trending scores for articles based on user events
-func (s *TrendingService) calculateTrendingScores(lat, lon, radius float64) ([]models.TrendingArticle, error) {
-	// Get time window
-	timeWindow := time.Now().Add(-time.Duration(s.cfg.TrendingTimeWindow) * time.Hour)
+// trendingScoreGridPrecision is the grid cell size (degrees, ~5km) trending
+// scores are materialized by
+const trendingScoreGridPrecision = 0.05
+
+// Start materializes trending scores immediately and then on every configured
+// interval until ctx is cancelled. A non-positive interval computes them once.
+func (s *TrendingService) Start(ctx context.Context) {
+	if err := s.RefreshScores(); err != nil {
+		log.Printf("Trending score refresh failed: %v", err)
+	}
+	if s.cfg.TrendingRefreshInterval <= 0 {
+		log.Println("Trending score worker disabled after initial refresh")
+		return
+	}
+
+	interval := time.Duration(s.cfg.TrendingRefreshInterval) * time.Second
+	log.Printf("Trending score worker started (interval: %v)", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Trending score worker stopped")
+			return
+		case <-ticker.C:
+		}
+
+		if err := s.RefreshScores(); err != nil {
+			log.Printf("Trending score refresh failed: %v", err)
+		}
+	}
+}
+
+// RefreshScores recomputes the trending_scores table from the events in the
+// time window: per grid cell and article, the event count, the distinct users,
+// the event weights with recency decay (each user's first
+// TrendingMaxEventsPerUser events only), and the events' mean location
+func (s *TrendingService) RefreshScores() error {
+	now := time.Now().UTC()
+	timeWindow := now.Add(-time.Duration(s.cfg.TrendingTimeWindow) * time.Hour)
 
-	// Get all events within time window
 	var events []models.UserEvent
-	err := s.db.Where("timestamp >= ?", timeWindow).Find(&events).Error
+	err := s.db.Select("article_id", "user_id", "event_type", "latitude", "longitude", "timestamp").
+		Where("timestamp >= ?", timeWindow).
+		Order("timestamp").
+		Find(&events).Error
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch user events: %w", err)
+		return fmt.Errorf("failed to fetch user events: %w", err)
 	}
+	rows := s.scoreEvents(events, now)
 
-	log.Printf("Found %d user events in last %d hours", len(events), s.cfg.TrendingTimeWindow)
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.TrendingScore{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(&rows, 500).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store trending scores: %w", err)
+	}
 
-	// Filter events by location and aggregate by article
-	articleEvents := make(map[string][]models.UserEvent)
+	// Cached results were computed from the previous scores
+	s.clearCache()
+	log.Printf("Materialized %d trending scores from %d events in %v", len(rows), len(events), time.Since(now))
+	return nil
+}
+
+// scoreEvents aggregates time-ordered events into per-cell trending scores,
+// decaying each event's weight by its age at now
+func (s *TrendingService) scoreEvents(events []models.UserEvent, now time.Time) []models.TrendingScore {
+	type scoreKey struct {
+		cell      utils.GridCell
+		articleID string
+	}
+	type userKey struct {
+		articleID string
+		userID    string
+	}
+	scores := make(map[scoreKey]*models.TrendingScore)
+	userEvents := make(map[userKey]int)
 	for _, event := range events {
-		if utils.IsWithinRadius(lat, lon, event.Latitude, event.Longitude, radius) {
-			articleEvents[event.ArticleID] = append(articleEvents[event.ArticleID], event)
+		cell := utils.ToGridCell(event.Latitude, event.Longitude, trendingScoreGridPrecision)
+		key := scoreKey{cell, event.ArticleID}
+		score, ok := scores[key]
+		if !ok {
+			score = &models.TrendingScore{
+				LatCell:    cell.LatCell,
+				LonCell:    cell.LonCell,
+				ArticleID:  event.ArticleID,
+				ComputedAt: now,
+			}
+			scores[key] = score
+		}
+		score.AddEvent(event.EventType)
+		score.Latitude += event.Latitude
+		score.Longitude += event.Longitude
+
+		user := userKey{event.ArticleID, event.UserID}
+		userEvents[user]++
+		if userEvents[user] == 1 {
+			score.Users++
+		}
+		if s.countsTowardScore(userEvents[user]) {
+			score.Weight += models.GetEventWeight(event.EventType) *
+				utils.CalculateRecencyFactor(now.Sub(event.Timestamp).Hours())
 		}
 	}
 
-	log.Printf("Found events for %d articles within %.2f km", len(articleEvents), radius)
+	rows := make([]models.TrendingScore, 0, len(scores))
+	for _, score := range scores {
+		score.Latitude /= float64(score.Events)
+		score.Longitude /= float64(score.Events)
+		rows = append(rows, *score)
+	}
+	return rows
+}
+
+// addEventScore folds a new event into its cell's materialized score so it
+// counts before the next refresh
+func (s *TrendingService) addEventScore(event *models.UserEvent) error {
+	cell := utils.ToGridCell(event.Latitude, event.Longitude, trendingScoreGridPrecision)
+	// The user's events on the article in the window, including this one
+	var n int64
+	err := s.db.Model(&models.UserEvent{}).
+		Where("article_id = ? AND user_id = ? AND timestamp >= ?", event.ArticleID, event.UserID,
+			time.Now().UTC().Add(-time.Duration(s.cfg.TrendingTimeWindow)*time.Hour)).
+		Count(&n).Error
+	if err != nil {
+		return fmt.Errorf("failed to count user events: %w", err)
+	}
+	users := 0
+	if n <= 1 {
+		users = 1
+	}
+	weight := 0.0
+	if s.countsTowardScore(int(n)) {
+		weight = models.GetEventWeight(event.EventType)
+	}
 
-	if len(articleEvents) == 0 {
+	column := models.TrendingScoreColumn(event.EventType)
+	score := models.TrendingScore{
+		LatCell:    cell.LatCell,
+		LonCell:    cell.LonCell,
+		ArticleID:  event.ArticleID,
+		Latitude:   event.Latitude,
+		Longitude:  event.Longitude,
+		Users:      users,
+		Weight:     weight,
+		ComputedAt: event.Timestamp,
+	}
+	score.AddEvent(event.EventType)
+	return s.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "lat_cell"}, {Name: "lon_cell"}, {Name: "article_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"latitude":  gorm.Expr("(trending_scores.latitude * trending_scores.events + ?) / (trending_scores.events + 1)", event.Latitude),
+			"longitude": gorm.Expr("(trending_scores.longitude * trending_scores.events + ?) / (trending_scores.events + 1)", event.Longitude),
+			"events":    gorm.Expr("trending_scores.events + 1"),
+			"users":     gorm.Expr("trending_scores.users + ?", users),
+			"weight":    gorm.Expr("trending_scores.weight + ?", weight),
+			column:      gorm.Expr("trending_scores." + column + " + 1"),
+		}),
+	}).Create(&score).Error
+}
+
+// countsTowardScore reports whether a user's nth event on an article in the
+// time window adds weight, so one user can't push an article up alone
+func (s *TrendingService) countsTowardScore(n int) bool {
+	return s.cfg.TrendingMaxEventsPerUser <= 0 || n <= s.cfg.TrendingMaxEventsPerUser
+}
+
+// calculateTrendingScores sums the materialized scores of the cells whose
+// events lie within radius, per article
+func (s *TrendingService) calculateTrendingScores(lat, lon, radius float64) ([]models.TrendingArticle, error) {
+	box := utils.BoundingBoxAround(lat, lon, radius)
+	var rows []models.TrendingScore
+	err := s.db.Where("latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?",
+		box.MinLat, box.MaxLat, box.MinLon, box.MaxLon).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch trending scores: %w", err)
+	}
+
+	scores := sumArticleScores(rows, lat, lon, radius)
+	log.Printf("Found trending scores for %d articles within %.2f km", len(scores), radius)
+	return s.rankTrending(scores, lat, lon, radius, time.Now())
+}
+
+// rankTrending turns the scores summed over a radius into trending articles
+// as published at now: small cells suppressed, counts noised per location
+// cell, and the relevance fallback when nothing is left. Live trending and
+// replays share it, and the noise is seeded by the cell rather than drawn,
+// so a replay of the present matches what was served.
+func (s *TrendingService) rankTrending(scores map[string]*articleScore, lat, lon, radius float64, now time.Time) ([]models.TrendingArticle, error) {
+	s.suppressSmallCells(scores)
+	if len(scores) == 0 {
 		// No events found, return popular articles by relevance score
-		return s.getFallbackTrending(lat, lon, radius)
+		return s.getFallbackTrending(lat, lon, radius, now)
+	}
+	s.addCountNoise(scores, s.getCacheKey(lat, lon, radius))
+	trendingArticles, err := s.toTrendingArticles(scores, lat, lon)
+	if err != nil || len(trendingArticles) > 0 {
+		return trendingArticles, err
 	}
+	// Every engaged article was opted out of trending or anomalous
+	return s.getFallbackTrending(lat, lon, radius, now)
+}
 
-	// Calculate trending score for each article
-	trendingArticles := []models.TrendingArticle{}
-	now := time.Now()
+// articleScore is an article's materialized scores summed over a radius
+type articleScore struct {
+	events    int
+	users     int
+	weight    float64
+	breakdown map[string]int
+}
 
-	for articleID, events := range articleEvents {
-		// Fetch article details
-		var article models.Article
-		if err := s.db.Where("id = ?", articleID).First(&article).Error; err != nil {
-			log.Printf("Article %s not found, skipping", articleID)
+// sumArticleScores sums, per article, the scores of the cells whose events
+// lie within radius
+func sumArticleScores(rows []models.TrendingScore, lat, lon, radius float64) map[string]*articleScore {
+	scores := make(map[string]*articleScore)
+	for _, row := range rows {
+		if !utils.IsWithinRadius(lat, lon, row.Latitude, row.Longitude, radius) {
 			continue
 		}
+		score, ok := scores[row.ArticleID]
+		if !ok {
+			score = &articleScore{breakdown: make(map[string]int)}
+			scores[row.ArticleID] = score
+		}
+		score.events += row.Events
+		score.users += row.Users
+		score.weight += row.Weight
+		score.breakdown[models.EventTypeView] += row.Views
+		score.breakdown[models.EventTypeClick] += row.Clicks
+		score.breakdown[models.EventTypeShare] += row.Shares
+	}
+	return scores
+}
 
-		// Calculate distance from query location
-		distance := utils.CalculateDistance[models.Article](&article, lat, lon)
+// toTrendingArticles loads the scored articles and computes their trending
+// scores, boosted by relevance, source reliability and proximity to the
+// query location. Articles opted out of trending or with anomalous
+// engagement are dropped.
+func (s *TrendingService) toTrendingArticles(scores map[string]*articleScore, lat, lon float64) ([]models.TrendingArticle, error) {
+	excludedSources, err := s.excludedSourceNames()
+	if err != nil {
+		return nil, err
+	}
+	ratings, err := loadSourceRatings(s.db)
+	if err != nil {
+		return nil, err
+	}
 
-		// Calculate trending score
-		totalWeight := 0.0
-		for _, event := range events {
-			// Weight by event type
-			weight := models.GetEventWeight(event.EventType)
+	ids := make([]string, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	var articles []models.Article
+	if err := s.db.Where("id IN ?", ids).Find(&articles).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch trending articles: %w", err)
+	}
 
-			// Apply recency decay
-			hoursAgo := now.Sub(event.Timestamp).Hours()
-			recencyFactor := utils.CalculateRecencyFactor(hoursAgo)
+	curve := s.proximityCurve()
+	trendingArticles := make([]models.TrendingArticle, 0, len(articles))
+	for _, article := range articles {
+		// Honor editorial opt-outs
+		if article.ExcludeFromTrending || excludedSources[article.SourceName] {
+			continue
+		}
 
-			totalWeight += weight * recencyFactor
+		// Drop articles whose engagement comes from a few very active users
+		score := scores[article.ID]
+		if utils.IsTrendingAnomaly(score.events, score.users, s.cfg.TrendingAnomalyMinEvents, s.cfg.TrendingAnomalyEventsPerUser) {
+			log.Printf("Dropping anomalous trending article %s: %d events from %d users", article.ID, score.events, score.users)
+			continue
 		}
 
+		// Calculate distance from query location
+		distance := utils.CalculateDistance[models.Article](&article, lat, lon)
+
 		// Compute final trending score
-		trendingScore := utils.ComputeTrendingScore(len(events), totalWeight, 1.0)
+		trendingScore := utils.ComputeTrendingScore(score.events, score.weight, 1.0)
 
-		// Boost by article relevance and proximity
+		// Boost by article relevance, source reliability and proximity
 		trendingScore *= (1.0 + article.RelevanceScore*0.2)
-		if distance < 10 {
-			trendingScore *= 1.5 // Boost very local news
-		}
+		trendingScore *= ratings.factor(s.cfg, article.SourceName)
+		trendingScore *= curve.Boost(distance)
+
+		trendingArticles = append(trendingArticles, models.TrendingArticle{
+			Article:        article,
+			TrendingScore:  trendingScore,
+			EventCount:     score.events,
+			UniqueUsers:    score.users,
+			EventBreakdown: score.breakdown,
+		})
+	}
 
-		trendingArticle := models.TrendingArticle{
-			Article:       article,
-			TrendingScore: trendingScore,
-			EventCount:    len(events),
-		}
+	return trendingArticles, nil
+}
 
-		trendingArticles = append(trendingArticles, trendingArticle)
+// TrendingReplay is what trending would have returned at a past moment
+type TrendingReplay struct {
+	At          time.Time
+	WindowStart time.Time
+	Events      int  // Events in the time window, anywhere
+	Complete    bool // False when raw events of the window may have been deleted
+	Articles    []models.TrendingArticle
+	RadiusKm    float64
+}
+
+// ReplayTrending recomputes trending around a location as of at, from the
+// events in the time window ending then, scored as the refresh worker would
+// have and ranked as live trending is. Article data and exclusions are
+// today's. Rollups have no location, so events past EVENT_RETENTION_DAYS
+// can't be replayed.
+func (s *TrendingService) ReplayTrending(at time.Time, lat, lon, radius float64, limit int) (*TrendingReplay, error) {
+	if radius == 0 {
+		radius = s.cfg.TrendingRadius
 	}
+	if limit == 0 {
+		limit = s.cfg.MaxArticlesReturn
+	}
+	limit = min(limit, s.cfg.MaxLimit)
 
-	return trendingArticles, nil
+	at = at.UTC()
+	windowStart := at.Add(-time.Duration(s.cfg.TrendingTimeWindow) * time.Hour)
+	var events []models.UserEvent
+	err := s.db.Select("article_id", "user_id", "event_type", "latitude", "longitude", "timestamp").
+		Where("timestamp >= ? AND timestamp <= ?", windowStart, at).
+		Order("timestamp").
+		Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user events: %w", err)
+	}
+
+	replay := &TrendingReplay{
+		At:          at,
+		WindowStart: windowStart,
+		Events:      len(events),
+		Complete:    s.cfg.EventRetentionDays <= 0 || !windowStart.Before(time.Now().AddDate(0, 0, -s.cfg.EventRetentionDays)),
+		Articles:    []models.TrendingArticle{},
+		RadiusKm:    radius,
+	}
+
+	scores := sumArticleScores(s.scoreEvents(events, at), lat, lon, radius)
+	trendingArticles, err := s.rankTrending(scores, lat, lon, radius, at)
+	if err != nil {
+		return nil, err
+	}
+	replay.Articles = s.orderTrending(trendingArticles, limit)
+	return replay, nil
 }
 
-// getFallbackTrending returns popular articles when no events are found
-func (s *TrendingService) getFallbackTrending(lat, lon, radius float64) ([]models.TrendingArticle, error) {
+// getFallbackTrending returns the articles popular at now when no events are
+// found
+func (s *TrendingService) getFallbackTrending(lat, lon, radius float64, now time.Time) ([]models.TrendingArticle, error) {
 	var articles []models.Article
 
-	// Get all articles
-	s.db.Find(&articles)
+	// Only consider articles published within the freshness horizon
+	windowHours := float64(s.cfg.TrendingTimeWindow)
+	horizon := time.Duration(windowHours*s.cfg.TrendingFallbackFreshness) * time.Hour
+
+	// Get fresh articles not opted out of trending
+	err := s.db.Where("exclude_from_trending = ?", false).
+		Where("source_name NOT IN (?)", s.excludedSourcesQuery()).
+		Where("publication_date >= ? AND publication_date <= ?", now.Add(-horizon), now).
+		Find(&articles).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch fallback articles: %w", err)
+	}
 
 	// Filter by location and score using generic helper
 	scoreThreshold := s.cfg.ScoreThreshold
@@ -212,141 +612,666 @@ func (s *TrendingService) getFallbackTrending(lat, lon, radius float64) ([]model
 		},
 	)
 
-	// Convert to TrendingArticle
+	// Convert to TrendingArticle, scoring relevance decayed by age
 	trendingArticles := make([]models.TrendingArticle, len(filtered))
 	for i, article := range filtered {
+		hoursAgo := now.Sub(article.PublicationDate).Hours()
 		trendingArticles[i] = models.TrendingArticle{
 			Article:       article,
-			TrendingScore: article.RelevanceScore * 10, // Use relevance as fallback score
+			TrendingScore: article.RelevanceScore * 10 * utils.CalculateFreshnessFactor(hoursAgo, windowHours),
 			EventCount:    0,
 		}
 	}
 
-	log.Printf("Fallback: returning %d articles with high relevance scores", len(trendingArticles))
+	log.Printf("Fallback: returning %d articles published in the last %v", len(trendingArticles), horizon)
 	return trendingArticles, nil
 }
 
+// excludedSourcesQuery selects the names of sources opted out of trending
+func (s *TrendingService) excludedSourcesQuery() *gorm.DB {
+	return s.db.Model(&models.Source{}).Where("exclude_from_trending = ?", true).Select("name")
+}
+
+// excludedSourceNames returns the set of sources opted out of trending
+func (s *TrendingService) excludedSourceNames() (map[string]bool, error) {
+	var names []string
+	if err := s.excludedSourcesQuery().Pluck("name", &names).Error; err != nil {
+		return nil, fmt.Errorf("failed to load trending exclusions: %w", err)
+	}
+
+	excluded := make(map[string]bool, len(names))
+	for _, name := range names {
+		excluded[name] = true
+	}
+	return excluded, nil
+}
+
+// SetArticleExclusion opts an article in or out of trending
+func (s *TrendingService) SetArticleExclusion(articleID string, excluded bool) error {
+	result := s.db.Model(&models.Article{}).
+		Where("id = ?", articleID).
+		Update("exclude_from_trending", excluded)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	s.InvalidateCache()
+	return nil
+}
+
+// SetSourceExclusion opts a whole source in or out of trending, creating the
+// source record if it doesn't exist yet
+func (s *TrendingService) SetSourceExclusion(sourceName string, excluded bool) error {
+	source := models.Source{Name: sourceName, Enabled: true}
+	err := s.db.Where(models.Source{Name: sourceName}).
+		Attrs(source).
+		FirstOrCreate(&source).Error
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.Model(&source).Update("exclude_from_trending", excluded).Error; err != nil {
+		return err
+	}
+
+	s.InvalidateCache()
+	return nil
+}
+
+// GetExclusions lists articles and sources currently opted out of trending
+func (s *TrendingService) GetExclusions() (map[string]interface{}, error) {
+	var articleIDs, sourceNames []string
+	if err := s.db.Model(&models.Article{}).
+		Where("exclude_from_trending = ?", true).
+		Pluck("id", &articleIDs).Error; err != nil {
+		return nil, err
+	}
+	if err := s.excludedSourcesQuery().Pluck("name", &sourceNames).Error; err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"articles": articleIDs,
+		"sources":  sourceNames,
+	}, nil
+}
+
 // getCacheKey generates a cache key based on location
 func (s *TrendingService) getCacheKey(lat, lon, radius float64) string {
 	// Round to grid cells for better cache hits
-	// Grid size ~5km
-	precision := 0.05
-	latCell := int(lat / precision)
-	lonCell := int(lon / precision)
-	radiusCell := int(radius / 10) // Group by 10km radius increments
+	cell := utils.ToGridCell(lat, lon, cacheGridPrecision)
+	radiusCell := int(radius / cacheRadiusBucket)
+
+	return gridCacheKey(cell, radiusCell)
+}
+
+// roundToGrid rounds a coordinate to the trending cache's grid size
+func roundToGrid(degrees float64) float64 {
+	return math.Round(degrees/cacheGridPrecision) * cacheGridPrecision
+}
 
-	return fmt.Sprintf("trending_%d_%d_%d", latCell, lonCell, radiusCell)
+// gridCacheKey formats the cache key for a grid cell and radius bucket
+func gridCacheKey(cell utils.GridCell, radiusCell int) string {
+	return fmt.Sprintf("%s%d_%d_%d", trendingCachePrefix, cell.LatCell, cell.LonCell, radiusCell)
 }
 
-// getFromCache retrieves cached trending data if still valid
+// cacheTTL is how long trending results stay cached
+func (s *TrendingService) cacheTTL() time.Duration {
+	return time.Duration(s.cfg.TrendingCacheTTL) * time.Second
+}
+
+// getFromCache retrieves cached trending data if still valid. Cache errors
+// are logged and treated as a miss.
 func (s *TrendingService) getFromCache(key string) (*TrendingCache, bool) {
-	if cached, ok := s.cache.Load(key); ok {
-		cache := cached.(*TrendingCache)
+	data, ok, err := s.cache.Get(context.Background(), key)
+	if err != nil {
+		log.Printf("Failed to read trending cache: %v", err)
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
 
-		// Check if cache is still valid
-		if time.Since(cache.CachedAt).Seconds() < float64(s.cfg.TrendingCacheTTL) {
-			return cache, true
-		}
+	var cached TrendingCache
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&cached); err != nil {
+		log.Printf("Ignoring malformed trending cache entry %s: %v", key, err)
+		return nil, false
+	}
+	// Entries written with a longer TTL than ours are expired here
+	if time.Since(cached.CachedAt) >= s.cacheTTL() {
+		return nil, false
+	}
+	return &cached, true
+}
+
+// putInCache stores trending data in cache. Entries are gob-encoded since
+// Article's JSON decoding expects the dataset format.
+func (s *TrendingService) putInCache(key string, cached *TrendingCache) {
+	var data bytes.Buffer
+	if err := gob.NewEncoder(&data).Encode(cached); err != nil {
+		log.Printf("Failed to encode trending cache entry %s: %v", key, err)
+		return
+	}
+
+	if err := s.cache.Set(context.Background(), key, data.Bytes(), s.cacheTTL()); err != nil {
+		log.Printf("Failed to write trending cache: %v", err)
+		return
+	}
+	s.radiusMu.Lock()
+	s.radiusBuckets[int(cached.RadiusKm/cacheRadiusBucket)] = time.Now().Add(s.cacheTTL())
+	s.radiusMu.Unlock()
+}
 
-		// Cache expired, remove it
-		s.cache.Delete(key)
-		s.cacheTimes.Delete(key)
+// cachedRadiusBuckets returns the radius buckets this instance may still
+// have results cached for, forgetting expired ones
+func (s *TrendingService) cachedRadiusBuckets() []int {
+	s.radiusMu.Lock()
+	defer s.radiusMu.Unlock()
+	now := time.Now()
+	buckets := make([]int, 0, len(s.radiusBuckets))
+	for bucket, expires := range s.radiusBuckets {
+		if now.After(expires) {
+			delete(s.radiusBuckets, bucket)
+			continue
+		}
+		buckets = append(buckets, bucket)
 	}
+	return buckets
+}
 
-	return nil, false
+// TrendingLocationKey formats a point for InvalidationTrendingNear
+func TrendingLocationKey(lat, lon float64) string {
+	return strconv.FormatFloat(lat, 'f', -1, 64) + "," + strconv.FormatFloat(lon, 'f', -1, 64)
 }
 
-// putInCache stores trending data in cache
-func (s *TrendingService) putInCache(key string, cache *TrendingCache) {
-	s.cache.Store(key, cache)
-	s.cacheTimes.Store(key, time.Now())
+func parseTrendingLocationKey(key string) (float64, float64, error) {
+	latText, lonText, ok := strings.Cut(key, ",")
+	if !ok {
+		return 0, 0, errors.New("expected lat,lon")
+	}
+	lat, err := strconv.ParseFloat(latText, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	lon, err := strconv.ParseFloat(lonText, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	return lat, lon, nil
+}
+
+// invalidateCacheNear removes only the cache entries whose query area could
+// include the given coordinates, leaving the rest of the cache intact. It
+// covers the radius buckets this instance cached; entries other instances
+// wrote to a shared cache are theirs to drop when they get the
+// InvalidationTrendingNear broadcast.
+func (s *TrendingService) invalidateCacheNear(lat, lon float64) int {
+	var keys []string
+	for _, radiusCell := range s.cachedRadiusBuckets() {
+		// Queries in this bucket cover at most the bucket's upper bound
+		maxRadius := float64(radiusCell+1) * cacheRadiusBucket
+
+		for _, cell := range utils.GridCellsWithin(lat, lon, maxRadius, cacheGridPrecision) {
+			keys = append(keys, gridCacheKey(cell, radiusCell))
+		}
+	}
+	if len(keys) == 0 {
+		return 0
+	}
+
+	removed, err := s.cache.Delete(context.Background(), keys...)
+	if err != nil {
+		log.Printf("Failed to invalidate trending cache near (%.4f, %.4f): %v", lat, lon, err)
+	}
+	return removed
 }
 
 // InvalidateCache clears all cached trending data
 func (s *TrendingService) InvalidateCache() {
-	s.cache.Range(func(key, value interface{}) bool {
-		s.cache.Delete(key)
-		return true
-	})
-	s.cacheTimes.Range(func(key, value interface{}) bool {
-		s.cacheTimes.Delete(key)
-		return true
-	})
+	s.clearCache()
+	s.invalidation.Publish(InvalidationTrending, "")
+	s.cdnService.Purge(SurrogateKeyTrending)
 	log.Println("Trending cache invalidated")
 }
 
-// RecordUserEvent records a user interaction with an article
-func (s *TrendingService) RecordUserEvent(articleID, userID, eventType string, lat, lon float64) error {
+// clearCache drops the cached trending results
+func (s *TrendingService) clearCache() {
+	if _, err := cache.Clear(context.Background(), s.cache, trendingCachePrefix); err != nil {
+		log.Printf("Failed to clear trending cache: %v", err)
+	}
+}
+
+// RecordUserEvent records a user interaction with an article, decoded
+// from any event schema version by eventschema.Decode
+func (s *TrendingService) RecordUserEvent(in eventschema.Event) error {
 	// Validate event type
-	validTypes := map[string]bool{
-		models.EventTypeView:  true,
-		models.EventTypeClick: true,
-		models.EventTypeShare: true,
+	if !models.IsValidEventType(in.EventType) {
+		return fmt.Errorf("invalid event type: %s", in.EventType)
 	}
 
-	if !validTypes[eventType] {
-		return fmt.Errorf("invalid event type: %s", eventType)
+	leave, ok := s.eventQueue.TryEnter()
+	if !ok {
+		return ErrEventQueueFull
 	}
+	defer leave()
 
-	// Create event
+	// Create event, attributing it to the canonical user across devices
 	event := models.UserEvent{
-		ArticleID: articleID,
-		UserID:    userID,
-		EventType: eventType,
-		Latitude:  lat,
-		Longitude: lon,
-		Timestamp: time.Now(),
+		ArticleID:     in.ArticleID,
+		UserID:        s.userService.ResolveUserID(in.UserID),
+		DeviceID:      in.UserID,
+		EventType:     in.EventType,
+		Latitude:      in.Lat,
+		Longitude:     in.Lon,
+		DwellMs:       in.DwellMs,
+		Platform:      in.Platform,
+		SchemaVersion: in.SchemaVersion,
+		Timestamp:     time.Now(),
 	}
 
 	if err := s.db.Create(&event).Error; err != nil {
 		return fmt.Errorf("failed to record user event: %w", err)
 	}
 
-	log.Printf("Recorded %s event for article %s by user %s", eventType, articleID, userID)
+	log.Printf("Recorded %s event for article %s by user %s", event.EventType, event.ArticleID, event.DeviceID)
+	s.summaryExperiment.RecordEvent(event.UserID, event.EventType)
+	if err := s.embeddingService.RecordInterest(event.UserID, event.ArticleID, event.EventType, event.Timestamp); err != nil {
+		log.Printf("Failed to update interest vector of user %s: %v", event.UserID, err)
+	}
+
+	if err := s.addEventScore(&event); err != nil {
+		log.Printf("Failed to add event to trending scores: %v", err)
+	}
 
-	// Invalidate nearby caches (simple approach)
-	// In production, use more sophisticated cache invalidation
-	s.InvalidateCache()
+	// Invalidate only the cached grid cells that could include this event,
+	// here and on the other instances
+	if removed := s.invalidateCacheNear(event.Latitude, event.Longitude); removed > 0 {
+		log.Printf("Invalidated %d trending cache entries near (%.4f, %.4f)", removed, event.Latitude, event.Longitude)
+	}
+	s.invalidation.Publish(InvalidationTrendingNear, TrendingLocationKey(event.Latitude, event.Longitude))
 
 	return nil
 }
 
-// GetEventStats returns statistics about user events
-func (s *TrendingService) GetEventStats() (map[string]interface{}, error) {
-	var totalEvents int64
-	var uniqueArticles int64
-	var uniqueUsers int64
+// GetEventStats returns statistics about user events. Hours already rolled
+// up are counted from event_rollups, except for region filters, which need
+// the raw events' locations; unique_users only covers raw events.
+func (s *TrendingService) GetEventStats(filter EventStatsFilter) (map[string]interface{}, error) {
+	if filter.Region != nil && filter.Region.RadiusKm <= 0 {
+		region := *filter.Region
+		region.RadiusKm = s.cfg.TrendingRadius
+		filter.Region = &region
+	}
 
-	s.db.Model(&models.UserEvent{}).Count(&totalEvents)
-	s.db.Model(&models.UserEvent{}).Distinct("article_id").Count(&uniqueArticles)
-	s.db.Model(&models.UserEvent{}).Distinct("user_id").Count(&uniqueUsers)
+	watermark, err := rollupWatermark(s.db)
+	if err != nil {
+		return nil, err
+	}
+	rawFilter := filter
+	withRollups := filter.Region == nil && !watermark.IsZero() &&
+		(filter.From.IsZero() || filter.From.Before(watermark))
+	if withRollups {
+		// Raw events before the watermark are counted in the rollups
+		rawFilter.From = watermark
+	}
+
+	var totals struct {
+		TotalEvents    int64
+		UniqueArticles int64
+		UniqueUsers    int64
+	}
+	err = rawFilter.apply(s.db.Model(&models.UserEvent{})).
+		Select("COUNT(*) AS total_events, COUNT(DISTINCT article_id) AS unique_articles, COUNT(DISTINCT user_id) AS unique_users").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count events: %w", err)
+	}
 
 	// Event type breakdown
-	var viewCount, clickCount, shareCount int64
-	s.db.Model(&models.UserEvent{}).Where("event_type = ?", models.EventTypeView).Count(&viewCount)
-	s.db.Model(&models.UserEvent{}).Where("event_type = ?", models.EventTypeClick).Count(&clickCount)
-	s.db.Model(&models.UserEvent{}).Where("event_type = ?", models.EventTypeShare).Count(&shareCount)
+	var typeCounts []eventTypeCount
+	err = rawFilter.apply(s.db.Model(&models.UserEvent{})).
+		Select("event_type, COUNT(*) AS count").
+		Group("event_type").
+		Scan(&typeCounts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count events by type: %w", err)
+	}
+	byType := make(map[string]int64, len(typeCounts))
+	for _, tc := range typeCounts {
+		byType[tc.EventType] = tc.Count
+	}
+
+	if withRollups {
+		var rollupCounts []eventTypeCount
+		err := filter.applyRollups(s.db.Model(&models.EventRollup{})).
+			Select("event_type, SUM(count) AS count").
+			Group("event_type").
+			Scan(&rollupCounts).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to count rolled-up events: %w", err)
+		}
+		for _, tc := range rollupCounts {
+			byType[tc.EventType] += tc.Count
+			totals.TotalEvents += tc.Count
+		}
+
+		// Articles can have events on both sides of the watermark
+		if len(rollupCounts) > 0 {
+			var rawIDs, rollupIDs []string
+			if err := rawFilter.apply(s.db.Model(&models.UserEvent{})).Distinct().Pluck("article_id", &rawIDs).Error; err != nil {
+				return nil, fmt.Errorf("failed to load event articles: %w", err)
+			}
+			if err := filter.applyRollups(s.db.Model(&models.EventRollup{})).Distinct().Pluck("article_id", &rollupIDs).Error; err != nil {
+				return nil, fmt.Errorf("failed to load rolled-up event articles: %w", err)
+			}
+			articles := make(map[string]bool, len(rawIDs)+len(rollupIDs))
+			for _, id := range append(rawIDs, rollupIDs...) {
+				articles[id] = true
+			}
+			totals.UniqueArticles = int64(len(articles))
+		}
+	}
 
 	stats := map[string]interface{}{
-		"total_events":      totalEvents,
-		"unique_articles":   uniqueArticles,
-		"unique_users":      uniqueUsers,
-		"views":             viewCount,
-		"clicks":            clickCount,
-		"shares":            shareCount,
+		"total_events":      totals.TotalEvents,
+		"unique_articles":   totals.UniqueArticles,
+		"unique_users":      totals.UniqueUsers,
+		"views":             byType[models.EventTypeView],
+		"clicks":            byType[models.EventTypeClick],
+		"shares":            byType[models.EventTypeShare],
 		"cache_size":        s.getCacheSize(),
 		"cache_ttl_seconds": s.cfg.TrendingCacheTTL,
 	}
 
+	if filter.Bucket != "" {
+		rollupsUntil := time.Time{}
+		if withRollups {
+			rollupsUntil = watermark
+		}
+		buckets, err := s.eventBuckets(filter, rollupsUntil)
+		if err != nil {
+			return nil, err
+		}
+		stats["bucket"] = filter.Bucket
+		stats["buckets"] = buckets
+	}
+	if filter.Region != nil {
+		s.protectRegionStats(stats, filter, totals.UniqueUsers)
+	}
+
 	return stats, nil
 }
 
+// Time bucket sizes supported by GetEventStats
+const (
+	EventBucketHour = "hour"
+	EventBucketDay  = "day"
+)
+
+// eventBucketFormats maps bucket sizes to SQLite strftime formats
+var eventBucketFormats = map[string]string{
+	EventBucketHour: "%Y-%m-%dT%H:00:00Z",
+	EventBucketDay:  "%Y-%m-%d",
+}
+
+// IsValidEventBucket reports whether bucket is a supported bucket size
+func IsValidEventBucket(bucket string) bool {
+	_, ok := eventBucketFormats[bucket]
+	return ok
+}
+
+// EventPage is one page of the raw event listing
+type EventPage struct {
+	Events     []models.UserEvent
+	NextCursor string // empty on the last page
+}
+
+// ListEvents returns up to limit raw events matching filter after cursor,
+// newest first; Region and Bucket are ignored. A non-empty userID matches
+// the canonical user it resolves to. Pages are keyset-based, so events
+// recorded while an admin pages don't shift what comes next.
+func (s *TrendingService) ListEvents(filter EventStatsFilter, userID string, cursor utils.Cursor, limit int) (*EventPage, error) {
+	filter.Region = nil
+	query := filter.apply(s.db.Model(&models.UserEvent{}))
+	if userID != "" {
+		query = query.Where("user_id = ?", s.userService.ResolveUserID(userID))
+	}
+	if !cursor.IsZero() {
+		id, err := strconv.ParseUint(cursor.ID, 10, 64)
+		if err != nil {
+			return nil, utils.ErrInvalidCursor
+		}
+		query = afterCursor(query, "timestamp", cursor.Time.UTC(), id)
+	}
+
+	var events []models.UserEvent
+	if err := query.Order("timestamp DESC, id DESC").Limit(limit + 1).Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	page := &EventPage{Events: events}
+	if len(events) > limit {
+		page.Events = events[:limit]
+		last := page.Events[limit-1]
+		page.NextCursor = utils.EncodeCursor(last.Timestamp, strconv.FormatUint(uint64(last.ID), 10))
+	}
+	return page, nil
+}
+
+// EventStatsFilter narrows the events included in GetEventStats.
+// Zero values mean "no filter".
+type EventStatsFilter struct {
+	From      time.Time
+	To        time.Time
+	Bucket    string // "", EventBucketHour or EventBucketDay
+	ArticleID string
+	EventType string
+	Region    *EventRegion
+}
+
+// EventRegion limits stats to events around a point. The region is matched
+// as a bounding box so it can be filtered in SQL.
+type EventRegion struct {
+	Lat      float64
+	Lon      float64
+	RadiusKm float64 // defaults to TRENDING_RADIUS
+}
+
+// apply adds the filter's conditions to a user_events query
+func (f EventStatsFilter) apply(query *gorm.DB) *gorm.DB {
+	if !f.From.IsZero() {
+		query = query.Where("timestamp >= ?", f.From.UTC())
+	}
+	if !f.To.IsZero() {
+		query = query.Where("timestamp < ?", f.To.UTC())
+	}
+	if f.ArticleID != "" {
+		query = query.Where("article_id = ?", f.ArticleID)
+	}
+	if f.EventType != "" {
+		query = query.Where("event_type = ?", f.EventType)
+	}
+	if f.Region != nil {
+		box := utils.BoundingBoxAround(f.Region.Lat, f.Region.Lon, f.Region.RadiusKm)
+		query = query.Where("latitude BETWEEN ? AND ?", box.MinLat, box.MaxLat).
+			Where("longitude BETWEEN ? AND ?", box.MinLon, box.MaxLon)
+	}
+	return query
+}
+
+// applyRollups adds the filter's conditions to an event_rollups query, at
+// hourly granularity. Rollups have no location, so Region is ignored.
+func (f EventStatsFilter) applyRollups(query *gorm.DB) *gorm.DB {
+	if !f.From.IsZero() {
+		query = query.Where("hour >= ?", f.From.UTC().Truncate(time.Hour))
+	}
+	if !f.To.IsZero() {
+		query = query.Where("hour < ?", f.To.UTC())
+	}
+	if f.ArticleID != "" {
+		query = query.Where("article_id = ?", f.ArticleID)
+	}
+	if f.EventType != "" {
+		query = query.Where("event_type = ?", f.EventType)
+	}
+	return query
+}
+
+// eventTypeCount is a row of an event count grouped by type
+type eventTypeCount struct {
+	EventType string
+	Count     int64
+}
+
+// EventBucket holds event counts for one time bucket
+type EventBucket struct {
+	Start  string           `json:"start"`
+	Total  int64            `json:"total"`
+	ByType map[string]int64 `json:"by_type"`
+}
+
+// eventBuckets groups filtered events into time buckets, oldest first. A
+// non-zero rollupsUntil reads events before it from event_rollups.
+func (s *TrendingService) eventBuckets(filter EventStatsFilter, rollupsUntil time.Time) ([]EventBucket, error) {
+	type bucketRow struct {
+		Bucket    string
+		EventType string
+		Count     int64
+	}
+	format := eventBucketFormats[filter.Bucket]
+
+	var rows []bucketRow
+	if !rollupsUntil.IsZero() {
+		err := filter.applyRollups(s.db.Model(&models.EventRollup{})).
+			Select(fmt.Sprintf("strftime('%s', hour) AS bucket, event_type, SUM(count) AS count", format)).
+			Group("bucket, event_type").
+			Order("bucket").
+			Scan(&rows).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to bucket rolled-up events: %w", err)
+		}
+	}
+
+	rawFilter := filter
+	if !rollupsUntil.IsZero() {
+		rawFilter.From = rollupsUntil
+	}
+	var rawRows []bucketRow
+	err := rawFilter.apply(s.db.Model(&models.UserEvent{})).
+		Select(fmt.Sprintf("strftime('%s', timestamp) AS bucket, event_type, COUNT(*) AS count", format)).
+		Group("bucket, event_type").
+		Order("bucket").
+		Scan(&rawRows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to bucket events: %w", err)
+	}
+	rows = append(rows, rawRows...)
+
+	buckets := []EventBucket{}
+	for _, row := range rows {
+		if len(buckets) == 0 || buckets[len(buckets)-1].Start != row.Bucket {
+			buckets = append(buckets, EventBucket{Start: row.Bucket, ByType: map[string]int64{}})
+		}
+		current := &buckets[len(buckets)-1]
+		current.Total += row.Count
+		current.ByType[row.EventType] += row.Count
+	}
+	return buckets, nil
+}
+
+// EngagementCounts counts an article's events by type
+type EngagementCounts struct {
+	Views  int64 `json:"views"`
+	Clicks int64 `json:"clicks"`
+	Shares int64 `json:"shares"`
+}
+
+// ArticleEngagement is an article's engagement, for popularity badges
+type ArticleEngagement struct {
+	EngagementCounts                  // All time, including rolled-up events
+	UniqueUsers      int64            `json:"unique_users"` // Over the raw events still kept
+	Last24h          EngagementCounts `json:"last_24h"`
+}
+
+// GetArticleEngagement counts an article's views, clicks and shares, all
+// time and over the last 24 hours. Rollups don't keep user IDs, so unique
+// users only cover the EVENT_RETENTION_DAYS of raw events.
+func (s *TrendingService) GetArticleEngagement(articleID string) (*ArticleEngagement, error) {
+	watermark, err := rollupWatermark(s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	engagement := &ArticleEngagement{}
+	engagement.EngagementCounts, err = s.countEventsByType(EventStatsFilter{ArticleID: articleID}, watermark)
+	if err != nil {
+		return nil, err
+	}
+	engagement.Last24h, err = s.countEventsByType(EventStatsFilter{
+		ArticleID: articleID,
+		From:      time.Now().Add(-24 * time.Hour),
+	}, watermark)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.Model(&models.UserEvent{}).
+		Where("article_id = ?", articleID).
+		Distinct("user_id").
+		Count(&engagement.UniqueUsers).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count article users: %w", err)
+	}
+	return engagement, nil
+}
+
+// countEventsByType counts filtered events by type, reading events before
+// the rollup watermark from event_rollups
+func (s *TrendingService) countEventsByType(filter EventStatsFilter, watermark time.Time) (EngagementCounts, error) {
+	rawFilter := filter
+	var typeCounts []eventTypeCount
+	if !watermark.IsZero() && (filter.From.IsZero() || filter.From.Before(watermark)) {
+		rawFilter.From = watermark
+		err := filter.applyRollups(s.db.Model(&models.EventRollup{})).
+			Select("event_type, SUM(count) AS count").
+			Group("event_type").
+			Scan(&typeCounts).Error
+		if err != nil {
+			return EngagementCounts{}, fmt.Errorf("failed to count rolled-up events: %w", err)
+		}
+	}
+
+	var rawCounts []eventTypeCount
+	err := rawFilter.apply(s.db.Model(&models.UserEvent{})).
+		Select("event_type, COUNT(*) AS count").
+		Group("event_type").
+		Scan(&rawCounts).Error
+	if err != nil {
+		return EngagementCounts{}, fmt.Errorf("failed to count events by type: %w", err)
+	}
+
+	var counts EngagementCounts
+	for _, tc := range append(typeCounts, rawCounts...) {
+		switch tc.EventType {
+		case models.EventTypeView:
+			counts.Views += tc.Count
+		case models.EventTypeClick:
+			counts.Clicks += tc.Count
+		case models.EventTypeShare:
+			counts.Shares += tc.Count
+		}
+	}
+	return counts, nil
+}
+
 // getCacheSize returns the number of cached entries
 func (s *TrendingService) getCacheSize() int {
-	count := 0
-	s.cache.Range(func(key, value interface{}) bool {
-		count++
-		return true
-	})
-	return count
+	keys, err := s.cache.Keys(context.Background(), trendingCachePrefix)
+	if err != nil {
+		log.Printf("Failed to count trending cache entries: %v", err)
+	}
+	return len(keys)
 }
@@ -0,0 +1,328 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"news-backend/cache"
+	"news-backend/config"
+	"news-backend/models"
+	"news-backend/utils"
+)
+
+func TestTrendingFallsBackWhenEngagedArticlesAreExcluded(t *testing.T) {
+	db := openTestDB(t)
+	cfg := &config.Config{TrendingTimeWindow: 24, TrendingFallbackFreshness: 1, ScoreThreshold: 0.5}
+	invalidation, err := NewInvalidationService(cfg)
+	if err != nil {
+		t.Fatalf("NewInvalidationService() error = %v", err)
+	}
+	service := NewTrendingService(cfg, nil, nil, nil, invalidation, nil, nil, nil, nil, nil)
+
+	lat, lon := 17.385, 78.4867
+	now := time.Now()
+	articles := []models.Article{
+		{ID: "excluded", Title: "Excluded", URL: "https://example.com/excluded", PublicationDate: now,
+			RelevanceScore: 0.9, Latitude: lat, Longitude: lon, ExcludeFromTrending: true},
+		{ID: "fresh", Title: "Fresh", URL: "https://example.com/fresh", PublicationDate: now,
+			RelevanceScore: 0.8, Latitude: lat, Longitude: lon},
+	}
+	if err := db.Create(&articles).Error; err != nil {
+		t.Fatalf("failed to create articles: %v", err)
+	}
+	// Only the excluded article was engaged with
+	score := models.TrendingScore{ArticleID: "excluded", Latitude: lat, Longitude: lon,
+		Events: 20, Views: 20, Users: 10, Weight: 20, ComputedAt: now}
+	if err := db.Create(&score).Error; err != nil {
+		t.Fatalf("failed to create trending score: %v", err)
+	}
+
+	trending, err := service.calculateTrendingScores(lat, lon, 10)
+	if err != nil {
+		t.Fatalf("calculateTrendingScores() error = %v", err)
+	}
+	if len(trending) != 1 || trending[0].ID != "fresh" {
+		t.Errorf("trending = %v, expected the fallback's fresh article", trendingIDs(trending))
+	}
+}
+
+func TestReplayTrendingMatchesLive(t *testing.T) {
+	db := openTestDB(t)
+	cfg := &config.Config{TrendingTimeWindow: 24, TrendingFallbackFreshness: 1, ScoreThreshold: 0.5,
+		TrendingCacheTTL: 300, MaxArticlesReturn: 10, MaxLimit: 50,
+		AggregateMinUsers: 2, AggregateNoiseEpsilon: 1, AggregateNoiseSecret: "replay"}
+	invalidation, err := NewInvalidationService(cfg)
+	if err != nil {
+		t.Fatalf("NewInvalidationService() error = %v", err)
+	}
+	store := cache.NewMemory()
+	service := NewTrendingService(cfg, nil, nil, nil, invalidation, NewGeocodingService(cfg, store), store, nil, nil, nil)
+
+	lat, lon := 17.385, 78.4867
+	now := time.Now()
+	articles := []models.Article{
+		{ID: "popular", Title: "Popular", URL: "https://example.com/popular", PublicationDate: now.Add(-48 * time.Hour),
+			RelevanceScore: 0.6, Latitude: lat, Longitude: lon},
+		{ID: "lonely", Title: "Lonely", URL: "https://example.com/lonely", PublicationDate: now.Add(-48 * time.Hour),
+			RelevanceScore: 0.9, Latitude: lat, Longitude: lon},
+		{ID: "fresh", Title: "Fresh", URL: "https://example.com/fresh", PublicationDate: now.Add(-time.Hour),
+			RelevanceScore: 0.8, Latitude: 28.6139, Longitude: 77.209},
+	}
+	if err := db.Create(&articles).Error; err != nil {
+		t.Fatalf("failed to create articles: %v", err)
+	}
+	// Three readers of one article, and one of another, which suppression
+	// must leave out of both
+	var events []models.UserEvent
+	for i, user := range []string{"u1", "u2", "u3", "u1", "u2"} {
+		events = append(events, models.UserEvent{ArticleID: "popular", UserID: user, DeviceID: user,
+			EventType: models.EventTypeView, Latitude: lat, Longitude: lon, Timestamp: now.Add(-time.Duration(i+1) * time.Hour)})
+	}
+	events = append(events, models.UserEvent{ArticleID: "lonely", UserID: "u4", DeviceID: "u4",
+		EventType: models.EventTypeShare, Latitude: lat, Longitude: lon, Timestamp: now.Add(-time.Hour)})
+	if err := db.Create(&events).Error; err != nil {
+		t.Fatalf("failed to create events: %v", err)
+	}
+	if err := service.RefreshScores(); err != nil {
+		t.Fatalf("RefreshScores() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		lat, lon float64
+		want     string
+	}{
+		{"engaged", lat, lon, "popular"},
+		{"fallback", 28.6139, 77.209, "fresh"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			live, _, err := service.GetTrendingNews(tt.lat, tt.lon, 25, 10)
+			if err != nil {
+				t.Fatalf("GetTrendingNews() error = %v", err)
+			}
+			replay, err := service.ReplayTrending(time.Now(), tt.lat, tt.lon, 25, 10)
+			if err != nil {
+				t.Fatalf("ReplayTrending() error = %v", err)
+			}
+
+			if len(live) != 1 || live[0].ID != tt.want {
+				t.Fatalf("live trending = %v, expected only %s", trendingIDs(live), tt.want)
+			}
+			if len(replay.Articles) != len(live) {
+				t.Fatalf("replay = %v, expected the live %v", trendingIDs(replay.Articles), trendingIDs(live))
+			}
+			for i, got := range replay.Articles {
+				want := live[i]
+				// Recency decays a little further between the two
+				if got.ID != want.ID || got.EventCount != want.EventCount || got.UniqueUsers != want.UniqueUsers ||
+					math.Abs(got.TrendingScore-want.TrendingScore) > 1e-3*want.TrendingScore {
+					t.Errorf("replay[%d] = %s with %d events, %d users, score %v, expected the live %s with %d, %d, %v", i,
+						got.ID, got.EventCount, got.UniqueUsers, got.TrendingScore,
+						want.ID, want.EventCount, want.UniqueUsers, want.TrendingScore)
+				}
+				for eventType, count := range want.EventBreakdown {
+					if got.EventBreakdown[eventType] != count {
+						t.Errorf("replay[%d] %s count = %d, expected the live %d", i, eventType, got.EventBreakdown[eventType], count)
+					}
+				}
+			}
+		})
+	}
+}
+
+func trendingIDs(articles []models.TrendingArticle) []string {
+	ids := make([]string, len(articles))
+	for i, article := range articles {
+		ids[i] = article.ID
+	}
+	return ids
+}
+
+// scoreRow returns the materialized score of an article in the cell of a point
+func scoreRow(t *testing.T, rows []models.TrendingScore, articleID string, lat, lon float64) models.TrendingScore {
+	t.Helper()
+	cell := utils.ToGridCell(lat, lon, trendingScoreGridPrecision)
+	for _, row := range rows {
+		if row.ArticleID == articleID && row.LatCell == cell.LatCell && row.LonCell == cell.LonCell {
+			return row
+		}
+	}
+	t.Fatalf("no trending score for %s in cell %v among %d rows", articleID, cell, len(rows))
+	return models.TrendingScore{}
+}
+
+func TestScoreEventsAggregatesPerCell(t *testing.T) {
+	service := &TrendingService{cfg: &config.Config{TrendingMaxEventsPerUser: 2}}
+	now := time.Date(2026, 3, 26, 12, 0, 0, 0, time.UTC)
+	lat, lon := 17.385, 78.4867
+	farLat := lat + 1
+
+	events := []models.UserEvent{
+		{ArticleID: "a", UserID: "u1", EventType: models.EventTypeView, Latitude: lat, Longitude: lon, Timestamp: now.Add(-12 * time.Hour)},
+		{ArticleID: "a", UserID: "u2", EventType: models.EventTypeShare, Latitude: lat + 0.01, Longitude: lon, Timestamp: now},
+		{ArticleID: "a", UserID: "u1", EventType: models.EventTypeClick, Latitude: farLat, Longitude: lon, Timestamp: now},
+		// u1's third event on the article counts, but adds no weight
+		{ArticleID: "a", UserID: "u1", EventType: models.EventTypeView, Latitude: lat, Longitude: lon, Timestamp: now},
+		{ArticleID: "b", UserID: "u1", EventType: models.EventTypeView, Latitude: lat, Longitude: lon, Timestamp: now},
+	}
+	rows := service.scoreEvents(events, now)
+	if len(rows) != 3 {
+		t.Fatalf("scoreEvents() returned %d rows, expected one per cell and article", len(rows))
+	}
+
+	tests := []struct {
+		name                         string
+		articleID                    string
+		lat                          float64
+		events, views, clicks, share int
+		users                        int
+		weight                       float64
+		meanLat                      float64
+	}{
+		// A view decayed by 12 hours, a fresh share and an uncounted view
+		{"near cell", "a", lat, 3, 2, 0, 1, 2, math.Exp(-1) + 3, lat + 0.01/3},
+		// u1 was counted where they first engaged
+		{"far cell", "a", farLat, 1, 0, 1, 0, 0, 2, farLat},
+		{"other article", "b", lat, 1, 1, 0, 0, 1, 1, lat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := scoreRow(t, rows, tt.articleID, tt.lat, lon)
+			if row.Events != tt.events || row.Views != tt.views || row.Clicks != tt.clicks || row.Shares != tt.share {
+				t.Errorf("events = %d (%d views, %d clicks, %d shares), expected %d (%d, %d, %d)",
+					row.Events, row.Views, row.Clicks, row.Shares, tt.events, tt.views, tt.clicks, tt.share)
+			}
+			if row.Users != tt.users {
+				t.Errorf("users = %d, expected %d", row.Users, tt.users)
+			}
+			if math.Abs(row.Weight-tt.weight) > 1e-9 {
+				t.Errorf("weight = %v, expected %v", row.Weight, tt.weight)
+			}
+			if math.Abs(row.Latitude-tt.meanLat) > 1e-9 || row.Longitude != lon {
+				t.Errorf("location = %v,%v, expected the events' mean %v,%v", row.Latitude, row.Longitude, tt.meanLat, lon)
+			}
+			if !row.ComputedAt.Equal(now) {
+				t.Errorf("computed at %v, expected %v", row.ComputedAt, now)
+			}
+		})
+	}
+}
+
+func TestRefreshScoresReplacesOldRows(t *testing.T) {
+	db := openTestDB(t)
+	cfg := &config.Config{TrendingTimeWindow: 24}
+	invalidation, err := NewInvalidationService(cfg)
+	if err != nil {
+		t.Fatalf("NewInvalidationService() error = %v", err)
+	}
+	service := NewTrendingService(cfg, nil, nil, nil, invalidation, nil, cache.NewMemory(), nil, nil, nil)
+
+	lat, lon := 17.385, 78.4867
+	now := time.Now()
+	stale := models.TrendingScore{ArticleID: "gone", Latitude: lat, Longitude: lon, Events: 50, Users: 10, Weight: 50, ComputedAt: now.Add(-time.Hour)}
+	if err := db.Create(&stale).Error; err != nil {
+		t.Fatalf("failed to create trending score: %v", err)
+	}
+	events := []models.UserEvent{
+		{ArticleID: "a", UserID: "u1", DeviceID: "u1", EventType: models.EventTypeShare, Latitude: lat, Longitude: lon, Timestamp: now.Add(-6 * time.Hour)},
+		// Outside the time window
+		{ArticleID: "old", UserID: "u1", DeviceID: "u1", EventType: models.EventTypeView, Latitude: lat, Longitude: lon, Timestamp: now.Add(-25 * time.Hour)},
+	}
+	if err := db.Create(&events).Error; err != nil {
+		t.Fatalf("failed to create events: %v", err)
+	}
+
+	if err := service.RefreshScores(); err != nil {
+		t.Fatalf("RefreshScores() error = %v", err)
+	}
+	var rows []models.TrendingScore
+	if err := db.Find(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || rows[0].ArticleID != "a" {
+		ids := make([]string, len(rows))
+		for i, row := range rows {
+			ids[i] = row.ArticleID
+		}
+		t.Fatalf("trending scores for %v, expected only the event in the window", ids)
+	}
+	// A share decayed by 6 hours as of the refresh
+	if want := 3 * math.Exp(-0.5); math.Abs(rows[0].Weight-want) > 1e-3 {
+		t.Errorf("weight = %v, expected %v", rows[0].Weight, want)
+	}
+
+	// Refreshing with no events left in the window empties the table
+	if err := db.Where("1 = 1").Delete(&models.UserEvent{}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := service.RefreshScores(); err != nil {
+		t.Fatalf("RefreshScores() error = %v", err)
+	}
+	var n int64
+	if err := db.Model(&models.TrendingScore{}).Count(&n).Error; err != nil || n != 0 {
+		t.Errorf("%d trending scores left (%v), expected none", n, err)
+	}
+}
+
+func TestAddEventScoreCountsBeforeRefresh(t *testing.T) {
+	db := openTestDB(t)
+	cfg := &config.Config{TrendingTimeWindow: 24, TrendingMaxEventsPerUser: 1}
+	invalidation, err := NewInvalidationService(cfg)
+	if err != nil {
+		t.Fatalf("NewInvalidationService() error = %v", err)
+	}
+	service := NewTrendingService(cfg, nil, nil, nil, invalidation, nil, cache.NewMemory(), nil, nil, nil)
+
+	lat, lon := 17.385, 78.4867
+	article := models.Article{ID: "a", Title: "A", URL: "https://example.com/a", PublicationDate: time.Now(),
+		RelevanceScore: 0.5, Latitude: lat, Longitude: lon}
+	if err := db.Create(&article).Error; err != nil {
+		t.Fatalf("failed to create article: %v", err)
+	}
+
+	record := func(user, eventType string, lat, lon float64) {
+		t.Helper()
+		event := models.UserEvent{ArticleID: "a", UserID: user, DeviceID: user, EventType: eventType,
+			Latitude: lat, Longitude: lon, Timestamp: time.Now()}
+		if err := db.Create(&event).Error; err != nil {
+			t.Fatalf("failed to create event: %v", err)
+		}
+		if err := service.addEventScore(&event); err != nil {
+			t.Fatalf("addEventScore() error = %v", err)
+		}
+	}
+	record("u1", models.EventTypeView, lat, lon)
+	record("u2", models.EventTypeShare, lat+0.01, lon)
+	// Past u1's cap: counted, but no weight or new user
+	record("u1", models.EventTypeClick, lat, lon)
+	// Another cell within the radius, and one far outside it
+	record("u3", models.EventTypeView, lat+0.1, lon)
+	record("u4", models.EventTypeView, lat+2, lon)
+
+	var rows []models.TrendingScore
+	if err := db.Find(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	row := scoreRow(t, rows, "a", lat, lon)
+	if row.Events != 3 || row.Views != 1 || row.Clicks != 1 || row.Shares != 1 || row.Users != 2 || row.Weight != 4 {
+		t.Errorf("cell score = %d events (%d/%d/%d), %d users, weight %v, expected 3 (1/1/1), 2 users, weight 4",
+			row.Events, row.Views, row.Clicks, row.Shares, row.Users, row.Weight)
+	}
+	if want := lat + 0.01/3; math.Abs(row.Latitude-want) > 1e-9 {
+		t.Errorf("cell latitude = %v, expected the events' mean %v", row.Latitude, want)
+	}
+
+	// Trending sums the cells within the radius
+	trending, err := service.calculateTrendingScores(lat, lon, 25)
+	if err != nil {
+		t.Fatalf("calculateTrendingScores() error = %v", err)
+	}
+	if len(trending) != 1 || trending[0].EventCount != 4 || trending[0].UniqueUsers != 3 {
+		t.Fatalf("trending = %+v, expected article a with the 4 events of 3 users nearby", trending)
+	}
+	if breakdown := trending[0].EventBreakdown; breakdown[models.EventTypeView] != 2 || breakdown[models.EventTypeShare] != 1 {
+		t.Errorf("breakdown = %v, expected 2 views, 1 click and 1 share", breakdown)
+	}
+}
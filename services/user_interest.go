@@ -0,0 +1,117 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"news-backend/models"
+	"news-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// interestMinWeight is the decayed event weight below which an interest
+// vector is too stale to rank with
+const interestMinWeight = 0.1
+
+// RecordInterest folds the embedding of an article a canonical user engaged
+// with into their interest vector, weighted by the event's weight, after
+// decaying what is already there by USER_INTEREST_HALF_LIFE_HOURS. Articles
+// not embedded with EMBEDDING_MODEL yet are skipped; an interest vector from
+// another model is replaced.
+func (s *EmbeddingService) RecordInterest(userID, articleID, eventType string, at time.Time) error {
+	if !s.Enabled() {
+		return nil
+	}
+
+	var embedding models.ArticleEmbedding
+	err := s.currentEmbeddings().Where("article_id = ?", articleID).First(&embedding).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load article embedding: %w", err)
+	}
+	vector := embedding.GetVector()
+
+	// Events of one user arrive close together; updates must not interleave
+	s.interestMu.Lock()
+	defer s.interestMu.Unlock()
+
+	interest := models.UserInterest{UserID: userID}
+	err = s.db.Where("user_id = ?", userID).First(&interest).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to load user interest: %w", err)
+	}
+
+	var current []float32
+	total := 0.0
+	if interest.Model == embedding.Model {
+		current = interest.GetVector()
+		total = interest.Weight * utils.HalfLifeDecay(at.Sub(interest.UpdatedAt).Hours(), s.cfg.UserInterestHalfLifeHours)
+	} else {
+		interest.Events = 0
+	}
+	blended, weight := utils.BlendVector(current, total, vector, models.GetEventWeight(eventType))
+
+	interest.Model = embedding.Model
+	interest.SetVector(blended)
+	interest.Weight = weight
+	interest.Events++
+	interest.UpdatedAt = at
+	if err := s.db.Save(&interest).Error; err != nil {
+		return fmt.Errorf("failed to store user interest: %w", err)
+	}
+	return nil
+}
+
+// InterestScores returns the cosine similarity between a canonical user's
+// interest vector and each of the given articles that has a stored
+// embedding. It is empty for users without a recent enough interest vector
+// from EMBEDDING_MODEL.
+func (s *EmbeddingService) InterestScores(userID string, articleIDs []string) (map[string]float64, error) {
+	if !s.Enabled() {
+		return nil, ErrEmbeddingsDisabled
+	}
+
+	var interest models.UserInterest
+	err := s.db.Where("user_id = ?", userID).First(&interest).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user interest: %w", err)
+	}
+	if interest.Model != s.cfg.EmbeddingModel {
+		return nil, nil
+	}
+	decayed := interest.Weight * utils.HalfLifeDecay(time.Since(interest.UpdatedAt).Hours(), s.cfg.UserInterestHalfLifeHours)
+	if decayed < interestMinWeight {
+		return nil, nil
+	}
+	vector := interest.GetVector()
+
+	if index := s.loadedIndex(); index != nil {
+		scores := make(map[string]float64, len(articleIDs))
+		for _, id := range articleIDs {
+			if score, ok := index.Similarity(vector, id); ok {
+				scores[id] = score
+			}
+		}
+		return scores, nil
+	}
+
+	scores := make(map[string]float64, len(articleIDs))
+	for start := 0; start < len(articleIDs); start += embeddingBatchSize {
+		end := min(start+embeddingBatchSize, len(articleIDs))
+		var embeddings []models.ArticleEmbedding
+		if err := s.currentEmbeddings().Where("article_id IN ?", articleIDs[start:end]).Find(&embeddings).Error; err != nil {
+			return nil, fmt.Errorf("failed to load embeddings: %w", err)
+		}
+		for i := range embeddings {
+			scores[embeddings[i].ArticleID] = utils.CosineSimilarity(vector, embeddings[i].GetVector())
+		}
+	}
+	return scores, nil
+}
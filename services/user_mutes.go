@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"news-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidMute is returned for unknown mute kinds, bad values, or users
+// with too many mutes
+var ErrInvalidMute = errors.New("invalid mute")
+
+// Mute limits
+const (
+	maxMuteValueLength = 100 // bytes
+	maxUserMutes       = 200
+)
+
+type mutesKey struct{}
+
+// MuteSet is everything a user muted, lowercased
+type MuteSet struct {
+	Sources    []string
+	Categories []string
+	Keywords   []string
+}
+
+// Empty reports whether nothing is muted
+func (m *MuteSet) Empty() bool {
+	return m == nil || len(m.Sources)+len(m.Categories)+len(m.Keywords) == 0
+}
+
+// Matches reports whether an article is muted
+func (m *MuteSet) Matches(article *models.Article) bool {
+	if m.Empty() {
+		return false
+	}
+	source := strings.ToLower(article.SourceName)
+	for _, muted := range m.Sources {
+		if source == muted {
+			return true
+		}
+	}
+	for _, category := range models.SplitCategories(article.Category) {
+		category = strings.ToLower(category)
+		for _, muted := range m.Categories {
+			if category == muted {
+				return true
+			}
+		}
+	}
+	text := strings.ToLower(article.Title + "\n" + article.Description)
+	for _, keyword := range m.Keywords {
+		if strings.Contains(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the articles that aren't muted
+func (m *MuteSet) Filter(articles []models.Article) []models.Article {
+	if m.Empty() {
+		return articles
+	}
+	kept := make([]models.Article, 0, len(articles))
+	for i := range articles {
+		if !m.Matches(&articles[i]) {
+			kept = append(kept, articles[i])
+		}
+	}
+	return kept
+}
+
+// apply excludes muted articles from an article query
+func (m *MuteSet) apply(db, query *gorm.DB) *gorm.DB {
+	if m.Empty() {
+		return query
+	}
+	if len(m.Sources) > 0 {
+		query = query.Where("LOWER(source_name) NOT IN ?", m.Sources)
+	}
+	if len(m.Categories) > 0 {
+		query = query.Where("id NOT IN (?)", db.Table("article_categories").
+			Select("article_categories.article_id").
+			Joins("JOIN categories ON categories.id = article_categories.category_id").
+			Where("LOWER(categories.name) IN ?", m.Categories))
+	}
+	for _, keyword := range m.Keywords {
+		pattern := "%" + escapeLike(keyword) + "%"
+		query = query.Where(`LOWER(title) NOT LIKE ? ESCAPE '\' AND LOWER(description) NOT LIKE ? ESCAPE '\'`, pattern, pattern)
+	}
+	return query
+}
+
+// escapeLike makes LIKE's wildcards in s match literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// WithMutes hides what mutes holds from the articles fetched for ctx
+func WithMutes(ctx context.Context, mutes *MuteSet) context.Context {
+	return context.WithValue(ctx, mutesKey{}, mutes)
+}
+
+// Mutes returns what is muted for ctx, nil when nothing is
+func Mutes(ctx context.Context) *MuteSet {
+	mutes, _ := ctx.Value(mutesKey{}).(*MuteSet)
+	return mutes
+}
+
+// ListMutes returns the canonical user's mutes, oldest first
+func (s *UserService) ListMutes(userID string) ([]models.UserMute, error) {
+	mutes := []models.UserMute{}
+	if err := s.db.Where("user_id = ?", s.ResolveUserID(userID)).Order("id").Find(&mutes).Error; err != nil {
+		return nil, fmt.Errorf("failed to load mutes: %w", err)
+	}
+	return mutes, nil
+}
+
+// MuteSetFor returns everything the canonical user muted
+func (s *UserService) MuteSetFor(userID string) (*MuteSet, error) {
+	mutes, err := s.ListMutes(userID)
+	if err != nil {
+		return nil, err
+	}
+	set := &MuteSet{}
+	for _, mute := range mutes {
+		switch mute.Kind {
+		case models.MuteKindSource:
+			set.Sources = append(set.Sources, mute.Value)
+		case models.MuteKindCategory:
+			set.Categories = append(set.Categories, mute.Value)
+		case models.MuteKindKeyword:
+			set.Keywords = append(set.Keywords, mute.Value)
+		}
+	}
+	return set, nil
+}
+
+// AddMute mutes a source, category or keyword for the canonical user.
+// Muting something already muted returns the existing mute.
+func (s *UserService) AddMute(userID, kind, value string) (*models.UserMute, error) {
+	if !models.IsValidMuteKind(kind) {
+		return nil, fmt.Errorf("%w: unknown kind %q (expected source, category or keyword)", ErrInvalidMute, kind)
+	}
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" || len(value) > maxMuteValueLength {
+		return nil, fmt.Errorf("%w: value must be 1 to %d bytes", ErrInvalidMute, maxMuteValueLength)
+	}
+
+	canonicalID := s.ResolveUserID(userID)
+	var count int64
+	if err := s.db.Model(&models.UserMute{}).Where("user_id = ?", canonicalID).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to count mutes: %w", err)
+	}
+	if count >= maxUserMutes {
+		return nil, fmt.Errorf("%w: at most %d mutes per user", ErrInvalidMute, maxUserMutes)
+	}
+
+	mute := models.UserMute{UserID: canonicalID, Kind: kind, Value: value}
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&mute).Error; err != nil {
+		return nil, fmt.Errorf("failed to store mute: %w", err)
+	}
+	if mute.ID == 0 {
+		if err := s.db.Where(&models.UserMute{UserID: canonicalID, Kind: kind, Value: value}).First(&mute).Error; err != nil {
+			return nil, fmt.Errorf("failed to load mute: %w", err)
+		}
+	}
+	return &mute, nil
+}
+
+// RemoveMute unmutes one of the canonical user's mutes
+func (s *UserService) RemoveMute(userID string, muteID uint) error {
+	result := s.db.Where("id = ? AND user_id = ?", muteID, s.ResolveUserID(userID)).Delete(&models.UserMute{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove mute: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
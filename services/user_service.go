@@ -0,0 +1,665 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"news-backend/config"
+	"news-backend/database"
+	"news-backend/models"
+	"news-backend/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidLink is returned for self-links or links that would create a cycle
+var ErrInvalidLink = errors.New("invalid user link")
+
+// ErrInvalidLinkToken is returned when a link token is unknown, expired,
+// already used or issued to another identifier
+var ErrInvalidLinkToken = errors.New("invalid or expired link token")
+
+// ErrDeviceRegistered is returned when registering an identifier that
+// already has a device secret
+var ErrDeviceRegistered = errors.New("device already registered")
+
+// ErrInvalidDeviceSecret is returned when an identifier has no device
+// secret or another one was presented
+var ErrInvalidDeviceSecret = errors.New("invalid device secret")
+
+// ErrInvalidPreference is returned for unknown preference values
+var ErrInvalidPreference = errors.New("invalid user preference")
+
+// ErrInvalidSignal is returned for unknown feed signals
+var ErrInvalidSignal = errors.New("invalid feed signal")
+
+// Heuristic link suggestion parameters
+const (
+	suggestionLookbackDays = 30
+	suggestionMinScore     = 0.5
+	suggestionMaxResults   = 10
+	suggestionNearbyKm     = 5.0
+	affinityLookbackDays   = 30
+	// feedSignalStep is how far one "more/less like this" moves the article's
+	// categories and source, out of a full-scale adjustment of 1
+	feedSignalStep = 0.25
+	// followedTopicBoost is added to the personal score of articles tagged
+	// with a topic the user follows
+	followedTopicBoost = 0.5
+	// linkTokenTTL is how long a link token can be redeemed
+	linkTokenTTL = 10 * time.Minute
+	// Resolved canonical IDs are kept for the most recently seen identifiers
+	canonicalCacheSize = 10000
+	canonicalCacheTTL  = 10 * time.Minute
+)
+
+// UserService resolves device/user identifiers to canonical users
+type UserService struct {
+	db           *gorm.DB
+	cfg          *config.Config
+	canonical    *utils.LRU[string, string] // linked user ID -> canonical user ID
+	invalidation *InvalidationService
+}
+
+// NewUserService creates a new user service instance
+func NewUserService(cfg *config.Config, invalidation *InvalidationService) *UserService {
+	s := &UserService{
+		db:           database.GetDB(),
+		cfg:          cfg,
+		canonical:    utils.NewLRU[string, string](canonicalCacheSize, canonicalCacheTTL),
+		invalidation: invalidation,
+	}
+	invalidation.Subscribe(InvalidationUserLinks, s.clearCache)
+	return s
+}
+
+// ResolveUserID returns the canonical user for an identifier (itself if unlinked)
+func (s *UserService) ResolveUserID(userID string) string {
+	if cached, ok := s.canonical.Get(userID); ok {
+		return cached
+	}
+
+	canonical := userID
+	var link models.UserLink
+	if err := s.db.Where("linked_user_id = ?", userID).First(&link).Error; err == nil {
+		canonical = link.CanonicalUserID
+	}
+
+	s.canonical.Add(userID, canonical)
+	return canonical
+}
+
+// RegisterDevice issues userID its device secret, which is returned only
+// this once. The first caller to register an identifier holds it; later
+// registrations get ErrDeviceRegistered.
+func (s *UserService) RegisterDevice(userID string) (*models.DeviceSecretResponse, error) {
+	secret, err := randomToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate device secret: %w", err)
+	}
+	result := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.DeviceSecret{
+		UserID:     userID,
+		SecretHash: hashToken(secret),
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to store device secret: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrDeviceRegistered
+	}
+	return &models.DeviceSecretResponse{UserID: userID, DeviceSecret: secret}, nil
+}
+
+// verifyDevice checks that secret is the device secret userID registered
+func (s *UserService) verifyDevice(userID, secret string) error {
+	var device models.DeviceSecret
+	err := s.db.Where("user_id = ?", userID).First(&device).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrInvalidDeviceSecret
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load device secret: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(device.SecretHash), []byte(hashToken(secret))) != 1 {
+		return ErrInvalidDeviceSecret
+	}
+	return nil
+}
+
+// IssueLinkToken creates a single-use token for userID, valid for
+// linkTokenTTL, once the caller proves it holds the device with its device
+// secret. The device hands the token to the one it is being linked to,
+// which proves the link is wanted by both.
+func (s *UserService) IssueLinkToken(userID, deviceSecret string) (*models.LinkTokenResponse, error) {
+	if err := s.verifyDevice(userID, deviceSecret); err != nil {
+		return nil, err
+	}
+	token, err := randomToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate link token: %w", err)
+	}
+	now := time.Now()
+	record := models.UserLinkToken{
+		TokenHash: hashToken(token),
+		UserID:    userID,
+		ExpiresAt: now.Add(linkTokenTTL),
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at < ?", now).Delete(&models.UserLinkToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&record).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store link token: %w", err)
+	}
+	return &models.LinkTokenResponse{UserID: userID, Token: token, ExpiresAt: record.ExpiresAt}, nil
+}
+
+// randomToken returns 32 random bytes, base64url-encoded
+func randomToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// hashToken is how device secrets and link tokens are stored
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// LinkUsers links linkedID to the canonical user behind canonicalID,
+// redeeming token, which must have been issued to linkedID. Existing events
+// and links of linkedID are moved to the canonical user.
+func (s *UserService) LinkUsers(canonicalID, linkedID, token, method string) (*models.UserLink, error) {
+	canonicalID = s.ResolveUserID(canonicalID)
+	if canonicalID == linkedID || s.ResolveUserID(linkedID) == canonicalID {
+		return nil, fmt.Errorf("%w: %s already resolves to %s", ErrInvalidLink, linkedID, canonicalID)
+	}
+
+	link := models.UserLink{
+		CanonicalUserID: canonicalID,
+		LinkedUserID:    linkedID,
+		Method:          method,
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		redeemed := tx.Where("token_hash = ? AND user_id = ? AND expires_at >= ?", hashToken(token), linkedID, time.Now()).
+			Delete(&models.UserLinkToken{})
+		if redeemed.Error != nil {
+			return redeemed.Error
+		}
+		if redeemed.RowsAffected == 0 {
+			return ErrInvalidLinkToken
+		}
+		// Drop any previous link for this identifier
+		if err := tx.Where("linked_user_id = ?", linkedID).Delete(&models.UserLink{}).Error; err != nil {
+			return err
+		}
+		// Identifiers that pointed at linkedID now point at the new canonical user
+		if err := tx.Model(&models.UserLink{}).
+			Where("canonical_user_id = ?", linkedID).
+			Update("canonical_user_id", canonicalID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.UserEvent{}).
+			Where("user_id = ?", linkedID).
+			Update("user_id", canonicalID).Error; err != nil {
+			return err
+		}
+		return tx.Create(&link).Error
+	})
+	if errors.Is(err, ErrInvalidLinkToken) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to link users: %w", err)
+	}
+
+	s.resetCache("")
+	log.Printf("Linked user %s to canonical user %s (%s)", linkedID, canonicalID, method)
+	return &link, nil
+}
+
+// UnlinkUser removes a link and restores the identifier's events to itself
+func (s *UserService) UnlinkUser(canonicalID, linkedID string) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("canonical_user_id = ? AND linked_user_id = ?", canonicalID, linkedID).
+			Delete(&models.UserLink{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Model(&models.UserEvent{}).
+			Where("device_id = ?", linkedID).
+			Update("user_id", linkedID).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	s.resetCache(linkedID)
+	return nil
+}
+
+// GetLinks returns every identifier linked to the user's canonical identity
+func (s *UserService) GetLinks(userID string) (string, []models.UserLink, error) {
+	canonicalID := s.ResolveUserID(userID)
+
+	var links []models.UserLink
+	err := s.db.Where("canonical_user_id = ?", canonicalID).Order("created_at").Find(&links).Error
+	return canonicalID, links, err
+}
+
+// SuggestLinks scores identifiers likely belonging to the same person, based
+// on overlapping reading history and nearby event locations, without naming
+// them
+func (s *UserService) SuggestLinks(userID string) ([]models.UserLinkSuggestion, error) {
+	canonicalID := s.ResolveUserID(userID)
+	since := time.Now().UTC().AddDate(0, 0, -suggestionLookbackDays)
+
+	history, err := s.userHistory([]string{canonicalID}, since)
+	if err != nil {
+		return nil, err
+	}
+	own, ok := history[canonicalID]
+	if !ok {
+		return []models.UserLinkSuggestion{}, nil
+	}
+
+	// Candidates are users who engaged with the same articles
+	var candidates []string
+	err = s.db.Model(&models.UserEvent{}).
+		Where("article_id IN ? AND user_id <> ? AND timestamp >= ?", own.articles, canonicalID, since).
+		Distinct("user_id").
+		Pluck("user_id", &candidates).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find candidate users: %w", err)
+	}
+
+	candidateHistory, err := s.userHistory(candidates, since)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := []models.UserLinkSuggestion{}
+	for _, other := range candidateHistory {
+		overlap := utils.JaccardSimilarity(own.articles, other.articles)
+		dist := utils.HaversineDistance(own.lat, own.lon, other.lat, other.lon)
+		proximity := 1 - dist/suggestionNearbyKm
+		if proximity < 0 {
+			proximity = 0
+		}
+
+		score := overlap*0.6 + proximity*0.4
+		if score < suggestionMinScore {
+			continue
+		}
+
+		suggestions = append(suggestions, models.UserLinkSuggestion{Score: score})
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		return suggestions[i].Score > suggestions[j].Score
+	})
+	if len(suggestions) > suggestionMaxResults {
+		suggestions = suggestions[:suggestionMaxResults]
+	}
+	return suggestions, nil
+}
+
+// userActivity summarizes a user's recent events
+type userActivity struct {
+	articles []string
+	lat, lon float64 // Mean event location
+}
+
+// userHistory loads recent article IDs and mean location for each user
+func (s *UserService) userHistory(userIDs []string, since time.Time) (map[string]*userActivity, error) {
+	if len(userIDs) == 0 {
+		return map[string]*userActivity{}, nil
+	}
+
+	var events []models.UserEvent
+	err := s.db.Select("user_id", "article_id", "latitude", "longitude").
+		Where("user_id IN ? AND timestamp >= ?", userIDs, since).
+		Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user events: %w", err)
+	}
+
+	history := make(map[string]*userActivity)
+	counts := make(map[string]int)
+	seen := make(map[string]map[string]bool)
+	for _, event := range events {
+		activity, ok := history[event.UserID]
+		if !ok {
+			activity = &userActivity{}
+			history[event.UserID] = activity
+			seen[event.UserID] = make(map[string]bool)
+		}
+		if !seen[event.UserID][event.ArticleID] {
+			seen[event.UserID][event.ArticleID] = true
+			activity.articles = append(activity.articles, event.ArticleID)
+		}
+		activity.lat += event.Latitude
+		activity.lon += event.Longitude
+		counts[event.UserID]++
+	}
+
+	for userID, activity := range history {
+		activity.lat /= float64(counts[userID])
+		activity.lon /= float64(counts[userID])
+	}
+	return history, nil
+}
+
+// UserAffinity is a user's share of recent engagement per category and
+// source, weighted by event type, nudged by "more/less like this" signals
+type UserAffinity struct {
+	Categories             map[string]float64 // lowercased category -> share
+	Sources                map[string]float64 // source name -> share
+	CategoryAdjustments    map[string]float64 // lowercased category -> feed signal nudge in [-1, 1]
+	SourceAdjustments      map[string]float64 // source name -> feed signal nudge in [-1, 1]
+	FollowedTopics         int64              // number of topics the user follows
+	TopicArticles          map[string]bool    // IDs of ranked articles with a followed topic, see MatchFollowedTopics
+	maxCategory, maxSource float64
+}
+
+// Affinity summarizes what the (canonical) user engaged with recently and
+// the feed signals they gave. A user without either gets an empty affinity
+// that scores every article 0.
+func (s *UserService) Affinity(userID string) (*UserAffinity, error) {
+	canonicalID := s.ResolveUserID(userID)
+	since := time.Now().AddDate(0, 0, -affinityLookbackDays)
+
+	var events []models.UserEvent
+	err := s.db.Select("article_id", "event_type").
+		Where("user_id = ? AND timestamp >= ?", canonicalID, since.UTC()).
+		Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user events: %w", err)
+	}
+
+	affinity := &UserAffinity{
+		Categories:          make(map[string]float64),
+		Sources:             make(map[string]float64),
+		CategoryAdjustments: make(map[string]float64),
+		SourceAdjustments:   make(map[string]float64),
+	}
+	if err := s.applyFeedSignals(affinity, canonicalID, since); err != nil {
+		return nil, err
+	}
+	if err := s.db.Model(&models.TopicFollow{}).Where("user_id = ?", canonicalID).Count(&affinity.FollowedTopics).Error; err != nil {
+		return nil, fmt.Errorf("failed to count followed topics: %w", err)
+	}
+	if len(events) == 0 {
+		return affinity, nil
+	}
+
+	weights := make(map[string]float64)
+	for _, event := range events {
+		weights[event.ArticleID] += models.GetEventWeight(event.EventType)
+	}
+	ids := make([]string, 0, len(weights))
+	for id := range weights {
+		ids = append(ids, id)
+	}
+
+	var articles []models.Article
+	if err := s.db.Select("id", "category", "source_name").Where("id IN ?", ids).Find(&articles).Error; err != nil {
+		return nil, fmt.Errorf("failed to load engaged articles: %w", err)
+	}
+
+	total := 0.0
+	for _, article := range articles {
+		weight := weights[article.ID]
+		total += weight
+		affinity.Sources[article.SourceName] += weight
+		for _, category := range models.SplitCategories(article.Category) {
+			affinity.Categories[strings.ToLower(category)] += weight
+		}
+	}
+	for category, weight := range affinity.Categories {
+		affinity.Categories[category] = weight / total
+		affinity.maxCategory = math.Max(affinity.maxCategory, weight/total)
+	}
+	for source, weight := range affinity.Sources {
+		affinity.Sources[source] = weight / total
+		affinity.maxSource = math.Max(affinity.maxSource, weight/total)
+	}
+	return affinity, nil
+}
+
+// applyFeedSignals adds the user's recent feed signals to the category and
+// source adjustments, each capped to [-1, 1]
+func (s *UserService) applyFeedSignals(affinity *UserAffinity, canonicalID string, since time.Time) error {
+	var signals []models.FeedSignal
+	err := s.db.Select("article_id", "signal").
+		Where("user_id = ? AND updated_at >= ?", canonicalID, since).
+		Find(&signals).Error
+	if err != nil {
+		return fmt.Errorf("failed to load feed signals: %w", err)
+	}
+	if len(signals) == 0 {
+		return nil
+	}
+
+	steps := make(map[string]float64, len(signals))
+	ids := make([]string, len(signals))
+	for i, signal := range signals {
+		ids[i] = signal.ArticleID
+		steps[signal.ArticleID] = feedSignalStep
+		if signal.Signal == models.FeedSignalLess {
+			steps[signal.ArticleID] = -feedSignalStep
+		}
+	}
+
+	var articles []models.Article
+	if err := s.db.Select("id", "category", "source_name").Where("id IN ?", ids).Find(&articles).Error; err != nil {
+		return fmt.Errorf("failed to load signalled articles: %w", err)
+	}
+	for _, article := range articles {
+		step := steps[article.ID]
+		affinity.SourceAdjustments[article.SourceName] = clampUnit(affinity.SourceAdjustments[article.SourceName] + step)
+		for _, category := range models.SplitCategories(article.Category) {
+			name := strings.ToLower(category)
+			affinity.CategoryAdjustments[name] = clampUnit(affinity.CategoryAdjustments[name] + step)
+		}
+	}
+	return nil
+}
+
+// MatchFollowedTopics notes which of the articles are tagged with a topic
+// the (canonical) user follows, so Score boosts them
+func (s *UserService) MatchFollowedTopics(affinity *UserAffinity, userID string, articles []models.Article) error {
+	affinity.TopicArticles = make(map[string]bool)
+	if affinity.FollowedTopics == 0 {
+		return nil
+	}
+
+	canonicalID := s.ResolveUserID(userID)
+	for start := 0; start < len(articles); start += 500 {
+		end := min(start+500, len(articles))
+		ids := make([]string, 0, end-start)
+		for _, article := range articles[start:end] {
+			ids = append(ids, article.ID)
+		}
+		var matched []string
+		err := s.db.Table("article_topics").
+			Distinct("article_topics.article_id").
+			Joins("JOIN topic_follows ON topic_follows.topic_id = article_topics.topic_id").
+			Where("topic_follows.user_id = ? AND article_topics.article_id IN ?", canonicalID, ids).
+			Pluck("article_topics.article_id", &matched).Error
+		if err != nil {
+			return fmt.Errorf("failed to match followed topics: %w", err)
+		}
+		for _, id := range matched {
+			affinity.TopicArticles[id] = true
+		}
+	}
+	return nil
+}
+
+// clampUnit limits v to [-1, 1]
+func clampUnit(v float64) float64 {
+	return math.Max(-1, math.Min(1, v))
+}
+
+// Personalized reports whether the affinity has any engagement, feed
+// signals or followed topics
+func (a *UserAffinity) Personalized() bool {
+	return len(a.Categories)+len(a.Sources)+len(a.CategoryAdjustments)+len(a.SourceAdjustments) > 0 ||
+		a.FollowedTopics > 0
+}
+
+// Score rates an article in [-1, 1]: the mean of its best category share and
+// its source share, each relative to the user's favourite, shifted by half
+// the feed signal adjustments of its categories and source, plus
+// followedTopicBoost when it has a followed topic. "Less like this" can
+// push an article below ones the user has shown no interest in.
+func (a *UserAffinity) Score(article *models.Article) float64 {
+	category, categoryAdjustment := 0.0, 0.0
+	for _, name := range models.SplitCategories(article.Category) {
+		name = strings.ToLower(name)
+		if a.maxCategory > 0 {
+			category = math.Max(category, a.Categories[name]/a.maxCategory)
+		}
+		categoryAdjustment += a.CategoryAdjustments[name]
+	}
+	source := 0.0
+	if a.maxSource > 0 {
+		source = a.Sources[article.SourceName] / a.maxSource
+	}
+	adjustment := (clampUnit(categoryAdjustment) + a.SourceAdjustments[article.SourceName]) / 2
+	if a.TopicArticles[article.ID] {
+		adjustment += followedTopicBoost
+	}
+	return clampUnit((category+source)/2 + adjustment)
+}
+
+// RecordFeedSignal stores a "more/less like this" reaction for the canonical
+// user, replacing any earlier signal on the same article. It shifts the
+// personal ranking signal from the next request on.
+func (s *UserService) RecordFeedSignal(userID, articleID, signal string) (*models.FeedSignal, error) {
+	if !models.IsValidFeedSignal(signal) {
+		return nil, fmt.Errorf("%w: unknown signal %q", ErrInvalidSignal, signal)
+	}
+
+	var article models.Article
+	if err := s.db.Select("id").Where("id = ?", articleID).First(&article).Error; err != nil {
+		return nil, err
+	}
+
+	feedSignal := models.FeedSignal{UserID: s.ResolveUserID(userID), ArticleID: articleID, Signal: signal}
+	err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "article_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"signal", "updated_at"}),
+	}).Create(&feedSignal).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to store feed signal: %w", err)
+	}
+	return &feedSignal, nil
+}
+
+// ClearFeedSignals drops all of the canonical user's feed signals
+func (s *UserService) ClearFeedSignals(userID string) (int64, error) {
+	result := s.db.Where("user_id = ?", s.ResolveUserID(userID)).Delete(&models.FeedSignal{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to clear feed signals: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// GetPreferences returns the canonical user's preferences, with defaults for
+// a user who has set none
+func (s *UserService) GetPreferences(userID string) (*models.UserPreference, error) {
+	canonicalID := s.ResolveUserID(userID)
+	prefs := models.UserPreference{UserID: canonicalID}
+	err := s.db.Where("user_id = ?", canonicalID).First(&prefs).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		prefs.SummaryTone = models.SummaryToneNeutral
+		return &prefs, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load preferences: %w", err)
+	}
+	return &prefs, nil
+}
+
+// SetPreferences stores the canonical user's preferences; nil values keep
+// their current setting. Opting out of save_places deletes the user's saved
+// places.
+func (s *UserService) SetPreferences(userID string, summaryTone *string, savePlaces *bool) (*models.UserPreference, error) {
+	if summaryTone != nil && !models.IsValidSummaryTone(*summaryTone) {
+		return nil, fmt.Errorf("%w: unknown summary tone %q", ErrInvalidPreference, *summaryTone)
+	}
+
+	prefs, err := s.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+	if summaryTone != nil {
+		prefs.SummaryTone = *summaryTone
+	}
+	if savePlaces != nil {
+		prefs.SavePlaces = *savePlaces
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(prefs).Error; err != nil {
+			return err
+		}
+		if !prefs.SavePlaces {
+			return tx.Where("user_id = ?", prefs.UserID).Delete(&models.SavedPlace{}).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+// PreferredSummaryTone returns the user's summary tone, neutral when unset or
+// unavailable
+func (s *UserService) PreferredSummaryTone(userID string) string {
+	prefs, err := s.GetPreferences(userID)
+	if err != nil {
+		log.Printf("Failed to load summary tone for user %s: %v", userID, err)
+		return models.SummaryToneNeutral
+	}
+	return prefs.SummaryTone
+}
+
+// resetCache clears the identifier's resolved canonical ID, or all of them
+// when userID is empty, on every instance after links change
+func (s *UserService) resetCache(userID string) {
+	s.clearCache(userID)
+	s.invalidation.Publish(InvalidationUserLinks, userID)
+}
+
+// clearCache drops this instance's resolved canonical ID for userID, or all
+// of them when it is empty
+func (s *UserService) clearCache(userID string) {
+	if userID == "" {
+		s.canonical.Purge()
+	} else {
+		s.canonical.Remove(userID)
+	}
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"news-backend/config"
+	"news-backend/models"
+)
+
+func newTestUserService(t *testing.T) *UserService {
+	t.Helper()
+	cfg := &config.Config{}
+	invalidation, err := NewInvalidationService(cfg)
+	if err != nil {
+		t.Fatalf("NewInvalidationService() error = %v", err)
+	}
+	return NewUserService(cfg, invalidation)
+}
+
+func TestLinkTokensRequireDeviceSecret(t *testing.T) {
+	openTestDB(t)
+	service := newTestUserService(t)
+
+	if _, err := service.IssueLinkToken("phone", "guess"); !errors.Is(err, ErrInvalidDeviceSecret) {
+		t.Errorf("IssueLinkToken() for an unregistered device error = %v, expected ErrInvalidDeviceSecret", err)
+	}
+
+	device, err := service.RegisterDevice("phone")
+	if err != nil {
+		t.Fatalf("RegisterDevice() error = %v", err)
+	}
+	if _, err := service.RegisterDevice("phone"); !errors.Is(err, ErrDeviceRegistered) {
+		t.Errorf("RegisterDevice() again error = %v, expected ErrDeviceRegistered", err)
+	}
+	if _, err := service.IssueLinkToken("phone", "guess"); !errors.Is(err, ErrInvalidDeviceSecret) {
+		t.Errorf("IssueLinkToken() with a wrong secret error = %v, expected ErrInvalidDeviceSecret", err)
+	}
+
+	token, err := service.IssueLinkToken("phone", device.DeviceSecret)
+	if err != nil {
+		t.Fatalf("IssueLinkToken() error = %v", err)
+	}
+	if _, err := service.LinkUsers("laptop", "phone", token.Token, models.LinkMethodExplicit); err != nil {
+		t.Fatalf("LinkUsers() error = %v", err)
+	}
+	if canonical := service.ResolveUserID("phone"); canonical != "laptop" {
+		t.Errorf("ResolveUserID(phone) = %q, expected laptop", canonical)
+	}
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"news-backend/config"
+	"news-backend/models"
+)
+
+// Webhook event types
+const (
+	WebhookArticleUpdated = "article.updated"
+	WebhookSLOBurnRate    = "slo.burn_rate"
+	WebhookKeywordAlert   = "keyword_alert.matched"
+)
+
+// WebhookEvent is the payload delivered to webhook subscribers
+type WebhookEvent struct {
+	Event     string      `json:"event"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// articleUpdatedData is the article.updated payload. It carries the
+// article's licensing so subscribers that republish it can comply.
+func articleUpdatedData(article models.Article) map[string]interface{} {
+	return map[string]interface{}{
+		"id":            article.ID,
+		"title":         article.Title,
+		"url":           article.URL,
+		"source_name":   article.SourceName,
+		"publisher":     article.Publisher,
+		"license":       article.License,
+		"attribution":   article.Attribution,
+		"ingest_source": article.IngestSource,
+	}
+}
+
+// WebhookService delivers event notifications to configured URLs
+type WebhookService struct {
+	cfg    *config.Config
+	urls   []string
+	client *http.Client
+
+	// pending tracks in-flight deliveries so shutdown can wait for them
+	pending sync.WaitGroup
+}
+
+// NewWebhookService creates a new webhook service instance
+func NewWebhookService(cfg *config.Config) *WebhookService {
+	var urls []string
+	for _, url := range strings.Split(cfg.WebhookURLs, ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			urls = append(urls, url)
+		}
+	}
+
+	return &WebhookService{
+		cfg:    cfg,
+		urls:   urls,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Emit sends an event to every configured URL asynchronously
+func (s *WebhookService) Emit(event string, data interface{}) {
+	if len(s.urls) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(WebhookEvent{
+		Event:     event,
+		Timestamp: time.Now(),
+		Data:      data,
+	})
+	if err != nil {
+		log.Printf("Failed to encode webhook event %s: %v", event, err)
+		return
+	}
+
+	for _, url := range s.urls {
+		s.pending.Add(1)
+		go func(url string) {
+			defer s.pending.Done()
+			s.deliver(url, event, body)
+		}(url)
+	}
+}
+
+// Wait blocks until in-flight deliveries finish or ctx is done
+func (s *WebhookService) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// deliver posts a single event, signing the body when a secret is configured
+func (s *WebhookService) deliver(url, event string, body []byte) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		log.Printf("Failed to build webhook request for %s: %v", url, err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", event)
+
+	if s.cfg.WebhookSecret != "" {
+		mac := hmac.New(sha256.New, []byte(s.cfg.WebhookSecret))
+		mac.Write(body)
+		req.Header.Set("X-Webhook-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		log.Printf("Webhook delivery to %s failed: %v", url, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		log.Printf("Webhook delivery to %s returned status %d", url, resp.StatusCode)
+	}
+}
@@ -0,0 +1,301 @@
+package shadow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Header marks mirrored requests so the secondary never mirrors them again
+const Header = "X-Shadow-Request"
+
+// recentDiffs is how many differing responses are kept for inspection
+const recentDiffs = 50
+
+// mirroredHeaders are copied from the primary request to the shadow request.
+// Accept-Encoding is deliberately left out so the shadow body is comparable.
+// The caller's X-API-Key is replaced when the mirror has its own key.
+var mirroredHeaders = []string{"Accept", "Accept-Language", "User-Agent", "X-API-Key"}
+
+// Diff describes how a shadow response differed from the primary one
+type Diff struct {
+	Time          time.Time `json:"time"`
+	Request       string    `json:"request"` // method, path and query
+	PrimaryStatus int       `json:"primary_status"`
+	ShadowStatus  int       `json:"shadow_status,omitempty"`
+	Added         []string  `json:"added,omitempty"`   // articles only the shadow returned
+	Removed       []string  `json:"removed,omitempty"` // articles only the primary returned
+	Reordered     bool      `json:"reordered,omitempty"`
+	Intent        string    `json:"intent,omitempty"` // "primary -> shadow" when the parsed intent changed
+	BodyMismatch  bool      `json:"body_mismatch,omitempty"`
+	Error         string    `json:"error,omitempty"`
+}
+
+// Differs reports whether the comparison found any difference
+func (d *Diff) Differs() bool {
+	return d.PrimaryStatus != d.ShadowStatus || len(d.Added) > 0 || len(d.Removed) > 0 ||
+		d.Reordered || d.Intent != "" || d.BodyMismatch
+}
+
+// Stats summarizes shadow traffic since startup
+type Stats struct {
+	Upstream string  `json:"upstream"`
+	Percent  float64 `json:"percent"`
+	Mirrored int64   `json:"mirrored"`
+	Matched  int64   `json:"matched"`
+	Differed int64   `json:"differed"`
+	Failed   int64   `json:"failed"`
+	Recent   []Diff  `json:"recent"` // newest first
+}
+
+// Mirror copies a sample of read requests to a secondary upstream (e.g. a
+// canary build) and logs where its responses differ from the primary's
+type Mirror struct {
+	upstream *url.URL
+	percent  float64
+	apiKey   string // sent instead of the caller's key, if set
+	client   *http.Client
+
+	mu     sync.Mutex
+	stats  Stats
+	recent []Diff
+
+	// pending tracks in-flight shadow requests so shutdown can wait for them
+	pending sync.WaitGroup
+}
+
+// New creates a mirror sending percent (0-100) of requests to upstream.
+// Mirrored requests carry apiKey when it is set, so the upstream can have
+// keys of its own, and the caller's key otherwise. It returns nil when
+// upstream is empty or percent is not positive, and every method of a nil
+// Mirror is a no-op.
+func New(upstream string, percent float64, timeout time.Duration, apiKey string) (*Mirror, error) {
+	if upstream == "" || percent <= 0 {
+		return nil, nil
+	}
+
+	target, err := url.Parse(upstream)
+	if err != nil || target.Scheme == "" || target.Host == "" {
+		return nil, fmt.Errorf("invalid shadow upstream %q", upstream)
+	}
+	if percent > 100 {
+		percent = 100
+	}
+
+	return &Mirror{
+		upstream: target,
+		percent:  percent,
+		apiKey:   apiKey,
+		client:   &http.Client{Timeout: timeout},
+		stats:    Stats{Upstream: target.String(), Percent: percent},
+	}, nil
+}
+
+// Sample decides whether a request should be mirrored
+func (m *Mirror) Sample(r *http.Request) bool {
+	if m == nil || r.Method != http.MethodGet || r.Header.Get(Header) != "" {
+		return false
+	}
+	return rand.Float64()*100 < m.percent
+}
+
+// Send asynchronously replays r against the upstream and compares the
+// result with the primary status and body
+func (m *Mirror) Send(r *http.Request, primaryStatus int, primaryBody []byte) {
+	if m == nil {
+		return
+	}
+
+	target := *m.upstream
+	target.Path = strings.TrimSuffix(target.Path, "/") + r.URL.Path
+	target.RawQuery = r.URL.RawQuery
+
+	header := make(http.Header)
+	for _, name := range mirroredHeaders {
+		if value := r.Header.Get(name); value != "" {
+			header.Set(name, value)
+		}
+	}
+	if m.apiKey != "" {
+		query := r.URL.Query()
+		if query.Has("api_key") {
+			query.Del("api_key")
+			target.RawQuery = query.Encode()
+		}
+		header.Set("X-API-Key", m.apiKey)
+	}
+	header.Set(Header, "1")
+	request := r.Method + " " + r.URL.RequestURI()
+
+	m.pending.Add(1)
+	go func() {
+		defer m.pending.Done()
+		m.record(m.replay(target.String(), header, request, primaryStatus, primaryBody))
+	}()
+}
+
+// replay performs the shadow request and builds the diff
+func (m *Mirror) replay(target string, header http.Header, request string, primaryStatus int, primaryBody []byte) Diff {
+	diff := Diff{Time: time.Now(), Request: request, PrimaryStatus: primaryStatus}
+
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		diff.Error = err.Error()
+		return diff
+	}
+	req.Header = header
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		diff.Error = err.Error()
+		return diff
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		diff.Error = err.Error()
+		return diff
+	}
+
+	diff.ShadowStatus = resp.StatusCode
+	Compare(&diff, primaryBody, body)
+	return diff
+}
+
+// record updates the counters and logs differing responses
+func (m *Mirror) record(diff Diff) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.stats.Mirrored++
+	switch {
+	case diff.Error != "":
+		m.stats.Failed++
+		log.Printf("Shadow request %s failed: %s", diff.Request, diff.Error)
+	case diff.Differs():
+		m.stats.Differed++
+		log.Printf("Shadow diff %s: status %d -> %d, +%d/-%d articles, reordered=%v, intent=%q, body_mismatch=%v",
+			diff.Request, diff.PrimaryStatus, diff.ShadowStatus, len(diff.Added), len(diff.Removed),
+			diff.Reordered, diff.Intent, diff.BodyMismatch)
+	default:
+		m.stats.Matched++
+		return
+	}
+
+	m.recent = append(m.recent, diff)
+	if len(m.recent) > recentDiffs {
+		m.recent = m.recent[1:]
+	}
+}
+
+// Stats returns counters and the most recent diffs
+func (m *Mirror) Stats() Stats {
+	if m == nil {
+		return Stats{Recent: []Diff{}}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := m.stats
+	stats.Recent = make([]Diff, len(m.recent))
+	for i, diff := range m.recent {
+		stats.Recent[len(m.recent)-1-i] = diff
+	}
+	return stats
+}
+
+// Wait blocks until in-flight shadow requests finish or ctx is done
+func (m *Mirror) Wait(ctx context.Context) error {
+	if m == nil {
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		m.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Compare fills diff with the differences between two response bodies.
+// Article lists are compared by URL (or title) so ranking changes show up
+// as added, removed or reordered articles; volatile fields such as summaries
+// and scores are ignored. Other JSON bodies must be equal, and non-JSON
+// bodies byte-identical.
+func Compare(diff *Diff, primary, shadow []byte) {
+	var primaryDoc, shadowDoc map[string]interface{}
+	if json.Unmarshal(primary, &primaryDoc) != nil || json.Unmarshal(shadow, &shadowDoc) != nil {
+		diff.BodyMismatch = string(primary) != string(shadow)
+		return
+	}
+
+	primaryArticles, primaryHasArticles := articleKeys(primaryDoc)
+	shadowArticles, shadowHasArticles := articleKeys(shadowDoc)
+	if !primaryHasArticles || !shadowHasArticles {
+		diff.BodyMismatch = !reflect.DeepEqual(primaryDoc, shadowDoc)
+		return
+	}
+
+	inPrimary := make(map[string]bool, len(primaryArticles))
+	for _, key := range primaryArticles {
+		inPrimary[key] = true
+	}
+	inShadow := make(map[string]bool, len(shadowArticles))
+	for _, key := range shadowArticles {
+		inShadow[key] = true
+		if !inPrimary[key] {
+			diff.Added = append(diff.Added, key)
+		}
+	}
+	for _, key := range primaryArticles {
+		if !inShadow[key] {
+			diff.Removed = append(diff.Removed, key)
+		}
+	}
+	if len(diff.Added) == 0 && len(diff.Removed) == 0 {
+		diff.Reordered = !reflect.DeepEqual(primaryArticles, shadowArticles)
+	}
+
+	primaryIntent, _ := primaryDoc["intent"].(string)
+	shadowIntent, _ := shadowDoc["intent"].(string)
+	if primaryIntent != shadowIntent {
+		diff.Intent = primaryIntent + " -> " + shadowIntent
+	}
+}
+
+// articleKeys returns the URL (or title) of every article in the body, in order
+func articleKeys(doc map[string]interface{}) ([]string, bool) {
+	list, ok := doc["articles"].([]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	keys := make([]string, 0, len(list))
+	for _, item := range list {
+		article, _ := item.(map[string]interface{})
+		key, _ := article["url"].(string)
+		if key == "" {
+			key, _ = article["title"].(string)
+		}
+		keys = append(keys, key)
+	}
+	return keys, true
+}
@@ -0,0 +1,172 @@
+package shadow
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name     string
+		primary  string
+		shadow   string
+		expected Diff
+	}{
+		{
+			name:     "Same articles, different summaries",
+			primary:  `{"intent":"search","articles":[{"url":"a","llm_summary":"x"},{"url":"b"}]}`,
+			shadow:   `{"intent":"search","articles":[{"url":"a","llm_summary":"y"},{"url":"b"}]}`,
+			expected: Diff{},
+		},
+		{
+			name:     "Reordered",
+			primary:  `{"articles":[{"url":"a"},{"url":"b"}]}`,
+			shadow:   `{"articles":[{"url":"b"},{"url":"a"}]}`,
+			expected: Diff{Reordered: true},
+		},
+		{
+			name:     "Added and removed",
+			primary:  `{"articles":[{"url":"a"},{"url":"b"}]}`,
+			shadow:   `{"articles":[{"url":"a"},{"title":"c"}]}`,
+			expected: Diff{Added: []string{"c"}, Removed: []string{"b"}},
+		},
+		{
+			name:     "Intent changed",
+			primary:  `{"intent":"search","articles":[]}`,
+			shadow:   `{"intent":"category","articles":[]}`,
+			expected: Diff{Intent: "search -> category"},
+		},
+		{
+			name:     "Other JSON",
+			primary:  `{"total_articles":10}`,
+			shadow:   `{"total_articles":11}`,
+			expected: Diff{BodyMismatch: true},
+		},
+		{
+			name:     "Non-JSON",
+			primary:  "\x0a\x01a",
+			shadow:   "\x0a\x01a",
+			expected: Diff{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diff Diff
+			Compare(&diff, []byte(tt.primary), []byte(tt.shadow))
+			if !reflect.DeepEqual(diff, tt.expected) {
+				t.Errorf("Compare() = %+v, expected %+v", diff, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewDisabled(t *testing.T) {
+	mirror, err := New("", 50, 0, "")
+	if err != nil || mirror != nil {
+		t.Fatalf("New() with no upstream = %v, %v; expected nil, nil", mirror, err)
+	}
+	if mirror.Sample(httptest.NewRequest("GET", "/api/v1/news/search", nil)) {
+		t.Error("nil mirror sampled a request")
+	}
+	if stats := mirror.Stats(); stats.Mirrored != 0 {
+		t.Errorf("nil mirror stats = %+v", stats)
+	}
+
+	if _, err := New("not a url", 50, 0, ""); err == nil {
+		t.Error("New() accepted an invalid upstream")
+	}
+}
+
+func TestSample(t *testing.T) {
+	mirror, err := New("http://canary:8080", 100, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !mirror.Sample(httptest.NewRequest("GET", "/api/v1/news/search", nil)) {
+		t.Error("GET not sampled at 100%")
+	}
+	if mirror.Sample(httptest.NewRequest("POST", "/api/v1/feedback", nil)) {
+		t.Error("POST was sampled")
+	}
+
+	mirrored := httptest.NewRequest("GET", "/api/v1/news/search", nil)
+	mirrored.Header.Set(Header, "1")
+	if mirror.Sample(mirrored) {
+		t.Error("already-mirrored request was sampled")
+	}
+}
+
+func TestSendWithAPIKeys(t *testing.T) {
+	const body = `{"articles":[{"url":"a"}]}`
+	// An upstream with keys enabled, answering like the primary
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("X-API-Key")
+		if r.URL.Query().Get("api_key") != "" {
+			key = r.URL.Query().Get("api_key")
+		}
+		if key != "caller-key" && key != "canary-key" {
+			http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer upstream.Close()
+
+	tests := []struct {
+		name    string
+		apiKey  string
+		request func() *http.Request
+		matched bool
+	}{
+		{
+			name:   "Caller's key is forwarded",
+			apiKey: "",
+			request: func() *http.Request {
+				r := httptest.NewRequest("GET", "/api/v1/news/search?query=a", nil)
+				r.Header.Set("X-API-Key", "caller-key")
+				return r
+			},
+			matched: true,
+		},
+		{
+			name:   "Shadow key replaces the caller's",
+			apiKey: "canary-key",
+			request: func() *http.Request {
+				return httptest.NewRequest("GET", "/api/v1/news/search?query=a&api_key=primary-only", nil)
+			},
+			matched: true,
+		},
+		{
+			name:   "Caller's key unknown upstream",
+			apiKey: "",
+			request: func() *http.Request {
+				return httptest.NewRequest("GET", "/api/v1/news/search?query=a&api_key=primary-only", nil)
+			},
+			matched: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mirror, err := New(upstream.URL, 100, time.Second, tt.apiKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			mirror.Send(tt.request(), http.StatusOK, []byte(body))
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := mirror.Wait(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if stats := mirror.Stats(); (stats.Matched == 1) != tt.matched {
+				t.Errorf("stats = %+v, expected matched %v", stats, tt.matched)
+			}
+		})
+	}
+}
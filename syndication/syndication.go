@@ -0,0 +1,222 @@
+// Package syndication renders article lists as RSS 2.0 and Atom 1.0 feeds,
+// so feed readers and aggregators can subscribe to them.
+package syndication
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// Content types of the two formats
+const (
+	ContentTypeRSS  = "application/rss+xml"
+	ContentTypeAtom = "application/atom+xml"
+)
+
+// Feed is a feed in either format. Links and IDs are absolute IRIs.
+type Feed struct {
+	ID          string // permanent identifier of the feed
+	Title       string
+	Description string
+	Link        string    // the site the feed belongs to
+	SelfURL     string    // where the feed itself is served
+	Updated     time.Time // when an item last changed; the newest item's when zero
+	Items       []Item
+}
+
+// Item is one entry of a feed
+type Item struct {
+	ID        string // permanent identifier, never reused for another item
+	Title     string
+	Link      string // the article; may be empty
+	Summary   string // plain text
+	Author    string // the publisher's name
+	Published time.Time
+	// Categories are plain terms, e.g. "technology"
+	Categories []string
+}
+
+// updated returns when f last changed
+func (f Feed) updated() time.Time {
+	if !f.Updated.IsZero() {
+		return f.Updated
+	}
+	var newest time.Time
+	for _, item := range f.Items {
+		if item.Published.After(newest) {
+			newest = item.Published
+		}
+	}
+	if newest.IsZero() {
+		return time.Now()
+	}
+	return newest
+}
+
+// =============================================================================
+// RSS 2.0
+// =============================================================================
+
+type rssDocument struct {
+	XMLName  xml.Name   `xml:"rss"`
+	Version  string     `xml:"version,attr"`
+	AtomNS   string     `xml:"xmlns:atom,attr"`
+	DublinNS string     `xml:"xmlns:dc,attr"`
+	Channel  rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Self          atomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link,omitempty"`
+	Description string   `xml:"description,omitempty"`
+	Creator     string   `xml:"dc:creator,omitempty"`
+	Categories  []string `xml:"category"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// RSS renders f as an RSS 2.0 document. Item IDs become guids that are not
+// permalinks, since they need not be web pages.
+func RSS(f Feed) ([]byte, error) {
+	doc := rssDocument{
+		Version:  "2.0",
+		AtomNS:   atomNamespace,
+		DublinNS: "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.Link,
+			Description:   f.Description,
+			Self:          atomLink{Rel: "self", Type: ContentTypeRSS, Href: f.SelfURL},
+			LastBuildDate: f.updated().UTC().Format(time.RFC1123Z),
+			Items:         make([]rssItem, len(f.Items)),
+		},
+	}
+	for i, item := range f.Items {
+		doc.Channel.Items[i] = rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Summary,
+			Creator:     item.Author,
+			Categories:  item.Categories,
+			GUID:        rssGUID{Value: item.ID},
+		}
+		if !item.Published.IsZero() {
+			doc.Channel.Items[i].PubDate = item.Published.UTC().Format(time.RFC1123Z)
+		}
+	}
+	return encode(doc)
+}
+
+// =============================================================================
+// Atom 1.0
+// =============================================================================
+
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	NS      string      `xml:"xmlns,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomPerson  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Links      []atomLink     `xml:"link"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Author     *atomPerson    `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+	Summary    string         `xml:"summary,omitempty"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// Atom renders f as an Atom 1.0 document. The feed's title stands in as
+// the author of items without one, as Atom requires an author for each.
+func Atom(f Feed) ([]byte, error) {
+	doc := atomFeed{
+		NS:      atomNamespace,
+		ID:      f.ID,
+		Title:   f.Title,
+		Updated: f.updated().UTC().Format(time.RFC3339),
+		Author:  atomPerson{Name: f.Title},
+		Links: []atomLink{
+			{Rel: "self", Type: ContentTypeAtom, Href: f.SelfURL},
+			{Rel: "alternate", Href: f.Link},
+		},
+		Entries: make([]atomEntry, len(f.Items)),
+	}
+	for i, item := range f.Items {
+		updated := item.Published
+		if updated.IsZero() {
+			updated = f.updated()
+		}
+		entry := atomEntry{
+			ID:      item.ID,
+			Title:   item.Title,
+			Updated: updated.UTC().Format(time.RFC3339),
+			Summary: item.Summary,
+		}
+		if item.Link != "" {
+			entry.Links = []atomLink{{Rel: "alternate", Href: item.Link}}
+		}
+		if !item.Published.IsZero() {
+			entry.Published = entry.Updated
+		}
+		if item.Author != "" {
+			entry.Author = &atomPerson{Name: item.Author}
+		}
+		for _, category := range item.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: category})
+		}
+		doc.Entries[i] = entry
+	}
+	return encode(doc)
+}
+
+// encode writes doc with an XML declaration
+func encode(doc interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode feed: %w", err)
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,156 @@
+package syndication
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testFeed() Feed {
+	return Feed{
+		ID:          "https://news.example.com/api/v1/feeds/atom",
+		Title:       "Latest news",
+		Description: "The newest articles",
+		Link:        "https://news.example.com/",
+		SelfURL:     "https://news.example.com/api/v1/feeds/atom?category=technology",
+		Items: []Item{
+			{
+				ID:         "https://news.example.com/api/v1/articles/a1",
+				Title:      "Chips & <Cheaper> Power",
+				Link:       "https://example.com/chips",
+				Summary:    "Chip makers cut prices.",
+				Author:     "Reuters",
+				Published:  time.Date(2025, 3, 25, 17, 0, 0, 0, time.FixedZone("IST", 5*3600+1800)),
+				Categories: []string{"technology", "business"},
+			},
+			{
+				ID:        "https://news.example.com/api/v1/articles/a2",
+				Title:     "Older",
+				Published: time.Date(2025, 3, 24, 9, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+}
+
+func TestRSS(t *testing.T) {
+	body, err := RSS(testFeed())
+	if err != nil {
+		t.Fatalf("RSS() error = %v", err)
+	}
+	if !strings.HasPrefix(string(body), xml.Header) {
+		t.Errorf("RSS() does not start with an XML declaration: %.60s", body)
+	}
+
+	var doc struct {
+		Version string `xml:"version,attr"`
+		Channel struct {
+			LastBuildDate string `xml:"lastBuildDate"`
+			Self          struct {
+				Rel  string `xml:"rel,attr"`
+				Href string `xml:"href,attr"`
+			} `xml:"http://www.w3.org/2005/Atom link"`
+			Items []struct {
+				Title      string   `xml:"title"`
+				Link       string   `xml:"link"`
+				Creator    string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+				Categories []string `xml:"category"`
+				GUID       struct {
+					IsPermaLink string `xml:"isPermaLink,attr"`
+					Value       string `xml:",chardata"`
+				} `xml:"guid"`
+				PubDate string `xml:"pubDate"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("RSS() is not well-formed: %v\n%s", err, body)
+	}
+	if doc.Version != "2.0" {
+		t.Errorf("version = %q, expected 2.0", doc.Version)
+	}
+	if doc.Channel.Self.Rel != "self" || doc.Channel.Self.Href != testFeed().SelfURL {
+		t.Errorf("atom:link = %+v, expected the self URL", doc.Channel.Self)
+	}
+	if doc.Channel.LastBuildDate != "Tue, 25 Mar 2025 11:30:00 +0000" {
+		t.Errorf("lastBuildDate = %q, expected the newest item's date in UTC", doc.Channel.LastBuildDate)
+	}
+	if len(doc.Channel.Items) != 2 {
+		t.Fatalf("got %d items, expected 2", len(doc.Channel.Items))
+	}
+	item := doc.Channel.Items[0]
+	if item.Title != "Chips & <Cheaper> Power" || item.Creator != "Reuters" || len(item.Categories) != 2 {
+		t.Errorf("item = %+v, expected the escaped title, creator and both categories", item)
+	}
+	if item.GUID.IsPermaLink != "false" || item.GUID.Value != "https://news.example.com/api/v1/articles/a1" {
+		t.Errorf("guid = %+v, expected the item ID, not a permalink", item.GUID)
+	}
+	if _, err := time.Parse(time.RFC1123Z, item.PubDate); err != nil {
+		t.Errorf("pubDate %q is not RFC 822: %v", item.PubDate, err)
+	}
+	if strings.Contains(string(body), "<link></link>") {
+		t.Error("RSS() wrote an empty link for an item without one")
+	}
+}
+
+func TestAtom(t *testing.T) {
+	body, err := Atom(testFeed())
+	if err != nil {
+		t.Fatalf("Atom() error = %v", err)
+	}
+
+	var doc struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		ID      string   `xml:"id"`
+		Updated string   `xml:"updated"`
+		Author  string   `xml:"author>name"`
+		Links   []struct {
+			Rel  string `xml:"rel,attr"`
+			Href string `xml:"href,attr"`
+		} `xml:"link"`
+		Entries []struct {
+			ID        string `xml:"id"`
+			Updated   string `xml:"updated"`
+			Published string `xml:"published"`
+			Author    string `xml:"author>name"`
+			Links     []struct {
+				Href string `xml:"href,attr"`
+			} `xml:"link"`
+			Categories []struct {
+				Term string `xml:"term,attr"`
+			} `xml:"category"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("Atom() is not well-formed Atom: %v\n%s", err, body)
+	}
+	if doc.ID != testFeed().ID || doc.Author != "Latest news" {
+		t.Errorf("feed id = %q, author = %q, expected the feed's ID and title", doc.ID, doc.Author)
+	}
+	if doc.Updated != "2025-03-25T11:30:00Z" {
+		t.Errorf("updated = %q, expected the newest entry's date", doc.Updated)
+	}
+	if len(doc.Links) != 2 || doc.Links[0].Rel != "self" || doc.Links[1].Rel != "alternate" {
+		t.Errorf("links = %+v, expected self and alternate", doc.Links)
+	}
+	if len(doc.Entries) != 2 {
+		t.Fatalf("got %d entries, expected 2", len(doc.Entries))
+	}
+	entry := doc.Entries[0]
+	if entry.Published != "2025-03-25T11:30:00Z" || entry.Updated != entry.Published {
+		t.Errorf("entry dates = %q / %q, expected the publication date in UTC", entry.Published, entry.Updated)
+	}
+	if entry.Author != "Reuters" || len(entry.Links) != 1 || len(entry.Categories) != 2 {
+		t.Errorf("entry = %+v, expected an author, one link and two categories", entry)
+	}
+	if doc.Entries[1].Author != "" || len(doc.Entries[1].Links) != 0 {
+		t.Errorf("entry = %+v, expected no author or link when the item has none", doc.Entries[1])
+	}
+}
+
+func TestUpdatedWithoutItems(t *testing.T) {
+	before := time.Now()
+	if updated := (Feed{}).updated(); updated.Before(before) {
+		t.Errorf("updated() = %v, expected now for an empty feed", updated)
+	}
+}
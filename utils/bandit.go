@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"math"
+	"math/rand"
+)
+
+// =============================================================================
+// Multi-Armed Bandit Utilities
+// =============================================================================
+
+// BanditArm is an arm's record: successes out of trials
+type BanditArm struct {
+	Successes int64
+	Trials    int64
+}
+
+// SampleGamma draws from Gamma(shape, 1) with Marsaglia and Tsang's method
+func SampleGamma(r *rand.Rand, shape float64) float64 {
+	if shape < 1 {
+		// Gamma(a) is Gamma(a+1) scaled by U^(1/a)
+		return SampleGamma(r, shape+1) * math.Pow(r.Float64(), 1/shape)
+	}
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := r.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := r.Float64()
+		if u < 1-0.0331*x*x*x*x || math.Log(u) < 0.5*x*x+d*(1-v+math.Log(v)) {
+			return d * v
+		}
+	}
+}
+
+// SampleBeta draws from Beta(alpha, beta)
+func SampleBeta(r *rand.Rand, alpha, beta float64) float64 {
+	x := SampleGamma(r, alpha)
+	y := SampleGamma(r, beta)
+	return x / (x + y)
+}
+
+// ThompsonPick returns the index of the arm whose draw from its
+// Beta(1+successes, 1+failures) posterior is highest, or -1 without arms.
+// Successes beyond trials count as trials.
+func ThompsonPick(r *rand.Rand, arms []BanditArm) int {
+	best, bestDraw := -1, -1.0
+	for i, arm := range arms {
+		successes := min(arm.Successes, arm.Trials)
+		draw := SampleBeta(r, float64(1+successes), float64(1+arm.Trials-successes))
+		if draw > bestDraw {
+			best, bestDraw = i, draw
+		}
+	}
+	return best
+}
+
+// ThompsonShares estimates the share of picks ThompsonPick gives each arm
+// from n simulated picks
+func ThompsonShares(r *rand.Rand, arms []BanditArm, n int) []float64 {
+	shares := make([]float64, len(arms))
+	if len(arms) == 0 || n <= 0 {
+		return shares
+	}
+	for i := 0; i < n; i++ {
+		shares[ThompsonPick(r, arms)]++
+	}
+	for i := range shares {
+		shares[i] /= float64(n)
+	}
+	return shares
+}
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSampleBeta(t *testing.T) {
+	tests := []struct {
+		name  string
+		alpha float64
+		beta  float64
+	}{
+		{"Uniform", 1, 1},
+		{"Skewed high", 30, 10},
+		{"Small shape", 0.5, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			const n = 20000
+			sum := 0.0
+			for i := 0; i < n; i++ {
+				draw := SampleBeta(r, tt.alpha, tt.beta)
+				if draw < 0 || draw > 1 {
+					t.Fatalf("SampleBeta() = %v, outside [0, 1]", draw)
+				}
+				sum += draw
+			}
+			expected := tt.alpha / (tt.alpha + tt.beta)
+			if mean := sum / n; math.Abs(mean-expected) > 0.01 {
+				t.Errorf("mean of SampleBeta() = %v, expected %v", mean, expected)
+			}
+		})
+	}
+}
+
+func TestThompsonPick(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	if got := ThompsonPick(r, nil); got != -1 {
+		t.Errorf("ThompsonPick(nil) = %d, expected -1", got)
+	}
+
+	// Successes beyond trials must not break the posterior
+	arms := []BanditArm{{Successes: 5, Trials: 2}}
+	if got := ThompsonPick(r, arms); got != 0 {
+		t.Errorf("ThompsonPick() = %d, expected 0", got)
+	}
+}
+
+func TestThompsonShares(t *testing.T) {
+	tests := []struct {
+		name     string
+		arms     []BanditArm
+		expected []float64
+		within   float64
+	}{
+		{
+			name:     "Untried arms share evenly",
+			arms:     []BanditArm{{}, {}},
+			expected: []float64{0.5, 0.5},
+			within:   0.05,
+		},
+		{
+			name:     "Clearly better arm takes almost everything",
+			arms:     []BanditArm{{Successes: 10, Trials: 1000}, {Successes: 300, Trials: 1000}},
+			expected: []float64{0, 1},
+			within:   0.01,
+		},
+		{
+			name:     "No arms",
+			arms:     []BanditArm{},
+			expected: []float64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares := ThompsonShares(rand.New(rand.NewSource(1)), tt.arms, 2000)
+			if len(shares) != len(tt.expected) {
+				t.Fatalf("ThompsonShares() = %v, expected %v", shares, tt.expected)
+			}
+			for i := range shares {
+				if math.Abs(shares[i]-tt.expected[i]) > tt.within {
+					t.Errorf("ThompsonShares() = %v, expected %v", shares, tt.expected)
+				}
+			}
+		})
+	}
+}
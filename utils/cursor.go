@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor is returned for a cursor EncodeCursor didn't produce
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is a keyset position in a list ordered by time, then ID, both
+// descending: the next page starts after the row with this time and ID
+type Cursor struct {
+	Time time.Time
+	ID   string
+}
+
+// EncodeCursor returns an opaque, URL-safe cursor for the row at t with id.
+// The time keeps its offset so it compares equal to the stored value.
+func EncodeCursor(t time.Time, id string) string {
+	raw := t.Format(time.RFC3339Nano) + "|" + id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor from EncodeCursor. An empty cursor yields
+// the zero Cursor, the start of the list.
+func DecodeCursor(cursor string) (Cursor, error) {
+	if cursor == "" {
+		return Cursor{}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	timestamp, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+	t, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return Cursor{Time: t, ID: id}, nil
+}
+
+// IsZero reports whether the cursor is the start of the list
+func (c Cursor) IsZero() bool {
+	return c.ID == ""
+}
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	at := time.Date(2025, 3, 26, 4, 46, 55, 123456789, time.FixedZone("IST", 5*3600+1800))
+	cursor := EncodeCursor(at, "19aaddc0-7508|x")
+
+	decoded, err := DecodeCursor(cursor)
+	if err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+	if !decoded.Time.Equal(at) || decoded.ID != "19aaddc0-7508|x" {
+		t.Errorf("DecodeCursor() = %+v, expected %v and the original ID", decoded, at)
+	}
+	if _, offset := decoded.Time.Zone(); offset != 5*3600+1800 {
+		t.Errorf("offset = %d, expected the original +05:30", offset)
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	if cursor, err := DecodeCursor(""); err != nil || !cursor.IsZero() {
+		t.Errorf("DecodeCursor(\"\") = %+v, %v, expected the zero cursor", cursor, err)
+	}
+
+	tests := []string{
+		"not base64!",
+		EncodeCursor(time.Now(), ""),
+		"bm8tc2VwYXJhdG9y", // "no-separator"
+		"eWVzdGVyZGF5fDQy", // "yesterday|42"
+	}
+	for _, cursor := range tests {
+		if _, err := DecodeCursor(cursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("DecodeCursor(%q) error = %v, expected ErrInvalidCursor", cursor, err)
+		}
+	}
+}
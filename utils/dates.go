@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+)
+
+// DateLayout is the date-only format accepted alongside RFC3339
+const DateLayout = "2006-01-02"
+
+// ParseDate parses an RFC3339 timestamp or a YYYY-MM-DD date (UTC). A
+// date-only value means the start of that day, or its last instant when
+// endOfDay is set, so it can serve as an inclusive upper bound.
+// An empty value yields the zero time.
+func ParseDate(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(DateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected RFC3339 or YYYY-MM-DD, got %q", value)
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
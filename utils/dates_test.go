@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		endOfDay  bool
+		expected  time.Time
+		expectErr bool
+	}{
+		{"Empty", "", false, time.Time{}, false},
+		{"Date start of day", "2025-03-24", false, time.Date(2025, 3, 24, 0, 0, 0, 0, time.UTC), false},
+		{"Date end of day", "2025-03-24", true, time.Date(2025, 3, 24, 23, 59, 59, 999999999, time.UTC), false},
+		{"RFC3339 ignores endOfDay", "2025-03-24T10:30:00Z", true, time.Date(2025, 3, 24, 10, 30, 0, 0, time.UTC), false},
+		{"Invalid", "24/03/2025", false, time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseDate(tt.value, tt.endOfDay)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("ParseDate(%q) error = %v, expectErr %v", tt.value, err, tt.expectErr)
+			}
+			if !result.Equal(tt.expected) {
+				t.Errorf("ParseDate(%q, %v) = %v, expected %v", tt.value, tt.endOfDay, result, tt.expected)
+			}
+		})
+	}
+}
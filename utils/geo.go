@@ -3,6 +3,8 @@ package utils
 import (
 	"fmt"
 	"math"
+	"runtime"
+	"sync"
 )
 
 // HaversineDistance calculates the distance between two points on Earth using the Haversine formula
@@ -26,6 +28,53 @@ func HaversineDistance(lat1, lon1, lat2, lon2 float64) float64 {
 	return EarthRadiusKm * c
 }
 
+// parallelDistanceMinPoints is the input size from which HaversineDistances
+// shards the work across CPUs; below it, goroutine startup costs more than
+// it saves
+const parallelDistanceMinPoints = 8192
+
+// HaversineDistances computes the distance in kilometers from a reference
+// point to every point given as parallel latitude and longitude slices,
+// writing them to out (len(lats) == len(lons) == len(out)). The reference
+// point's trigonometry is done once and the loop runs over plain float64
+// slices, so large candidate sets cost far less than calling
+// HaversineDistance per item; results are identical.
+func HaversineDistances(refLat, refLon float64, lats, lons, out []float64) {
+	workers := runtime.GOMAXPROCS(0)
+	if len(out) < parallelDistanceMinPoints || workers == 1 {
+		haversineDistances(refLat, refLon, lats, lons, out)
+		return
+	}
+
+	chunk := (len(out) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < len(out); start += chunk {
+		end := min(start+chunk, len(out))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			haversineDistances(refLat, refLon, lats[start:end], lons[start:end], out[start:end])
+		}()
+	}
+	wg.Wait()
+}
+
+// haversineDistances is the single-threaded loop of HaversineDistances
+func haversineDistances(refLat, refLon float64, lats, lons, out []float64) {
+	const EarthRadiusKm = 6371.0
+
+	refLatRad := refLat * math.Pi / 180
+	cosRefLat := math.Cos(refLatRad)
+	for i := range out {
+		latRad := lats[i] * math.Pi / 180
+		sinDeltaLat := math.Sin((lats[i] - refLat) * math.Pi / 180 / 2)
+		sinDeltaLon := math.Sin((lons[i] - refLon) * math.Pi / 180 / 2)
+
+		a := sinDeltaLat*sinDeltaLat + cosRefLat*math.Cos(latRad)*sinDeltaLon*sinDeltaLon
+		out[i] = EarthRadiusKm * (2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a)))
+	}
+}
+
 // GeoHash creates a simple geohash for location clustering
 // Used for caching trending data by location
 func GeoHash(lat, lon float64, precision int) string {
@@ -51,3 +100,64 @@ func ValidateLocation(lat, lon float64) error {
 func IsWithinRadius(refLat, refLon, pointLat, pointLon, radius float64) bool {
 	return HaversineDistance(refLat, refLon, pointLat, pointLon) <= radius
 }
+
+// BoundingBox is a lat/lon rectangle enclosing a circular region
+type BoundingBox struct {
+	MinLat, MaxLat float64
+	MinLon, MaxLon float64
+}
+
+// BoundingBoxAround returns the smallest lat/lon box containing every point
+// within radiusKm of the given coordinate, clamped to valid ranges
+func BoundingBoxAround(lat, lon, radiusKm float64) BoundingBox {
+	latSpan := radiusKm / 111.0
+	lonSpan := 180.0
+	if cosLat := math.Cos(lat * math.Pi / 180); cosLat > 0.01 {
+		lonSpan = math.Min(180.0, radiusKm/(111.0*cosLat))
+	}
+
+	return BoundingBox{
+		MinLat: math.Max(lat-latSpan, -90),
+		MaxLat: math.Min(lat+latSpan, 90),
+		MinLon: math.Max(lon-lonSpan, -180),
+		MaxLon: math.Min(lon+lonSpan, 180),
+	}
+}
+
+// GridCell identifies a cell in the lat/lon grid used for location-based caching
+type GridCell struct {
+	LatCell int
+	LonCell int
+}
+
+// ToGridCell maps a coordinate onto a grid of the given cell size (degrees)
+func ToGridCell(lat, lon, precision float64) GridCell {
+	return GridCell{
+		LatCell: int(lat / precision),
+		LonCell: int(lon / precision),
+	}
+}
+
+// GridCellsWithin returns every grid cell that may contain a point within
+// radiusKm of the given coordinate. The result is a conservative bounding box.
+func GridCellsWithin(lat, lon, radiusKm, precision float64) []GridCell {
+	const kmPerDegree = 111.0
+
+	latSpan := radiusKm/kmPerDegree + precision
+	cosLat := math.Cos(lat * math.Pi / 180)
+	lonSpan := 360.0
+	if cosLat > 0.01 {
+		lonSpan = math.Min(360.0, radiusKm/(kmPerDegree*cosLat)+precision)
+	}
+
+	minCell := ToGridCell(math.Max(lat-latSpan, -90), math.Max(lon-lonSpan, -180), precision)
+	maxCell := ToGridCell(math.Min(lat+latSpan, 90), math.Min(lon+lonSpan, 180), precision)
+
+	cells := make([]GridCell, 0, (maxCell.LatCell-minCell.LatCell+1)*(maxCell.LonCell-minCell.LonCell+1))
+	for latCell := minCell.LatCell; latCell <= maxCell.LatCell; latCell++ {
+		for lonCell := minCell.LonCell; lonCell <= maxCell.LonCell; lonCell++ {
+			cells = append(cells, GridCell{LatCell: latCell, LonCell: lonCell})
+		}
+	}
+	return cells
+}
@@ -72,6 +72,28 @@ func TestHaversineDistance(t *testing.T) {
 	}
 }
 
+func TestHaversineDistances(t *testing.T) {
+	refLat, refLon := 37.7749, -122.4194
+
+	// Below and above the size that is sharded across CPUs
+	for _, n := range []int{0, 3, parallelDistanceMinPoints + 7} {
+		lats := make([]float64, n)
+		lons := make([]float64, n)
+		for i := range lats {
+			lats[i] = math.Mod(float64(i)*7.31, 180) - 90
+			lons[i] = math.Mod(float64(i)*13.17, 360) - 180
+		}
+
+		out := make([]float64, n)
+		HaversineDistances(refLat, refLon, lats, lons, out)
+		for i := range out {
+			if expected := HaversineDistance(refLat, refLon, lats[i], lons[i]); out[i] != expected {
+				t.Fatalf("n=%d: distance %d = %v, expected %v", n, i, out[i], expected)
+			}
+		}
+	}
+}
+
 func TestIsWithinRadius(t *testing.T) {
 	// San Francisco coordinates
 	sfLat, sfLon := 37.7749, -122.4194
@@ -150,3 +172,67 @@ func TestValidateLocation(t *testing.T) {
 		})
 	}
 }
+
+func TestGridCellsWithin(t *testing.T) {
+	const precision = 0.05
+	sfLat, sfLon := 37.7749, -122.4194
+
+	cells := GridCellsWithin(sfLat, sfLon, 20, precision)
+	cellSet := make(map[GridCell]bool, len(cells))
+	for _, cell := range cells {
+		cellSet[cell] = true
+	}
+
+	t.Run("Contains own cell", func(t *testing.T) {
+		if !cellSet[ToGridCell(sfLat, sfLon, precision)] {
+			t.Error("GridCellsWithin() should include the cell of the origin")
+		}
+	})
+
+	t.Run("Contains cells of nearby points", func(t *testing.T) {
+		// Oakland (~13 km) and Daly City (~12 km) are both within 20 km
+		nearby := [][2]float64{{37.8044, -122.2712}, {37.6879, -122.4702}}
+		for _, p := range nearby {
+			if !cellSet[ToGridCell(p[0], p[1], precision)] {
+				t.Errorf("GridCellsWithin() missing cell for (%v, %v)", p[0], p[1])
+			}
+		}
+	})
+
+	t.Run("Excludes distant cells", func(t *testing.T) {
+		// San Jose is ~70 km away
+		if cellSet[ToGridCell(37.3382, -121.8863, precision)] {
+			t.Error("GridCellsWithin() should not include cells 70 km away")
+		}
+	})
+}
+
+func TestBoundingBoxAround(t *testing.T) {
+	sfLat, sfLon := 37.7749, -122.4194
+	box := BoundingBoxAround(sfLat, sfLon, 20)
+
+	contains := func(lat, lon float64) bool {
+		return lat >= box.MinLat && lat <= box.MaxLat && lon >= box.MinLon && lon <= box.MaxLon
+	}
+
+	t.Run("Contains nearby points", func(t *testing.T) {
+		// Oakland (~13 km) and Daly City (~12 km)
+		if !contains(37.8044, -122.2712) || !contains(37.6879, -122.4702) {
+			t.Errorf("BoundingBoxAround() = %+v should contain points within 20 km", box)
+		}
+	})
+
+	t.Run("Excludes distant points", func(t *testing.T) {
+		// San Jose is ~70 km away
+		if contains(37.3382, -121.8863) {
+			t.Errorf("BoundingBoxAround() = %+v should not contain San Jose", box)
+		}
+	})
+
+	t.Run("Clamps near the pole", func(t *testing.T) {
+		polar := BoundingBoxAround(89.9, 0, 100)
+		if polar.MaxLat != 90 || polar.MinLon != -180 || polar.MaxLon != 180 {
+			t.Errorf("BoundingBoxAround() near pole = %+v, expected clamped box", polar)
+		}
+	})
+}
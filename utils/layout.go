@@ -0,0 +1,76 @@
+package utils
+
+// =============================================================================
+// Layout Hints
+// =============================================================================
+
+// Layout hints a client can render a list item with
+const (
+	LayoutHero     = "hero"
+	LayoutStandard = "standard"
+	LayoutCompact  = "compact"
+)
+
+// Importance signal weights behind layout hints (they sum to 1)
+const (
+	LayoutWeightBreaking = 0.4
+	LayoutWeightTrending = 0.4 // scaled by the item's share of the list's top trending score
+	LayoutWeightImage    = 0.2
+)
+
+// Importance a list item needs for a hero or standard layout
+const (
+	LayoutHeroThreshold     = 0.6
+	LayoutStandardThreshold = 0.25
+)
+
+// LayoutSignals are the importance signals of one list item
+type LayoutSignals struct {
+	Breaking bool    // urgent news
+	Trending float64 // trending score, 0 when not trending
+	HasImage bool
+}
+
+// LayoutImportance scores an item's signals in [0, 1]; maxTrending is the
+// list's highest trending score
+func LayoutImportance(signals LayoutSignals, maxTrending float64) float64 {
+	importance := 0.0
+	if signals.Breaking {
+		importance += LayoutWeightBreaking
+	}
+	if maxTrending > 0 {
+		importance += LayoutWeightTrending * signals.Trending / maxTrending
+	}
+	if signals.HasImage {
+		importance += LayoutWeightImage
+	}
+	return importance
+}
+
+// LayoutHints assigns each item of a list a layout hint from its
+// importance. Only the most important item (the first on a tie) can be the
+// hero, so a list has at most one; the rest are standard or compact.
+func LayoutHints(items []LayoutSignals) []string {
+	maxTrending := 0.0
+	for _, item := range items {
+		maxTrending = max(maxTrending, item.Trending)
+	}
+
+	hints := make([]string, len(items))
+	hero := -1
+	heroImportance := 0.0
+	for i, item := range items {
+		importance := LayoutImportance(item, maxTrending)
+		hints[i] = LayoutCompact
+		if importance >= LayoutStandardThreshold {
+			hints[i] = LayoutStandard
+		}
+		if importance >= LayoutHeroThreshold && importance > heroImportance {
+			hero, heroImportance = i, importance
+		}
+	}
+	if hero >= 0 {
+		hints[hero] = LayoutHero
+	}
+	return hints
+}
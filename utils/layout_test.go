@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestLayoutImportance(t *testing.T) {
+	tests := []struct {
+		name        string
+		signals     LayoutSignals
+		maxTrending float64
+		expected    float64
+	}{
+		{"No signals", LayoutSignals{}, 0, 0},
+		{"Every signal at the top", LayoutSignals{Breaking: true, Trending: 8, HasImage: true}, 8, 1},
+		{"Half the top trending score", LayoutSignals{Trending: 4}, 8, 0.2},
+		{"Trending without a list maximum", LayoutSignals{Trending: 4}, 0, 0},
+		{"Breaking with an image", LayoutSignals{Breaking: true, HasImage: true}, 0, 0.6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := LayoutImportance(tt.signals, tt.maxTrending)
+			if math.Abs(result-tt.expected) > 1e-9 {
+				t.Errorf("LayoutImportance() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLayoutHints(t *testing.T) {
+	tests := []struct {
+		name     string
+		items    []LayoutSignals
+		expected []string
+	}{
+		{"Empty list", []LayoutSignals{}, []string{}},
+		{
+			name: "Most important item is the only hero",
+			items: []LayoutSignals{
+				{Breaking: true, HasImage: true}, // 0.6
+				{Breaking: true, Trending: 10},   // 0.8
+				{Trending: 5, HasImage: true},    // 0.4
+				{Trending: 1},                    // 0.04
+			},
+			expected: []string{LayoutStandard, LayoutHero, LayoutStandard, LayoutCompact},
+		},
+		{
+			name: "First of equally important items wins",
+			items: []LayoutSignals{
+				{Breaking: true, HasImage: true},
+				{Breaking: true, HasImage: true},
+			},
+			expected: []string{LayoutHero, LayoutStandard},
+		},
+		{
+			name: "No hero below the threshold",
+			items: []LayoutSignals{
+				{Breaking: true},
+				{HasImage: true},
+			},
+			expected: []string{LayoutStandard, LayoutCompact},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := LayoutHints(tt.items)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("LayoutHints() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
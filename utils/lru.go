@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Bounded LRU Cache
+// =============================================================================
+
+// LRU is a thread-safe cache holding at most size entries, each for at most
+// ttl. Adding to a full cache evicts the least recently used entry.
+type LRU[K comparable, V any] struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	order   *list.List // front is the most recently used
+	entries map[K]*list.Element
+	now     func() time.Time
+}
+
+type lruEntry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time
+}
+
+// NewLRU creates an empty cache of size entries kept for ttl
+func NewLRU[K comparable, V any](size int, ttl time.Duration) *LRU[K, V] {
+	return newLRUWithClock[K, V](size, ttl, time.Now)
+}
+
+// newLRUWithClock allows tests to control time
+func newLRUWithClock[K comparable, V any](size int, ttl time.Duration, now func() time.Time) *LRU[K, V] {
+	return &LRU[K, V]{
+		size:    max(size, 1),
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[K]*list.Element),
+		now:     now,
+	}
+}
+
+// Get returns the value for key, or false when it is missing or expired
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var zero V
+	element, ok := c.entries[key]
+	if !ok {
+		return zero, false
+	}
+	entry := element.Value.(*lruEntry[K, V])
+	if c.now().After(entry.expires) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return zero, false
+	}
+	c.order.MoveToFront(element)
+	return entry.value, true
+}
+
+// Add stores value under key for the cache's ttl
+func (c *LRU[K, V]) Add(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expires := c.now().Add(c.ttl)
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*lruEntry[K, V])
+		entry.value, entry.expires = value, expires
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value, expires: expires})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry[K, V]).key)
+	}
+}
+
+// Remove drops key
+func (c *LRU[K, V]) Remove(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		c.order.Remove(element)
+		delete(c.entries, key)
+	}
+}
+
+// Purge drops every entry
+func (c *LRU[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	c.entries = make(map[K]*list.Element)
+}
+
+// Len is the number of entries held, expired ones included until read
+func (c *LRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
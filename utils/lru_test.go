@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRU(t *testing.T) {
+	now := time.Unix(0, 0)
+	clock := func() time.Time { return now }
+
+	t.Run("Evicts the least recently used entry", func(t *testing.T) {
+		cache := newLRUWithClock[string, int](2, time.Minute, clock)
+		cache.Add("a", 1)
+		cache.Add("b", 2)
+		cache.Get("a") // b is now the least recently used
+		cache.Add("c", 3)
+
+		if _, ok := cache.Get("b"); ok {
+			t.Error("Get(b) found an entry, expected it evicted")
+		}
+		if v, ok := cache.Get("a"); !ok || v != 1 {
+			t.Errorf("Get(a) = %v, %v, expected 1, true", v, ok)
+		}
+		if cache.Len() != 2 {
+			t.Errorf("Len() = %d, expected 2", cache.Len())
+		}
+	})
+
+	t.Run("Expires entries after the ttl", func(t *testing.T) {
+		cache := newLRUWithClock[string, int](2, time.Minute, clock)
+		cache.Add("a", 1)
+		now = now.Add(2 * time.Minute)
+		if _, ok := cache.Get("a"); ok {
+			t.Error("Get(a) found an expired entry")
+		}
+		if cache.Len() != 0 {
+			t.Errorf("Len() = %d, expected the expired entry dropped", cache.Len())
+		}
+	})
+
+	t.Run("Removes and purges", func(t *testing.T) {
+		cache := newLRUWithClock[string, int](3, time.Minute, clock)
+		cache.Add("a", 1)
+		cache.Add("b", 2)
+		cache.Remove("a")
+		if _, ok := cache.Get("a"); ok {
+			t.Error("Get(a) found a removed entry")
+		}
+		cache.Purge()
+		if cache.Len() != 0 {
+			t.Errorf("Len() = %d after Purge, expected 0", cache.Len())
+		}
+		cache.Add("c", 3)
+		if v, ok := cache.Get("c"); !ok || v != 3 {
+			t.Errorf("Get(c) = %v, %v after Purge, expected 3, true", v, ok)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package utils
+
+import "math"
+
+// =============================================================================
+// Score Normalization
+// =============================================================================
+
+// Score normalization methods, applied per signal across one request's candidates
+const (
+	NormalizationMinMax = "minmax" // rescales so the lowest candidate is 0 and the highest 1
+	NormalizationZScore = "zscore" // standardizes, then maps through the normal CDF into (0, 1)
+	NormalizationNone   = "none"   // uses each signal's raw scale
+)
+
+// IsNormalization reports whether method is a known score normalization
+func IsNormalization(method string) bool {
+	switch method {
+	case NormalizationMinMax, NormalizationZScore, NormalizationNone:
+		return true
+	default:
+		return false
+	}
+}
+
+// NormalizeScores rescales values in place so signals on different scales
+// weigh in comparably when blended. A signal that is the same for every
+// candidate can't order them and maps to 0.5 under either method.
+func NormalizeScores(values []float64, method string) {
+	if len(values) == 0 {
+		return
+	}
+
+	switch method {
+	case NormalizationMinMax:
+		lo, hi := values[0], values[0]
+		for _, v := range values[1:] {
+			lo = math.Min(lo, v)
+			hi = math.Max(hi, v)
+		}
+		for i, v := range values {
+			if hi == lo {
+				values[i] = 0.5
+			} else {
+				values[i] = (v - lo) / (hi - lo)
+			}
+		}
+	case NormalizationZScore:
+		mean := 0.0
+		for _, v := range values {
+			mean += v
+		}
+		mean /= float64(len(values))
+		variance := 0.0
+		for _, v := range values {
+			variance += (v - mean) * (v - mean)
+		}
+		stddev := math.Sqrt(variance / float64(len(values)))
+		for i, v := range values {
+			if stddev == 0 {
+				values[i] = 0.5
+			} else {
+				values[i] = 0.5 * (1 + math.Erf((v-mean)/stddev/math.Sqrt2))
+			}
+		}
+	}
+}
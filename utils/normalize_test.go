@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormalizeScores(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		values   []float64
+		expected []float64
+	}{
+		{"Min-max", NormalizationMinMax, []float64{2, 4, 10}, []float64{0, 0.25, 1}},
+		{"Min-max flat signal", NormalizationMinMax, []float64{3, 3}, []float64{0.5, 0.5}},
+		{"Z-score", NormalizationZScore, []float64{1, 2, 3}, []float64{0.1103, 0.5, 0.8897}},
+		{"Z-score flat signal", NormalizationZScore, []float64{0, 0, 0}, []float64{0.5, 0.5, 0.5}},
+		{"None", NormalizationNone, []float64{-1, 40}, []float64{-1, 40}},
+		{"Empty", NormalizationMinMax, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := append([]float64(nil), tt.values...)
+			NormalizeScores(values, tt.method)
+			for i := range values {
+				if math.Abs(values[i]-tt.expected[i]) > 1e-4 {
+					t.Errorf("NormalizeScores(%v, %s) = %v, expected %v", tt.values, tt.method, values, tt.expected)
+					break
+				}
+			}
+		})
+	}
+
+	if IsNormalization("rank") || !IsNormalization(NormalizationZScore) {
+		t.Error("IsNormalization() accepted or rejected the wrong method")
+	}
+}
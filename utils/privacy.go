@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"strconv"
+)
+
+// =============================================================================
+// Aggregate Privacy
+// =============================================================================
+
+// CountPrivacy protects engagement counts published outside the service,
+// so small cells can't reveal what individual users read or where they
+// were: cells with fewer than MinUsers distinct users are suppressed, and
+// counts get Laplace noise of scale 1/Epsilon (each user changing a count
+// by one). The zero value publishes exact counts.
+type CountPrivacy struct {
+	MinUsers int     // 0 or 1 keeps every cell
+	Epsilon  float64 // privacy budget per count; 0 adds no noise
+	salt     uint64
+}
+
+// NewCountPrivacy returns a CountPrivacy whose noise is derived from salt,
+// which should be secret so the noise can't be recomputed and subtracted
+func NewCountPrivacy(minUsers int, epsilon float64, salt uint64) CountPrivacy {
+	return CountPrivacy{MinUsers: minUsers, Epsilon: epsilon, salt: salt}
+}
+
+// Suppressed reports whether a cell with this many distinct users must not
+// be published
+func (p CountPrivacy) Suppressed(users int) bool {
+	return users < p.MinUsers
+}
+
+// Noisy returns count with Laplace noise added, rounded and at least 0. The
+// noise is fixed by key (naming the cell and count, e.g. an article and
+// location) and the count, so asking for the same count again returns the
+// same value instead of fresh noise that could be averaged away.
+func (p CountPrivacy) Noisy(key string, count int) int {
+	if p.Epsilon <= 0 {
+		return count
+	}
+	hash := fnv.New64a()
+	var salt [8]byte
+	binary.LittleEndian.PutUint64(salt[:], p.salt)
+	hash.Write(salt[:])
+	hash.Write([]byte(key + "|" + strconv.Itoa(count)))
+	u := rand.New(rand.NewSource(int64(hash.Sum64()))).Float64() - 0.5
+
+	noise := -math.Copysign(1/p.Epsilon, u) * math.Log(1-2*math.Abs(u))
+	return max(int(math.Round(float64(count)+noise)), 0)
+}
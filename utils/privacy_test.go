@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestCountPrivacySuppressed(t *testing.T) {
+	privacy := NewCountPrivacy(3, 0, 1)
+	if !privacy.Suppressed(2) || privacy.Suppressed(3) {
+		t.Errorf("Suppressed(2), Suppressed(3) = %v, %v, expected true, false", privacy.Suppressed(2), privacy.Suppressed(3))
+	}
+	if (CountPrivacy{}).Suppressed(0) {
+		t.Error("zero CountPrivacy suppressed a cell, expected every cell kept")
+	}
+}
+
+func TestCountPrivacyNoisy(t *testing.T) {
+	if got := (CountPrivacy{}).Noisy("article", 7); got != 7 {
+		t.Errorf("Noisy() without epsilon = %d, expected the exact count 7", got)
+	}
+
+	privacy := NewCountPrivacy(0, 0.5, 42)
+	if first, again := privacy.Noisy("article|cell", 10), privacy.Noisy("article|cell", 10); first != again {
+		t.Errorf("Noisy() = %d then %d, expected the same noise for the same key and count", first, again)
+	}
+	if other := NewCountPrivacy(0, 0.5, 43); other.Noisy("a", 10) == privacy.Noisy("a", 10) &&
+		other.Noisy("b", 10) == privacy.Noisy("b", 10) && other.Noisy("c", 10) == privacy.Noisy("c", 10) {
+		t.Error("Noisy() matched across salts, expected the salt to change the noise")
+	}
+
+	// Laplace noise of scale 2 has mean 0 and mean absolute deviation 2
+	var sum, deviation float64
+	const samples = 5000
+	for i := 0; i < samples; i++ {
+		noisy := privacy.Noisy("cell"+strconv.Itoa(i), 100)
+		sum += float64(noisy)
+		deviation += math.Abs(float64(noisy - 100))
+	}
+	if mean := sum / samples; math.Abs(mean-100) > 0.3 {
+		t.Errorf("mean noisy count = %.2f, expected about 100", mean)
+	}
+	if mad := deviation / samples; mad < 1.6 || mad > 2.4 {
+		t.Errorf("mean absolute noise = %.2f, expected about 2", mad)
+	}
+
+	for i := 0; i < 100; i++ {
+		if noisy := privacy.Noisy("small"+strconv.Itoa(i), 0); noisy < 0 {
+			t.Fatalf("Noisy(0) = %d, expected counts never negative", noisy)
+		}
+	}
+}
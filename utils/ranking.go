@@ -0,0 +1,69 @@
+package utils
+
+// =============================================================================
+// Ranking Profiles
+// =============================================================================
+
+// RankingWeights sets how much each normalized signal contributes when
+// blending a ranking profile
+type RankingWeights struct {
+	Recency    float64 // Freshness of the publication date
+	Engagement float64 // Recent user events on the article
+	Distance   float64 // Proximity to the request location
+	Personal   float64 // Affinity of the user for the article's categories and source
+}
+
+// Map returns the weights keyed by signal name
+func (w RankingWeights) Map() map[string]float64 {
+	return map[string]float64{
+		"recency":    w.Recency,
+		"engagement": w.Engagement,
+		"distance":   w.Distance,
+		"personal":   w.Personal,
+	}
+}
+
+// RankingSignals holds one article's signals, each in [0, 1] once normalized
+type RankingSignals struct {
+	Recency    float64
+	Engagement float64
+	Distance   float64
+	Personal   float64
+}
+
+// Blend returns the weighted sum of the signals
+func (s RankingSignals) Blend(w RankingWeights) float64 {
+	return s.Recency*w.Recency +
+		s.Engagement*w.Engagement +
+		s.Distance*w.Distance +
+		s.Personal*w.Personal
+}
+
+// ProximityScore maps a distance to (0, 1], halving at scaleKm
+func ProximityScore(distanceKm, scaleKm float64) float64 {
+	if scaleKm <= 0 || distanceKm < 0 {
+		return 0
+	}
+	return 1 / (1 + distanceKm/scaleKm)
+}
+
+// NormalizeRankingSignals normalizes each signal across the candidates with
+// the given method so profile weights trade off like for like
+func NormalizeRankingSignals(signals []RankingSignals, method string) {
+	fields := []func(*RankingSignals) *float64{
+		func(s *RankingSignals) *float64 { return &s.Recency },
+		func(s *RankingSignals) *float64 { return &s.Engagement },
+		func(s *RankingSignals) *float64 { return &s.Distance },
+		func(s *RankingSignals) *float64 { return &s.Personal },
+	}
+	values := make([]float64, len(signals))
+	for _, field := range fields {
+		for i := range signals {
+			values[i] = *field(&signals[i])
+		}
+		NormalizeScores(values, method)
+		for i := range signals {
+			*field(&signals[i]) = values[i]
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRankingSignalsBlend(t *testing.T) {
+	weights := RankingWeights{Recency: 0.6, Engagement: 0.2, Distance: 0.1, Personal: 0.1}
+	signals := RankingSignals{Recency: 1, Engagement: 0.5, Distance: 0, Personal: 1}
+
+	// 0.6*1 + 0.2*0.5 + 0.1*0 + 0.1*1 = 0.8
+	if got := signals.Blend(weights); math.Abs(got-0.8) > 1e-9 {
+		t.Errorf("Blend() = %v, expected 0.8", got)
+	}
+
+	m := weights.Map()
+	if len(m) != 4 || m["recency"] != 0.6 || m["personal"] != 0.1 {
+		t.Errorf("Map() = %v", m)
+	}
+}
+
+func TestProximityScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		distance float64
+		scale    float64
+		expected float64
+	}{
+		{"At the location", 0, 50, 1},
+		{"Halves at the scale", 50, 50, 0.5},
+		{"Decays further out", 150, 50, 0.25},
+		{"No scale", 10, 0, 0},
+		{"Unknown distance", -1, 50, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ProximityScore(tt.distance, tt.scale); math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("ProximityScore(%v, %v) = %v, expected %v", tt.distance, tt.scale, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNormalizeRankingSignals(t *testing.T) {
+	signals := []RankingSignals{
+		{Recency: 1, Engagement: 0.02, Distance: 0.5, Personal: -0.5},
+		{Recency: 0.9, Engagement: 0.01, Distance: 0.5, Personal: 0.5},
+	}
+	NormalizeRankingSignals(signals, NormalizationMinMax)
+
+	expected := []RankingSignals{
+		{Recency: 1, Engagement: 1, Distance: 0.5, Personal: 0},
+		{Recency: 0, Engagement: 0, Distance: 0.5, Personal: 1},
+	}
+	for i := range signals {
+		if signals[i] != expected[i] {
+			t.Errorf("signals[%d] = %+v, expected %+v", i, signals[i], expected[i])
+		}
+	}
+}
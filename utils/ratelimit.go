@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Token Bucket Rate Limiter
+// =============================================================================
+
+// TokenBucket is a thread-safe token bucket rate limiter
+type TokenBucket struct {
+	mu       sync.Mutex
+	capacity float64
+	rate     float64 // tokens added per second
+	tokens   float64
+	last     time.Time
+	now      func() time.Time
+}
+
+// NewTokenBucket creates a full bucket holding capacity tokens, refilled at rate tokens/second
+func NewTokenBucket(capacity, rate float64) *TokenBucket {
+	return newTokenBucketWithClock(capacity, rate, time.Now)
+}
+
+// newTokenBucketWithClock allows tests to control time
+func newTokenBucketWithClock(capacity, rate float64, now func() time.Time) *TokenBucket {
+	return &TokenBucket{
+		capacity: capacity,
+		rate:     rate,
+		tokens:   capacity,
+		last:     now(),
+		now:      now,
+	}
+}
+
+// refill adds tokens for the time elapsed since the last call (lock must be held)
+func (b *TokenBucket) refill() {
+	now := b.now()
+	elapsed := now.Sub(b.last).Seconds()
+	b.last = now
+	b.tokens = math.Min(b.capacity, b.tokens+elapsed*b.rate)
+}
+
+// Allow takes a token if one is available
+func (b *TokenBucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	if b.tokens >= 1 {
+		b.tokens--
+		return true
+	}
+	return false
+}
+
+// WaitTime returns how long until a token will be available (0 if one is available now)
+func (b *TokenBucket) WaitTime() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	if b.tokens >= 1 || b.rate <= 0 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// Tokens returns the number of tokens currently available
+func (b *TokenBucket) Tokens() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	return b.tokens
+}
+
+// Capacity returns the bucket size
+func (b *TokenBucket) Capacity() float64 {
+	return b.capacity
+}
+
+// =============================================================================
+// Per-Client Rate Limiter
+// =============================================================================
+
+// clientLimiterPruneInterval is how often idle client buckets are dropped
+const clientLimiterPruneInterval = time.Minute
+
+// ClientLimiter keeps a token bucket per client, refilled at perMinute
+// tokens a minute
+type ClientLimiter struct {
+	perMinute float64
+
+	mu        sync.Mutex
+	buckets   map[string]*TokenBucket
+	lastPrune time.Time
+}
+
+// NewClientLimiter creates a limiter allowing each client perMinute
+// requests a minute, at least 1
+func NewClientLimiter(perMinute int) *ClientLimiter {
+	return &ClientLimiter{
+		perMinute: math.Max(1, float64(perMinute)),
+		buckets:   make(map[string]*TokenBucket),
+		lastPrune: time.Now(),
+	}
+}
+
+// Allow takes a token for client and reports the tokens left, or how long
+// until one is available
+func (l *ClientLimiter) Allow(client string) (int, time.Duration, bool) {
+	l.mu.Lock()
+	if time.Since(l.lastPrune) > clientLimiterPruneInterval {
+		// A full bucket has been idle long enough to forget
+		for key, bucket := range l.buckets {
+			if bucket.Tokens() >= bucket.Capacity() {
+				delete(l.buckets, key)
+			}
+		}
+		l.lastPrune = time.Now()
+	}
+	bucket, ok := l.buckets[client]
+	if !ok {
+		bucket = NewTokenBucket(l.perMinute, l.perMinute/60)
+		l.buckets[client] = bucket
+	}
+	l.mu.Unlock()
+
+	if bucket.Allow() {
+		return int(bucket.Tokens()), 0, true
+	}
+	return 0, bucket.WaitTime(), false
+}
+
+// PerMinute is the number of requests a minute each client is allowed
+func (l *ClientLimiter) PerMinute() int {
+	return int(l.perMinute)
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Unix(0, 0)
+	clock := func() time.Time { return now }
+
+	bucket := newTokenBucketWithClock(3, 1, clock) // 3 burst, 1 token/second
+
+	t.Run("Allows burst up to capacity", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			if !bucket.Allow() {
+				t.Fatalf("Allow() = false on request %d, expected true", i+1)
+			}
+		}
+		if bucket.Allow() {
+			t.Error("Allow() = true after burst, expected false")
+		}
+	})
+
+	t.Run("Reports wait time when empty", func(t *testing.T) {
+		if wait := bucket.WaitTime(); wait != time.Second {
+			t.Errorf("WaitTime() = %v, expected 1s", wait)
+		}
+	})
+
+	t.Run("Refills over time", func(t *testing.T) {
+		now = now.Add(2 * time.Second)
+		if tokens := bucket.Tokens(); tokens != 2 {
+			t.Errorf("Tokens() = %v, expected 2", tokens)
+		}
+		if !bucket.Allow() {
+			t.Error("Allow() = false after refill, expected true")
+		}
+	})
+
+	t.Run("Never exceeds capacity", func(t *testing.T) {
+		now = now.Add(time.Hour)
+		if tokens := bucket.Tokens(); tokens != 3 {
+			t.Errorf("Tokens() = %v, expected capacity 3", tokens)
+		}
+	})
+}
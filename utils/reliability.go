@@ -0,0 +1,13 @@
+package utils
+
+// =============================================================================
+// Source Reliability Utilities
+// =============================================================================
+
+// ReliabilityFactor is the score multiplier for an article whose source is
+// rated reliability (0 to 1): 1 at the baseline rating, moving by weight per
+// unit of rating above or below it. Ratings outside [0, 1] are clamped.
+func ReliabilityFactor(reliability, baseline, weight float64) float64 {
+	reliability = min(max(reliability, 0), 1)
+	return max(1+weight*(reliability-baseline), 0)
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestReliabilityFactor(t *testing.T) {
+	tests := []struct {
+		name        string
+		reliability float64
+		baseline    float64
+		weight      float64
+		expected    float64
+	}{
+		{"Baseline is neutral", 0.5, 0.5, 0.4, 1},
+		{"Reliable source boosted", 1, 0.5, 0.4, 1.2},
+		{"Unreliable source demoted", 0, 0.5, 0.4, 0.8},
+		{"Zero weight disables", 0.1, 0.5, 0, 1},
+		{"Rating clamped", 3, 0.5, 0.4, 1.2},
+		{"Never negative", 0, 1, 2, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ReliabilityFactor(tt.reliability, tt.baseline, tt.weight)
+			if math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("ReliabilityFactor(%v, %v, %v) = %v, expected %v",
+					tt.reliability, tt.baseline, tt.weight, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package utils
+
+import "strings"
+
+// =============================================================================
+// Sentiment and Tone Classification
+// =============================================================================
+
+// Article sentiments
+const (
+	SentimentPositive = "positive"
+	SentimentNegative = "negative"
+	SentimentNeutral  = "neutral"
+)
+
+// Article tones
+const (
+	ToneFactual    = "factual"    // straight reporting
+	ToneAnalytical = "analytical" // explainers, analysis, studies
+	ToneOpinion    = "opinion"    // columns, editorials, commentary
+	ToneUrgent     = "urgent"     // breaking news, alerts, warnings
+)
+
+// IsSentiment reports whether sentiment is a known article sentiment
+func IsSentiment(sentiment string) bool {
+	switch sentiment {
+	case SentimentPositive, SentimentNegative, SentimentNeutral:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsTone reports whether tone is a known article tone
+func IsTone(tone string) bool {
+	switch tone {
+	case ToneFactual, ToneAnalytical, ToneOpinion, ToneUrgent:
+		return true
+	default:
+		return false
+	}
+}
+
+// sentimentLexicon weighs words that lean a headline positive (+1) or
+// negative (-1)
+var sentimentLexicon = map[string]int{
+	"win": 1, "wins": 1, "won": 1, "victory": 1, "success": 1, "successful": 1,
+	"gain": 1, "gains": 1, "rise": 1, "rises": 1, "surge": 1, "surges": 1,
+	"record": 1, "boost": 1, "boosts": 1, "growth": 1, "celebrate": 1, "celebrates": 1,
+	"award": 1, "awarded": 1, "launch": 1, "launches": 1, "improve": 1, "improves": 1,
+	"recovery": 1, "recovers": 1, "rescue": 1, "rescued": 1, "breakthrough": 1,
+	"hope": 1, "hopeful": 1, "peace": 1, "deal": 1, "approve": 1, "approved": 1,
+	"strong": 1, "profit": 1, "profits": 1, "best": 1, "historic": 1, "honoured": 1,
+	"honored": 1, "relief": 1, "welcome": 1, "welcomes": 1, "top": 1,
+
+	"loss": -1, "losses": -1, "lose": -1, "loses": -1, "lost": -1, "defeat": -1,
+	"fall": -1, "falls": -1, "drop": -1, "drops": -1, "crash": -1, "crashes": -1,
+	"decline": -1, "slump": -1, "death": -1, "dead": -1, "dies": -1, "killed": -1,
+	"kill": -1, "murder": -1, "attack": -1, "attacks": -1, "war": -1, "violence": -1,
+	"injured": -1, "accident": -1, "fire": -1, "flood": -1, "floods": -1, "disaster": -1,
+	"crisis": -1, "arrest": -1, "arrested": -1, "fraud": -1, "scam": -1, "protest": -1,
+	"protests": -1, "fear": -1, "fears": -1, "threat": -1, "warns": -1, "fails": -1,
+	"failure": -1, "ban": -1, "banned": -1, "shortage": -1, "collapse": -1, "weak": -1,
+	"worst": -1, "suicide": -1, "riot": -1, "clash": -1, "clashes": -1, "cuts": -1,
+}
+
+// sentimentNegators flip the lean of the next word ("not approved")
+var sentimentNegators = map[string]bool{
+	"not": true, "no": true, "never": true, "without": true,
+}
+
+// ClassifySentiment labels text positive, negative or neutral with a word
+// lexicon. It needs at least a third more weight on one side than the
+// other; text with no or evenly mixed cues is neutral.
+func ClassifySentiment(text string) string {
+	positive, negative := 0, 0
+	negate := false
+	for _, word := range strings.Fields(NormalizeTitle(text)) {
+		if sentimentNegators[word] {
+			negate = true
+			continue
+		}
+		weight := sentimentLexicon[word]
+		if negate {
+			weight = -weight
+			negate = false
+		}
+		switch {
+		case weight > 0:
+			positive += weight
+		case weight < 0:
+			negative -= weight
+		}
+	}
+
+	if positive+negative == 0 {
+		return SentimentNeutral
+	}
+	score := float64(positive-negative) / float64(positive+negative)
+	switch {
+	case score >= 1.0/3:
+		return SentimentPositive
+	case score <= -1.0/3:
+		return SentimentNegative
+	default:
+		return SentimentNeutral
+	}
+}
+
+// Phrases that mark a tone, checked in this order
+var tonePhrases = []struct {
+	tone    string
+	phrases []string
+}{
+	{ToneUrgent, []string{"breaking", "urgent", "alert", "emergency", "evacuate", "evacuation", "live updates", "red alert"}},
+	{ToneOpinion, []string{"opinion", "editorial", "op ed", "column", "commentary", "viewpoint", "my take"}},
+	{ToneAnalytical, []string{"analysis", "explained", "explainer", "what it means", "why", "study", "report finds", "data shows"}},
+}
+
+// ClassifyTone labels text urgent, opinion or analytical by cue phrases,
+// and factual otherwise
+func ClassifyTone(text string) string {
+	for _, group := range tonePhrases {
+		for _, phrase := range group.phrases {
+			if ContainsPhrase(text, phrase, false) {
+				return group.tone
+			}
+		}
+	}
+	return ToneFactual
+}
@@ -0,0 +1,50 @@
+package utils
+
+import "testing"
+
+func TestClassifySentiment(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"India wins historic series against Australia", SentimentPositive},
+		{"Five killed as flood hits Assam villages", SentimentNegative},
+		{"Parliament session to begin on Monday", SentimentNeutral},
+		{"Merger not approved by regulator", SentimentNegative},
+		{"Markets rise as auto stocks fall", SentimentNeutral},
+		{"", SentimentNeutral},
+	}
+
+	for _, tt := range tests {
+		if result := ClassifySentiment(tt.input); result != tt.expected {
+			t.Errorf("ClassifySentiment(%q) = %q, expected %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestClassifyTone(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Breaking: earthquake jolts Delhi, residents evacuate", ToneUrgent},
+		{"Opinion: Why the budget misses the point", ToneOpinion},
+		{"Explained: What the new tax regime means for you", ToneAnalytical},
+		{"RBI keeps repo rate unchanged at 6.5%", ToneFactual},
+	}
+
+	for _, tt := range tests {
+		if result := ClassifyTone(tt.input); result != tt.expected {
+			t.Errorf("ClassifyTone(%q) = %q, expected %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestIsSentimentAndTone(t *testing.T) {
+	if !IsSentiment(SentimentNegative) || IsSentiment("angry") || IsSentiment("") {
+		t.Error("IsSentiment() accepted or rejected the wrong values")
+	}
+	if !IsTone(ToneOpinion) || IsTone("sarcastic") || IsTone("") {
+		t.Error("IsTone() accepted or rejected the wrong values")
+	}
+}
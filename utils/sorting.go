@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"cmp"
 	"sort"
 	"strings"
 )
@@ -57,38 +58,51 @@ type SortConfig struct {
 	Order SortOrder
 }
 
+// LessOnTie orders articles that rank equally: newer first, then by ID, so
+// pages and cached results don't shuffle between requests
+func LessOnTie(a, b ArticleSortable) bool {
+	if da, db := a.GetPublicationDateUnix(), b.GetPublicationDateUnix(); da != db {
+		return da > db
+	}
+	return a.GetID() < b.GetID()
+}
+
 // SortArticles sorts a slice of articles based on the provided configuration
 // Uses generics to work with any slice that implements ArticleSortable
 func SortArticles[T ArticleSortable](articles []T, config SortConfig) {
 	sort.Slice(articles, func(i, j int) bool {
-		var less bool
+		var c int
 		switch config.Field {
-		case SortByDate:
-			less = articles[i].GetPublicationDateUnix() < articles[j].GetPublicationDateUnix()
 		case SortByScore:
-			less = articles[i].GetRelevanceScore() < articles[j].GetRelevanceScore()
+			c = cmp.Compare(articles[i].GetRelevanceScore(), articles[j].GetRelevanceScore())
 		case SortByDistance:
-			less = articles[i].GetDistance() < articles[j].GetDistance()
-		default:
-			less = articles[i].GetPublicationDateUnix() < articles[j].GetPublicationDateUnix()
+			c = cmp.Compare(articles[i].GetDistance(), articles[j].GetDistance())
+		default: // SortByDate
+			c = cmp.Compare(articles[i].GetPublicationDateUnix(), articles[j].GetPublicationDateUnix())
 		}
 
+		if c == 0 {
+			return LessOnTie(articles[i], articles[j])
+		}
 		// Reverse if descending
 		if config.Order == Descending {
-			return !less
+			return c > 0
 		}
-		return less
+		return c < 0
 	})
 }
 
 // SortByScoreMap sorts articles using a precomputed score map (for search relevance)
 func SortByScoreMap[T ArticleSortable](articles []T, scores map[string]float64, order SortOrder) {
 	sort.Slice(articles, func(i, j int) bool {
-		less := scores[articles[i].GetID()] < scores[articles[j].GetID()]
+		c := cmp.Compare(scores[articles[i].GetID()], scores[articles[j].GetID()])
+		if c == 0 {
+			return LessOnTie(articles[i], articles[j])
+		}
 		if order == Descending {
-			return !less
+			return c > 0
 		}
-		return less
+		return c < 0
 	})
 }
 
@@ -119,7 +133,11 @@ func SortByDistanceFrom[T any, PT interface {
 	}
 	// Sort by distance ascending (nearest first)
 	sort.Slice(items, func(i, j int) bool {
-		return PT(&items[i]).GetDistance() < PT(&items[j]).GetDistance()
+		a, b := PT(&items[i]), PT(&items[j])
+		if a.GetDistance() != b.GetDistance() {
+			return a.GetDistance() < b.GetDistance()
+		}
+		return LessOnTie(a, b)
 	})
 }
 
@@ -133,12 +151,11 @@ func FilterByDistance[T any, PT interface {
 	*T
 	DistanceSortable
 }](items []T, refLat, refLon, radius float64) []T {
+	distances := distancesFrom[T, PT](items, refLat, refLon)
 	filtered := make([]T, 0, len(items))
 	for i := range items {
-		ptr := PT(&items[i])
-		dist := HaversineDistance(refLat, refLon, ptr.GetLatitude(), ptr.GetLongitude())
-		if dist <= radius {
-			ptr.SetDistance(dist)
+		if distances[i] <= radius {
+			PT(&items[i]).SetDistance(distances[i])
 			filtered = append(filtered, items[i])
 		}
 	}
@@ -150,18 +167,35 @@ func FilterByDistanceWithPredicate[T any, PT interface {
 	*T
 	DistanceSortable
 }](items []T, refLat, refLon, radius float64, predicate func(PT) bool) []T {
+	distances := distancesFrom[T, PT](items, refLat, refLon)
 	filtered := make([]T, 0, len(items))
 	for i := range items {
 		ptr := PT(&items[i])
-		dist := HaversineDistance(refLat, refLon, ptr.GetLatitude(), ptr.GetLongitude())
-		if dist <= radius && predicate(ptr) {
-			ptr.SetDistance(dist)
+		if distances[i] <= radius && predicate(ptr) {
+			ptr.SetDistance(distances[i])
 			filtered = append(filtered, items[i])
 		}
 	}
 	return filtered
 }
 
+// distancesFrom gathers the items' coordinates into parallel slices and
+// computes every distance from the reference point in one batch
+func distancesFrom[T any, PT interface {
+	*T
+	DistanceSortable
+}](items []T, refLat, refLon float64) []float64 {
+	lats := make([]float64, len(items))
+	lons := make([]float64, len(items))
+	for i := range items {
+		ptr := PT(&items[i])
+		lats[i], lons[i] = ptr.GetLatitude(), ptr.GetLongitude()
+	}
+	distances := make([]float64, len(items))
+	HaversineDistances(refLat, refLon, lats, lons, distances)
+	return distances
+}
+
 // CalculateDistance calculates and sets distance for a single item
 func CalculateDistance[T any, PT interface {
 	*T
@@ -186,7 +220,9 @@ type SearchSortable interface {
 
 // SortBySearchRelevance sorts articles by combination of relevance_score and text matching
 // As per requirement: "rank by a combination of relevance_score and text matching score"
-func SortBySearchRelevance[T SearchSortable](items []T, query string) {
+// Each item's score is multiplied by its entry in factors (keyed by ID), if any,
+// e.g. its source's ReliabilityFactor.
+func SortBySearchRelevance[T SearchSortable](items []T, query string, factors map[string]float64) {
 	scores := make(map[string]float64, len(items))
 	queryLower := strings.ToLower(query)
 
@@ -194,10 +230,71 @@ func SortBySearchRelevance[T SearchSortable](items []T, query string) {
 		textScore := calculateTextMatchScore(items[i], queryLower)
 		relevanceScore := items[i].GetRelevanceScore()
 		// Combine: text matching weight + relevance score weight
-		scores[items[i].GetID()] = textScore*WeightTextScore + relevanceScore*WeightRelevanceScore
+		score := textScore*WeightTextScore + relevanceScore*WeightRelevanceScore
+		if factor, ok := factors[items[i].GetID()]; ok {
+			score *= factor
+		}
+		scores[items[i].GetID()] = score
+	}
+
+	SortByScoreMap(items, scores, Descending)
+}
+
+// =============================================================================
+// Hybrid Ranking
+// =============================================================================
+
+// HybridWeights configures how search signals are combined in hybrid mode
+type HybridWeights struct {
+	Text      float64 // Weight for keyword text matching
+	Relevance float64 // Weight for base relevance score
+	Semantic  float64 // Weight for embedding cosine similarity
+}
+
+// HybridScore holds the per-signal scores for a single item
+type HybridScore struct {
+	Text      float64
+	Relevance float64
+	Semantic  float64
+	Combined  float64
+}
+
+// SortByHybridRelevance ranks items by a weighted mix of text matching, relevance
+// and semantic similarity (keyed by item ID), each signal normalized across the
+// items with the given method first. Returns the per-signal breakdown.
+func SortByHybridRelevance[T SearchSortable](items []T, query string, similarity map[string]float64, weights HybridWeights, normalization string) map[string]HybridScore {
+	queryLower := strings.ToLower(query)
+	text := make([]float64, len(items))
+	relevance := make([]float64, len(items))
+	semantic := make([]float64, len(items))
+	for i := range items {
+		text[i] = calculateTextMatchScore(items[i], queryLower)
+		relevance[i] = items[i].GetRelevanceScore()
+		semantic[i] = similarity[items[i].GetID()]
+	}
+	NormalizeScores(text, normalization)
+	NormalizeScores(relevance, normalization)
+	NormalizeScores(semantic, normalization)
+
+	breakdown := make(map[string]HybridScore, len(items))
+	scores := make(map[string]float64, len(items))
+	for i := range items {
+		id := items[i].GetID()
+		score := HybridScore{
+			Text:      text[i],
+			Relevance: relevance[i],
+			Semantic:  semantic[i],
+		}
+		score.Combined = score.Text*weights.Text +
+			score.Relevance*weights.Relevance +
+			score.Semantic*weights.Semantic
+
+		breakdown[id] = score
+		scores[id] = score.Combined
 	}
 
 	SortByScoreMap(items, scores, Descending)
+	return breakdown
 }
 
 // calculateTextMatchScore calculates how well title/description matches the query
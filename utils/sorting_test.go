@@ -62,6 +62,33 @@ func TestSortArticles_ByScore(t *testing.T) {
 	}
 }
 
+func TestSortArticles_Ties(t *testing.T) {
+	// Equal scores go newest first, then by ID, whatever the input order
+	expected := []string{"new-a", "new-b", "old", "top"}
+	inputs := [][]mockArticle{
+		{{id: "old", score: 0.5, pubDateUnix: 100}, {id: "new-b", score: 0.5, pubDateUnix: 200}, {id: "new-a", score: 0.5, pubDateUnix: 200}, {id: "top", score: 0.1}},
+		{{id: "top", score: 0.1}, {id: "new-a", score: 0.5, pubDateUnix: 200}, {id: "old", score: 0.5, pubDateUnix: 100}, {id: "new-b", score: 0.5, pubDateUnix: 200}},
+	}
+
+	for _, articles := range inputs {
+		SortArticles(articles, SortScoreDesc)
+		for i, id := range expected {
+			if articles[i].id != id {
+				t.Errorf("SortScoreDesc position %d = %s, expected %s", i, articles[i].id, id)
+			}
+		}
+
+		scores := map[string]float64{"top": 1, "old": 0.5, "new-a": 0.5, "new-b": 0.5}
+		SortByScoreMap(articles, scores, Descending)
+		order := []string{"top", "new-a", "new-b", "old"}
+		for i, id := range order {
+			if articles[i].id != id {
+				t.Errorf("SortByScoreMap position %d = %s, expected %s", i, articles[i].id, id)
+			}
+		}
+	}
+}
+
 func TestSortByDistanceFrom(t *testing.T) {
 	// Reference point: San Francisco
 	refLat, refLon := 37.7749, -122.4194
@@ -127,13 +154,19 @@ func TestSortBySearchRelevance(t *testing.T) {
 		{id: "both-match", title: "Climate Summit", description: "Leaders discuss climate", score: 0.3},
 	}
 
-	SortBySearchRelevance(articles, "climate")
+	SortBySearchRelevance(articles, "climate", nil)
 
 	// "both-match" should be first (matches in title AND description)
 	// Even though "no-match" has higher base score, text matching matters more
 	if articles[0].id != "both-match" {
 		t.Errorf("Expected 'both-match' first, got %s", articles[0].id)
 	}
+
+	// A factor (e.g. source reliability) can reorder close matches
+	SortBySearchRelevance(articles, "climate", map[string]float64{"both-match": 0.5})
+	if articles[0].id != "title-match" {
+		t.Errorf("Expected 'title-match' first with 'both-match' demoted, got %s", articles[0].id)
+	}
 }
 
 func TestCalculateTextMatchScore(t *testing.T) {
@@ -191,3 +224,60 @@ func TestCalculateTextMatchScore(t *testing.T) {
 		})
 	}
 }
+
+func TestSortByHybridRelevance(t *testing.T) {
+	articles := []mockArticle{
+		{id: "keyword", title: "EV battery plants expand", description: "Battery news", score: 0.4},
+		{id: "semantic", title: "Electric vehicle gigafactory opens", description: "Cell production", score: 0.4},
+		{id: "unrelated", title: "Cricket scores", description: "Match report", score: 0.9},
+	}
+	similarity := map[string]float64{"keyword": 0.6, "semantic": 0.9, "unrelated": 0.1}
+
+	t.Run("Semantic weight surfaces paraphrased match", func(t *testing.T) {
+		items := append([]mockArticle(nil), articles...)
+		breakdown := SortByHybridRelevance(items, "ev battery plants", similarity,
+			HybridWeights{Text: 0.2, Relevance: 0.1, Semantic: 0.7}, NormalizationNone)
+
+		if items[len(items)-1].id != "unrelated" {
+			t.Errorf("Expected 'unrelated' last, got %s", items[len(items)-1].id)
+		}
+		if breakdown["semantic"].Semantic != 0.9 {
+			t.Errorf("Breakdown semantic = %v, expected 0.9", breakdown["semantic"].Semantic)
+		}
+		if breakdown["keyword"].Text <= breakdown["semantic"].Text {
+			t.Error("Expected keyword article to have a higher text score")
+		}
+	})
+
+	t.Run("Zero semantic weight matches keyword ranking", func(t *testing.T) {
+		items := append([]mockArticle(nil), articles...)
+		breakdown := SortByHybridRelevance(items, "ev battery plants", similarity,
+			HybridWeights{Text: WeightTextScore, Relevance: WeightRelevanceScore}, NormalizationNone)
+
+		if items[0].id != "keyword" {
+			t.Errorf("Expected 'keyword' first, got %s", items[0].id)
+		}
+		for id, score := range breakdown {
+			expected := score.Text*WeightTextScore + score.Relevance*WeightRelevanceScore
+			if score.Combined != expected {
+				t.Errorf("Combined score for %s = %v, expected %v", id, score.Combined, expected)
+			}
+		}
+	})
+
+	t.Run("Min-max puts every signal on the same scale", func(t *testing.T) {
+		items := append([]mockArticle(nil), articles...)
+		breakdown := SortByHybridRelevance(items, "ev battery plants", similarity,
+			HybridWeights{Text: 0.4, Relevance: 0.2, Semantic: 0.4}, NormalizationMinMax)
+
+		if items[0].id != "keyword" || items[len(items)-1].id != "unrelated" {
+			t.Errorf("Unexpected order: %s first, %s last", items[0].id, items[len(items)-1].id)
+		}
+		if breakdown["semantic"].Semantic != 1 || breakdown["unrelated"].Semantic != 0 {
+			t.Errorf("Semantic not rescaled: %+v", breakdown)
+		}
+		if breakdown["unrelated"].Relevance != 1 || breakdown["keyword"].Relevance != 0 {
+			t.Errorf("Relevance not rescaled: %+v", breakdown)
+		}
+	})
+}
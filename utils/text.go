@@ -0,0 +1,255 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// =============================================================================
+// Text Similarity Utilities
+// =============================================================================
+
+// NormalizeTitle lowercases text and collapses punctuation and whitespace
+// runs into single spaces, so headlines differing only in formatting compare equal
+func NormalizeTitle(title string) string {
+	return normalizeWords(strings.ToLower(title))
+}
+
+// normalizeWords collapses punctuation and whitespace runs into single spaces
+func normalizeWords(text string) string {
+	var b strings.Builder
+	space := true
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			space = false
+		} else if !space {
+			b.WriteByte(' ')
+			space = true
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// Shingles returns the distinct k-character shingles of the normalized text.
+// Text shorter than k yields a single shingle; empty text yields none.
+func Shingles(text string, k int) []string {
+	runes := []rune(NormalizeTitle(text))
+	if len(runes) == 0 {
+		return nil
+	}
+	if len(runes) <= k {
+		return []string{string(runes)}
+	}
+
+	seen := make(map[string]bool, len(runes)-k+1)
+	shingles := make([]string, 0, len(runes)-k+1)
+	for i := 0; i+k <= len(runes); i++ {
+		shingle := string(runes[i : i+k])
+		if !seen[shingle] {
+			seen[shingle] = true
+			shingles = append(shingles, shingle)
+		}
+	}
+	return shingles
+}
+
+// ContainsPhrase reports whether phrase occurs in text as whole words,
+// ignoring punctuation and whitespace differences. Case is ignored unless
+// caseSensitive is set, which tells names like "Apple" from "apple".
+func ContainsPhrase(text, phrase string, caseSensitive bool) bool {
+	if !caseSensitive {
+		text, phrase = strings.ToLower(text), strings.ToLower(phrase)
+	}
+	phrase = normalizeWords(phrase)
+	if phrase == "" {
+		return false
+	}
+	return strings.Contains(" "+normalizeWords(text)+" ", " "+phrase+" ")
+}
+
+// maxTopicWords bounds a topic name; longer ones are descriptions, not topics
+const maxTopicWords = 6
+
+// NormalizeTopic lowercases a topic and collapses punctuation, so "IPL-2025"
+// and "ipl 2025" are one topic. Names of more than maxTopicWords words
+// normalize to "".
+func NormalizeTopic(topic string) string {
+	topic = NormalizeTitle(topic)
+	if strings.Count(topic, " ") >= maxTopicWords {
+		return ""
+	}
+	return topic
+}
+
+// =============================================================================
+// Keyword Terms
+// =============================================================================
+
+// questionWords carry no topic, so they are dropped from keyword terms
+var questionWords = map[string]bool{
+	"what": true, "who": true, "whom": true, "whose": true, "when": true, "where": true,
+	"why": true, "how": true, "which": true, "did": true, "does": true, "do": true,
+	"was": true, "were": true, "has": true, "have": true, "had": true, "will": true,
+	"would": true, "can": true, "could": true, "should": true, "the": true, "and": true,
+	"for": true, "with": true, "about": true, "from": true, "that": true, "this": true,
+	"there": true, "their": true, "they": true, "are": true, "any": true, "tell": true,
+	"know": true, "news": true, "latest": true, "happened": true, "happening": true,
+}
+
+// KeywordTerms returns the distinct normalized words of text worth matching
+// articles on: at least 3 characters (or digits, like years) and not a
+// question or filler word
+func KeywordTerms(text string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, word := range strings.Fields(NormalizeTitle(text)) {
+		if seen[word] || questionWords[word] {
+			continue
+		}
+		if len([]rune(word)) < 3 && strings.IndexFunc(word, func(r rune) bool { return !unicode.IsDigit(r) }) >= 0 {
+			continue
+		}
+		seen[word] = true
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+// TermCoverage is the share of terms that occur as whole words in text
+func TermCoverage(text string, terms []string) float64 {
+	if len(terms) == 0 {
+		return 0
+	}
+	words := " " + NormalizeTitle(text) + " "
+	matched := 0
+	for _, term := range terms {
+		if strings.Contains(words, " "+term+" ") {
+			matched++
+		}
+	}
+	return float64(matched) / float64(len(terms))
+}
+
+// =============================================================================
+// Generic Query Detection
+// =============================================================================
+
+// genericQueryWords ask for news in general without naming a topic. Queries
+// are normalized first, so "what's" arrives as "what s". "Top" is left out:
+// "top stories" asks for the highest-scoring news.
+var genericQueryWords = map[string]bool{
+	"news": true, "latest": true, "recent": true, "new": true, "breaking": true,
+	"headlines": true, "headline": true, "stories": true, "story": true, "articles": true,
+	"updates": true, "update": true, "today": true, "todays": true, "tonight": true, "now": true,
+	"current": true, "happening": true, "happened": true, "going": true, "on": true, "up": true,
+	"what": true, "whats": true, "s": true, "is": true, "are": true, "there": true, "any": true,
+	"anything": true, "everything": true, "show": true, "me": true, "give": true, "get": true,
+	"tell": true, "the": true, "a": true, "all": true, "some": true, "of": true, "in": true,
+	"this": true, "morning": true, "evening": true, "please": true,
+}
+
+// genericQueryTypoMinLength is the shortest word matched with one typo
+const genericQueryTypoMinLength = 5
+
+// IsGenericQuery reports whether query only asks for the news in general
+// ("show me the news", "what's happening today"), ignoring case, punctuation
+// and single-letter typos in longer words ("hapening", "headlnes")
+func IsGenericQuery(query string) bool {
+	words := strings.Fields(NormalizeTitle(query))
+	if len(words) == 0 {
+		return false
+	}
+	for _, word := range words {
+		if !isGenericQueryWord(word) {
+			return false
+		}
+	}
+	return true
+}
+
+// isGenericQueryWord matches word against genericQueryWords, allowing one
+// edit in words long enough not to turn into another word by it
+func isGenericQueryWord(word string) bool {
+	if genericQueryWords[word] {
+		return true
+	}
+	if len([]rune(word)) < genericQueryTypoMinLength {
+		return false
+	}
+	for generic := range genericQueryWords {
+		if len([]rune(generic)) >= genericQueryTypoMinLength && editDistanceAtMostOne(word, generic) {
+			return true
+		}
+	}
+	return false
+}
+
+// editDistanceAtMostOne reports whether a and b differ by at most one
+// inserted, deleted or substituted rune
+func editDistanceAtMostOne(a, b string) bool {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra
+	}
+	if len(ra)-len(rb) > 1 {
+		return false
+	}
+
+	i, j, edits := 0, 0, 0
+	for i < len(ra) && j < len(rb) {
+		if ra[i] == rb[j] {
+			i++
+			j++
+			continue
+		}
+		edits++
+		if edits > 1 {
+			return false
+		}
+		if len(ra) == len(rb) {
+			j++ // substitution
+		}
+		i++
+	}
+	return edits+len(ra)-i <= 1
+}
+
+// =============================================================================
+// PII Scrubbing
+// =============================================================================
+
+// piiPatterns replace personal data with a placeholder. Card numbers and IP
+// addresses go before phone numbers so their digit runs aren't misread.
+// Matches with fewer than minDigits digits (e.g. dates) are left alone.
+var piiPatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+	minDigits   int
+}{
+	{regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), "[email]", 0},
+	{regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`), "[card]", 0},
+	{regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`), "[ip]", 0},
+	{regexp.MustCompile(`\+?\(?\d[\d ().-]{8,}\d`), "[phone]", 10},
+}
+
+// ScrubPII masks email addresses, card and phone numbers, and IPv4
+// addresses in text
+func ScrubPII(text string) string {
+	for _, p := range piiPatterns {
+		text = p.pattern.ReplaceAllStringFunc(text, func(match string) string {
+			digits := 0
+			for _, r := range match {
+				if r >= '0' && r <= '9' {
+					digits++
+				}
+			}
+			if digits < p.minDigits {
+				return match
+			}
+			return p.replacement
+		})
+	}
+	return text
+}
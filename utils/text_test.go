@@ -0,0 +1,196 @@
+package utils
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeTitle(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Hello, World!", "hello world"},
+		{"  RBI cuts   repo rate -- again ", "rbi cuts repo rate again"},
+		{"IPL 2025: CSK vs. MI", "ipl 2025 csk vs mi"},
+		{"!!!", ""},
+	}
+
+	for _, tt := range tests {
+		if result := NormalizeTitle(tt.input); result != tt.expected {
+			t.Errorf("NormalizeTitle(%q) = %q, expected %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestShingles(t *testing.T) {
+	if result := Shingles("A-B-C", 5); !reflect.DeepEqual(result, []string{"a b c"}) {
+		t.Errorf("Shingles() of short text = %v", result)
+	}
+	if result := Shingles("...", 5); result != nil {
+		t.Errorf("Shingles() of empty text = %v, expected nil", result)
+	}
+
+	expected := []string{"aaa", "aab", "aba"}
+	if result := Shingles("aaaaaba", 3); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Shingles() = %v, expected %v", result, expected)
+	}
+
+	// Formatting differences don't affect similarity
+	a := Shingles("Markets rally as Sensex gains 500 points", 5)
+	b := Shingles("Markets Rally as Sensex Gains 500 Points!", 5)
+	if sim := JaccardSimilarity(a, b); sim != 1 {
+		t.Errorf("JaccardSimilarity() of reformatted titles = %v, expected 1", sim)
+	}
+}
+
+func TestContainsPhrase(t *testing.T) {
+	text := "Apple unveils new iPhone; Tata-Motors shares jump"
+	tests := []struct {
+		phrase        string
+		caseSensitive bool
+		expected      bool
+	}{
+		{"apple", false, true},
+		{"apple", true, false},
+		{"Apple", true, true},
+		{"tata motors", false, true},
+		{"phone", false, false},
+		{"new iphone", false, true},
+		{"!!", false, false},
+	}
+
+	for _, tt := range tests {
+		if result := ContainsPhrase(text, tt.phrase, tt.caseSensitive); result != tt.expected {
+			t.Errorf("ContainsPhrase(%q, %v) = %v, expected %v", tt.phrase, tt.caseSensitive, result, tt.expected)
+		}
+	}
+}
+
+func TestNormalizeTopic(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"IPL-2025", "ipl 2025"},
+		{"  Reserve Bank of India ", "reserve bank of india"},
+		{"a very long description that is not really a topic", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if result := NormalizeTopic(tt.input); result != tt.expected {
+			t.Errorf("NormalizeTopic(%q) = %q, expected %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestKeywordTerms(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected []string
+	}{
+		{"What did the RBI decide about the repo rate?", []string{"rbi", "decide", "repo", "rate"}},
+		{"Who won IPL 2025's opening match, and who won?", []string{"won", "ipl", "2025", "opening", "match"}},
+		{"How is the UP police handling it?", []string{"police", "handling"}},
+		{"what happened?", nil},
+	}
+
+	for _, tt := range tests {
+		if result := KeywordTerms(tt.text); !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("KeywordTerms(%q) = %q, expected %q", tt.text, result, tt.expected)
+		}
+	}
+}
+
+func TestTermCoverage(t *testing.T) {
+	terms := []string{"repo", "rate", "rbi"}
+	tests := []struct {
+		text     string
+		expected float64
+	}{
+		{"RBI cuts repo rate by 25 bps", 1},
+		{"Repo-rate unchanged", 2.0 / 3},
+		{"Reporters gather at the rates panel", 0},
+	}
+
+	for _, tt := range tests {
+		if result := TermCoverage(tt.text, terms); math.Abs(result-tt.expected) > 1e-9 {
+			t.Errorf("TermCoverage(%q) = %v, expected %v", tt.text, result, tt.expected)
+		}
+	}
+	if result := TermCoverage("anything", nil); result != 0 {
+		t.Errorf("TermCoverage() without terms = %v, expected 0", result)
+	}
+}
+
+func TestIsGenericQuery(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected bool
+	}{
+		{"news", true},
+		{"Show me the news", true},
+		{"What's happening today?", true},
+		{"latest headlines", true},
+		{"what is going on", true},
+		{"Whats hapening", true},
+		{"latest headlnes this morning", true},
+		{"top stories", false},
+		{"", false},
+		{"?!", false},
+		{"sports news", false},
+		{"what's happening in delhi", false},
+		{"new iphone", false},
+		{"news from reuters", false},
+		{"newt", false},
+	}
+
+	for _, tt := range tests {
+		if result := IsGenericQuery(tt.query); result != tt.expected {
+			t.Errorf("IsGenericQuery(%q) = %v, expected %v", tt.query, result, tt.expected)
+		}
+	}
+}
+
+func TestEditDistanceAtMostOne(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"latest", "latest", true},
+		{"lastest", "latest", true},
+		{"latst", "latest", true},
+		{"lateset", "latest", true},
+		{"lotest", "latest", true},
+		{"ltaest", "latest", false},
+		{"late", "latest", false},
+		{"", "a", true},
+	}
+
+	for _, tt := range tests {
+		if result := editDistanceAtMostOne(tt.a, tt.b); result != tt.expected {
+			t.Errorf("editDistanceAtMostOne(%q, %q) = %v, expected %v", tt.a, tt.b, result, tt.expected)
+		}
+	}
+}
+
+func TestScrubPII(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"mail jane.doe+news@example.co.in now", "mail [email] now"},
+		{"call +91 98765 43210 or (415) 555-0132", "call [phone] or [phone]"},
+		{"card 4111 1111 1111 1111 expired", "card [card] expired"},
+		{"from 192.168.1.20 and 10.100.200.250", "from [ip] and [ip]"},
+		{"Today is 2025-03-26T04:46:55, IPL 2025 scores 187/4", "Today is 2025-03-26T04:46:55, IPL 2025 scores 187/4"},
+	}
+
+	for _, tt := range tests {
+		if result := ScrubPII(tt.input); result != tt.expected {
+			t.Errorf("ScrubPII(%q) = %q, expected %q", tt.input, result, tt.expected)
+		}
+	}
+}
@@ -19,6 +19,16 @@ func ComputeTrendingScore(eventCount int, totalWeight float64, recencyFactor flo
 	return float64(eventCount) * avgWeight * recencyFactor
 }
 
+// IsTrendingAnomaly reports whether an article's engagement looks gamed: at
+// least minEvents events, averaging more than maxEventsPerUser per distinct
+// user. A non-positive maxEventsPerUser disables the check.
+func IsTrendingAnomaly(events, users, minEvents int, maxEventsPerUser float64) bool {
+	if maxEventsPerUser <= 0 || events < minEvents {
+		return false
+	}
+	return float64(events) > float64(max(users, 1))*maxEventsPerUser
+}
+
 // CalculateRecencyFactor calculates a decay factor based on time
 // More recent events get higher scores
 func CalculateRecencyFactor(hoursAgo float64) float64 {
@@ -26,3 +36,68 @@ func CalculateRecencyFactor(hoursAgo float64) float64 {
 	// Half-life of 12 hours
 	return math.Exp(-hoursAgo / 12.0)
 }
+
+// CalculateFreshnessFactor decays exponentially with age relative to a window,
+// reaching 1/e after one full window
+func CalculateFreshnessFactor(hoursAgo, windowHours float64) float64 {
+	if windowHours <= 0 {
+		return 0
+	}
+	if hoursAgo < 0 {
+		hoursAgo = 0
+	}
+	return math.Exp(-hoursAgo / windowHours)
+}
+
+// Proximity boost curves for trending
+const (
+	ProximityCurveLinear      = "linear"      // falls in a straight line to no boost at ScaleKm
+	ProximityCurveExponential = "exponential" // the extra boost halves every ScaleKm
+	ProximityCurveNone        = "none"        // distance doesn't affect the score
+)
+
+// ProximityCurve shapes the score multiplier nearby articles get in trending
+type ProximityCurve struct {
+	Kind     string
+	MaxBoost float64 // multiplier at distance 0; 1 or less disables the boost
+	ScaleKm  float64
+}
+
+// IsProximityCurve reports whether kind is a known proximity curve
+func IsProximityCurve(kind string) bool {
+	switch kind {
+	case ProximityCurveLinear, ProximityCurveExponential, ProximityCurveNone:
+		return true
+	default:
+		return false
+	}
+}
+
+// Boost returns the score multiplier for an article distanceKm away, falling
+// continuously from MaxBoost at 0 towards 1
+func (c ProximityCurve) Boost(distanceKm float64) float64 {
+	if c.MaxBoost <= 1 || c.ScaleKm <= 0 {
+		return 1
+	}
+	if distanceKm < 0 {
+		distanceKm = 0
+	}
+
+	extra := c.MaxBoost - 1
+	switch c.Kind {
+	case ProximityCurveLinear:
+		return 1 + extra*math.Max(0, 1-distanceKm/c.ScaleKm)
+	case ProximityCurveExponential:
+		return 1 + extra*math.Exp2(-distanceKm/c.ScaleKm)
+	default:
+		return 1
+	}
+}
+
+// BlendRelevance mixes a static relevance score with normalized engagement (0..1).
+// weight is the share given to engagement and is clamped to [0, 1].
+func BlendRelevance(baseScore, engagement, weight float64) float64 {
+	weight = math.Max(0, math.Min(1, weight))
+	engagement = math.Max(0, math.Min(1, engagement))
+	return baseScore*(1-weight) + engagement*weight
+}
@@ -102,3 +102,128 @@ func TestCalculateRecencyFactor(t *testing.T) {
 		}
 	})
 }
+
+func TestBlendRelevance(t *testing.T) {
+	tests := []struct {
+		name       string
+		base       float64
+		engagement float64
+		weight     float64
+		expected   float64
+	}{
+		{name: "Zero weight keeps base score", base: 0.8, engagement: 1.0, weight: 0, expected: 0.8},
+		{name: "Full weight uses engagement", base: 0.8, engagement: 0.2, weight: 1, expected: 0.2},
+		{name: "Even blend", base: 0.6, engagement: 1.0, weight: 0.5, expected: 0.8},
+		{name: "Weight clamped above one", base: 0.6, engagement: 0.4, weight: 2, expected: 0.4},
+		{name: "Engagement clamped", base: 0.5, engagement: 3, weight: 0.5, expected: 0.75},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := BlendRelevance(tt.base, tt.engagement, tt.weight)
+			if math.Abs(result-tt.expected) > 1e-9 {
+				t.Errorf("BlendRelevance() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCalculateFreshnessFactor(t *testing.T) {
+	tests := []struct {
+		name     string
+		hoursAgo float64
+		window   float64
+		expected float64
+	}{
+		{"Just published", 0, 24, 1.0},
+		{"One window old", 24, 24, math.Exp(-1)},
+		{"Two windows old", 48, 24, math.Exp(-2)},
+		{"Future date clamps to now", -5, 24, 1.0},
+		{"Zero window", 10, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CalculateFreshnessFactor(tt.hoursAgo, tt.window)
+			if math.Abs(result-tt.expected) > 1e-9 {
+				t.Errorf("CalculateFreshnessFactor(%v, %v) = %v, expected %v",
+					tt.hoursAgo, tt.window, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProximityCurveBoost(t *testing.T) {
+	linear := ProximityCurve{Kind: ProximityCurveLinear, MaxBoost: 1.5, ScaleKm: 20}
+	exponential := ProximityCurve{Kind: ProximityCurveExponential, MaxBoost: 1.5, ScaleKm: 10}
+
+	tests := []struct {
+		name     string
+		curve    ProximityCurve
+		distance float64
+		expected float64
+	}{
+		{"Linear at origin", linear, 0, 1.5},
+		{"Linear halfway", linear, 10, 1.25},
+		{"Linear at scale", linear, 20, 1.0},
+		{"Linear beyond scale", linear, 50, 1.0},
+		{"Exponential at origin", exponential, 0, 1.5},
+		{"Exponential one half-distance", exponential, 10, 1.25},
+		{"Exponential two half-distances", exponential, 20, 1.125},
+		{"Negative distance clamps", exponential, -5, 1.5},
+		{"None", ProximityCurve{Kind: ProximityCurveNone, MaxBoost: 1.5, ScaleKm: 10}, 0, 1.0},
+		{"No boost configured", ProximityCurve{Kind: ProximityCurveLinear, MaxBoost: 1, ScaleKm: 10}, 0, 1.0},
+		{"Zero scale", ProximityCurve{Kind: ProximityCurveLinear, MaxBoost: 2, ScaleKm: 0}, 0, 1.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.curve.Boost(tt.distance)
+			if math.Abs(result-tt.expected) > 1e-9 {
+				t.Errorf("Boost(%v) = %v, expected %v", tt.distance, result, tt.expected)
+			}
+		})
+	}
+
+	// No cliff: the boost never increases with distance
+	for _, curve := range []ProximityCurve{linear, exponential} {
+		prev := curve.Boost(0)
+		for d := 0.5; d <= 60; d += 0.5 {
+			current := curve.Boost(d)
+			if current > prev {
+				t.Fatalf("%s boost increased from %v to %v at %vkm", curve.Kind, prev, current, d)
+			}
+			if prev-current > 0.05 {
+				t.Fatalf("%s boost dropped by %v at %vkm", curve.Kind, prev-current, d)
+			}
+			prev = current
+		}
+	}
+}
+
+func TestIsTrendingAnomaly(t *testing.T) {
+	tests := []struct {
+		name      string
+		events    int
+		users     int
+		minEvents int
+		perUser   float64
+		expected  bool
+	}{
+		{"Many users", 500, 200, 20, 10, false},
+		{"One user refreshing", 500, 1, 20, 10, true},
+		{"Below the minimum", 15, 1, 20, 10, false},
+		{"At the threshold", 100, 10, 20, 10, false},
+		{"No users recorded", 30, 0, 20, 10, true},
+		{"Disabled", 500, 1, 20, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTrendingAnomaly(tt.events, tt.users, tt.minEvents, tt.perUser); got != tt.expected {
+				t.Errorf("IsTrendingAnomaly(%d, %d, %d, %v) = %v, expected %v",
+					tt.events, tt.users, tt.minEvents, tt.perUser, got, tt.expected)
+			}
+		})
+	}
+}
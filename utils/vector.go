@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"math"
+)
+
+// =============================================================================
+// Vector Similarity Utilities
+// =============================================================================
+
+// JaccardSimilarity returns |a ∩ b| / |a ∪ b| for two sets of strings (0 to 1)
+func JaccardSimilarity(a, b []string) float64 {
+	setA := make(map[string]bool, len(a))
+	for _, item := range a {
+		setA[item] = true
+	}
+	setB := make(map[string]bool, len(b))
+	for _, item := range b {
+		setB[item] = true
+	}
+
+	intersection := 0
+	for item := range setB {
+		if setA[item] {
+			intersection++
+		}
+	}
+	union := len(setA) + len(setB) - intersection
+	if union == 0 {
+		return 0
+	}
+	return float64(intersection) / float64(union)
+}
+
+// CosineSimilarity returns the cosine similarity of two vectors (-1 to 1)
+// Returns 0 when the vectors differ in length or either has zero magnitude
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// BlendVector folds v, weighing weight, into avg, a weighted average of
+// vectors weighing total, and returns the new average and its weight. An
+// empty or mismatched avg, or a non-positive total, starts over from v.
+func BlendVector(avg []float32, total float64, v []float32, weight float64) ([]float32, float64) {
+	if len(avg) != len(v) || total <= 0 {
+		return append([]float32(nil), v...), weight
+	}
+
+	blended := make([]float32, len(v))
+	sum := total + weight
+	for i := range v {
+		blended[i] = float32((float64(avg[i])*total + float64(v[i])*weight) / sum)
+	}
+	return blended, sum
+}
+
+// HalfLifeDecay is the share of a weight left after hours, halving every
+// halfLife hours. A non-positive halfLife never decays.
+func HalfLifeDecay(hours, halfLife float64) float64 {
+	if halfLife <= 0 || hours <= 0 {
+		return 1
+	}
+	return math.Pow(0.5, hours/halfLife)
+}
@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCosineSimilarity(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        []float32
+		b        []float32
+		expected float64
+	}{
+		{"Identical vectors", []float32{1, 2, 3}, []float32{1, 2, 3}, 1.0},
+		{"Scaled vectors", []float32{1, 2, 3}, []float32{2, 4, 6}, 1.0},
+		{"Orthogonal vectors", []float32{1, 0}, []float32{0, 1}, 0.0},
+		{"Opposite vectors", []float32{1, 1}, []float32{-1, -1}, -1.0},
+		{"Length mismatch", []float32{1, 2}, []float32{1, 2, 3}, 0.0},
+		{"Zero vector", []float32{0, 0}, []float32{1, 1}, 0.0},
+		{"Empty vectors", []float32{}, []float32{}, 0.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CosineSimilarity(tt.a, tt.b)
+			if math.Abs(result-tt.expected) > 1e-6 {
+				t.Errorf("CosineSimilarity() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestJaccardSimilarity(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        []string
+		b        []string
+		expected float64
+	}{
+		{"Identical sets", []string{"a", "b"}, []string{"b", "a"}, 1.0},
+		{"Disjoint sets", []string{"a"}, []string{"b"}, 0.0},
+		{"Partial overlap", []string{"a", "b", "c"}, []string{"b", "c", "d"}, 0.5},
+		{"Duplicates ignored", []string{"a", "a", "b"}, []string{"a", "b", "b"}, 1.0},
+		{"Both empty", nil, nil, 0.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := JaccardSimilarity(tt.a, tt.b)
+			if math.Abs(result-tt.expected) > 1e-9 {
+				t.Errorf("JaccardSimilarity() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBlendVector(t *testing.T) {
+	tests := []struct {
+		name          string
+		avg           []float32
+		total         float64
+		v             []float32
+		weight        float64
+		expected      []float32
+		expectedTotal float64
+	}{
+		{"Empty average starts over", nil, 0, []float32{1, 2}, 2, []float32{1, 2}, 2},
+		{"Weighted mean", []float32{1, 0}, 1, []float32{0, 1}, 3, []float32{0.25, 0.75}, 4},
+		{"Decayed to nothing starts over", []float32{1, 0}, 0, []float32{0, 1}, 1, []float32{0, 1}, 1},
+		{"Dimension change starts over", []float32{1, 0, 0}, 5, []float32{0, 1}, 1, []float32{0, 1}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, total := BlendVector(tt.avg, tt.total, tt.v, tt.weight)
+			if math.Abs(total-tt.expectedTotal) > 1e-9 {
+				t.Errorf("total = %v, expected %v", total, tt.expectedTotal)
+			}
+			if len(result) != len(tt.expected) {
+				t.Fatalf("BlendVector() = %v, expected %v", result, tt.expected)
+			}
+			for i := range result {
+				if math.Abs(float64(result[i]-tt.expected[i])) > 1e-6 {
+					t.Errorf("BlendVector() = %v, expected %v", result, tt.expected)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestHalfLifeDecay(t *testing.T) {
+	tests := []struct {
+		name     string
+		hours    float64
+		halfLife float64
+		expected float64
+	}{
+		{"No time passed", 0, 72, 1},
+		{"One half-life", 72, 72, 0.5},
+		{"Two half-lives", 144, 72, 0.25},
+		{"Decay disabled", 1000, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := HalfLifeDecay(tt.hours, tt.halfLife)
+			if math.Abs(result-tt.expected) > 1e-9 {
+				t.Errorf("HalfLifeDecay(%v, %v) = %v, expected %v", tt.hours, tt.halfLife, result, tt.expected)
+			}
+		})
+	}
+}